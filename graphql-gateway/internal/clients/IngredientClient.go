@@ -0,0 +1,100 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	m "graphql-gateway/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type IngredientClient struct {
+	ServiceClient
+}
+
+func NewIngredientClient(httpClient HTTPClient, baseUrl string) *IngredientClient {
+	return &IngredientClient{
+		ServiceClient: newServiceClient(httpClient, baseUrl),
+	}
+}
+
+func (c IngredientClient) GetAll(ctx context.Context) ([]m.IngredientDTO, error) {
+	var ingredients []m.IngredientDTO
+
+	if err := c.do(ctx, http.MethodGet, "/api/v2/ingredient", nil, &ingredients); err != nil {
+		return nil, err
+	}
+
+	return ingredients, nil
+}
+
+func (c IngredientClient) Get(ctx context.Context, id uuid.UUID) (m.IngredientDTO, error) {
+	var ingredient m.IngredientDTO
+
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v2/ingredient/%s", id), nil, &ingredient); err != nil {
+		return m.IngredientDTO{}, err
+	}
+
+	return ingredient, nil
+}
+
+func (c IngredientClient) Create(ctx context.Context, ingredientDTO m.IngredientDTO) (m.IngredientDTO, error) {
+	var ingredient m.IngredientDTO
+
+	if err := c.do(ctx, http.MethodPost, "/api/v2/ingredient", ingredientDTO, &ingredient); err != nil {
+		return m.IngredientDTO{}, err
+	}
+
+	return ingredient, nil
+}
+
+func (c IngredientClient) Update(ctx context.Context, ingredientDTO m.IngredientDTO) (m.IngredientDTO, error) {
+	var ingredient m.IngredientDTO
+
+	if err := c.do(ctx, http.MethodPut, fmt.Sprintf("/api/v2/ingredient/%s", ingredientDTO.ID), ingredientDTO, &ingredient); err != nil {
+		return m.IngredientDTO{}, err
+	}
+
+	return ingredient, nil
+}
+
+func (c IngredientClient) Delete(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/ingredient/%s", id), nil, nil)
+}
+
+func (c IngredientClient) GetAllUnits(ctx context.Context) ([]m.UnitDTO, error) {
+	var units []m.UnitDTO
+
+	if err := c.do(ctx, http.MethodGet, "/api/v2/unit", nil, &units); err != nil {
+		return nil, err
+	}
+
+	return units, nil
+}
+
+func (c IngredientClient) GetUnit(ctx context.Context, id uuid.UUID) (m.UnitDTO, error) {
+	var unit m.UnitDTO
+
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v2/unit/%s", id), nil, &unit); err != nil {
+		return m.UnitDTO{}, err
+	}
+
+	return unit, nil
+}
+
+// GetRecipeIngredients retrieves the ingredient lines of several recipes in a single request
+func (c IngredientClient) GetRecipeIngredients(ctx context.Context, recipeIDs []uuid.UUID) ([]m.RecipeIngredientDTO, error) {
+	var recipeIngredients []m.RecipeIngredientDTO
+
+	query := url.Values{}
+	query.Set("recipe_ids", joinIDs(recipeIDs))
+
+	if err := c.do(ctx, http.MethodGet, "/api/v2/ingredient/recipe?"+query.Encode(), nil, &recipeIngredients); err != nil {
+		return nil, err
+	}
+
+	return recipeIngredients, nil
+}
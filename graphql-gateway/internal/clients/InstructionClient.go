@@ -0,0 +1,65 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	m "graphql-gateway/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type InstructionClient struct {
+	ServiceClient
+}
+
+func NewInstructionClient(httpClient HTTPClient, baseUrl string) *InstructionClient {
+	return &InstructionClient{
+		ServiceClient: newServiceClient(httpClient, baseUrl),
+	}
+}
+
+func (c InstructionClient) Get(ctx context.Context, id uuid.UUID) (m.InstructionDTO, error) {
+	var instruction m.InstructionDTO
+
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v2/instruction/%s", id), nil, &instruction); err != nil {
+		return m.InstructionDTO{}, err
+	}
+
+	return instruction, nil
+}
+
+func (c InstructionClient) GetByRecipe(ctx context.Context, recipeID uuid.UUID) ([]m.InstructionDTO, error) {
+	var instructions []m.InstructionDTO
+
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v2/instruction/recipe/%s", recipeID), nil, &instructions); err != nil {
+		return nil, err
+	}
+
+	return instructions, nil
+}
+
+func (c InstructionClient) Create(ctx context.Context, recipeID uuid.UUID, instructionDTO m.InstructionDTO) (m.InstructionDTO, error) {
+	var instruction m.InstructionDTO
+
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/api/v2/instruction/%s", recipeID), instructionDTO, &instruction); err != nil {
+		return m.InstructionDTO{}, err
+	}
+
+	return instruction, nil
+}
+
+func (c InstructionClient) Update(ctx context.Context, instructionDTO m.InstructionDTO) (m.InstructionDTO, error) {
+	var instruction m.InstructionDTO
+
+	if err := c.do(ctx, http.MethodPut, fmt.Sprintf("/api/v2/instruction/%s", instructionDTO.ID), instructionDTO, &instruction); err != nil {
+		return m.InstructionDTO{}, err
+	}
+
+	return instruction, nil
+}
+
+func (c InstructionClient) Delete(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/instruction/%s", id), nil, nil)
+}
@@ -0,0 +1,154 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	m "graphql-gateway/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type MetadataClient struct {
+	ServiceClient
+}
+
+func NewMetadataClient(httpClient HTTPClient, baseUrl string) *MetadataClient {
+	return &MetadataClient{
+		ServiceClient: newServiceClient(httpClient, baseUrl),
+	}
+}
+
+func (c MetadataClient) GetAllCategories(ctx context.Context) ([]m.CategoryDTO, error) {
+	var categories []m.CategoryDTO
+
+	if err := c.do(ctx, http.MethodGet, "/api/v2/metadata/category", nil, &categories); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (c MetadataClient) GetCategory(ctx context.Context, id uuid.UUID) (m.CategoryDTO, error) {
+	var category m.CategoryDTO
+
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v2/metadata/category/%s", id), nil, &category); err != nil {
+		return m.CategoryDTO{}, err
+	}
+
+	return category, nil
+}
+
+func (c MetadataClient) CreateCategory(ctx context.Context, categoryDTO m.CategoryDTO) (m.CategoryDTO, error) {
+	var category m.CategoryDTO
+
+	if err := c.do(ctx, http.MethodPost, "/api/v2/metadata/category", categoryDTO, &category); err != nil {
+		return m.CategoryDTO{}, err
+	}
+
+	return category, nil
+}
+
+func (c MetadataClient) UpdateCategory(ctx context.Context, categoryDTO m.CategoryDTO) (m.CategoryDTO, error) {
+	var category m.CategoryDTO
+
+	if err := c.do(ctx, http.MethodPut, fmt.Sprintf("/api/v2/metadata/category/%s", categoryDTO.ID), categoryDTO, &category); err != nil {
+		return m.CategoryDTO{}, err
+	}
+
+	return category, nil
+}
+
+func (c MetadataClient) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/metadata/category/%s", id), nil, nil)
+}
+
+func (c MetadataClient) GetAllTags(ctx context.Context) ([]m.TagDTO, error) {
+	var tags []m.TagDTO
+
+	if err := c.do(ctx, http.MethodGet, "/api/v2/metadata/tag", nil, &tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (c MetadataClient) GetTag(ctx context.Context, id uuid.UUID) (m.TagDTO, error) {
+	var tag m.TagDTO
+
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v2/metadata/tag/%s", id), nil, &tag); err != nil {
+		return m.TagDTO{}, err
+	}
+
+	return tag, nil
+}
+
+func (c MetadataClient) CreateTag(ctx context.Context, tagDTO m.TagDTO) (m.TagDTO, error) {
+	var tag m.TagDTO
+
+	if err := c.do(ctx, http.MethodPost, "/api/v2/metadata/tag", tagDTO, &tag); err != nil {
+		return m.TagDTO{}, err
+	}
+
+	return tag, nil
+}
+
+func (c MetadataClient) UpdateTag(ctx context.Context, tagDTO m.TagDTO) (m.TagDTO, error) {
+	var tag m.TagDTO
+
+	if err := c.do(ctx, http.MethodPut, fmt.Sprintf("/api/v2/metadata/tag/%s", tagDTO.ID), tagDTO, &tag); err != nil {
+		return m.TagDTO{}, err
+	}
+
+	return tag, nil
+}
+
+func (c MetadataClient) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/metadata/tag/%s", id), nil, nil)
+}
+
+func (c MetadataClient) GetAllCuisineTypes(ctx context.Context) ([]m.CuisineTypeDTO, error) {
+	var cuisineTypes []m.CuisineTypeDTO
+
+	if err := c.do(ctx, http.MethodGet, "/api/v2/metadata/cuisinetype", nil, &cuisineTypes); err != nil {
+		return nil, err
+	}
+
+	return cuisineTypes, nil
+}
+
+func (c MetadataClient) GetAllDifficultyLevels(ctx context.Context) ([]m.DifficultyLevelDTO, error) {
+	var difficultyLevels []m.DifficultyLevelDTO
+
+	if err := c.do(ctx, http.MethodGet, "/api/v2/metadata/difficultylevel", nil, &difficultyLevels); err != nil {
+		return nil, err
+	}
+
+	return difficultyLevels, nil
+}
+
+func (c MetadataClient) GetAllPreparationTimes(ctx context.Context) ([]m.PreparationTimeDTO, error) {
+	var preparationTimes []m.PreparationTimeDTO
+
+	if err := c.do(ctx, http.MethodGet, "/api/v2/metadata/preparationtime", nil, &preparationTimes); err != nil {
+		return nil, err
+	}
+
+	return preparationTimes, nil
+}
+
+// GetRecipeMetadata retrieves the metadata of several recipes in a single request
+func (c MetadataClient) GetRecipeMetadata(ctx context.Context, recipeIDs []uuid.UUID) ([]m.RecipeMetadataDTO, error) {
+	var metadata []m.RecipeMetadataDTO
+
+	query := url.Values{}
+	query.Set("recipe_ids", joinIDs(recipeIDs))
+
+	if err := c.do(ctx, http.MethodGet, "/api/v2/metadata/recipe?"+query.Encode(), nil, &metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
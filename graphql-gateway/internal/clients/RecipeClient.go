@@ -0,0 +1,65 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	m "graphql-gateway/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type RecipeClient struct {
+	ServiceClient
+}
+
+func NewRecipeClient(httpClient HTTPClient, baseUrl string) *RecipeClient {
+	return &RecipeClient{
+		ServiceClient: newServiceClient(httpClient, baseUrl),
+	}
+}
+
+func (c RecipeClient) GetAll(ctx context.Context) ([]m.RecipeDTO, error) {
+	var recipes []m.RecipeDTO
+
+	if err := c.do(ctx, http.MethodGet, "/api/v2/recipes", nil, &recipes); err != nil {
+		return nil, err
+	}
+
+	return recipes, nil
+}
+
+func (c RecipeClient) Get(ctx context.Context, id uuid.UUID) (m.RecipeDTO, error) {
+	var recipe m.RecipeDTO
+
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v2/recipes/%s", id), nil, &recipe); err != nil {
+		return m.RecipeDTO{}, err
+	}
+
+	return recipe, nil
+}
+
+func (c RecipeClient) Create(ctx context.Context, recipeDTO m.RecipeDTO) (m.RecipeDTO, error) {
+	var recipe m.RecipeDTO
+
+	if err := c.do(ctx, http.MethodPost, "/api/v2/recipes", recipeDTO, &recipe); err != nil {
+		return m.RecipeDTO{}, err
+	}
+
+	return recipe, nil
+}
+
+func (c RecipeClient) Update(ctx context.Context, recipeDTO m.RecipeDTO) (m.RecipeDTO, error) {
+	var recipe m.RecipeDTO
+
+	if err := c.do(ctx, http.MethodPut, fmt.Sprintf("/api/v2/recipes/%s", recipeDTO.ID), recipeDTO, &recipe); err != nil {
+		return m.RecipeDTO{}, err
+	}
+
+	return recipe, nil
+}
+
+func (c RecipeClient) Delete(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/recipes/%s", id), nil, nil)
+}
@@ -0,0 +1,108 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type authorizationKey struct{}
+
+// WithAuthorization stores the authorization header of the incoming request so it can be passed on to the downstream services
+func WithAuthorization(ctx context.Context, authorization string) context.Context {
+	return context.WithValue(ctx, authorizationKey{}, authorization)
+}
+
+func authorizationFromContext(ctx context.Context) string {
+	authorization, _ := ctx.Value(authorizationKey{}).(string)
+	return authorization
+}
+
+// ServiceClient holds the shared request logic for the downstream service clients
+type ServiceClient struct {
+	httpClient HTTPClient
+	baseUrl    string
+}
+
+func newServiceClient(httpClient HTTPClient, baseUrl string) ServiceClient {
+	return ServiceClient{
+		httpClient: httpClient,
+		baseUrl:    strings.TrimSuffix(baseUrl, "/"),
+	}
+}
+
+// do performs a request against the downstream service. body is sent as JSON when set and the response is decoded into target when set.
+func (c ServiceClient) do(ctx context.Context, method string, path string, body interface{}, target interface{}) error {
+	var reader io.Reader
+
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseUrl+path, reader)
+	if err != nil {
+		return err
+	}
+
+	if authorization := authorizationFromContext(ctx); authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errors.New("not found")
+	case resp.StatusCode >= http.StatusBadRequest:
+		// the services report what went wrong as {"error": "..."}
+		var response struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&response); err == nil && response.Error != "" {
+			return errors.New(response.Error)
+		}
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	case target == nil || resp.StatusCode == http.StatusNoContent:
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("unable to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// joinIDs formats a list of IDs as a comma separated query value
+func joinIDs(ids []uuid.UUID) string {
+	values := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		values = append(values, id.String())
+	}
+
+	return strings.Join(values, ",")
+}
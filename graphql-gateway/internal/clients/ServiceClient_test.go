@@ -0,0 +1,148 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	m "graphql-gateway/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	recipeID uuid.UUID = uuid.New()
+)
+
+func newTestServer(t *testing.T, status int, body interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		w.WriteHeader(status)
+		if body != nil {
+			json.NewEncoder(w).Encode(body)
+		}
+	}))
+}
+
+func testContext() context.Context {
+	return WithAuthorization(context.Background(), "Bearer token")
+}
+
+func TestRecipeClientGet_OK(t *testing.T) {
+	srv := newTestServer(t, http.StatusOK, m.RecipeDTO{ID: recipeID, Name: "apple pie"})
+	defer srv.Close()
+
+	c := NewRecipeClient(http.DefaultClient, srv.URL)
+
+	result, err := c.Get(testContext(), recipeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, recipeID, result.ID)
+	assert.Equal(t, "apple pie", result.Name)
+}
+
+func TestRecipeClientGet_NotFound(t *testing.T) {
+	srv := newTestServer(t, http.StatusNotFound, map[string]string{"error": "recipe not found"})
+	defer srv.Close()
+
+	c := NewRecipeClient(http.DefaultClient, srv.URL)
+
+	result, err := c.Get(testContext(), recipeID)
+
+	assert.EqualError(t, err, "not found")
+	assert.Equal(t, m.RecipeDTO{}, result)
+}
+
+func TestRecipeClientCreate_Err(t *testing.T) {
+	srv := newTestServer(t, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	defer srv.Close()
+
+	c := NewRecipeClient(http.DefaultClient, srv.URL)
+
+	result, err := c.Create(testContext(), m.RecipeDTO{})
+
+	assert.EqualError(t, err, "invalid request body")
+	assert.Equal(t, m.RecipeDTO{}, result)
+}
+
+func TestRecipeClientDelete_StatusErr(t *testing.T) {
+	srv := newTestServer(t, http.StatusInternalServerError, nil)
+	defer srv.Close()
+
+	c := NewRecipeClient(http.DefaultClient, srv.URL)
+
+	err := c.Delete(testContext(), recipeID)
+
+	assert.EqualError(t, err, "unexpected status code 500")
+}
+
+func TestGetRecipeIngredients_OK(t *testing.T) {
+	var path, query string
+	otherRecipeID := uuid.New()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		query = r.URL.Query().Get("recipe_ids")
+		json.NewEncoder(w).Encode([]m.RecipeIngredientDTO{{RecipeID: recipeID}, {RecipeID: otherRecipeID}})
+	}))
+	defer srv.Close()
+
+	c := NewIngredientClient(http.DefaultClient, srv.URL+"/")
+
+	result, err := c.GetRecipeIngredients(context.Background(), []uuid.UUID{recipeID, otherRecipeID})
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "/api/v2/ingredient/recipe", path)
+	assert.Equal(t, recipeID.String()+","+otherRecipeID.String(), query)
+}
+
+func TestGetRecipeMetadata_DecodeErr(t *testing.T) {
+	srv := newTestServer(t, http.StatusOK, "metadata")
+	defer srv.Close()
+
+	c := NewMetadataClient(http.DefaultClient, srv.URL)
+
+	result, err := c.GetRecipeMetadata(testContext(), []uuid.UUID{recipeID})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestInstructionClientCreate_OK(t *testing.T) {
+	var path string
+	var received m.InstructionDTO
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(received)
+	}))
+	defer srv.Close()
+
+	c := NewInstructionClient(http.DefaultClient, srv.URL)
+
+	result, err := c.Create(context.Background(), recipeID, m.InstructionDTO{Sequence: 1, Description: "preheat the oven"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v2/instruction/"+recipeID.String(), path)
+	assert.Equal(t, "preheat the oven", received.Description)
+	assert.Equal(t, 1, result.Sequence)
+}
+
+func TestClient_ConnectionErr(t *testing.T) {
+	srv := newTestServer(t, http.StatusOK, nil)
+	srv.Close()
+
+	c := NewMetadataClient(http.DefaultClient, srv.URL)
+
+	result, err := c.GetAllTags(testContext())
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
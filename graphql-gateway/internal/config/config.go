@@ -0,0 +1,65 @@
+package config
+
+import (
+	"net/http"
+
+	cl "graphql-gateway/internal/clients"
+	m "graphql-gateway/internal/models"
+	res "graphql-gateway/internal/resolvers"
+	"graphql-gateway/internal/schema"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gin-contrib/cors"
+	"github.com/graph-gophers/graphql-go"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+var (
+	Configuration m.Config
+
+	Logger *log.Logger = log.New()
+	Cors   cors.Config
+
+	// Clients
+	RecipeClient      *cl.RecipeClient
+	IngredientClient  *cl.IngredientClient
+	InstructionClient *cl.InstructionClient
+	MetadataClient    *cl.MetadataClient
+
+	// Resolvers
+	Resolver *res.Resolver
+
+	// Schema
+	Schema *graphql.Schema
+)
+
+func init() {
+	initViper()
+	initConfig()
+	initLogging()
+
+	viper.WatchConfig()
+	viper.OnConfigChange(func(e fsnotify.Event) {
+		log.Infof("config file changed: %s", e.Name)
+
+		initConfig()
+		initLogging()
+	})
+
+	initCors()
+
+	// Init clients
+	httpClient := &http.Client{Timeout: serviceTimeout()}
+
+	RecipeClient = cl.NewRecipeClient(httpClient, Configuration.Services.RecipeServiceUrl)
+	IngredientClient = cl.NewIngredientClient(httpClient, Configuration.Services.IngredientServiceUrl)
+	InstructionClient = cl.NewInstructionClient(httpClient, Configuration.Services.InstructionServiceUrl)
+	MetadataClient = cl.NewMetadataClient(httpClient, Configuration.Services.MetadataServiceUrl)
+
+	// Init resolvers
+	Resolver = res.NewResolver(RecipeClient, IngredientClient, InstructionClient, MetadataClient)
+
+	// Init schema
+	Schema = graphql.MustParseSchema(schema.Schema, Resolver)
+}
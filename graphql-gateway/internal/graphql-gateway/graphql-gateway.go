@@ -0,0 +1,72 @@
+package graphqlgateway
+
+import (
+	"context"
+	cl "graphql-gateway/internal/clients"
+	c "graphql-gateway/internal/config"
+	l "graphql-gateway/internal/loaders"
+	m "graphql-gateway/internal/middleware"
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/tbaehler/gin-keycloak/pkg/ginkeycloak"
+)
+
+var (
+	log = c.Logger
+)
+
+func GraphqlGateway(ctx context.Context) {
+	router := gin.New()
+	gin.SetMode(gin.ReleaseMode)
+
+	// Logging
+	router.Use(m.Logger(log))
+
+	// Panic recovery
+	router.Use(gin.Recovery())
+
+	// Cors handler
+	router.Use(cors.New(c.Cors))
+
+	v1 := router.Group("/api/v2")
+	{
+		graphql := v1.Group("/graphql")
+		graphql.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
+		{
+			graphql.POST("", graphqlHandler(&relay.Handler{Schema: c.Schema}))
+		}
+	}
+
+	// Server startup
+	srv := &http.Server{
+		Handler:      router,
+		Addr:         ":8080",
+		WriteTimeout: 300 * time.Second,
+		ReadTimeout:  15 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(ctx)
+	}()
+
+	log.Info("graphql gateway available on port 8080")
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		log.Error(err)
+	}
+}
+
+// graphqlHandler prepares the request context for the resolvers. The authorization header is forwarded to
+// the downstream services and every request gets its own set of loaders.
+func graphqlHandler(handler http.Handler) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		reqCtx := cl.WithAuthorization(ctx.Request.Context(), ctx.GetHeader("Authorization"))
+		reqCtx = l.WithLoaders(reqCtx, l.NewLoaders(c.IngredientClient, c.MetadataClient))
+
+		handler.ServeHTTP(ctx.Writer, ctx.Request.WithContext(reqCtx))
+	}
+}
@@ -0,0 +1,98 @@
+package loaders
+
+import (
+	"context"
+
+	m "graphql-gateway/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+type IngredientClient interface {
+	GetRecipeIngredients(ctx context.Context, recipeIDs []uuid.UUID) ([]m.RecipeIngredientDTO, error)
+}
+
+type MetadataClient interface {
+	GetRecipeMetadata(ctx context.Context, recipeIDs []uuid.UUID) ([]m.RecipeMetadataDTO, error)
+}
+
+// Loaders batch the per recipe lookups of a single GraphQL request, so resolving a list of recipes
+// results in one downstream request per service instead of one per recipe
+type Loaders struct {
+	RecipeIngredients *dataloader.Loader[uuid.UUID, []m.RecipeIngredientDTO]
+	RecipeMetadata    *dataloader.Loader[uuid.UUID, *m.RecipeMetadataDTO]
+}
+
+// NewLoaders creates a new set of loaders. Loaders cache their results, so a new set should be created for every request.
+func NewLoaders(ingredients IngredientClient, metadata MetadataClient) *Loaders {
+	return &Loaders{
+		RecipeIngredients: dataloader.NewBatchedLoader(recipeIngredientsBatch(ingredients)),
+		RecipeMetadata:    dataloader.NewBatchedLoader(recipeMetadataBatch(metadata)),
+	}
+}
+
+type loadersKey struct{}
+
+// WithLoaders attaches the loaders to the request context
+func WithLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, loaders)
+}
+
+// For returns the loaders attached to the request context
+func For(ctx context.Context) *Loaders {
+	loaders, _ := ctx.Value(loadersKey{}).(*Loaders)
+	return loaders
+}
+
+func recipeIngredientsBatch(client IngredientClient) dataloader.BatchFunc[uuid.UUID, []m.RecipeIngredientDTO] {
+	return func(ctx context.Context, recipeIDs []uuid.UUID) []*dataloader.Result[[]m.RecipeIngredientDTO] {
+		results := make([]*dataloader.Result[[]m.RecipeIngredientDTO], len(recipeIDs))
+
+		recipeIngredients, err := client.GetRecipeIngredients(ctx, recipeIDs)
+		if err != nil && err.Error() != "not found" {
+			for i := range results {
+				results[i] = &dataloader.Result[[]m.RecipeIngredientDTO]{Error: err}
+			}
+			return results
+		}
+
+		byRecipe := make(map[uuid.UUID][]m.RecipeIngredientDTO, len(recipeIDs))
+		for _, recipeIngredient := range recipeIngredients {
+			byRecipe[recipeIngredient.RecipeID] = append(byRecipe[recipeIngredient.RecipeID], recipeIngredient)
+		}
+
+		// results have to be returned in the same order as the requested keys
+		for i, recipeID := range recipeIDs {
+			results[i] = &dataloader.Result[[]m.RecipeIngredientDTO]{Data: byRecipe[recipeID]}
+		}
+
+		return results
+	}
+}
+
+func recipeMetadataBatch(client MetadataClient) dataloader.BatchFunc[uuid.UUID, *m.RecipeMetadataDTO] {
+	return func(ctx context.Context, recipeIDs []uuid.UUID) []*dataloader.Result[*m.RecipeMetadataDTO] {
+		results := make([]*dataloader.Result[*m.RecipeMetadataDTO], len(recipeIDs))
+
+		metadata, err := client.GetRecipeMetadata(ctx, recipeIDs)
+		if err != nil && err.Error() != "not found" {
+			for i := range results {
+				results[i] = &dataloader.Result[*m.RecipeMetadataDTO]{Error: err}
+			}
+			return results
+		}
+
+		byRecipe := make(map[uuid.UUID]*m.RecipeMetadataDTO, len(metadata))
+		for i := range metadata {
+			byRecipe[metadata[i].RecipeID] = &metadata[i]
+		}
+
+		// recipes without metadata resolve to nil
+		for i, recipeID := range recipeIDs {
+			results[i] = &dataloader.Result[*m.RecipeMetadataDTO]{Data: byRecipe[recipeID]}
+		}
+
+		return results
+	}
+}
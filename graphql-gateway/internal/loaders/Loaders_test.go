@@ -0,0 +1,134 @@
+package loaders
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	m "graphql-gateway/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type ClientMock struct {
+	mu    sync.Mutex
+	calls [][]uuid.UUID
+	err   error
+}
+
+func (c *ClientMock) record(recipeIDs []uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls = append(c.calls, recipeIDs)
+}
+
+func (c *ClientMock) GetRecipeIngredients(ctx context.Context, recipeIDs []uuid.UUID) ([]m.RecipeIngredientDTO, error) {
+	c.record(recipeIDs)
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	var recipeIngredients []m.RecipeIngredientDTO
+	for _, recipeID := range recipeIDs {
+		recipeIngredients = append(recipeIngredients, m.RecipeIngredientDTO{RecipeID: recipeID, Quantity: 1}, m.RecipeIngredientDTO{RecipeID: recipeID, Quantity: 2})
+	}
+	return recipeIngredients, nil
+}
+
+func (c *ClientMock) GetRecipeMetadata(ctx context.Context, recipeIDs []uuid.UUID) ([]m.RecipeMetadataDTO, error) {
+	c.record(recipeIDs)
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	// only the first recipe has metadata
+	return []m.RecipeMetadataDTO{{RecipeID: recipeIDs[0]}}, nil
+}
+
+func loadConcurrently[V any](load func(ctx context.Context, id uuid.UUID) (V, error), ids []uuid.UUID) ([]V, []error) {
+	var wg sync.WaitGroup
+	results := make([]V, len(ids))
+	errs := make([]error, len(ids))
+
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id uuid.UUID) {
+			defer wg.Done()
+			results[i], errs[i] = load(context.Background(), id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	return results, errs
+}
+
+func TestRecipeIngredients_Batched(t *testing.T) {
+	client := &ClientMock{}
+	l := NewLoaders(client, client)
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+	results, errs := loadConcurrently(func(ctx context.Context, id uuid.UUID) ([]m.RecipeIngredientDTO, error) {
+		return l.RecipeIngredients.Load(ctx, id)()
+	}, ids)
+
+	assert.Len(t, client.calls, 1)
+	assert.ElementsMatch(t, ids, client.calls[0])
+	for i, id := range ids {
+		assert.NoError(t, errs[i])
+		assert.Len(t, results[i], 2)
+		assert.Equal(t, id, results[i][0].RecipeID)
+	}
+}
+
+func TestRecipeIngredients_Err(t *testing.T) {
+	client := &ClientMock{err: errors.New("unexpected status code 500")}
+	l := NewLoaders(client, client)
+
+	result, err := l.RecipeIngredients.Load(context.Background(), uuid.New())()
+
+	assert.EqualError(t, err, "unexpected status code 500")
+	assert.Nil(t, result)
+}
+
+func TestRecipeMetadata_Batched(t *testing.T) {
+	client := &ClientMock{}
+	l := NewLoaders(client, client)
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	results, errs := loadConcurrently(func(ctx context.Context, id uuid.UUID) (*m.RecipeMetadataDTO, error) {
+		return l.RecipeMetadata.Load(ctx, id)()
+	}, ids)
+
+	assert.Len(t, client.calls, 1)
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+
+	// one of the recipes has no metadata and resolves to nil
+	var found int
+	for _, result := range results {
+		if result != nil {
+			found++
+		}
+	}
+	assert.Equal(t, 1, found)
+}
+
+func TestRecipeMetadata_NotFound(t *testing.T) {
+	client := &ClientMock{err: errors.New("not found")}
+	l := NewLoaders(client, client)
+
+	result, err := l.RecipeMetadata.Load(context.Background(), uuid.New())()
+
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestFor(t *testing.T) {
+	l := NewLoaders(&ClientMock{}, &ClientMock{})
+
+	assert.Same(t, l, For(WithLoaders(context.Background(), l)))
+	assert.Nil(t, For(context.Background()))
+}
@@ -0,0 +1,79 @@
+package models
+
+import "github.com/google/uuid"
+
+// The models below mirror the DTOs as exchanged with the downstream services
+
+// recipe-service
+type RecipeDTO struct {
+	ID           uuid.UUID
+	Name         string `json:"name" example:"apple pie"`
+	Description  string `json:"description" example:"pie with apples"`
+	ServingCount int    `json:"servingcount" example:"4"`
+}
+
+// ingredient-service
+type IngredientDTO struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name" example:"asparagus"`
+}
+
+type UnitDTO struct {
+	ID        uuid.UUID `json:"ID"`
+	FullName  string    `json:"FullName" example:"Fluid ounce"`
+	ShortName string    `json:"ShortName" example:"fl oz"`
+}
+
+type RecipeIngredientDTO struct {
+	RecipeID       uuid.UUID `json:"RecipeID"`
+	IngredientID   uuid.UUID `json:"IngredientID"`
+	IngredientName string    `json:"IngredientName" example:"asparagus"`
+	Quantity       float64   `json:"Quantity" example:"1.5"`
+	MaxQuantity    float64   `json:"MaxQuantity,omitempty" example:"2"`
+	ToTaste        bool      `json:"ToTaste,omitempty"`
+	Amount         string    `json:"Amount,omitempty" example:"1½–2"`
+	Unit           UnitDTO   `json:"unit"`
+}
+
+// instruction-service
+type InstructionDTO struct {
+	ID          uuid.UUID `json:"id"`
+	Sequence    int       `json:"sequence" example:"1"`
+	Description string    `json:"description" example:"description"`
+	MediaID     uuid.UUID `json:"media_url"`
+}
+
+// metadata-service
+type CategoryDTO struct {
+	ID   uuid.UUID `json:"id,omitempty"`
+	Name string    `json:"name"`
+}
+
+type TagDTO struct {
+	ID   uuid.UUID `json:"id,omitempty"`
+	Name string    `json:"name"`
+}
+
+type CuisineTypeDTO struct {
+	ID   uuid.UUID `json:"id,omitempty"`
+	Name string    `json:"name"`
+}
+
+type DifficultyLevelDTO struct {
+	ID    uuid.UUID `json:"id,omitempty"`
+	Level int       `json:"name"`
+}
+
+type PreparationTimeDTO struct {
+	ID       uuid.UUID `json:"id,omitempty"`
+	Duration int       `json:"name"`
+}
+
+type RecipeMetadataDTO struct {
+	RecipeID        uuid.UUID          `json:"recipe_id"`
+	Categories      []CategoryDTO      `json:"categories"`
+	Tags            []TagDTO           `json:"tags"`
+	CuisineType     CuisineTypeDTO     `json:"cuisine_type"`
+	DifficultyLevel DifficultyLevelDTO `json:"difficulty_level"`
+	PreparationTime PreparationTimeDTO `json:"preparation_time"`
+}
@@ -0,0 +1,42 @@
+package models
+
+type Config struct {
+	Global   GlobalConfig
+	Cors     CorsConfig
+	Oauth    OauthConfig
+	Services ServicesConfig
+}
+
+// GlobalConfig holds global configuration items
+type GlobalConfig struct {
+	LogLevel string
+}
+
+// ServicesConfig holds the base urls of the services the gateway resolves against
+type ServicesConfig struct {
+	RecipeServiceUrl      string
+	IngredientServiceUrl  string
+	InstructionServiceUrl string
+	MetadataServiceUrl    string
+	Timeout               int // in seconds
+}
+
+type OauthConfig struct {
+	Service              string
+	Url                  string
+	Realm                string
+	FullCertsPath        *string
+	DisableSecurityCheck bool
+}
+
+type CorsConfig struct {
+	AllowedOrigins   []string
+	AllowCredentials bool
+	AllowedHeaders   []string
+	AllowedMethods   []string
+}
+
+type LoggerInterface interface {
+	Debugf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+}
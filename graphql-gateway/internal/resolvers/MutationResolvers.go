@@ -0,0 +1,247 @@
+package resolvers
+
+import (
+	"context"
+
+	m "graphql-gateway/internal/models"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+func (r *Resolver) CreateRecipe(ctx context.Context, args struct {
+	RecipeName   string
+	Description  string
+	ServingCount int32
+}) (*RecipeResolver, error) {
+	recipe, err := r.recipes.Create(ctx, m.RecipeDTO{
+		Name:         args.RecipeName,
+		Description:  args.Description,
+		ServingCount: int(args.ServingCount),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RecipeResolver{recipe: recipe}, nil
+}
+
+// UpdateRecipe only sends the provided fields. The recipe service skips zero values on update, so the
+// recipe is read back afterwards to return the complete state.
+func (r *Resolver) UpdateRecipe(ctx context.Context, args struct {
+	ID           graphql.ID
+	RecipeName   *string
+	Description  *string
+	ServingCount *int32
+}) (*RecipeResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipeDTO := m.RecipeDTO{ID: id}
+	if args.RecipeName != nil {
+		recipeDTO.Name = *args.RecipeName
+	}
+	if args.Description != nil {
+		recipeDTO.Description = *args.Description
+	}
+	if args.ServingCount != nil {
+		recipeDTO.ServingCount = int(*args.ServingCount)
+	}
+
+	if _, err := r.recipes.Update(ctx, recipeDTO); err != nil {
+		return nil, err
+	}
+
+	return r.GetRecipe(ctx, idArgs{ID: args.ID})
+}
+
+func (r *Resolver) DeleteRecipe(ctx context.Context, args idArgs) (*graphql.ID, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.recipes.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return &args.ID, nil
+}
+
+func (r *Resolver) CreateInstruction(ctx context.Context, args struct {
+	RecipeID    graphql.ID
+	Sequence    int32
+	Description string
+}) (*InstructionResolver, error) {
+	recipeID, err := parseID(args.RecipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	instruction, err := r.instructions.Create(ctx, recipeID, m.InstructionDTO{
+		Sequence:    int(args.Sequence),
+		Description: args.Description,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &InstructionResolver{instruction: instruction}, nil
+}
+
+// UpdateInstruction only sends the provided fields and reads the instruction back afterwards, same as UpdateRecipe
+func (r *Resolver) UpdateInstruction(ctx context.Context, args struct {
+	ID          graphql.ID
+	Sequence    *int32
+	Description *string
+}) (*InstructionResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	instructionDTO := m.InstructionDTO{ID: id}
+	if args.Sequence != nil {
+		instructionDTO.Sequence = int(*args.Sequence)
+	}
+	if args.Description != nil {
+		instructionDTO.Description = *args.Description
+	}
+
+	if _, err := r.instructions.Update(ctx, instructionDTO); err != nil {
+		return nil, err
+	}
+
+	return r.GetInstruction(ctx, idArgs{ID: args.ID})
+}
+
+func (r *Resolver) DeleteInstruction(ctx context.Context, args idArgs) (*graphql.ID, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.instructions.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return &args.ID, nil
+}
+
+func (r *Resolver) CreateIngredient(ctx context.Context, args struct{ IngredientName string }) (*IngredientResolver, error) {
+	ingredient, err := r.ingredients.Create(ctx, m.IngredientDTO{Name: args.IngredientName})
+	if err != nil {
+		return nil, err
+	}
+
+	return &IngredientResolver{ingredient: ingredient}, nil
+}
+
+func (r *Resolver) UpdateIngredient(ctx context.Context, args struct {
+	ID             graphql.ID
+	IngredientName string
+}) (*IngredientResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ingredient, err := r.ingredients.Update(ctx, m.IngredientDTO{ID: id, Name: args.IngredientName})
+	if err != nil {
+		return nil, err
+	}
+
+	return &IngredientResolver{ingredient: ingredient}, nil
+}
+
+func (r *Resolver) DeleteIngredient(ctx context.Context, args idArgs) (*graphql.ID, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.ingredients.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return &args.ID, nil
+}
+
+func (r *Resolver) CreateCategory(ctx context.Context, args struct{ CategoryName string }) (*CategoryResolver, error) {
+	category, err := r.metadata.CreateCategory(ctx, m.CategoryDTO{Name: args.CategoryName})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CategoryResolver{category: category}, nil
+}
+
+func (r *Resolver) UpdateCategory(ctx context.Context, args struct {
+	ID           graphql.ID
+	CategoryName string
+}) (*CategoryResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := r.metadata.UpdateCategory(ctx, m.CategoryDTO{ID: id, Name: args.CategoryName})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CategoryResolver{category: category}, nil
+}
+
+func (r *Resolver) DeleteCategory(ctx context.Context, args idArgs) (*graphql.ID, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.metadata.DeleteCategory(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return &args.ID, nil
+}
+
+func (r *Resolver) CreateTag(ctx context.Context, args struct{ TagName string }) (*TagResolver, error) {
+	tag, err := r.metadata.CreateTag(ctx, m.TagDTO{Name: args.TagName})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TagResolver{tag: tag}, nil
+}
+
+func (r *Resolver) UpdateTag(ctx context.Context, args struct {
+	ID      graphql.ID
+	TagName string
+}) (*TagResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := r.metadata.UpdateTag(ctx, m.TagDTO{ID: id, Name: args.TagName})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TagResolver{tag: tag}, nil
+}
+
+func (r *Resolver) DeleteTag(ctx context.Context, args idArgs) (*graphql.ID, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.metadata.DeleteTag(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return &args.ID, nil
+}
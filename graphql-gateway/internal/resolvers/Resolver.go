@@ -0,0 +1,312 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+
+	m "graphql-gateway/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type RecipeClient interface {
+	GetAll(ctx context.Context) ([]m.RecipeDTO, error)
+	Get(ctx context.Context, id uuid.UUID) (m.RecipeDTO, error)
+	Create(ctx context.Context, recipe m.RecipeDTO) (m.RecipeDTO, error)
+	Update(ctx context.Context, recipe m.RecipeDTO) (m.RecipeDTO, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type IngredientClient interface {
+	GetAll(ctx context.Context) ([]m.IngredientDTO, error)
+	Get(ctx context.Context, id uuid.UUID) (m.IngredientDTO, error)
+	Create(ctx context.Context, ingredient m.IngredientDTO) (m.IngredientDTO, error)
+	Update(ctx context.Context, ingredient m.IngredientDTO) (m.IngredientDTO, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetAllUnits(ctx context.Context) ([]m.UnitDTO, error)
+	GetUnit(ctx context.Context, id uuid.UUID) (m.UnitDTO, error)
+}
+
+type InstructionClient interface {
+	Get(ctx context.Context, id uuid.UUID) (m.InstructionDTO, error)
+	GetByRecipe(ctx context.Context, recipeID uuid.UUID) ([]m.InstructionDTO, error)
+	Create(ctx context.Context, recipeID uuid.UUID, instruction m.InstructionDTO) (m.InstructionDTO, error)
+	Update(ctx context.Context, instruction m.InstructionDTO) (m.InstructionDTO, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type MetadataClient interface {
+	GetAllCategories(ctx context.Context) ([]m.CategoryDTO, error)
+	GetCategory(ctx context.Context, id uuid.UUID) (m.CategoryDTO, error)
+	CreateCategory(ctx context.Context, category m.CategoryDTO) (m.CategoryDTO, error)
+	UpdateCategory(ctx context.Context, category m.CategoryDTO) (m.CategoryDTO, error)
+	DeleteCategory(ctx context.Context, id uuid.UUID) error
+	GetAllTags(ctx context.Context) ([]m.TagDTO, error)
+	GetTag(ctx context.Context, id uuid.UUID) (m.TagDTO, error)
+	CreateTag(ctx context.Context, tag m.TagDTO) (m.TagDTO, error)
+	UpdateTag(ctx context.Context, tag m.TagDTO) (m.TagDTO, error)
+	DeleteTag(ctx context.Context, id uuid.UUID) error
+	GetAllCuisineTypes(ctx context.Context) ([]m.CuisineTypeDTO, error)
+	GetAllDifficultyLevels(ctx context.Context) ([]m.DifficultyLevelDTO, error)
+	GetAllPreparationTimes(ctx context.Context) ([]m.PreparationTimeDTO, error)
+}
+
+// Resolver is the root resolver of the schema. It resolves the queries and mutations against the REST services.
+type Resolver struct {
+	recipes      RecipeClient
+	ingredients  IngredientClient
+	instructions InstructionClient
+	metadata     MetadataClient
+}
+
+func NewResolver(recipes RecipeClient, ingredients IngredientClient, instructions InstructionClient, metadata MetadataClient) *Resolver {
+	return &Resolver{
+		recipes:      recipes,
+		ingredients:  ingredients,
+		instructions: instructions,
+		metadata:     metadata,
+	}
+}
+
+type idArgs struct {
+	ID graphql.ID
+}
+
+func parseID(id graphql.ID) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, errors.New("invalid ID")
+	}
+
+	return parsed, nil
+}
+
+func isNotFound(err error) bool {
+	return err != nil && err.Error() == "not found"
+}
+
+// ====== Queries ======
+
+func (r *Resolver) GetRecipe(ctx context.Context, args idArgs) (*RecipeResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe, err := r.recipes.Get(ctx, id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &RecipeResolver{recipe: recipe}, nil
+}
+
+func (r *Resolver) GetAllRecipes(ctx context.Context) ([]*RecipeResolver, error) {
+	recipes, err := r.recipes.GetAll(ctx)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	resolvers := make([]*RecipeResolver, 0, len(recipes))
+	for _, recipe := range recipes {
+		resolvers = append(resolvers, &RecipeResolver{recipe: recipe})
+	}
+
+	return resolvers, nil
+}
+
+func (r *Resolver) GetInstruction(ctx context.Context, args idArgs) (*InstructionResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	instruction, err := r.instructions.Get(ctx, id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &InstructionResolver{instruction: instruction}, nil
+}
+
+func (r *Resolver) GetAllInstructions(ctx context.Context, args struct{ RecipeID graphql.ID }) ([]*InstructionResolver, error) {
+	recipeID, err := parseID(args.RecipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	instructions, err := r.instructions.GetByRecipe(ctx, recipeID)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	resolvers := make([]*InstructionResolver, 0, len(instructions))
+	for _, instruction := range instructions {
+		resolvers = append(resolvers, &InstructionResolver{instruction: instruction})
+	}
+
+	return resolvers, nil
+}
+
+func (r *Resolver) GetIngredient(ctx context.Context, args idArgs) (*IngredientResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ingredient, err := r.ingredients.Get(ctx, id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &IngredientResolver{ingredient: ingredient}, nil
+}
+
+func (r *Resolver) GetAllIngredients(ctx context.Context) ([]*IngredientResolver, error) {
+	ingredients, err := r.ingredients.GetAll(ctx)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	resolvers := make([]*IngredientResolver, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		resolvers = append(resolvers, &IngredientResolver{ingredient: ingredient})
+	}
+
+	return resolvers, nil
+}
+
+func (r *Resolver) GetUnit(ctx context.Context, args idArgs) (*UnitResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	unit, err := r.ingredients.GetUnit(ctx, id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &UnitResolver{unit: unit}, nil
+}
+
+func (r *Resolver) GetAllUnits(ctx context.Context) ([]*UnitResolver, error) {
+	units, err := r.ingredients.GetAllUnits(ctx)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	resolvers := make([]*UnitResolver, 0, len(units))
+	for _, unit := range units {
+		resolvers = append(resolvers, &UnitResolver{unit: unit})
+	}
+
+	return resolvers, nil
+}
+
+func (r *Resolver) GetCategory(ctx context.Context, args idArgs) (*CategoryResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := r.metadata.GetCategory(ctx, id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &CategoryResolver{category: category}, nil
+}
+
+func (r *Resolver) GetAllCategories(ctx context.Context) ([]*CategoryResolver, error) {
+	categories, err := r.metadata.GetAllCategories(ctx)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	return categoryResolvers(categories), nil
+}
+
+func (r *Resolver) GetTag(ctx context.Context, args idArgs) (*TagResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := r.metadata.GetTag(ctx, id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &TagResolver{tag: tag}, nil
+}
+
+func (r *Resolver) GetAllTags(ctx context.Context) ([]*TagResolver, error) {
+	tags, err := r.metadata.GetAllTags(ctx)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	return tagResolvers(tags), nil
+}
+
+func (r *Resolver) GetAllCuisineTypes(ctx context.Context) ([]*CuisineTypeResolver, error) {
+	cuisineTypes, err := r.metadata.GetAllCuisineTypes(ctx)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	resolvers := make([]*CuisineTypeResolver, 0, len(cuisineTypes))
+	for _, cuisineType := range cuisineTypes {
+		resolvers = append(resolvers, &CuisineTypeResolver{cuisineType: cuisineType})
+	}
+
+	return resolvers, nil
+}
+
+func (r *Resolver) GetAllDifficultyLevels(ctx context.Context) ([]*DifficultyLevelResolver, error) {
+	difficultyLevels, err := r.metadata.GetAllDifficultyLevels(ctx)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	resolvers := make([]*DifficultyLevelResolver, 0, len(difficultyLevels))
+	for _, difficultyLevel := range difficultyLevels {
+		resolvers = append(resolvers, &DifficultyLevelResolver{difficultyLevel: difficultyLevel})
+	}
+
+	return resolvers, nil
+}
+
+func (r *Resolver) GetAllPreparationTimes(ctx context.Context) ([]*PreparationTimeResolver, error) {
+	preparationTimes, err := r.metadata.GetAllPreparationTimes(ctx)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	resolvers := make([]*PreparationTimeResolver, 0, len(preparationTimes))
+	for _, preparationTime := range preparationTimes {
+		resolvers = append(resolvers, &PreparationTimeResolver{preparationTime: preparationTime})
+	}
+
+	return resolvers, nil
+}
@@ -0,0 +1,382 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	l "graphql-gateway/internal/loaders"
+	m "graphql-gateway/internal/models"
+	"graphql-gateway/internal/schema"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	recipes []m.RecipeDTO = []m.RecipeDTO{
+		{ID: uuid.New(), Name: "apple pie", Description: "pie with apples", ServingCount: 4},
+		{ID: uuid.New(), Name: "pancakes", Description: "fluffy pancakes", ServingCount: 2},
+	}
+	ingredientID   uuid.UUID = uuid.New()
+	recipeErr      error
+	ingredientErr  error
+	updatedRecipe  m.RecipeDTO
+	ingredientRuns int
+	metadataRuns   int
+	batchMutex     sync.Mutex
+)
+
+// ====== Mocks ======
+
+type RecipeClientMock struct{}
+
+func (RecipeClientMock) GetAll(ctx context.Context) ([]m.RecipeDTO, error) {
+	return recipes, recipeErr
+}
+
+func (RecipeClientMock) Get(ctx context.Context, id uuid.UUID) (m.RecipeDTO, error) {
+	if recipeErr != nil {
+		return m.RecipeDTO{}, recipeErr
+	}
+	for _, recipe := range recipes {
+		if recipe.ID == id {
+			return recipe, nil
+		}
+	}
+	return m.RecipeDTO{}, errors.New("not found")
+}
+
+func (RecipeClientMock) Create(ctx context.Context, recipe m.RecipeDTO) (m.RecipeDTO, error) {
+	recipe.ID = uuid.New()
+	return recipe, recipeErr
+}
+
+func (RecipeClientMock) Update(ctx context.Context, recipe m.RecipeDTO) (m.RecipeDTO, error) {
+	updatedRecipe = recipe
+	return recipe, recipeErr
+}
+
+func (RecipeClientMock) Delete(ctx context.Context, id uuid.UUID) error {
+	return recipeErr
+}
+
+type IngredientClientMock struct{}
+
+func (IngredientClientMock) GetAll(ctx context.Context) ([]m.IngredientDTO, error) {
+	return nil, errors.New("not found")
+}
+
+func (IngredientClientMock) Get(ctx context.Context, id uuid.UUID) (m.IngredientDTO, error) {
+	return m.IngredientDTO{ID: id, Name: "apple"}, nil
+}
+
+func (IngredientClientMock) Create(ctx context.Context, ingredient m.IngredientDTO) (m.IngredientDTO, error) {
+	return ingredient, nil
+}
+
+func (IngredientClientMock) Update(ctx context.Context, ingredient m.IngredientDTO) (m.IngredientDTO, error) {
+	return ingredient, nil
+}
+
+func (IngredientClientMock) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (IngredientClientMock) GetAllUnits(ctx context.Context) ([]m.UnitDTO, error) {
+	return []m.UnitDTO{{ID: uuid.New(), FullName: "gram", ShortName: "g"}}, nil
+}
+
+func (IngredientClientMock) GetUnit(ctx context.Context, id uuid.UUID) (m.UnitDTO, error) {
+	return m.UnitDTO{}, errors.New("not found")
+}
+
+func (IngredientClientMock) GetRecipeIngredients(ctx context.Context, recipeIDs []uuid.UUID) ([]m.RecipeIngredientDTO, error) {
+	batchMutex.Lock()
+	ingredientRuns++
+	batchMutex.Unlock()
+
+	if ingredientErr != nil {
+		return nil, ingredientErr
+	}
+
+	var recipeIngredients []m.RecipeIngredientDTO
+	for _, recipeID := range recipeIDs {
+		recipeIngredients = append(recipeIngredients, m.RecipeIngredientDTO{
+			RecipeID:       recipeID,
+			IngredientID:   ingredientID,
+			IngredientName: "apple",
+			Quantity:       1.5,
+			MaxQuantity:    2,
+			Amount:         "1½–2",
+			Unit:           m.UnitDTO{ShortName: "pc"},
+		})
+	}
+	return recipeIngredients, nil
+}
+
+type InstructionClientMock struct{}
+
+func (InstructionClientMock) Get(ctx context.Context, id uuid.UUID) (m.InstructionDTO, error) {
+	return m.InstructionDTO{ID: id, Sequence: 1, Description: "preheat the oven"}, nil
+}
+
+func (InstructionClientMock) GetByRecipe(ctx context.Context, recipeID uuid.UUID) ([]m.InstructionDTO, error) {
+	return []m.InstructionDTO{{ID: uuid.New(), Sequence: 1}, {ID: uuid.New(), Sequence: 2, MediaID: uuid.New()}}, nil
+}
+
+func (InstructionClientMock) Create(ctx context.Context, recipeID uuid.UUID, instruction m.InstructionDTO) (m.InstructionDTO, error) {
+	instruction.ID = uuid.New()
+	return instruction, nil
+}
+
+func (InstructionClientMock) Update(ctx context.Context, instruction m.InstructionDTO) (m.InstructionDTO, error) {
+	return instruction, nil
+}
+
+func (InstructionClientMock) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+type MetadataClientMock struct{}
+
+func (MetadataClientMock) GetAllCategories(ctx context.Context) ([]m.CategoryDTO, error) {
+	return []m.CategoryDTO{{ID: uuid.New(), Name: "dessert"}}, nil
+}
+
+func (MetadataClientMock) GetCategory(ctx context.Context, id uuid.UUID) (m.CategoryDTO, error) {
+	return m.CategoryDTO{ID: id, Name: "dessert"}, nil
+}
+
+func (MetadataClientMock) CreateCategory(ctx context.Context, category m.CategoryDTO) (m.CategoryDTO, error) {
+	return category, nil
+}
+
+func (MetadataClientMock) UpdateCategory(ctx context.Context, category m.CategoryDTO) (m.CategoryDTO, error) {
+	return category, nil
+}
+
+func (MetadataClientMock) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (MetadataClientMock) GetAllTags(ctx context.Context) ([]m.TagDTO, error) {
+	return []m.TagDTO{}, nil
+}
+
+func (MetadataClientMock) GetTag(ctx context.Context, id uuid.UUID) (m.TagDTO, error) {
+	return m.TagDTO{}, errors.New("not found")
+}
+
+func (MetadataClientMock) CreateTag(ctx context.Context, tag m.TagDTO) (m.TagDTO, error) {
+	return tag, nil
+}
+
+func (MetadataClientMock) UpdateTag(ctx context.Context, tag m.TagDTO) (m.TagDTO, error) {
+	return tag, nil
+}
+
+func (MetadataClientMock) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (MetadataClientMock) GetAllCuisineTypes(ctx context.Context) ([]m.CuisineTypeDTO, error) {
+	return []m.CuisineTypeDTO{{ID: uuid.New(), Name: "thai"}}, nil
+}
+
+func (MetadataClientMock) GetAllDifficultyLevels(ctx context.Context) ([]m.DifficultyLevelDTO, error) {
+	return []m.DifficultyLevelDTO{{ID: uuid.New(), Level: 1}}, nil
+}
+
+func (MetadataClientMock) GetAllPreparationTimes(ctx context.Context) ([]m.PreparationTimeDTO, error) {
+	return []m.PreparationTimeDTO{{ID: uuid.New(), Duration: 30}}, nil
+}
+
+func (MetadataClientMock) GetRecipeMetadata(ctx context.Context, recipeIDs []uuid.UUID) ([]m.RecipeMetadataDTO, error) {
+	batchMutex.Lock()
+	metadataRuns++
+	batchMutex.Unlock()
+
+	// only the first recipe has metadata
+	return []m.RecipeMetadataDTO{{
+		RecipeID:        recipes[0].ID,
+		Tags:            []m.TagDTO{{ID: uuid.New(), Name: "weeknight"}},
+		CuisineType:     m.CuisineTypeDTO{ID: uuid.New(), Name: "french"},
+		DifficultyLevel: m.DifficultyLevelDTO{ID: uuid.New(), Level: 2},
+	}}, nil
+}
+
+// ====== Helpers ======
+
+func resetMocks() {
+	recipeErr = nil
+	ingredientErr = nil
+	updatedRecipe = m.RecipeDTO{}
+	ingredientRuns = 0
+	metadataRuns = 0
+}
+
+func execute(t *testing.T, query string, variables map[string]interface{}) (map[string]interface{}, []string) {
+	s := graphql.MustParseSchema(schema.Schema, NewResolver(&RecipeClientMock{}, &IngredientClientMock{}, &InstructionClientMock{}, &MetadataClientMock{}))
+	ctx := l.WithLoaders(context.Background(), l.NewLoaders(&IngredientClientMock{}, &MetadataClientMock{}))
+
+	response := s.Exec(ctx, query, "", variables)
+
+	var errs []string
+	for _, err := range response.Errors {
+		errs = append(errs, err.Message)
+	}
+
+	var data map[string]interface{}
+	if response.Data != nil {
+		assert.NoError(t, json.Unmarshal(response.Data, &data))
+	}
+
+	return data, errs
+}
+
+// ====== Tests ======
+
+func TestGetAllRecipes_Batched(t *testing.T) {
+	resetMocks()
+
+	data, errs := execute(t, `{
+		getAllRecipes {
+			ID
+			RecipeName
+			ServingCount
+			Ingredients { Quantity MaxQuantity ToTaste Amount Ingredient { IngredientName } Unit { ShortName } }
+			Tags { TagName }
+			CuisineType { CuisineTypeName }
+			DifficultyLevel { Level }
+			PreparationTime { Duration }
+		}
+	}`, nil)
+
+	assert.Empty(t, errs)
+	assert.Equal(t, 1, ingredientRuns)
+	assert.Equal(t, 1, metadataRuns)
+
+	result := data["getAllRecipes"].([]interface{})
+	assert.Len(t, result, 2)
+
+	first := result[0].(map[string]interface{})
+	assert.Equal(t, recipes[0].ID.String(), first["ID"])
+	assert.Equal(t, "apple pie", first["RecipeName"])
+	assert.Equal(t, float64(4), first["ServingCount"])
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"Quantity":    1.5,
+		"MaxQuantity": float64(2),
+		"ToTaste":     false,
+		"Amount":      "1½–2",
+		"Ingredient":  map[string]interface{}{"IngredientName": "apple"},
+		"Unit":        map[string]interface{}{"ShortName": "pc"},
+	}}, first["Ingredients"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"TagName": "weeknight"}}, first["Tags"])
+	assert.Equal(t, map[string]interface{}{"CuisineTypeName": "french"}, first["CuisineType"])
+	assert.Equal(t, map[string]interface{}{"Level": float64(2)}, first["DifficultyLevel"])
+	assert.Nil(t, first["PreparationTime"])
+
+	second := result[1].(map[string]interface{})
+	assert.Equal(t, []interface{}{}, second["Tags"])
+	assert.Nil(t, second["CuisineType"])
+}
+
+func TestGetAllRecipes_Err(t *testing.T) {
+	resetMocks()
+	recipeErr = errors.New("unexpected status code 500")
+
+	data, errs := execute(t, `{ getAllRecipes { ID } }`, nil)
+
+	assert.Equal(t, []string{"unexpected status code 500"}, errs)
+	assert.Nil(t, data)
+}
+
+func TestGetRecipe_IngredientsErr(t *testing.T) {
+	resetMocks()
+	ingredientErr = errors.New("unexpected status code 503")
+
+	data, errs := execute(t, `query($id: ID!) { getRecipe(id: $id) { RecipeName Ingredients { Quantity } } }`,
+		map[string]interface{}{"id": recipes[1].ID.String()})
+
+	assert.Equal(t, []string{"unexpected status code 503"}, errs)
+	assert.Equal(t, map[string]interface{}{"getRecipe": nil}, data)
+}
+
+func TestGetRecipe_NotFound(t *testing.T) {
+	resetMocks()
+
+	data, errs := execute(t, `query($id: ID!) { getRecipe(id: $id) { ID } }`,
+		map[string]interface{}{"id": uuid.NewString()})
+
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"getRecipe": nil}, data)
+}
+
+func TestGetRecipe_IDErr(t *testing.T) {
+	resetMocks()
+
+	_, errs := execute(t, `{ getRecipe(id: "1") { ID } }`, nil)
+
+	assert.Equal(t, []string{"invalid ID"}, errs)
+}
+
+func TestGetAllInstructions_OK(t *testing.T) {
+	resetMocks()
+
+	data, errs := execute(t, `query($id: ID!) { getAllInstructions(recipeID: $id) { Sequence MediaID } }`,
+		map[string]interface{}{"id": recipes[0].ID.String()})
+
+	assert.Empty(t, errs)
+
+	result := data["getAllInstructions"].([]interface{})
+	assert.Len(t, result, 2)
+	assert.Nil(t, result[0].(map[string]interface{})["MediaID"])
+	assert.NotNil(t, result[1].(map[string]interface{})["MediaID"])
+}
+
+func TestGetAllIngredients_NotFound(t *testing.T) {
+	resetMocks()
+
+	data, errs := execute(t, `{ getAllIngredients { ID } }`, nil)
+
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"getAllIngredients": []interface{}{}}, data)
+}
+
+func TestUpdateRecipe_Partial(t *testing.T) {
+	resetMocks()
+
+	data, errs := execute(t, `mutation($id: ID!) { updateRecipe(id: $id, ServingCount: 6) { RecipeName } }`,
+		map[string]interface{}{"id": recipes[0].ID.String()})
+
+	assert.Empty(t, errs)
+	assert.Equal(t, m.RecipeDTO{ID: recipes[0].ID, ServingCount: 6}, updatedRecipe)
+	assert.Equal(t, map[string]interface{}{"updateRecipe": map[string]interface{}{"RecipeName": "apple pie"}}, data)
+}
+
+func TestDeleteRecipe_OK(t *testing.T) {
+	resetMocks()
+
+	data, errs := execute(t, `mutation($id: ID!) { deleteRecipe(id: $id) }`,
+		map[string]interface{}{"id": recipes[0].ID.String()})
+
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"deleteRecipe": recipes[0].ID.String()}, data)
+}
+
+func TestCreateInstruction_OK(t *testing.T) {
+	resetMocks()
+
+	data, errs := execute(t, `mutation($id: ID!) { createInstruction(RecipeID: $id, Sequence: 1, Description: "preheat the oven") { Sequence Description } }`,
+		map[string]interface{}{"id": recipes[0].ID.String()})
+
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"createInstruction": map[string]interface{}{
+		"Sequence":    float64(1),
+		"Description": "preheat the oven",
+	}}, data)
+}
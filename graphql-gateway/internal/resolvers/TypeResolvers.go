@@ -0,0 +1,288 @@
+package resolvers
+
+import (
+	"context"
+
+	l "graphql-gateway/internal/loaders"
+	m "graphql-gateway/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+func toID(id uuid.UUID) graphql.ID {
+	return graphql.ID(id.String())
+}
+
+// ====== Recipe ======
+
+type RecipeResolver struct {
+	recipe m.RecipeDTO
+}
+
+func (r *RecipeResolver) ID() graphql.ID {
+	return toID(r.recipe.ID)
+}
+
+func (r *RecipeResolver) RecipeName() string {
+	return r.recipe.Name
+}
+
+func (r *RecipeResolver) Description() string {
+	return r.recipe.Description
+}
+
+func (r *RecipeResolver) ServingCount() int32 {
+	return int32(r.recipe.ServingCount)
+}
+
+func (r *RecipeResolver) Ingredients(ctx context.Context) ([]*RecipeIngredientResolver, error) {
+	recipeIngredients, err := l.For(ctx).RecipeIngredients.Load(ctx, r.recipe.ID)()
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*RecipeIngredientResolver, 0, len(recipeIngredients))
+	for _, recipeIngredient := range recipeIngredients {
+		resolvers = append(resolvers, &RecipeIngredientResolver{recipeIngredient: recipeIngredient})
+	}
+
+	return resolvers, nil
+}
+
+func (r *RecipeResolver) Categories(ctx context.Context) ([]*CategoryResolver, error) {
+	metadata, err := r.loadMetadata(ctx)
+	if err != nil || metadata == nil {
+		return []*CategoryResolver{}, err
+	}
+
+	return categoryResolvers(metadata.Categories), nil
+}
+
+func (r *RecipeResolver) Tags(ctx context.Context) ([]*TagResolver, error) {
+	metadata, err := r.loadMetadata(ctx)
+	if err != nil || metadata == nil {
+		return []*TagResolver{}, err
+	}
+
+	return tagResolvers(metadata.Tags), nil
+}
+
+func (r *RecipeResolver) CuisineType(ctx context.Context) (*CuisineTypeResolver, error) {
+	metadata, err := r.loadMetadata(ctx)
+	if err != nil || metadata == nil || metadata.CuisineType.ID == uuid.Nil {
+		return nil, err
+	}
+
+	return &CuisineTypeResolver{cuisineType: metadata.CuisineType}, nil
+}
+
+func (r *RecipeResolver) DifficultyLevel(ctx context.Context) (*DifficultyLevelResolver, error) {
+	metadata, err := r.loadMetadata(ctx)
+	if err != nil || metadata == nil || metadata.DifficultyLevel.ID == uuid.Nil {
+		return nil, err
+	}
+
+	return &DifficultyLevelResolver{difficultyLevel: metadata.DifficultyLevel}, nil
+}
+
+func (r *RecipeResolver) PreparationTime(ctx context.Context) (*PreparationTimeResolver, error) {
+	metadata, err := r.loadMetadata(ctx)
+	if err != nil || metadata == nil || metadata.PreparationTime.ID == uuid.Nil {
+		return nil, err
+	}
+
+	return &PreparationTimeResolver{preparationTime: metadata.PreparationTime}, nil
+}
+
+// loadMetadata goes through the request loader, so all metadata fields of all recipes in a query share one downstream request
+func (r *RecipeResolver) loadMetadata(ctx context.Context) (*m.RecipeMetadataDTO, error) {
+	return l.For(ctx).RecipeMetadata.Load(ctx, r.recipe.ID)()
+}
+
+// ====== RecipeIngredient ======
+
+type RecipeIngredientResolver struct {
+	recipeIngredient m.RecipeIngredientDTO
+}
+
+func (r *RecipeIngredientResolver) Ingredient() *IngredientResolver {
+	return &IngredientResolver{ingredient: m.IngredientDTO{
+		ID:   r.recipeIngredient.IngredientID,
+		Name: r.recipeIngredient.IngredientName,
+	}}
+}
+
+func (r *RecipeIngredientResolver) Quantity() float64 {
+	return r.recipeIngredient.Quantity
+}
+
+// MaxQuantity is the upper bound of a range such as 2-3, and null for a single quantity
+func (r *RecipeIngredientResolver) MaxQuantity() *float64 {
+	if r.recipeIngredient.MaxQuantity == 0 {
+		return nil
+	}
+	return &r.recipeIngredient.MaxQuantity
+}
+
+func (r *RecipeIngredientResolver) ToTaste() bool {
+	return r.recipeIngredient.ToTaste
+}
+
+// Amount is the quantity written the way a cook would, such as 1½, 2–3 or "to taste"
+func (r *RecipeIngredientResolver) Amount() string {
+	return r.recipeIngredient.Amount
+}
+
+func (r *RecipeIngredientResolver) Unit() *UnitResolver {
+	return &UnitResolver{unit: r.recipeIngredient.Unit}
+}
+
+// ====== Instruction ======
+
+type InstructionResolver struct {
+	instruction m.InstructionDTO
+}
+
+func (r *InstructionResolver) ID() graphql.ID {
+	return toID(r.instruction.ID)
+}
+
+func (r *InstructionResolver) Sequence() int32 {
+	return int32(r.instruction.Sequence)
+}
+
+func (r *InstructionResolver) Description() string {
+	return r.instruction.Description
+}
+
+func (r *InstructionResolver) MediaID() *graphql.ID {
+	if r.instruction.MediaID == uuid.Nil {
+		return nil
+	}
+
+	id := toID(r.instruction.MediaID)
+	return &id
+}
+
+// ====== Ingredient ======
+
+type IngredientResolver struct {
+	ingredient m.IngredientDTO
+}
+
+func (r *IngredientResolver) ID() graphql.ID {
+	return toID(r.ingredient.ID)
+}
+
+func (r *IngredientResolver) IngredientName() string {
+	return r.ingredient.Name
+}
+
+// ====== Unit ======
+
+type UnitResolver struct {
+	unit m.UnitDTO
+}
+
+func (r *UnitResolver) ID() graphql.ID {
+	return toID(r.unit.ID)
+}
+
+func (r *UnitResolver) FullName() string {
+	return r.unit.FullName
+}
+
+func (r *UnitResolver) ShortName() string {
+	return r.unit.ShortName
+}
+
+// ====== Category ======
+
+type CategoryResolver struct {
+	category m.CategoryDTO
+}
+
+func categoryResolvers(categories []m.CategoryDTO) []*CategoryResolver {
+	resolvers := make([]*CategoryResolver, 0, len(categories))
+
+	for _, category := range categories {
+		resolvers = append(resolvers, &CategoryResolver{category: category})
+	}
+
+	return resolvers
+}
+
+func (r *CategoryResolver) ID() graphql.ID {
+	return toID(r.category.ID)
+}
+
+func (r *CategoryResolver) CategoryName() string {
+	return r.category.Name
+}
+
+// ====== Tag ======
+
+type TagResolver struct {
+	tag m.TagDTO
+}
+
+func tagResolvers(tags []m.TagDTO) []*TagResolver {
+	resolvers := make([]*TagResolver, 0, len(tags))
+
+	for _, tag := range tags {
+		resolvers = append(resolvers, &TagResolver{tag: tag})
+	}
+
+	return resolvers
+}
+
+func (r *TagResolver) ID() graphql.ID {
+	return toID(r.tag.ID)
+}
+
+func (r *TagResolver) TagName() string {
+	return r.tag.Name
+}
+
+// ====== CuisineType ======
+
+type CuisineTypeResolver struct {
+	cuisineType m.CuisineTypeDTO
+}
+
+func (r *CuisineTypeResolver) ID() graphql.ID {
+	return toID(r.cuisineType.ID)
+}
+
+func (r *CuisineTypeResolver) CuisineTypeName() string {
+	return r.cuisineType.Name
+}
+
+// ====== DifficultyLevel ======
+
+type DifficultyLevelResolver struct {
+	difficultyLevel m.DifficultyLevelDTO
+}
+
+func (r *DifficultyLevelResolver) ID() graphql.ID {
+	return toID(r.difficultyLevel.ID)
+}
+
+func (r *DifficultyLevelResolver) Level() int32 {
+	return int32(r.difficultyLevel.Level)
+}
+
+// ====== PreparationTime ======
+
+type PreparationTimeResolver struct {
+	preparationTime m.PreparationTimeDTO
+}
+
+func (r *PreparationTimeResolver) ID() graphql.ID {
+	return toID(r.preparationTime.ID)
+}
+
+func (r *PreparationTimeResolver) Duration() int32 {
+	return int32(r.preparationTime.Duration)
+}
@@ -0,0 +1,10 @@
+package schema
+
+import (
+	_ "embed"
+)
+
+// Schema holds the GraphQL schema served by the gateway
+//
+//go:embed schema.graphqls
+var Schema string
@@ -0,0 +1,110 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	m "image-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// purgedStatuses are the states of a deletion in which the entity can no longer be restored
+var purgedStatuses = map[string]bool{"purging": true, "purged": true}
+
+// EntityClient looks up the entities images are linked to at the services owning them
+type EntityClient struct {
+	httpClient    HTTPClient
+	urls          map[string]string
+	deletionPaths map[string]string
+}
+
+func NewEntityClient(httpClient HTTPClient, entities []m.EntityConfig) *EntityClient {
+	urls := make(map[string]string, len(entities))
+	deletionPaths := make(map[string]string, len(entities))
+
+	for _, entity := range entities {
+		urls[entity.Type] = strings.TrimSuffix(entity.Url, "/")
+		if entity.DeletionPath != "" {
+			deletionPaths[entity.Type] = strings.Trim(entity.DeletionPath, "/")
+		}
+	}
+
+	return &EntityClient{
+		httpClient:    httpClient,
+		urls:          urls,
+		deletionPaths: deletionPaths,
+	}
+}
+
+// Exists reports whether the entity can still be retrieved from its service. A deleted entity that can still be
+// restored exists as well, its images are restored along with it
+func (c EntityClient) Exists(ctx context.Context, entityType string, entityID uuid.UUID) (bool, error) {
+
+	url, found := c.urls[entityType]
+	if !found {
+		return false, fmt.Errorf("unknown entity type %s", entityType)
+	}
+
+	resp, err := c.get(ctx, fmt.Sprintf("%s/%s", url, entityID))
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return c.restorable(ctx, entityType, url, entityID)
+	default:
+		return false, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+}
+
+// restorable reports whether a deleted entity can still be restored, according to its deletion
+func (c EntityClient) restorable(ctx context.Context, entityType string, url string, entityID uuid.UUID) (bool, error) {
+	var deletion struct {
+		Status string `json:"status"`
+	}
+
+	path, found := c.deletionPaths[entityType]
+	if !found {
+		return false, nil
+	}
+
+	resp, err := c.get(ctx, fmt.Sprintf("%s/%s/%s", url, entityID, path))
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if err := json.NewDecoder(resp.Body).Decode(&deletion); err != nil {
+			return false, fmt.Errorf("unable to decode deletion: %w", err)
+		}
+		return !purgedStatuses[deletion.Status], nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+}
+
+func (c EntityClient) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	return c.httpClient.Do(req)
+}
@@ -0,0 +1,146 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	m "image-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	entityID uuid.UUID = uuid.New()
+)
+
+func newTestClient(url string) *EntityClient {
+	return NewEntityClient(http.DefaultClient, []m.EntityConfig{
+		{Type: "recipe", Url: url + "/api/v2/recipe/", DeletionPath: "deletion"},
+		{Type: "tag", Url: url + "/api/v2/metadata/tag/"},
+	})
+}
+
+func TestExists_OK(t *testing.T) {
+	var path string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	result, err := c.Exists(context.Background(), "recipe", entityID)
+
+	assert.NoError(t, err)
+	assert.True(t, result)
+	assert.Equal(t, "/api/v2/recipe/"+entityID.String(), path)
+}
+
+func TestExists_NotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	result, err := c.Exists(context.Background(), "recipe", entityID)
+
+	assert.NoError(t, err)
+	assert.False(t, result)
+}
+
+func TestExists_Restorable(t *testing.T) {
+	var paths []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/api/v2/recipe/"+entityID.String() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"status":"deleted"}`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	result, err := c.Exists(context.Background(), "recipe", entityID)
+
+	assert.NoError(t, err)
+	assert.True(t, result)
+	assert.Equal(t, []string{"/api/v2/recipe/" + entityID.String(), "/api/v2/recipe/" + entityID.String() + "/deletion"}, paths)
+}
+
+func TestExists_Purged(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/recipe/"+entityID.String() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"status":"purged"}`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	result, err := c.Exists(context.Background(), "recipe", entityID)
+
+	assert.NoError(t, err)
+	assert.False(t, result)
+}
+
+func TestExists_NotRestorable(t *testing.T) {
+	calls := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	result, err := c.Exists(context.Background(), "tag", entityID)
+
+	assert.NoError(t, err)
+	assert.False(t, result)
+	assert.Equal(t, 1, calls)
+}
+
+func TestExists_DeletionErr(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/recipe/"+entityID.String() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	_, err := c.Exists(context.Background(), "recipe", entityID)
+
+	assert.EqualError(t, err, "unexpected status code 502")
+}
+
+func TestExists_Err(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	_, err := c.Exists(context.Background(), "recipe", entityID)
+	assert.EqualError(t, err, "unexpected status code 401")
+
+	_, err = c.Exists(context.Background(), "ingredient", entityID)
+	assert.EqualError(t, err, "unknown entity type ingredient")
+}
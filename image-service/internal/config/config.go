@@ -1,22 +1,47 @@
 package config
 
 import (
+	"context"
+	"cookbook/pkg/outbox"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	cl "image-service/internal/clients"
+	hl "image-service/internal/handlers"
 	h "image-service/internal/helpers"
 	m "image-service/internal/models"
+	p "image-service/internal/processing"
+	fr "image-service/internal/repositories/filesystem"
+	ir "image-service/internal/repositories/image"
+	sr "image-service/internal/repositories/s3"
+	ur "image-service/internal/repositories/upload"
+	s "image-service/internal/services"
+	"net/http"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/fsnotify/fsnotify"
 	"github.com/gin-contrib/cors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"golang.org/x/oauth2/clientcredentials"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
+const (
+	BACKEND_S3         = "s3"
+	BACKEND_FILESYSTEM = "filesystem"
+
+	FILES_PATH = "/api/v2/image/files"
+)
+
 const (
 	INIT_NOK = "> Init NOK"
 	INIT_OK  = "> Init OK"
@@ -29,6 +54,37 @@ var (
 	DatabaseClient *gorm.DB
 	S3Client       *s3.S3
 	Cors           cors.Config
+	UrlExpiry      time.Duration
+
+	// Repositories
+	ImageRepository      *ir.ImageRepository
+	UploadRepository     *ur.UploadRepository
+	S3Repository         *sr.S3Repository
+	FilesystemRepository *fr.FilesystemRepository
+	BlobStore            s.BlobStore
+
+	// Clients
+	EntityClient *cl.EntityClient
+
+	// Events
+	Relay         *outbox.Relay
+	RelayInterval time.Duration
+
+	// Processing
+	ImageProcessor *p.ImageProcessor
+	ImageValidator *p.ImageValidator
+
+	// Services
+	ImageService       *s.ImageService
+	EntityImageService *s.EntityImageService
+	GarbageCollector   *s.GarbageCollector
+	GarbageInterval    time.Duration
+
+	// Handlers
+	ImageHandlers       *hl.ImageHandlers
+	EntityImageHandlers *hl.EntityImageHandlers
+	FileHandlers        *hl.FileHandlers
+	GarbageHandlers     *hl.GarbageHandlers
 )
 
 func initLogging() {
@@ -100,6 +156,9 @@ func initDatabase() {
 
 	if err := DatabaseClient.AutoMigrate(
 		&m.Image{},
+		&m.ImageVariant{},
+		&m.ImageUpload{},
+		&outbox.Message{},
 	); err != nil {
 		Logger.Errorf("Error while automigrating database: %s", err.Error())
 		Logger.Fatal(INIT_NOK)
@@ -108,6 +167,269 @@ func initDatabase() {
 	Logger.Info(INIT_OK)
 }
 
+func initStorage() {
+	UrlExpiry = urlExpiry()
+
+	switch Configuration.Storage.Backend {
+	case BACKEND_S3, "":
+		initS3()
+
+		S3Repository = sr.NewS3Repository(S3Client, Logger, Configuration.S3.BucketName, UrlExpiry)
+		BlobStore = S3Repository
+
+	case BACKEND_FILESYSTEM:
+		if err := os.MkdirAll(Configuration.Filesystem.Path, 0o755); err != nil {
+			Logger.Errorf("Unable to create the storage directory: %s", err.Error())
+			Logger.Fatal(INIT_NOK)
+		}
+
+		FilesystemRepository = fr.NewFilesystemRepository(Configuration.Filesystem.Path, filesUrl(), signingKey(), UrlExpiry)
+		BlobStore = FilesystemRepository
+
+		Logger.Info(INIT_OK)
+
+	default:
+		Logger.Errorf("Invalid storage backend %s. Valid backends are: %s %s", Configuration.Storage.Backend, BACKEND_S3, BACKEND_FILESYSTEM)
+		Logger.Fatal(INIT_NOK)
+	}
+}
+
+func initS3() {
+	config := aws.NewConfig().
+		WithRegion(Configuration.S3.AWSRegion).
+		WithCredentials(credentials.NewStaticCredentials(Configuration.S3.AWSAccessKey, Configuration.S3.AWSAccessSecret, ""))
+
+	if Configuration.S3.Endpoint != "" {
+		config = config.WithEndpoint(Configuration.S3.Endpoint).WithS3ForcePathStyle(true)
+	}
+
+	sess, err := session.NewSession(config)
+	if err != nil {
+		Logger.Errorf("Unable to create the S3 session: %s", err.Error())
+		Logger.Fatal(INIT_NOK)
+	}
+
+	S3Client = s3.New(sess)
+
+	Logger.Info(INIT_OK)
+}
+
+// urlExpiry returns how long the download and upload urls handed out are valid
+func urlExpiry() time.Duration {
+	if Configuration.Storage.UrlExpiry <= 0 {
+		Logger.Warn("no or invalid url expiry specified. Assuming default value of 900 seconds")
+		return 15 * time.Minute
+	}
+
+	return time.Duration(Configuration.Storage.UrlExpiry) * time.Second
+}
+
+// signingKey returns the configured key the file urls are signed with. Without one a random key is generated,
+// so urls handed out become invalid on restart and when running multiple instances
+func signingKey() string {
+	if Configuration.Filesystem.SigningKey != "" {
+		return Configuration.Filesystem.SigningKey
+	}
+
+	Logger.Warn("no signing key specified. Generating a random key, file urls won't survive a restart")
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		Logger.Errorf("Unable to generate a signing key: %s", err.Error())
+		Logger.Fatal(INIT_NOK)
+	}
+
+	return hex.EncodeToString(key)
+}
+
+// objectStore returns the storage backend as far as the garbage collection needs it
+func objectStore() s.ObjectStore {
+	if FilesystemRepository != nil {
+		return FilesystemRepository
+	}
+
+	return S3Repository
+}
+
+// garbageInterval returns the time between the garbage collection runs
+func garbageInterval() time.Duration {
+	if Configuration.Garbage.Interval <= 0 {
+		Logger.Warn("no or invalid garbage collection interval specified. Assuming default value of 60 minutes")
+		return time.Hour
+	}
+
+	return time.Duration(Configuration.Garbage.Interval) * time.Minute
+}
+
+// gracePeriod returns how long images and objects are kept before the garbage collection considers them
+func gracePeriod() time.Duration {
+	if Configuration.Garbage.GracePeriod <= 0 {
+		Logger.Warn("no or invalid garbage collection grace period specified. Assuming default value of 24 hours")
+		return 24 * time.Hour
+	}
+
+	return time.Duration(Configuration.Garbage.GracePeriod) * time.Hour
+}
+
+// entityHttpClient returns the client the entities are looked up with. With a client configured it authenticates
+// with the client credentials at keycloak
+func entityHttpClient() *http.Client {
+	if Configuration.Garbage.ClientID == "" {
+		Logger.Warn("no garbage collection client specified. Entities are looked up without authentication")
+		return &http.Client{Timeout: 10 * time.Second}
+	}
+
+	credentials := clientcredentials.Config{
+		ClientID:     Configuration.Garbage.ClientID,
+		ClientSecret: Configuration.Garbage.ClientSecret,
+		TokenURL:     fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", strings.TrimSuffix(Configuration.Oauth.Url, "/"), Configuration.Oauth.Realm),
+	}
+
+	client := credentials.Client(context.Background())
+	client.Timeout = 10 * time.Second
+
+	return client
+}
+
+// eventHttpClient returns the client the events are posted with. With a client configured it authenticates
+// with the client credentials at keycloak
+func eventHttpClient() *http.Client {
+	if Configuration.Events.ClientID == "" {
+		if len(Configuration.Events.Subscribers) > 0 {
+			Logger.Warn("no event client specified. Events are sent without authentication")
+		}
+		return &http.Client{Timeout: eventTimeout()}
+	}
+
+	credentials := clientcredentials.Config{
+		ClientID:     Configuration.Events.ClientID,
+		ClientSecret: Configuration.Events.ClientSecret,
+		TokenURL:     fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", strings.TrimSuffix(Configuration.Oauth.Url, "/"), Configuration.Oauth.Realm),
+	}
+
+	client := credentials.Client(context.Background())
+	client.Timeout = eventTimeout()
+
+	return client
+}
+
+// eventTimeout returns how long posting an event to a subscriber may take
+func eventTimeout() time.Duration {
+	if Configuration.Events.Timeout <= 0 {
+		Logger.Warn("no or invalid event timeout specified. Assuming default value of 30 seconds")
+		return 30 * time.Second
+	}
+
+	return time.Duration(Configuration.Events.Timeout) * time.Second
+}
+
+// eventBroker returns the broker the outbox relay publishes the events to
+func eventBroker() outbox.Broker {
+	switch Configuration.Events.Broker {
+	case "nats":
+		broker, err := outbox.NewNatsBroker(Configuration.Events.NatsUrl)
+		if err != nil {
+			Logger.Fatalf("unable to connect to the event broker at %s: %v", Configuration.Events.NatsUrl, err)
+		}
+		return broker
+	case "", "http":
+		return outbox.NewHttpBroker(eventHttpClient(), Configuration.Events.Subscribers, Logger)
+	default:
+		Logger.Fatalf("unknown event broker %s. Use nats or http", Configuration.Events.Broker)
+		return nil
+	}
+}
+
+// relayInterval returns the time between the runs of the outbox relay
+func relayInterval() time.Duration {
+	if Configuration.Events.Interval <= 0 {
+		Logger.Warn("no or invalid relay interval specified. Assuming default value of 1000 milliseconds")
+		return time.Second
+	}
+
+	return time.Duration(Configuration.Events.Interval) * time.Millisecond
+}
+
+// relayBatchSize returns the number of events the outbox relay publishes per run
+func relayBatchSize() int {
+	if Configuration.Events.BatchSize <= 0 {
+		Logger.Warn("no or invalid relay batch size specified. Assuming default value of 100")
+		return 100
+	}
+
+	return Configuration.Events.BatchSize
+}
+
+// filesUrl returns the configured public url of the file handlers or the path they are registered on
+func filesUrl() string {
+	if Configuration.Filesystem.PublicUrl != "" {
+		return Configuration.Filesystem.PublicUrl
+	}
+
+	return FILES_PATH
+}
+
+// imagesConfig returns the image processing configuration, falling back to defaults for the missing items
+func imagesConfig() m.ImagesConfig {
+	config := Configuration.Images
+
+	if config.Quality <= 0 || config.Quality > 100 {
+		Logger.Warn("no or invalid image quality specified. Assuming default value of 80")
+		config.Quality = 80
+	}
+
+	if len(config.Formats) == 0 {
+		config.Formats = []string{"jpeg", "webp"}
+	}
+
+	if len(config.Variants) == 0 {
+		config.Variants = []m.VariantConfig{
+			{Name: "thumbnail", MaxWidth: 200, MaxHeight: 200},
+			{Name: "card", MaxWidth: 800, MaxHeight: 800},
+			{Name: "full", MaxWidth: 2048, MaxHeight: 2048},
+		}
+	}
+
+	return config
+}
+
+// uploadConfig returns the limits uploads are validated against, falling back to defaults for the missing items
+func uploadConfig() m.UploadConfig {
+	config := Configuration.Upload
+
+	if config.MaxBytes <= 0 {
+		Logger.Warn("no or invalid upload size limit specified. Assuming default value of 20 MB")
+		config.MaxBytes = 20 << 20
+	}
+
+	if config.MaxMegapixels <= 0 {
+		Logger.Warn("no or invalid upload dimension limit specified. Assuming default value of 50 megapixels")
+		config.MaxMegapixels = 50
+	}
+
+	if config.MaxCompressionRatio <= 0 {
+		config.MaxCompressionRatio = 5000
+	}
+
+	// only formats the processing can decode are accepted
+	var formats []string
+	for _, format := range config.Formats {
+		switch format {
+		case "jpeg", "png", "webp":
+			formats = append(formats, format)
+		default:
+			Logger.Warnf("upload format %s is not supported and ignored. Supported formats are: jpeg png webp", format)
+		}
+	}
+
+	if len(formats) == 0 {
+		formats = []string{"jpeg", "png", "webp"}
+	}
+	config.Formats = formats
+
+	return config
+}
+
 func initCors() {
 	Cors = cors.Config{
 		AllowOrigins:     Configuration.Cors.AllowedOrigins,
@@ -123,5 +445,37 @@ func init() {
 	initViper()
 	initLogging()
 	initDatabase()
+	initStorage()
 	initCors()
+
+	// Init repositories
+	ImageRepository = ir.NewImageRepository(DatabaseClient)
+	UploadRepository = ur.NewUploadRepository(DatabaseClient)
+
+	// Init clients
+	EntityClient = cl.NewEntityClient(entityHttpClient(), Configuration.Garbage.Entities)
+
+	// Init events
+	Relay = outbox.NewRelay(DatabaseClient, eventBroker(), "/image-service", relayBatchSize(), Logger)
+	RelayInterval = relayInterval()
+
+	// Init processing
+	ImageProcessor = p.NewImageProcessor(imagesConfig())
+	upload := uploadConfig()
+	ImageValidator = p.NewImageValidator(upload)
+
+	// Init services
+	ImageService = s.NewImageService(BlobStore, ImageRepository, UploadRepository, ImageProcessor, ImageValidator, UrlExpiry, Logger)
+	EntityImageService = s.NewEntityImageService(ImageRepository, gracePeriod(), Logger)
+	GarbageCollector = s.NewGarbageCollector(objectStore(), ImageRepository, UploadRepository, EntityClient, gracePeriod(), Logger)
+	GarbageInterval = garbageInterval()
+
+	// Init handlers
+	ImageHandlers = hl.NewImageHandlers(ImageService, upload.MaxBytes, Logger)
+	EntityImageHandlers = hl.NewEntityImageHandlers(EntityImageService, Logger)
+	GarbageHandlers = hl.NewGarbageHandlers(GarbageCollector, Logger)
+
+	if FilesystemRepository != nil {
+		FileHandlers = hl.NewFileHandlers(FilesystemRepository, upload.MaxBytes, UrlExpiry, Logger)
+	}
 }
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	m "image-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type EntityImageService interface {
+	DeleteByEntity(entityType string, entityID uuid.UUID) error
+	RestoreByEntity(entityType string, entityID uuid.UUID, since time.Time) error
+}
+
+type EntityImageHandlers struct {
+	entityImageService EntityImageService
+	logger             m.LoggerInterface
+}
+
+func NewEntityImageHandlers(entityImages EntityImageService, logger m.LoggerInterface) *EntityImageHandlers {
+	return &EntityImageHandlers{
+		entityImageService: entityImages,
+		logger:             logger,
+	}
+}
+
+// Delete removes all images of a deleted entity
+func (h EntityImageHandlers) Delete(ctx *gin.Context) {
+
+	entityType, entityID, ok := entityParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.entityImageService.DeleteByEntity(entityType, entityID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Restore brings back the images of a restored entity. The optional since parameter limits the restore to the
+// images deleted along with the entity
+func (h EntityImageHandlers) Restore(ctx *gin.Context) {
+
+	entityType, entityID, ok := entityParams(ctx)
+	if !ok {
+		return
+	}
+
+	var since time.Time
+	if value := ctx.Query("since"); value != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, value); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid since parameter"})
+			return
+		}
+	}
+
+	if err := h.entityImageService.RestoreByEntity(entityType, entityID, since); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// entityParams reads the entity from the path, responding with a bad request when it is invalid
+func entityParams(ctx *gin.Context) (string, uuid.UUID, bool) {
+
+	entityType := ctx.Param("type")
+	if entityType == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid entity type"})
+		return "", uuid.Nil, false
+	}
+
+	entityID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid entity ID"})
+		return "", uuid.Nil, false
+	}
+
+	return entityType, entityID, true
+}
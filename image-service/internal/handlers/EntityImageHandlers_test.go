@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	m "image-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type EntityImageServiceMock struct{}
+
+var (
+	restoredSince time.Time
+)
+
+func (EntityImageServiceMock) DeleteByEntity(entityType string, entityID uuid.UUID) error {
+	switch mode {
+	case "delete":
+		return nil
+	default:
+		return errors.New("internal server error")
+	}
+}
+
+func (EntityImageServiceMock) RestoreByEntity(entityType string, entityID uuid.UUID, since time.Time) error {
+	restoredSince = since
+
+	switch mode {
+	case "restore":
+		return nil
+	default:
+		return errors.New("internal server error")
+	}
+}
+
+func newEntityTestContext(method string, url string, entityType string, entityID string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, url, nil)
+	c.Params = gin.Params{
+		gin.Param{Key: "type", Value: entityType},
+		gin.Param{Key: "id", Value: entityID},
+	}
+
+	return c, w
+}
+
+func TestEntityImageDelete_OK(t *testing.T) {
+	h := NewEntityImageHandlers(&EntityImageServiceMock{}, &m.LoggerInterfaceMock{})
+	c, _ := newEntityTestContext("DELETE", "http://example.com/api/v2/image/entity/recipe/1", "recipe", uuid.NewString())
+
+	mode = "delete"
+
+	h.Delete(c)
+
+	assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+}
+
+func TestEntityImageDelete_IDErr(t *testing.T) {
+	h := NewEntityImageHandlers(&EntityImageServiceMock{}, &m.LoggerInterfaceMock{})
+	c, w := newEntityTestContext("DELETE", "http://example.com/api/v2/image/entity/recipe/1", "recipe", "1")
+
+	mode = "delete"
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid entity ID"}`, string(body))
+}
+
+func TestEntityImageDelete_Err(t *testing.T) {
+	h := NewEntityImageHandlers(&EntityImageServiceMock{}, &m.LoggerInterfaceMock{})
+	c, w := newEntityTestContext("DELETE", "http://example.com/api/v2/image/entity/recipe/1", "recipe", uuid.NewString())
+
+	mode = "error"
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, `{"error":"internal server error"}`, string(body))
+}
+
+func TestEntityImageRestore_OK(t *testing.T) {
+	h := NewEntityImageHandlers(&EntityImageServiceMock{}, &m.LoggerInterfaceMock{})
+	c, _ := newEntityTestContext("POST", "http://example.com/api/v2/image/entity/recipe/1/restore?since=2024-05-01T10:00:00Z", "recipe", uuid.NewString())
+
+	mode = "restore"
+
+	h.Restore(c)
+
+	assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), restoredSince)
+}
+
+func TestEntityImageRestore_SinceErr(t *testing.T) {
+	h := NewEntityImageHandlers(&EntityImageServiceMock{}, &m.LoggerInterfaceMock{})
+	c, w := newEntityTestContext("POST", "http://example.com/api/v2/image/entity/recipe/1/restore?since=yesterday", "recipe", uuid.NewString())
+
+	mode = "restore"
+
+	h.Restore(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid since parameter"}`, string(body))
+}
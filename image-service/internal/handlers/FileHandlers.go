@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	m "image-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FileStore interface {
+	OpenObject(key string) (io.ReadSeekCloser, fs.FileInfo, error)
+	StoreObject(key string, body io.Reader) error
+	VerifyURL(method string, key string, expires string, signature string) error
+}
+
+// FileHandlers serve the objects of the filesystem storage backend. Like presigned S3 urls, every request has to
+// carry a valid signature handed out by the storage backend
+type FileHandlers struct {
+	fileStore FileStore
+	maxBytes  int64         // the size of the largest upload accepted
+	urlExpiry time.Duration // how long a signed url stays valid
+	logger    m.LoggerInterface
+}
+
+func NewFileHandlers(files FileStore, maxBytes int64, urlExpiry time.Duration, logger m.LoggerInterface) *FileHandlers {
+	return &FileHandlers{
+		fileStore: files,
+		maxBytes:  maxBytes,
+		urlExpiry: urlExpiry,
+		logger:    logger,
+	}
+}
+
+func (h FileHandlers) Get(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+
+	if !h.verify(ctx, http.MethodGet, key) {
+		return
+	}
+
+	file, info, err := h.fileStore.OpenObject(key)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+			return
+		default:
+			h.logger.Warnf("unable to open file %s: %s", key, err.Error())
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	defer file.Close()
+
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		ctx.Header("Content-Type", contentType)
+	}
+
+	// the url is signed and expires, so the object is only cached privately and for no longer than the signature is
+	// valid. It isn't immutable either: a variant stored under img/<hash>/<name>.<ext> is written again when the same
+	// image is processed after the quality or the variants were configured differently
+	ctx.Header("Cache-Control", "private, max-age="+strconv.Itoa(int(h.urlExpiry.Seconds())))
+	ctx.Header("ETag", fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+
+	// handles range requests as well as conditional requests against the ETag and modification time
+	http.ServeContent(ctx.Writer, ctx.Request, info.Name(), info.ModTime(), file)
+}
+
+func (h FileHandlers) Put(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+
+	if !h.verify(ctx, http.MethodPut, key) {
+		return
+	}
+
+	// the upload is only validated once it is confirmed, the size is limited right away so it can't fill the disk
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, h.maxBytes)
+
+	if err := h.fileStore.StoreObject(key, body); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large"})
+			return
+		}
+
+		h.logger.Warnf("unable to store file %s: %s", key, err.Error())
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// verify checks the signature of the request url, responding with 403 if it isn't valid for the method and key
+func (h FileHandlers) verify(ctx *gin.Context, method string, key string) bool {
+
+	err := h.fileStore.VerifyURL(method, key, ctx.Query("expires"), ctx.Query("signature"))
+	if err != nil {
+		h.logger.Debugf("rejected %s of file %s: %s", method, key, err.Error())
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return false
+	}
+
+	return true
+}
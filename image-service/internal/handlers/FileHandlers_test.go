@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	m "image-service/internal/models"
+	fr "image-service/internal/repositories/filesystem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFileRouter(t *testing.T) (*gin.Engine, *fr.FilesystemRepository) {
+	gin.SetMode(gin.TestMode)
+
+	store := fr.NewFilesystemRepository(t.TempDir(), "http://example.com/api/v2/image/files", "secret", 15*time.Minute)
+	if err := store.UploadObject("img/id/card.webp", strings.NewReader("0123456789"), "image/webp"); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewFileHandlers(store, maxBytes, 15*time.Minute, &m.LoggerInterfaceMock{})
+
+	router := gin.New()
+	router.GET("/api/v2/image/files/*key", h.Get)
+	router.PUT("/api/v2/image/files/*key", h.Put)
+
+	return router, store
+}
+
+func TestFileGet_OK(t *testing.T) {
+	router, store := newFileRouter(t)
+
+	req := httptest.NewRequest("GET", store.ObjectURL("img/id/card.webp"), nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "0123456789", string(body))
+	assert.Equal(t, "image/webp", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "bytes", resp.Header.Get("Accept-Ranges"))
+	assert.Equal(t, "private, max-age=900", resp.Header.Get("Cache-Control"))
+	assert.NotEmpty(t, resp.Header.Get("ETag"))
+	assert.NotEmpty(t, resp.Header.Get("Last-Modified"))
+}
+
+func TestFileGet_Range(t *testing.T) {
+	router, store := newFileRouter(t)
+
+	req := httptest.NewRequest("GET", store.ObjectURL("img/id/card.webp"), nil)
+	req.Header.Set("Range", "bytes=2-5")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, "2345", string(body))
+	assert.Equal(t, "bytes 2-5/10", resp.Header.Get("Content-Range"))
+}
+
+func TestFileGet_RangeErr(t *testing.T) {
+	router, store := newFileRouter(t)
+
+	req := httptest.NewRequest("GET", store.ObjectURL("img/id/card.webp"), nil)
+	req.Header.Set("Range", "bytes=20-30")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Result().StatusCode)
+}
+
+func TestFileGet_NotModified(t *testing.T) {
+	router, store := newFileRouter(t)
+
+	req := httptest.NewRequest("GET", store.ObjectURL("img/id/card.webp"), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	req = httptest.NewRequest("GET", store.ObjectURL("img/id/card.webp"), nil)
+	req.Header.Set("If-None-Match", w.Result().Header.Get("ETag"))
+	w = httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+	assert.Empty(t, body)
+}
+
+func TestFileGet_NotFound(t *testing.T) {
+	router, store := newFileRouter(t)
+
+	for _, path := range []string{"img/id/full.webp", "img/id", "../../etc/passwd"} {
+		req := httptest.NewRequest("GET", store.ObjectURL(path), nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode, path)
+	}
+}
+
+func TestFileGet_SignatureErr(t *testing.T) {
+	router, store := newFileRouter(t)
+	expired := fr.NewFilesystemRepository(store.Root, store.PublicUrl, "secret", -time.Minute)
+
+	for _, url := range []string{
+		"http://example.com/api/v2/image/files/img/id/card.webp",
+		strings.Replace(store.ObjectURL("img/id/card.webp"), "card.webp", "full.webp", 1),
+		expired.ObjectURL("img/id/card.webp"),
+	} {
+		req := httptest.NewRequest("GET", url, nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Result().StatusCode, url)
+	}
+}
+
+func TestFilePut_OK(t *testing.T) {
+	router, store := newFileRouter(t)
+
+	uploadURL, _ := store.UploadURL("uploads/id", "image/png")
+	req := httptest.NewRequest("PUT", uploadURL, strings.NewReader("image"))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	file, err := store.DownloadObject("uploads/id")
+	assert.NoError(t, err)
+
+	body, _ := io.ReadAll(file)
+	file.Close()
+	assert.Equal(t, "image", string(body))
+}
+
+func TestFilePut_TooLargeErr(t *testing.T) {
+	router, store := newFileRouter(t)
+
+	uploadURL, _ := store.UploadURL("uploads/id", "image/png")
+	req := httptest.NewRequest("PUT", uploadURL, strings.NewReader(strings.Repeat("x", maxBytes+1)))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	assert.Equal(t, `{"error":"file too large"}`, string(body))
+
+	// nothing is left behind
+	_, err := store.DownloadObject("uploads/id")
+	assert.EqualError(t, err, "not found")
+}
+
+func TestFilePut_SignatureErr(t *testing.T) {
+	router, store := newFileRouter(t)
+
+	// a download url doesn't allow uploads
+	req := httptest.NewRequest("PUT", store.ObjectURL("uploads/id"), strings.NewReader("image"))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Result().StatusCode)
+
+	_, err := store.DownloadObject("uploads/id")
+	assert.EqualError(t, err, "not found")
+}
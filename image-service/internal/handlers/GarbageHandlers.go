@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	m "image-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GarbageCollector interface {
+	Report(ctx context.Context) (m.GarbageReportDTO, error)
+}
+
+type GarbageHandlers struct {
+	garbageCollector GarbageCollector
+	logger           m.LoggerInterface
+}
+
+func NewGarbageHandlers(garbageCollector GarbageCollector, logger m.LoggerInterface) *GarbageHandlers {
+	return &GarbageHandlers{
+		garbageCollector: garbageCollector,
+		logger:           logger,
+	}
+}
+
+// Report lists what the next garbage collection would remove, without removing anything
+func (h GarbageHandlers) Report(ctx *gin.Context) {
+
+	report, err := h.garbageCollector.Report(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	m "image-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type GarbageCollectorMock struct{}
+
+var (
+	report m.GarbageReportDTO = m.GarbageReportDTO{
+		DryRun:      true,
+		GracePeriod: "24h0m0s",
+		Images:      []m.OrphanedImageDTO{{ID: uuid.New(), EntityType: "recipe", EntityID: uuid.New()}},
+		Objects:     []m.StoredObject{{Key: "img/hash/card.jpg", Size: 5}},
+		Uploads:     []uuid.UUID{},
+		Size:        5,
+	}
+)
+
+func (GarbageCollectorMock) Report(ctx context.Context) (m.GarbageReportDTO, error) {
+	switch mode {
+	case "report":
+		return report, nil
+	default:
+		return m.GarbageReportDTO{}, errors.New("internal server error")
+	}
+}
+
+func TestGarbageReport_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewGarbageHandlers(&GarbageCollectorMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "report"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/image/gc/report", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Report(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(report)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestGarbageReport_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewGarbageHandlers(&GarbageCollectorMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "error"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/image/gc/report", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Report(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, `{"error":"internal server error"}`, string(body))
+}
@@ -1 +1,276 @@
 package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	m "image-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ImageService interface {
+	FindByEntity(entityType string, entityID uuid.UUID) ([]m.ImageDTO, error)
+	FindSingle(image m.ImageDTO) (m.ImageDTO, error)
+	Download(image m.ImageDTO, name string, format string) (m.ImageVariantDTO, io.ReadCloser, error)
+	Create(image m.ImageDTO) (m.ImageDTO, error)
+	RequestUpload(upload m.ImageUploadDTO) (m.ImageUploadDTO, error)
+	ConfirmUpload(upload m.ImageUploadDTO) (m.ImageDTO, error)
+	Delete(image m.ImageDTO) error
+}
+
+// multipartOverhead is the room left next to the file for the other form fields and the multipart boundaries
+const multipartOverhead = 1 << 20
+
+type ImageHandlers struct {
+	imageService ImageService
+	maxBytes     int64 // the size of the largest file accepted
+	logger       m.LoggerInterface
+}
+
+func NewImageHandlers(images ImageService, maxBytes int64, logger m.LoggerInterface) *ImageHandlers {
+	return &ImageHandlers{
+		imageService: images,
+		maxBytes:     maxBytes,
+		logger:       logger,
+	}
+}
+
+func (h ImageHandlers) GetAll(ctx *gin.Context) {
+
+	entityType := ctx.Query("entity_type")
+	if entityType == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid entity type"})
+		return
+	}
+
+	entityID, err := uuid.Parse(ctx.Query("entity_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid entity ID"})
+		return
+	}
+
+	imageDTOs, err := h.imageService.FindByEntity(entityType, entityID)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "no images found"})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, imageDTOs)
+}
+
+func (h ImageHandlers) Get(ctx *gin.Context) {
+	var imageDTO m.ImageDTO
+	var err error
+
+	imageDTO.ID, err = uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid image ID"})
+		return
+	}
+
+	imageDTO, err = h.imageService.FindSingle(imageDTO)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "image not found"})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, imageDTO)
+}
+
+func (h ImageHandlers) Download(ctx *gin.Context) {
+	var imageDTO m.ImageDTO
+	var err error
+
+	imageDTO.ID, err = uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid image ID"})
+		return
+	}
+
+	variantDTO, file, err := h.imageService.Download(imageDTO, ctx.Query("variant"), ctx.Query("format"))
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "image not found"})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	defer file.Close()
+
+	ctx.DataFromReader(http.StatusOK, variantDTO.Size, "image/"+variantDTO.Format, file, nil)
+}
+
+func (h ImageHandlers) Create(ctx *gin.Context) {
+	var imageDTO m.ImageDTO
+	var err error
+
+	// the body is limited before the form is parsed, so an oversized upload is never spooled to disk as a whole
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, h.maxBytes+multipartOverhead)
+
+	if _, err = ctx.MultipartForm(); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large"})
+			return
+		}
+
+		h.logger.Debugf("unable to read uploaded form: %s", err.Error())
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "no file uploaded"})
+		return
+	}
+
+	imageDTO.EntityType = ctx.PostForm("entity_type")
+
+	imageDTO.EntityID, err = uuid.Parse(ctx.PostForm("entity_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid entity ID"})
+		return
+	}
+
+	file, header, err := ctx.Request.FormFile("file")
+	if err != nil {
+		h.logger.Debugf("unable to read uploaded file: %s", err.Error())
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "no file uploaded"})
+		return
+	}
+	defer file.Close()
+
+	imageDTO.File = file
+	imageDTO.Size = header.Size
+	imageDTO.Type = header.Header.Get("Content-Type")
+
+	imageDTO, err = h.imageService.Create(imageDTO)
+	if err != nil {
+		switch err.Error() {
+		case "invalid entity type", "invalid entity ID", "no file uploaded", "invalid image data":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case "file too large":
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		case "unsupported image format":
+			ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+			return
+		case "image dimensions too large", "decompression bomb detected":
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, imageDTO)
+}
+
+func (h ImageHandlers) RequestUpload(ctx *gin.Context) {
+	var uploadDTO m.ImageUploadDTO
+	var err error
+
+	if err = ctx.ShouldBindJSON(&uploadDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unexpected JSON input"})
+		return
+	}
+
+	uploadDTO, err = h.imageService.RequestUpload(uploadDTO)
+	if err != nil {
+		switch err.Error() {
+		case "invalid entity type", "invalid entity ID", "invalid content type":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case "unsupported image format":
+			ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, uploadDTO)
+}
+
+func (h ImageHandlers) ConfirmUpload(ctx *gin.Context) {
+	var uploadDTO m.ImageUploadDTO
+	var err error
+
+	uploadDTO.ID, err = uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid upload ID"})
+		return
+	}
+
+	imageDTO, err := h.imageService.ConfirmUpload(uploadDTO)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "upload not found"})
+			return
+		case "upload expired":
+			ctx.JSON(http.StatusGone, gin.H{"error": err.Error()})
+			return
+		case "file not uploaded":
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case "invalid image data":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case "file too large":
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		case "unsupported image format":
+			ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+			return
+		case "image dimensions too large", "decompression bomb detected":
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, imageDTO)
+}
+
+func (h ImageHandlers) Delete(ctx *gin.Context) {
+	var imageDTO m.ImageDTO
+	var err error
+
+	imageDTO.ID, err = uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid image ID"})
+		return
+	}
+
+	if err = h.imageService.Delete(imageDTO); err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "image not found"})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -1 +1,700 @@
 package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	m "image-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type ImageServiceMock struct{}
+
+// maxBytes is the size limit of the uploads in the tests
+const maxBytes = 1 << 10
+
+var (
+	images []m.ImageDTO
+	image  m.ImageDTO = m.ImageDTO{
+		ID:         uuid.New(),
+		EntityType: "recipe",
+		EntityID:   uuid.New(),
+		Size:       5,
+		Type:       "image/jpeg",
+		URL:        "https://cdn.example.com/img/image.jpg",
+	}
+
+	// mode selects the behaviour of the ImageServiceMock
+	mode string
+
+	// rejection is the error the upload is rejected with in the "reject" mode
+	rejection string
+)
+
+// ====== ImageService ======
+
+func (s *ImageServiceMock) FindByEntity(entityType string, entityID uuid.UUID) ([]m.ImageDTO, error) {
+	switch mode {
+	case "findall":
+		return images, nil
+	case "notfound":
+		return nil, errors.New("not found")
+	default:
+		return nil, errors.New("error")
+	}
+}
+
+func (s *ImageServiceMock) FindSingle(imageDTO m.ImageDTO) (m.ImageDTO, error) {
+	switch mode {
+	case "find":
+		return image, nil
+	case "notfound":
+		return m.ImageDTO{}, errors.New("not found")
+	default:
+		return m.ImageDTO{}, errors.New("error")
+	}
+}
+
+func (s *ImageServiceMock) Download(imageDTO m.ImageDTO, name string, format string) (m.ImageVariantDTO, io.ReadCloser, error) {
+	switch mode {
+	case "download":
+		return m.ImageVariantDTO{Name: name, Format: format, Size: 5}, io.NopCloser(strings.NewReader("image")), nil
+	case "notfound":
+		return m.ImageVariantDTO{}, nil, errors.New("not found")
+	default:
+		return m.ImageVariantDTO{}, nil, errors.New("error")
+	}
+}
+
+func (s *ImageServiceMock) Create(imageDTO m.ImageDTO) (m.ImageDTO, error) {
+	switch mode {
+	case "create":
+		return image, nil
+	case "invalid":
+		return m.ImageDTO{}, errors.New("invalid entity type")
+	case "reject":
+		return m.ImageDTO{}, errors.New(rejection)
+	default:
+		return m.ImageDTO{}, errors.New("error")
+	}
+}
+
+func (s *ImageServiceMock) RequestUpload(uploadDTO m.ImageUploadDTO) (m.ImageUploadDTO, error) {
+	switch mode {
+	case "upload":
+		uploadDTO.ID = image.ID
+		uploadDTO.UploadURL = "https://storage.example.com/uploads/" + image.ID.String()
+		return uploadDTO, nil
+	case "invalid":
+		return m.ImageUploadDTO{}, errors.New("invalid content type")
+	case "reject":
+		return m.ImageUploadDTO{}, errors.New("unsupported image format")
+	default:
+		return m.ImageUploadDTO{}, errors.New("error")
+	}
+}
+
+func (s *ImageServiceMock) ConfirmUpload(uploadDTO m.ImageUploadDTO) (m.ImageDTO, error) {
+	switch mode {
+	case "confirm":
+		return image, nil
+	case "notfound":
+		return m.ImageDTO{}, errors.New("not found")
+	case "expired":
+		return m.ImageDTO{}, errors.New("upload expired")
+	case "missing":
+		return m.ImageDTO{}, errors.New("file not uploaded")
+	case "reject":
+		return m.ImageDTO{}, errors.New(rejection)
+	default:
+		return m.ImageDTO{}, errors.New("error")
+	}
+}
+
+func (s *ImageServiceMock) Delete(imageDTO m.ImageDTO) error {
+	switch mode {
+	case "delete":
+		return nil
+	case "notfound":
+		return errors.New("not found")
+	default:
+		return errors.New("error")
+	}
+}
+
+// ==================================================================================================
+
+func TestImageGetAll_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "findall"
+	images = []m.ImageDTO{image}
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/image?entity_type=recipe&entity_id="+image.EntityID.String(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.GetAll(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(images)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestImageGetAll_QueryErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	for query, expected := range map[string]string{
+		"entity_id=" + image.EntityID.String(): `{"error":"invalid entity type"}`,
+		"entity_type=recipe&entity_id=1":       `{"error":"invalid entity ID"}`,
+	} {
+		req := httptest.NewRequest("GET", "http://example.com/api/v2/image?"+query, nil)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+
+		h.GetAll(c)
+
+		resp := w.Result()
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, expected, string(body))
+	}
+}
+
+func TestImageGetAll_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "notfound"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/image?entity_type=recipe&entity_id="+image.EntityID.String(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.GetAll(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"no images found"}`, string(body))
+}
+
+func TestImageGetAll_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "error"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/image?entity_type=recipe&entity_id="+image.EntityID.String(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.GetAll(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestImageGet_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "find"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/image/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: image.ID.String()},
+	}
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(image)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestImageGet_IDErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/image/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: "1"},
+	}
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid image ID"}`, string(body))
+}
+
+func TestImageGet_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "notfound"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/image/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: image.ID.String()},
+	}
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"image not found"}`, string(body))
+}
+
+func TestImageDownload_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "download"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/image/1/download?variant=card&format=webp", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: image.ID.String()},
+	}
+
+	h.Download(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "image/webp", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "5", resp.Header.Get("Content-Length"))
+	assert.Equal(t, "image", string(body))
+}
+
+func TestImageDownload_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "notfound"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/image/1/download", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: image.ID.String()},
+	}
+
+	h.Download(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"image not found"}`, string(body))
+}
+
+func TestImageCreate_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "create"
+
+	req := newUploadRequest(t, image.EntityID.String(), true)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(image)
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestImageCreate_TooLargeErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "create"
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("entity_type", "recipe")
+	writer.WriteField("entity_id", image.EntityID.String())
+	part, _ := writer.CreateFormFile("file", "image.jpg")
+	part.Write(bytes.Repeat([]byte("x"), maxBytes+multipartOverhead))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/image", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	assert.Equal(t, `{"error":"file too large"}`, string(respBody))
+}
+
+func TestImageCreate_IDErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	req := newUploadRequest(t, "1", true)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid entity ID"}`, string(body))
+}
+
+func TestImageCreate_FileErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	req := newUploadRequest(t, image.EntityID.String(), false)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"no file uploaded"}`, string(body))
+}
+
+func TestImageCreate_ValidationErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "invalid"
+
+	req := newUploadRequest(t, image.EntityID.String(), true)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid entity type"}`, string(body))
+}
+
+func TestImageCreate_RejectedErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "reject"
+
+	for message, status := range map[string]int{
+		"invalid image data":          http.StatusBadRequest,
+		"file too large":              http.StatusRequestEntityTooLarge,
+		"unsupported image format":    http.StatusUnsupportedMediaType,
+		"image dimensions too large":  http.StatusUnprocessableEntity,
+		"decompression bomb detected": http.StatusUnprocessableEntity,
+	} {
+		rejection = message
+
+		req := newUploadRequest(t, image.EntityID.String(), true)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+
+		h.Create(c)
+
+		resp := w.Result()
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, status, resp.StatusCode, message)
+		assert.Equal(t, `{"error":"`+message+`"}`, string(body))
+	}
+}
+
+func TestImageCreate_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "error"
+
+	req := newUploadRequest(t, image.EntityID.String(), true)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestImageDelete_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "delete"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/image/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: image.ID.String()},
+	}
+
+	h.Delete(c)
+	c.Writer.WriteHeaderNow()
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestImageDelete_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "notfound"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/image/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: image.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"image not found"}`, string(body))
+}
+
+func TestImageDelete_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "error"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/image/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: image.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestImageRequestUpload_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "upload"
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/image/uploads",
+		strings.NewReader(`{"entity_type":"recipe","entity_id":"`+image.EntityID.String()+`","type":"image/jpeg"}`))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.RequestUpload(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	var result m.ImageUploadDTO
+	json.Unmarshal(body, &result)
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, image.ID, result.ID)
+	assert.Equal(t, image.EntityID, result.EntityID)
+	assert.Equal(t, "image/jpeg", result.Type)
+	assert.Equal(t, "https://storage.example.com/uploads/"+image.ID.String(), result.UploadURL)
+}
+
+func TestImageRequestUpload_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	for _, tc := range []struct {
+		mode     string
+		body     string
+		status   int
+		expected string
+	}{
+		{"upload", `{"entity_id":1}`, http.StatusBadRequest, `{"error":"unexpected JSON input"}`},
+		{"invalid", `{"entity_type":"recipe"}`, http.StatusBadRequest, `{"error":"invalid content type"}`},
+		{"reject", `{"entity_type":"recipe","type":"image/heic"}`, http.StatusUnsupportedMediaType, `{"error":"unsupported image format"}`},
+		{"error", `{"entity_type":"recipe"}`, http.StatusInternalServerError, `{"error":"error"}`},
+	} {
+		mode = tc.mode
+
+		req := httptest.NewRequest("POST", "http://example.com/api/v2/image/uploads", strings.NewReader(tc.body))
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+
+		h.RequestUpload(c)
+
+		resp := w.Result()
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, tc.status, resp.StatusCode, tc.mode)
+		assert.Equal(t, tc.expected, string(body), tc.mode)
+	}
+}
+
+func TestImageConfirmUpload_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	mode = "confirm"
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/image/uploads/"+image.ID.String()+"/confirm", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = []gin.Param{{Key: "id", Value: image.ID.String()}}
+
+	h.ConfirmUpload(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(image)
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestImageConfirmUpload_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImageHandlers(&ImageServiceMock{}, maxBytes, &m.LoggerInterfaceMock{})
+
+	rejection = "unsupported image format"
+
+	for _, tc := range []struct {
+		mode     string
+		id       string
+		status   int
+		expected string
+	}{
+		{"confirm", "1", http.StatusBadRequest, `{"error":"invalid upload ID"}`},
+		{"notfound", image.ID.String(), http.StatusNotFound, `{"error":"upload not found"}`},
+		{"expired", image.ID.String(), http.StatusGone, `{"error":"upload expired"}`},
+		{"missing", image.ID.String(), http.StatusConflict, `{"error":"file not uploaded"}`},
+		{"reject", image.ID.String(), http.StatusUnsupportedMediaType, `{"error":"unsupported image format"}`},
+		{"error", image.ID.String(), http.StatusInternalServerError, `{"error":"error"}`},
+	} {
+		mode = tc.mode
+
+		req := httptest.NewRequest("POST", "http://example.com/api/v2/image/uploads/"+tc.id+"/confirm", nil)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = []gin.Param{{Key: "id", Value: tc.id}}
+
+		h.ConfirmUpload(c)
+
+		resp := w.Result()
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, tc.status, resp.StatusCode, tc.mode)
+		assert.Equal(t, tc.expected, string(body), tc.mode)
+	}
+}
+
+// ====== Helpers ======
+
+func newUploadRequest(t *testing.T, entityID string, withFile bool) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	writer.WriteField("entity_type", "recipe")
+	writer.WriteField("entity_id", entityID)
+
+	if withFile {
+		part, err := writer.CreateFormFile("file", "image.jpg")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte("image"))
+	}
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/image", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return req
+}
@@ -1,4 +1,4 @@
-package instructionservice
+package imageservice
 
 import (
 	"context"
@@ -14,12 +14,6 @@ import (
 
 var (
 	log = c.Logger
-
-	config = ginkeycloak.BuilderConfig{
-		Service: "",
-		Url:     "",
-		Realm:   "",
-	}
 )
 
 func ImageService(ctx context.Context) {
@@ -35,14 +29,49 @@ func ImageService(ctx context.Context) {
 	// Cors handler
 	router.Use(cors.New(c.Cors))
 
-	privateGroup := router.Group("/api")
-	privateGroup.Use(ginkeycloak.NewAccessBuilder(config).
-		RestrictButForRole("administrator").
-		Build())
+	v1 := router.Group("/api/v2")
+	{
+		image := v1.Group("/image")
+		{
+			readImage := image.Group("")
+			readImage.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
+			{
+				readImage.GET("", c.ImageHandlers.GetAll)
+				readImage.GET(":id", c.ImageHandlers.Get)
+				readImage.GET(":id/download", c.ImageHandlers.Download)
+			}
+
+			createImage := image.Group("")
+			createImage.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
+			{
+				createImage.POST("", c.ImageHandlers.Create)
+				createImage.POST("uploads", c.ImageHandlers.RequestUpload)
+				createImage.POST("uploads/:id/confirm", c.ImageHandlers.ConfirmUpload)
+			}
+
+			// the files of the filesystem backend are authorized by the signature of their url, like presigned
+			// urls of the S3 backend
+			if c.FileHandlers != nil {
+				image.GET("files/*key", c.FileHandlers.Get)
+				image.PUT("files/*key", c.FileHandlers.Put)
+			}
+
+			adminImage := image.Group("")
+			adminImage.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
+			{
+				adminImage.DELETE(":id", c.ImageHandlers.Delete)
+				adminImage.DELETE("entity/:type/:id", c.EntityImageHandlers.Delete)
+				adminImage.POST("entity/:type/:id/restore", c.EntityImageHandlers.Restore)
+				adminImage.GET("gc/report", c.GarbageHandlers.Report)
+			}
+		}
+	}
+
+	// Garbage collection
+	go c.GarbageCollector.Run(ctx, c.GarbageInterval)
 
-	privateGroup.GET("/privategroup", func(c *gin.Context) {
-		c.JSON(200, gin.H{"message": "Hello from private for groups"})
-	})
+	// Outbox relay
+	go c.Relay.Run(ctx, c.RelayInterval)
 
 	// Server startup
 	srv := &http.Server{
@@ -57,7 +86,7 @@ func ImageService(ctx context.Context) {
 		srv.Shutdown(ctx)
 	}()
 
-	log.Info("instruction service available on port 8080")
+	log.Info("image service available on port 8080")
 	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
 		log.Error(err)
 	}
@@ -1,11 +1,17 @@
 package models
 
 type Config struct {
-	Global   GlobalConfig
-	Cors     CorsConfig
-	Oauth    OauthConfig
-	Database DatabaseConfig
-	S3       S3Config
+	Global     GlobalConfig
+	Cors       CorsConfig
+	Oauth      OauthConfig
+	Database   DatabaseConfig
+	Storage    StorageConfig
+	S3         S3Config
+	Filesystem FilesystemConfig
+	Images     ImagesConfig
+	Upload     UploadConfig
+	Garbage    GarbageConfig
+	Events     EventsConfig
 }
 
 // GlobalConfig holds global configuration items
@@ -25,9 +31,11 @@ type DatabaseConfig struct {
 }
 
 type OauthConfig struct {
-	Domain   string
-	ClientID string
-	Audience string
+	Service              string
+	Url                  string
+	Realm                string
+	FullCertsPath        *string
+	DisableSecurityCheck bool
 }
 
 type CorsConfig struct {
@@ -37,6 +45,18 @@ type CorsConfig struct {
 	AllowedMethods   []string
 }
 
+// StorageConfig selects the backend the image files are stored in
+type StorageConfig struct {
+	Backend   string // "s3" or "filesystem"
+	UrlExpiry int    // seconds the download and upload urls are valid for
+}
+
+type FilesystemConfig struct {
+	Path       string // directory the files are stored in
+	PublicUrl  string // base url the files are served on
+	SigningKey string // secret the file urls are signed with
+}
+
 type S3Config struct {
 	AWSRegion       string
 	AWSAccessKey    string
@@ -44,3 +64,55 @@ type S3Config struct {
 	BucketName      string
 	Endpoint        string
 }
+
+// ImagesConfig holds the settings of the image processing pipeline
+type ImagesConfig struct {
+	Quality  int      // encoding quality of the lossy formats, 1-100
+	Formats  []string // e.g., "jpeg" and "webp"
+	Variants []VariantConfig
+}
+
+// VariantConfig describes the bounding box a variant is scaled down to
+type VariantConfig struct {
+	Name      string
+	MaxWidth  int
+	MaxHeight int
+}
+
+// UploadConfig holds the limits uploads are validated against
+type UploadConfig struct {
+	MaxBytes            int64    // size of the uploaded file in bytes
+	MaxMegapixels       float64  // width times height in millions of pixels
+	MaxCompressionRatio int64    // decoded size relative to the file size, larger ratios are rejected as decompression bombs
+	Formats             []string // formats accepted, e.g., "jpeg", "png" and "webp"
+}
+
+// GarbageConfig holds the settings of the garbage collection of orphaned images and objects
+type GarbageConfig struct {
+	Interval     int    // minutes between the runs
+	GracePeriod  int    // hours an image or object is kept before it is considered garbage
+	ClientID     string // client the entities are looked up with, requires the administrator role
+	ClientSecret string
+	Entities     []EntityConfig
+}
+
+// EntityConfig holds the url an entity type is retrieved from by appending the entity ID. For an entity type that
+// can be restored after it was deleted, the deletion of an entity is reported by appending the deletion path
+type EntityConfig struct {
+	Type         string // e.g., "recipe"
+	Url          string // e.g., "http://recipe-service:8080/api/v2/recipe"
+	DeletionPath string // e.g., "deletion"
+}
+
+// EventsConfig holds the broker the changes are published to. The http broker posts them to the subscribers,
+// authenticated with the client credentials
+type EventsConfig struct {
+	Broker       string   // nats or http, defaults to http
+	NatsUrl      string   // e.g. nats://nats:4222
+	Subscribers  []string // urls the events are posted to
+	Timeout      int      // in seconds
+	ClientID     string
+	ClientSecret string
+	Interval     int // in milliseconds, between the runs of the outbox relay
+	BatchSize    int // number of events the outbox relay publishes per run
+}
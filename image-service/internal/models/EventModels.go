@@ -0,0 +1,26 @@
+package models
+
+import (
+	"cookbook/pkg/outbox"
+
+	"github.com/google/uuid"
+)
+
+// The kinds of changes that are reported
+const (
+	EventCreated = "created"
+	EventUpdated = "updated"
+	EventDeleted = "deleted"
+)
+
+// The entities this service reports changes on
+const (
+	EntityImage = "image"
+)
+
+// ChangeEvent tells other services that an image was created, updated or deleted
+type ChangeEvent = outbox.ChangeEvent
+
+func NewChangeEvent(eventType string, entityType string, entityID uuid.UUID) ChangeEvent {
+	return outbox.NewChangeEvent(eventType, entityType, entityID)
+}
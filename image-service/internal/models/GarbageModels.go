@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StoredObject describes an object kept in storage
+type StoredObject struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// OrphanedImageDTO is an image whose entity doesn't exist anymore
+type OrphanedImageDTO struct {
+	ID         uuid.UUID `json:"id"`
+	EntityType string    `json:"entity_type"`
+	EntityID   uuid.UUID `json:"entity_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// GarbageReportDTO lists what a garbage collection run removes, or would remove on a dry run
+type GarbageReportDTO struct {
+	DryRun      bool               `json:"dry_run"`
+	GracePeriod string             `json:"grace_period"`
+	Images      []OrphanedImageDTO `json:"images"`
+	Objects     []StoredObject     `json:"objects"`
+	Uploads     []uuid.UUID        `json:"uploads"`
+	Size        int64              `json:"size"` // bytes freed in storage
+}
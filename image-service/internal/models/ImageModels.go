@@ -9,12 +9,14 @@ import (
 )
 
 type Image struct {
-	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primary_key"`
-	EntityType string    `gorm:"type:varchar(50);not null"` // e.g., "recipe" or "ingredient"
-	EntityID   uuid.UUID `gorm:"type:uuid;not null"`
-	Size       int64     `gorm:"not null"`                  // size in bytes
-	Type       string    `gorm:"type:varchar(50);not null"` // e.g., "image/jpeg"
-	File       multipart.File
+	ID         uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primary_key"`
+	EntityType string         `gorm:"type:varchar(50);not null"` // e.g., "recipe" or "ingredient"
+	EntityID   uuid.UUID      `gorm:"type:uuid;not null"`
+	Size       int64          `gorm:"not null"`                  // size in bytes
+	Type       string         `gorm:"type:varchar(50);not null"` // e.g., "image/jpeg"
+	Hash       string         `gorm:"type:varchar(64);index"`    // SHA-256 of the uploaded file
+	File       multipart.File `gorm:"-"`
+	Variants   []ImageVariant `gorm:"foreignKey:ImageID"`
 	CreatedAt  time.Time      `gorm:"autoCreateTime"`
 	UpdatedAt  time.Time      `gorm:"autoUpdateTime"`
 	DeletedAt  gorm.DeletedAt `gorm:"index"`
@@ -27,17 +29,21 @@ func (i Image) ConvertToDTO() ImageDTO {
 		EntityID:   i.EntityID,
 		Size:       i.Size,
 		Type:       i.Type,
-		File:       i.File,
+		Hash:       i.Hash,
+		Variants:   ImageVariant{}.ConvertAllToDTO(i.Variants),
 	}
 }
 
 type ImageDTO struct {
-	ID         uuid.UUID `json:"id"`
-	EntityType string    `json:"entity_type"`
-	EntityID   uuid.UUID `json:"entity_id"`
-	Size       int64     `json:"size"`
-	Type       string    `json:"type"`
-	File       multipart.File
+	ID         uuid.UUID         `json:"id"`
+	EntityType string            `json:"entity_type"`
+	EntityID   uuid.UUID         `json:"entity_id"`
+	Size       int64             `json:"size"`
+	Type       string            `json:"type"`
+	Hash       string            `json:"hash"`
+	URL        string            `json:"url"`
+	Variants   []ImageVariantDTO `json:"variants"`
+	File       multipart.File    `json:"-"`
 }
 
 func (i ImageDTO) ConvertFromDTO() Image {
@@ -47,6 +53,58 @@ func (i ImageDTO) ConvertFromDTO() Image {
 		EntityID:   i.EntityID,
 		Size:       i.Size,
 		Type:       i.Type,
+		Hash:       i.Hash,
 		File:       i.File,
 	}
 }
+
+// ImageVariant is a resized and re-encoded rendition of an uploaded image
+type ImageVariant struct {
+	ImageID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Name    string    `gorm:"type:varchar(50);primaryKey"` // e.g., "thumbnail" or "card"
+	Format  string    `gorm:"type:varchar(10);primaryKey"` // e.g., "jpeg" or "webp"
+	Width   int       `gorm:"not null"`
+	Height  int       `gorm:"not null"`
+	Size    int64     `gorm:"not null"` // size in bytes
+	Key     string    `gorm:"type:varchar(255);not null"`
+	Data    []byte    `gorm:"-"`
+}
+
+func (v ImageVariant) ConvertToDTO() ImageVariantDTO {
+	return ImageVariantDTO{
+		Name:   v.Name,
+		Format: v.Format,
+		Width:  v.Width,
+		Height: v.Height,
+		Size:   v.Size,
+	}
+}
+
+func (v ImageVariant) ConvertAllToDTO(variants []ImageVariant) []ImageVariantDTO {
+	variantDTOs := make([]ImageVariantDTO, len(variants))
+
+	for i, variant := range variants {
+		variantDTOs[i] = variant.ConvertToDTO()
+	}
+
+	return variantDTOs
+}
+
+// ContentType returns the mime type the variant is encoded in
+func (v ImageVariant) ContentType() string {
+	return "image/" + v.Format
+}
+
+type ImageVariantDTO struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Size   int64  `json:"size"`
+	URL    string `json:"url"`
+}
+
+type LoggerInterface interface {
+	Debugf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImageUpload is a pending direct upload to storage. The uploaded file only becomes an image once it is confirmed
+type ImageUpload struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key"`
+	EntityType string    `gorm:"type:varchar(50);not null"`
+	EntityID   uuid.UUID `gorm:"type:uuid;not null"`
+	Type       string    `gorm:"type:varchar(50);not null"` // content type the file has to be uploaded with
+	Key        string    `gorm:"type:varchar(255);not null"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+func (u ImageUpload) ConvertToDTO() ImageUploadDTO {
+	return ImageUploadDTO{
+		ID:         u.ID,
+		EntityType: u.EntityType,
+		EntityID:   u.EntityID,
+		Type:       u.Type,
+		ExpiresAt:  u.ExpiresAt,
+	}
+}
+
+type ImageUploadDTO struct {
+	ID         uuid.UUID `json:"id"`
+	EntityType string    `json:"entity_type"`
+	EntityID   uuid.UUID `json:"entity_id"`
+	Type       string    `json:"type"`
+	UploadURL  string    `json:"upload_url"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+func (u ImageUploadDTO) ConvertFromDTO() ImageUpload {
+	return ImageUpload{
+		ID:         u.ID,
+		EntityType: u.EntityType,
+		EntityID:   u.EntityID,
+		Type:       u.Type,
+		ExpiresAt:  u.ExpiresAt,
+	}
+}
@@ -0,0 +1,128 @@
+package processing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+
+	m "image-service/internal/models"
+
+	"github.com/chai2010/webp"
+	"golang.org/x/image/draw"
+)
+
+type ImageProcessor struct {
+	quality  int
+	formats  []string
+	variants []m.VariantConfig
+}
+
+func NewImageProcessor(config m.ImagesConfig) *ImageProcessor {
+	return &ImageProcessor{
+		quality:  config.Quality,
+		formats:  config.Formats,
+		variants: config.Variants,
+	}
+}
+
+// Process decodes the uploaded file and renders every configured variant in every configured format.
+// The variants are re-encoded from the decoded pixels, so no EXIF or other metadata of the upload is retained
+func (p ImageProcessor) Process(file io.Reader) ([]m.ImageVariant, error) {
+	var variants []m.ImageVariant
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("unsupported image format")
+	}
+
+	oriented := flatten(applyOrientation(src, readOrientation(data)))
+
+	for _, variantConfig := range p.variants {
+		resized := resize(oriented, variantConfig.MaxWidth, variantConfig.MaxHeight)
+
+		for _, format := range p.formats {
+			var buf bytes.Buffer
+
+			if err := p.encode(&buf, resized, format); err != nil {
+				return nil, err
+			}
+
+			variants = append(variants, m.ImageVariant{
+				Name:   variantConfig.Name,
+				Format: format,
+				Width:  resized.Bounds().Dx(),
+				Height: resized.Bounds().Dy(),
+				Size:   int64(buf.Len()),
+				Data:   buf.Bytes(),
+			})
+		}
+	}
+
+	return variants, nil
+}
+
+func (p ImageProcessor) encode(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case "jpeg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: p.quality})
+	case "webp":
+		return webp.Encode(w, img, &webp.Options{Quality: float32(p.quality)})
+	default:
+		return fmt.Errorf("unsupported output format %s", format)
+	}
+}
+
+// flatten draws the image on a white background, as not all output formats support transparency
+func flatten(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Over)
+
+	return dst
+}
+
+// resize scales the image down to fit within the bounding box, keeping its aspect ratio. Images are never
+// scaled up and a zero maximum leaves that dimension unbounded
+func resize(src *image.RGBA, maxWidth int, maxHeight int) *image.RGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	scale := 1.0
+
+	if maxWidth > 0 && w > maxWidth {
+		scale = float64(maxWidth) / float64(w)
+	}
+
+	if maxHeight > 0 && float64(h)*scale > float64(maxHeight) {
+		scale = float64(maxHeight) / float64(h)
+	}
+
+	if scale == 1.0 {
+		return src
+	}
+
+	width := max(int(float64(w)*scale+0.5), 1)
+	height := max(int(float64(h)*scale+0.5), 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+
+	return dst
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
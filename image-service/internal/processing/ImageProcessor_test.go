@@ -0,0 +1,194 @@
+package processing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	m "image-service/internal/models"
+
+	"github.com/chai2010/webp"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	config m.ImagesConfig = m.ImagesConfig{
+		Quality: 80,
+		Formats: []string{"jpeg", "webp"},
+		Variants: []m.VariantConfig{
+			{Name: "thumbnail", MaxWidth: 100, MaxHeight: 100},
+			{Name: "full", MaxWidth: 1000, MaxHeight: 1000},
+		},
+	}
+)
+
+func TestProcess_OK(t *testing.T) {
+	p := NewImageProcessor(config)
+
+	result, err := p.Process(bytes.NewReader(createPNG(t, 400, 200)))
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 4)
+
+	// the thumbnail is scaled down, the full variant is never scaled up
+	for i, expected := range []m.ImageVariant{
+		{Name: "thumbnail", Format: "jpeg", Width: 100, Height: 50},
+		{Name: "thumbnail", Format: "webp", Width: 100, Height: 50},
+		{Name: "full", Format: "jpeg", Width: 400, Height: 200},
+		{Name: "full", Format: "webp", Width: 400, Height: 200},
+	} {
+		assert.Equal(t, expected.Name, result[i].Name)
+		assert.Equal(t, expected.Format, result[i].Format)
+		assert.Equal(t, expected.Width, result[i].Width)
+		assert.Equal(t, expected.Height, result[i].Height)
+		assert.Equal(t, int64(len(result[i].Data)), result[i].Size)
+	}
+
+	decoded, err := jpeg.Decode(bytes.NewReader(result[0].Data))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), decoded.Bounds())
+
+	decoded, err = webp.Decode(bytes.NewReader(result[1].Data))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), decoded.Bounds())
+}
+
+func TestProcess_Orientation(t *testing.T) {
+	p := NewImageProcessor(config)
+
+	result, err := p.Process(bytes.NewReader(createJPEG(t, 40, 20, 6)))
+
+	assert.NoError(t, err)
+
+	// rotated by 90 degrees, so width and height are swapped
+	assert.Equal(t, 20, result[0].Width)
+	assert.Equal(t, 40, result[0].Height)
+
+	// the EXIF data is not carried over to the variants
+	for _, variant := range result {
+		assert.False(t, bytes.Contains(variant.Data, []byte("Exif")))
+	}
+}
+
+func TestProcess_FormatErr(t *testing.T) {
+	p := NewImageProcessor(config)
+
+	result, err := p.Process(strings.NewReader("not an image"))
+
+	assert.EqualError(t, err, "unsupported image format")
+	assert.Nil(t, result)
+}
+
+func TestProcess_OutputFormatErr(t *testing.T) {
+	p := NewImageProcessor(m.ImagesConfig{
+		Quality:  80,
+		Formats:  []string{"gif"},
+		Variants: config.Variants,
+	})
+
+	result, err := p.Process(bytes.NewReader(createPNG(t, 40, 20)))
+
+	assert.EqualError(t, err, "unsupported output format gif")
+	assert.Nil(t, result)
+}
+
+func TestReadOrientation(t *testing.T) {
+	for orientation := 1; orientation <= 8; orientation++ {
+		assert.Equal(t, orientation, readOrientation(createJPEG(t, 4, 2, orientation)))
+	}
+
+	assert.Equal(t, 1, readOrientation(createJPEG(t, 4, 2, 0)))
+	assert.Equal(t, 1, readOrientation(createPNG(t, 4, 2)))
+	assert.Equal(t, 1, readOrientation(nil))
+}
+
+func TestApplyOrientation(t *testing.T) {
+	// a 2x1 image with a red pixel on the left and a blue one on the right
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	src.Set(1, 0, color.RGBA{0, 0, 255, 255})
+
+	red := color.RGBA{255, 0, 0, 255}
+
+	for orientation, expected := range map[int]image.Point{
+		1: {0, 0},
+		2: {1, 0},
+		3: {1, 0},
+		4: {0, 0},
+		5: {0, 0},
+		6: {0, 0},
+		7: {0, 1},
+		8: {0, 1},
+	} {
+		result := applyOrientation(src, orientation)
+
+		if orientation >= 5 {
+			assert.Equal(t, image.Rect(0, 0, 1, 2), result.Bounds())
+		} else {
+			assert.Equal(t, image.Rect(0, 0, 2, 1), result.Bounds())
+		}
+		assert.Equal(t, red, result.At(expected.X, expected.Y), "orientation %d", orientation)
+	}
+}
+
+// ====== Helpers ======
+
+func createImage(width int, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 200, 0xff})
+		}
+	}
+
+	return img
+}
+
+func createPNG(t *testing.T, width int, height int) []byte {
+	var buf bytes.Buffer
+
+	if err := png.Encode(&buf, createImage(width, height)); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// createJPEG encodes a JPEG with an EXIF segment holding the orientation. An orientation of 0 omits the segment
+func createJPEG(t *testing.T, width int, height int, orientation int) []byte {
+	var buf bytes.Buffer
+
+	if err := jpeg.Encode(&buf, createImage(width, height), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	if orientation == 0 {
+		return data
+	}
+
+	// little endian TIFF header followed by an IFD with a single orientation entry
+	tiff := []byte{'I', 'I', 0x2A, 0x00, 0x08, 0x00, 0x00, 0x00, 0x01, 0x00}
+	entry := make([]byte, 12)
+	binary.LittleEndian.PutUint16(entry[0:2], exifOrientationTag)
+	binary.LittleEndian.PutUint16(entry[2:4], 3) // SHORT
+	binary.LittleEndian.PutUint32(entry[4:8], 1)
+	binary.LittleEndian.PutUint16(entry[8:10], uint16(orientation))
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0x00, 0x00, 0x00, 0x00)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	length := make([]byte, 2)
+	binary.BigEndian.PutUint16(length, uint16(len(segment)+2))
+
+	result := append([]byte{0xFF, 0xD8, 0xFF, 0xE1}, length...)
+	result = append(result, segment...)
+
+	return append(result, data[2:]...)
+}
@@ -0,0 +1,114 @@
+package processing
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"io"
+
+	m "image-service/internal/models"
+)
+
+// ImageValidator checks uploads against the configured limits before they are decoded
+type ImageValidator struct {
+	maxBytes            int64
+	maxPixels           int64
+	maxCompressionRatio int64
+	formats             map[string]bool
+}
+
+func NewImageValidator(config m.UploadConfig) *ImageValidator {
+	formats := make(map[string]bool, len(config.Formats))
+
+	for _, format := range config.Formats {
+		formats[format] = true
+	}
+
+	return &ImageValidator{
+		maxBytes:            config.MaxBytes,
+		maxPixels:           int64(config.MaxMegapixels * 1000000),
+		maxCompressionRatio: config.MaxCompressionRatio,
+		formats:             formats,
+	}
+}
+
+// Read reads the upload, but never more than one byte past the size limit
+func (v ImageValidator) Read(file io.Reader) ([]byte, error) {
+
+	data, err := io.ReadAll(io.LimitReader(file, v.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > v.maxBytes {
+		return nil, errors.New("file too large")
+	}
+
+	return data, nil
+}
+
+// Accepts reports whether uploads of the content type are allowed
+func (v ImageValidator) Accepts(contentType string) bool {
+	for format := range v.formats {
+		if contentType == "image/"+format {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate determines the format from the contents of the file and checks the dimensions declared in its header
+// before anything is decoded. The content type of the format is returned
+func (v ImageValidator) Validate(data []byte) (string, error) {
+
+	if int64(len(data)) > v.maxBytes {
+		return "", errors.New("file too large")
+	}
+
+	format := sniff(data)
+	if !v.formats[format] {
+		return "", errors.New("unsupported image format")
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return "", errors.New("invalid image data")
+	}
+
+	pixels := int64(config.Width) * int64(config.Height)
+	if pixels > v.maxPixels {
+		return "", errors.New("image dimensions too large")
+	}
+
+	// a small file declaring a large canvas expands to far more memory than it takes up
+	if pixels*4 > int64(len(data))*v.maxCompressionRatio {
+		return "", errors.New("decompression bomb detected")
+	}
+
+	return "image/" + format, nil
+}
+
+// sniff determines the format from the magic bytes at the start of the file. HEIC is recognized, but can't be
+// decoded without libheif and so is never accepted
+func sniff(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return "jpeg"
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return "png"
+	case len(data) >= 12 && bytes.Equal(data[0:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WEBP")):
+		return "webp"
+	case bytes.HasPrefix(data, []byte("GIF87a")), bytes.HasPrefix(data, []byte("GIF89a")):
+		return "gif"
+	case len(data) >= 12 && bytes.Equal(data[4:8], []byte("ftyp")):
+		switch string(data[8:12]) {
+		case "heic", "heix", "hevc", "hevx", "heim", "heis", "mif1", "msf1":
+			return "heic"
+		case "avif", "avis":
+			return "avif"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,126 @@
+package processing
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	m "image-service/internal/models"
+
+	"github.com/chai2010/webp"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	uploadConfig m.UploadConfig = m.UploadConfig{
+		MaxBytes:            1 << 20,
+		MaxMegapixels:       1,
+		MaxCompressionRatio: 5000,
+		Formats:             []string{"jpeg", "png", "webp"},
+	}
+)
+
+func TestValidate_OK(t *testing.T) {
+	v := NewImageValidator(uploadConfig)
+
+	for expected, data := range map[string][]byte{
+		"image/png":  createPNG(t, 40, 20),
+		"image/jpeg": createJPEG(t, 40, 20, 6),
+		"image/webp": createWebP(t, 40, 20),
+	} {
+		result, err := v.Validate(data)
+
+		assert.NoError(t, err, expected)
+		assert.Equal(t, expected, result)
+	}
+}
+
+func TestValidate_FormatErr(t *testing.T) {
+	v := NewImageValidator(m.UploadConfig{MaxBytes: 1 << 20, MaxMegapixels: 1, MaxCompressionRatio: 5000, Formats: []string{"png"}})
+
+	for name, data := range map[string][]byte{
+		"jpeg": createJPEG(t, 40, 20, 0),
+		"gif":  []byte("GIF89a\x01\x00\x01\x00"),
+		"heic": []byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00mif1heic"),
+		"text": []byte("not an image"),
+	} {
+		_, err := v.Validate(data)
+
+		assert.EqualError(t, err, "unsupported image format", name)
+	}
+}
+
+func TestValidate_InvalidErr(t *testing.T) {
+	v := NewImageValidator(uploadConfig)
+
+	// the magic bytes of a PNG without a valid header following
+	_, err := v.Validate([]byte("\x89PNG\r\n\x1a\nnot really"))
+
+	assert.EqualError(t, err, "invalid image data")
+}
+
+func TestValidate_SizeErr(t *testing.T) {
+	v := NewImageValidator(m.UploadConfig{MaxBytes: 10, MaxMegapixels: 1, MaxCompressionRatio: 5000, Formats: []string{"png"}})
+
+	_, err := v.Validate(createPNG(t, 40, 20))
+
+	assert.EqualError(t, err, "file too large")
+}
+
+func TestValidate_DimensionsErr(t *testing.T) {
+	v := NewImageValidator(uploadConfig)
+
+	// 1001 x 1000 pixels is just over the limit of a megapixel
+	_, err := v.Validate(createPNG(t, 1001, 1000))
+
+	assert.EqualError(t, err, "image dimensions too large")
+}
+
+func TestValidate_BombErr(t *testing.T) {
+	v := NewImageValidator(m.UploadConfig{MaxBytes: 1 << 20, MaxMegapixels: 1, MaxCompressionRatio: 100, Formats: []string{"png"}})
+
+	// a single color compresses to next to nothing, but still takes up 4 bytes per pixel once decoded
+	var buf bytes.Buffer
+	solid := image.NewGray(image.Rect(0, 0, 1000, 1000))
+	if err := png.Encode(&buf, solid); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := v.Validate(buf.Bytes())
+
+	assert.EqualError(t, err, "decompression bomb detected")
+}
+
+func TestRead(t *testing.T) {
+	v := NewImageValidator(m.UploadConfig{MaxBytes: 10})
+
+	result, err := v.Read(bytes.NewReader([]byte("0123456789")))
+	assert.NoError(t, err)
+	assert.Equal(t, "0123456789", string(result))
+
+	result, err = v.Read(bytes.NewReader([]byte("0123456789a")))
+	assert.EqualError(t, err, "file too large")
+	assert.Nil(t, result)
+}
+
+func TestAccepts(t *testing.T) {
+	v := NewImageValidator(uploadConfig)
+
+	assert.True(t, v.Accepts("image/jpeg"))
+	assert.True(t, v.Accepts("image/webp"))
+	assert.False(t, v.Accepts("image/heic"))
+	assert.False(t, v.Accepts("jpeg"))
+}
+
+// ====== Helpers ======
+
+func createWebP(t *testing.T, width int, height int) []byte {
+	var buf bytes.Buffer
+
+	if err := webp.Encode(&buf, createImage(width, height), &webp.Options{Quality: 80}); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,130 @@
+package processing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+)
+
+const (
+	exifOrientationTag = 0x0112
+)
+
+// readOrientation returns the EXIF orientation of a JPEG file. Files without (valid) EXIF data are
+// reported as 1, meaning no transformation is needed
+func readOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	offset := 2
+	for offset+4 <= len(data) {
+		if data[offset] != 0xFF {
+			return 1
+		}
+
+		marker := data[offset+1]
+		length := int(binary.BigEndian.Uint16(data[offset+2 : offset+4]))
+
+		// the start of scan marker is followed by the image data, no metadata beyond this point
+		if marker == 0xDA || length < 2 || offset+2+length > len(data) {
+			return 1
+		}
+
+		segment := data[offset+4 : offset+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return readTiffOrientation(segment[6:])
+		}
+
+		offset += 2 + length
+	}
+
+	return 1
+}
+
+func readTiffOrientation(tiff []byte) int {
+	var order binary.ByteOrder
+
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+
+		if order.Uint16(tiff[entry:entry+2]) == exifOrientationTag {
+			orientation := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+
+	return 1
+}
+
+// applyOrientation transforms the image so it is displayed upright without needing the EXIF orientation
+func applyOrientation(src image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	in := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(in, in.Bounds(), src, bounds.Min, draw.Src)
+
+	// orientations 5-8 swap width and height
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	if orientation >= 5 {
+		out = image.NewRGBA(image.Rect(0, 0, h, w))
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+
+			switch orientation {
+			case 2: // mirrored horizontally
+				dx, dy = w-1-x, y
+			case 3: // rotated 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				dx, dy = x, h-1-y
+			case 5: // transposed
+				dx, dy = y, x
+			case 6: // rotated 90 clockwise
+				dx, dy = h-1-y, x
+			case 7: // transversed
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotated 90 counter clockwise
+				dx, dy = y, w-1-x
+			}
+
+			copy(out.Pix[out.PixOffset(dx, dy):out.PixOffset(dx, dy)+4], in.Pix[in.PixOffset(x, y):in.PixOffset(x, y)+4])
+		}
+	}
+
+	return out
+}
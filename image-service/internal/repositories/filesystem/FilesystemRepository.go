@@ -0,0 +1,230 @@
+package repositories
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	m "image-service/internal/models"
+)
+
+// FilesystemRepository stores objects as files below a root directory, using the object key as relative path.
+// Like presigned S3 urls, the urls it hands out are signed and only valid until they expire
+type FilesystemRepository struct {
+	Root       string
+	PublicUrl  string
+	Expiry     time.Duration
+	signingKey []byte
+}
+
+func NewFilesystemRepository(root string, publicUrl string, signingKey string, expiry time.Duration) *FilesystemRepository {
+	return &FilesystemRepository{
+		Root:       filepath.Clean(root),
+		PublicUrl:  strings.TrimSuffix(publicUrl, "/"),
+		Expiry:     expiry,
+		signingKey: []byte(signingKey),
+	}
+}
+
+func (r FilesystemRepository) UploadObject(key string, body io.ReadSeeker, contentType string) error {
+	return r.StoreObject(key, body)
+}
+
+// StoreObject writes the contents of the reader to the object
+func (r FilesystemRepository) StoreObject(key string, body io.Reader) error {
+
+	path, err := r.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// write to a temporary file first so readers never see a partially written object
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (r FilesystemRepository) DownloadObject(key string) (io.ReadCloser, error) {
+	file, _, err := r.OpenObject(key)
+
+	return file, err
+}
+
+// OpenObject opens the stored object for reading. Contrary to DownloadObject the result is seekable, allowing
+// range requests to be served from it
+func (r FilesystemRepository) OpenObject(key string) (io.ReadSeekCloser, fs.FileInfo, error) {
+
+	path, err := r.path(key)
+	if err != nil {
+		return nil, nil, errors.New("not found")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, errors.New("not found")
+		}
+		return nil, nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	if info.IsDir() {
+		file.Close()
+		return nil, nil, errors.New("not found")
+	}
+
+	return file, info, nil
+}
+
+func (r FilesystemRepository) DeleteObject(key string) error {
+
+	path, err := r.path(key)
+	if err != nil {
+		return err
+	}
+
+	// like S3, deleting an object that doesn't exist is not an error
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// ListObjects returns the objects with keys starting with the prefix
+func (r FilesystemRepository) ListObjects(prefix string) ([]m.StoredObject, error) {
+	var objects []m.StoredObject
+
+	err := filepath.WalkDir(r.Root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(r.Root, path)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, m.StoredObject{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// ObjectURL returns a signed url to download the object from the file handlers
+func (r FilesystemRepository) ObjectURL(key string) string {
+	return r.signedURL(http.MethodGet, key)
+}
+
+// UploadURL returns a signed url to upload the object to the file handlers with a PUT request
+func (r FilesystemRepository) UploadURL(key string, contentType string) (string, error) {
+	return r.signedURL(http.MethodPut, key), nil
+}
+
+// VerifyURL checks the signature of a url handed out for the method and key
+func (r FilesystemRepository) VerifyURL(method string, key string, expires string, signature string) error {
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return errors.New("invalid signature")
+	}
+
+	expected, _ := hex.DecodeString(r.signature(method, key, expires))
+	actual, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, actual) {
+		return errors.New("invalid signature")
+	}
+
+	if time.Now().Unix() > expiresAt {
+		return errors.New("url expired")
+	}
+
+	return nil
+}
+
+func (r FilesystemRepository) signedURL(method string, key string) string {
+	expires := strconv.FormatInt(time.Now().Add(r.Expiry).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", r.signature(method, key, expires))
+
+	return fmt.Sprintf("%s/%s?%s", r.PublicUrl, key, query.Encode())
+}
+
+func (r FilesystemRepository) signature(method string, key string, expires string) string {
+	mac := hmac.New(sha256.New, r.signingKey)
+	mac.Write([]byte(method + "\n" + key + "\n" + expires))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path resolves the key to a file below the root directory, rejecting keys that would escape it
+func (r FilesystemRepository) path(key string) (string, error) {
+	path := filepath.Join(r.Root, filepath.FromSlash(key))
+
+	if key == "" || !strings.HasPrefix(path, r.Root+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid object key %s", key)
+	}
+
+	return path, nil
+}
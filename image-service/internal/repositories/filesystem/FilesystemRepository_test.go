@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjectUpload_OK(t *testing.T) {
+	root := t.TempDir()
+	r := NewFilesystemRepository(root, "http://example.com/files/", "secret", 15*time.Minute)
+
+	err := r.UploadObject("img/id/card.jpg", strings.NewReader("image"), "image/jpeg")
+
+	assert.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(root, "img", "id", "card.jpg"))
+	assert.NoError(t, err)
+	assert.Equal(t, "image", string(data))
+
+	// no temporary files are left behind
+	entries, _ := os.ReadDir(filepath.Join(root, "img", "id"))
+	assert.Len(t, entries, 1)
+}
+
+func TestObjectUpload_Overwrite(t *testing.T) {
+	r := NewFilesystemRepository(t.TempDir(), "http://example.com/files", "secret", 15*time.Minute)
+
+	assert.NoError(t, r.UploadObject("img/id/card.jpg", strings.NewReader("image"), "image/jpeg"))
+	assert.NoError(t, r.UploadObject("img/id/card.jpg", strings.NewReader("other"), "image/jpeg"))
+
+	file, err := r.DownloadObject("img/id/card.jpg")
+	assert.NoError(t, err)
+	defer file.Close()
+
+	data, _ := io.ReadAll(file)
+	assert.Equal(t, "other", string(data))
+}
+
+func TestObjectUpload_KeyErr(t *testing.T) {
+	root := t.TempDir()
+	r := NewFilesystemRepository(filepath.Join(root, "store"), "http://example.com/files", "secret", 15*time.Minute)
+
+	for _, key := range []string{"", "../escaped.jpg", "img/../../escaped.jpg"} {
+		err := r.UploadObject(key, strings.NewReader("image"), "image/jpeg")
+
+		assert.Error(t, err)
+	}
+
+	_, err := os.Stat(filepath.Join(root, "escaped.jpg"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestObjectDownload_OK(t *testing.T) {
+	r := NewFilesystemRepository(t.TempDir(), "http://example.com/files", "secret", 15*time.Minute)
+	r.UploadObject("img/id/card.jpg", strings.NewReader("image"), "image/jpeg")
+
+	file, err := r.DownloadObject("img/id/card.jpg")
+
+	assert.NoError(t, err)
+	defer file.Close()
+
+	data, _ := io.ReadAll(file)
+	assert.Equal(t, "image", string(data))
+}
+
+func TestObjectDownload_NotFound(t *testing.T) {
+	r := NewFilesystemRepository(t.TempDir(), "http://example.com/files", "secret", 15*time.Minute)
+	r.UploadObject("img/id/card.jpg", strings.NewReader("image"), "image/jpeg")
+
+	for _, key := range []string{"img/id/full.jpg", "img/id", "../card.jpg"} {
+		file, err := r.DownloadObject(key)
+
+		assert.EqualError(t, err, "not found")
+		assert.Nil(t, file)
+	}
+}
+
+func TestObjectOpen_OK(t *testing.T) {
+	r := NewFilesystemRepository(t.TempDir(), "http://example.com/files", "secret", 15*time.Minute)
+	r.UploadObject("img/id/card.jpg", strings.NewReader("image"), "image/jpeg")
+
+	file, info, err := r.OpenObject("img/id/card.jpg")
+
+	assert.NoError(t, err)
+	defer file.Close()
+
+	assert.Equal(t, int64(5), info.Size())
+
+	file.Seek(2, io.SeekStart)
+	data, _ := io.ReadAll(file)
+	assert.Equal(t, "age", string(data))
+}
+
+func TestObjectDelete_OK(t *testing.T) {
+	root := t.TempDir()
+	r := NewFilesystemRepository(root, "http://example.com/files", "secret", 15*time.Minute)
+	r.UploadObject("img/id/card.jpg", strings.NewReader("image"), "image/jpeg")
+
+	err := r.DeleteObject("img/id/card.jpg")
+
+	assert.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(root, "img", "id", "card.jpg"))
+	assert.True(t, os.IsNotExist(err))
+
+	// deleting again is not an error
+	assert.NoError(t, r.DeleteObject("img/id/card.jpg"))
+}
+
+func TestObjectDelete_KeyErr(t *testing.T) {
+	r := NewFilesystemRepository(t.TempDir(), "http://example.com/files", "secret", 15*time.Minute)
+
+	err := r.DeleteObject("../card.jpg")
+
+	assert.Error(t, err)
+}
+
+func TestListObjects(t *testing.T) {
+	r := NewFilesystemRepository(t.TempDir(), "http://example.com/files", "secret", 15*time.Minute)
+
+	for _, key := range []string{"img/a/card.jpg", "img/b/card.jpg", "uploads/c"} {
+		assert.NoError(t, r.UploadObject(key, strings.NewReader("image"), "image/jpeg"))
+	}
+
+	result, err := r.ListObjects("img/")
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "img/a/card.jpg", result[0].Key)
+	assert.Equal(t, int64(5), result[0].Size)
+	assert.WithinDuration(t, time.Now(), result[0].LastModified, time.Minute)
+
+	result, err = r.ListObjects("")
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+}
+
+func TestListObjects_EmptyRoot(t *testing.T) {
+	r := NewFilesystemRepository(filepath.Join(t.TempDir(), "missing"), "http://example.com/files", "secret", 15*time.Minute)
+
+	result, err := r.ListObjects("")
+
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+}
+
+func TestObjectURL(t *testing.T) {
+	r := NewFilesystemRepository(t.TempDir(), "http://example.com/files/", "secret", 15*time.Minute)
+
+	result, err := url.Parse(r.ObjectURL("img/id/card.jpg"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/files/img/id/card.jpg", result.Path)
+	assert.NoError(t, r.VerifyURL("GET", "img/id/card.jpg", result.Query().Get("expires"), result.Query().Get("signature")))
+}
+
+func TestUploadURL(t *testing.T) {
+	r := NewFilesystemRepository(t.TempDir(), "http://example.com/files/", "secret", 15*time.Minute)
+
+	uploadURL, err := r.UploadURL("uploads/id", "image/jpeg")
+	assert.NoError(t, err)
+
+	result, _ := url.Parse(uploadURL)
+	expires, signature := result.Query().Get("expires"), result.Query().Get("signature")
+
+	assert.NoError(t, r.VerifyURL("PUT", "uploads/id", expires, signature))
+
+	// the signature is bound to the method and key
+	assert.EqualError(t, r.VerifyURL("GET", "uploads/id", expires, signature), "invalid signature")
+	assert.EqualError(t, r.VerifyURL("PUT", "uploads/other", expires, signature), "invalid signature")
+}
+
+func TestVerifyURL_Err(t *testing.T) {
+	r := NewFilesystemRepository(t.TempDir(), "http://example.com/files/", "secret", 15*time.Minute)
+	other := NewFilesystemRepository(t.TempDir(), "http://example.com/files/", "other", 15*time.Minute)
+	expired := NewFilesystemRepository(t.TempDir(), "http://example.com/files/", "secret", -time.Minute)
+
+	result, _ := url.Parse(r.ObjectURL("img/id/card.jpg"))
+	expires, signature := result.Query().Get("expires"), result.Query().Get("signature")
+
+	assert.EqualError(t, r.VerifyURL("GET", "img/id/card.jpg", "", signature), "invalid signature")
+	assert.EqualError(t, r.VerifyURL("GET", "img/id/card.jpg", expires, ""), "invalid signature")
+	assert.EqualError(t, r.VerifyURL("GET", "img/id/card.jpg", expires, "zz"), "invalid signature")
+	assert.EqualError(t, r.VerifyURL("GET", "img/id/card.jpg", expires+"0", signature), "invalid signature")
+	assert.EqualError(t, other.VerifyURL("GET", "img/id/card.jpg", expires, signature), "invalid signature")
+
+	result, _ = url.Parse(expired.ObjectURL("img/id/card.jpg"))
+	assert.EqualError(t, expired.VerifyURL("GET", "img/id/card.jpg", result.Query().Get("expires"), result.Query().Get("signature")), "url expired")
+}
@@ -1,9 +1,12 @@
 package repositories
 
 import (
+	"cookbook/pkg/outbox"
 	"errors"
 	m "image-service/internal/models"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -20,7 +23,42 @@ func NewImageRepository(db *gorm.DB) *ImageRepository {
 func (r ImageRepository) FindAll() ([]m.Image, error) {
 	var images []m.Image
 
-	if err := r.db.Find(&images).Error; err != nil {
+	if err := r.db.Preload("Variants").Find(&images).Error; err != nil {
+		return nil, err
+	}
+
+	if len(images) <= 0 {
+		return nil, errors.New("not found")
+	}
+
+	return images, nil
+}
+
+func (r ImageRepository) FindByEntity(entityType string, entityID uuid.UUID) ([]m.Image, error) {
+	var images []m.Image
+
+	if err := r.db.Preload("Variants").
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("created_at").
+		Find(&images).Error; err != nil {
+		return nil, err
+	}
+
+	if len(images) <= 0 {
+		return nil, errors.New("not found")
+	}
+
+	return images, nil
+}
+
+// FindDeleted returns the images deleted since the given time. Their variants are kept in storage, as the images
+// may still be restored along with their entity.
+func (r ImageRepository) FindDeleted(since time.Time) ([]m.Image, error) {
+	var images []m.Image
+
+	if err := r.db.Unscoped().Preload("Variants").
+		Where("deleted_at >= ?", since).
+		Find(&images).Error; err != nil {
 		return nil, err
 	}
 
@@ -33,7 +71,23 @@ func (r ImageRepository) FindAll() ([]m.Image, error) {
 
 func (r ImageRepository) Find(image m.Image) (m.Image, error) {
 
-	result := r.db.First(&image)
+	result := r.db.Preload("Variants").First(&image)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return m.Image{}, errors.New("not found")
+		} else {
+			return m.Image{}, result.Error
+		}
+	}
+
+	return image, nil
+}
+
+// FindByHash returns an image uploaded with the same contents, so its stored variants can be reused
+func (r ImageRepository) FindByHash(hash string) (m.Image, error) {
+	var image m.Image
+
+	result := r.db.Preload("Variants").Where("hash = ?", hash).Order("created_at").First(&image)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return m.Image{}, errors.New("not found")
@@ -53,7 +107,7 @@ func (r ImageRepository) Create(image m.Image) (m.Image, error) {
 			return err
 		}
 
-		return nil
+		return outbox.Record(tx, m.NewChangeEvent(m.EventCreated, m.EntityImage, image.ID))
 	}); err != nil {
 		return m.Image{}, err
 	}
@@ -69,7 +123,7 @@ func (r ImageRepository) Update(image m.Image) (m.Image, error) {
 			return err
 		}
 
-		return nil
+		return outbox.Record(tx, m.NewChangeEvent(m.EventUpdated, m.EntityImage, image.ID))
 	}); err != nil {
 		return m.Image{}, err
 	}
@@ -86,10 +140,71 @@ func (r ImageRepository) Delete(image m.Image) error {
 			return err
 		}
 
-		return nil
+		return outbox.Record(tx, m.NewChangeEvent(m.EventDeleted, m.EntityImage, image.ID))
 	}); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// DeleteByEntity soft-deletes all images of a deleted entity. The stored variants are left in place, so the images
+// can be restored along with the entity.
+func (r ImageRepository) DeleteByEntity(entityType string, entityID uuid.UUID) error {
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var imageIDs []uuid.UUID
+
+		if err := tx.Model(&m.Image{}).
+			Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+			Pluck("id", &imageIDs).Error; err != nil {
+			return err
+		}
+
+		if len(imageIDs) <= 0 {
+			return nil
+		}
+
+		if err := tx.Where("id IN ?", imageIDs).Delete(&m.Image{}).Error; err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, imageEvents(m.EventDeleted, imageIDs)...)
+	})
+}
+
+// RestoreByEntity restores the images of an entity that were deleted since the given time
+func (r ImageRepository) RestoreByEntity(entityType string, entityID uuid.UUID, since time.Time) error {
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var imageIDs []uuid.UUID
+
+		if err := tx.Unscoped().Model(&m.Image{}).
+			Where("entity_type = ? AND entity_id = ? AND deleted_at >= ?", entityType, entityID, since).
+			Pluck("id", &imageIDs).Error; err != nil {
+			return err
+		}
+
+		if len(imageIDs) <= 0 {
+			return nil
+		}
+
+		if err := tx.Unscoped().Model(&m.Image{}).
+			Where("id IN ?", imageIDs).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, imageEvents(m.EventCreated, imageIDs)...)
+	})
+}
+
+func imageEvents(eventType string, imageIDs []uuid.UUID) []m.ChangeEvent {
+	events := make([]m.ChangeEvent, len(imageIDs))
+
+	for i, imageID := range imageIDs {
+		events[i] = m.NewChangeEvent(eventType, m.EntityImage, imageID)
+	}
+
+	return events
+}
@@ -25,6 +25,17 @@ var (
 		EntityID:   uuid.New(),
 		Size:       1,
 		Type:       "image/jpeg",
+		Hash:       "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+	}
+
+	variant m.ImageVariant = m.ImageVariant{
+		ImageID: image.ID,
+		Name:    "card",
+		Format:  "jpeg",
+		Width:   800,
+		Height:  600,
+		Size:    1,
+		Key:     "img/" + image.ID.String() + "/card.jpg",
 	}
 )
 
@@ -70,6 +81,12 @@ func timeFunc() time.Time {
 	return time
 }
 
+func expectOutbox(mock sqlmock.Sqlmock, eventType string) {
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "sequence"`)).
+		WithArgs(sqlmock.AnyArg(), m.EntityImage, image.ID, eventType, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "").
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1))
+}
+
 func TestImageFindAll_OK(t *testing.T) {
 	db, mock := newMockDatabase(t)
 	r := NewImageRepository(db)
@@ -83,11 +100,16 @@ func TestImageFindAll_OK(t *testing.T) {
 				image.Size,
 				image.Type,
 			))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "image_variants" WHERE "image_variants"."image_id" = $1`)).
+		WithArgs(image.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"image_id", "name", "format", "key"}).
+			AddRow(variant.ImageID, variant.Name, variant.Format, variant.Key))
 
 	result, err := r.FindAll()
 
 	assert.NoError(t, err)
 	assert.Len(t, result, 1)
+	assert.Len(t, result[0].Variants, 1)
 }
 
 func TestImageFindAll_NotFoundErr(t *testing.T) {
@@ -118,6 +140,72 @@ func TestImageFindAll_Err(t *testing.T) {
 	assert.Len(t, result, 0)
 }
 
+func TestImageFindByEntity_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewImageRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "images" WHERE (entity_type = $1 AND entity_id = $2) AND "images"."deleted_at" IS NULL ORDER BY created_at`)).
+		WithArgs(image.EntityType, image.EntityID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "entity_type", "entity_id", "size", "type", "hash"}).
+			AddRow(
+				image.ID,
+				image.EntityType,
+				image.EntityID,
+				image.Size,
+				image.Type,
+				image.Hash,
+			))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "image_variants" WHERE "image_variants"."image_id" = $1`)).
+		WithArgs(image.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"image_id", "name", "format", "width", "height", "size", "key"}).
+			AddRow(
+				variant.ImageID,
+				variant.Name,
+				variant.Format,
+				variant.Width,
+				variant.Height,
+				variant.Size,
+				variant.Key,
+			))
+
+	result, err := r.FindByEntity(image.EntityType, image.EntityID)
+
+	expected := image
+	expected.Variants = []m.ImageVariant{variant}
+
+	assert.NoError(t, err)
+	assert.Equal(t, []m.Image{expected}, result)
+}
+
+func TestImageFindByEntity_NotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewImageRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "images" WHERE (entity_type = $1 AND entity_id = $2) AND "images"."deleted_at" IS NULL ORDER BY created_at`)).
+		WithArgs(image.EntityType, image.EntityID).
+		WillReturnRows(&sqlmock.Rows{})
+
+	result, err := r.FindByEntity(image.EntityType, image.EntityID)
+
+	assert.EqualError(t, err, "not found")
+	assert.Len(t, result, 0)
+}
+
+func TestImageFindByEntity_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewImageRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "images" WHERE (entity_type = $1 AND entity_id = $2) AND "images"."deleted_at" IS NULL ORDER BY created_at`)).
+		WithArgs(image.EntityType, image.EntityID).
+		WillReturnError(errors.New("error"))
+
+	result, err := r.FindByEntity(image.EntityType, image.EntityID)
+
+	assert.EqualError(t, err, "error")
+	assert.Len(t, result, 0)
+}
+
 func TestImageFind_OK(t *testing.T) {
 	db, mock := newMockDatabase(t)
 	r := NewImageRepository(db)
@@ -132,10 +220,26 @@ func TestImageFind_OK(t *testing.T) {
 				image.Type,
 			))
 
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "image_variants" WHERE "image_variants"."image_id" = $1`)).
+		WithArgs(image.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"image_id", "name", "format", "width", "height", "size", "key"}).
+			AddRow(
+				variant.ImageID,
+				variant.Name,
+				variant.Format,
+				variant.Width,
+				variant.Height,
+				variant.Size,
+				variant.Key,
+			))
+
 	result, err := r.Find(image)
 
+	expected := image
+	expected.Variants = []m.ImageVariant{variant}
+
 	assert.NoError(t, err)
-	assert.Equal(t, image, result)
+	assert.Equal(t, expected, result)
 }
 
 func TestImageFind_NotFoundErr(t *testing.T) {
@@ -166,17 +270,66 @@ func TestImageFind_Err(t *testing.T) {
 	assert.Equal(t, m.Image{}, result)
 }
 
+func TestImageFindByHash_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewImageRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "images" WHERE hash = $1 AND "images"."deleted_at" IS NULL ORDER BY created_at,"images"."id" LIMIT $2`)).
+		WithArgs(image.Hash, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "entity_type", "entity_id", "hash"}).
+			AddRow(image.ID, image.EntityType, image.EntityID, image.Hash))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "image_variants" WHERE "image_variants"."image_id" = $1`)).
+		WithArgs(image.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"image_id", "name", "format", "key"}).
+			AddRow(variant.ImageID, variant.Name, variant.Format, variant.Key))
+
+	result, err := r.FindByHash(image.Hash)
+
+	assert.NoError(t, err)
+	assert.Equal(t, image.ID, result.ID)
+	assert.Equal(t, variant.Key, result.Variants[0].Key)
+}
+
+func TestImageFindByHash_NotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewImageRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "images" WHERE hash = $1 AND "images"."deleted_at" IS NULL ORDER BY created_at,"images"."id" LIMIT $2`)).
+		WithArgs(image.Hash, 1).
+		WillReturnRows(&sqlmock.Rows{})
+
+	result, err := r.FindByHash(image.Hash)
+
+	assert.EqualError(t, err, "not found")
+	assert.Equal(t, m.Image{}, result)
+}
+
+func TestImageFindByHash_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewImageRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "images" WHERE hash = $1 AND "images"."deleted_at" IS NULL ORDER BY created_at,"images"."id" LIMIT $2`)).
+		WithArgs(image.Hash, 1).
+		WillReturnError(errors.New("error"))
+
+	result, err := r.FindByHash(image.Hash)
+
+	assert.EqualError(t, err, "error")
+	assert.Equal(t, m.Image{}, result)
+}
+
 func TestImageCreate_OK(t *testing.T) {
 	db, mock := newMockDatabase(t)
 	r := NewImageRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "images" ("entity_type","entity_id","size","type","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "images" ("entity_type","entity_id","size","type","hash","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING "id"`)).
 		WithArgs(
 			image.EntityType,
 			image.EntityID,
 			image.Size,
 			image.Type,
+			image.Hash,
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
 			nil,
@@ -184,11 +337,45 @@ func TestImageCreate_OK(t *testing.T) {
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).
 			AddRow(image.ID))
+	expectOutbox(mock, m.EventCreated)
 	mock.ExpectCommit()
 	result, err := r.Create(image)
 
 	assert.NoError(t, err)
 	assert.IsType(t, m.Image{}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImageCreate_VariantsOK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewImageRepository(db)
+
+	withVariants := image
+	withVariants.Variants = []m.ImageVariant{variant}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "images" ("entity_type","entity_id","size","type","hash","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING "id"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(image.ID))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "image_variants" ("image_id","name","format","width","height","size","key") VALUES ($1,$2,$3,$4,$5,$6,$7) ON CONFLICT ("image_id","name","format") DO UPDATE SET "image_id"="excluded"."image_id"`)).
+		WithArgs(
+			variant.ImageID,
+			variant.Name,
+			variant.Format,
+			variant.Width,
+			variant.Height,
+			variant.Size,
+			variant.Key,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutbox(mock, m.EventCreated)
+	mock.ExpectCommit()
+
+	result, err := r.Create(withVariants)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Variants, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestImageCreate_Err(t *testing.T) {
@@ -196,12 +383,13 @@ func TestImageCreate_Err(t *testing.T) {
 	r := NewImageRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "images" ("entity_type","entity_id","size","type","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "images" ("entity_type","entity_id","size","type","hash","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING "id"`)).
 		WithArgs(
 			image.EntityType,
 			image.EntityID,
 			image.Size,
 			image.Type,
+			image.Hash,
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
 			nil,
@@ -221,21 +409,24 @@ func TestImageUpdate_OK(t *testing.T) {
 	r := NewImageRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "images" SET "entity_type"=$1,"entity_id"=$2,"size"=$3,"type"=$4,"updated_at"=$5 WHERE "images"."deleted_at" IS NULL AND "id" = $6`)).
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "images" SET "entity_type"=$1,"entity_id"=$2,"size"=$3,"type"=$4,"hash"=$5,"updated_at"=$6 WHERE "images"."deleted_at" IS NULL AND "id" = $7`)).
 		WithArgs(
 			image.EntityType,
 			image.EntityID,
 			image.Size,
 			image.Type,
+			image.Hash,
 			sqlmock.AnyArg(),
 			image.ID,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutbox(mock, m.EventUpdated)
 	mock.ExpectCommit()
 	result, err := r.Update(image)
 
 	assert.NoError(t, err)
 	assert.IsType(t, m.Image{}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestImageUpdate_Err(t *testing.T) {
@@ -243,12 +434,13 @@ func TestImageUpdate_Err(t *testing.T) {
 	r := NewImageRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "images" SET "entity_type"=$1,"entity_id"=$2,"size"=$3,"type"=$4,"updated_at"=$5 WHERE "images"."deleted_at" IS NULL AND "id" = $6`)).
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "images" SET "entity_type"=$1,"entity_id"=$2,"size"=$3,"type"=$4,"hash"=$5,"updated_at"=$6 WHERE "images"."deleted_at" IS NULL AND "id" = $7`)).
 		WithArgs(
 			image.EntityType,
 			image.EntityID,
 			image.Size,
 			image.Type,
+			image.Hash,
 			sqlmock.AnyArg(),
 			image.ID,
 		).
@@ -272,11 +464,13 @@ func TestImageDelete_OK(t *testing.T) {
 			image.ID,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutbox(mock, m.EventDeleted)
 	mock.ExpectCommit()
 
 	err := r.Delete(image)
 
 	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestImageDelete_Err(t *testing.T) {
@@ -297,3 +491,117 @@ func TestImageDelete_Err(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualError(t, err, "error")
 }
+
+func TestImageFindDeleted_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewImageRepository(db)
+	since := timeFunc()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "images" WHERE deleted_at >= $1`)).
+		WithArgs(since).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "entity_type", "entity_id", "size", "type"}).
+			AddRow(
+				image.ID,
+				image.EntityType,
+				image.EntityID,
+				image.Size,
+				image.Type,
+			))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "image_variants" WHERE "image_variants"."image_id" = $1`)).
+		WithArgs(image.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"image_id", "name", "format", "key"}).
+			AddRow(variant.ImageID, variant.Name, variant.Format, variant.Key))
+
+	result, err := r.FindDeleted(since)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Len(t, result[0].Variants, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImageFindDeleted_NotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewImageRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "images" WHERE deleted_at >= $1`)).
+		WillReturnRows(&sqlmock.Rows{})
+
+	result, err := r.FindDeleted(timeFunc())
+
+	assert.EqualError(t, err, "not found")
+	assert.Len(t, result, 0)
+}
+
+func TestImageDeleteByEntity_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewImageRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "images" WHERE (entity_type = $1 AND entity_id = $2) AND "images"."deleted_at" IS NULL`)).
+		WithArgs(image.EntityType, image.EntityID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(image.ID))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "images" SET "deleted_at"=$1 WHERE id IN ($2) AND "images"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), image.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutbox(mock, m.EventDeleted)
+	mock.ExpectCommit()
+
+	err := r.DeleteByEntity(image.EntityType, image.EntityID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImageDeleteByEntity_NoImages(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewImageRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "images" WHERE (entity_type = $1 AND entity_id = $2) AND "images"."deleted_at" IS NULL`)).
+		WithArgs(image.EntityType, image.EntityID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	err := r.DeleteByEntity(image.EntityType, image.EntityID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImageDeleteByEntity_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewImageRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "images" WHERE (entity_type = $1 AND entity_id = $2) AND "images"."deleted_at" IS NULL`)).
+		WithArgs(image.EntityType, image.EntityID).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	err := r.DeleteByEntity(image.EntityType, image.EntityID)
+
+	assert.EqualError(t, err, "error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImageRestoreByEntity_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewImageRepository(db)
+	since := timeFunc()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "images" WHERE entity_type = $1 AND entity_id = $2 AND deleted_at >= $3`)).
+		WithArgs(image.EntityType, image.EntityID, since).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(image.ID))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "images" SET "deleted_at"=$1,"updated_at"=$2 WHERE id IN ($3)`)).
+		WithArgs(nil, sqlmock.AnyArg(), image.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutbox(mock, m.EventCreated)
+	mock.ExpectCommit()
+
+	err := r.RestoreByEntity(image.EntityType, image.EntityID, since)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
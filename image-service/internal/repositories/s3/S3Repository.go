@@ -1,11 +1,17 @@
 package repositories
 
 import (
+	"errors"
 	"fmt"
-	m "image-service/internal/models"
+	"io"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
+
+	m "image-service/internal/models"
 )
 
 type LoggerInterface interface {
@@ -14,45 +20,120 @@ type LoggerInterface interface {
 
 type S3Interface interface {
 	PutObject(input *s3.PutObjectInput) (*s3.PutObjectOutput, error)
+	PutObjectRequest(input *s3.PutObjectInput) (*request.Request, *s3.PutObjectOutput)
+	GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error)
+	GetObjectRequest(input *s3.GetObjectInput) (*request.Request, *s3.GetObjectOutput)
 	DeleteObject(input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error)
+	ListObjectsV2Pages(input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error
 }
 
+// S3Repository keeps the objects private in the bucket. Access is granted through presigned urls that are
+// valid for the configured expiry
 type S3Repository struct {
 	BucketName string
+	Expiry     time.Duration
 	s3Client   S3Interface
 	logger     LoggerInterface
 }
 
-func NewS3Repository(s3Client S3Interface, logger LoggerInterface, bucketName string) *S3Repository {
+func NewS3Repository(s3Client S3Interface, logger LoggerInterface, bucketName string, expiry time.Duration) *S3Repository {
 	return &S3Repository{
 		BucketName: bucketName,
+		Expiry:     expiry,
 		s3Client:   s3Client,
 		logger:     logger,
 	}
 }
 
-func (r S3Repository) UploadImage(image m.Image) error {
-
-	objectPath := fmt.Sprintf("img/%s.jpg", image.ID.String())
+func (r S3Repository) UploadObject(key string, body io.ReadSeeker, contentType string) error {
 
 	_, err := r.s3Client.PutObject(&s3.PutObjectInput{
-		Bucket: aws.String(r.BucketName),
-		Key:    aws.String(objectPath),
-		Body:   image.File,
-		ACL:    aws.String("public-read"),
+		Bucket:      aws.String(r.BucketName),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
 	})
 
 	return err
 }
 
-func (r S3Repository) DeleteImage(image m.Image) error {
+func (r S3Repository) DownloadObject(key string) (io.ReadCloser, error) {
+
+	output, err := r.s3Client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(r.BucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, errors.New("not found")
+		}
+		return nil, err
+	}
+
+	return output.Body, nil
+}
 
-	objectPath := fmt.Sprintf("img/%s.jpg", image.ID.String())
+func (r S3Repository) DeleteObject(key string) error {
 
 	_, err := r.s3Client.DeleteObject(&s3.DeleteObjectInput{
 		Bucket: aws.String(r.BucketName),
-		Key:    aws.String(objectPath),
+		Key:    aws.String(key),
 	})
 
 	return err
 }
+
+// ListObjects returns the objects with keys starting with the prefix
+func (r S3Repository) ListObjects(prefix string) ([]m.StoredObject, error) {
+	var objects []m.StoredObject
+
+	err := r.s3Client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(r.BucketName),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			objects = append(objects, m.StoredObject{
+				Key:          aws.StringValue(object.Key),
+				Size:         aws.Int64Value(object.Size),
+				LastModified: aws.TimeValue(object.LastModified),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// ObjectURL returns a presigned url to download the object. An empty string is returned if signing fails
+func (r S3Repository) ObjectURL(key string) string {
+
+	req, _ := r.s3Client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(r.BucketName),
+		Key:    aws.String(key),
+	})
+
+	url, err := req.Presign(r.Expiry)
+	if err != nil {
+		r.logger.Error(fmt.Sprintf("unable to presign download of %s: %s", key, err.Error()))
+		return ""
+	}
+
+	return url
+}
+
+// UploadURL returns a presigned url to upload the object with a PUT request. The request has to carry the given
+// content type
+func (r S3Repository) UploadURL(key string, contentType string) (string, error) {
+
+	req, _ := r.s3Client.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:      aws.String(r.BucketName),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	})
+
+	return req.Presign(r.Expiry)
+}
@@ -3,48 +3,59 @@ package repositories
 import (
 	"errors"
 	"fmt"
-	"image"
-	m "image-service/internal/models"
-	"image/color"
-	"image/png"
 	"io"
-	"mime/multipart"
+	"net/http"
 	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
 var (
 	filename string
-
-	img m.Image = m.Image{
-		ID: uuid.New(),
-	}
 )
 
 type LoggerInterfaceMock struct{}
 
-type S3InterfaceMock struct{}
+// S3InterfaceMock overrides the object operations, presigning is done by the embedded client
+type S3InterfaceMock struct {
+	*s3.S3
+}
 
 func (LoggerInterfaceMock) Error(args ...interface{}) {}
 
 func (S3InterfaceMock) PutObject(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
-	name := fmt.Sprintf("img/%s.jpg", filename)
 	switch *input.Key {
-	case name:
+	case filename:
 		return nil, nil
 	default:
 		return nil, errors.New("error")
 	}
 }
 
+func (S3InterfaceMock) GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	switch {
+	case *input.Key == filename:
+		return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("image"))}, nil
+	case filename == "notfound":
+		return nil, awserr.New(s3.ErrCodeNoSuchKey, "key does not exist", nil)
+	default:
+		return nil, errors.New("error")
+	}
+}
+
 func (S3InterfaceMock) DeleteObject(input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error) {
-	name := fmt.Sprintf("img/%s.jpg", filename)
 	switch *input.Key {
-	case name:
+	case filename:
 		return nil, nil
 	default:
 		return nil, errors.New("error")
@@ -52,93 +63,265 @@ func (S3InterfaceMock) DeleteObject(input *s3.DeleteObjectInput) (*s3.DeleteObje
 
 }
 
+func newMockRepository(t *testing.T) *S3Repository {
+	return NewS3Repository(&S3InterfaceMock{newS3Client(t, "http://localhost:9000")}, &LoggerInterfaceMock{}, "bucket", 15*time.Minute)
+}
+
 // ========================================================================================================
 
-func TestImageUpload_OK(t *testing.T) {
+func TestObjectUpload_OK(t *testing.T) {
+	r := newMockRepository(t)
+	filename = "img/id/card.jpg"
+
+	err := r.UploadObject("img/id/card.jpg", strings.NewReader("image"), "image/jpeg")
+
+	assert.NoError(t, err)
+}
+
+func TestObjectUpload_PutErr(t *testing.T) {
+	r := newMockRepository(t)
+	filename = "filename"
+
+	err := r.UploadObject("img/id/card.jpg", strings.NewReader("image"), "image/jpeg")
+
+	assert.Error(t, err)
+}
+
+func TestObjectDownload_OK(t *testing.T) {
+	r := newMockRepository(t)
+	filename = "img/id/card.jpg"
+
+	result, err := r.DownloadObject("img/id/card.jpg")
+
+	assert.NoError(t, err)
+
+	body, _ := io.ReadAll(result)
+	assert.Equal(t, "image", string(body))
+}
+
+func TestObjectDownload_NotFound(t *testing.T) {
+	r := newMockRepository(t)
+	filename = "notfound"
+
+	result, err := r.DownloadObject("img/id/card.jpg")
+
+	assert.EqualError(t, err, "not found")
+	assert.Nil(t, result)
+}
 
-	r := NewS3Repository(&S3InterfaceMock{}, &LoggerInterfaceMock{}, "bucket")
+func TestObjectDownload_Err(t *testing.T) {
+	r := newMockRepository(t)
 	filename = "filename"
-	img.File = createFile(t)
 
-	err := r.UploadImage(img)
+	result, err := r.DownloadObject("img/id/card.jpg")
+
+	assert.EqualError(t, err, "error")
+	assert.Nil(t, result)
+}
+
+func TestObjectDelete_OK(t *testing.T) {
+	r := newMockRepository(t)
+	filename = "img/id/card.jpg"
+
+	err := r.DeleteObject("img/id/card.jpg")
 
 	assert.NoError(t, err)
 }
 
-func TestImageUpload_PutErr(t *testing.T) {
-	r := NewS3Repository(&S3InterfaceMock{}, &LoggerInterfaceMock{}, "bucket")
+func TestObjectDelete_Err(t *testing.T) {
+	r := newMockRepository(t)
 	filename = "filename"
-	img.File = createFile(t)
 
-	err := r.UploadImage(img)
+	err := r.DeleteObject("img/id/card.jpg")
 
 	assert.Error(t, err)
 }
 
+func TestObjectURL(t *testing.T) {
+	r := newMockRepository(t)
+
+	result := r.ObjectURL("img/id/card.jpg")
+
+	assert.True(t, strings.HasPrefix(result, "http://localhost:9000/bucket/img/id/card.jpg?"))
+	assert.Contains(t, result, "X-Amz-Signature=")
+	assert.Contains(t, result, "X-Amz-Expires=900")
+}
+
+func TestUploadURL(t *testing.T) {
+	r := newMockRepository(t)
+
+	result, err := r.UploadURL("uploads/id", "image/jpeg")
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(result, "http://localhost:9000/bucket/uploads/id?"))
+	assert.Contains(t, result, "X-Amz-SignedHeaders=content-type%3Bhost")
+}
+
+// ====== S3 stand-in ======
+
+func TestStandIn_PresignedRoundTrip(t *testing.T) {
+	srv := newS3StandIn()
+	defer srv.Close()
+
+	r := NewS3Repository(newS3Client(t, srv.URL), &LoggerInterfaceMock{}, "bucket", 15*time.Minute)
+
+	// upload directly to storage with the presigned url
+	uploadURL, err := r.UploadURL("uploads/id", "image/jpeg")
+	assert.NoError(t, err)
+
+	req, _ := http.NewRequest("PUT", uploadURL, strings.NewReader("image"))
+	req.Header.Set("Content-Type", "image/jpeg")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// the upload is available to the service
+	file, err := r.DownloadObject("uploads/id")
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(file)
+	assert.Equal(t, "image", string(body))
+
+	// and to clients through the presigned download url
+	resp, err = http.Get(r.ObjectURL("uploads/id"))
+	assert.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	assert.Equal(t, "image", string(body))
+
+	assert.NoError(t, r.DeleteObject("uploads/id"))
+
+	_, err = r.DownloadObject("uploads/id")
+	assert.EqualError(t, err, "not found")
+}
+
+func TestStandIn_Upload(t *testing.T) {
+	srv := newS3StandIn()
+	defer srv.Close()
+
+	r := NewS3Repository(newS3Client(t, srv.URL), &LoggerInterfaceMock{}, "bucket", 15*time.Minute)
+
+	err := r.UploadObject("img/id/card.webp", strings.NewReader("image"), "image/webp")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "image/webp", srv.contentTypes["/bucket/img/id/card.webp"])
+
+	// objects are private, no ACL is sent along
+	assert.Empty(t, srv.acls["/bucket/img/id/card.webp"])
+}
+
+func TestStandIn_ListObjects(t *testing.T) {
+	srv := newS3StandIn()
+	defer srv.Close()
+
+	r := NewS3Repository(newS3Client(t, srv.URL), &LoggerInterfaceMock{}, "bucket", 15*time.Minute)
+
+	for _, key := range []string{"img/a/card.jpg", "img/b/card.jpg", "uploads/c"} {
+		assert.NoError(t, r.UploadObject(key, strings.NewReader("image"), "image/jpeg"))
+	}
+
+	result, err := r.ListObjects("img/")
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "img/a/card.jpg", result[0].Key)
+	assert.Equal(t, int64(5), result[0].Size)
+	assert.WithinDuration(t, time.Now(), result[0].LastModified, time.Minute)
+}
+
 // ====== Helpers ======
-func createImage() *image.RGBA {
-	width := 200
-	height := 100
-
-	upLeft := image.Point{0, 0}
-	lowRight := image.Point{width, height}
-
-	img := image.NewRGBA(image.Rectangle{upLeft, lowRight})
-
-	// Colors are defined by Red, Green, Blue, Alpha uint8 values.
-	cyan := color.RGBA{100, 200, 200, 0xff}
-
-	// Set color for each pixel.
-	for x := 0; x < width; x++ {
-		for y := 0; y < height; y++ {
-			switch {
-			case x < width/2 && y < height/2: // upper left quadrant
-				img.Set(x, y, cyan)
-			case x >= width/2 && y >= height/2: // lower right quadrant
-				img.Set(x, y, color.White)
-			default:
-				// Use zero value.
-			}
-		}
+
+func newS3Client(t *testing.T, endpoint string) *s3.S3 {
+	sess, err := session.NewSession(aws.NewConfig().
+		WithRegion("eu-west-1").
+		WithEndpoint(endpoint).
+		WithS3ForcePathStyle(true).
+		WithCredentials(credentials.NewStaticCredentials("key", "secret", "")))
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	return img
+	return s3.New(sess)
 }
 
-func createFile(t *testing.T) multipart.File {
-	// Set up a pipe to avoid buffering
-	pr, pw := io.Pipe()
-	// This writer is going to transform
-	// what we pass to it to multipart form data
-	// and write it to our io.Pipe
-	writer := multipart.NewWriter(pw)
+// s3StandIn is an in-memory, path style S3 compatible server supporting the object operations used by the repository
+type s3StandIn struct {
+	*httptest.Server
 
-	go func() {
-		defer writer.Close()
-		// We create the form data field 'fileupload'
-		// which returns another writer to write the actual file
-		part, err := writer.CreateFormFile("file", "someimg.png")
-		if err != nil {
-			t.Error(err)
-		}
+	mu           sync.Mutex
+	objects      map[string][]byte
+	contentTypes map[string]string
+	acls         map[string]string
+	modified     map[string]time.Time
+}
+
+func newS3StandIn() *s3StandIn {
+	s := &s3StandIn{
+		objects:      map[string][]byte{},
+		contentTypes: map[string]string{},
+		acls:         map[string]string{},
+		modified:     map[string]time.Time{},
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			s.objects[r.URL.Path] = body
+			s.contentTypes[r.URL.Path] = r.Header.Get("Content-Type")
+			s.acls[r.URL.Path] = r.Header.Get("X-Amz-Acl")
+			s.modified[r.URL.Path] = time.Now()
+			w.WriteHeader(http.StatusOK)
 
-		// https://yourbasic.org/golang/create-image/
-		img := createImage()
-
-		// Encode() takes an io.Writer.
-		// We pass the multipart field
-		// 'fileupload' that we defined
-		// earlier which, in turn, writes
-		// to our io.Pipe
-		err = png.Encode(part, img)
-		if err != nil {
-			t.Error(err)
+		case http.MethodGet:
+			if r.URL.Query().Get("list-type") == "2" {
+				s.list(w, r)
+				return
+			}
+
+			body, found := s.objects[r.URL.Path]
+			if !found {
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(http.StatusNotFound)
+				io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+				return
+			}
+			w.Header().Set("Content-Type", s.contentTypes[r.URL.Path])
+			w.Write(body)
+
+		case http.MethodDelete:
+			delete(s.objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
-	}()
+	}))
 
-	req := httptest.NewRequest("POST", "http://example.com/v1/recipe/1/upload", pr)
-	file, _, _ := req.FormFile("file")
+	return s
+}
 
-	return file
+// list answers a ListObjectsV2 request with all matching objects on a single page
+func (s *s3StandIn) list(w http.ResponseWriter, r *http.Request) {
+	bucket := strings.TrimSuffix(r.URL.Path, "/") + "/"
+	prefix := r.URL.Query().Get("prefix")
+
+	var keys []string
+	for path := range s.objects {
+		if key := strings.TrimPrefix(path, bucket); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var contents strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&contents, "<Contents><Key>%s</Key><Size>%d</Size><LastModified>%s</LastModified></Contents>",
+			key, len(s.objects[bucket+key]), s.modified[bucket+key].UTC().Format(time.RFC3339))
+	}
 
+	w.Header().Set("Content-Type", "application/xml")
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><ListBucketResult><Prefix>%s</Prefix><KeyCount>%d</KeyCount><IsTruncated>false</IsTruncated>%s</ListBucketResult>`,
+		prefix, len(keys), contents.String())
 }
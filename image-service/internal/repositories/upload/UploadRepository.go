@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"errors"
+	m "image-service/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type UploadRepository struct {
+	db *gorm.DB
+}
+
+func NewUploadRepository(db *gorm.DB) *UploadRepository {
+	return &UploadRepository{
+		db: db,
+	}
+}
+
+func (r UploadRepository) FindAll() ([]m.ImageUpload, error) {
+	var uploads []m.ImageUpload
+
+	if err := r.db.Find(&uploads).Error; err != nil {
+		return nil, err
+	}
+
+	if len(uploads) <= 0 {
+		return nil, errors.New("not found")
+	}
+
+	return uploads, nil
+}
+
+func (r UploadRepository) Find(upload m.ImageUpload) (m.ImageUpload, error) {
+
+	result := r.db.First(&upload)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return m.ImageUpload{}, errors.New("not found")
+		} else {
+			return m.ImageUpload{}, result.Error
+		}
+	}
+
+	return upload, nil
+}
+
+func (r UploadRepository) Create(upload m.ImageUpload) (m.ImageUpload, error) {
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Create(&upload).Error; err != nil {
+			return err
+		}
+
+		return nil
+	}); err != nil {
+		return m.ImageUpload{}, err
+	}
+
+	return upload, nil
+}
+
+func (r UploadRepository) Delete(upload m.ImageUpload) error {
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Delete(&upload).Error; err != nil {
+			return err
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
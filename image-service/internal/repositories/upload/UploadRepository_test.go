@@ -0,0 +1,233 @@
+package repositories
+
+import (
+	"errors"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	m "image-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	upload m.ImageUpload = m.ImageUpload{
+		ID:         uuid.New(),
+		EntityType: "recipe",
+		EntityID:   uuid.New(),
+		Type:       "image/jpeg",
+		ExpiresAt:  timeFunc().Add(15 * time.Minute),
+	}
+)
+
+func newMockDatabase(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+
+	var mockDB *gorm.DB
+
+	newLogger := logger.New(
+		log.New(os.Stdout, "\r\n", log.LstdFlags), // io writer
+		logger.Config{
+			SlowThreshold:             time.Second, // Slow SQL threshold
+			LogLevel:                  logger.Info, // Log level
+			IgnoreRecordNotFoundError: true,        // Ignore ErrRecordNotFound error for logger
+			Colorful:                  false,       // Disable color
+		},
+	)
+
+	sqlMockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sql mock init failed: %v", err.Error())
+	}
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 sqlMockDB,
+		PreferSimpleProtocol: true,
+	})
+
+	mockDB, err = gorm.Open(dialector, &gorm.Config{
+		NowFunc: timeFunc,
+		Logger:  newLogger,
+	})
+	if err != nil {
+		t.Fatalf("gorm mock init failed: %v", err.Error())
+	}
+
+	return mockDB, mock
+}
+
+func timeFunc() time.Time {
+	time, _ := time.Parse("2006-01-02 15:04", "2023-02-04 18:00")
+	return time
+}
+
+func TestUploadFindAll_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewUploadRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "image_uploads"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "key", "expires_at"}).
+			AddRow(upload.ID, "uploads/"+upload.ID.String(), upload.ExpiresAt))
+
+	result, err := r.FindAll()
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+}
+
+func TestUploadFindAll_NotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewUploadRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "image_uploads"`)).
+		WillReturnRows(&sqlmock.Rows{})
+
+	result, err := r.FindAll()
+
+	assert.EqualError(t, err, "not found")
+	assert.Len(t, result, 0)
+}
+
+func TestUploadFindAll_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewUploadRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "image_uploads"`)).
+		WillReturnError(errors.New("error"))
+
+	result, err := r.FindAll()
+
+	assert.EqualError(t, err, "error")
+	assert.Nil(t, result)
+}
+
+func TestUploadFind_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewUploadRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "image_uploads" WHERE "image_uploads"."id" = $1 ORDER BY "image_uploads"."id" LIMIT $2`)).
+		WithArgs(upload.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "entity_type", "entity_id", "type", "key", "expires_at"}).
+			AddRow(
+				upload.ID,
+				upload.EntityType,
+				upload.EntityID,
+				upload.Type,
+				"uploads/"+upload.ID.String(),
+				upload.ExpiresAt,
+			))
+
+	result, err := r.Find(m.ImageUpload{ID: upload.ID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "uploads/"+upload.ID.String(), result.Key)
+	assert.Equal(t, upload.EntityID, result.EntityID)
+}
+
+func TestUploadFind_NotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewUploadRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "image_uploads" WHERE "image_uploads"."id" = $1 ORDER BY "image_uploads"."id" LIMIT $2`)).
+		WithArgs(upload.ID, 1).
+		WillReturnRows(&sqlmock.Rows{})
+
+	result, err := r.Find(m.ImageUpload{ID: upload.ID})
+
+	assert.EqualError(t, err, "not found")
+	assert.Equal(t, m.ImageUpload{}, result)
+}
+
+func TestUploadFind_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewUploadRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "image_uploads" WHERE "image_uploads"."id" = $1 ORDER BY "image_uploads"."id" LIMIT $2`)).
+		WithArgs(upload.ID, 1).
+		WillReturnError(errors.New("error"))
+
+	result, err := r.Find(m.ImageUpload{ID: upload.ID})
+
+	assert.EqualError(t, err, "error")
+	assert.Equal(t, m.ImageUpload{}, result)
+}
+
+func TestUploadCreate_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewUploadRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "image_uploads" ("id","entity_type","entity_id","type","key","expires_at","created_at") VALUES ($1,$2,$3,$4,$5,$6,$7)`)).
+		WithArgs(
+			upload.ID,
+			upload.EntityType,
+			upload.EntityID,
+			upload.Type,
+			upload.Key,
+			upload.ExpiresAt,
+			sqlmock.AnyArg(),
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	result, err := r.Create(upload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, upload.ID, result.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUploadCreate_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewUploadRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "image_uploads" ("id","entity_type","entity_id","type","key","expires_at","created_at") VALUES ($1,$2,$3,$4,$5,$6,$7)`)).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	result, err := r.Create(upload)
+
+	assert.EqualError(t, err, "error")
+	assert.Equal(t, m.ImageUpload{}, result)
+}
+
+func TestUploadDelete_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewUploadRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "image_uploads" WHERE "image_uploads"."id" = $1`)).
+		WithArgs(upload.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := r.Delete(upload)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUploadDelete_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewUploadRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "image_uploads" WHERE "image_uploads"."id" = $1`)).
+		WithArgs(upload.ID).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	err := r.Delete(upload)
+
+	assert.EqualError(t, err, "error")
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EntityImageRepository interface {
+	DeleteByEntity(entityType string, entityID uuid.UUID) error
+	RestoreByEntity(entityType string, entityID uuid.UUID, since time.Time) error
+}
+
+// EntityImageService deletes and restores all images of an entity at once, for services deleting an entity along
+// with everything linked to it. The stored variants of deleted images are kept by the garbage collection for the
+// grace period, so only images deleted within the grace period can be restored
+type EntityImageService struct {
+	imageRepo   EntityImageRepository
+	gracePeriod time.Duration
+	logger      LoggerInterface
+}
+
+func NewEntityImageService(imageRepo EntityImageRepository, gracePeriod time.Duration, logger LoggerInterface) *EntityImageService {
+	return &EntityImageService{
+		imageRepo:   imageRepo,
+		gracePeriod: gracePeriod,
+		logger:      logger,
+	}
+}
+
+// DeleteByEntity deletes the images of the entity. An entity without images is not an error, so the call can be
+// repeated safely
+func (s EntityImageService) DeleteByEntity(entityType string, entityID uuid.UUID) error {
+
+	if err := s.imageRepo.DeleteByEntity(entityType, entityID); err != nil {
+		s.logger.Errorf("error deleting the images of %s %s: %s", entityType, entityID, err.Error())
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
+
+// RestoreByEntity restores the images of the entity deleted since the given time, but no further back than the
+// grace period
+func (s EntityImageService) RestoreByEntity(entityType string, entityID uuid.UUID, since time.Time) error {
+
+	if cutoff := time.Now().Add(-s.gracePeriod); since.Before(cutoff) {
+		since = cutoff
+	}
+
+	if err := s.imageRepo.RestoreByEntity(entityType, entityID, since); err != nil {
+		s.logger.Errorf("error restoring the images of %s %s: %s", entityType, entityID, err.Error())
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	entityFound uuid.UUID = uuid.New()
+	entityError uuid.UUID = uuid.New()
+
+	restoredSince time.Time
+)
+
+type EntityImageRepositoryMock struct{}
+
+func (EntityImageRepositoryMock) DeleteByEntity(entityType string, entityID uuid.UUID) error {
+	if entityID == entityError {
+		return errors.New("error")
+	}
+	return nil
+}
+
+func (EntityImageRepositoryMock) RestoreByEntity(entityType string, entityID uuid.UUID, since time.Time) error {
+	restoredSince = since
+
+	if entityID == entityError {
+		return errors.New("error")
+	}
+	return nil
+}
+
+func newEntityImageService() *EntityImageService {
+	restoredSince = time.Time{}
+	return NewEntityImageService(&EntityImageRepositoryMock{}, 24*time.Hour, &LoggerInterfaceMock{})
+}
+
+func TestEntityImageDelete_OK(t *testing.T) {
+	s := newEntityImageService()
+
+	err := s.DeleteByEntity("recipe", entityFound)
+
+	assert.NoError(t, err)
+}
+
+func TestEntityImageDelete_Err(t *testing.T) {
+	s := newEntityImageService()
+
+	err := s.DeleteByEntity("recipe", entityError)
+
+	assert.EqualError(t, err, "internal server error")
+}
+
+func TestEntityImageRestore_OK(t *testing.T) {
+	s := newEntityImageService()
+	since := time.Now().Add(-time.Hour)
+
+	err := s.RestoreByEntity("recipe", entityFound, since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, since, restoredSince)
+}
+
+func TestEntityImageRestore_BeyondGracePeriod(t *testing.T) {
+	s := newEntityImageService()
+
+	err := s.RestoreByEntity("recipe", entityFound, time.Time{})
+
+	// the variants of images deleted before the grace period may be gone already
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(-24*time.Hour), restoredSince, time.Minute)
+}
+
+func TestEntityImageRestore_Err(t *testing.T) {
+	s := newEntityImageService()
+
+	err := s.RestoreByEntity("recipe", entityError, time.Now())
+
+	assert.EqualError(t, err, "internal server error")
+}
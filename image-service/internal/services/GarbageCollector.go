@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"errors"
+	m "image-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ObjectStore lists and removes the objects kept in storage
+type ObjectStore interface {
+	ListObjects(prefix string) ([]m.StoredObject, error)
+	DeleteObject(key string) error
+}
+
+type GarbageImageRepository interface {
+	FindAll() ([]m.Image, error)
+	FindDeleted(since time.Time) ([]m.Image, error)
+	Delete(img m.Image) error
+}
+
+type GarbageUploadRepository interface {
+	FindAll() ([]m.ImageUpload, error)
+	Delete(upload m.ImageUpload) error
+}
+
+// EntityResolver checks whether the entity an image belongs to still exists
+type EntityResolver interface {
+	Exists(ctx context.Context, entityType string, entityID uuid.UUID) (bool, error)
+}
+
+// GarbageCollector removes images whose entity doesn't exist anymore and objects in storage no image or pending
+// upload refers to. Only images and objects older than the grace period are considered, so uploads that are still
+// being linked to their entity are left alone
+type GarbageCollector struct {
+	store       ObjectStore
+	imageRepo   GarbageImageRepository
+	uploadRepo  GarbageUploadRepository
+	entities    EntityResolver
+	gracePeriod time.Duration
+	logger      LoggerInterface
+}
+
+type entity struct {
+	entityType string
+	entityID   uuid.UUID
+}
+
+func NewGarbageCollector(store ObjectStore, imageRepo GarbageImageRepository, uploadRepo GarbageUploadRepository, entities EntityResolver, gracePeriod time.Duration, logger LoggerInterface) *GarbageCollector {
+	return &GarbageCollector{
+		store:       store,
+		imageRepo:   imageRepo,
+		uploadRepo:  uploadRepo,
+		entities:    entities,
+		gracePeriod: gracePeriod,
+		logger:      logger,
+	}
+}
+
+// Run collects the garbage every interval until the context is cancelled
+func (g GarbageCollector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := g.Collect(ctx)
+			if err != nil {
+				continue
+			}
+
+			g.logger.Infof("garbage collection removed %d images, %d uploads and %d objects freeing %d bytes",
+				len(report.Images), len(report.Uploads), len(report.Objects), report.Size)
+		}
+	}
+}
+
+// Report returns what a garbage collection would remove, without removing anything
+func (g GarbageCollector) Report(ctx context.Context) (m.GarbageReportDTO, error) {
+	report, _, _, err := g.find(ctx)
+
+	return report, err
+}
+
+// Collect removes the garbage and returns what was removed
+func (g GarbageCollector) Collect(ctx context.Context) (m.GarbageReportDTO, error) {
+
+	report, images, uploads, err := g.find(ctx)
+	if err != nil {
+		return m.GarbageReportDTO{}, err
+	}
+	report.DryRun = false
+
+	// the rows are removed first. The objects are only unreferenced once they are gone
+	for _, image := range images {
+		if err := g.imageRepo.Delete(image); err != nil {
+			g.logger.Errorf("error deleting orphaned image %s: %s", image.ID, err.Error())
+			return m.GarbageReportDTO{}, errors.New("internal server error")
+		}
+	}
+
+	for _, upload := range uploads {
+		if err := g.uploadRepo.Delete(upload); err != nil {
+			g.logger.Errorf("error deleting expired upload %s: %s", upload.ID, err.Error())
+			return m.GarbageReportDTO{}, errors.New("internal server error")
+		}
+	}
+
+	for _, object := range report.Objects {
+		if err := g.store.DeleteObject(object.Key); err != nil {
+			g.logger.Errorf("error deleting orphaned object %s: %s", object.Key, err.Error())
+			return m.GarbageReportDTO{}, errors.New("internal server error")
+		}
+	}
+
+	return report, nil
+}
+
+// find determines the orphaned images, the expired uploads and the objects left without reference once both
+// are removed
+func (g GarbageCollector) find(ctx context.Context) (m.GarbageReportDTO, []m.Image, []m.ImageUpload, error) {
+	var orphanedImages []m.Image
+	var expiredUploads []m.ImageUpload
+
+	cutoff := time.Now().Add(-g.gracePeriod)
+	report := m.GarbageReportDTO{
+		DryRun:      true,
+		GracePeriod: g.gracePeriod.String(),
+		Images:      []m.OrphanedImageDTO{},
+		Objects:     []m.StoredObject{},
+		Uploads:     []uuid.UUID{},
+	}
+
+	images, err := g.imageRepo.FindAll()
+	if err != nil && err.Error() != "not found" {
+		g.logger.Errorf("error finding images: %s", err.Error())
+		return m.GarbageReportDTO{}, nil, nil, errors.New("internal server error")
+	}
+
+	// images deleted within the grace period may still be restored, their objects are kept until it expires
+	deletedImages, err := g.imageRepo.FindDeleted(cutoff)
+	if err != nil && err.Error() != "not found" {
+		g.logger.Errorf("error finding deleted images: %s", err.Error())
+		return m.GarbageReportDTO{}, nil, nil, errors.New("internal server error")
+	}
+
+	uploads, err := g.uploadRepo.FindAll()
+	if err != nil && err.Error() != "not found" {
+		g.logger.Errorf("error finding uploads: %s", err.Error())
+		return m.GarbageReportDTO{}, nil, nil, errors.New("internal server error")
+	}
+
+	objects, err := g.store.ListObjects("")
+	if err != nil {
+		g.logger.Errorf("error listing objects: %s", err.Error())
+		return m.GarbageReportDTO{}, nil, nil, errors.New("internal server error")
+	}
+
+	referenced := map[string]bool{}
+	resolved := map[entity]bool{}
+
+	for _, image := range images {
+		if image.CreatedAt.Before(cutoff) && !g.exists(ctx, image, resolved) {
+			orphanedImages = append(orphanedImages, image)
+			report.Images = append(report.Images, m.OrphanedImageDTO{
+				ID:         image.ID,
+				EntityType: image.EntityType,
+				EntityID:   image.EntityID,
+				CreatedAt:  image.CreatedAt,
+			})
+			continue
+		}
+
+		for _, variant := range image.Variants {
+			referenced[variant.Key] = true
+		}
+	}
+
+	for _, image := range deletedImages {
+		for _, variant := range image.Variants {
+			referenced[variant.Key] = true
+		}
+	}
+
+	for _, upload := range uploads {
+		if upload.ExpiresAt.Before(cutoff) {
+			expiredUploads = append(expiredUploads, upload)
+			report.Uploads = append(report.Uploads, upload.ID)
+			continue
+		}
+
+		referenced[upload.Key] = true
+	}
+
+	for _, object := range objects {
+		if referenced[object.Key] || object.LastModified.After(cutoff) {
+			continue
+		}
+
+		report.Objects = append(report.Objects, object)
+		report.Size += object.Size
+	}
+
+	return report, orphanedImages, expiredUploads, nil
+}
+
+// exists resolves the entity of the image, remembering the result for the other images of the entity. An entity
+// that can't be resolved is assumed to exist, an image is never removed without certainty
+func (g GarbageCollector) exists(ctx context.Context, image m.Image, resolved map[entity]bool) bool {
+	key := entity{image.EntityType, image.EntityID}
+
+	if exists, found := resolved[key]; found {
+		return exists
+	}
+
+	exists, err := g.entities.Exists(ctx, image.EntityType, image.EntityID)
+	if err != nil {
+		g.logger.Errorf("error resolving %s %s of image %s: %s", image.EntityType, image.EntityID, image.ID, err.Error())
+		exists = true
+	}
+
+	resolved[key] = exists
+
+	return exists
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	m "image-service/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	// garbageMode selects the behaviour of the garbage collector mocks
+	garbageMode string
+
+	old           time.Time = time.Now().Add(-48 * time.Hour)
+	liveEntity    uuid.UUID = uuid.New()
+	deletedEntity uuid.UUID = uuid.New()
+
+	liveImage       m.Image = newGarbageImage("recipe", liveEntity, old, "img/live/card.jpg")
+	orphanedImage   m.Image = newGarbageImage("recipe", deletedEntity, old, "img/orphaned/card.jpg")
+	recentImage     m.Image = newGarbageImage("recipe", deletedEntity, time.Now(), "img/recent/card.jpg")
+	unresolvedImage m.Image = newGarbageImage("unknown", uuid.New(), old, "img/unresolved/card.jpg")
+	deletedImage    m.Image = newGarbageImage("recipe", deletedEntity, old, "img/deleted/card.jpg")
+
+	openUpload    m.ImageUpload = m.ImageUpload{ID: uuid.New(), Key: "uploads/pending", ExpiresAt: time.Now().Add(time.Hour)}
+	expiredUpload m.ImageUpload = m.ImageUpload{ID: uuid.New(), Key: "uploads/expired", ExpiresAt: old}
+
+	removedImages []uuid.UUID
+)
+
+type GarbageStoreMock struct{}
+type GarbageImageRepositoryMock struct{}
+type GarbageUploadRepositoryMock struct{}
+type EntityResolverMock struct{}
+
+func (GarbageStoreMock) ListObjects(prefix string) ([]m.StoredObject, error) {
+	switch garbageMode {
+	case "list_err":
+		return nil, errors.New("error")
+	default:
+		return []m.StoredObject{
+			{Key: "img/live/card.jpg", Size: 1, LastModified: old},
+			{Key: "img/orphaned/card.jpg", Size: 2, LastModified: old},
+			{Key: "img/recent/card.jpg", Size: 4, LastModified: old},
+			{Key: "img/unresolved/card.jpg", Size: 8, LastModified: old},
+			{Key: "img/deleted/card.jpg", Size: 256, LastModified: old},
+			{Key: "img/stray/card.jpg", Size: 16, LastModified: old},
+			{Key: "img/fresh/card.jpg", Size: 32, LastModified: time.Now()},
+			{Key: "uploads/pending", Size: 64, LastModified: old},
+			{Key: "uploads/expired", Size: 128, LastModified: old},
+		}, nil
+	}
+}
+
+func (GarbageStoreMock) DeleteObject(key string) error {
+	deletedObjects = append(deletedObjects, key)
+
+	switch garbageMode {
+	case "delete_err":
+		return errors.New("error")
+	default:
+		return nil
+	}
+}
+
+func (GarbageImageRepositoryMock) FindAll() ([]m.Image, error) {
+	switch garbageMode {
+	case "find_err":
+		return nil, errors.New("error")
+	case "empty":
+		return nil, errors.New("not found")
+	default:
+		return []m.Image{liveImage, orphanedImage, recentImage, unresolvedImage}, nil
+	}
+}
+
+func (GarbageImageRepositoryMock) FindDeleted(since time.Time) ([]m.Image, error) {
+	switch garbageMode {
+	case "find_deleted_err":
+		return nil, errors.New("error")
+	case "empty":
+		return nil, errors.New("not found")
+	default:
+		return []m.Image{deletedImage}, nil
+	}
+}
+
+func (GarbageImageRepositoryMock) Delete(img m.Image) error {
+	removedImages = append(removedImages, img.ID)
+
+	switch garbageMode {
+	case "image_delete_err":
+		return errors.New("error")
+	default:
+		return nil
+	}
+}
+
+func (GarbageUploadRepositoryMock) FindAll() ([]m.ImageUpload, error) {
+	switch garbageMode {
+	case "empty":
+		return nil, errors.New("not found")
+	default:
+		return []m.ImageUpload{openUpload, expiredUpload}, nil
+	}
+}
+
+func (GarbageUploadRepositoryMock) Delete(upload m.ImageUpload) error {
+	removedUploads = append(removedUploads, upload.ID)
+	return nil
+}
+
+func (EntityResolverMock) Exists(ctx context.Context, entityType string, entityID uuid.UUID) (bool, error) {
+	switch {
+	case entityType == "recipe" && entityID == liveEntity:
+		return true, nil
+	case entityType == "recipe":
+		return false, nil
+	default:
+		return false, errors.New("unknown entity type " + entityType)
+	}
+}
+
+func newGarbageCollector() *GarbageCollector {
+	deletedObjects = nil
+	removedImages = nil
+	removedUploads = nil
+	return NewGarbageCollector(&GarbageStoreMock{}, &GarbageImageRepositoryMock{}, &GarbageUploadRepositoryMock{}, &EntityResolverMock{}, 24*time.Hour, &LoggerInterfaceMock{})
+}
+
+func TestGarbageReport_OK(t *testing.T) {
+	g := newGarbageCollector()
+	garbageMode = ""
+
+	result, err := g.Report(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, "24h0m0s", result.GracePeriod)
+
+	// recent images, images of entities that can't be resolved and images that may still be restored are kept
+	assert.Len(t, result.Images, 1)
+	assert.Equal(t, orphanedImage.ID, result.Images[0].ID)
+	assert.Equal(t, []uuid.UUID{expiredUpload.ID}, result.Uploads)
+
+	var keys []string
+	for _, object := range result.Objects {
+		keys = append(keys, object.Key)
+	}
+	assert.Equal(t, []string{"img/orphaned/card.jpg", "img/stray/card.jpg", "uploads/expired"}, keys)
+	assert.Equal(t, int64(2+16+128), result.Size)
+
+	// nothing is removed on a dry run
+	assert.Empty(t, deletedObjects)
+	assert.Empty(t, removedImages)
+	assert.Empty(t, removedUploads)
+}
+
+func TestGarbageReport_Empty(t *testing.T) {
+	g := newGarbageCollector()
+	garbageMode = "empty"
+
+	result, err := g.Report(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Images)
+	assert.Empty(t, result.Uploads)
+
+	// without any images or uploads every object past the grace period is garbage
+	assert.Len(t, result.Objects, 8)
+}
+
+func TestGarbageReport_Err(t *testing.T) {
+	g := newGarbageCollector()
+
+	for _, mode := range []string{"find_err", "find_deleted_err", "list_err"} {
+		garbageMode = mode
+
+		_, err := g.Report(context.Background())
+
+		assert.EqualError(t, err, "internal server error", mode)
+	}
+}
+
+func TestGarbageCollect_OK(t *testing.T) {
+	g := newGarbageCollector()
+	garbageMode = ""
+
+	result, err := g.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.False(t, result.DryRun)
+	assert.Equal(t, []uuid.UUID{orphanedImage.ID}, removedImages)
+	assert.Equal(t, []uuid.UUID{expiredUpload.ID}, removedUploads)
+	assert.Equal(t, []string{"img/orphaned/card.jpg", "img/stray/card.jpg", "uploads/expired"}, deletedObjects)
+}
+
+func TestGarbageCollect_Err(t *testing.T) {
+	g := newGarbageCollector()
+
+	garbageMode = "image_delete_err"
+	_, err := g.Collect(context.Background())
+	assert.EqualError(t, err, "internal server error")
+
+	// the objects are kept as long as the image still refers to them
+	assert.Empty(t, deletedObjects)
+
+	garbageMode = "delete_err"
+	_, err = g.Collect(context.Background())
+	assert.EqualError(t, err, "internal server error")
+}
+
+func TestGarbageRun(t *testing.T) {
+	g := newGarbageCollector()
+	garbageMode = ""
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		g.Run(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+
+	assert.NotEmpty(t, removedImages)
+}
+
+// ====== Helpers ======
+
+func newGarbageImage(entityType string, entityID uuid.UUID, createdAt time.Time, key string) m.Image {
+	id := uuid.New()
+
+	return m.Image{
+		ID:         id,
+		EntityType: entityType,
+		EntityID:   entityID,
+		CreatedAt:  createdAt,
+		Variants:   []m.ImageVariant{{ImageID: id, Name: "card", Format: "jpeg", Key: key}},
+	}
+}
@@ -1,38 +1,162 @@
 package services
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	m "image-service/internal/models"
+	"io"
+	"time"
 
 	"github.com/google/uuid"
 )
 
-type S3Repository interface {
-	UploadImage(img m.Image) error
+// BlobStore is the object storage the image files are kept in
+type BlobStore interface {
+	UploadObject(key string, body io.ReadSeeker, contentType string) error
+	DownloadObject(key string) (io.ReadCloser, error)
+	DeleteObject(key string) error
+	ObjectURL(key string) string
+	UploadURL(key string, contentType string) (string, error)
 }
 
 type ImageRepository interface {
+	FindByEntity(entityType string, entityID uuid.UUID) ([]m.Image, error)
+	Find(img m.Image) (m.Image, error)
+	FindByHash(hash string) (m.Image, error)
+	Create(img m.Image) (m.Image, error)
+	Delete(img m.Image) error
+}
+
+type UploadRepository interface {
+	Find(upload m.ImageUpload) (m.ImageUpload, error)
+	Create(upload m.ImageUpload) (m.ImageUpload, error)
+	Delete(upload m.ImageUpload) error
+}
+
+type ImageProcessor interface {
+	Process(file io.Reader) ([]m.ImageVariant, error)
+}
+
+type ImageValidator interface {
+	Read(file io.Reader) ([]byte, error)
+	Accepts(contentType string) bool
+	Validate(data []byte) (string, error)
 }
 
 type LoggerInterface interface {
+	Infof(format string, args ...interface{})
 	Errorf(format string, args ...interface{})
 }
 
 type ImageService struct {
-	repo   S3Repository
-	logger LoggerInterface
+	store        BlobStore
+	imageRepo    ImageRepository
+	uploadRepo   UploadRepository
+	processor    ImageProcessor
+	validator    ImageValidator
+	uploadExpiry time.Duration
+	logger       LoggerInterface
 }
 
-func NewImageService(repo S3Repository, logger LoggerInterface) *ImageService {
+func NewImageService(store BlobStore, imageRepo ImageRepository, uploadRepo UploadRepository, processor ImageProcessor, validator ImageValidator, uploadExpiry time.Duration, logger LoggerInterface) *ImageService {
 	return &ImageService{
-		repo:   repo,
-		logger: logger,
+		store:        store,
+		imageRepo:    imageRepo,
+		uploadRepo:   uploadRepo,
+		processor:    processor,
+		validator:    validator,
+		uploadExpiry: uploadExpiry,
+		logger:       logger,
+	}
+}
+
+func (s ImageService) FindByEntity(entityType string, entityID uuid.UUID) ([]m.ImageDTO, error) {
+
+	images, err := s.imageRepo.FindByEntity(entityType, entityID)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return nil, err
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	imageDTOs := make([]m.ImageDTO, len(images))
+	for i, image := range images {
+		imageDTOs[i] = s.convertToDTO(image)
+	}
+
+	return imageDTOs, nil
+}
+
+func (s ImageService) FindSingle(imageDTO m.ImageDTO) (m.ImageDTO, error) {
+
+	image, err := s.imageRepo.Find(imageDTO.ConvertFromDTO())
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return m.ImageDTO{}, err
+		default:
+			return m.ImageDTO{}, errors.New("internal server error")
+		}
+	}
+
+	return s.convertToDTO(image), nil
+}
+
+// Download returns the requested variant of the image together with its contents. An empty name or format selects
+// the largest variant and JPEG respectively. The caller is responsible for closing the reader
+func (s ImageService) Download(imageDTO m.ImageDTO, name string, format string) (m.ImageVariantDTO, io.ReadCloser, error) {
+
+	image, err := s.imageRepo.Find(imageDTO.ConvertFromDTO())
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return m.ImageVariantDTO{}, nil, err
+		default:
+			return m.ImageVariantDTO{}, nil, errors.New("internal server error")
+		}
+	}
+
+	variant, found := findVariant(image.Variants, name, format)
+	if !found {
+		return m.ImageVariantDTO{}, nil, errors.New("not found")
 	}
+
+	file, err := s.store.DownloadObject(variant.Key)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return m.ImageVariantDTO{}, nil, err
+		default:
+			s.logger.Errorf("error downloading image %s: %s", image.ID, err.Error())
+			return m.ImageVariantDTO{}, nil, errors.New("internal server error")
+		}
+	}
+
+	return s.convertVariantToDTO(variant), file, nil
 }
 
 func (s ImageService) Create(imageDTO m.ImageDTO) (m.ImageDTO, error) {
 	var image m.Image = imageDTO.ConvertFromDTO()
 	var err error
 
+	if image.EntityType == "" {
+		return m.ImageDTO{}, errors.New("invalid entity type")
+	}
+
+	if image.EntityID == uuid.Nil {
+		return m.ImageDTO{}, errors.New("invalid entity ID")
+	}
+
+	if image.File == nil {
+		return m.ImageDTO{}, errors.New("no file uploaded")
+	}
+
 	// generate the image ID we will use to identify the file in storage
 	image.ID, err = uuid.NewRandom()
 	if err != nil {
@@ -40,9 +164,288 @@ func (s ImageService) Create(imageDTO m.ImageDTO) (m.ImageDTO, error) {
 		return m.ImageDTO{}, err
 	}
 
-	if err := s.repo.UploadImage(image); err != nil {
+	return s.createImage(image, image.File)
+}
+
+// RequestUpload hands out an upload slot the client uploads the file to directly. The file only becomes an image
+// once the upload is confirmed
+func (s ImageService) RequestUpload(uploadDTO m.ImageUploadDTO) (m.ImageUploadDTO, error) {
+	var upload m.ImageUpload = uploadDTO.ConvertFromDTO()
+	var err error
+
+	if upload.EntityType == "" {
+		return m.ImageUploadDTO{}, errors.New("invalid entity type")
+	}
+
+	if upload.EntityID == uuid.Nil {
+		return m.ImageUploadDTO{}, errors.New("invalid entity ID")
+	}
+
+	if upload.Type == "" {
+		return m.ImageUploadDTO{}, errors.New("invalid content type")
+	}
+
+	if !s.validator.Accepts(upload.Type) {
+		return m.ImageUploadDTO{}, errors.New("unsupported image format")
+	}
+
+	// the upload ID becomes the image ID once confirmed
+	upload.ID, err = uuid.NewRandom()
+	if err != nil {
+		s.logger.Errorf("error generating uuid %s", err.Error())
+		return m.ImageUploadDTO{}, err
+	}
+
+	upload.Key = uploadKey(upload)
+	upload.ExpiresAt = time.Now().Add(s.uploadExpiry)
+
+	uploadURL, err := s.store.UploadURL(upload.Key, upload.Type)
+	if err != nil {
+		s.logger.Errorf("error signing upload %s: %s", upload.ID, err.Error())
+		return m.ImageUploadDTO{}, errors.New("internal server error")
+	}
+
+	upload, err = s.uploadRepo.Create(upload)
+	if err != nil {
+		s.logger.Errorf("error storing upload %s: %s", upload.ID, err.Error())
+		return m.ImageUploadDTO{}, errors.New("internal server error")
+	}
+
+	uploadDTO = upload.ConvertToDTO()
+	uploadDTO.UploadURL = uploadURL
+
+	return uploadDTO, nil
+}
+
+// ConfirmUpload processes the file uploaded to the upload slot into an image. The original upload is removed
+// afterwards, only the processed variants are kept
+func (s ImageService) ConfirmUpload(uploadDTO m.ImageUploadDTO) (m.ImageDTO, error) {
+
+	upload, err := s.uploadRepo.Find(uploadDTO.ConvertFromDTO())
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return m.ImageDTO{}, err
+		default:
+			return m.ImageDTO{}, errors.New("internal server error")
+		}
+	}
+
+	if time.Now().After(upload.ExpiresAt) {
+		s.removeUpload(upload)
+		return m.ImageDTO{}, errors.New("upload expired")
+	}
+
+	file, err := s.store.DownloadObject(upload.Key)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return m.ImageDTO{}, errors.New("file not uploaded")
+		default:
+			s.logger.Errorf("error downloading upload %s: %s", upload.ID, err.Error())
+			return m.ImageDTO{}, errors.New("internal server error")
+		}
+	}
+	defer file.Close()
+
+	imageDTO, err := s.createImage(m.Image{
+		ID:         upload.ID,
+		EntityType: upload.EntityType,
+		EntityID:   upload.EntityID,
+		Type:       upload.Type,
+	}, file)
+	if err != nil {
+		return m.ImageDTO{}, err
+	}
+
+	s.removeUpload(upload)
+
+	return imageDTO, nil
+}
+
+func (s ImageService) Delete(imageDTO m.ImageDTO) error {
+
+	image, err := s.imageRepo.Find(imageDTO.ConvertFromDTO())
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return err
+		default:
+			return errors.New("internal server error")
+		}
+	}
+
+	// only the row is removed. The stored variants may be shared with other images of the same contents, including
+	// deleted ones that can still be restored, and an upload of the same contents may be reusing them right now. The
+	// garbage collector removes them once no image refers to them anymore and the grace period has passed
+	if err := s.imageRepo.Delete(image); err != nil {
+		s.logger.Errorf("error deleting image %s: %s", image.ID, err.Error())
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
+
+// createImage stores the image with the variants of the file. The variants are stored under the hash of the file,
+// so uploading the same file again reuses the variants already in storage
+func (s ImageService) createImage(image m.Image, file io.Reader) (m.ImageDTO, error) {
+
+	data, err := s.validator.Read(file)
+	if err != nil {
+		switch err.Error() {
+		case "file too large":
+			return m.ImageDTO{}, err
+		default:
+			s.logger.Errorf("error reading image %s: %s", image.ID, err.Error())
+			return m.ImageDTO{}, errors.New("internal server error")
+		}
+	}
+
+	// the type claimed by the client is replaced by the one determined from the contents
+	image.Type, err = s.validator.Validate(data)
+	if err != nil {
 		return m.ImageDTO{}, err
 	}
 
-	return image.ConvertToDTO(), nil
+	hash := sha256.Sum256(data)
+	image.Hash = hex.EncodeToString(hash[:])
+	image.Size = int64(len(data))
+
+	existing, err := s.imageRepo.FindByHash(image.Hash)
+	if err != nil && err.Error() != "not found" {
+		s.logger.Errorf("error looking up image %s: %s", image.Hash, err.Error())
+		return m.ImageDTO{}, errors.New("internal server error")
+	}
+
+	var uploaded []m.ImageVariant
+
+	if err == nil {
+		image.Variants = reuseVariants(image.ID, existing.Variants)
+	} else {
+		image.Variants, err = s.processor.Process(bytes.NewReader(data))
+		if err != nil {
+			switch err.Error() {
+			case "unsupported image format":
+				return m.ImageDTO{}, err
+			default:
+				s.logger.Errorf("error processing image %s: %s", image.ID, err.Error())
+				return m.ImageDTO{}, errors.New("internal server error")
+			}
+		}
+
+		for i := range image.Variants {
+			image.Variants[i].ImageID = image.ID
+			image.Variants[i].Key = variantKey(image.Hash, image.Variants[i])
+
+			if err := s.store.UploadObject(image.Variants[i].Key, bytes.NewReader(image.Variants[i].Data), image.Variants[i].ContentType()); err != nil {
+				s.logger.Errorf("error uploading image %s: %s", image.ID, err.Error())
+				s.deleteObjects(image.Variants[:i])
+				return m.ImageDTO{}, errors.New("internal server error")
+			}
+		}
+
+		uploaded = image.Variants
+	}
+
+	created, err := s.imageRepo.Create(image)
+	if err != nil {
+		s.logger.Errorf("error storing image %s: %s", image.ID, err.Error())
+
+		// remove the uploaded objects again so storage doesn't fill up with files nobody refers to
+		s.deleteObjects(uploaded)
+
+		return m.ImageDTO{}, errors.New("internal server error")
+	}
+
+	return s.convertToDTO(created), nil
+}
+
+// removeUpload deletes the uploaded file and the upload slot. Failures are only logged, the upload is of no use
+// anymore either way
+func (s ImageService) removeUpload(upload m.ImageUpload) {
+	if err := s.store.DeleteObject(upload.Key); err != nil {
+		s.logger.Errorf("error removing upload %s from storage: %s", upload.ID, err.Error())
+	}
+
+	if err := s.uploadRepo.Delete(upload); err != nil {
+		s.logger.Errorf("error removing upload %s: %s", upload.ID, err.Error())
+	}
+}
+
+func (s ImageService) deleteObjects(variants []m.ImageVariant) {
+	for _, variant := range variants {
+		if err := s.store.DeleteObject(variant.Key); err != nil {
+			s.logger.Errorf("error removing uploaded image %s: %s", variant.Key, err.Error())
+		}
+	}
+}
+
+func (s ImageService) convertToDTO(image m.Image) m.ImageDTO {
+	imageDTO := image.ConvertToDTO()
+
+	for i, variant := range image.Variants {
+		imageDTO.Variants[i].URL = s.store.ObjectURL(variant.Key)
+	}
+
+	if variant, found := findVariant(image.Variants, "", ""); found {
+		imageDTO.URL = s.store.ObjectURL(variant.Key)
+	}
+
+	return imageDTO
+}
+
+func (s ImageService) convertVariantToDTO(variant m.ImageVariant) m.ImageVariantDTO {
+	variantDTO := variant.ConvertToDTO()
+	variantDTO.URL = s.store.ObjectURL(variant.Key)
+
+	return variantDTO
+}
+
+// findVariant looks up the variant by name and format. Without a name the largest variant is returned,
+// without a format the JPEG rendition
+func findVariant(variants []m.ImageVariant, name string, format string) (m.ImageVariant, bool) {
+	var result m.ImageVariant
+	var found bool
+
+	if format == "" {
+		format = "jpeg"
+	}
+
+	for _, variant := range variants {
+		if variant.Format != format || (name != "" && variant.Name != name) {
+			continue
+		}
+
+		if !found || variant.Width*variant.Height > result.Width*result.Height {
+			result = variant
+			found = true
+		}
+	}
+
+	return result, found
+}
+
+// reuseVariants links the stored variants of another image with the same contents to the image
+func reuseVariants(imageID uuid.UUID, variants []m.ImageVariant) []m.ImageVariant {
+	result := make([]m.ImageVariant, len(variants))
+
+	for i, variant := range variants {
+		result[i] = variant
+		result[i].ImageID = imageID
+	}
+
+	return result
+}
+
+func variantKey(hash string, variant m.ImageVariant) string {
+	extension := variant.Format
+	if variant.Format == "jpeg" {
+		extension = "jpg"
+	}
+
+	return fmt.Sprintf("img/%s/%s.%s", hash, variant.Name, extension)
+}
+
+func uploadKey(upload m.ImageUpload) string {
+	return fmt.Sprintf("uploads/%s", upload.ID)
 }
@@ -4,52 +4,653 @@ import (
 	"errors"
 	"image"
 	m "image-service/internal/models"
+	pr "image-service/internal/processing"
+	fr "image-service/internal/repositories/filesystem"
 	"image/color"
 	"image/png"
 	"io"
 	"mime/multipart"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
 var (
 	imageDTO m.ImageDTO
+
+	imageID     uuid.UUID = uuid.New()
+	storedImage m.Image   = m.Image{
+		ID:         imageID,
+		EntityType: "recipe",
+		EntityID:   uuid.New(),
+		Size:       1,
+		Type:       "image/png",
+		Hash:       "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		Variants: []m.ImageVariant{
+			{ImageID: imageID, Name: "thumbnail", Format: "jpeg", Width: 200, Height: 150, Key: "img/" + imageID.String() + "/thumbnail.jpg"},
+			{ImageID: imageID, Name: "card", Format: "jpeg", Width: 800, Height: 600, Key: "img/" + imageID.String() + "/card.jpg"},
+			{ImageID: imageID, Name: "card", Format: "webp", Width: 800, Height: 600, Key: "img/" + imageID.String() + "/card.webp"},
+		},
+	}
+
+	uploadID      uuid.UUID     = uuid.New()
+	pendingUpload m.ImageUpload = m.ImageUpload{
+		ID:         uploadID,
+		EntityType: "recipe",
+		EntityID:   uuid.New(),
+		Type:       "image/png",
+		Key:        "uploads/" + uploadID.String(),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+
+	uploadedObjects []string
+	deletedObjects  []string
+	removedUploads  []uuid.UUID
 )
 
-type S3RepositoryMock struct{}
+type BlobStoreMock struct{}
+type ImageRepositoryMock struct{}
+type UploadRepositoryMock struct{}
+type ImageProcessorMock struct{}
+type ImageValidatorMock struct{}
 type LoggerInterfaceMock struct{}
 
-func (S3RepositoryMock) UploadImage(img m.Image) error {
+func (BlobStoreMock) UploadObject(key string, body io.ReadSeeker, contentType string) error {
+	switch imageDTO.EntityType {
+	case "create", "create_dberr", "confirm":
+		uploadedObjects = append(uploadedObjects, key)
+		return nil
+	case "create_s3err":
+		if len(uploadedObjects) > 0 {
+			return errors.New("error")
+		}
+		uploadedObjects = append(uploadedObjects, key)
+		return nil
+	default:
+		return errors.New("error")
+	}
+}
+
+func (BlobStoreMock) DownloadObject(key string) (io.ReadCloser, error) {
+	switch imageDTO.EntityType {
+	case "download", "confirm", "confirm_unsupported":
+		return io.NopCloser(strings.NewReader(key)), nil
+	case "download_notfound", "confirm_missing":
+		return nil, errors.New("not found")
+	default:
+		return nil, errors.New("error")
+	}
+}
+
+func (BlobStoreMock) DeleteObject(key string) error {
+	deletedObjects = append(deletedObjects, key)
+
+	switch imageDTO.EntityType {
+	case "delete", "delete_dberr", "create_dberr", "create_s3err", "confirm", "confirm_expired":
+		return nil
+	default:
+		return errors.New("error")
+	}
+}
+
+func (BlobStoreMock) ObjectURL(key string) string {
+	return "https://cdn.example.com/" + key
+}
+
+func (BlobStoreMock) UploadURL(key string, contentType string) (string, error) {
 	switch imageDTO.EntityType {
-	case "create":
+	case "upload", "upload_dberr":
+		return "https://cdn.example.com/" + key + "?signature=signature", nil
+	default:
+		return "", errors.New("error")
+	}
+}
+
+func (ImageRepositoryMock) FindByEntity(entityType string, entityID uuid.UUID) ([]m.Image, error) {
+	switch imageDTO.EntityType {
+	case "find":
+		return []m.Image{storedImage}, nil
+	case "notfound":
+		return nil, errors.New("not found")
+	default:
+		return nil, errors.New("error")
+	}
+}
+
+func (ImageRepositoryMock) Find(img m.Image) (m.Image, error) {
+	switch imageDTO.EntityType {
+	case "find", "download", "download_notfound", "download_err", "delete", "delete_dberr":
+		return storedImage, nil
+	case "notfound":
+		return m.Image{}, errors.New("not found")
+	default:
+		return m.Image{}, errors.New("error")
+	}
+}
+
+func (ImageRepositoryMock) FindByHash(hash string) (m.Image, error) {
+	switch imageDTO.EntityType {
+	case "create_duplicate":
+		return storedImage, nil
+	case "create_hasherr":
+		return m.Image{}, errors.New("error")
+	default:
+		return m.Image{}, errors.New("not found")
+	}
+}
+
+func (ImageRepositoryMock) Create(img m.Image) (m.Image, error) {
+	switch imageDTO.EntityType {
+	case "create", "create_duplicate", "confirm":
+		return img, nil
+	default:
+		return m.Image{}, errors.New("error")
+	}
+}
+
+func (ImageRepositoryMock) Delete(img m.Image) error {
+	switch imageDTO.EntityType {
+	case "delete":
 		return nil
 	default:
 		return errors.New("error")
 	}
 }
 
+func (UploadRepositoryMock) Find(upload m.ImageUpload) (m.ImageUpload, error) {
+	switch imageDTO.EntityType {
+	case "confirm", "confirm_missing", "confirm_unsupported":
+		return pendingUpload, nil
+	case "confirm_expired":
+		expired := pendingUpload
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		return expired, nil
+	case "notfound":
+		return m.ImageUpload{}, errors.New("not found")
+	default:
+		return m.ImageUpload{}, errors.New("error")
+	}
+}
+
+func (UploadRepositoryMock) Create(upload m.ImageUpload) (m.ImageUpload, error) {
+	switch imageDTO.EntityType {
+	case "upload":
+		return upload, nil
+	default:
+		return m.ImageUpload{}, errors.New("error")
+	}
+}
+
+func (UploadRepositoryMock) Delete(upload m.ImageUpload) error {
+	removedUploads = append(removedUploads, upload.ID)
+	return nil
+}
+
+func (ImageProcessorMock) Process(file io.Reader) ([]m.ImageVariant, error) {
+	switch imageDTO.EntityType {
+	case "unsupported", "confirm_unsupported":
+		return nil, errors.New("unsupported image format")
+	case "process_err":
+		return nil, errors.New("error")
+	default:
+		return []m.ImageVariant{
+			{Name: "card", Format: "jpeg", Width: 800, Height: 600, Data: []byte("jpeg")},
+			{Name: "card", Format: "webp", Width: 800, Height: 600, Data: []byte("webp")},
+		}, nil
+	}
+}
+
+func (ImageValidatorMock) Read(file io.Reader) ([]byte, error) {
+	switch imageDTO.EntityType {
+	case "create_toolarge":
+		return nil, errors.New("file too large")
+	default:
+		return io.ReadAll(file)
+	}
+}
+
+func (ImageValidatorMock) Accepts(contentType string) bool {
+	return contentType != "image/heic"
+}
+
+func (ImageValidatorMock) Validate(data []byte) (string, error) {
+	switch imageDTO.EntityType {
+	case "create_bomb":
+		return "", errors.New("decompression bomb detected")
+	default:
+		return "image/png", nil
+	}
+}
+
+func (LoggerInterfaceMock) Infof(format string, args ...interface{})  {}
 func (LoggerInterfaceMock) Errorf(format string, args ...interface{}) {}
 
+func newImageService() *ImageService {
+	uploadedObjects = nil
+	deletedObjects = nil
+	removedUploads = nil
+	return NewImageService(&BlobStoreMock{}, &ImageRepositoryMock{}, &UploadRepositoryMock{}, &ImageProcessorMock{}, &ImageValidatorMock{}, time.Hour, &LoggerInterfaceMock{})
+}
+
+func TestFindByEntity_OK(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "find"}
+
+	result, err := s.FindByEntity(storedImage.EntityType, storedImage.EntityID)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, storedImage.ID, result[0].ID)
+	assert.Equal(t, "https://cdn.example.com/img/"+imageID.String()+"/card.jpg", result[0].URL)
+	assert.Len(t, result[0].Variants, 3)
+	assert.Equal(t, "https://cdn.example.com/img/"+imageID.String()+"/thumbnail.jpg", result[0].Variants[0].URL)
+}
+
+func TestFindByEntity_NotFound(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "notfound"}
+
+	result, err := s.FindByEntity(storedImage.EntityType, storedImage.EntityID)
+
+	assert.EqualError(t, err, "not found")
+	assert.Nil(t, result)
+}
+
+func TestFindByEntity_Err(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "error"}
+
+	result, err := s.FindByEntity(storedImage.EntityType, storedImage.EntityID)
+
+	assert.EqualError(t, err, "internal server error")
+	assert.Nil(t, result)
+}
+
+func TestFindSingle_OK(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "find"}
+
+	result, err := s.FindSingle(m.ImageDTO{ID: storedImage.ID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, storedImage.EntityID, result.EntityID)
+	assert.NotEmpty(t, result.URL)
+}
+
+func TestFindSingle_NotFound(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "notfound"}
+
+	result, err := s.FindSingle(m.ImageDTO{ID: storedImage.ID})
+
+	assert.EqualError(t, err, "not found")
+	assert.Equal(t, m.ImageDTO{}, result)
+}
+
+func TestFindSingle_Err(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "error"}
+
+	result, err := s.FindSingle(m.ImageDTO{ID: storedImage.ID})
+
+	assert.EqualError(t, err, "internal server error")
+	assert.Equal(t, m.ImageDTO{}, result)
+}
+
+func TestDownload_OK(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "download"}
+
+	for _, tc := range []struct {
+		name   string
+		format string
+		key    string
+	}{
+		{"", "", "card.jpg"},
+		{"thumbnail", "", "thumbnail.jpg"},
+		{"card", "webp", "card.webp"},
+	} {
+		result, file, err := s.Download(m.ImageDTO{ID: storedImage.ID}, tc.name, tc.format)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://cdn.example.com/img/"+imageID.String()+"/"+tc.key, result.URL)
+
+		body, _ := io.ReadAll(file)
+		assert.Equal(t, "img/"+imageID.String()+"/"+tc.key, string(body))
+	}
+}
+
+func TestDownload_NotFound(t *testing.T) {
+	s := newImageService()
+
+	for _, tc := range []struct {
+		entityType string
+		name       string
+		format     string
+	}{
+		{"notfound", "", ""},
+		{"download_notfound", "", ""},
+		{"download", "thumbnail", "webp"},
+		{"download", "hero", ""},
+	} {
+		imageDTO = m.ImageDTO{EntityType: tc.entityType}
+
+		_, file, err := s.Download(m.ImageDTO{ID: storedImage.ID}, tc.name, tc.format)
+
+		assert.EqualError(t, err, "not found")
+		assert.Nil(t, file)
+	}
+}
+
+func TestDownload_Err(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "download_err"}
+
+	_, file, err := s.Download(m.ImageDTO{ID: storedImage.ID}, "", "")
+
+	assert.EqualError(t, err, "internal server error")
+	assert.Nil(t, file)
+}
+
 func TestUploadImage_OK(t *testing.T) {
-	imageDTO.File = createFile(t)
-	s := NewImageService(&S3RepositoryMock{}, &LoggerInterfaceMock{})
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "create", EntityID: uuid.New(), Type: "image/gif", File: createFile(t)}
 
 	result, err := s.Create(imageDTO)
 
 	assert.NoError(t, err)
 	assert.IsType(t, m.ImageDTO{}, result)
+
+	// the claimed type is replaced by the one determined from the contents
+	assert.Equal(t, "image/png", result.Type)
+	assert.NotEqual(t, uuid.Nil, result.ID)
+	assert.Equal(t, imageDTO.EntityID, result.EntityID)
+	assert.Len(t, result.Hash, 64)
+
+	// the variants are stored under the hash of the contents
+	assert.Equal(t, []string{
+		"img/" + result.Hash + "/card.jpg",
+		"img/" + result.Hash + "/card.webp",
+	}, uploadedObjects)
+	assert.Equal(t, "https://cdn.example.com/img/"+result.Hash+"/card.jpg", result.URL)
+	assert.Len(t, result.Variants, 2)
+	assert.Empty(t, deletedObjects)
+}
+
+func TestUploadImage_Duplicate(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "create_duplicate", EntityID: uuid.New(), Type: "image/png", File: createFile(t)}
+
+	result, err := s.Create(imageDTO)
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, storedImage.ID, result.ID)
+	assert.Equal(t, imageDTO.EntityID, result.EntityID)
+
+	// the stored variants of the image with the same contents are reused
+	assert.Empty(t, uploadedObjects)
+	assert.Len(t, result.Variants, 3)
+	assert.Equal(t, "https://cdn.example.com/img/"+imageID.String()+"/card.jpg", result.URL)
+}
+
+func TestUploadImage_FilesystemStore(t *testing.T) {
+	store := fr.NewFilesystemRepository(t.TempDir(), "/api/v2/image/files", "secret", time.Hour)
+	processor := pr.NewImageProcessor(m.ImagesConfig{
+		Quality:  80,
+		Formats:  []string{"jpeg", "webp"},
+		Variants: []m.VariantConfig{{Name: "card", MaxWidth: 100, MaxHeight: 100}},
+	})
+	validator := pr.NewImageValidator(m.UploadConfig{
+		MaxBytes:            1 << 20,
+		MaxMegapixels:       1,
+		MaxCompressionRatio: 5000,
+		Formats:             []string{"jpeg", "png", "webp"},
+	})
+	s := NewImageService(store, &ImageRepositoryMock{}, &UploadRepositoryMock{}, processor, validator, time.Hour, &LoggerInterfaceMock{})
+	imageDTO = m.ImageDTO{EntityType: "create", EntityID: uuid.New(), Type: "image/png", File: createFile(t)}
+
+	result, err := s.Create(imageDTO)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(result.URL, "/api/v2/image/files/img/"+result.Hash+"/card.jpg?"))
+
+	for _, variant := range result.Variants {
+		assert.Equal(t, 100, variant.Width)
+		assert.Equal(t, 50, variant.Height)
+
+		key, _, _ := strings.Cut(strings.TrimPrefix(variant.URL, "/api/v2/image/files/"), "?")
+
+		file, err := store.DownloadObject(key)
+		assert.NoError(t, err)
+
+		data, _ := io.ReadAll(file)
+		file.Close()
+		assert.Equal(t, variant.Size, int64(len(data)))
+	}
 }
 
 func TestUploadImage_Err(t *testing.T) {
-	imageDTO.File = createFile(t)
-	s := NewImageService(&S3RepositoryMock{}, &LoggerInterfaceMock{})
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "error", EntityID: uuid.New(), File: createFile(t)}
+
+	result, err := s.Create(imageDTO)
+
+	assert.EqualError(t, err, "internal server error")
+	assert.Equal(t, m.ImageDTO{}, result)
+}
+
+func TestUploadImage_PartialUploadErr(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "create_s3err", EntityID: uuid.New(), File: createFile(t)}
 
 	result, err := s.Create(imageDTO)
 
+	assert.EqualError(t, err, "internal server error")
+	assert.Equal(t, m.ImageDTO{}, result)
+	assert.Equal(t, uploadedObjects, deletedObjects)
+}
+
+func TestUploadImage_DatabaseErr(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "create_dberr", EntityID: uuid.New(), File: createFile(t)}
+
+	result, err := s.Create(imageDTO)
+
+	assert.EqualError(t, err, "internal server error")
+	assert.Equal(t, m.ImageDTO{}, result)
+	assert.Len(t, deletedObjects, 2)
+	assert.Equal(t, uploadedObjects, deletedObjects)
+}
+
+func TestUploadImage_Rejected(t *testing.T) {
+	s := newImageService()
+
+	for entityType, expected := range map[string]string{
+		"create_toolarge": "file too large",
+		"create_bomb":     "decompression bomb detected",
+	} {
+		imageDTO = m.ImageDTO{EntityType: entityType, EntityID: uuid.New(), File: createFile(t)}
+
+		_, err := s.Create(imageDTO)
+
+		assert.EqualError(t, err, expected)
+	}
+
+	assert.Empty(t, uploadedObjects)
+}
+
+func TestUploadImage_HashErr(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "create_hasherr", EntityID: uuid.New(), File: createFile(t)}
+
+	_, err := s.Create(imageDTO)
+
+	assert.EqualError(t, err, "internal server error")
+	assert.Empty(t, uploadedObjects)
+}
+
+func TestUploadImage_ProcessErr(t *testing.T) {
+	s := newImageService()
+
+	imageDTO = m.ImageDTO{EntityType: "unsupported", EntityID: uuid.New(), File: createFile(t)}
+	_, err := s.Create(imageDTO)
+	assert.EqualError(t, err, "unsupported image format")
+
+	imageDTO = m.ImageDTO{EntityType: "process_err", EntityID: uuid.New(), File: createFile(t)}
+	_, err = s.Create(imageDTO)
+	assert.EqualError(t, err, "internal server error")
+
+	assert.Empty(t, uploadedObjects)
+}
+
+func TestUploadImage_ValidationErr(t *testing.T) {
+	s := newImageService()
+
+	_, err := s.Create(m.ImageDTO{EntityID: uuid.New()})
+	assert.EqualError(t, err, "invalid entity type")
+
+	_, err = s.Create(m.ImageDTO{EntityType: "recipe"})
+	assert.EqualError(t, err, "invalid entity ID")
+
+	_, err = s.Create(m.ImageDTO{EntityType: "recipe", EntityID: uuid.New()})
+	assert.EqualError(t, err, "no file uploaded")
+}
+
+func TestRequestUpload_OK(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "upload"}
+
+	result, err := s.RequestUpload(m.ImageUploadDTO{EntityType: "recipe", EntityID: uuid.New(), Type: "image/png"})
+
 	assert.NoError(t, err)
-	assert.IsType(t, m.ImageDTO{}, result)
+	assert.NotEqual(t, uuid.Nil, result.ID)
+	assert.Equal(t, "https://cdn.example.com/uploads/"+result.ID.String()+"?signature=signature", result.UploadURL)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), result.ExpiresAt, time.Minute)
+}
+
+func TestRequestUpload_ValidationErr(t *testing.T) {
+	s := newImageService()
+
+	_, err := s.RequestUpload(m.ImageUploadDTO{EntityID: uuid.New(), Type: "image/png"})
+	assert.EqualError(t, err, "invalid entity type")
+
+	_, err = s.RequestUpload(m.ImageUploadDTO{EntityType: "recipe", Type: "image/png"})
+	assert.EqualError(t, err, "invalid entity ID")
+
+	_, err = s.RequestUpload(m.ImageUploadDTO{EntityType: "recipe", EntityID: uuid.New()})
+	assert.EqualError(t, err, "invalid content type")
+
+	_, err = s.RequestUpload(m.ImageUploadDTO{EntityType: "recipe", EntityID: uuid.New(), Type: "image/heic"})
+	assert.EqualError(t, err, "unsupported image format")
+}
+
+func TestRequestUpload_Err(t *testing.T) {
+	s := newImageService()
+
+	for _, entityType := range []string{"upload_signerr", "upload_dberr"} {
+		imageDTO = m.ImageDTO{EntityType: entityType}
+
+		result, err := s.RequestUpload(m.ImageUploadDTO{EntityType: "recipe", EntityID: uuid.New(), Type: "image/png"})
+
+		assert.EqualError(t, err, "internal server error")
+		assert.Equal(t, m.ImageUploadDTO{}, result)
+	}
+}
+
+func TestConfirmUpload_OK(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "confirm"}
+
+	result, err := s.ConfirmUpload(m.ImageUploadDTO{ID: uploadID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uploadID, result.ID)
+	assert.Equal(t, pendingUpload.EntityID, result.EntityID)
+	assert.Equal(t, []string{
+		"img/" + result.Hash + "/card.jpg",
+		"img/" + result.Hash + "/card.webp",
+	}, uploadedObjects)
+
+	// only the processed variants are kept
+	assert.Equal(t, []string{"uploads/" + uploadID.String()}, deletedObjects)
+	assert.Equal(t, []uuid.UUID{uploadID}, removedUploads)
+}
+
+func TestConfirmUpload_NotFound(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "notfound"}
+
+	_, err := s.ConfirmUpload(m.ImageUploadDTO{ID: uploadID})
+
+	assert.EqualError(t, err, "not found")
+}
+
+func TestConfirmUpload_Expired(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "confirm_expired"}
+
+	_, err := s.ConfirmUpload(m.ImageUploadDTO{ID: uploadID})
+
+	assert.EqualError(t, err, "upload expired")
+	assert.Empty(t, uploadedObjects)
+	assert.Equal(t, []string{"uploads/" + uploadID.String()}, deletedObjects)
+	assert.Equal(t, []uuid.UUID{uploadID}, removedUploads)
+}
+
+func TestConfirmUpload_Err(t *testing.T) {
+	s := newImageService()
+
+	for entityType, expected := range map[string]string{
+		"confirm_missing":     "file not uploaded",
+		"confirm_unsupported": "unsupported image format",
+		"error":               "internal server error",
+	} {
+		imageDTO = m.ImageDTO{EntityType: entityType}
+
+		_, err := s.ConfirmUpload(m.ImageUploadDTO{ID: uploadID})
+
+		assert.EqualError(t, err, expected, entityType)
+	}
+
+	// the upload is kept, so the file can still be uploaded or replaced before it expires
+	assert.Empty(t, removedUploads)
+}
+
+func TestDelete_OK(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "delete"}
+
+	err := s.Delete(m.ImageDTO{ID: storedImage.ID})
+
+	// the stored variants are left to the garbage collector, other images may still use them
+	assert.NoError(t, err)
+	assert.Empty(t, deletedObjects)
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	s := newImageService()
+	imageDTO = m.ImageDTO{EntityType: "notfound"}
+
+	err := s.Delete(m.ImageDTO{ID: storedImage.ID})
+
+	assert.EqualError(t, err, "not found")
+	assert.Empty(t, deletedObjects)
+}
+
+func TestDelete_Err(t *testing.T) {
+	s := newImageService()
+
+	for _, entityType := range []string{"error", "delete_dberr"} {
+		imageDTO = m.ImageDTO{EntityType: entityType}
+
+		err := s.Delete(m.ImageDTO{ID: storedImage.ID})
+
+		assert.EqualError(t, err, "internal server error")
+	}
 }
 
 // ====== Helpers ======
@@ -114,7 +715,11 @@ func createFile(t *testing.T) multipart.File {
 	}()
 
 	req := httptest.NewRequest("POST", "http://example.com/v1/recipe/1/upload", pr)
-	file, _, _ := req.FormFile("file")
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	file, _, err := req.FormFile("file")
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	return file
 
@@ -1,12 +1,21 @@
 package config
 
 import (
+	"time"
+
+	"cookbook/pkg/outbox"
 	ih "ingredient-service/internal/handlers/ingredients"
+	ph "ingredient-service/internal/handlers/pantry"
+	rih "ingredient-service/internal/handlers/recipeingredients"
 	uh "ingredient-service/internal/handlers/units"
 	m "ingredient-service/internal/models"
 	ir "ingredient-service/internal/repositories/ingredients"
+	pr "ingredient-service/internal/repositories/pantry"
+	rir "ingredient-service/internal/repositories/recipeingredients"
 	ur "ingredient-service/internal/repositories/units"
 	is "ingredient-service/internal/services/ingredients"
+	ps "ingredient-service/internal/services/pantry"
+	ris "ingredient-service/internal/services/recipeingredients"
 	us "ingredient-service/internal/services/units"
 
 	"github.com/fsnotify/fsnotify"
@@ -24,15 +33,26 @@ var (
 	Cors           cors.Config
 
 	// Repositories
-	IngredientRepository *ir.IngredientRepository
-	UnitRepository       *ur.UnitRepository
+	IngredientRepository       *ir.IngredientRepository
+	UnitRepository             *ur.UnitRepository
+	RecipeIngredientRepository *rir.RecipeIngredientRepository
+	PantryRepository           *pr.PantryRepository
+
+	// Events
+	Relay         *outbox.Relay
+	RelayInterval time.Duration
+
 	// Services
-	IngredientService *is.IngredientService
-	UnitService       *us.UnitService
+	IngredientService       *is.IngredientService
+	UnitService             *us.UnitService
+	RecipeIngredientService *ris.RecipeIngredientService
+	PantryService           *ps.PantryService
 
 	// Handlers
-	IngredientHandlers *ih.IngredientHandlers
-	UnitHandlers       *uh.UnitHandlers
+	IngredientHandlers       *ih.IngredientHandlers
+	UnitHandlers             *uh.UnitHandlers
+	RecipeIngredientHandlers *rih.RecipeIngredientHandlers
+	PantryHandlers           *ph.PantryHandlers
 )
 
 func init() {
@@ -54,12 +74,34 @@ func init() {
 	// Init repositories
 	IngredientRepository = ir.NewIngredientRepository(DatabaseClient)
 	UnitRepository = ur.NewUnitRepository(DatabaseClient)
+	RecipeIngredientRepository = rir.NewRecipeIngredientRepository(DatabaseClient)
+	PantryRepository = pr.NewPantryRepository(DatabaseClient)
+
+	if normalized, err := IngredientRepository.NormalizeNames(); err != nil {
+		Logger.Fatalf("Error while normalizing ingredient names: %s", err.Error())
+	} else if normalized > 0 {
+		Logger.Infof("normalized the names of %d ingredients", normalized)
+	}
+
+	if classified, err := UnitRepository.Classify(); err != nil {
+		Logger.Fatalf("Error while classifying units: %s", err.Error())
+	} else if classified > 0 {
+		Logger.Infof("classified %d units", classified)
+	}
+
+	// Init events
+	Relay = outbox.NewRelay(DatabaseClient, eventBroker(), "/ingredient-service", relayBatchSize(), Logger)
+	RelayInterval = relayInterval()
 
 	// Init services
 	IngredientService = is.NewIngredientService(IngredientRepository)
-	UnitService = us.NewUnitService(UnitRepository)
+	UnitService = us.NewUnitService(UnitRepository, IngredientRepository)
+	RecipeIngredientService = ris.NewRecipeIngredientService(RecipeIngredientRepository, IngredientRepository, UnitRepository)
+	PantryService = ps.NewPantryService(PantryRepository, IngredientRepository)
 
 	// Init handlers
 	IngredientHandlers = ih.NewIngredientHandlers(IngredientService, Logger)
 	UnitHandlers = uh.NewUnitHandlers(UnitService, Logger)
+	RecipeIngredientHandlers = rih.NewRecipeIngredientHandlers(RecipeIngredientService, Logger)
+	PantryHandlers = ph.NewPantryHandlers(PantryService, Logger)
 }
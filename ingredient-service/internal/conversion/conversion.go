@@ -0,0 +1,122 @@
+package conversion
+
+import (
+	"errors"
+	"math"
+
+	m "ingredient-service/internal/models"
+)
+
+// Convert converts a quantity from one unit into another of the same dimension. With the density of the ingredient,
+// in grams per millilitre, volumes and weights can be converted into each other as well.
+func Convert(quantity float64, from m.Unit, to m.Unit, density float64) (float64, error) {
+
+	if from.Dimension == "" || from.Factor <= 0 || to.Dimension == "" || to.Factor <= 0 {
+		return 0, errors.New("unit has no dimension")
+	}
+
+	base := quantity*from.Factor + from.Offset
+
+	switch {
+	case from.Dimension == to.Dimension:
+	case from.Dimension == m.DimensionVolume && to.Dimension == m.DimensionMass:
+		if density <= 0 {
+			return 0, errors.New("density is unknown")
+		}
+		base = base * density
+	case from.Dimension == m.DimensionMass && to.Dimension == m.DimensionVolume:
+		if density <= 0 {
+			return 0, errors.New("density is unknown")
+		}
+		base = base / density
+	default:
+		return 0, errors.New("units cannot be converted")
+	}
+
+	return (base - to.Offset) / to.Factor, nil
+}
+
+// ToSystem converts a quantity into the unit of the measurement system that reads best, which is the largest unit
+// the quantity amounts to at least one of. When the density is known volumes are converted into weights, as weighed
+// amounts are more precise. Quantities in a unit without a system, such as a piece, are returned as they are.
+func ToSystem(quantity float64, from m.Unit, units []m.Unit, system string, density float64) (float64, m.Unit, error) {
+
+	if from.Dimension == "" || from.Factor <= 0 {
+		return 0, m.Unit{}, errors.New("unit has no dimension")
+	}
+
+	if from.System == "" || (from.System == system && (from.Dimension != m.DimensionVolume || density <= 0)) {
+		return quantity, from, nil
+	}
+
+	dimension := from.Dimension
+	if dimension == m.DimensionVolume && density > 0 {
+		dimension = m.DimensionMass
+	}
+
+	var best m.Unit
+	var converted float64
+	for _, unit := range units {
+		if unit.System != system || unit.Dimension != dimension || unit.Factor <= 0 {
+			continue
+		}
+
+		value, err := Convert(quantity, from, unit, density)
+		if err != nil {
+			return 0, m.Unit{}, err
+		}
+
+		if best.Factor <= 0 || readsBetter(value, unit, converted, best) {
+			best, converted = unit, value
+		}
+	}
+
+	if best.Factor <= 0 {
+		return 0, m.Unit{}, errors.New("no unit to convert to")
+	}
+
+	return converted, best, nil
+}
+
+// readsBetter tells whether a quantity in a unit reads better than the current one. The largest unit the quantity
+// amounts to at least one of wins, and below one of every unit the smallest.
+func readsBetter(value float64, unit m.Unit, current float64, currentUnit m.Unit) bool {
+	whole, currentWhole := math.Abs(value) >= 1, math.Abs(current) >= 1
+
+	switch {
+	case whole != currentWhole:
+		return whole
+	case whole:
+		return unit.Factor > currentUnit.Factor
+	default:
+		return unit.Factor < currentUnit.Factor
+	}
+}
+
+// Find finds a unit by its ID, its full or short name or another common spelling of the name, so "tbsp" and
+// "tablespoons" both find the tablespoon
+func Find(units []m.Unit, name string) (m.Unit, bool) {
+	spellings := []string{name}
+	if known, ok := lookup(name); ok {
+		spellings = append(spellings, known.names...)
+	}
+
+	for _, unit := range units {
+		if unit.ID.String() == name {
+			return unit, true
+		}
+
+		for _, spelling := range spellings {
+			if sameName(spelling, unit.FullName) || sameName(spelling, unit.ShortName) {
+				return unit, true
+			}
+		}
+	}
+
+	return m.Unit{}, false
+}
+
+// Round rounds a converted quantity to two decimals for display
+func Round(quantity float64) float64 {
+	return math.Round(quantity*100) / 100
+}
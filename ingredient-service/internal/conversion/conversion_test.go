@@ -0,0 +1,147 @@
+package conversion
+
+import (
+	"testing"
+
+	m "ingredient-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	gram       = unit("Gram", "g")
+	kilogram   = unit("Kilogram", "kg")
+	ounce      = unit("Ounce", "oz")
+	pound      = unit("Pound", "lb")
+	millilitre = unit("Millilitre", "ml")
+	litre      = unit("Litre", "l")
+	teaspoon   = unit("Teaspoon", "tsp")
+	tablespoon = unit("Tablespoon", "tbsp")
+	cup        = unit("Cup", "cup")
+	piece      = unit("Piece", "pc")
+	celsius    = unit("Degree Celsius", "°C")
+	fahrenheit = unit("Degree Fahrenheit", "°F")
+
+	units = []m.Unit{gram, kilogram, ounce, pound, millilitre, litre, teaspoon, tablespoon, cup, piece, celsius, fahrenheit}
+)
+
+func unit(fullName string, shortName string) m.Unit {
+	unit, _ := Classify(m.Unit{ID: uuid.New(), FullName: fullName, ShortName: shortName})
+	return unit
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		quantity float64
+		from     m.Unit
+		to       m.Unit
+		density  float64
+		expected float64
+	}{
+		{1, cup, millilitre, 0, 236.588},
+		{3, teaspoon, tablespoon, 0, 1},
+		{1.5, kilogram, gram, 0, 1500},
+		{1, pound, ounce, 0, 16},
+		{1, cup, gram, 0.507, 119.95},
+		{120, gram, cup, 0.507, 1},
+		{180, celsius, fahrenheit, 0, 356},
+		{212, fahrenheit, celsius, 0, 100},
+	}
+
+	for _, test := range tests {
+		result, err := Convert(test.quantity, test.from, test.to, test.density)
+
+		assert.NoError(t, err)
+		assert.InDelta(t, test.expected, result, 0.01, "%v %s to %s", test.quantity, test.from.ShortName, test.to.ShortName)
+	}
+}
+
+func TestConvert_DensityErr(t *testing.T) {
+	_, err := Convert(1, cup, gram, 0)
+
+	assert.EqualError(t, err, "density is unknown")
+}
+
+func TestConvert_DimensionErr(t *testing.T) {
+	_, err := Convert(1, cup, piece, 0)
+
+	assert.EqualError(t, err, "units cannot be converted")
+}
+
+func TestConvert_NoDimensionErr(t *testing.T) {
+	_, err := Convert(1, m.Unit{FullName: "Handful"}, gram, 0)
+
+	assert.EqualError(t, err, "unit has no dimension")
+}
+
+func TestToSystem(t *testing.T) {
+	tests := []struct {
+		quantity float64
+		from     m.Unit
+		system   string
+		density  float64
+		expected float64
+		unit     m.Unit
+	}{
+		{1, cup, m.SystemMetric, 0, 236.588, millilitre},
+		{2, litre, m.SystemImperial, 0, 8.454, cup},
+		{1, millilitre, m.SystemImperial, 0, 0.203, teaspoon},
+		{1, cup, m.SystemMetric, 0.507, 119.95, gram},
+		{500, gram, m.SystemImperial, 0, 1.102, pound},
+		{100, gram, m.SystemImperial, 0, 3.527, ounce},
+		{2, cup, m.SystemImperial, 0, 2, cup},
+		{3, piece, m.SystemMetric, 0, 3, piece},
+		{350, fahrenheit, m.SystemMetric, 0, 176.667, celsius},
+	}
+
+	for _, test := range tests {
+		quantity, unit, err := ToSystem(test.quantity, test.from, units, test.system, test.density)
+
+		assert.NoError(t, err)
+		assert.InDelta(t, test.expected, quantity, 0.01, "%v %s in %s", test.quantity, test.from.ShortName, test.system)
+		assert.Equal(t, test.unit.ID, unit.ID, "%v %s in %s", test.quantity, test.from.ShortName, test.system)
+	}
+}
+
+func TestToSystem_NoUnitErr(t *testing.T) {
+	_, _, err := ToSystem(1, cup, []m.Unit{cup, gram}, m.SystemMetric, 0)
+
+	assert.EqualError(t, err, "no unit to convert to")
+}
+
+func TestFind(t *testing.T) {
+	tests := map[string]m.Unit{
+		"g":             gram,
+		"grams":         gram,
+		"Tablespoons":   tablespoon,
+		"tbsp":          tablespoon,
+		"T":             tablespoon,
+		"t":             teaspoon,
+		"liter":         litre,
+		"℃":             celsius,
+		cup.ID.String(): cup,
+	}
+
+	for name, expected := range tests {
+		result, ok := Find(units, name)
+
+		assert.True(t, ok, name)
+		assert.Equal(t, expected.ID, result.ID, name)
+	}
+
+	_, ok := Find(units, "handful")
+	assert.False(t, ok)
+}
+
+func TestClassify(t *testing.T) {
+	result, ok := Classify(m.Unit{FullName: "Fluid ounces", ShortName: "fl oz"})
+
+	assert.True(t, ok)
+	assert.Equal(t, m.DimensionVolume, result.Dimension)
+	assert.Equal(t, m.SystemImperial, result.System)
+	assert.InDelta(t, 29.57, result.Factor, 0.01)
+
+	_, ok = Classify(m.Unit{FullName: "Handful", ShortName: "handful"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,90 @@
+package conversion
+
+import (
+	"cookbook/pkg/normalize"
+	m "ingredient-service/internal/models"
+)
+
+// standardUnit is a well-known unit, by which the units stored before units had a dimension are classified
+type standardUnit struct {
+	names     []string // the full name, the short name and other common spellings
+	dimension string
+	system    string
+	factor    float64
+	offset    float64
+}
+
+// the US customary volumes are used for the imperial system, as that is what most recipes mean by a cup
+var standardUnits = []standardUnit{
+	{[]string{"milligram", "mg"}, m.DimensionMass, m.SystemMetric, 0.001, 0},
+	{[]string{"gram", "g", "gr", "gramme"}, m.DimensionMass, m.SystemMetric, 1, 0},
+	{[]string{"kilogram", "kg", "kilo", "kilogramme"}, m.DimensionMass, m.SystemMetric, 1000, 0},
+	{[]string{"ounce", "oz"}, m.DimensionMass, m.SystemImperial, 28.349523125, 0},
+	{[]string{"pound", "lb", "lbs"}, m.DimensionMass, m.SystemImperial, 453.59237, 0},
+
+	{[]string{"millilitre", "ml", "milliliter"}, m.DimensionVolume, m.SystemMetric, 1, 0},
+	{[]string{"centilitre", "cl", "centiliter"}, m.DimensionVolume, m.SystemMetric, 10, 0},
+	{[]string{"decilitre", "dl", "deciliter"}, m.DimensionVolume, m.SystemMetric, 100, 0},
+	{[]string{"litre", "l", "liter"}, m.DimensionVolume, m.SystemMetric, 1000, 0},
+	{[]string{"teaspoon", "tsp", "t"}, m.DimensionVolume, m.SystemImperial, 4.92892159375, 0},
+	{[]string{"tablespoon", "tbsp", "tbs", "T"}, m.DimensionVolume, m.SystemImperial, 14.78676478125, 0},
+	{[]string{"fluid ounce", "fl oz", "fl. oz."}, m.DimensionVolume, m.SystemImperial, 29.5735295625, 0},
+	{[]string{"cup", "c"}, m.DimensionVolume, m.SystemImperial, 236.5882365, 0},
+	{[]string{"pint", "pt"}, m.DimensionVolume, m.SystemImperial, 473.176473, 0},
+	{[]string{"quart", "qt"}, m.DimensionVolume, m.SystemImperial, 946.352946, 0},
+	{[]string{"gallon", "gal"}, m.DimensionVolume, m.SystemImperial, 3785.411784, 0},
+
+	{[]string{"piece", "pc", "pcs", "each"}, m.DimensionCount, "", 1, 0},
+	{[]string{"dozen", "doz"}, m.DimensionCount, "", 12, 0},
+
+	{[]string{"millimetre", "mm", "millimeter"}, m.DimensionLength, m.SystemMetric, 1, 0},
+	{[]string{"centimetre", "cm", "centimeter"}, m.DimensionLength, m.SystemMetric, 10, 0},
+	{[]string{"inch", "in"}, m.DimensionLength, m.SystemImperial, 25.4, 0},
+
+	{[]string{"degree Celsius", "°C", "celsius", "degrees Celsius"}, m.DimensionTemperature, m.SystemMetric, 1, 0},
+	{[]string{"degree Fahrenheit", "°F", "fahrenheit", "degrees Fahrenheit"}, m.DimensionTemperature, m.SystemImperial, 5.0 / 9.0, -160.0 / 9.0},
+}
+
+// Classify sets the dimension, system, factor and offset of a unit without a dimension when its full or short name
+// is that of a well-known unit. It tells whether the unit is known.
+func Classify(unit m.Unit) (m.Unit, bool) {
+	known, ok := lookup(unit.FullName)
+	if !ok {
+		known, ok = lookup(unit.ShortName)
+	}
+
+	if !ok {
+		return unit, false
+	}
+
+	unit.Dimension = known.dimension
+	unit.System = known.system
+	unit.Factor = known.factor
+	unit.Offset = known.offset
+
+	return unit, true
+}
+
+// lookup finds the well-known unit a name refers to
+func lookup(name string) (standardUnit, bool) {
+
+	for _, known := range standardUnits {
+		for _, spelling := range known.names {
+			if sameName(spelling, name) {
+				return known, true
+			}
+		}
+	}
+
+	return standardUnit{}, false
+}
+
+// sameName tells whether two unit names are the same once normalized. Single letters are compared as they are, as
+// "t" is a teaspoon and "T" a tablespoon.
+func sameName(a string, b string) bool {
+	if len(a) == 1 || len(b) == 1 {
+		return a == b
+	}
+
+	return normalize.Name(a) == normalize.Name(b)
+}
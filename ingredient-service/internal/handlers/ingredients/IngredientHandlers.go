@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	m "ingredient-service/internal/models"
 
@@ -12,9 +14,12 @@ import (
 type IngredientService interface {
 	FindAll() ([]m.IngredientDTO, error)
 	FindSingle(ingredientDTO m.IngredientDTO) (m.IngredientDTO, error)
+	Lookup(name string) (m.IngredientDTO, error)
 	Create(ingredientDTO m.IngredientDTO) (m.IngredientDTO, error)
 	Update(ingredientDTO m.IngredientDTO) (m.IngredientDTO, error)
 	Delete(ingredientDTO m.IngredientDTO) error
+	Reassign(ingredientDTO m.IngredientDTO, replacementDTO m.IngredientDTO) error
+	Merge(mergeDTO m.MergeRequestDTO, preview bool) (m.MergeResultDTO, error)
 }
 
 type IngredientHandlers struct {
@@ -75,6 +80,29 @@ func (h IngredientHandlers) GetSingle(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, ingredientDTO)
 }
 
+// Lookup the ingredient a name refers to, by its normalized name or one of its aliases
+func (h IngredientHandlers) Lookup(ctx *gin.Context) {
+	name := ctx.Query("name")
+	if name == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	ingredientDTO, err := h.ingredientService.Lookup(name)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "no ingredient found"})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, ingredientDTO)
+}
+
 // Create an ingredient
 func (h IngredientHandlers) Create(ctx *gin.Context) {
 	var ingredientDTO m.IngredientDTO
@@ -87,8 +115,21 @@ func (h IngredientHandlers) Create(ctx *gin.Context) {
 
 	ingredientDTO, err = h.ingredientService.Create(ingredientDTO)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		switch err.Error() {
+		case "ingredient already exists":
+			// the name refers to an existing ingredient, which is returned instead of creating a duplicate
+			ctx.JSON(http.StatusOK, ingredientDTO)
+			return
+		case "alias is already used by another ingredient", "name is already used":
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case "density cannot be negative":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	ctx.JSON(http.StatusCreated, ingredientDTO)
@@ -115,8 +156,17 @@ func (h IngredientHandlers) Update(ctx *gin.Context) {
 
 	ingredientDTO, err = h.ingredientService.Update(ingredientDTO)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		switch err.Error() {
+		case "ingredient already exists", "alias is already used by another ingredient", "name is already used":
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case "density cannot be negative":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	ctx.JSON(http.StatusOK, ingredientDTO)
@@ -133,11 +183,76 @@ func (h IngredientHandlers) Delete(ctx *gin.Context) {
 		return
 	}
 
-	err = h.ingredientService.Delete(ingredientDTO)
+	// with reassignTo the references are moved to the replacement, instead of refusing the delete
+	if reassignTo := ctx.Query("reassignTo"); reassignTo != "" {
+		var replacementDTO m.IngredientDTO
+
+		replacementDTO.ID, err = uuid.Parse(reassignTo)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid reassignTo parameter"})
+			return
+		}
+
+		err = h.ingredientService.Reassign(ingredientDTO, replacementDTO)
+	} else {
+		err = h.ingredientService.Delete(ingredientDTO)
+	}
+
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		var inUse m.InUseError
+		switch {
+		case errors.As(err, &inUse):
+			ctx.JSON(http.StatusConflict, inUse.ConvertToDTO())
+			return
+		case err.Error() == "replacement not found", err.Error() == "ingredient cannot be reassigned to itself":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	ctx.Status(http.StatusOK)
 }
+
+// Merge duplicate ingredients into one. With preview=true nothing is changed, the impacted recipes are only reported.
+func (h IngredientHandlers) Merge(ctx *gin.Context) {
+	var mergeDTO m.MergeRequestDTO
+	var err error
+
+	if err = ctx.ShouldBindJSON(&mergeDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unexpected JSON input"})
+		return
+	}
+
+	preview := false
+	if value := ctx.Query("preview"); value != "" {
+		preview, err = strconv.ParseBool(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid preview parameter"})
+			return
+		}
+	}
+
+	resultDTO, err := h.ingredientService.Merge(mergeDTO, preview)
+	if err != nil {
+		var inUse m.InUseError
+		switch {
+		case errors.As(err, &inUse):
+			ctx.JSON(http.StatusConflict, inUse.ConvertToDTO())
+			return
+		case err.Error() == "target not found", err.Error() == "source not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case err.Error() == "ingredient cannot be merged into itself", err.Error() == "no ingredients to merge":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, resultDTO)
+}
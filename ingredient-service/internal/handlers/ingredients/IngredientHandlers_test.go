@@ -57,10 +57,29 @@ func (s *IngredientServiceMock) FindSingle(ingredientDTO m.IngredientDTO) (m.Ing
 	}
 }
 
+func (s *IngredientServiceMock) Lookup(name string) (m.IngredientDTO, error) {
+	switch name {
+	case "Eggs":
+		return ingredient, nil
+	case "unknown":
+		return m.IngredientDTO{}, errors.New("not found")
+	default:
+		return m.IngredientDTO{}, errors.New("error")
+	}
+}
+
 func (s *IngredientServiceMock) Create(ingredientDTO m.IngredientDTO) (m.IngredientDTO, error) {
 	switch ingredientDTO.Name {
 	case "create":
 		return ingredient, nil
+	case "Eggs":
+		return ingredient, errors.New("ingredient already exists")
+	case "taken":
+		return m.IngredientDTO{}, errors.New("alias is already used by another ingredient")
+	case "used":
+		return m.IngredientDTO{}, errors.New("name is already used")
+	case "negative":
+		return m.IngredientDTO{}, errors.New("density cannot be negative")
 	default:
 		return ingredient, errors.New("error")
 	}
@@ -70,6 +89,8 @@ func (s *IngredientServiceMock) Update(ingredientDTO m.IngredientDTO) (m.Ingredi
 	switch ingredientDTO.Name {
 	case "update":
 		return ingredient, nil
+	case "taken":
+		return m.IngredientDTO{}, errors.New("ingredient already exists")
 	default:
 		return ingredient, errors.New("error")
 	}
@@ -79,11 +100,41 @@ func (s *IngredientServiceMock) Delete(ingredientDTO m.IngredientDTO) error {
 	switch ingredient.Name {
 	case "delete":
 		return nil
+	case "inuse":
+		return m.NewInUseError("ingredient", inUseUsage)
+	default:
+		return errors.New("error")
+	}
+}
+
+func (s *IngredientServiceMock) Reassign(ingredientDTO m.IngredientDTO, replacementDTO m.IngredientDTO) error {
+	switch ingredient.Name {
+	case "reassign":
+		return nil
+	case "noreplacement":
+		return errors.New("replacement not found")
 	default:
 		return errors.New("error")
 	}
 }
 
+func (s *IngredientServiceMock) Merge(mergeDTO m.MergeRequestDTO, preview bool) (m.MergeResultDTO, error) {
+	switch ingredient.Name {
+	case "merge":
+		return m.MergeResultDTO{Target: ingredient, Merged: mergeDTO.SourceIDs, Aliases: []string{"duplicate"}, RecipeIDs: []uuid.UUID{}, Conflicts: []uuid.UUID{}, Preview: preview}, nil
+	case "inuse":
+		return m.MergeResultDTO{}, m.InUseError{Message: "12 recipes use more than one of the merged ingredients", Usage: inUseUsage}
+	case "notfound":
+		return m.MergeResultDTO{}, errors.New("source not found")
+	default:
+		return m.MergeResultDTO{}, errors.New("error")
+	}
+}
+
+var (
+	inUseUsage m.Usage = m.Usage{Count: 12, RecipeIDs: []uuid.UUID{uuid.New(), uuid.New()}}
+)
+
 type LoggerInterfaceMock struct{}
 
 func (l *LoggerInterfaceMock) Debugf(format string, args ...interface{}) {}
@@ -461,3 +512,365 @@ func TestIngredientDelete_DeleteErr(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	assert.Equal(t, []byte(`{"error":"error"}`), body)
 }
+
+func TestIngredientDelete_InUseErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	ingredient.Name = "inuse"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/ingredient/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: ingredient.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(m.InUseDTO{Error: "ingredient is used by 12 recipes", Count: 12, RecipeIDs: inUseUsage.RecipeIDs, DeletedRecipeIDs: []uuid.UUID{}})
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestIngredientDelete_ReassignOK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	ingredient.Name = "reassign"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/ingredient/1?reassignTo="+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: ingredient.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestIngredientDelete_ReassignInvalidErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	ingredient.Name = "reassign"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/ingredient/1?reassignTo=1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: ingredient.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"invalid reassignTo parameter"}`), body)
+}
+
+func TestIngredientDelete_ReassignNotFoundErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	ingredient.Name = "noreplacement"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/ingredient/1?reassignTo="+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: ingredient.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"replacement not found"}`), body)
+}
+
+func TestIngredientMerge_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	ingredient.Name = "merge"
+	mergeDTO := m.MergeRequestDTO{TargetID: ingredient.ID, SourceIDs: []uuid.UUID{uuid.New()}}
+	jsonValue, _ := json.Marshal(mergeDTO)
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/ingredient/merge?preview=true", bytes.NewBuffer(jsonValue))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Merge(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(m.MergeResultDTO{Target: ingredient, Merged: mergeDTO.SourceIDs, Aliases: []string{"duplicate"}, RecipeIDs: []uuid.UUID{}, Conflicts: []uuid.UUID{}, Preview: true})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestIngredientMerge_UnmarshalErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	ingredient.Name = "merge"
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/ingredient/merge", bytes.NewBufferString(`{"source_ids": []}`))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Merge(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"unexpected JSON input"}`, string(body))
+}
+
+func TestIngredientMerge_PreviewErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	ingredient.Name = "merge"
+	jsonValue, _ := json.Marshal(m.MergeRequestDTO{TargetID: ingredient.ID, SourceIDs: []uuid.UUID{uuid.New()}})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/ingredient/merge?preview=maybe", bytes.NewBuffer(jsonValue))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Merge(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid preview parameter"}`, string(body))
+}
+
+func TestIngredientMerge_InUseErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	ingredient.Name = "inuse"
+	jsonValue, _ := json.Marshal(m.MergeRequestDTO{TargetID: ingredient.ID, SourceIDs: []uuid.UUID{uuid.New()}})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/ingredient/merge", bytes.NewBuffer(jsonValue))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Merge(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(m.InUseDTO{Error: "12 recipes use more than one of the merged ingredients", Count: 12, RecipeIDs: inUseUsage.RecipeIDs, DeletedRecipeIDs: []uuid.UUID{}})
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestIngredientMerge_NotFoundErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	ingredient.Name = "notfound"
+	jsonValue, _ := json.Marshal(m.MergeRequestDTO{TargetID: ingredient.ID, SourceIDs: []uuid.UUID{uuid.New()}})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/ingredient/merge", bytes.NewBuffer(jsonValue))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Merge(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"source not found"}`, string(body))
+}
+
+func TestIngredientLookup_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/ingredient/lookup?name=Eggs", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Lookup(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(ingredient)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestIngredientLookup_NameRequiredErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/ingredient/lookup", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Lookup(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"name is required"}`, string(body))
+}
+
+func TestIngredientLookup_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/ingredient/lookup?name=unknown", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Lookup(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"no ingredient found"}`, string(body))
+}
+
+func TestIngredientCreate_Existing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	reqBody, _ := json.Marshal(m.IngredientDTO{Name: "Eggs"})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/ingredient", bytes.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(ingredient)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestIngredientCreate_AliasTakenErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	reqBody, _ := json.Marshal(m.IngredientDTO{Name: "taken"})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/ingredient", bytes.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, `{"error":"alias is already used by another ingredient"}`, string(body))
+}
+
+func TestIngredientCreate_NameUsedErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	reqBody, _ := json.Marshal(m.IngredientDTO{Name: "used"})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/ingredient", bytes.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, `{"error":"name is already used"}`, string(body))
+}
+
+func TestIngredientUpdate_ExistsErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	reqBody, _ := json.Marshal(m.IngredientDTO{Name: "taken"})
+
+	req := httptest.NewRequest("PUT", "http://example.com/api/v2/ingredient/1", bytes.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: ingredient.ID.String()},
+	}
+
+	h.Update(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, `{"error":"ingredient already exists"}`, string(body))
+}
+
+func TestIngredientCreate_DensityErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIngredientHandlers(&IngredientServiceMock{}, &LoggerInterfaceMock{})
+
+	reqBody, _ := json.Marshal(m.IngredientDTO{Name: "negative", Density: -1})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/ingredient", bytes.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"density cannot be negative"}`, string(body))
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	m "ingredient-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tbaehler/gin-keycloak/pkg/ginkeycloak"
+)
+
+type PantryService interface {
+	FindBySubject(subject string) ([]m.IngredientDTO, error)
+	Replace(subject string, requestDTO m.PantryRequestDTO) ([]m.IngredientDTO, error)
+	Match(subject string, requestDTO m.PantryMatchRequestDTO) ([]m.PantryMatchDTO, error)
+}
+
+type PantryHandlers struct {
+	pantryService PantryService
+	logger        m.LoggerInterface
+}
+
+func NewPantryHandlers(pantry PantryService, logger m.LoggerInterface) *PantryHandlers {
+	return &PantryHandlers{
+		pantryService: pantry,
+		logger:        logger,
+	}
+}
+
+// Get the ingredients in the pantry of the current user
+func (h PantryHandlers) Get(ctx *gin.Context) {
+	subject, ok := subject(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unknown user"})
+		return
+	}
+
+	ingredientDTOs, err := h.pantryService.FindBySubject(subject)
+	if err != nil {
+		h.respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ingredientDTOs)
+}
+
+// Replace the ingredients in the pantry of the current user
+func (h PantryHandlers) Replace(ctx *gin.Context) {
+	var requestDTO m.PantryRequestDTO
+
+	subject, ok := subject(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unknown user"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&requestDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unexpected JSON input"})
+		return
+	}
+
+	ingredientDTOs, err := h.pantryService.Replace(subject, requestDTO)
+	if err != nil {
+		h.respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ingredientDTOs)
+}
+
+// Match ranks the recipes that can be cooked with the given ingredients or, without any, the pantry of the current user
+func (h PantryHandlers) Match(ctx *gin.Context) {
+	var requestDTO m.PantryMatchRequestDTO
+
+	subject, ok := subject(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unknown user"})
+		return
+	}
+
+	// an empty body matches against the pantry with the default options
+	if err := ctx.ShouldBindJSON(&requestDTO); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unexpected JSON input"})
+		return
+	}
+
+	matchDTOs, err := h.pantryService.Match(subject, requestDTO)
+	if err != nil {
+		h.respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, matchDTOs)
+}
+
+func (h PantryHandlers) respondWithError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case "ingredient does not exist", "duplicate ingredient in list", "no ingredients provided", "max missing must not be negative":
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// subject returns the keycloak subject of the authenticated user, which the access check stores on the context
+func subject(ctx *gin.Context) (string, bool) {
+	value, found := ctx.Get("token")
+	if !found {
+		return "", false
+	}
+
+	token, ok := value.(ginkeycloak.KeyCloakToken)
+	if !ok || token.Sub == "" {
+		return "", false
+	}
+
+	return token.Sub, true
+}
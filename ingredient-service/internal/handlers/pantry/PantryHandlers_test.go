@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	m "ingredient-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/tbaehler/gin-keycloak/pkg/ginkeycloak"
+)
+
+type PantryServiceMock struct{}
+
+var (
+	subjectFound   = "subject-found"
+	subjectInvalid = "subject-invalid"
+	subjectError   = "subject-error"
+
+	ingredient m.IngredientDTO = m.IngredientDTO{ID: uuid.New(), Name: "rice"}
+
+	pantryMatch m.PantryMatchDTO = m.PantryMatchDTO{
+		RecipeID:    uuid.New(),
+		Ingredients: 2,
+		Matched:     1,
+		Coverage:    0.5,
+		Missing:     []m.IngredientDTO{{ID: uuid.New(), Name: "saffron"}},
+	}
+
+	requestedMatch m.PantryMatchRequestDTO
+)
+
+func mockResult(subject string) error {
+	switch subject {
+	case subjectFound:
+		return nil
+	case subjectInvalid:
+		return errors.New("ingredient does not exist")
+	default:
+		return errors.New("internal server error")
+	}
+}
+
+func (s *PantryServiceMock) FindBySubject(subject string) ([]m.IngredientDTO, error) {
+	if err := mockResult(subject); err != nil {
+		return nil, err
+	}
+	return []m.IngredientDTO{ingredient}, nil
+}
+
+func (s *PantryServiceMock) Replace(subject string, requestDTO m.PantryRequestDTO) ([]m.IngredientDTO, error) {
+	return s.FindBySubject(subject)
+}
+
+func (s *PantryServiceMock) Match(subject string, requestDTO m.PantryMatchRequestDTO) ([]m.PantryMatchDTO, error) {
+	requestedMatch = requestDTO
+	if err := mockResult(subject); err != nil {
+		return nil, err
+	}
+	return []m.PantryMatchDTO{pantryMatch}, nil
+}
+
+type LoggerInterfaceMock struct{}
+
+func (l *LoggerInterfaceMock) Debugf(format string, args ...interface{}) {}
+func (l *LoggerInterfaceMock) Warnf(format string, args ...interface{})  {}
+
+func newTestContext(method string, url string, body io.Reader, subject string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, url, body)
+
+	if subject != "" {
+		c.Set("token", ginkeycloak.KeyCloakToken{Sub: subject})
+	}
+
+	return c, w
+}
+
+// ==================================================================================================
+func TestPantryGet_OK(t *testing.T) {
+	h := NewPantryHandlers(&PantryServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("GET", "http://example.com/api/v2/ingredient/pantry", nil, subjectFound)
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+	expectedBody, _ := json.Marshal([]m.IngredientDTO{ingredient})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestPantryGet_UnauthorizedErr(t *testing.T) {
+	h := NewPantryHandlers(&PantryServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("GET", "http://example.com/api/v2/ingredient/pantry", nil, "")
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, `{"error":"unknown user"}`, string(body))
+}
+
+func TestPantryGet_Err(t *testing.T) {
+	h := NewPantryHandlers(&PantryServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("GET", "http://example.com/api/v2/ingredient/pantry", nil, subjectError)
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, `{"error":"internal server error"}`, string(body))
+}
+
+// ==================================================================================================
+func TestPantryReplace_OK(t *testing.T) {
+	h := NewPantryHandlers(&PantryServiceMock{}, &LoggerInterfaceMock{})
+	requestBody, _ := json.Marshal(m.PantryRequestDTO{IngredientIDs: []uuid.UUID{ingredient.ID}})
+	c, w := newTestContext("PUT", "http://example.com/api/v2/ingredient/pantry", bytes.NewBuffer(requestBody), subjectFound)
+
+	h.Replace(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+	expectedBody, _ := json.Marshal([]m.IngredientDTO{ingredient})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestPantryReplace_UnmarshalErr(t *testing.T) {
+	h := NewPantryHandlers(&PantryServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("PUT", "http://example.com/api/v2/ingredient/pantry", bytes.NewBufferString(`{"ingredient_ids":"rice"}`), subjectFound)
+
+	h.Replace(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"unexpected JSON input"}`, string(body))
+}
+
+func TestPantryReplace_ValidationErr(t *testing.T) {
+	h := NewPantryHandlers(&PantryServiceMock{}, &LoggerInterfaceMock{})
+	requestBody, _ := json.Marshal(m.PantryRequestDTO{IngredientIDs: []uuid.UUID{uuid.New()}})
+	c, w := newTestContext("PUT", "http://example.com/api/v2/ingredient/pantry", bytes.NewBuffer(requestBody), subjectInvalid)
+
+	h.Replace(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"ingredient does not exist"}`, string(body))
+}
+
+// ==================================================================================================
+func TestPantryMatch_OK(t *testing.T) {
+	h := NewPantryHandlers(&PantryServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("POST", "http://example.com/api/v2/ingredient/pantry/match", bytes.NewBufferString(`{"ignore_staples":true,"limit":5}`), subjectFound)
+
+	h.Match(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+	expectedBody, _ := json.Marshal([]m.PantryMatchDTO{pantryMatch})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+	assert.Equal(t, m.PantryMatchRequestDTO{IgnoreStaples: true, Limit: 5}, requestedMatch)
+}
+
+func TestPantryMatch_EmptyBody(t *testing.T) {
+	h := NewPantryHandlers(&PantryServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("POST", "http://example.com/api/v2/ingredient/pantry/match", nil, subjectFound)
+
+	h.Match(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, m.PantryMatchRequestDTO{}, requestedMatch)
+}
+
+func TestPantryMatch_UnmarshalErr(t *testing.T) {
+	h := NewPantryHandlers(&PantryServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("POST", "http://example.com/api/v2/ingredient/pantry/match", bytes.NewBufferString(`{"limit":500}`), subjectFound)
+
+	h.Match(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"unexpected JSON input"}`, string(body))
+}
+
+func TestPantryMatch_UnauthorizedErr(t *testing.T) {
+	h := NewPantryHandlers(&PantryServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("POST", "http://example.com/api/v2/ingredient/pantry/match", nil, "")
+
+	h.Match(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestPantryMatch_Err(t *testing.T) {
+	h := NewPantryHandlers(&PantryServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("POST", "http://example.com/api/v2/ingredient/pantry/match", nil, subjectError)
+
+	h.Match(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, `{"error":"internal server error"}`, string(body))
+}
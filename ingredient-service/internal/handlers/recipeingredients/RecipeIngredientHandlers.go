@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	m "ingredient-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RecipeIngredientService interface {
+	FindByRecipe(recipeID uuid.UUID) ([]m.RecipeIngredientDTO, error)
+	FindByRecipeInSystem(recipeID uuid.UUID, system string) ([]m.RecipeIngredientDTO, error)
+	FindByRecipes(recipeIDs []uuid.UUID) ([]m.RecipeIngredientDTO, error)
+	Create(recipeIngredientDTO m.RecipeIngredientDTO) (m.RecipeIngredientDTO, error)
+	Update(recipeIngredientDTO m.RecipeIngredientDTO) (m.RecipeIngredientDTO, error)
+	Replace(recipeID uuid.UUID, recipeIngredientDTOs []m.RecipeIngredientDTO) ([]m.RecipeIngredientDTO, error)
+	Delete(recipeIngredientDTO m.RecipeIngredientDTO) error
+	DeleteByRecipe(recipeID uuid.UUID) error
+	RestoreByRecipe(recipeID uuid.UUID, since time.Time) error
+	PurgeByRecipe(recipeID uuid.UUID) error
+}
+
+type RecipeIngredientHandlers struct {
+	recipeIngredientService RecipeIngredientService
+	logger                  m.LoggerInterface
+}
+
+func NewRecipeIngredientHandlers(recipeIngredients RecipeIngredientService, logger m.LoggerInterface) *RecipeIngredientHandlers {
+	return &RecipeIngredientHandlers{
+		recipeIngredientService: recipeIngredients,
+		logger:                  logger,
+	}
+}
+
+// Get all ingredient lines of a recipe. With ?system=metric or ?system=imperial the lines also hold their quantity
+// converted into that measurement system.
+func (h RecipeIngredientHandlers) GetByRecipe(ctx *gin.Context) {
+	var recipeIngredientDTOs []m.RecipeIngredientDTO
+
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	if system := ctx.Query("system"); system != "" {
+		recipeIngredientDTOs, err = h.recipeIngredientService.FindByRecipeInSystem(recipeID, system)
+	} else {
+		recipeIngredientDTOs, err = h.recipeIngredientService.FindByRecipe(recipeID)
+	}
+
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "no ingredients found"})
+			return
+		case "unknown measurement system":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, recipeIngredientDTOs)
+}
+
+// Get the ingredient lines of several recipes. The recipes are passed as ?recipe_ids=<id>,<id>
+func (h RecipeIngredientHandlers) GetByRecipes(ctx *gin.Context) {
+	var recipeIDs []uuid.UUID
+
+	for _, value := range strings.Split(ctx.Query("recipe_ids"), ",") {
+		if value == "" {
+			continue
+		}
+
+		recipeID, err := uuid.Parse(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+			return
+		}
+		recipeIDs = append(recipeIDs, recipeID)
+	}
+
+	if len(recipeIDs) <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "no recipe IDs provided"})
+		return
+	}
+
+	recipeIngredientDTOs, err := h.recipeIngredientService.FindByRecipes(recipeIDs)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "no ingredients found"})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, recipeIngredientDTOs)
+}
+
+// Add an ingredient line to a recipe
+func (h RecipeIngredientHandlers) Create(ctx *gin.Context) {
+	var recipeIngredientDTO m.RecipeIngredientDTO
+	var err error
+
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	if err = ctx.ShouldBindJSON(&recipeIngredientDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unexpected JSON input"})
+		return
+	}
+
+	recipeIngredientDTO.RecipeID = recipeID
+
+	recipeIngredientDTO, err = h.recipeIngredientService.Create(recipeIngredientDTO)
+	if err != nil {
+		h.respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, recipeIngredientDTO)
+}
+
+// Replace all ingredient lines of a recipe
+func (h RecipeIngredientHandlers) Replace(ctx *gin.Context) {
+	var recipeIngredientDTOs []m.RecipeIngredientDTO
+	var err error
+
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	if err = ctx.ShouldBindJSON(&recipeIngredientDTOs); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unexpected JSON input"})
+		return
+	}
+
+	recipeIngredientDTOs, err = h.recipeIngredientService.Replace(recipeID, recipeIngredientDTOs)
+	if err != nil {
+		h.respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, recipeIngredientDTOs)
+}
+
+// Update the quantity or unit of an ingredient line
+func (h RecipeIngredientHandlers) Update(ctx *gin.Context) {
+	var recipeIngredientDTO m.RecipeIngredientDTO
+	var err error
+
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	ingredientID, err := uuid.Parse(ctx.Param("ingredientId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ingredient ID"})
+		return
+	}
+
+	if err = ctx.ShouldBindJSON(&recipeIngredientDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unexpected JSON input"})
+		return
+	}
+
+	// the line is identified by the path, ids in the body are ignored
+	recipeIngredientDTO.RecipeID = recipeID
+	recipeIngredientDTO.IngredientID = ingredientID
+
+	recipeIngredientDTO, err = h.recipeIngredientService.Update(recipeIngredientDTO)
+	if err != nil {
+		h.respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, recipeIngredientDTO)
+}
+
+// Remove an ingredient line from a recipe
+func (h RecipeIngredientHandlers) Delete(ctx *gin.Context) {
+	var recipeIngredientDTO m.RecipeIngredientDTO
+	var err error
+
+	recipeIngredientDTO.RecipeID, err = uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	recipeIngredientDTO.IngredientID, err = uuid.Parse(ctx.Param("ingredientId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ingredient ID"})
+		return
+	}
+
+	err = h.recipeIngredientService.Delete(recipeIngredientDTO)
+	if err != nil {
+		h.respondWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// Delete all ingredient lines of a deleted recipe
+func (h RecipeIngredientHandlers) DeleteByRecipe(ctx *gin.Context) {
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	if err = h.recipeIngredientService.DeleteByRecipe(recipeID); err != nil {
+		h.respondWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// Restore the ingredient lines of a restored recipe. The optional since parameter limits the restore to the lines
+// deleted along with the recipe
+func (h RecipeIngredientHandlers) RestoreByRecipe(ctx *gin.Context) {
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	var since time.Time
+	if value := ctx.Query("since"); value != "" {
+		if since, err = time.Parse(time.RFC3339, value); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid since parameter"})
+			return
+		}
+	}
+
+	if err = h.recipeIngredientService.RestoreByRecipe(recipeID, since); err != nil {
+		h.respondWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// Purge the ingredient lines of a deleted recipe that can no longer be restored
+func (h RecipeIngredientHandlers) PurgeByRecipe(ctx *gin.Context) {
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	if err = h.recipeIngredientService.PurgeByRecipe(recipeID); err != nil {
+		h.respondWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (h RecipeIngredientHandlers) respondWithError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case "recipe ingredient does not exist. nothing to update", "recipe ingredient does not exist. nothing to delete":
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "ingredient already part of recipe":
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case "invalid recipe ID", "quantity must be greater than zero", "maximum quantity must be greater than the quantity",
+		"invalid amount", "amount is empty", "ingredient does not exist", "unit does not exist", "duplicate ingredient in list":
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,557 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	m "ingredient-service/internal/models"
+	s "ingredient-service/internal/services/recipeingredients"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type RecipeIngredientServiceMock struct{}
+
+var (
+	recipeFound    uuid.UUID = uuid.New()
+	recipeNotFound uuid.UUID = uuid.New()
+	recipeInvalid  uuid.UUID = uuid.New()
+	recipeError    uuid.UUID = uuid.New()
+
+	recipeIngredient m.RecipeIngredientDTO = m.RecipeIngredientDTO{
+		RecipeID:       recipeFound,
+		IngredientID:   uuid.New(),
+		IngredientName: "ingredient",
+		Quantity:       2,
+		Amount:         "2",
+		Unit:           m.UnitDTO{ID: uuid.New(), FullName: "gram", ShortName: "g"},
+	}
+
+	requestedRecipeIDs []uuid.UUID
+)
+
+func mockResult(recipeID uuid.UUID) error {
+	switch recipeID {
+	case recipeFound:
+		return nil
+	case recipeNotFound:
+		return errors.New("not found")
+	case recipeInvalid:
+		return errors.New("unit does not exist")
+	default:
+		return errors.New("error")
+	}
+}
+
+func (s *RecipeIngredientServiceMock) FindByRecipe(recipeID uuid.UUID) ([]m.RecipeIngredientDTO, error) {
+	if err := mockResult(recipeID); err != nil {
+		return nil, err
+	}
+	return []m.RecipeIngredientDTO{recipeIngredient}, nil
+}
+
+func (s *RecipeIngredientServiceMock) FindByRecipeInSystem(recipeID uuid.UUID, system string) ([]m.RecipeIngredientDTO, error) {
+	if system != m.SystemMetric && system != m.SystemImperial {
+		return nil, errors.New("unknown measurement system")
+	}
+	if err := mockResult(recipeID); err != nil {
+		return nil, err
+	}
+
+	converted := recipeIngredient
+	converted.Converted = &m.ConversionDTO{Quantity: 0.07, Amount: "⅛", Unit: m.UnitDTO{ID: uuid.New(), FullName: "ounce", ShortName: "oz"}}
+	return []m.RecipeIngredientDTO{converted}, nil
+}
+
+func (s *RecipeIngredientServiceMock) FindByRecipes(recipeIDs []uuid.UUID) ([]m.RecipeIngredientDTO, error) {
+	requestedRecipeIDs = recipeIDs
+	return s.FindByRecipe(recipeIDs[0])
+}
+
+func (s *RecipeIngredientServiceMock) Create(recipeIngredientDTO m.RecipeIngredientDTO) (m.RecipeIngredientDTO, error) {
+	if err := mockResult(recipeIngredientDTO.RecipeID); err != nil {
+		return m.RecipeIngredientDTO{}, err
+	}
+	return recipeIngredient, nil
+}
+
+func (s *RecipeIngredientServiceMock) Update(recipeIngredientDTO m.RecipeIngredientDTO) (m.RecipeIngredientDTO, error) {
+	if recipeIngredientDTO.RecipeID == recipeNotFound {
+		return m.RecipeIngredientDTO{}, errors.New("recipe ingredient does not exist. nothing to update")
+	}
+	if err := mockResult(recipeIngredientDTO.RecipeID); err != nil {
+		return m.RecipeIngredientDTO{}, err
+	}
+	return recipeIngredientDTO, nil
+}
+
+func (s *RecipeIngredientServiceMock) DeleteByRecipe(recipeID uuid.UUID) error {
+	return mockResult(recipeID)
+}
+
+func (s *RecipeIngredientServiceMock) RestoreByRecipe(recipeID uuid.UUID, since time.Time) error {
+	if !since.IsZero() && since.Year() != 2024 {
+		return errors.New("unexpected since")
+	}
+	return mockResult(recipeID)
+}
+
+func (s *RecipeIngredientServiceMock) PurgeByRecipe(recipeID uuid.UUID) error {
+	return mockResult(recipeID)
+}
+
+func (s *RecipeIngredientServiceMock) Replace(recipeID uuid.UUID, recipeIngredientDTOs []m.RecipeIngredientDTO) ([]m.RecipeIngredientDTO, error) {
+	if err := mockResult(recipeID); err != nil {
+		return nil, err
+	}
+	return recipeIngredientDTOs, nil
+}
+
+func (s *RecipeIngredientServiceMock) Delete(recipeIngredientDTO m.RecipeIngredientDTO) error {
+	if recipeIngredientDTO.RecipeID == recipeNotFound {
+		return errors.New("recipe ingredient does not exist. nothing to delete")
+	}
+	return mockResult(recipeIngredientDTO.RecipeID)
+}
+
+type LoggerInterfaceMock struct{}
+
+func (l *LoggerInterfaceMock) Debugf(format string, args ...interface{}) {}
+func (l *LoggerInterfaceMock) Warnf(format string, args ...interface{})  {}
+
+func newTestContext(method string, url string, body io.Reader, params gin.Params) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, url, body)
+	c.Params = params
+
+	return c, w
+}
+
+// ==================================================================================================
+func TestRecipeIngredientGetByRecipe_OK(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("GET", "http://example.com/api/v2/ingredient/recipe/1", nil, gin.Params{
+		gin.Param{Key: "id", Value: recipeFound.String()},
+	})
+
+	h.GetByRecipe(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+	expectedBody, _ := json.Marshal([]m.RecipeIngredientDTO{recipeIngredient})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestRecipeIngredientGetByRecipe_System(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("GET", "http://example.com/api/v2/ingredient/recipe/1?system=imperial", nil, gin.Params{
+		gin.Param{Key: "id", Value: recipeFound.String()},
+	})
+
+	h.GetByRecipe(c)
+
+	resp := w.Result()
+
+	var result []m.RecipeIngredientDTO
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, float64(2), result[0].Quantity)
+	assert.Equal(t, 0.07, result[0].Converted.Quantity)
+	assert.Equal(t, "⅛", result[0].Converted.Amount)
+	assert.Equal(t, "oz", result[0].Converted.Unit.ShortName)
+}
+
+func TestRecipeIngredientGetByRecipe_SystemErr(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("GET", "http://example.com/api/v2/ingredient/recipe/1?system=nautical", nil, gin.Params{
+		gin.Param{Key: "id", Value: recipeFound.String()},
+	})
+
+	h.GetByRecipe(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"unknown measurement system"}`, string(body))
+}
+
+func TestRecipeIngredientGetByRecipe_IDErr(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("GET", "http://example.com/api/v2/ingredient/recipe/1", nil, gin.Params{
+		gin.Param{Key: "id", Value: "1"},
+	})
+
+	h.GetByRecipe(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid recipe ID"}`, string(body))
+}
+
+func TestRecipeIngredientGetByRecipe_NotFound(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("GET", "http://example.com/api/v2/ingredient/recipe/1", nil, gin.Params{
+		gin.Param{Key: "id", Value: recipeNotFound.String()},
+	})
+
+	h.GetByRecipe(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"no ingredients found"}`, string(body))
+}
+
+func TestRecipeIngredientGetByRecipe_Err(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("GET", "http://example.com/api/v2/ingredient/recipe/1", nil, gin.Params{
+		gin.Param{Key: "id", Value: recipeError.String()},
+	})
+
+	h.GetByRecipe(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestRecipeIngredientGetByRecipes_OK(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	otherRecipeID := uuid.New()
+	c, w := newTestContext("GET", "http://example.com/api/v2/ingredient/recipe?recipe_ids="+recipeFound.String()+","+otherRecipeID.String(), nil, nil)
+
+	h.GetByRecipes(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []uuid.UUID{recipeFound, otherRecipeID}, requestedRecipeIDs)
+}
+
+func TestRecipeIngredientGetByRecipes_IDErr(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("GET", "http://example.com/api/v2/ingredient/recipe?recipe_ids="+recipeFound.String()+",1", nil, nil)
+
+	h.GetByRecipes(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid recipe ID"}`, string(body))
+}
+
+func TestRecipeIngredientGetByRecipes_MissingErr(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("GET", "http://example.com/api/v2/ingredient/recipe", nil, nil)
+
+	h.GetByRecipes(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"no recipe IDs provided"}`, string(body))
+}
+
+func TestRecipeIngredientCreate_OK(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	reqBody, _ := json.Marshal(recipeIngredient)
+	c, w := newTestContext("POST", "http://example.com/api/v2/ingredient/recipe/1", bytes.NewReader(reqBody), gin.Params{
+		gin.Param{Key: "id", Value: recipeFound.String()},
+	})
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+	expectedBody, _ := json.Marshal(recipeIngredient)
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestRecipeIngredientCreate_UnmarshalErr(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("POST", "http://example.com/api/v2/ingredient/recipe/1", bytes.NewReader([]byte(`{"Quantity": "two"}`)), gin.Params{
+		gin.Param{Key: "id", Value: recipeFound.String()},
+	})
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"unexpected JSON input"}`, string(body))
+}
+
+func TestRecipeIngredientCreate_ValidationErr(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	reqBody, _ := json.Marshal(recipeIngredient)
+	c, w := newTestContext("POST", "http://example.com/api/v2/ingredient/recipe/1", bytes.NewReader(reqBody), gin.Params{
+		gin.Param{Key: "id", Value: recipeInvalid.String()},
+	})
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"unit does not exist"}`, string(body))
+}
+
+func TestRecipeIngredientReplace_OK(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	reqBody, _ := json.Marshal([]m.RecipeIngredientDTO{recipeIngredient, recipeIngredient})
+	c, w := newTestContext("PUT", "http://example.com/api/v2/ingredient/recipe/1", bytes.NewReader(reqBody), gin.Params{
+		gin.Param{Key: "id", Value: recipeFound.String()},
+	})
+
+	h.Replace(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, reqBody, body)
+}
+
+func TestRecipeIngredientReplace_Err(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("PUT", "http://example.com/api/v2/ingredient/recipe/1", bytes.NewReader([]byte(`[]`)), gin.Params{
+		gin.Param{Key: "id", Value: recipeError.String()},
+	})
+
+	h.Replace(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, `{"error":"error"}`, string(body))
+}
+
+func TestRecipeIngredientUpdate_OK(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	ingredientID := uuid.New()
+	reqBody, _ := json.Marshal(m.RecipeIngredientDTO{IngredientID: uuid.New(), Quantity: 5})
+	c, w := newTestContext("PUT", "http://example.com/api/v2/ingredient/recipe/1/2", bytes.NewReader(reqBody), gin.Params{
+		gin.Param{Key: "id", Value: recipeFound.String()},
+		gin.Param{Key: "ingredientId", Value: ingredientID.String()},
+	})
+
+	h.Update(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	var result m.RecipeIngredientDTO
+	json.Unmarshal(body, &result)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, ingredientID, result.IngredientID)
+	assert.Equal(t, float64(5), result.Quantity)
+}
+
+// RecipeIngredientRepositoryMock only finds the line being updated, the other methods are left unimplemented
+type RecipeIngredientRepositoryMock struct {
+	s.RecipeIngredientRepository
+}
+
+func (r *RecipeIngredientRepositoryMock) FindSingle(recipeIngredient m.RecipeIngredient) (m.RecipeIngredient, error) {
+	return recipeIngredient, nil
+}
+
+func TestRecipeIngredientUpdate_AmountErr(t *testing.T) {
+	// the actual service, so the malformed amount is rejected by its validation rather than by a mock
+	service := s.NewRecipeIngredientService(&RecipeIngredientRepositoryMock{}, nil, nil)
+	h := NewRecipeIngredientHandlers(service, &LoggerInterfaceMock{})
+	reqBody, _ := json.Marshal(m.RecipeIngredientDTO{IngredientID: uuid.New(), Amount: "a few"})
+	c, w := newTestContext("PUT", "http://example.com/api/v2/ingredient/recipe/1/2", bytes.NewReader(reqBody), gin.Params{
+		gin.Param{Key: "id", Value: recipeFound.String()},
+		gin.Param{Key: "ingredientId", Value: uuid.New().String()},
+	})
+
+	h.Update(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid amount"}`, string(body))
+}
+
+func TestRecipeIngredientUpdate_IngredientIDErr(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("PUT", "http://example.com/api/v2/ingredient/recipe/1/2", nil, gin.Params{
+		gin.Param{Key: "id", Value: recipeFound.String()},
+		gin.Param{Key: "ingredientId", Value: "2"},
+	})
+
+	h.Update(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid ingredient ID"}`, string(body))
+}
+
+func TestRecipeIngredientUpdate_NotFound(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	reqBody, _ := json.Marshal(m.RecipeIngredientDTO{Quantity: 5})
+	c, w := newTestContext("PUT", "http://example.com/api/v2/ingredient/recipe/1/2", bytes.NewReader(reqBody), gin.Params{
+		gin.Param{Key: "id", Value: recipeNotFound.String()},
+		gin.Param{Key: "ingredientId", Value: uuid.New().String()},
+	})
+
+	h.Update(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestRecipeIngredientDelete_OK(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("DELETE", "http://example.com/api/v2/ingredient/recipe/1/2", nil, gin.Params{
+		gin.Param{Key: "id", Value: recipeFound.String()},
+		gin.Param{Key: "ingredientId", Value: uuid.New().String()},
+	})
+
+	h.Delete(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestRecipeIngredientDelete_NotFound(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("DELETE", "http://example.com/api/v2/ingredient/recipe/1/2", nil, gin.Params{
+		gin.Param{Key: "id", Value: recipeNotFound.String()},
+		gin.Param{Key: "ingredientId", Value: uuid.New().String()},
+	})
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"recipe ingredient does not exist. nothing to delete"}`, string(body))
+}
+
+func TestRecipeIngredientDeleteByRecipe_OK(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("DELETE", "http://example.com/api/v2/ingredient/recipe/1", nil, gin.Params{
+		gin.Param{Key: "id", Value: recipeFound.String()},
+	})
+
+	h.DeleteByRecipe(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestRecipeIngredientDeleteByRecipe_InvalidID(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("DELETE", "http://example.com/api/v2/ingredient/recipe/1", nil, gin.Params{
+		gin.Param{Key: "id", Value: "invalid"},
+	})
+
+	h.DeleteByRecipe(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid recipe ID"}`, string(body))
+}
+
+func TestRecipeIngredientDeleteByRecipe_Err(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("DELETE", "http://example.com/api/v2/ingredient/recipe/1", nil, gin.Params{
+		gin.Param{Key: "id", Value: recipeError.String()},
+	})
+
+	h.DeleteByRecipe(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestRecipeIngredientRestoreByRecipe_OK(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("POST", "http://example.com/api/v2/ingredient/recipe/1/restore?since=2024-05-01T10:00:00Z", nil, gin.Params{
+		gin.Param{Key: "id", Value: recipeFound.String()},
+	})
+
+	h.RestoreByRecipe(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestRecipeIngredientRestoreByRecipe_InvalidSince(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("POST", "http://example.com/api/v2/ingredient/recipe/1/restore?since=yesterday", nil, gin.Params{
+		gin.Param{Key: "id", Value: recipeFound.String()},
+	})
+
+	h.RestoreByRecipe(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid since parameter"}`, string(body))
+}
+
+func TestRecipeIngredientPurgeByRecipe_OK(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("POST", "http://example.com/api/v2/ingredient/recipe/1/purge", nil, gin.Params{
+		gin.Param{Key: "id", Value: recipeFound.String()},
+	})
+
+	h.PurgeByRecipe(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestRecipeIngredientPurgeByRecipe_Err(t *testing.T) {
+	h := NewRecipeIngredientHandlers(&RecipeIngredientServiceMock{}, &LoggerInterfaceMock{})
+	c, w := newTestContext("POST", "http://example.com/api/v2/ingredient/recipe/1/purge", nil, gin.Params{
+		gin.Param{Key: "id", Value: recipeError.String()},
+	})
+
+	h.PurgeByRecipe(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
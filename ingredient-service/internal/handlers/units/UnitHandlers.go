@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	m "ingredient-service/internal/models"
+	"ingredient-service/internal/quantity"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -15,6 +17,8 @@ type UnitService interface {
 	Create(unitDTO m.UnitDTO) (m.UnitDTO, error)
 	Update(unitDTO m.UnitDTO) (m.UnitDTO, error)
 	Delete(unitDTO m.UnitDTO) error
+	Reassign(unitDTO m.UnitDTO, replacementDTO m.UnitDTO) error
+	Convert(request m.ConversionRequest) (m.ConversionDTO, error)
 }
 
 type UnitHandlers struct {
@@ -87,8 +91,14 @@ func (h UnitHandlers) Create(ctx *gin.Context) {
 
 	unitDTO, err = h.unitService.Create(unitDTO)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		switch err.Error() {
+		case "unknown dimension", "unknown measurement system", "factor must be greater than zero":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	ctx.JSON(http.StatusCreated, unitDTO)
@@ -115,8 +125,14 @@ func (h UnitHandlers) Update(ctx *gin.Context) {
 
 	unitDTO, err = h.unitService.Update(unitDTO)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		switch err.Error() {
+		case "unknown dimension", "unknown measurement system", "factor must be greater than zero":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	ctx.JSON(http.StatusOK, unitDTO)
@@ -133,11 +149,86 @@ func (h UnitHandlers) Delete(ctx *gin.Context) {
 		return
 	}
 
-	err = h.unitService.Delete(unitDTO)
+	// with reassignTo the references are moved to the replacement, instead of refusing the delete
+	if reassignTo := ctx.Query("reassignTo"); reassignTo != "" {
+		var replacementDTO m.UnitDTO
+
+		replacementDTO.ID, err = uuid.Parse(reassignTo)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid reassignTo parameter"})
+			return
+		}
+
+		err = h.unitService.Reassign(unitDTO, replacementDTO)
+	} else {
+		err = h.unitService.Delete(unitDTO)
+	}
+
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		var inUse m.InUseError
+		switch {
+		case errors.As(err, &inUse):
+			ctx.JSON(http.StatusConflict, inUse.ConvertToDTO())
+			return
+		case err.Error() == "replacement not found", err.Error() == "unit cannot be reassigned to itself",
+			err.Error() == "unit has no dimension", err.Error() == "units cannot be converted", err.Error() == "density is unknown":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	ctx.Status(http.StatusOK)
 }
+
+// Convert a quantity, passed as ?quantity=1&from=cup, into another unit with &to=g or into the unit it reads best in
+// within a measurement system with &system=metric. The quantity may be written as 1.5, 1 1/2 or 1½. With
+// &ingredient=<id> the density of the ingredient is used to convert between volume and weight.
+func (h UnitHandlers) Convert(ctx *gin.Context) {
+	var request m.ConversionRequest
+	var err error
+
+	amount, err := quantity.Parse(ctx.Query("quantity"))
+	if err != nil || amount.Min.IsZero() || !amount.Max.IsZero() {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid quantity parameter"})
+		return
+	}
+	request.Quantity = amount.Min.Float()
+
+	request.From = ctx.Query("from")
+	if request.From == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "from is required"})
+		return
+	}
+
+	request.To = ctx.Query("to")
+	request.System = ctx.Query("system")
+
+	if ingredient := ctx.Query("ingredient"); ingredient != "" {
+		request.IngredientID, err = uuid.Parse(ingredient)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ingredient parameter"})
+			return
+		}
+	}
+
+	conversionDTO, err := h.unitService.Convert(request)
+	if err != nil {
+		switch err.Error() {
+		case "unit not found", "ingredient not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case "either a unit or a measurement system to convert into is required", "unknown measurement system",
+			"unit has no dimension", "units cannot be converted", "density is unknown", "no unit to convert to":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, conversionDTO)
+}
@@ -55,6 +55,8 @@ func (s *UnitServiceMock) Create(unitDTO m.UnitDTO) (m.UnitDTO, error) {
 	switch unitDTO.FullName {
 	case "create":
 		return unit, nil
+	case "dimension":
+		return m.UnitDTO{}, errors.New("unknown dimension")
 	default:
 		return unit, errors.New("error")
 	}
@@ -73,11 +75,43 @@ func (s *UnitServiceMock) Delete(unitDTO m.UnitDTO) error {
 	switch unit.FullName {
 	case "delete":
 		return nil
+	case "inuse":
+		return m.NewInUseError("unit", inUseUsage)
 	default:
 		return errors.New("error")
 	}
 }
 
+func (s *UnitServiceMock) Reassign(unitDTO m.UnitDTO, replacementDTO m.UnitDTO) error {
+	switch unit.FullName {
+	case "reassign":
+		return nil
+	case "noreplacement":
+		return errors.New("replacement not found")
+	case "nodensity":
+		return errors.New("density is unknown")
+	default:
+		return errors.New("error")
+	}
+}
+
+func (s *UnitServiceMock) Convert(request m.ConversionRequest) (m.ConversionDTO, error) {
+	switch request.From {
+	case "cup":
+		return m.ConversionDTO{Quantity: request.Quantity * 236.5882365, Unit: unit}, nil
+	case "handful":
+		return m.ConversionDTO{}, errors.New("unit not found")
+	case "piece":
+		return m.ConversionDTO{}, errors.New("units cannot be converted")
+	default:
+		return m.ConversionDTO{}, errors.New("error")
+	}
+}
+
+var (
+	inUseUsage m.Usage = m.Usage{Count: 12, RecipeIDs: []uuid.UUID{uuid.New(), uuid.New()}}
+)
+
 type LoggerInterfaceMock struct{}
 
 func (l *LoggerInterfaceMock) Debugf(format string, args ...interface{}) {}
@@ -455,3 +489,268 @@ func TestUnitDelete_DeleteErr(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	assert.Equal(t, []byte(`{"error":"error"}`), body)
 }
+
+func TestUnitDelete_InUseErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewUnitHandlers(&UnitServiceMock{}, &LoggerInterfaceMock{})
+
+	unit.FullName = "inuse"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/unit/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: unit.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(m.InUseDTO{Error: "unit is used by 12 recipes", Count: 12, RecipeIDs: inUseUsage.RecipeIDs, DeletedRecipeIDs: []uuid.UUID{}})
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestUnitDelete_ReassignOK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewUnitHandlers(&UnitServiceMock{}, &LoggerInterfaceMock{})
+
+	unit.FullName = "reassign"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/unit/1?reassignTo="+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: unit.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestUnitDelete_ReassignInvalidErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewUnitHandlers(&UnitServiceMock{}, &LoggerInterfaceMock{})
+
+	unit.FullName = "reassign"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/unit/1?reassignTo=1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: unit.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"invalid reassignTo parameter"}`), body)
+}
+
+func TestUnitDelete_ReassignNotFoundErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewUnitHandlers(&UnitServiceMock{}, &LoggerInterfaceMock{})
+
+	unit.FullName = "noreplacement"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/unit/1?reassignTo="+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: unit.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"replacement not found"}`), body)
+}
+
+func TestUnitDelete_ReassignConversionErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewUnitHandlers(&UnitServiceMock{}, &LoggerInterfaceMock{})
+
+	unit.FullName = "nodensity"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/unit/1?reassignTo="+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: unit.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"density is unknown"}`), body)
+}
+
+func TestUnitCreate_DimensionErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewUnitHandlers(&UnitServiceMock{}, &LoggerInterfaceMock{})
+
+	reqBody, _ := json.Marshal(m.UnitDTO{FullName: "dimension"})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/unit", bytes.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"unknown dimension"}`, string(body))
+}
+
+func TestUnitConvert_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewUnitHandlers(&UnitServiceMock{}, &LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/unit/convert?quantity=2&from=cup&to=ml", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Convert(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+	assertBody, _ := json.Marshal(m.ConversionDTO{Quantity: 2 * 236.5882365, Unit: unit})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, assertBody, body)
+}
+
+func TestUnitConvert_FractionOK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewUnitHandlers(&UnitServiceMock{}, &LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/unit/convert?quantity=1%C2%BD&from=cup&to=ml", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Convert(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+	assertBody, _ := json.Marshal(m.ConversionDTO{Quantity: 1.5 * 236.5882365, Unit: unit})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, assertBody, body)
+}
+
+func TestUnitConvert_QuantityErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewUnitHandlers(&UnitServiceMock{}, &LoggerInterfaceMock{})
+
+	for _, quantity := range []string{"some", "2-3", "to+taste"} {
+		req := httptest.NewRequest("GET", "http://example.com/api/v2/unit/convert?quantity="+quantity+"&from=cup&to=ml", nil)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+
+		h.Convert(c)
+
+		resp := w.Result()
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, quantity)
+		assert.Equal(t, `{"error":"invalid quantity parameter"}`, string(body), quantity)
+	}
+}
+
+func TestUnitConvert_FromRequiredErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewUnitHandlers(&UnitServiceMock{}, &LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/unit/convert?quantity=1&to=ml", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Convert(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"from is required"}`, string(body))
+}
+
+func TestUnitConvert_IngredientErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewUnitHandlers(&UnitServiceMock{}, &LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/unit/convert?quantity=1&from=cup&to=g&ingredient=flour", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Convert(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid ingredient parameter"}`, string(body))
+}
+
+func TestUnitConvert_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewUnitHandlers(&UnitServiceMock{}, &LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/unit/convert?quantity=1&from=handful&to=g", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Convert(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"unit not found"}`, string(body))
+}
+
+func TestUnitConvert_ConvertErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewUnitHandlers(&UnitServiceMock{}, &LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/unit/convert?quantity=1&from=piece&to=g", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Convert(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"units cannot be converted"}`, string(body))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	c "ingredient-service/internal/config"
 	m "ingredient-service/internal/middleware"
+	"ingredient-service/internal/routes"
 	"net/http"
 	"time"
 
@@ -29,64 +30,16 @@ func IngredientService(ctx context.Context) {
 	// Cors handler
 	router.Use(cors.New(c.Cors))
 
-	v1 := router.Group("/api/v2")
-	{
-		ingredient := v1.Group("/ingredient")
-		{
-			readIngredient := ingredient.Group("")
-			readIngredient.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
-			{
-				readIngredient.GET("", c.IngredientHandlers.GetAll)
-				readIngredient.GET(":id", c.IngredientHandlers.GetSingle)
-			}
+	// Routes
+	routes.Register(router, ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build(), routes.Handlers{
+		Ingredient:       c.IngredientHandlers,
+		Unit:             c.UnitHandlers,
+		RecipeIngredient: c.RecipeIngredientHandlers,
+		Pantry:           c.PantryHandlers,
+	})
 
-			createIngredient := ingredient.Group("")
-			readIngredient.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
-			{
-				createIngredient.POST("", c.IngredientHandlers.Create)
-			}
-
-			updateIngredient := ingredient.Group("")
-			readIngredient.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
-			{
-				updateIngredient.PUT(":id", c.IngredientHandlers.Update)
-			}
-
-			adminIngredient := ingredient.Group("")
-			readIngredient.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
-			{
-				adminIngredient.DELETE(":id", c.IngredientHandlers.Delete)
-			}
-		}
-
-		unit := v1.Group("/unit")
-		{
-			readUnit := unit.Group("")
-			readUnit.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
-			{
-				readUnit.GET("", c.UnitHandlers.GetAll)
-				readUnit.GET(":id", c.UnitHandlers.GetSingle)
-			}
-
-			createUnit := unit.Group("")
-			createUnit.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
-			{
-				createUnit.POST("", c.UnitHandlers.Create)
-			}
-
-			updateUnit := unit.Group("")
-			updateUnit.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
-			{
-				updateUnit.PUT(":id", c.UnitHandlers.Update)
-			}
-
-			deleteUnit := unit.Group("")
-			deleteUnit.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
-			{
-				deleteUnit.DELETE(":id", c.UnitHandlers.Delete)
-			}
-		}
-	}
+	// Outbox relay
+	go c.Relay.Run(ctx, c.RelayInterval)
 
 	// Server startup
 	srv := &http.Server{
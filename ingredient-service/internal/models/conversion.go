@@ -0,0 +1,21 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// ConversionRequest asks to convert a quantity either into the unit To or into the best reading unit of System
+type ConversionRequest struct {
+	Quantity     float64
+	From         string    // the ID or a name of the unit the quantity is in
+	To           string    // the ID or a name of the unit to convert into
+	System       string    // the measurement system to convert into
+	IngredientID uuid.UUID // optional, the density of the ingredient allows converting between volume and weight
+}
+
+type ConversionDTO struct {
+	Quantity    float64 `json:"quantity" example:"120"`
+	MaxQuantity float64 `json:"max_quantity,omitempty" example:"180"`
+	Amount      string  `json:"amount,omitempty" example:"120–180"` // the quantity as a cook would write it in the unit's system
+	Unit        UnitDTO `json:"unit"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"cookbook/pkg/outbox"
+
+	"github.com/google/uuid"
+)
+
+// The kinds of changes that are reported
+const (
+	EventCreated = "created"
+	EventUpdated = "updated"
+	EventDeleted = "deleted"
+)
+
+// The entities this service reports changes on. The ingredients of a recipe
+// are reported as a whole, by the ID of the recipe.
+const (
+	EntityIngredient        = "ingredient"
+	EntityRecipeIngredients = "recipe_ingredients"
+	EntityUnit              = "unit"
+)
+
+// ChangeEvent tells other services, such as the search service, that an entity was created, updated or deleted
+type ChangeEvent = outbox.ChangeEvent
+
+func NewChangeEvent(eventType string, entityType string, entityID uuid.UUID) ChangeEvent {
+	return outbox.NewChangeEvent(eventType, entityType, entityID)
+}
@@ -3,17 +3,24 @@ package models
 import (
 	"time"
 
+	"cookbook/pkg/normalize"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 // Ingredient struct to hold ingredient data
 type Ingredient struct {
-	ID        uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	Name      string         `gorm:"unique; not null" json:"IngredientName"`
-	CreatedAt time.Time      `gorm:"autoCreateTime"`
-	UpdatedAt time.Time      `gorm:"autoUpdateTime"`
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	ID             uuid.UUID         `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Name           string            `gorm:"unique; not null" json:"IngredientName"`
+	NormalizedName string            `gorm:"not null;default:'';uniqueIndex:idx_ingredients_normalized_name_active,where:deleted_at IS NULL AND normalized_name <> ''"` // the name as matched, see normalize.Name, unique among the ones not deleted
+	Plural         string            `gorm:"not null;default:''"`
+	Staple         bool              `gorm:"not null;default:false" json:"Staple"` // staples such as salt or water are assumed to be at hand
+	Density        float64           `gorm:"not null;default:0"`                   // grams per millilitre, 0 when unknown
+	Aliases        []IngredientAlias `gorm:"foreignKey:IngredientID"`
+	CreatedAt      time.Time         `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time         `gorm:"autoUpdateTime"`
+	DeletedAt      gorm.DeletedAt    `gorm:"index"`
 }
 
 func (ingredient *Ingredient) BeforeCreate(tx *gorm.DB) (err error) {
@@ -22,10 +29,19 @@ func (ingredient *Ingredient) BeforeCreate(tx *gorm.DB) (err error) {
 }
 
 func (i Ingredient) ConvertToDTO() IngredientDTO {
-	return IngredientDTO{
-		ID:   i.ID,
-		Name: i.Name,
+	dto := IngredientDTO{
+		ID:      i.ID,
+		Name:    i.Name,
+		Plural:  i.Plural,
+		Staple:  i.Staple,
+		Density: i.Density,
+	}
+
+	for _, alias := range i.Aliases {
+		dto.Aliases = append(dto.Aliases, alias.Name)
 	}
+
+	return dto
 }
 
 func (c Ingredient) ConvertAllToDTO(ingredients []Ingredient) []IngredientDTO {
@@ -39,15 +55,42 @@ func (c Ingredient) ConvertAllToDTO(ingredients []Ingredient) []IngredientDTO {
 }
 
 type IngredientDTO struct {
-	ID   uuid.UUID `json:"id" example:"23582396-12a3-425b-a597-8a22052823da"`
-	Name string    `json:"name" example:"asparagus"`
+	ID      uuid.UUID `json:"id" example:"23582396-12a3-425b-a597-8a22052823da"`
+	Name    string    `json:"name" example:"egg"`
+	Plural  string    `json:"plural,omitempty" example:"eggs"`
+	Aliases []string  `json:"aliases,omitempty"` // on update the aliases are kept when omitted, and replaced otherwise
+	Staple  bool      `json:"staple" example:"false"`
+	Density float64   `json:"density,omitempty" example:"0.51"` // grams per millilitre, used to convert volumes to weights
 }
 
+// ConvertFromDTO converts the DTO, leaving out the aliases that match the name or another alias
 func (i IngredientDTO) ConvertFromDTO() Ingredient {
-	return Ingredient{
-		ID:   i.ID,
-		Name: i.Name,
+	ingredient := Ingredient{
+		ID:             i.ID,
+		Name:           i.Name,
+		NormalizedName: normalize.Name(i.Name),
+		Plural:         i.Plural,
+		Staple:         i.Staple,
+		Density:        i.Density,
 	}
+
+	if i.Aliases == nil {
+		return ingredient
+	}
+
+	ingredient.Aliases = []IngredientAlias{}
+	seen := map[string]bool{ingredient.NormalizedName: true}
+	for _, name := range i.Aliases {
+		alias := NewIngredientAlias(name, i.ID)
+		if alias.NormalizedName == "" || seen[alias.NormalizedName] {
+			continue
+		}
+
+		seen[alias.NormalizedName] = true
+		ingredient.Aliases = append(ingredient.Aliases, alias)
+	}
+
+	return ingredient
 }
 
 func (c IngredientDTO) ConvertAllFromDTO(ingredientDTOs []IngredientDTO) []Ingredient {
@@ -59,3 +102,20 @@ func (c IngredientDTO) ConvertAllFromDTO(ingredientDTOs []IngredientDTO) []Ingre
 
 	return data
 }
+
+// IngredientAlias is another name an ingredient is known by, such as the name of a duplicate merged into it. As
+// it is keyed by the normalized name, a name can point to one ingredient only.
+type IngredientAlias struct {
+	NormalizedName string    `gorm:"primaryKey"`
+	Name           string    `gorm:"not null"`
+	IngredientID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+}
+
+func NewIngredientAlias(name string, ingredientID uuid.UUID) IngredientAlias {
+	return IngredientAlias{
+		NormalizedName: normalize.Name(name),
+		Name:           name,
+		IngredientID:   ingredientID,
+	}
+}
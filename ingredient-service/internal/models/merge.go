@@ -0,0 +1,51 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// MergeRequestDTO asks to fold duplicate ingredients into the one that is kept
+type MergeRequestDTO struct {
+	TargetID  uuid.UUID   `json:"target_id" binding:"required" example:"23582396-12a3-425b-a597-8a22052823da"`
+	SourceIDs []uuid.UUID `json:"source_ids" binding:"required,min=1"`
+}
+
+// MergeResult describes a merge, or what a merge would do when previewed
+type MergeResult struct {
+	Target    Ingredient
+	Sources   []Ingredient
+	Aliases   []string    // the names of the sources, kept as aliases of the target
+	RecipeIDs []uuid.UUID // the recipes whose ingredient lines are moved to the target
+	Conflicts []uuid.UUID // the recipes listing more than one of the merged ingredients, which block the merge
+}
+
+func (r MergeResult) ConvertToDTO(preview bool) MergeResultDTO {
+	dto := MergeResultDTO{
+		Target:    r.Target.ConvertToDTO(),
+		Merged:    []uuid.UUID{},
+		Aliases:   []string{},
+		RecipeIDs: []uuid.UUID{},
+		Conflicts: []uuid.UUID{},
+		Preview:   preview,
+	}
+
+	for _, source := range r.Sources {
+		dto.Merged = append(dto.Merged, source.ID)
+	}
+
+	dto.Aliases = append(dto.Aliases, r.Aliases...)
+	dto.RecipeIDs = append(dto.RecipeIDs, r.RecipeIDs...)
+	dto.Conflicts = append(dto.Conflicts, r.Conflicts...)
+
+	return dto
+}
+
+// MergeResultDTO reports a merge. With preview set nothing was changed yet.
+type MergeResultDTO struct {
+	Target    IngredientDTO `json:"target"`
+	Merged    []uuid.UUID   `json:"merged"`
+	Aliases   []string      `json:"aliases" example:"tomatoes"`
+	RecipeIDs []uuid.UUID   `json:"recipe_ids"`
+	Conflicts []uuid.UUID   `json:"conflicts"`
+	Preview   bool          `json:"preview" example:"true"`
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PantryItem is an ingredient a user has at home. Users are identified by the subject of their token.
+type PantryItem struct {
+	Subject      string     `gorm:"type:varchar(64);primaryKey"`
+	IngredientID uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	Ingredient   Ingredient `gorm:"references:ID"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime"`
+}
+
+func (p PantryItem) ConvertToDTO() IngredientDTO {
+	return p.Ingredient.ConvertToDTO()
+}
+
+func (p PantryItem) ConvertAllToDTO(pantryItems []PantryItem) []IngredientDTO {
+	data := []IngredientDTO{}
+
+	for _, pantryItem := range pantryItems {
+		data = append(data, pantryItem.ConvertToDTO())
+	}
+
+	return data
+}
+
+// PantryRequestDTO replaces the contents of a pantry
+type PantryRequestDTO struct {
+	IngredientIDs []uuid.UUID `json:"ingredient_ids" binding:"required"`
+}
+
+// PantryMatchRequest asks for the recipes that can be cooked with a set of ingredients
+type PantryMatchRequest struct {
+	IngredientIDs []uuid.UUID
+	MaxMissing    *int // nil to allow any number of missing ingredients
+	IgnoreStaples bool
+	Limit         int
+}
+
+type PantryMatchRequestDTO struct {
+	IngredientIDs []uuid.UUID `json:"ingredient_ids"` // the pantry of the user is used when left out
+	MaxMissing    *int        `json:"max_missing" binding:"omitempty,min=0"`
+	IgnoreStaples bool        `json:"ignore_staples"`
+	Limit         int         `json:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// PantryMatch tells how many ingredient lines of a recipe are covered by the available ingredients
+type PantryMatch struct {
+	RecipeID    uuid.UUID
+	Ingredients int // number of ingredient lines considered
+	Matched     int
+	Missing     []Ingredient
+}
+
+type PantryMatchDTO struct {
+	RecipeID    uuid.UUID       `json:"recipe_id" example:"23582396-12a3-425b-a597-8a22052823da"`
+	Ingredients int             `json:"ingredients" example:"5"`
+	Matched     int             `json:"matched" example:"4"`
+	Coverage    float64         `json:"coverage" example:"0.8"`
+	Missing     []IngredientDTO `json:"missing"`
+}
+
+func (p PantryMatch) ConvertToDTO() PantryMatchDTO {
+	dto := PantryMatchDTO{
+		RecipeID:    p.RecipeID,
+		Ingredients: p.Ingredients,
+		Matched:     p.Matched,
+		Missing:     Ingredient{}.ConvertAllToDTO(p.Missing),
+	}
+
+	if p.Ingredients > 0 {
+		dto.Coverage = float64(p.Matched) / float64(p.Ingredients)
+	}
+
+	// always return a list, also when nothing is missing
+	if dto.Missing == nil {
+		dto.Missing = []IngredientDTO{}
+	}
+
+	return dto
+}
+
+func (p PantryMatch) ConvertAllToDTO(pantryMatches []PantryMatch) []PantryMatchDTO {
+	data := []PantryMatchDTO{}
+
+	for _, pantryMatch := range pantryMatches {
+		data = append(data, pantryMatch.ConvertToDTO())
+	}
+
+	return data
+}
@@ -1,25 +1,43 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"ingredient-service/internal/quantity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
 
 // RecipeIngredient struct to hold recipe ingredient data
 type RecipeIngredient struct {
-	RecipeID     uuid.UUID `gorm:"primaryKey"`
-	IngredientID uuid.UUID `gorm:"primaryKey"`
-	Quantity     int       `json:"Quantity"`
-	UnitID       uuid.UUID `json:"UnitID"`
-	Unit         Unit      `gorm:"references:ID"`
+	RecipeID     uuid.UUID         `gorm:"type:uuid;primaryKey"`
+	IngredientID uuid.UUID         `gorm:"type:uuid;primaryKey"`
+	Ingredient   Ingredient        `gorm:"references:ID"`
+	Quantity     quantity.Quantity `gorm:"type:text" json:"Quantity"`      // stored exactly, such as 3/2, and the lower bound of a range
+	MaxQuantity  quantity.Quantity `gorm:"type:text;not null;default:'0'"` // the upper bound of a range such as 2-3, zero otherwise
+	ToTaste      bool              `gorm:"not null;default:false"`
+	UnitID       uuid.UUID         `gorm:"type:uuid" json:"UnitID"`
+	Unit         Unit              `gorm:"references:ID"`
+	DeletedAt    gorm.DeletedAt    `gorm:"index"` // set when the recipe is deleted, so the lines can be restored along with it
 }
 
 func (r RecipeIngredient) ConvertToDTO() RecipeIngredientDTO {
 	return RecipeIngredientDTO{
-		RecipeID:     r.RecipeID,
-		IngredientID: r.IngredientID,
-		Quantity:     r.Quantity,
-		Unit:         r.Unit.ConvertToDTO(),
+		RecipeID:       r.RecipeID,
+		IngredientID:   r.IngredientID,
+		IngredientName: r.Ingredient.Name,
+		Quantity:       r.Quantity.Float(),
+		MaxQuantity:    r.MaxQuantity.Float(),
+		ToTaste:        r.ToTaste,
+		Amount:         r.Amount().Format(r.Unit.System),
+		Unit:           r.Unit.ConvertToDTO(),
 	}
 }
 
+// Amount returns the quantity, range or "to taste" of the ingredient line
+func (r RecipeIngredient) Amount() quantity.Amount {
+	return quantity.Amount{Min: r.Quantity, Max: r.MaxQuantity, ToTaste: r.ToTaste}
+}
+
 func (r RecipeIngredient) ConvertAllToDTO(recipeIngredients []RecipeIngredient) []RecipeIngredientDTO {
 	var data []RecipeIngredientDTO
 
@@ -31,28 +49,61 @@ func (r RecipeIngredient) ConvertAllToDTO(recipeIngredients []RecipeIngredient)
 }
 
 type RecipeIngredientDTO struct {
-	RecipeID     uuid.UUID `json:"RecipeID" example:"23582396-12a3-425b-a597-8a22052823da"`
-	IngredientID uuid.UUID `json:"IngredientID" example:"23582396-12a3-425b-a597-8a22052823da"`
-	Quantity     int       `json:"Quantity" example:"40"`
-	Unit         UnitDTO   `json:"unit"`
+	RecipeID       uuid.UUID `json:"RecipeID" example:"23582396-12a3-425b-a597-8a22052823da"`
+	IngredientID   uuid.UUID `json:"IngredientID" example:"23582396-12a3-425b-a597-8a22052823da"`
+	IngredientName string    `json:"IngredientName" example:"asparagus"`
+	// Deprecated: Quantity and MaxQuantity, the upper bound of a range as in 1.5-2, can't hold amounts such as 1/3
+	// exactly. Send Amount instead; they are still filled in responses and only read when no Amount is given.
+	Quantity    float64        `json:"Quantity" example:"1.5"`
+	MaxQuantity float64        `json:"MaxQuantity,omitempty" example:"2"`
+	ToTaste     bool           `json:"ToTaste,omitempty"`
+	Amount      string         `json:"Amount,omitempty" example:"1½–2"` // the amount as free text, which takes precedence over the numbers when given
+	Unit        UnitDTO        `json:"unit"`
+	Converted   *ConversionDTO `json:"converted,omitempty"` // the quantity in the measurement system asked for
 }
 
-func (r RecipeIngredientDTO) ConvertFromDTO() RecipeIngredient {
+// ConvertFromDTO converts the DTO, returning the error of ParseAmount when the amount can't be read
+func (r RecipeIngredientDTO) ConvertFromDTO() (RecipeIngredient, error) {
+	amount, err := r.ParseAmount()
+	if err != nil {
+		return RecipeIngredient{}, err
+	}
+
 	return RecipeIngredient{
 		RecipeID:     r.RecipeID,
 		IngredientID: r.IngredientID,
-		Quantity:     r.Quantity,
+		Quantity:     amount.Min,
+		MaxQuantity:  amount.Max,
+		ToTaste:      amount.ToTaste,
 		UnitID:       r.Unit.ID,
 		Unit:         r.Unit.ConvertFromDTO(),
+	}, nil
+}
+
+// ParseAmount reads the amount of the ingredient line from its free text, or from its numbers when there is no text
+func (r RecipeIngredientDTO) ParseAmount() (quantity.Amount, error) {
+	if r.Amount != "" {
+		return quantity.Parse(r.Amount)
 	}
+
+	return quantity.Amount{
+		Min:     quantity.FromFloat(r.Quantity),
+		Max:     quantity.FromFloat(r.MaxQuantity),
+		ToTaste: r.ToTaste,
+	}, nil
 }
 
-func (r RecipeIngredientDTO) ConvertAllFromDTO(recipeIngredients []RecipeIngredientDTO) []RecipeIngredient {
+func (r RecipeIngredientDTO) ConvertAllFromDTO(recipeIngredients []RecipeIngredientDTO) ([]RecipeIngredient, error) {
 	var data []RecipeIngredient
 
 	for _, ri := range recipeIngredients {
-		data = append(data, ri.ConvertFromDTO())
+		recipeIngredient, err := ri.ConvertFromDTO()
+		if err != nil {
+			return nil, err
+		}
+
+		data = append(data, recipeIngredient)
 	}
 
-	return data
+	return data, nil
 }
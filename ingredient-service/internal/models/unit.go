@@ -7,10 +7,31 @@ import (
 	"gorm.io/gorm"
 )
 
+// Dimensions a unit can measure. Units of the same dimension can be converted into each other.
+const (
+	DimensionMass        = "mass"
+	DimensionVolume      = "volume"
+	DimensionCount       = "count"
+	DimensionLength      = "length"
+	DimensionTemperature = "temperature"
+)
+
+// Measurement systems, units such as a piece belong to neither
+const (
+	SystemMetric   = "metric"
+	SystemImperial = "imperial"
+)
+
+// Unit struct to hold unit data. A quantity in the unit equals quantity * Factor + Offset in the base unit of its
+// dimension, which is the gram, millilitre, piece, millimetre or degree Celsius.
 type Unit struct {
 	ID        uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	FullName  string         `gorm:"not null;unique" json:"FullName" example:"Fluid ounce"`
 	ShortName string         `gorm:"not null;unique" json:"ShortName" example:"fl oz"`
+	Dimension string         `gorm:"not null;default:''" json:"Dimension" example:"volume"`
+	System    string         `gorm:"not null;default:''" json:"System" example:"imperial"`
+	Factor    float64        `gorm:"not null;default:0" json:"Factor" example:"29.5735295625"`
+	Offset    float64        `gorm:"not null;default:0" json:"Offset" example:"0"` // only temperatures have an offset
 	CreatedAt time.Time      `gorm:"autoCreateTime"`
 	UpdatedAt time.Time      `gorm:"autoUpdateTime"`
 	DeletedAt gorm.DeletedAt `gorm:"index"`
@@ -26,6 +47,10 @@ func (u Unit) ConvertToDTO() UnitDTO {
 		ID:        u.ID,
 		FullName:  u.FullName,
 		ShortName: u.ShortName,
+		Dimension: u.Dimension,
+		System:    u.System,
+		Factor:    u.Factor,
+		Offset:    u.Offset,
 	}
 }
 
@@ -43,6 +68,10 @@ type UnitDTO struct {
 	ID        uuid.UUID `gorm:"primaryKey;not null;unique;index" json:"ID" example:"1"`
 	FullName  string    `gorm:"not null;unique" json:"FullName" example:"Fluid ounce"`
 	ShortName string    `gorm:"not null;unique" json:"ShortName" example:"fl oz"`
+	Dimension string    `json:"Dimension,omitempty" example:"volume"`
+	System    string    `json:"System,omitempty" example:"imperial"`
+	Factor    float64   `json:"Factor,omitempty" example:"29.5735295625"`
+	Offset    float64   `json:"Offset,omitempty" example:"0"`
 }
 
 func (u UnitDTO) ConvertFromDTO() Unit {
@@ -50,6 +79,10 @@ func (u UnitDTO) ConvertFromDTO() Unit {
 		ID:        u.ID,
 		FullName:  u.FullName,
 		ShortName: u.ShortName,
+		Dimension: u.Dimension,
+		System:    u.System,
+		Factor:    u.Factor,
+		Offset:    u.Offset,
 	}
 }
 
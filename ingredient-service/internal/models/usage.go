@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// UsageSampleSize is the number of referencing recipes listed when an entity is still in use
+const UsageSampleSize = 10
+
+// Usage tells by how many recipes an entity is referenced, with a sample of them. Deleted recipes are told apart,
+// as they only reference the entity until they can no longer be restored.
+type Usage struct {
+	Count            int64
+	RecipeIDs        []uuid.UUID
+	DeletedCount     int64
+	DeletedRecipeIDs []uuid.UUID
+}
+
+// InUse tells whether any recipe, deleted or not, references the entity
+func (u Usage) InUse() bool {
+	return u.Count > 0 || u.DeletedCount > 0
+}
+
+// InUseError is returned when an entity cannot be deleted or replaced because recipes still reference it
+type InUseError struct {
+	Message string
+	Usage   Usage
+}
+
+// NewInUseError creates the error for an entity of the given kind, e.g. ingredient, that is still referenced
+func NewInUseError(entity string, usage Usage) InUseError {
+	message := fmt.Sprintf("%s is used by %d recipes", entity, usage.Count)
+	switch {
+	case usage.Count == 0 && usage.DeletedCount > 0:
+		message = fmt.Sprintf("%s is used by %d deleted recipes that can still be restored", entity, usage.DeletedCount)
+	case usage.DeletedCount > 0:
+		message += fmt.Sprintf(" and %d deleted recipes that can still be restored", usage.DeletedCount)
+	}
+
+	return InUseError{
+		Message: message,
+		Usage:   usage,
+	}
+}
+
+func (e InUseError) Error() string {
+	return e.Message
+}
+
+func (e InUseError) ConvertToDTO() InUseDTO {
+	dto := InUseDTO{
+		Error:            e.Message,
+		Count:            e.Usage.Count,
+		RecipeIDs:        e.Usage.RecipeIDs,
+		DeletedCount:     e.Usage.DeletedCount,
+		DeletedRecipeIDs: e.Usage.DeletedRecipeIDs,
+	}
+
+	if dto.RecipeIDs == nil {
+		dto.RecipeIDs = []uuid.UUID{}
+	}
+
+	if dto.DeletedRecipeIDs == nil {
+		dto.DeletedRecipeIDs = []uuid.UUID{}
+	}
+
+	return dto
+}
+
+// InUseDTO is the body of a refused delete, it lists up to UsageSampleSize of the referencing recipes, and as many
+// of the deleted recipes that can still be restored
+type InUseDTO struct {
+	Error            string      `json:"error" example:"ingredient is used by 12 recipes"`
+	Count            int64       `json:"count" example:"12"`
+	RecipeIDs        []uuid.UUID `json:"recipe_ids"`
+	DeletedCount     int64       `json:"deleted_count" example:"0"`
+	DeletedRecipeIDs []uuid.UUID `json:"deleted_recipe_ids"`
+}
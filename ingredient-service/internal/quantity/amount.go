@@ -0,0 +1,150 @@
+package quantity
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Amount is how much of an ingredient a recipe takes: a quantity, a range such as 2-3, or just "to taste"
+type Amount struct {
+	Min     Quantity
+	Max     Quantity // zero unless the amount is a range
+	ToTaste bool
+}
+
+// the separators of a range, as in 2-3, 2–3 and 2 to 3
+var rangeSeparator = regexp.MustCompile(`\s*(?:-|–|—|\bto\b)\s*`)
+
+// the endings of an amount that is to taste, as in "to taste" and "1 tsp, or to taste"
+var toTaste = regexp.MustCompile(`(?:^|[\s,]+)(?:or\s+)?to\s+taste$`)
+
+// Parse reads an amount written as free text, such as "1 1/2", "1½", "0.75", "2-3" and "to taste"
+func Parse(text string) (Amount, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	var amount Amount
+	if location := toTaste.FindStringIndex(text); location != nil {
+		amount.ToTaste = true
+		text = strings.TrimSpace(text[:location[0]])
+
+		if text == "" {
+			return amount, nil
+		}
+	}
+
+	if text == "" {
+		return Amount{}, errors.New("amount is empty")
+	}
+
+	bounds := rangeSeparator.Split(text, -1)
+	if len(bounds) > 2 {
+		return Amount{}, errors.New("invalid amount")
+	}
+
+	min, err := parseMixed(bounds[0])
+	if err != nil {
+		return Amount{}, err
+	}
+	amount.Min = min
+
+	if len(bounds) == 2 {
+		max, err := parseMixed(bounds[1])
+		if err != nil {
+			return Amount{}, err
+		}
+		if !min.Less(max) {
+			return Amount{}, errors.New("invalid amount")
+		}
+		amount.Max = max
+	}
+
+	return amount, nil
+}
+
+// Format writes the amount the way a cook would read it in the measurement system: as fractions such as 1½, or as
+// decimals such as 0.75 in the metric system. A quantity that is also to taste is written as "1, or to taste", which
+// Parse reads back.
+func (a Amount) Format(system string) string {
+	if a.ToTaste && !a.Min.IsZero() {
+		return Amount{Min: a.Min, Max: a.Max}.Format(system) + ", or to taste"
+	}
+
+	if a.Min.IsZero() {
+		if a.ToTaste {
+			return "to taste"
+		}
+		return "0"
+	}
+
+	format := Quantity.Fraction
+	if system == metric {
+		format = Quantity.Decimal
+	}
+
+	// a range can shrink to a single quantity once it is rounded for display
+	if !a.Min.Less(a.Max) || format(a.Min) == format(a.Max) {
+		return format(a.Min)
+	}
+	return format(a.Min) + "–" + format(a.Max)
+}
+
+// metric is the name of the metric measurement system, in which amounts are written as decimals
+const metric = "metric"
+
+// parseMixed reads a quantity that is a whole number, a decimal or a fraction, or a whole number and a fraction
+// as in "1 1/2" and "1½"
+func parseMixed(text string) (Quantity, error) {
+	fields := strings.Fields(expandGlyphs(text))
+
+	switch len(fields) {
+	case 1:
+		return parse(fields[0])
+	case 2:
+		whole, err := parse(fields[0])
+		if err != nil || whole.den > 1 || !strings.Contains(fields[1], "/") {
+			return Quantity{}, errors.New("invalid amount")
+		}
+
+		fraction, err := parse(fields[1])
+		if err != nil || !fraction.Less(New(1, 1)) {
+			return Quantity{}, errors.New("invalid amount")
+		}
+
+		// both parts are bounded by maxTerm, so the numerator of the sum fits before it is checked itself
+		return fromRat(big.NewRat(whole.num*fraction.denominator()+fraction.num, fraction.denominator()))
+	default:
+		return Quantity{}, errors.New("invalid amount")
+	}
+}
+
+// expandGlyphs writes fraction characters out, so "1½" becomes "1 1/2"
+func expandGlyphs(text string) string {
+	var builder strings.Builder
+
+	for _, r := range text {
+		if r == '⁄' {
+			builder.WriteRune('/')
+			continue
+		}
+
+		if fraction, ok := glyphFractions[r]; ok {
+			builder.WriteString(" " + fraction.String())
+			continue
+		}
+
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+// glyphFractions are the fractions of the fraction characters
+var glyphFractions = func() map[rune]Quantity {
+	fractions := make(map[rune]Quantity, len(glyphs))
+	for fraction, glyph := range glyphs {
+		fractions[[]rune(glyph)[0]] = New(fraction[0], fraction[1])
+	}
+	return fractions
+}()
@@ -0,0 +1,195 @@
+package quantity
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Quantity is an exact, non-negative amount such as 3/2. The zero value is zero.
+type Quantity struct {
+	num int64
+	den int64 // 0 in the zero value, which reads as 1
+}
+
+// New creates the quantity num/den in its lowest terms
+func New(num int64, den int64) Quantity {
+	if num == 0 || den == 0 {
+		return Quantity{}
+	}
+
+	divisor := gcd(num, den)
+	return Quantity{num: num / divisor, den: den / divisor}
+}
+
+// FromFloat converts a number into a quantity. Numbers with a few decimals, such as 0.75, are converted exactly.
+func FromFloat(value float64) Quantity {
+	if value <= 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+		return Quantity{}
+	}
+
+	q, err := parse(strconv.FormatFloat(math.Round(value*1e6)/1e6, 'f', -1, 64))
+	if err != nil {
+		return Quantity{}
+	}
+	return q
+}
+
+// Approximate converts a converted, inexact number into a quantity that reads well in the measurement system. For
+// the metric system that is a number with a few decimals, for other systems the nearest eighth.
+func Approximate(value float64, system string) Quantity {
+	if system == metric {
+		return FromFloat(round(value))
+	}
+
+	if eighths := math.Round(value * 8); eighths > 0 {
+		return New(int64(eighths), 8)
+	}
+	return FromFloat(round(value))
+}
+
+func (q Quantity) IsZero() bool {
+	return q.num == 0
+}
+
+func (q Quantity) Float() float64 {
+	if q.num == 0 {
+		return 0
+	}
+	return float64(q.num) / float64(q.den)
+}
+
+// Less tells whether the quantity is smaller than the other one
+func (q Quantity) Less(other Quantity) bool {
+	return q.num*other.denominator() < other.num*q.denominator()
+}
+
+// String returns the quantity as it is stored, such as 3/2 or 2
+func (q Quantity) String() string {
+	if q.denominator() == 1 {
+		return strconv.FormatInt(q.num, 10)
+	}
+	return fmt.Sprintf("%d/%d", q.num, q.den)
+}
+
+// Fraction formats the quantity the way a cook would write it, such as 1½ or 1 5/16
+func (q Quantity) Fraction() string {
+	whole, rest := q.num/q.denominator(), q.num%q.denominator()
+
+	if rest == 0 {
+		return strconv.FormatInt(whole, 10)
+	}
+
+	fraction, ok := glyphs[[2]int64{rest, q.den}]
+	if !ok {
+		fraction = fmt.Sprintf("%d/%d", rest, q.den)
+		if whole > 0 {
+			fraction = " " + fraction
+		}
+	}
+
+	if whole == 0 {
+		return strings.TrimSpace(fraction)
+	}
+	return strconv.FormatInt(whole, 10) + fraction
+}
+
+// Decimal formats the quantity as a number, with fewer decimals as the quantity gets larger
+func (q Quantity) Decimal() string {
+	return strconv.FormatFloat(round(q.Float()), 'f', -1, 64)
+}
+
+// Scan reads a quantity stored as text, such as 3/2, or as a number
+func (q *Quantity) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*q = Quantity{}
+		return nil
+	case int64:
+		*q = New(v, 1)
+		return nil
+	case float64:
+		*q = FromFloat(v)
+		return nil
+	case []byte:
+		return q.Scan(string(v))
+	case string:
+		parsed, err := parse(v)
+		if err != nil {
+			return err
+		}
+		*q = parsed
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into a quantity", value)
+	}
+}
+
+// Value stores the quantity as text, so it stays exact
+func (q Quantity) Value() (driver.Value, error) {
+	return q.String(), nil
+}
+
+func (q Quantity) denominator() int64 {
+	if q.den == 0 {
+		return 1
+	}
+	return q.den
+}
+
+// parse reads a single quantity, a whole number, a decimal or a fraction
+func parse(text string) (Quantity, error) {
+	r, ok := new(big.Rat).SetString(strings.Replace(strings.TrimSpace(text), ",", ".", 1))
+	if !ok {
+		return Quantity{}, errors.New("invalid amount")
+	}
+
+	return fromRat(r)
+}
+
+// maxTerm bounds the numerator and denominator of a parsed quantity, so that comparing two quantities, which multiplies
+// the numerator of one by the denominator of the other, can't overflow
+const maxTerm = 1000000000
+
+func fromRat(r *big.Rat) (Quantity, error) {
+	bound := big.NewInt(maxTerm)
+
+	if r.Sign() < 0 || r.Num().Cmp(bound) > 0 || r.Denom().Cmp(bound) > 0 {
+		return Quantity{}, errors.New("invalid amount")
+	}
+
+	return New(r.Num().Int64(), r.Denom().Int64()), nil
+}
+
+// round rounds a number to the decimals that matter, which are less as the number gets larger
+func round(value float64) float64 {
+	switch {
+	case value >= 100:
+		return math.Round(value)
+	case value >= 10:
+		return math.Round(value*10) / 10
+	default:
+		return math.Round(value*100) / 100
+	}
+}
+
+func gcd(a int64, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// glyphs are the fractions that have a character of their own
+var glyphs = map[[2]int64]string{
+	{1, 2}: "½",
+	{1, 3}: "⅓", {2, 3}: "⅔",
+	{1, 4}: "¼", {3, 4}: "¾",
+	{1, 5}: "⅕", {2, 5}: "⅖", {3, 5}: "⅗", {4, 5}: "⅘",
+	{1, 6}: "⅙", {5, 6}: "⅚",
+	{1, 8}: "⅛", {3, 8}: "⅜", {5, 8}: "⅝", {7, 8}: "⅞",
+}
@@ -0,0 +1,116 @@
+package quantity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]Amount{
+		"2":             {Min: New(2, 1)},
+		"0.75":          {Min: New(3, 4)},
+		"0,5":           {Min: New(1, 2)},
+		"3/4":           {Min: New(3, 4)},
+		"1 1/2":         {Min: New(3, 2)},
+		"1½":            {Min: New(3, 2)},
+		"1 ½":           {Min: New(3, 2)},
+		"⅓":             {Min: New(1, 3)},
+		"2-3":           {Min: New(2, 1), Max: New(3, 1)},
+		"2–3":           {Min: New(2, 1), Max: New(3, 1)},
+		"1/2 to 1":      {Min: New(1, 2), Max: New(1, 1)},
+		"to taste":      {ToTaste: true},
+		"To Taste":      {ToTaste: true},
+		"1, to taste":   {Min: New(1, 1), ToTaste: true},
+		"½ or to taste": {Min: New(1, 2), ToTaste: true},
+	}
+
+	for text, expected := range tests {
+		result, err := Parse(text)
+
+		assert.NoError(t, err, text)
+		assert.Equal(t, expected, result, text)
+	}
+}
+
+func TestParse_Err(t *testing.T) {
+	for _, text := range []string{"", "a few", "-1", "3-2", "1-2-3", "1 1", "1 3/2", "1/0", "999999999999/999999999998", "1 1/999999999999", "99999999999"} {
+		_, err := Parse(text)
+
+		assert.Error(t, err, text)
+	}
+}
+
+func TestAmount_Format(t *testing.T) {
+	tests := []struct {
+		amount   Amount
+		system   string
+		expected string
+	}{
+		{Amount{Min: New(3, 2)}, "imperial", "1½"},
+		{Amount{Min: New(3, 2)}, "", "1½"},
+		{Amount{Min: New(3, 2)}, "metric", "1.5"},
+		{Amount{Min: New(1, 3)}, "metric", "0.33"},
+		{Amount{Min: New(21, 16)}, "imperial", "1 5/16"},
+		{Amount{Min: New(3, 16)}, "imperial", "3/16"},
+		{Amount{Min: New(2, 1), Max: New(3, 1)}, "", "2–3"},
+		{Amount{Min: New(1, 1), Max: New(1001, 1000)}, "metric", "1"},
+		{Amount{ToTaste: true}, "", "to taste"},
+		{Amount{Min: New(1, 4), ToTaste: true}, "", "¼, or to taste"},
+		{Amount{Min: New(1, 1), Max: New(2, 1), ToTaste: true}, "metric", "1–2, or to taste"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.amount.Format(test.system))
+	}
+}
+
+func TestAmount_FormatParse(t *testing.T) {
+	for _, amount := range []Amount{
+		{Min: New(1, 1), ToTaste: true},
+		{Min: New(3, 2), Max: New(2, 1), ToTaste: true},
+		{ToTaste: true},
+		{Min: New(21, 16)},
+	} {
+		parsed, err := Parse(amount.Format("imperial"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, amount, parsed)
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	assert.Equal(t, New(3, 4), FromFloat(0.75))
+	assert.Equal(t, New(1, 10), FromFloat(0.1))
+	assert.Equal(t, New(2, 1), FromFloat(2))
+	assert.Equal(t, Quantity{}, FromFloat(-1))
+}
+
+func TestApproximate(t *testing.T) {
+	assert.Equal(t, "2⅛", Approximate(2.113, "imperial").Fraction())
+	assert.Equal(t, "0.05", Approximate(0.05, "imperial").Decimal())
+	assert.Equal(t, "237", Approximate(236.588, "metric").Decimal())
+	assert.Equal(t, "0.47", Approximate(0.4732, "metric").Decimal())
+}
+
+func TestQuantity_ScanValue(t *testing.T) {
+	var q Quantity
+
+	assert.NoError(t, q.Scan("3/2"))
+	assert.Equal(t, New(3, 2), q)
+
+	assert.NoError(t, q.Scan([]byte("2")))
+	assert.Equal(t, New(2, 1), q)
+
+	assert.NoError(t, q.Scan(int64(5)))
+	assert.Equal(t, New(5, 1), q)
+
+	assert.Error(t, q.Scan("a few"))
+
+	value, err := New(6, 4).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "3/2", value)
+
+	value, _ = Quantity{}.Value()
+	assert.Equal(t, "0", value)
+}
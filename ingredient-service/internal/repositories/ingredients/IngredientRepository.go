@@ -2,10 +2,16 @@ package repositories
 
 import (
 	"errors"
+	"fmt"
 
+	"cookbook/pkg/normalize"
+	"cookbook/pkg/outbox"
 	m "ingredient-service/internal/models"
+	"ingredient-service/internal/repositories/references"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type IngredientRepository struct {
@@ -21,7 +27,7 @@ func NewIngredientRepository(db *gorm.DB) *IngredientRepository {
 func (r IngredientRepository) FindAll() ([]m.Ingredient, error) {
 	var ingredients []m.Ingredient
 
-	if err := r.db.Find(&ingredients).Error; err != nil {
+	if err := r.db.Preload("Aliases", orderAliases).Find(&ingredients).Error; err != nil {
 		return nil, err
 	}
 
@@ -34,7 +40,7 @@ func (r IngredientRepository) FindAll() ([]m.Ingredient, error) {
 
 func (r IngredientRepository) FindSingle(ingredient m.Ingredient) (m.Ingredient, error) {
 
-	result := r.db.First(&ingredient)
+	result := r.db.Preload("Aliases", orderAliases).First(&ingredient)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return m.Ingredient{}, errors.New("not found")
@@ -46,50 +52,360 @@ func (r IngredientRepository) FindSingle(ingredient m.Ingredient) (m.Ingredient,
 	return ingredient, nil
 }
 
+// FindByName finds the ingredient a name refers to, by its normalized name or one of its aliases
+func (r IngredientRepository) FindByName(name string) (m.Ingredient, error) {
+	var ingredient m.Ingredient
+
+	normalized := normalize.Name(name)
+
+	result := r.db.Preload("Aliases", orderAliases).
+		Where("normalized_name = ?", normalized).
+		Or("id IN (?)", r.db.Model(&m.IngredientAlias{}).Select("ingredient_id").Where("normalized_name = ?", normalized)).
+		First(&ingredient)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return m.Ingredient{}, errors.New("not found")
+		} else {
+			return m.Ingredient{}, result.Error
+		}
+	}
+
+	return ingredient, nil
+}
+
+// Create stores an ingredient with its aliases. An "already exists" error is returned when the name or an alias
+// is taken by another ingredient.
 func (r IngredientRepository) Create(ingredient m.Ingredient) (m.Ingredient, error) {
 
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
 
-		if err := tx.Create(&ingredient).Error; err != nil {
+		if err := tx.Omit("Aliases").Create(&ingredient).Error; err != nil {
 			return err
 		}
 
-		return nil
-	}); err != nil {
+		if err := createAliases(tx, &ingredient); err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventCreated, m.EntityIngredient, ingredient.ID))
+	}); errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ingredient, errors.New("already exists")
+	} else if err != nil {
 		return ingredient, err
 	}
 
 	return ingredient, nil
 }
 
+// Update stores the changes to an ingredient, replacing its aliases when they are given. An "already exists"
+// error is returned when the name or an alias is taken by another ingredient.
 func (r IngredientRepository) Update(ingredient m.Ingredient) (m.Ingredient, error) {
 
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
 
-		if err := tx.Updates(&ingredient).Error; err != nil {
+		// staple, plural and density are selected explicitly, as Updates would skip them when they are reset
+		if err := tx.Select("name", "normalized_name", "plural", "staple", "density").Updates(&ingredient).Error; err != nil {
 			return err
 		}
 
-		return nil
-	}); err != nil {
+		// without aliases given the ones the ingredient has are kept
+		if ingredient.Aliases != nil {
+			if err := tx.Where("ingredient_id = ?", ingredient.ID).Delete(&m.IngredientAlias{}).Error; err != nil {
+				return err
+			}
+
+			if err := createAliases(tx, &ingredient); err != nil {
+				return err
+			}
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventUpdated, m.EntityIngredient, ingredient.ID))
+	}); errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ingredient, errors.New("already exists")
+	} else if err != nil {
 		return ingredient, err
 	}
 
 	return ingredient, nil
 }
 
+// Delete removes an ingredient that is not used by any recipe, otherwise an InUseError is returned. The
+// ingredient is removed from the pantries holding it, and its aliases are removed so the names can be used again.
 func (r IngredientRepository) Delete(ingredient m.Ingredient) error {
 
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
 
+		usage, err := references.Usage(tx, "ingredient_id", ingredient.ID)
+		if err != nil {
+			return err
+		}
+
+		if usage.InUse() {
+			return m.NewInUseError("ingredient", usage)
+		}
+
+		if err := tx.Where("ingredient_id = ?", ingredient.ID).Delete(&m.PantryItem{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("ingredient_id = ?", ingredient.ID).Delete(&m.IngredientAlias{}).Error; err != nil {
+			return err
+		}
+
 		if err := tx.Delete(&ingredient).Error; err != nil {
 			return err
 		}
 
-		return nil
+		return outbox.Record(tx, m.NewChangeEvent(m.EventDeleted, m.EntityIngredient, ingredient.ID))
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reassign moves all references to an ingredient, in recipes and pantries, to the replacement and removes the
+// ingredient and its aliases afterwards. A recipe can list an ingredient only once, so an InUseError is returned
+// when recipes already use both. A pantry already holding the replacement just loses the ingredient.
+func (r IngredientRepository) Reassign(ingredient m.Ingredient, replacement m.Ingredient) error {
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.First(&replacement).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("replacement not found")
+			}
+			return err
+		}
+
+		shared, err := references.Shared(tx, "ingredient_id", ingredient.ID, replacement.ID)
+		if err != nil {
+			return err
+		}
+
+		if shared.Count > 0 {
+			return m.InUseError{
+				Message: fmt.Sprintf("replacement is already used alongside the ingredient by %d recipes", shared.Count),
+				Usage:   shared,
+			}
+		}
+
+		recipeIDs, err := references.Recipes(tx, "ingredient_id", ingredient.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := references.Reassign(tx, "ingredient_id", ingredient.ID, replacement.ID); err != nil {
+			return err
+		}
+
+		if err := movePantryItems(tx, ingredient.ID, replacement.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Where("ingredient_id = ?", ingredient.ID).Delete(&m.IngredientAlias{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&ingredient).Error; err != nil {
+			return err
+		}
+
+		events := []m.ChangeEvent{m.NewChangeEvent(m.EventDeleted, m.EntityIngredient, ingredient.ID)}
+		for _, recipeID := range recipeIDs {
+			events = append(events, m.NewChangeEvent(m.EventUpdated, m.EntityRecipeIngredients, recipeID))
+		}
+
+		return outbox.Record(tx, events...)
 	}); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// Merge folds duplicate ingredients into the target. The recipe lines and pantry items of the sources are moved to
+// the target, their names and aliases become aliases of the target and the sources are removed, all at once. As a
+// recipe can list an ingredient only once, recipes listing more than one of the merged ingredients block the merge
+// with an InUseError. With preview nothing is changed, the result only tells what the merge would do.
+func (r IngredientRepository) Merge(target m.Ingredient, sources []m.Ingredient, preview bool) (m.MergeResult, error) {
+	var result m.MergeResult
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.First(&target).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("target not found")
+			}
+			return err
+		}
+
+		var sourceIDs []uuid.UUID
+		for _, source := range sources {
+			sourceIDs = append(sourceIDs, source.ID)
+		}
+
+		sources = nil
+		if err := tx.Where("id IN ?", sourceIDs).Order("name").Find(&sources).Error; err != nil {
+			return err
+		}
+
+		if len(sources) != len(sourceIDs) {
+			return errors.New("source not found")
+		}
+
+		result = m.MergeResult{Target: target, Sources: sources}
+		for _, source := range sources {
+			result.Aliases = append(result.Aliases, source.Name)
+		}
+
+		conflicts, err := references.Conflicts(tx, "ingredient_id", append([]uuid.UUID{target.ID}, sourceIDs...))
+		if err != nil {
+			return err
+		}
+		result.Conflicts = conflicts
+
+		seen := map[uuid.UUID]bool{}
+		for _, source := range sources {
+			recipeIDs, err := references.Recipes(tx, "ingredient_id", source.ID)
+			if err != nil {
+				return err
+			}
+
+			for _, recipeID := range recipeIDs {
+				if !seen[recipeID] {
+					seen[recipeID] = true
+					result.RecipeIDs = append(result.RecipeIDs, recipeID)
+				}
+			}
+		}
+
+		if preview {
+			return nil
+		}
+
+		if len(conflicts) > 0 {
+			usage := m.Usage{Count: int64(len(conflicts)), RecipeIDs: conflicts}
+			if len(usage.RecipeIDs) > m.UsageSampleSize {
+				usage.RecipeIDs = usage.RecipeIDs[:m.UsageSampleSize]
+			}
+
+			return m.InUseError{
+				Message: fmt.Sprintf("%d recipes use more than one of the merged ingredients", usage.Count),
+				Usage:   usage,
+			}
+		}
+
+		events := []m.ChangeEvent{m.NewChangeEvent(m.EventUpdated, m.EntityIngredient, target.ID)}
+		for _, source := range sources {
+			if err := references.Reassign(tx, "ingredient_id", source.ID, target.ID); err != nil {
+				return err
+			}
+
+			if err := movePantryItems(tx, source.ID, target.ID); err != nil {
+				return err
+			}
+
+			if err := tx.Model(&m.IngredientAlias{}).Where("ingredient_id = ?", source.ID).Update("ingredient_id", target.ID).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Delete(&source).Error; err != nil {
+				return err
+			}
+
+			events = append(events, m.NewChangeEvent(m.EventDeleted, m.EntityIngredient, source.ID))
+		}
+
+		var aliases []m.IngredientAlias
+		for _, name := range result.Aliases {
+			if alias := m.NewIngredientAlias(name, target.ID); alias.NormalizedName != target.NormalizedName {
+				aliases = append(aliases, alias)
+			}
+		}
+
+		// a name that already is an alias keeps pointing where it does
+		if len(aliases) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&aliases).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, recipeID := range result.RecipeIDs {
+			events = append(events, m.NewChangeEvent(m.EventUpdated, m.EntityRecipeIngredients, recipeID))
+		}
+
+		return outbox.Record(tx, events...)
+	}); err != nil {
+		return m.MergeResult{}, err
+	}
+
+	return result, nil
+}
+
+// movePantryItems moves the pantry items of an ingredient to another one, pantries already holding the other one
+// just lose the ingredient
+func movePantryItems(tx *gorm.DB, from uuid.UUID, to uuid.UUID) error {
+
+	if err := tx.Where("ingredient_id = ? AND subject IN (?)", from, tx.Model(&m.PantryItem{}).Select("subject").Where("ingredient_id = ?", to)).
+		Delete(&m.PantryItem{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&m.PantryItem{}).Where("ingredient_id = ?", from).Update("ingredient_id", to).Error
+}
+
+// NormalizeNames sets the normalized name of the ingredients stored before names were normalized. An ingredient
+// whose normalized name is already taken, such as "Eggs" next to "Egg", is left as it is to be merged.
+func (r IngredientRepository) NormalizeNames() (int, error) {
+	var ingredients []m.Ingredient
+
+	if err := r.db.Unscoped().Where("normalized_name = ''").Find(&ingredients).Error; err != nil {
+		return 0, err
+	}
+
+	normalized := 0
+	for _, ingredient := range ingredients {
+		err := r.db.Unscoped().Model(&ingredient).UpdateColumn("normalized_name", normalize.Name(ingredient.Name)).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+
+		normalized++
+	}
+
+	return normalized, nil
+}
+
+// createAliases stores the aliases of an ingredient. An alias is keyed by its normalized name, so names that
+// normalize to the name of the ingredient or to an earlier alias, such as "Egg" and "egg", are stored only once.
+func createAliases(tx *gorm.DB, ingredient *m.Ingredient) error {
+	if len(ingredient.Aliases) == 0 {
+		return nil
+	}
+
+	aliases := []m.IngredientAlias{}
+	seen := map[string]bool{normalize.Name(ingredient.Name): true}
+	for _, alias := range ingredient.Aliases {
+		alias = m.NewIngredientAlias(alias.Name, ingredient.ID)
+		if alias.NormalizedName == "" || seen[alias.NormalizedName] {
+			continue
+		}
+
+		seen[alias.NormalizedName] = true
+		aliases = append(aliases, alias)
+	}
+
+	ingredient.Aliases = aliases
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	return tx.Create(&ingredient.Aliases).Error
+}
+
+func orderAliases(db *gorm.DB) *gorm.DB {
+	return db.Order("name")
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -20,8 +21,9 @@ import (
 
 var (
 	ingredient m.Ingredient = m.Ingredient{
-		ID:   uuid.New(),
-		Name: "ingredient",
+		ID:             uuid.New(),
+		Name:           "ingredient",
+		NormalizedName: "ingredient",
 	}
 )
 
@@ -52,8 +54,9 @@ func newMockDatabase(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
 	})
 
 	mockDB, err = gorm.Open(dialector, &gorm.Config{
-		NowFunc: timeFunc,
-		Logger:  newLogger,
+		NowFunc:        timeFunc,
+		Logger:         newLogger,
+		TranslateError: true,
 	})
 	if err != nil {
 		t.Fatalf("gorm mock init failed: %v", err.Error())
@@ -67,6 +70,22 @@ func timeFunc() time.Time {
 	return time
 }
 
+func expectOutbox(mock sqlmock.Sqlmock, eventType string) {
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "sequence"`)).
+		WithArgs(sqlmock.AnyArg(), m.EntityIngredient, ingredient.ID, eventType, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "").
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1))
+}
+
+func expectAliases(mock sqlmock.Sqlmock, names ...string) {
+	rows := sqlmock.NewRows([]string{"normalized_name", "name", "ingredient_id"})
+	for _, name := range names {
+		rows.AddRow(ingredient.NormalizedName, name, ingredient.ID)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "ingredient_aliases" WHERE "ingredient_aliases"."ingredient_id" = $1 ORDER BY name`)).
+		WithArgs(ingredient.ID).
+		WillReturnRows(rows)
+}
+
 func TestIngredientFindAll_OK(t *testing.T) {
 	db, mock := newMockDatabase(t)
 	r := NewIngredientRepository(db)
@@ -77,11 +96,13 @@ func TestIngredientFindAll_OK(t *testing.T) {
 				ingredient.ID,
 				ingredient.Name,
 			))
+	expectAliases(mock, "Ingredients")
 
 	result, err := r.FindAll()
 
 	assert.NoError(t, err)
 	assert.Len(t, result, 1)
+	assert.Equal(t, "Ingredients", result[0].Aliases[0].Name)
 }
 
 func TestIngredientFindAll_NotFoundErr(t *testing.T) {
@@ -122,11 +143,14 @@ func TestIngredientFindSingle_OK(t *testing.T) {
 				ingredient.ID,
 				ingredient.Name,
 			))
+	expectAliases(mock, "Ingredients")
 
 	result, err := r.FindSingle(ingredient)
 
 	assert.NoError(t, err)
-	assert.Equal(t, ingredient, result)
+	assert.Equal(t, ingredient.ID, result.ID)
+	assert.Equal(t, ingredient.Name, result.Name)
+	assert.Equal(t, []string{"Ingredients"}, result.ConvertToDTO().Aliases)
 }
 
 func TestIngredientFindSingle_NotFoundErr(t *testing.T) {
@@ -162,9 +186,13 @@ func TestIngredientCreate_OK(t *testing.T) {
 	r := NewIngredientRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "ingredients" ("name","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "ingredients" ("name","normalized_name","plural","staple","density","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING "id"`)).
 		WithArgs(
 			ingredient.Name,
+			ingredient.NormalizedName,
+			ingredient.Plural,
+			ingredient.Staple,
+			ingredient.Density,
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
 			nil,
@@ -172,11 +200,13 @@ func TestIngredientCreate_OK(t *testing.T) {
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).
 			AddRow(ingredient.ID))
+	expectOutbox(mock, m.EventCreated)
 	mock.ExpectCommit()
 	result, err := r.Create(ingredient)
 
 	assert.NoError(t, err)
 	assert.IsType(t, m.Ingredient{}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestIngredientCreate_Err(t *testing.T) {
@@ -184,9 +214,13 @@ func TestIngredientCreate_Err(t *testing.T) {
 	r := NewIngredientRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "ingredients" ("name","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "ingredients" ("name","normalized_name","plural","staple","density","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING "id"`)).
 		WithArgs(
 			ingredient.Name,
+			ingredient.NormalizedName,
+			ingredient.Plural,
+			ingredient.Staple,
+			ingredient.Density,
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
 			nil,
@@ -200,23 +234,44 @@ func TestIngredientCreate_Err(t *testing.T) {
 	assert.EqualError(t, err, "error")
 }
 
+func TestIngredientCreate_Duplicate(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "ingredients" ("name","normalized_name","plural","staple","density","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING "id"`)).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err := r.Create(ingredient)
+
+	assert.EqualError(t, err, "already exists")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestIngredientUpdate_OK(t *testing.T) {
 	db, mock := newMockDatabase(t)
 	r := NewIngredientRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "ingredients" SET "name"=$1,"updated_at"=$2 WHERE "ingredients"."deleted_at" IS NULL AND "id" = $3`)).
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "ingredients" SET "name"=$1,"normalized_name"=$2,"plural"=$3,"staple"=$4,"density"=$5,"updated_at"=$6 WHERE "ingredients"."deleted_at" IS NULL AND "id" = $7`)).
 		WithArgs(
 			ingredient.Name,
+			ingredient.NormalizedName,
+			ingredient.Plural,
+			ingredient.Staple,
+			ingredient.Density,
 			sqlmock.AnyArg(),
 			ingredient.ID,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutbox(mock, m.EventUpdated)
 	mock.ExpectCommit()
 	result, err := r.Update(ingredient)
 
 	assert.NoError(t, err)
 	assert.IsType(t, m.Ingredient{}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestIngredientUpdate_Err(t *testing.T) {
@@ -224,9 +279,13 @@ func TestIngredientUpdate_Err(t *testing.T) {
 	r := NewIngredientRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "ingredients" SET "name"=$1,"updated_at"=$2 WHERE "ingredients"."deleted_at" IS NULL AND "id" = $3`)).
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "ingredients" SET "name"=$1,"normalized_name"=$2,"plural"=$3,"staple"=$4,"density"=$5,"updated_at"=$6 WHERE "ingredients"."deleted_at" IS NULL AND "id" = $7`)).
 		WithArgs(
 			ingredient.Name,
+			ingredient.NormalizedName,
+			ingredient.Plural,
+			ingredient.Staple,
+			ingredient.Density,
 			sqlmock.AnyArg(),
 			ingredient.ID,
 		).
@@ -239,22 +298,110 @@ func TestIngredientUpdate_Err(t *testing.T) {
 	assert.EqualError(t, err, "error")
 }
 
+func TestIngredientUpdate_Duplicate(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "ingredients" SET "name"=$1,"normalized_name"=$2,"plural"=$3,"staple"=$4,"density"=$5,"updated_at"=$6 WHERE "ingredients"."deleted_at" IS NULL AND "id" = $7`)).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err := r.Update(ingredient)
+
+	assert.EqualError(t, err, "already exists")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// expectUsage expects the recipes referencing the ingredient to be counted and sampled, those of deleted recipes apart
+func expectUsage(mock sqlmock.Sqlmock, recipeIDs []uuid.UUID, deletedRecipeIDs []uuid.UUID) {
+	expectSample(mock, `ingredient_id = $1 AND "recipe_ingredients"."deleted_at" IS NULL`, recipeIDs)
+	expectSample(mock, `ingredient_id = $1 AND deleted_at IS NOT NULL`, deletedRecipeIDs)
+}
+
+func expectSample(mock sqlmock.Sqlmock, where string, recipeIDs []uuid.UUID) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(DISTINCT("recipe_id")) FROM "recipe_ingredients" WHERE ` + where)).
+		WithArgs(ingredient.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(recipeIDs)))
+
+	if len(recipeIDs) == 0 {
+		return
+	}
+
+	rows := sqlmock.NewRows([]string{"recipe_id"})
+	for _, recipeID := range recipeIDs {
+		rows.AddRow(recipeID)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "recipe_ingredients" WHERE ` + where + ` ORDER BY recipe_id LIMIT $2`)).
+		WithArgs(ingredient.ID, m.UsageSampleSize).
+		WillReturnRows(rows)
+}
+
 func TestIngredientDelete_OK(t *testing.T) {
 	db, mock := newMockDatabase(t)
 	r := NewIngredientRepository(db)
 
 	mock.ExpectBegin()
+	expectUsage(mock, nil, nil)
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "pantry_items" WHERE ingredient_id = $1`)).
+		WithArgs(ingredient.ID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "ingredient_aliases" WHERE ingredient_id = $1`)).
+		WithArgs(ingredient.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "ingredients" SET "deleted_at"=$1 WHERE "ingredients"."id" = $2 AND "ingredients"."deleted_at" IS NULL`)).
 		WithArgs(
 			sqlmock.AnyArg(),
 			ingredient.ID,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutbox(mock, m.EventDeleted)
 	mock.ExpectCommit()
 
 	err := r.Delete(ingredient)
 
 	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientDelete_InUseErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	expectUsage(mock, []uuid.UUID{recipeID}, nil)
+	mock.ExpectRollback()
+
+	err := r.Delete(ingredient)
+
+	var inUse m.InUseError
+	assert.ErrorAs(t, err, &inUse)
+	assert.EqualError(t, err, "ingredient is used by 1 recipes")
+	assert.Equal(t, []uuid.UUID{recipeID}, inUse.Usage.RecipeIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientDelete_DeletedRecipesErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	expectUsage(mock, nil, []uuid.UUID{recipeID})
+	mock.ExpectRollback()
+
+	err := r.Delete(ingredient)
+
+	var inUse m.InUseError
+	assert.ErrorAs(t, err, &inUse)
+	assert.EqualError(t, err, "ingredient is used by 1 deleted recipes that can still be restored")
+	assert.Empty(t, inUse.Usage.RecipeIDs)
+	assert.Equal(t, []uuid.UUID{recipeID}, inUse.Usage.DeletedRecipeIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestIngredientDelete_Err(t *testing.T) {
@@ -262,6 +409,13 @@ func TestIngredientDelete_Err(t *testing.T) {
 	r := NewIngredientRepository(db)
 
 	mock.ExpectBegin()
+	expectUsage(mock, nil, nil)
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "pantry_items" WHERE ingredient_id = $1`)).
+		WithArgs(ingredient.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "ingredient_aliases" WHERE ingredient_id = $1`)).
+		WithArgs(ingredient.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "ingredients" SET "deleted_at"=$1 WHERE "ingredients"."id" = $2 AND "ingredients"."deleted_at" IS NULL`)).
 		WithArgs(
 			sqlmock.AnyArg(),
@@ -275,3 +429,414 @@ func TestIngredientDelete_Err(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualError(t, err, "error")
 }
+
+func expectReplacement(mock sqlmock.Sqlmock, replacement m.Ingredient) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "ingredients" WHERE "ingredients"."deleted_at" IS NULL AND "ingredients"."id" = $1 ORDER BY "ingredients"."id" LIMIT $2`)).
+		WithArgs(replacement.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(replacement.ID, replacement.Name))
+}
+
+func expectShared(mock sqlmock.Sqlmock, replacement m.Ingredient, count int64) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(DISTINCT("recipe_id")) FROM "recipe_ingredients" WHERE ingredient_id = $1 AND recipe_id IN (SELECT "recipe_id" FROM "recipe_ingredients" WHERE ingredient_id = $2)`)).
+		WithArgs(ingredient.ID, replacement.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
+
+func TestIngredientReassign_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	replacement := m.Ingredient{ID: uuid.New(), Name: "replacement"}
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	expectReplacement(mock, replacement)
+	expectShared(mock, replacement, 0)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "recipe_ingredients" WHERE ingredient_id = $1`)).
+		WithArgs(ingredient.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(recipeID))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_ingredients" SET "ingredient_id"=$1 WHERE ingredient_id = $2`)).
+		WithArgs(replacement.ID, ingredient.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "pantry_items" WHERE ingredient_id = $1 AND subject IN (SELECT "subject" FROM "pantry_items" WHERE ingredient_id = $2)`)).
+		WithArgs(ingredient.ID, replacement.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pantry_items" SET "ingredient_id"=$1 WHERE ingredient_id = $2`)).
+		WithArgs(replacement.ID, ingredient.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "ingredient_aliases" WHERE ingredient_id = $1`)).
+		WithArgs(ingredient.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "ingredients" SET "deleted_at"=$1 WHERE "ingredients"."id" = $2 AND "ingredients"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), ingredient.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8),($9,$10,$11,$12,$13,$14,$15,$16) RETURNING "sequence"`)).
+		WithArgs(
+			sqlmock.AnyArg(), m.EntityIngredient, ingredient.ID, m.EventDeleted, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+			sqlmock.AnyArg(), m.EntityRecipeIngredients, recipeID, m.EventUpdated, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1).AddRow(2))
+	mock.ExpectCommit()
+
+	err := r.Reassign(ingredient, replacement)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientReassign_SharedErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	replacement := m.Ingredient{ID: uuid.New(), Name: "replacement"}
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	expectReplacement(mock, replacement)
+	expectShared(mock, replacement, 1)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "recipe_ingredients" WHERE ingredient_id = $1 AND recipe_id IN (SELECT "recipe_id" FROM "recipe_ingredients" WHERE ingredient_id = $2) ORDER BY recipe_id LIMIT $3`)).
+		WithArgs(ingredient.ID, replacement.ID, m.UsageSampleSize).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(recipeID))
+	mock.ExpectRollback()
+
+	err := r.Reassign(ingredient, replacement)
+
+	var inUse m.InUseError
+	assert.ErrorAs(t, err, &inUse)
+	assert.EqualError(t, err, "replacement is already used alongside the ingredient by 1 recipes")
+	assert.Equal(t, []uuid.UUID{recipeID}, inUse.Usage.RecipeIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientReassign_ReplacementNotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	replacement := m.Ingredient{ID: uuid.New()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "ingredients" WHERE "ingredients"."deleted_at" IS NULL AND "ingredients"."id" = $1 ORDER BY "ingredients"."id" LIMIT $2`)).
+		WithArgs(replacement.ID, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectRollback()
+
+	err := r.Reassign(ingredient, replacement)
+
+	assert.EqualError(t, err, "replacement not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func expectMergeLookup(mock sqlmock.Sqlmock, target m.Ingredient, sources []m.Ingredient) {
+	expectReplacement(mock, target)
+
+	rows := sqlmock.NewRows([]string{"id", "name"})
+	for _, source := range sources {
+		rows.AddRow(source.ID, source.Name)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "ingredients" WHERE id IN ($1) AND "ingredients"."deleted_at" IS NULL ORDER BY name`)).
+		WithArgs(ingredient.ID).
+		WillReturnRows(rows)
+}
+
+func expectConflicts(mock sqlmock.Sqlmock, target m.Ingredient, recipeIDs ...uuid.UUID) {
+	rows := sqlmock.NewRows([]string{"recipe_id"})
+	for _, recipeID := range recipeIDs {
+		rows.AddRow(recipeID)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "recipe_id" FROM "recipe_ingredients" WHERE ingredient_id IN ($1,$2) GROUP BY "recipe_id" HAVING COUNT(*) > 1 ORDER BY recipe_id`)).
+		WithArgs(target.ID, ingredient.ID).
+		WillReturnRows(rows)
+}
+
+func expectRecipes(mock sqlmock.Sqlmock, recipeIDs ...uuid.UUID) {
+	rows := sqlmock.NewRows([]string{"recipe_id"})
+	for _, recipeID := range recipeIDs {
+		rows.AddRow(recipeID)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "recipe_ingredients" WHERE ingredient_id = $1`)).
+		WithArgs(ingredient.ID).
+		WillReturnRows(rows)
+}
+
+func TestIngredientMerge_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	target := m.Ingredient{ID: uuid.New(), Name: "target"}
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	expectMergeLookup(mock, target, []m.Ingredient{ingredient})
+	expectConflicts(mock, target)
+	expectRecipes(mock, recipeID)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_ingredients" SET "ingredient_id"=$1 WHERE ingredient_id = $2`)).
+		WithArgs(target.ID, ingredient.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "pantry_items" WHERE ingredient_id = $1 AND subject IN (SELECT "subject" FROM "pantry_items" WHERE ingredient_id = $2)`)).
+		WithArgs(ingredient.ID, target.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pantry_items" SET "ingredient_id"=$1 WHERE ingredient_id = $2`)).
+		WithArgs(target.ID, ingredient.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "ingredient_aliases" SET "ingredient_id"=$1 WHERE ingredient_id = $2`)).
+		WithArgs(target.ID, ingredient.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "ingredients" SET "deleted_at"=$1 WHERE "ingredients"."id" = $2 AND "ingredients"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), ingredient.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "ingredient_aliases" ("normalized_name","name","ingredient_id","created_at") VALUES ($1,$2,$3,$4) ON CONFLICT DO NOTHING`)).
+		WithArgs(ingredient.NormalizedName, ingredient.Name, target.ID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8),($9,$10,$11,$12,$13,$14,$15,$16),($17,$18,$19,$20,$21,$22,$23,$24) RETURNING "sequence"`)).
+		WithArgs(
+			sqlmock.AnyArg(), m.EntityIngredient, target.ID, m.EventUpdated, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+			sqlmock.AnyArg(), m.EntityIngredient, ingredient.ID, m.EventDeleted, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+			sqlmock.AnyArg(), m.EntityRecipeIngredients, recipeID, m.EventUpdated, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1).AddRow(2).AddRow(3))
+	mock.ExpectCommit()
+
+	result, err := r.Merge(target, []m.Ingredient{{ID: ingredient.ID}}, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, target.ID, result.Target.ID)
+	assert.Equal(t, []string{ingredient.Name}, result.Aliases)
+	assert.Equal(t, []uuid.UUID{recipeID}, result.RecipeIDs)
+	assert.Empty(t, result.Conflicts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientMerge_Preview(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	target := m.Ingredient{ID: uuid.New(), Name: "target"}
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	expectMergeLookup(mock, target, []m.Ingredient{ingredient})
+	expectConflicts(mock, target, recipeID)
+	expectRecipes(mock, recipeID)
+	mock.ExpectCommit()
+
+	result, err := r.Merge(target, []m.Ingredient{{ID: ingredient.ID}}, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{ingredient.Name}, result.Aliases)
+	assert.Equal(t, []uuid.UUID{recipeID}, result.RecipeIDs)
+	assert.Equal(t, []uuid.UUID{recipeID}, result.Conflicts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientMerge_ConflictErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	target := m.Ingredient{ID: uuid.New(), Name: "target"}
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	expectMergeLookup(mock, target, []m.Ingredient{ingredient})
+	expectConflicts(mock, target, recipeID)
+	expectRecipes(mock, recipeID)
+	mock.ExpectRollback()
+
+	_, err := r.Merge(target, []m.Ingredient{{ID: ingredient.ID}}, false)
+
+	var inUse m.InUseError
+	assert.ErrorAs(t, err, &inUse)
+	assert.EqualError(t, err, "1 recipes use more than one of the merged ingredients")
+	assert.Equal(t, []uuid.UUID{recipeID}, inUse.Usage.RecipeIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientMerge_TargetNotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	target := m.Ingredient{ID: uuid.New()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "ingredients" WHERE "ingredients"."deleted_at" IS NULL AND "ingredients"."id" = $1 ORDER BY "ingredients"."id" LIMIT $2`)).
+		WithArgs(target.ID, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectRollback()
+
+	_, err := r.Merge(target, []m.Ingredient{{ID: ingredient.ID}}, false)
+
+	assert.EqualError(t, err, "target not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientMerge_SourceNotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	target := m.Ingredient{ID: uuid.New(), Name: "target"}
+
+	mock.ExpectBegin()
+	expectMergeLookup(mock, target, nil)
+	mock.ExpectRollback()
+
+	_, err := r.Merge(target, []m.Ingredient{{ID: ingredient.ID}}, false)
+
+	assert.EqualError(t, err, "source not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientFindByName_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "ingredients" WHERE (normalized_name = $1 OR id IN (SELECT "ingredient_id" FROM "ingredient_aliases" WHERE normalized_name = $2)) AND "ingredients"."deleted_at" IS NULL ORDER BY "ingredients"."id" LIMIT $3`)).
+		WithArgs("ingredient", "ingredient", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "normalized_name"}).AddRow(ingredient.ID, ingredient.Name, ingredient.NormalizedName))
+	expectAliases(mock)
+
+	result, err := r.FindByName(" Ingredients")
+
+	assert.NoError(t, err)
+	assert.Equal(t, ingredient.ID, result.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientFindByName_NotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "ingredients" WHERE (normalized_name = $1 OR id IN (SELECT "ingredient_id" FROM "ingredient_aliases" WHERE normalized_name = $2)) AND "ingredients"."deleted_at" IS NULL ORDER BY "ingredients"."id" LIMIT $3`)).
+		WithArgs("egg", "egg", 1).
+		WillReturnRows(&sqlmock.Rows{})
+
+	_, err := r.FindByName("Eggs")
+
+	assert.EqualError(t, err, "not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientCreate_Aliases(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	input := m.IngredientDTO{Name: "Egg", Plural: "eggs", Aliases: []string{"Eggs", "hen's egg"}}.ConvertFromDTO()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "ingredients" ("name","normalized_name","plural","staple","density","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING "id"`)).
+		WithArgs("Egg", "egg", "eggs", false, 0.0, sqlmock.AnyArg(), sqlmock.AnyArg(), nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(ingredient.ID))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "ingredient_aliases" ("normalized_name","name","ingredient_id","created_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs("hen's egg", "hen's egg", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1))
+	mock.ExpectCommit()
+
+	result, err := r.Create(input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"hen's egg"}, result.ConvertToDTO().Aliases)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientCreate_DuplicateAliases(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	input := m.Ingredient{Name: "Egg", NormalizedName: "egg", Aliases: []m.IngredientAlias{
+		m.NewIngredientAlias("Hen's egg", uuid.Nil),
+		m.NewIngredientAlias("hen's egg", uuid.Nil),
+		m.NewIngredientAlias("EGGS", uuid.Nil),
+	}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "ingredients"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(ingredient.ID))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "ingredient_aliases" ("normalized_name","name","ingredient_id","created_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs("hen's egg", "Hen's egg", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1))
+	mock.ExpectCommit()
+
+	result, err := r.Create(input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Hen's egg"}, result.ConvertToDTO().Aliases)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientUpdate_Aliases(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	input := ingredient
+	input.Aliases = []m.IngredientAlias{m.NewIngredientAlias("component", ingredient.ID)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "ingredients" SET "name"=$1,"normalized_name"=$2,"plural"=$3,"staple"=$4,"density"=$5,"updated_at"=$6 WHERE "ingredients"."deleted_at" IS NULL AND "id" = $7`)).
+		WithArgs(ingredient.Name, ingredient.NormalizedName, ingredient.Plural, ingredient.Staple, ingredient.Density, sqlmock.AnyArg(), ingredient.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "ingredient_aliases" WHERE ingredient_id = $1`)).
+		WithArgs(ingredient.ID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "ingredient_aliases" ("normalized_name","name","ingredient_id","created_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs("component", "component", ingredient.ID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutbox(mock, m.EventUpdated)
+	mock.ExpectCommit()
+
+	_, err := r.Update(input)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientNormalizeNames_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "ingredients" WHERE normalized_name = ''`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(ingredient.ID, "Eggs"))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "ingredients" SET "normalized_name"=$1 WHERE "id" = $2`)).
+		WithArgs("egg", ingredient.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	normalized, err := r.NormalizeNames()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, normalized)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientNormalizeNames_Duplicate(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "ingredients" WHERE normalized_name = ''`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(ingredient.ID, "Eggs"))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "ingredients" SET "normalized_name"=$1 WHERE "id" = $2`)).
+		WithArgs("egg", ingredient.ID).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	normalized, err := r.NormalizeNames()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, normalized)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientNormalizeNames_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewIngredientRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "ingredients" WHERE normalized_name = ''`)).
+		WillReturnError(errors.New("error"))
+
+	_, err := r.NormalizeNames()
+
+	assert.EqualError(t, err, "error")
+}
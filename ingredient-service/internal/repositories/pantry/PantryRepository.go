@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	m "ingredient-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PantryRepository struct {
+	db *gorm.DB
+}
+
+func NewPantryRepository(db *gorm.DB) *PantryRepository {
+	return &PantryRepository{
+		db: db,
+	}
+}
+
+// matchRow is a recipe with the number of its ingredient lines and how many of them are available
+type matchRow struct {
+	RecipeID    uuid.UUID
+	Ingredients int
+	Matched     int
+}
+
+type missingRow struct {
+	RecipeID uuid.UUID
+	m.Ingredient
+}
+
+// FindBySubject returns the pantry of a user. An empty pantry is not an error.
+func (r PantryRepository) FindBySubject(subject string) ([]m.PantryItem, error) {
+	var pantryItems []m.PantryItem
+
+	if err := r.db.Preload("Ingredient").Where("subject = ?", subject).Order("created_at").Find(&pantryItems).Error; err != nil {
+		return nil, err
+	}
+
+	return pantryItems, nil
+}
+
+// Replace swaps the contents of the pantry of a user in a single transaction
+func (r PantryRepository) Replace(subject string, pantryItems []m.PantryItem) error {
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Where("subject = ?", subject).Delete(&m.PantryItem{}).Error; err != nil {
+			return err
+		}
+
+		if len(pantryItems) <= 0 {
+			return nil
+		}
+
+		if err := tx.Omit(clause.Associations).Create(&pantryItems).Error; err != nil {
+			return err
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Match ranks the recipes using at least one of the available ingredients by the share of their ingredient lines
+// that are covered, then by the number of missing lines. Regardless of the number of recipes this takes two queries,
+// one for the ranking and one for the missing ingredients of the returned recipes.
+func (r PantryRepository) Match(request m.PantryMatchRequest) ([]m.PantryMatch, error) {
+	var rows []matchRow
+	var missing []missingRow
+
+	matched := gorm.Expr("count(*) FILTER (WHERE recipe_ingredients.ingredient_id IN ?)", request.IngredientIDs)
+
+	query := r.lines(request.IgnoreStaples).
+		Select("recipe_ingredients.recipe_id, count(*) AS ingredients, ? AS matched", matched).
+		Group("recipe_ingredients.recipe_id").
+		Having("? > 0", matched)
+
+	if request.MaxMissing != nil {
+		query = query.Having("count(*) - ? <= ?", matched, *request.MaxMissing)
+	}
+
+	if err := query.
+		Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "?::float / count(*) DESC, count(*) - ?, recipe_ingredients.recipe_id",
+			Vars:               []interface{}{matched, matched},
+			WithoutParentheses: true,
+		}}).
+		Limit(request.Limit).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	if len(rows) <= 0 {
+		return []m.PantryMatch{}, nil
+	}
+
+	recipeIDs := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		recipeIDs = append(recipeIDs, row.RecipeID)
+	}
+
+	if err := r.lines(request.IgnoreStaples).
+		Select("recipe_ingredients.recipe_id, ingredients.*").
+		Where("recipe_ingredients.recipe_id IN ?", recipeIDs).
+		Where("recipe_ingredients.ingredient_id NOT IN ?", request.IngredientIDs).
+		Order("ingredients.name").
+		Scan(&missing).Error; err != nil {
+		return nil, err
+	}
+
+	byRecipe := make(map[uuid.UUID][]m.Ingredient, len(rows))
+	for _, line := range missing {
+		byRecipe[line.RecipeID] = append(byRecipe[line.RecipeID], line.Ingredient)
+	}
+
+	matches := make([]m.PantryMatch, 0, len(rows))
+	for _, row := range rows {
+		matches = append(matches, m.PantryMatch{
+			RecipeID:    row.RecipeID,
+			Ingredients: row.Ingredients,
+			Matched:     row.Matched,
+			Missing:     byRecipe[row.RecipeID],
+		})
+	}
+
+	return matches, nil
+}
+
+// lines selects the ingredient lines of all recipes together with their ingredient, leaving out the lines of
+// deleted recipes
+func (r PantryRepository) lines(ignoreStaples bool) *gorm.DB {
+	query := r.db.Table("recipe_ingredients").
+		Joins("JOIN ingredients ON ingredients.id = recipe_ingredients.ingredient_id").
+		Where("recipe_ingredients.deleted_at IS NULL")
+
+	if ignoreStaples {
+		query = query.Where("NOT ingredients.staple")
+	}
+
+	return query
+}
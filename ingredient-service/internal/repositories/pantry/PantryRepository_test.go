@@ -0,0 +1,224 @@
+package repositories
+
+import (
+	"errors"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	m "ingredient-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	subject    string    = "f1a5b4d2-0c6e-4b55-9a35-2d3a1b5f3e77"
+	salt       uuid.UUID = uuid.New()
+	rice       uuid.UUID = uuid.New()
+	chicken    uuid.UUID = uuid.New()
+	recipeID   uuid.UUID = uuid.New()
+	maxMissing int       = 2
+
+	matched = `count(*) FILTER (WHERE recipe_ingredients.ingredient_id IN ($1,$2))`
+)
+
+func newMockDatabase(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+
+	var mockDB *gorm.DB
+
+	newLogger := logger.New(
+		log.New(os.Stdout, "\r\n", log.LstdFlags), // io writer
+		logger.Config{
+			SlowThreshold:             time.Second, // Slow SQL threshold
+			LogLevel:                  logger.Info, // Log level
+			IgnoreRecordNotFoundError: true,        // Ignore ErrRecordNotFound error for logger
+			Colorful:                  false,       // Disable color
+		},
+	)
+
+	sqlMockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sql mock init failed: %v", err.Error())
+	}
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 sqlMockDB,
+		PreferSimpleProtocol: true,
+	})
+
+	mockDB, err = gorm.Open(dialector, &gorm.Config{
+		NowFunc: timeFunc,
+		Logger:  newLogger,
+	})
+	if err != nil {
+		t.Fatalf("gorm mock init failed: %v", err.Error())
+	}
+
+	return mockDB, mock
+}
+
+func timeFunc() time.Time {
+	time, _ := time.Parse("2006-01-02 15:04", "2023-02-04 18:00")
+	return time
+}
+
+func TestPantryFindBySubject_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewPantryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pantry_items" WHERE subject = $1 ORDER BY created_at`)).
+		WithArgs(subject).
+		WillReturnRows(sqlmock.NewRows([]string{"subject", "ingredient_id"}).AddRow(subject, rice))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "ingredients" WHERE "ingredients"."id" = $1 AND "ingredients"."deleted_at" IS NULL`)).
+		WithArgs(rice).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(rice, "rice"))
+
+	result, err := r.FindBySubject(subject)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "rice", result[0].Ingredient.Name)
+}
+
+func TestPantryFindBySubject_Empty(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewPantryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pantry_items" WHERE subject = $1 ORDER BY created_at`)).
+		WithArgs(subject).
+		WillReturnRows(&sqlmock.Rows{})
+
+	result, err := r.FindBySubject(subject)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 0)
+}
+
+func TestPantryReplace_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewPantryRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "pantry_items" WHERE subject = $1`)).
+		WithArgs(subject).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "pantry_items" ("subject","ingredient_id","created_at") VALUES ($1,$2,$3),($4,$5,$6)`)).
+		WithArgs(subject, rice, sqlmock.AnyArg(), subject, chicken, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	mock.ExpectCommit()
+
+	err := r.Replace(subject, []m.PantryItem{{Subject: subject, IngredientID: rice}, {Subject: subject, IngredientID: chicken}})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPantryReplace_Empty(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewPantryRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "pantry_items" WHERE subject = $1`)).
+		WithArgs(subject).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := r.Replace(subject, nil)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPantryReplace_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewPantryRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "pantry_items" WHERE subject = $1`)).
+		WithArgs(subject).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	err := r.Replace(subject, nil)
+
+	assert.EqualError(t, err, "error")
+}
+
+func TestPantryMatch_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewPantryRepository(db)
+
+	otherRecipeID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT recipe_ingredients.recipe_id, count(*) AS ingredients, `+matched+` AS matched `+
+		`FROM "recipe_ingredients" JOIN ingredients ON ingredients.id = recipe_ingredients.ingredient_id WHERE recipe_ingredients.deleted_at IS NULL AND NOT ingredients.staple `+
+		`GROUP BY "recipe_ingredients"."recipe_id" `+
+		`HAVING count(*) FILTER (WHERE recipe_ingredients.ingredient_id IN ($3,$4)) > 0 AND count(*) - count(*) FILTER (WHERE recipe_ingredients.ingredient_id IN ($5,$6)) <= $7 `+
+		`ORDER BY count(*) FILTER (WHERE recipe_ingredients.ingredient_id IN ($8,$9))::float / count(*) DESC, count(*) - count(*) FILTER (WHERE recipe_ingredients.ingredient_id IN ($10,$11)), recipe_ingredients.recipe_id `+
+		`LIMIT $12`)).
+		WithArgs(rice, chicken, rice, chicken, rice, chicken, maxMissing, rice, chicken, rice, chicken, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "ingredients", "matched"}).
+			AddRow(recipeID, 2, 2).
+			AddRow(otherRecipeID, 3, 1))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT recipe_ingredients.recipe_id, ingredients.* FROM "recipe_ingredients" JOIN ingredients ON ingredients.id = recipe_ingredients.ingredient_id `+
+		`WHERE recipe_ingredients.deleted_at IS NULL AND NOT ingredients.staple AND recipe_ingredients.recipe_id IN ($1,$2) AND recipe_ingredients.ingredient_id NOT IN ($3,$4) ORDER BY ingredients.name`)).
+		WithArgs(recipeID, otherRecipeID, rice, chicken).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "id", "name", "staple"}).
+			AddRow(otherRecipeID, uuid.New(), "garlic", false).
+			AddRow(otherRecipeID, uuid.New(), "onion", false))
+
+	result, err := r.Match(m.PantryMatchRequest{
+		IngredientIDs: []uuid.UUID{rice, chicken},
+		MaxMissing:    &maxMissing,
+		IgnoreStaples: true,
+		Limit:         20,
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, result, 2)
+	assert.Equal(t, m.PantryMatch{RecipeID: recipeID, Ingredients: 2, Matched: 2}, result[0])
+	assert.Equal(t, otherRecipeID, result[1].RecipeID)
+	assert.Equal(t, 1, result[1].Matched)
+	assert.Len(t, result[1].Missing, 2)
+	assert.Equal(t, "garlic", result[1].Missing[0].Name)
+}
+
+func TestPantryMatch_NoMatches(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewPantryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT recipe_ingredients.recipe_id, count(*) AS ingredients, ` + matched + ` AS matched ` +
+		`FROM "recipe_ingredients" JOIN ingredients ON ingredients.id = recipe_ingredients.ingredient_id WHERE recipe_ingredients.deleted_at IS NULL ` +
+		`GROUP BY "recipe_ingredients"."recipe_id" HAVING count(*) FILTER (WHERE recipe_ingredients.ingredient_id IN ($3,$4)) > 0 ORDER BY`)).
+		WillReturnRows(&sqlmock.Rows{})
+
+	result, err := r.Match(m.PantryMatchRequest{IngredientIDs: []uuid.UUID{rice, salt}, Limit: 20})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []m.PantryMatch{}, result)
+}
+
+func TestPantryMatch_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewPantryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT recipe_ingredients.recipe_id`)).
+		WillReturnError(errors.New("error"))
+
+	result, err := r.Match(m.PantryMatchRequest{IngredientIDs: []uuid.UUID{rice}, Limit: 20})
+
+	assert.EqualError(t, err, "error")
+	assert.Nil(t, result)
+}
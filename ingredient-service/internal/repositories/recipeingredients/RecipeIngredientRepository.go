@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"cookbook/pkg/outbox"
+	m "ingredient-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecipeIngredientRepository struct {
+	db *gorm.DB
+}
+
+func NewRecipeIngredientRepository(db *gorm.DB) *RecipeIngredientRepository {
+	return &RecipeIngredientRepository{
+		db: db,
+	}
+}
+
+func (r RecipeIngredientRepository) FindByRecipe(recipeID uuid.UUID) ([]m.RecipeIngredient, error) {
+	var recipeIngredients []m.RecipeIngredient
+
+	if err := r.db.Preload("Ingredient").Preload("Unit").Where("recipe_id = ?", recipeID).Find(&recipeIngredients).Error; err != nil {
+		return nil, err
+	}
+
+	if len(recipeIngredients) <= 0 {
+		return nil, errors.New("not found")
+	}
+
+	return recipeIngredients, nil
+}
+
+func (r RecipeIngredientRepository) FindByRecipes(recipeIDs []uuid.UUID) ([]m.RecipeIngredient, error) {
+	var recipeIngredients []m.RecipeIngredient
+
+	if err := r.db.Preload("Ingredient").Preload("Unit").Where("recipe_id IN ?", recipeIDs).Find(&recipeIngredients).Error; err != nil {
+		return nil, err
+	}
+
+	if len(recipeIngredients) <= 0 {
+		return nil, errors.New("not found")
+	}
+
+	return recipeIngredients, nil
+}
+
+func (r RecipeIngredientRepository) FindSingle(recipeIngredient m.RecipeIngredient) (m.RecipeIngredient, error) {
+
+	result := r.db.Preload("Ingredient").Preload("Unit").
+		Where("recipe_id = ? AND ingredient_id = ?", recipeIngredient.RecipeID, recipeIngredient.IngredientID).
+		First(&recipeIngredient)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return m.RecipeIngredient{}, errors.New("not found")
+		} else {
+			return m.RecipeIngredient{}, result.Error
+		}
+	}
+
+	return recipeIngredient, nil
+}
+
+// Create adds a single ingredient line. The referenced ingredient and unit are never created or updated from here.
+func (r RecipeIngredientRepository) Create(recipeIngredient m.RecipeIngredient) (m.RecipeIngredient, error) {
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Omit(clause.Associations).Create(&recipeIngredient).Error; err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventUpdated, m.EntityRecipeIngredients, recipeIngredient.RecipeID))
+	}); err != nil {
+		return recipeIngredient, err
+	}
+
+	return recipeIngredient, nil
+}
+
+// Update changes the amount and unit of an ingredient line. The columns are listed, so a range or "to taste" can be
+// cleared again.
+func (r RecipeIngredientRepository) Update(recipeIngredient m.RecipeIngredient) (m.RecipeIngredient, error) {
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Select("quantity", "max_quantity", "to_taste", "unit_id").Omit(clause.Associations).Updates(&recipeIngredient).Error; err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventUpdated, m.EntityRecipeIngredients, recipeIngredient.RecipeID))
+	}); err != nil {
+		return recipeIngredient, err
+	}
+
+	return recipeIngredient, nil
+}
+
+// Replace swaps all ingredient lines of a recipe for the given lines in a single transaction. The old lines are removed
+// permanently, as they would otherwise collide with the new ones on the primary keys.
+func (r RecipeIngredientRepository) Replace(recipeID uuid.UUID, recipeIngredients []m.RecipeIngredient) error {
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Unscoped().Where("recipe_id = ?", recipeID).Delete(&m.RecipeIngredient{}).Error; err != nil {
+			return err
+		}
+
+		if len(recipeIngredients) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&recipeIngredients).Error; err != nil {
+				return err
+			}
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventUpdated, m.EntityRecipeIngredients, recipeID))
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes a single ingredient line permanently, so the ingredient can be added to the recipe again
+func (r RecipeIngredientRepository) Delete(recipeIngredient m.RecipeIngredient) error {
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Unscoped().Where("recipe_id = ? AND ingredient_id = ?", recipeIngredient.RecipeID, recipeIngredient.IngredientID).
+			Delete(&m.RecipeIngredient{}).Error; err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventUpdated, m.EntityRecipeIngredients, recipeIngredient.RecipeID))
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteByRecipe soft-deletes all ingredient lines of a deleted recipe, so they can be restored along with the recipe
+func (r RecipeIngredientRepository) DeleteByRecipe(recipeID uuid.UUID) error {
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Where("recipe_id = ?", recipeID).Delete(&m.RecipeIngredient{}).Error; err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventDeleted, m.EntityRecipeIngredients, recipeID))
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RestoreByRecipe restores the ingredient lines of a recipe that were deleted since the given time. As single lines
+// are removed permanently, only the lines deleted along with the recipe are restored.
+func (r RecipeIngredientRepository) RestoreByRecipe(recipeID uuid.UUID, since time.Time) error {
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Unscoped().Model(&m.RecipeIngredient{}).
+			Where("recipe_id = ? AND deleted_at >= ?", recipeID, since).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventCreated, m.EntityRecipeIngredients, recipeID))
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PurgeByRecipe permanently removes the ingredient lines of a deleted recipe that can no longer be restored. The
+// lines of a recipe that was restored in the meantime are kept.
+func (r RecipeIngredientRepository) PurgeByRecipe(recipeID uuid.UUID) error {
+
+	if err := r.db.Unscoped().
+		Where("recipe_id = ? AND deleted_at IS NOT NULL", recipeID).
+		Delete(&m.RecipeIngredient{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,382 @@
+package repositories
+
+import (
+	"errors"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	m "ingredient-service/internal/models"
+	"ingredient-service/internal/quantity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	recipeIngredient m.RecipeIngredient = m.RecipeIngredient{
+		RecipeID:     uuid.New(),
+		IngredientID: uuid.New(),
+		Quantity:     quantity.New(3, 2),
+		MaxQuantity:  quantity.New(2, 1),
+		UnitID:       uuid.New(),
+	}
+)
+
+func newMockDatabase(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+
+	var mockDB *gorm.DB
+
+	newLogger := logger.New(
+		log.New(os.Stdout, "\r\n", log.LstdFlags), // io writer
+		logger.Config{
+			SlowThreshold:             time.Second, // Slow SQL threshold
+			LogLevel:                  logger.Info, // Log level
+			IgnoreRecordNotFoundError: true,        // Ignore ErrRecordNotFound error for logger
+			Colorful:                  false,       // Disable color
+		},
+	)
+
+	sqlMockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sql mock init failed: %v", err.Error())
+	}
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 sqlMockDB,
+		PreferSimpleProtocol: true,
+	})
+
+	mockDB, err = gorm.Open(dialector, &gorm.Config{
+		NowFunc: timeFunc,
+		Logger:  newLogger,
+	})
+	if err != nil {
+		t.Fatalf("gorm mock init failed: %v", err.Error())
+	}
+
+	return mockDB, mock
+}
+
+func timeFunc() time.Time {
+	time, _ := time.Parse("2006-01-02 15:04", "2023-02-04 18:00")
+	return time
+}
+
+func expectOutbox(mock sqlmock.Sqlmock, eventType string) {
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "sequence"`)).
+		WithArgs(sqlmock.AnyArg(), m.EntityRecipeIngredients, recipeIngredient.RecipeID, eventType, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "").
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1))
+}
+
+func TestRecipeIngredientFindByRecipe_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeIngredientRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_ingredients" WHERE recipe_id = $1`)).
+		WithArgs(recipeIngredient.RecipeID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "ingredient_id", "quantity", "max_quantity", "unit_id"}).
+			AddRow(
+				recipeIngredient.RecipeID,
+				recipeIngredient.IngredientID,
+				"3/2",
+				"2",
+				recipeIngredient.UnitID,
+			))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "ingredients" WHERE "ingredients"."id" = $1 AND "ingredients"."deleted_at" IS NULL`)).
+		WithArgs(recipeIngredient.IngredientID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(recipeIngredient.IngredientID, "ingredient"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "units" WHERE "units"."id" = $1 AND "units"."deleted_at" IS NULL`)).
+		WithArgs(recipeIngredient.UnitID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "full_name", "short_name"}).
+			AddRow(recipeIngredient.UnitID, "gram", "g"))
+
+	result, err := r.FindByRecipe(recipeIngredient.RecipeID)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, recipeIngredient.Quantity, result[0].Quantity)
+	assert.Equal(t, recipeIngredient.MaxQuantity, result[0].MaxQuantity)
+	assert.Equal(t, "ingredient", result[0].Ingredient.Name)
+	assert.Equal(t, "g", result[0].Unit.ShortName)
+}
+
+func TestRecipeIngredientFindByRecipe_NotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeIngredientRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_ingredients" WHERE recipe_id = $1`)).
+		WithArgs(recipeIngredient.RecipeID).
+		WillReturnRows(&sqlmock.Rows{})
+
+	result, err := r.FindByRecipe(recipeIngredient.RecipeID)
+
+	assert.EqualError(t, err, "not found")
+	assert.Len(t, result, 0)
+}
+
+func TestRecipeIngredientFindByRecipe_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeIngredientRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_ingredients" WHERE recipe_id = $1`)).
+		WithArgs(recipeIngredient.RecipeID).
+		WillReturnError(errors.New("error"))
+
+	result, err := r.FindByRecipe(recipeIngredient.RecipeID)
+
+	assert.EqualError(t, err, "error")
+	assert.Len(t, result, 0)
+}
+
+func TestRecipeIngredientFindByRecipes_NotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeIngredientRepository(db)
+	otherRecipeID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_ingredients" WHERE recipe_id IN ($1,$2)`)).
+		WithArgs(recipeIngredient.RecipeID, otherRecipeID).
+		WillReturnRows(&sqlmock.Rows{})
+
+	result, err := r.FindByRecipes([]uuid.UUID{recipeIngredient.RecipeID, otherRecipeID})
+
+	assert.EqualError(t, err, "not found")
+	assert.Len(t, result, 0)
+}
+
+func TestRecipeIngredientFindSingle_NotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeIngredientRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_ingredients" WHERE (recipe_id = $1 AND ingredient_id = $2) AND "recipe_ingredients"."deleted_at" IS NULL AND "recipe_ingredients"."recipe_id" = $3 AND "recipe_ingredients"."ingredient_id" = $4 ORDER BY "recipe_ingredients"."recipe_id" LIMIT $5`)).
+		WillReturnRows(&sqlmock.Rows{})
+
+	result, err := r.FindSingle(recipeIngredient)
+
+	assert.EqualError(t, err, "not found")
+	assert.Equal(t, m.RecipeIngredient{}, result)
+}
+
+func TestRecipeIngredientCreate_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeIngredientRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_ingredients" ("recipe_id","ingredient_id","quantity","max_quantity","to_taste","unit_id","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7)`)).
+		WithArgs(
+			recipeIngredient.RecipeID,
+			recipeIngredient.IngredientID,
+			recipeIngredient.Quantity,
+			recipeIngredient.MaxQuantity,
+			recipeIngredient.ToTaste,
+			recipeIngredient.UnitID,
+			nil,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutbox(mock, m.EventUpdated)
+	mock.ExpectCommit()
+
+	result, err := r.Create(recipeIngredient)
+
+	assert.NoError(t, err)
+	assert.Equal(t, recipeIngredient, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeIngredientUpdate_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeIngredientRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_ingredients" SET "quantity"=$1,"max_quantity"=$2,"to_taste"=$3,"unit_id"=$4 WHERE "recipe_ingredients"."deleted_at" IS NULL AND "recipe_id" = $5 AND "ingredient_id" = $6`)).
+		WithArgs(
+			recipeIngredient.Quantity,
+			recipeIngredient.MaxQuantity,
+			recipeIngredient.ToTaste,
+			recipeIngredient.UnitID,
+			recipeIngredient.RecipeID,
+			recipeIngredient.IngredientID,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutbox(mock, m.EventUpdated)
+	mock.ExpectCommit()
+
+	result, err := r.Update(recipeIngredient)
+
+	assert.NoError(t, err)
+	assert.Equal(t, recipeIngredient, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeIngredientReplace_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeIngredientRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_ingredients" WHERE recipe_id = $1`)).
+		WithArgs(recipeIngredient.RecipeID).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_ingredients" ("recipe_id","ingredient_id","quantity","max_quantity","to_taste","unit_id","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7)`)).
+		WithArgs(
+			recipeIngredient.RecipeID,
+			recipeIngredient.IngredientID,
+			recipeIngredient.Quantity,
+			recipeIngredient.MaxQuantity,
+			recipeIngredient.ToTaste,
+			recipeIngredient.UnitID,
+			nil,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutbox(mock, m.EventUpdated)
+	mock.ExpectCommit()
+
+	err := r.Replace(recipeIngredient.RecipeID, []m.RecipeIngredient{recipeIngredient})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeIngredientReplace_Empty(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeIngredientRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_ingredients" WHERE recipe_id = $1`)).
+		WithArgs(recipeIngredient.RecipeID).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	expectOutbox(mock, m.EventUpdated)
+	mock.ExpectCommit()
+
+	err := r.Replace(recipeIngredient.RecipeID, []m.RecipeIngredient{})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeIngredientReplace_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeIngredientRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_ingredients" WHERE recipe_id = $1`)).
+		WithArgs(recipeIngredient.RecipeID).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_ingredients"`)).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	err := r.Replace(recipeIngredient.RecipeID, []m.RecipeIngredient{recipeIngredient})
+
+	assert.EqualError(t, err, "error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeIngredientDelete_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeIngredientRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_ingredients" WHERE recipe_id = $1 AND ingredient_id = $2`)).
+		WithArgs(
+			recipeIngredient.RecipeID,
+			recipeIngredient.IngredientID,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutbox(mock, m.EventUpdated)
+	mock.ExpectCommit()
+
+	err := r.Delete(recipeIngredient)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeIngredientDeleteByRecipe_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeIngredientRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_ingredients" SET "deleted_at"=$1 WHERE recipe_id = $2 AND "recipe_ingredients"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), recipeIngredient.RecipeID).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	expectOutbox(mock, m.EventDeleted)
+	mock.ExpectCommit()
+
+	err := r.DeleteByRecipe(recipeIngredient.RecipeID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeIngredientDeleteByRecipe_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeIngredientRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_ingredients" SET "deleted_at"=$1`)).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	err := r.DeleteByRecipe(recipeIngredient.RecipeID)
+
+	assert.EqualError(t, err, "error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeIngredientRestoreByRecipe_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeIngredientRepository(db)
+	since := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_ingredients" SET "deleted_at"=$1 WHERE recipe_id = $2 AND deleted_at >= $3`)).
+		WithArgs(nil, recipeIngredient.RecipeID, since).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	expectOutbox(mock, m.EventCreated)
+	mock.ExpectCommit()
+
+	err := r.RestoreByRecipe(recipeIngredient.RecipeID, since)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeIngredientPurgeByRecipe_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeIngredientRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_ingredients" WHERE recipe_id = $1 AND deleted_at IS NOT NULL`)).
+		WithArgs(recipeIngredient.RecipeID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := r.PurgeByRecipe(recipeIngredient.RecipeID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeIngredientPurgeByRecipe_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeIngredientRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_ingredients" WHERE recipe_id = $1 AND deleted_at IS NOT NULL`)).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	err := r.PurgeByRecipe(recipeIngredient.RecipeID)
+
+	assert.EqualError(t, err, "error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,117 @@
+package references
+
+import (
+	m "ingredient-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Usage counts the recipes whose ingredient lines reference an entity in the given column, e.g. unit_id, and
+// samples a few of them. The lines of deleted recipes are counted apart, as they come back when such a recipe is
+// restored. They are kept until the restore window of the recipe has passed and the recipe service purges them.
+func Usage(tx *gorm.DB, column string, id uuid.UUID) (m.Usage, error) {
+	var usage m.Usage
+	var err error
+
+	usage.Count, usage.RecipeIDs, err = sample(func() *gorm.DB {
+		return tx.Model(&m.RecipeIngredient{}).Where(column+" = ?", id)
+	})
+	if err != nil {
+		return m.Usage{}, err
+	}
+
+	usage.DeletedCount, usage.DeletedRecipeIDs, err = sample(func() *gorm.DB {
+		return tx.Unscoped().Model(&m.RecipeIngredient{}).Where(column+" = ? AND deleted_at IS NOT NULL", id)
+	})
+	if err != nil {
+		return m.Usage{}, err
+	}
+
+	return usage, nil
+}
+
+// sample counts the recipes of the ingredient lines a query selects, and returns the first few of them
+func sample(lines func() *gorm.DB) (int64, []uuid.UUID, error) {
+	var count int64
+	var recipeIDs []uuid.UUID
+
+	if err := lines().Distinct("recipe_id").Count(&count).Error; err != nil {
+		return 0, nil, err
+	}
+
+	if count == 0 {
+		return 0, nil, nil
+	}
+
+	if err := lines().Distinct().Order("recipe_id").Limit(m.UsageSampleSize).Pluck("recipe_id", &recipeIDs).Error; err != nil {
+		return 0, nil, err
+	}
+
+	return count, recipeIDs, nil
+}
+
+// Recipes returns all recipes whose ingredient lines reference an entity in the given column, including the
+// lines of deleted recipes, so they are moved along and still complete when such a recipe is restored
+func Recipes(tx *gorm.DB, column string, id uuid.UUID) ([]uuid.UUID, error) {
+	var recipeIDs []uuid.UUID
+
+	if err := tx.Unscoped().Model(&m.RecipeIngredient{}).
+		Where(column+" = ?", id).
+		Distinct().
+		Pluck("recipe_id", &recipeIDs).Error; err != nil {
+		return nil, err
+	}
+
+	return recipeIDs, nil
+}
+
+// Shared counts the recipes whose ingredient lines reference both entities in the given column, and samples a
+// few of them. Deleted recipes are included, as their lines are moved along by Reassign as well.
+func Shared(tx *gorm.DB, column string, from uuid.UUID, to uuid.UUID) (m.Usage, error) {
+	usage := m.Usage{}
+
+	shared := func() *gorm.DB {
+		return tx.Unscoped().Model(&m.RecipeIngredient{}).
+			Where(column+" = ? AND recipe_id IN (?)", from, tx.Unscoped().Model(&m.RecipeIngredient{}).Select("recipe_id").Where(column+" = ?", to))
+	}
+
+	if err := shared().Distinct("recipe_id").Count(&usage.Count).Error; err != nil {
+		return m.Usage{}, err
+	}
+
+	if usage.Count == 0 {
+		return usage, nil
+	}
+
+	if err := shared().Distinct().Order("recipe_id").Limit(m.UsageSampleSize).Pluck("recipe_id", &usage.RecipeIDs).Error; err != nil {
+		return m.Usage{}, err
+	}
+
+	return usage, nil
+}
+
+// Reassign moves the ingredient lines referencing an entity in the given column to the replacement
+func Reassign(tx *gorm.DB, column string, from uuid.UUID, to uuid.UUID) error {
+
+	return tx.Unscoped().Model(&m.RecipeIngredient{}).
+		Where(column+" = ?", from).
+		Update(column, to).Error
+}
+
+// Conflicts returns the recipes whose ingredient lines reference more than one of the given entities in the given
+// column, including deleted recipes. Merging the entities would list the same one twice in these recipes.
+func Conflicts(tx *gorm.DB, column string, ids []uuid.UUID) ([]uuid.UUID, error) {
+	var recipeIDs []uuid.UUID
+
+	if err := tx.Unscoped().Model(&m.RecipeIngredient{}).
+		Where(column+" IN ?", ids).
+		Group("recipe_id").
+		Having("COUNT(*) > 1").
+		Order("recipe_id").
+		Pluck("recipe_id", &recipeIDs).Error; err != nil {
+		return nil, err
+	}
+
+	return recipeIDs, nil
+}
@@ -3,8 +3,13 @@ package repositories
 import (
 	"errors"
 
+	"cookbook/pkg/outbox"
+	"ingredient-service/internal/conversion"
 	m "ingredient-service/internal/models"
+	"ingredient-service/internal/quantity"
+	"ingredient-service/internal/repositories/references"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -54,7 +59,7 @@ func (r UnitRepository) Create(unit m.Unit) (m.Unit, error) {
 			return err
 		}
 
-		return nil
+		return outbox.Record(tx, m.NewChangeEvent(m.EventCreated, m.EntityUnit, unit.ID))
 	}); err != nil {
 		return unit, err
 	}
@@ -66,11 +71,12 @@ func (r UnitRepository) Update(unit m.Unit) (m.Unit, error) {
 
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
 
-		if err := tx.Updates(&unit).Error; err != nil {
+		// the conversion fields are selected explicitly, as Updates would skip them when they are reset
+		if err := tx.Select("full_name", "short_name", "dimension", "system", "factor", "offset").Updates(&unit).Error; err != nil {
 			return err
 		}
 
-		return nil
+		return outbox.Record(tx, m.NewChangeEvent(m.EventUpdated, m.EntityUnit, unit.ID))
 	}); err != nil {
 		return unit, err
 	}
@@ -78,18 +84,183 @@ func (r UnitRepository) Update(unit m.Unit) (m.Unit, error) {
 	return unit, nil
 }
 
+// Delete removes a unit that is not used by any recipe, otherwise an InUseError is returned
 func (r UnitRepository) Delete(unit m.Unit) error {
 
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
 
+		usage, err := references.Usage(tx, "unit_id", unit.ID)
+		if err != nil {
+			return err
+		}
+
+		if usage.InUse() {
+			return m.NewInUseError("unit", usage)
+		}
+
 		if err := tx.Delete(&unit).Error; err != nil {
 			return err
 		}
 
-		return nil
+		return outbox.Record(tx, m.NewChangeEvent(m.EventDeleted, m.EntityUnit, unit.ID))
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reassign moves the ingredient lines measured in a unit to the replacement and removes the unit afterwards. The
+// quantities are converted into the replacement, between volume and weight with the density of the ingredient.
+// Lines that cannot be converted, for instance from a weight into pieces, keep the unit from being reassigned.
+// Between units that have no dimension yet the quantities are left as they are.
+func (r UnitRepository) Reassign(unit m.Unit, replacement m.Unit) error {
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.First(&replacement).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("replacement not found")
+			}
+			return err
+		}
+
+		if err := tx.First(&unit).Error; err != nil {
+			return err
+		}
+
+		// the lines of deleted recipes are converted as well, so they are still right when such a recipe is restored
+		var lines []m.RecipeIngredient
+		if err := tx.Unscoped().Where("unit_id = ?", unit.ID).Order("recipe_id, ingredient_id").Find(&lines).Error; err != nil {
+			return err
+		}
+
+		densities, err := densities(tx, lines)
+		if err != nil {
+			return err
+		}
+
+		var recipeIDs []uuid.UUID
+		for _, line := range lines {
+			line, err := convertLine(line, unit, replacement, densities[line.IngredientID])
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Unscoped().Model(&m.RecipeIngredient{}).
+				Where("recipe_id = ? AND ingredient_id = ?", line.RecipeID, line.IngredientID).
+				Updates(map[string]interface{}{
+					"unit_id":      replacement.ID,
+					"quantity":     line.Quantity,
+					"max_quantity": line.MaxQuantity,
+				}).Error; err != nil {
+				return err
+			}
+
+			if len(recipeIDs) == 0 || recipeIDs[len(recipeIDs)-1] != line.RecipeID {
+				recipeIDs = append(recipeIDs, line.RecipeID)
+			}
+		}
+
+		if err := tx.Delete(&unit).Error; err != nil {
+			return err
+		}
+
+		events := []m.ChangeEvent{m.NewChangeEvent(m.EventDeleted, m.EntityUnit, unit.ID)}
+		for _, recipeID := range recipeIDs {
+			events = append(events, m.NewChangeEvent(m.EventUpdated, m.EntityRecipeIngredients, recipeID))
+		}
+
+		return outbox.Record(tx, events...)
 	}); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// densities returns the densities of the ingredients of the lines that have one, including deleted ingredients
+func densities(tx *gorm.DB, lines []m.RecipeIngredient) (map[uuid.UUID]float64, error) {
+	densities := map[uuid.UUID]float64{}
+
+	if len(lines) == 0 {
+		return densities, nil
+	}
+
+	ingredientIDs := make([]uuid.UUID, len(lines))
+	for i, line := range lines {
+		ingredientIDs[i] = line.IngredientID
+	}
+
+	var ingredients []m.Ingredient
+	if err := tx.Unscoped().Select("id", "density").Where("id IN ? AND density > 0", ingredientIDs).Find(&ingredients).Error; err != nil {
+		return nil, err
+	}
+
+	for _, ingredient := range ingredients {
+		densities[ingredient.ID] = ingredient.Density
+	}
+
+	return densities, nil
+}
+
+// convertLine converts the quantities of an ingredient line from one unit into another
+func convertLine(line m.RecipeIngredient, from m.Unit, to m.Unit, density float64) (m.RecipeIngredient, error) {
+
+	if from.Dimension == "" && to.Dimension == "" {
+		return line, nil
+	}
+
+	convert := func(value quantity.Quantity) (quantity.Quantity, error) {
+		if value.IsZero() {
+			return value, nil
+		}
+
+		converted, err := conversion.Convert(value.Float(), from, to, density)
+		if err != nil {
+			return quantity.Quantity{}, err
+		}
+
+		return quantity.Approximate(converted, to.System), nil
+	}
+
+	var err error
+	if line.Quantity, err = convert(line.Quantity); err != nil {
+		return m.RecipeIngredient{}, err
+	}
+	if line.MaxQuantity, err = convert(line.MaxQuantity); err != nil {
+		return m.RecipeIngredient{}, err
+	}
+
+	return line, nil
+}
+
+// Classify sets the dimension and conversion factor of the units stored before units had a dimension, as far as
+// they are well-known units
+func (r UnitRepository) Classify() (int, error) {
+	var units []m.Unit
+
+	if err := r.db.Unscoped().Where("dimension = ''").Find(&units).Error; err != nil {
+		return 0, err
+	}
+
+	classified := 0
+	for _, unit := range units {
+		unit, ok := conversion.Classify(unit)
+		if !ok {
+			continue
+		}
+
+		if err := r.db.Unscoped().Model(&unit).UpdateColumns(map[string]interface{}{
+			"dimension": unit.Dimension,
+			"system":    unit.System,
+			"factor":    unit.Factor,
+			"offset":    unit.Offset,
+		}).Error; err != nil {
+			return 0, err
+		}
+		classified++
+	}
+
+	return classified, nil
+}
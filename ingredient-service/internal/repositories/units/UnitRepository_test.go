@@ -68,6 +68,12 @@ func timeFunc() time.Time {
 	return time
 }
 
+func expectOutbox(mock sqlmock.Sqlmock, eventType string) {
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "sequence"`)).
+		WithArgs(sqlmock.AnyArg(), m.EntityUnit, unit.ID, eventType, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "").
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1))
+}
+
 func TestUnitFindAll_OK(t *testing.T) {
 	db, mock := newMockDatabase(t)
 	r := NewUnitRepository(db)
@@ -163,10 +169,14 @@ func TestUnitCreate_OK(t *testing.T) {
 	r := NewUnitRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "units" ("full_name","short_name","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "units" ("full_name","short_name","dimension","system","factor","offset","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING "id"`)).
 		WithArgs(
 			unit.FullName,
 			unit.ShortName,
+			unit.Dimension,
+			unit.System,
+			unit.Factor,
+			unit.Offset,
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
@@ -174,11 +184,13 @@ func TestUnitCreate_OK(t *testing.T) {
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).
 			AddRow(unit.ID))
+	expectOutbox(mock, m.EventCreated)
 	mock.ExpectCommit()
 	result, err := r.Create(unit)
 
 	assert.NoError(t, err)
 	assert.IsType(t, m.Unit{}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestUnitCreate_Err(t *testing.T) {
@@ -186,10 +198,14 @@ func TestUnitCreate_Err(t *testing.T) {
 	r := NewUnitRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "units" ("full_name","short_name","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "units" ("full_name","short_name","dimension","system","factor","offset","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING "id"`)).
 		WithArgs(
 			unit.FullName,
 			unit.ShortName,
+			unit.Dimension,
+			unit.System,
+			unit.Factor,
+			unit.Offset,
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
@@ -209,19 +225,25 @@ func TestUnitUpdate_OK(t *testing.T) {
 	r := NewUnitRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "units" SET "full_name"=$1,"short_name"=$2,"updated_at"=$3 WHERE "units"."deleted_at" IS NULL AND "id" = $4`)).
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "units" SET "full_name"=$1,"short_name"=$2,"dimension"=$3,"system"=$4,"factor"=$5,"offset"=$6,"updated_at"=$7 WHERE "units"."deleted_at" IS NULL AND "id" = $8`)).
 		WithArgs(
 			unit.FullName,
 			unit.ShortName,
+			unit.Dimension,
+			unit.System,
+			unit.Factor,
+			unit.Offset,
 			sqlmock.AnyArg(),
 			unit.ID,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutbox(mock, m.EventUpdated)
 	mock.ExpectCommit()
 	result, err := r.Update(unit)
 
 	assert.NoError(t, err)
 	assert.IsType(t, m.Unit{}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestUnitUpdate_Err(t *testing.T) {
@@ -229,10 +251,14 @@ func TestUnitUpdate_Err(t *testing.T) {
 	r := NewUnitRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "units" SET "full_name"=$1,"short_name"=$2,"updated_at"=$3 WHERE "units"."deleted_at" IS NULL AND "id" = $4`)).
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "units" SET "full_name"=$1,"short_name"=$2,"dimension"=$3,"system"=$4,"factor"=$5,"offset"=$6,"updated_at"=$7 WHERE "units"."deleted_at" IS NULL AND "id" = $8`)).
 		WithArgs(
 			unit.FullName,
 			unit.ShortName,
+			unit.Dimension,
+			unit.System,
+			unit.Factor,
+			unit.Offset,
 			sqlmock.AnyArg(),
 			unit.ID,
 		).
@@ -245,22 +271,70 @@ func TestUnitUpdate_Err(t *testing.T) {
 	assert.EqualError(t, err, "error")
 }
 
+// expectUsage expects the recipes referencing the unit to be counted and sampled, those of deleted recipes apart
+func expectUsage(mock sqlmock.Sqlmock, recipeIDs []uuid.UUID, deletedRecipeIDs []uuid.UUID) {
+	expectSample(mock, `unit_id = $1 AND "recipe_ingredients"."deleted_at" IS NULL`, recipeIDs)
+	expectSample(mock, `unit_id = $1 AND deleted_at IS NOT NULL`, deletedRecipeIDs)
+}
+
+func expectSample(mock sqlmock.Sqlmock, where string, recipeIDs []uuid.UUID) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(DISTINCT("recipe_id")) FROM "recipe_ingredients" WHERE ` + where)).
+		WithArgs(unit.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(recipeIDs)))
+
+	if len(recipeIDs) == 0 {
+		return
+	}
+
+	rows := sqlmock.NewRows([]string{"recipe_id"})
+	for _, recipeID := range recipeIDs {
+		rows.AddRow(recipeID)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "recipe_ingredients" WHERE ` + where + ` ORDER BY recipe_id LIMIT $2`)).
+		WithArgs(unit.ID, m.UsageSampleSize).
+		WillReturnRows(rows)
+}
+
 func TestUnitDelete_OK(t *testing.T) {
 	db, mock := newMockDatabase(t)
 	r := NewUnitRepository(db)
 
 	mock.ExpectBegin()
+	expectUsage(mock, nil, nil)
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "units" SET "deleted_at"=$1 WHERE "units"."id" = $2 AND "units"."deleted_at" IS NULL`)).
 		WithArgs(
 			sqlmock.AnyArg(),
 			unit.ID,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutbox(mock, m.EventDeleted)
 	mock.ExpectCommit()
 
 	err := r.Delete(unit)
 
 	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitDelete_InUseErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewUnitRepository(db)
+
+	recipeIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	mock.ExpectBegin()
+	expectUsage(mock, recipeIDs, nil)
+	mock.ExpectRollback()
+
+	err := r.Delete(unit)
+
+	var inUse m.InUseError
+	assert.ErrorAs(t, err, &inUse)
+	assert.EqualError(t, err, "unit is used by 2 recipes")
+	assert.Equal(t, int64(2), inUse.Usage.Count)
+	assert.Equal(t, recipeIDs, inUse.Usage.RecipeIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestUnitDelete_Err(t *testing.T) {
@@ -268,6 +342,7 @@ func TestUnitDelete_Err(t *testing.T) {
 	r := NewUnitRepository(db)
 
 	mock.ExpectBegin()
+	expectUsage(mock, nil, nil)
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "units" SET "deleted_at"=$1 WHERE "units"."id" = $2 AND "units"."deleted_at" IS NULL`)).
 		WithArgs(
 			sqlmock.AnyArg(),
@@ -281,3 +356,137 @@ func TestUnitDelete_Err(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualError(t, err, "error")
 }
+
+func TestUnitReassign_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewUnitRepository(db)
+
+	cup := m.Unit{ID: unit.ID, Dimension: m.DimensionVolume, System: m.SystemImperial, Factor: 236.5882365}
+	replacement := m.Unit{ID: uuid.New(), FullName: "millilitre", ShortName: "ml"}
+	recipeID := uuid.New()
+	flour, sugar := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "units" WHERE "units"."deleted_at" IS NULL AND "units"."id" = $1 ORDER BY "units"."id" LIMIT $2`)).
+		WithArgs(replacement.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "full_name", "short_name", "dimension", "system", "factor"}).
+			AddRow(replacement.ID, replacement.FullName, replacement.ShortName, m.DimensionVolume, m.SystemMetric, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "units" WHERE "units"."deleted_at" IS NULL AND "units"."id" = $1 ORDER BY "units"."id" LIMIT $2`)).
+		WithArgs(unit.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "full_name", "short_name", "dimension", "system", "factor"}).
+			AddRow(cup.ID, unit.FullName, unit.ShortName, cup.Dimension, cup.System, cup.Factor))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_ingredients" WHERE unit_id = $1 ORDER BY recipe_id, ingredient_id`)).
+		WithArgs(unit.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "ingredient_id", "quantity", "max_quantity", "to_taste", "unit_id"}).
+			AddRow(recipeID, flour, "1/2", "0", false, unit.ID).
+			AddRow(recipeID, sugar, "1", "3/2", false, unit.ID))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","density" FROM "ingredients" WHERE id IN ($1,$2) AND density > 0`)).
+		WithArgs(flour, sugar).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "density"}))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_ingredients" SET "max_quantity"=$1,"quantity"=$2,"unit_id"=$3 WHERE recipe_id = $4 AND ingredient_id = $5`)).
+		WithArgs("0", "118", replacement.ID, recipeID, flour).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_ingredients" SET "max_quantity"=$1,"quantity"=$2,"unit_id"=$3 WHERE recipe_id = $4 AND ingredient_id = $5`)).
+		WithArgs("355", "237", replacement.ID, recipeID, sugar).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "units" SET "deleted_at"=$1 WHERE "units"."id" = $2 AND "units"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), unit.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8),($9,$10,$11,$12,$13,$14,$15,$16) RETURNING "sequence"`)).
+		WithArgs(
+			sqlmock.AnyArg(), m.EntityUnit, unit.ID, m.EventDeleted, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+			sqlmock.AnyArg(), m.EntityRecipeIngredients, recipeID, m.EventUpdated, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1).AddRow(2))
+	mock.ExpectCommit()
+
+	err := r.Reassign(unit, replacement)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitReassign_DensityErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewUnitRepository(db)
+
+	replacement := m.Unit{ID: uuid.New(), FullName: "gram", ShortName: "g"}
+	recipeID, flour := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "units" WHERE "units"."deleted_at" IS NULL AND "units"."id" = $1 ORDER BY "units"."id" LIMIT $2`)).
+		WithArgs(replacement.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "full_name", "short_name", "dimension", "system", "factor"}).
+			AddRow(replacement.ID, replacement.FullName, replacement.ShortName, m.DimensionMass, m.SystemMetric, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "units" WHERE "units"."deleted_at" IS NULL AND "units"."id" = $1 ORDER BY "units"."id" LIMIT $2`)).
+		WithArgs(unit.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "full_name", "short_name", "dimension", "system", "factor"}).
+			AddRow(unit.ID, unit.FullName, unit.ShortName, m.DimensionVolume, m.SystemImperial, 236.5882365))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_ingredients" WHERE unit_id = $1 ORDER BY recipe_id, ingredient_id`)).
+		WithArgs(unit.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "ingredient_id", "quantity", "max_quantity", "to_taste", "unit_id"}).
+			AddRow(recipeID, flour, "1/2", "0", false, unit.ID))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","density" FROM "ingredients" WHERE id IN ($1) AND density > 0`)).
+		WithArgs(flour).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "density"}))
+	mock.ExpectRollback()
+
+	err := r.Reassign(unit, replacement)
+
+	// cups of an ingredient without a density can't be turned into grams
+	assert.EqualError(t, err, "density is unknown")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitReassign_ReplacementNotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewUnitRepository(db)
+
+	replacement := m.Unit{ID: uuid.New()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "units" WHERE "units"."deleted_at" IS NULL AND "units"."id" = $1 ORDER BY "units"."id" LIMIT $2`)).
+		WithArgs(replacement.ID, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectRollback()
+
+	err := r.Reassign(unit, replacement)
+
+	assert.EqualError(t, err, "replacement not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitClassify_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewUnitRepository(db)
+
+	cup := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "units" WHERE dimension = ''`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "full_name", "short_name"}).
+			AddRow(cup, "Cups", "c").
+			AddRow(unit.ID, unit.FullName, unit.ShortName))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "units" SET "dimension"=$1,"factor"=$2,"offset"=$3,"system"=$4 WHERE "id" = $5`)).
+		WithArgs(m.DimensionVolume, 236.5882365, 0.0, m.SystemImperial, cup).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	classified, err := r.Classify()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, classified)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitClassify_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewUnitRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "units" WHERE dimension = ''`)).
+		WillReturnError(errors.New("error"))
+
+	_, err := r.Classify()
+
+	assert.EqualError(t, err, "error")
+}
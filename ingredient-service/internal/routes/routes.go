@@ -0,0 +1,106 @@
+package routes
+
+import (
+	ih "ingredient-service/internal/handlers/ingredients"
+	ph "ingredient-service/internal/handlers/pantry"
+	rih "ingredient-service/internal/handlers/recipeingredients"
+	uh "ingredient-service/internal/handlers/units"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handlers are the handlers the routes of the service are served by
+type Handlers struct {
+	Ingredient       *ih.IngredientHandlers
+	Unit             *uh.UnitHandlers
+	RecipeIngredient *rih.RecipeIngredientHandlers
+	Pantry           *ph.PantryHandlers
+}
+
+// Register adds the routes of the service to the router. Every group is guarded by the auth middleware, which is
+// attached to the group itself, as a middleware added to one group does not carry over to its siblings.
+func Register(router *gin.Engine, auth gin.HandlerFunc, h Handlers) {
+	v1 := router.Group("/api/v2")
+	{
+		ingredient := v1.Group("/ingredient")
+		{
+			readIngredient := ingredient.Group("")
+			readIngredient.Use(auth)
+			{
+				readIngredient.GET("", h.Ingredient.GetAll)
+				readIngredient.GET("lookup", h.Ingredient.Lookup)
+				readIngredient.GET(":id", h.Ingredient.GetSingle)
+			}
+
+			createIngredient := ingredient.Group("")
+			createIngredient.Use(auth)
+			{
+				createIngredient.POST("", h.Ingredient.Create)
+			}
+
+			updateIngredient := ingredient.Group("")
+			updateIngredient.Use(auth)
+			{
+				updateIngredient.PUT(":id", h.Ingredient.Update)
+			}
+
+			adminIngredient := ingredient.Group("")
+			adminIngredient.Use(auth)
+			{
+				adminIngredient.DELETE(":id", h.Ingredient.Delete)
+				adminIngredient.POST("merge", h.Ingredient.Merge)
+			}
+
+			recipeIngredient := ingredient.Group("/recipe")
+			recipeIngredient.Use(auth)
+			{
+				recipeIngredient.GET("", h.RecipeIngredient.GetByRecipes)
+				recipeIngredient.GET(":id", h.RecipeIngredient.GetByRecipe)
+				recipeIngredient.POST(":id", h.RecipeIngredient.Create)
+				recipeIngredient.PUT(":id", h.RecipeIngredient.Replace)
+				recipeIngredient.PUT(":id/:ingredientId", h.RecipeIngredient.Update)
+				recipeIngredient.DELETE(":id/:ingredientId", h.RecipeIngredient.Delete)
+				recipeIngredient.DELETE(":id", h.RecipeIngredient.DeleteByRecipe)
+				recipeIngredient.POST(":id/restore", h.RecipeIngredient.RestoreByRecipe)
+				recipeIngredient.POST(":id/purge", h.RecipeIngredient.PurgeByRecipe)
+			}
+
+			pantry := ingredient.Group("/pantry")
+			pantry.Use(auth)
+			{
+				pantry.GET("", h.Pantry.Get)
+				pantry.PUT("", h.Pantry.Replace)
+				pantry.POST("match", h.Pantry.Match)
+			}
+		}
+
+		unit := v1.Group("/unit")
+		{
+			readUnit := unit.Group("")
+			readUnit.Use(auth)
+			{
+				readUnit.GET("", h.Unit.GetAll)
+				readUnit.GET("convert", h.Unit.Convert)
+				readUnit.GET(":id", h.Unit.GetSingle)
+			}
+
+			createUnit := unit.Group("")
+			createUnit.Use(auth)
+			{
+				createUnit.POST("", h.Unit.Create)
+			}
+
+			updateUnit := unit.Group("")
+			updateUnit.Use(auth)
+			{
+				updateUnit.PUT(":id", h.Unit.Update)
+			}
+
+			deleteUnit := unit.Group("")
+			deleteUnit.Use(auth)
+			{
+				deleteUnit.DELETE(":id", h.Unit.Delete)
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ih "ingredient-service/internal/handlers/ingredients"
+	ph "ingredient-service/internal/handlers/pantry"
+	rih "ingredient-service/internal/handlers/recipeingredients"
+	uh "ingredient-service/internal/handlers/units"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/tbaehler/gin-keycloak/pkg/ginkeycloak"
+)
+
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	auth := ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig{Url: "http://keycloak.invalid", Realm: "test"}).RestrictButForRole("administrator").Build()
+	Register(router, auth, Handlers{
+		Ingredient:       &ih.IngredientHandlers{},
+		Unit:             &uh.UnitHandlers{},
+		RecipeIngredient: &rih.RecipeIngredientHandlers{},
+		Pantry:           &ph.PantryHandlers{},
+	})
+
+	return router
+}
+
+func TestRegister_MergeUnauthorized(t *testing.T) {
+	router := newRouter()
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/v2/ingredient/merge", strings.NewReader(`{}`)))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRegister_AllUnauthorized(t *testing.T) {
+	router := newRouter()
+	id := uuid.New().String()
+
+	for _, route := range router.Routes() {
+		path := strings.NewReplacer(":ingredientId", id, ":id", id).Replace(route.Path)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, httptest.NewRequest(route.Method, path, nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "%s %s", route.Method, route.Path)
+	}
+}
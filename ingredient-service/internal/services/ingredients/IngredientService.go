@@ -11,9 +11,12 @@ import (
 type IngredientRepository interface {
 	FindAll() ([]m.Ingredient, error)
 	FindSingle(ingredient m.Ingredient) (m.Ingredient, error)
+	FindByName(name string) (m.Ingredient, error)
 	Create(ingredient m.Ingredient) (m.Ingredient, error)
 	Update(ingredient m.Ingredient) (m.Ingredient, error)
 	Delete(ingredient m.Ingredient) error
+	Reassign(ingredient m.Ingredient, replacement m.Ingredient) error
+	Merge(target m.Ingredient, sources []m.Ingredient, preview bool) (m.MergeResult, error)
 }
 type IngredientService struct {
 	repo IngredientRepository
@@ -57,6 +60,24 @@ func (s IngredientService) FindSingle(ingredientDTO m.IngredientDTO) (m.Ingredie
 	return ingredient.ConvertToDTO(), nil
 }
 
+// Lookup finds the ingredient a name refers to, e.g. "Eggs" finds egg
+func (s IngredientService) Lookup(name string) (m.IngredientDTO, error) {
+
+	ingredient, err := s.repo.FindByName(name)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return m.IngredientDTO{}, err
+		default:
+			return m.IngredientDTO{}, errors.New("internal server error")
+		}
+	}
+
+	return ingredient.ConvertToDTO(), nil
+}
+
+// Create adds an ingredient. When the name already refers to an ingredient, that one is returned along with an
+// "ingredient already exists" error, so "Eggs" resolves to the existing egg instead of adding a duplicate.
 func (s IngredientService) Create(ingredientDTO m.IngredientDTO) (m.IngredientDTO, error) {
 	var ingredient m.Ingredient
 
@@ -64,17 +85,31 @@ func (s IngredientService) Create(ingredientDTO m.IngredientDTO) (m.IngredientDT
 		return m.IngredientDTO{}, errors.New("existing id on new element is not allowed")
 	}
 
-	if ingredientDTO.Name == "" {
+	ingredient = ingredientDTO.ConvertFromDTO()
+	if ingredient.NormalizedName == "" {
 		return m.IngredientDTO{}, errors.New("name is empty")
 	}
 
-	found, err := s.FindSingle(ingredientDTO)
-	if err == nil || found.ID != uuid.Nil {
-		return m.IngredientDTO{}, errors.New("ingredient already exists")
+	if ingredient.Density < 0 {
+		return m.IngredientDTO{}, errors.New("density cannot be negative")
 	}
 
-	ingredient, err = s.repo.Create(ingredientDTO.ConvertFromDTO())
-	if err != nil {
+	found, err := s.repo.FindByName(ingredient.Name)
+	if err == nil {
+		return found.ConvertToDTO(), errors.New("ingredient already exists")
+	} else if err.Error() != "not found" {
+		return m.IngredientDTO{}, errors.New("internal server error")
+	}
+
+	if err := s.checkAliases(ingredient); err != nil {
+		return m.IngredientDTO{}, err
+	}
+
+	ingredient, err = s.repo.Create(ingredient)
+	if err != nil && err.Error() == "already exists" {
+		found, err := s.conflict(ingredient)
+		return found.ConvertToDTO(), err
+	} else if err != nil {
 		return m.IngredientDTO{}, err
 	}
 
@@ -89,8 +124,31 @@ func (s IngredientService) Update(ingredientDTO m.IngredientDTO) (m.IngredientDT
 		return m.IngredientDTO{}, errors.New("ingredient does not exist. nothing to update")
 	}
 
-	ingredient, err = s.repo.Update(ingredientDTO.ConvertFromDTO())
-	if err != nil {
+	ingredient = ingredientDTO.ConvertFromDTO()
+	if ingredient.NormalizedName == "" {
+		return m.IngredientDTO{}, errors.New("name is empty")
+	}
+
+	if ingredient.Density < 0 {
+		return m.IngredientDTO{}, errors.New("density cannot be negative")
+	}
+
+	found, err := s.repo.FindByName(ingredient.Name)
+	if err == nil && found.ID != ingredient.ID {
+		return m.IngredientDTO{}, errors.New("ingredient already exists")
+	} else if err != nil && err.Error() != "not found" {
+		return m.IngredientDTO{}, errors.New("internal server error")
+	}
+
+	if err := s.checkAliases(ingredient); err != nil {
+		return m.IngredientDTO{}, err
+	}
+
+	ingredient, err = s.repo.Update(ingredient)
+	if err != nil && err.Error() == "already exists" {
+		_, err := s.conflict(ingredient)
+		return m.IngredientDTO{}, err
+	} else if err != nil {
 		return m.IngredientDTO{}, err
 	}
 
@@ -104,7 +162,6 @@ func (s IngredientService) Delete(ingredientDTO m.IngredientDTO) error {
 		return errors.New("ingredient does not exist. nothing to delete")
 	}
 
-	// TODO: check if there are recipies using the ingredient. If so, an error should be returned and the ingredient should not be deleted.
 	err = s.repo.Delete(ingredientDTO.ConvertFromDTO())
 	if err != nil {
 		return err
@@ -112,3 +169,83 @@ func (s IngredientService) Delete(ingredientDTO m.IngredientDTO) error {
 
 	return nil
 }
+
+// Reassign moves the references to an ingredient to the replacement before deleting it
+func (s IngredientService) Reassign(ingredientDTO m.IngredientDTO, replacementDTO m.IngredientDTO) error {
+
+	if replacementDTO.ID == ingredientDTO.ID {
+		return errors.New("ingredient cannot be reassigned to itself")
+	}
+
+	_, err := s.FindSingle(ingredientDTO)
+	if err != nil {
+		return errors.New("ingredient does not exist. nothing to delete")
+	}
+
+	err = s.repo.Reassign(ingredientDTO.ConvertFromDTO(), replacementDTO.ConvertFromDTO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Merge folds duplicate ingredients into the target, or with preview only tells what doing so would change
+func (s IngredientService) Merge(mergeDTO m.MergeRequestDTO, preview bool) (m.MergeResultDTO, error) {
+	var sources []m.Ingredient
+
+	seen := map[uuid.UUID]bool{}
+	for _, sourceID := range mergeDTO.SourceIDs {
+		if sourceID == mergeDTO.TargetID {
+			return m.MergeResultDTO{}, errors.New("ingredient cannot be merged into itself")
+		}
+
+		if !seen[sourceID] {
+			seen[sourceID] = true
+			sources = append(sources, m.Ingredient{ID: sourceID})
+		}
+	}
+
+	if len(sources) == 0 {
+		return m.MergeResultDTO{}, errors.New("no ingredients to merge")
+	}
+
+	result, err := s.repo.Merge(m.Ingredient{ID: mergeDTO.TargetID}, sources, preview)
+	if err != nil {
+		return m.MergeResultDTO{}, err
+	}
+
+	return result.ConvertToDTO(preview), nil
+}
+
+// checkAliases makes sure the aliases of an ingredient do not already refer to another ingredient
+func (s IngredientService) checkAliases(ingredient m.Ingredient) error {
+
+	for _, alias := range ingredient.Aliases {
+		found, err := s.repo.FindByName(alias.Name)
+		if err == nil && found.ID != ingredient.ID {
+			return errors.New("alias is already used by another ingredient")
+		} else if err != nil && err.Error() != "not found" {
+			return errors.New("internal server error")
+		}
+	}
+
+	return nil
+}
+
+// conflict tells which name an ingredient collided with when storing it violated a unique name. Another ingredient
+// may have been stored with the name or one of the aliases after they were checked, otherwise the name is still
+// held by a deleted ingredient.
+func (s IngredientService) conflict(ingredient m.Ingredient) (m.Ingredient, error) {
+
+	found, err := s.repo.FindByName(ingredient.Name)
+	if err == nil && found.ID != ingredient.ID {
+		return found, errors.New("ingredient already exists")
+	}
+
+	if err := s.checkAliases(ingredient); err != nil {
+		return m.Ingredient{}, err
+	}
+
+	return m.Ingredient{}, errors.New("name is already used")
+}
@@ -59,6 +59,10 @@ func (IngredientRepositoryMock) FindSingle(ingredientInput m.Ingredient) (m.Ingr
 		return ingredient, nil
 	case "deleteerror":
 		return ingredient, nil
+	case "taken":
+		return ingredient, nil
+	case "duplicate":
+		return ingredient, nil
 	case "":
 		return ingredient, nil
 	case "notfound":
@@ -68,10 +72,25 @@ func (IngredientRepositoryMock) FindSingle(ingredientInput m.Ingredient) (m.Ingr
 	}
 }
 
+func (IngredientRepositoryMock) FindByName(name string) (m.Ingredient, error) {
+	switch name {
+	case "find", "Eggs":
+		return ingredient, nil
+	case "taken":
+		return m.Ingredient{ID: uuid.New(), Name: "taken"}, nil
+	case "lookuperror":
+		return m.Ingredient{}, errors.New("error")
+	default:
+		return m.Ingredient{}, errors.New("not found")
+	}
+}
+
 func (IngredientRepositoryMock) Create(ingredientInput m.Ingredient) (m.Ingredient, error) {
 	switch ingredientInput.Name {
 	case "create":
 		return ingredient, nil
+	case "duplicate":
+		return m.Ingredient{}, errors.New("already exists")
 	default:
 		return m.Ingredient{}, errors.New("error")
 	}
@@ -83,6 +102,8 @@ func (IngredientRepositoryMock) Update(ingredientInput m.Ingredient) (m.Ingredie
 		return ingredient, nil
 	case "ingredient":
 		return ingredient, nil
+	case "duplicate":
+		return m.Ingredient{}, errors.New("already exists")
 	default:
 		return m.Ingredient{}, errors.New("error")
 	}
@@ -97,6 +118,24 @@ func (IngredientRepositoryMock) Delete(ingredientInput m.Ingredient) error {
 	}
 }
 
+func (IngredientRepositoryMock) Reassign(ingredientInput m.Ingredient, replacement m.Ingredient) error {
+	switch ingredientInput.Name {
+	case "delete":
+		return nil
+	default:
+		return errors.New("error")
+	}
+}
+
+func (IngredientRepositoryMock) Merge(target m.Ingredient, sources []m.Ingredient, preview bool) (m.MergeResult, error) {
+	switch {
+	case target.ID == ingredient.ID:
+		return m.MergeResult{Target: ingredient, Sources: sources, Aliases: []string{"duplicate"}}, nil
+	default:
+		return m.MergeResult{}, errors.New("target not found")
+	}
+}
+
 func TestIngredientFindAll_OK(t *testing.T) {
 	s := NewIngredientService(&IngredientRepositoryMock{})
 
@@ -212,10 +251,48 @@ func TestIngredientCreate_ExistsErr(t *testing.T) {
 	result, err := s.Create(ingredientDTO)
 
 	assert.Error(t, err)
-	assert.IsType(t, m.IngredientDTO{}, result)
+	assert.Equal(t, ingredient.ID, result.ID)
 	assert.EqualError(t, err, "ingredient already exists")
 }
 
+func TestIngredientCreate_DensityErr(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	ingredientDTO := m.IngredientDTO{
+		Name:    "create",
+		Density: -1,
+	}
+	_, err := s.Create(ingredientDTO)
+
+	assert.EqualError(t, err, "density cannot be negative")
+}
+
+func TestIngredientCreate_AliasTakenErr(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	ingredientDTO := m.IngredientDTO{
+		Name:    "create",
+		Aliases: []string{"taken"},
+	}
+	result, err := s.Create(ingredientDTO)
+
+	assert.Error(t, err)
+	assert.Equal(t, m.IngredientDTO{}, result)
+	assert.EqualError(t, err, "alias is already used by another ingredient")
+}
+
+func TestIngredientCreate_LookupErr(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	ingredientDTO := m.IngredientDTO{
+		Name: "lookuperror",
+	}
+	_, err := s.Create(ingredientDTO)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "internal server error")
+}
+
 func TestIngredientCreate_Err(t *testing.T) {
 	s := NewIngredientService(&IngredientRepositoryMock{})
 
@@ -229,6 +306,18 @@ func TestIngredientCreate_Err(t *testing.T) {
 	assert.EqualError(t, err, "error")
 }
 
+func TestIngredientCreate_DuplicateErr(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	ingredientDTO := m.IngredientDTO{
+		Name: "duplicate",
+	}
+	result, err := s.Create(ingredientDTO)
+
+	assert.Equal(t, uuid.Nil, result.ID)
+	assert.EqualError(t, err, "name is already used")
+}
+
 func TestIngredientCreate_NoName(t *testing.T) {
 	s := NewIngredientService(&IngredientRepositoryMock{})
 
@@ -270,6 +359,19 @@ func TestIngredientUpdate_NotFoundErr(t *testing.T) {
 	assert.EqualError(t, err, "ingredient does not exist. nothing to update")
 }
 
+func TestIngredientUpdate_ExistsErr(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	ingredientDTO := m.IngredientDTO{
+		ID:   ingredient.ID,
+		Name: "taken",
+	}
+	_, err := s.Update(ingredientDTO)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "ingredient already exists")
+}
+
 func TestIngredientUpdate_Err(t *testing.T) {
 	s := NewIngredientService(&IngredientRepositoryMock{})
 
@@ -284,6 +386,18 @@ func TestIngredientUpdate_Err(t *testing.T) {
 	assert.IsType(t, m.IngredientDTO{}, result)
 }
 
+func TestIngredientUpdate_DuplicateErr(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	ingredientDTO := m.IngredientDTO{
+		ID:   ingredient.ID,
+		Name: "duplicate",
+	}
+	_, err := s.Update(ingredientDTO)
+
+	assert.EqualError(t, err, "name is already used")
+}
+
 func TestIngredientDelete_Ok(t *testing.T) {
 	s := NewIngredientService(&IngredientRepositoryMock{})
 
@@ -321,3 +435,121 @@ func TestIngredientDelete_Err(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualError(t, err, "error")
 }
+
+func TestIngredientReassign_Ok(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	ingredientDTO := m.IngredientDTO{
+		ID:   ingredient.ID,
+		Name: "delete",
+	}
+	err := s.Reassign(ingredientDTO, m.IngredientDTO{ID: uuid.New()})
+
+	assert.NoError(t, err)
+}
+
+func TestIngredientReassign_SelfErr(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	ingredientDTO := m.IngredientDTO{
+		ID:   ingredient.ID,
+		Name: "delete",
+	}
+	err := s.Reassign(ingredientDTO, m.IngredientDTO{ID: ingredient.ID})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "ingredient cannot be reassigned to itself")
+}
+
+func TestIngredientReassign_NotFoundErr(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	ingredientDTO := m.IngredientDTO{
+		ID:   ingredient.ID,
+		Name: "notfound",
+	}
+	err := s.Reassign(ingredientDTO, m.IngredientDTO{ID: uuid.New()})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "ingredient does not exist. nothing to delete")
+}
+
+func TestIngredientReassign_Err(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	ingredientDTO := m.IngredientDTO{
+		ID:   ingredient.ID,
+		Name: "deleteerror",
+	}
+	err := s.Reassign(ingredientDTO, m.IngredientDTO{ID: uuid.New()})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "error")
+}
+
+func TestIngredientMerge_Ok(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	sourceID := uuid.New()
+
+	result, err := s.Merge(m.MergeRequestDTO{TargetID: ingredient.ID, SourceIDs: []uuid.UUID{sourceID, sourceID}}, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ingredient.ID, result.Target.ID)
+	assert.Equal(t, []uuid.UUID{sourceID}, result.Merged)
+	assert.Equal(t, []string{"duplicate"}, result.Aliases)
+	assert.Equal(t, []uuid.UUID{}, result.RecipeIDs)
+	assert.True(t, result.Preview)
+}
+
+func TestIngredientMerge_SelfErr(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	_, err := s.Merge(m.MergeRequestDTO{TargetID: ingredient.ID, SourceIDs: []uuid.UUID{ingredient.ID}}, false)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "ingredient cannot be merged into itself")
+}
+
+func TestIngredientMerge_EmptyErr(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	_, err := s.Merge(m.MergeRequestDTO{TargetID: ingredient.ID}, false)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "no ingredients to merge")
+}
+
+func TestIngredientMerge_Err(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	_, err := s.Merge(m.MergeRequestDTO{TargetID: uuid.New(), SourceIDs: []uuid.UUID{uuid.New()}}, false)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "target not found")
+}
+
+func TestIngredientLookup_OK(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	result, err := s.Lookup("Eggs")
+
+	assert.NoError(t, err)
+	assert.Equal(t, ingredient.ID, result.ID)
+}
+
+func TestIngredientLookup_NotFoundErr(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	_, err := s.Lookup("unknown")
+
+	assert.EqualError(t, err, "not found")
+}
+
+func TestIngredientLookup_Err(t *testing.T) {
+	s := NewIngredientService(&IngredientRepositoryMock{})
+
+	_, err := s.Lookup("lookuperror")
+
+	assert.EqualError(t, err, "internal server error")
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+
+	m "ingredient-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const defaultMatchLimit = 20
+
+type PantryRepository interface {
+	FindBySubject(subject string) ([]m.PantryItem, error)
+	Replace(subject string, pantryItems []m.PantryItem) error
+	Match(request m.PantryMatchRequest) ([]m.PantryMatch, error)
+}
+
+type IngredientRepository interface {
+	FindSingle(ingredient m.Ingredient) (m.Ingredient, error)
+}
+
+type PantryService struct {
+	repo           PantryRepository
+	ingredientRepo IngredientRepository
+}
+
+// NewPantryService creates a new PantryService instance
+func NewPantryService(pantryRepo PantryRepository, ingredientRepo IngredientRepository) *PantryService {
+	return &PantryService{
+		repo:           pantryRepo,
+		ingredientRepo: ingredientRepo,
+	}
+}
+
+func (s PantryService) FindBySubject(subject string) ([]m.IngredientDTO, error) {
+
+	pantryItems, err := s.repo.FindBySubject(subject)
+	if err != nil {
+		return nil, errors.New("internal server error")
+	}
+
+	return m.PantryItem{}.ConvertAllToDTO(pantryItems), nil
+}
+
+// Replace sets the complete contents of the pantry of a user
+func (s PantryService) Replace(subject string, requestDTO m.PantryRequestDTO) ([]m.IngredientDTO, error) {
+	var pantryItems []m.PantryItem
+	seen := make(map[uuid.UUID]bool, len(requestDTO.IngredientIDs))
+
+	for _, ingredientID := range requestDTO.IngredientIDs {
+		if seen[ingredientID] {
+			return nil, errors.New("duplicate ingredient in list")
+		}
+		seen[ingredientID] = true
+
+		// FindSingle without an ID would return the first record, so an empty ID is rejected up front
+		if ingredientID == uuid.Nil {
+			return nil, errors.New("ingredient does not exist")
+		}
+
+		if _, err := s.ingredientRepo.FindSingle(m.Ingredient{ID: ingredientID}); err != nil {
+			switch err.Error() {
+			case "not found":
+				return nil, errors.New("ingredient does not exist")
+			default:
+				return nil, errors.New("internal server error")
+			}
+		}
+
+		pantryItems = append(pantryItems, m.PantryItem{Subject: subject, IngredientID: ingredientID})
+	}
+
+	if err := s.repo.Replace(subject, pantryItems); err != nil {
+		return nil, errors.New("internal server error")
+	}
+
+	return s.FindBySubject(subject)
+}
+
+// Match ranks recipes by how well the given ingredients cover them. Without ingredients the pantry of the user is used.
+func (s PantryService) Match(subject string, requestDTO m.PantryMatchRequestDTO) ([]m.PantryMatchDTO, error) {
+	request := m.PantryMatchRequest{
+		IngredientIDs: requestDTO.IngredientIDs,
+		MaxMissing:    requestDTO.MaxMissing,
+		IgnoreStaples: requestDTO.IgnoreStaples,
+		Limit:         requestDTO.Limit,
+	}
+
+	if request.Limit <= 0 {
+		request.Limit = defaultMatchLimit
+	}
+
+	if request.MaxMissing != nil && *request.MaxMissing < 0 {
+		return nil, errors.New("max missing must not be negative")
+	}
+
+	if len(request.IngredientIDs) <= 0 {
+		pantryItems, err := s.repo.FindBySubject(subject)
+		if err != nil {
+			return nil, errors.New("internal server error")
+		}
+
+		for _, pantryItem := range pantryItems {
+			request.IngredientIDs = append(request.IngredientIDs, pantryItem.IngredientID)
+		}
+	}
+
+	if len(request.IngredientIDs) <= 0 {
+		return nil, errors.New("no ingredients provided")
+	}
+
+	matches, err := s.repo.Match(request)
+	if err != nil {
+		return nil, errors.New("internal server error")
+	}
+
+	return m.PantryMatch{}.ConvertAllToDTO(matches), nil
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	m "ingredient-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	subjectFound = "subject-found"
+	subjectEmpty = "subject-empty"
+	subjectError = "subject-error"
+
+	ingredientFound    uuid.UUID = uuid.New()
+	ingredientNotFound uuid.UUID = uuid.New()
+	ingredientError    uuid.UUID = uuid.New()
+
+	recipeID uuid.UUID = uuid.New()
+
+	pantryItem m.PantryItem = m.PantryItem{
+		Subject:      subjectFound,
+		IngredientID: ingredientFound,
+		Ingredient:   m.Ingredient{ID: ingredientFound, Name: "rice"},
+	}
+
+	replacedPantryItems []m.PantryItem
+	matchRequest        m.PantryMatchRequest
+)
+
+type PantryRepositoryMock struct{}
+
+func (PantryRepositoryMock) FindBySubject(subject string) ([]m.PantryItem, error) {
+	switch subject {
+	case subjectFound:
+		return []m.PantryItem{pantryItem}, nil
+	case subjectEmpty:
+		return []m.PantryItem{}, nil
+	default:
+		return nil, errors.New("error")
+	}
+}
+
+func (PantryRepositoryMock) Replace(subject string, pantryItems []m.PantryItem) error {
+	replacedPantryItems = pantryItems
+	if subject == subjectError {
+		return errors.New("error")
+	}
+	return nil
+}
+
+func (PantryRepositoryMock) Match(request m.PantryMatchRequest) ([]m.PantryMatch, error) {
+	matchRequest = request
+	if request.IngredientIDs[0] == ingredientError {
+		return nil, errors.New("error")
+	}
+	return []m.PantryMatch{
+		{RecipeID: recipeID, Ingredients: 4, Matched: 3, Missing: []m.Ingredient{{ID: ingredientNotFound, Name: "saffron"}}},
+	}, nil
+}
+
+type IngredientRepositoryMock struct{}
+
+func (IngredientRepositoryMock) FindSingle(ingredientInput m.Ingredient) (m.Ingredient, error) {
+	switch ingredientInput.ID {
+	case ingredientFound:
+		return m.Ingredient{ID: ingredientInput.ID}, nil
+	case ingredientNotFound:
+		return m.Ingredient{}, errors.New("not found")
+	default:
+		return m.Ingredient{}, errors.New("error")
+	}
+}
+
+func newPantryService() *PantryService {
+	return NewPantryService(&PantryRepositoryMock{}, &IngredientRepositoryMock{})
+}
+
+func TestPantryFindBySubject_OK(t *testing.T) {
+	s := newPantryService()
+
+	result, err := s.FindBySubject(subjectFound)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []m.IngredientDTO{{ID: ingredientFound, Name: "rice"}}, result)
+}
+
+func TestPantryFindBySubject_Empty(t *testing.T) {
+	s := newPantryService()
+
+	result, err := s.FindBySubject(subjectEmpty)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []m.IngredientDTO{}, result)
+}
+
+func TestPantryFindBySubject_Err(t *testing.T) {
+	s := newPantryService()
+
+	_, err := s.FindBySubject(subjectError)
+
+	assert.EqualError(t, err, "internal server error")
+}
+
+func TestPantryReplace_OK(t *testing.T) {
+	s := newPantryService()
+
+	result, err := s.Replace(subjectFound, m.PantryRequestDTO{IngredientIDs: []uuid.UUID{ingredientFound}})
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, []m.PantryItem{{Subject: subjectFound, IngredientID: ingredientFound}}, replacedPantryItems)
+}
+
+func TestPantryReplace_ValidationErr(t *testing.T) {
+	s := newPantryService()
+
+	tests := []struct {
+		ingredientIDs []uuid.UUID
+		err           string
+	}{
+		{[]uuid.UUID{ingredientFound, ingredientFound}, "duplicate ingredient in list"},
+		{[]uuid.UUID{uuid.Nil}, "ingredient does not exist"},
+		{[]uuid.UUID{ingredientNotFound}, "ingredient does not exist"},
+		{[]uuid.UUID{ingredientError}, "internal server error"},
+	}
+
+	for _, test := range tests {
+		_, err := s.Replace(subjectFound, m.PantryRequestDTO{IngredientIDs: test.ingredientIDs})
+
+		assert.EqualError(t, err, test.err)
+	}
+}
+
+func TestPantryReplace_Err(t *testing.T) {
+	s := newPantryService()
+
+	_, err := s.Replace(subjectError, m.PantryRequestDTO{IngredientIDs: []uuid.UUID{ingredientFound}})
+
+	assert.EqualError(t, err, "internal server error")
+}
+
+func TestPantryMatch_OK(t *testing.T) {
+	s := newPantryService()
+	maxMissing := 1
+
+	result, err := s.Match(subjectEmpty, m.PantryMatchRequestDTO{
+		IngredientIDs: []uuid.UUID{ingredientFound},
+		MaxMissing:    &maxMissing,
+		IgnoreStaples: true,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.PantryMatchRequest{
+		IngredientIDs: []uuid.UUID{ingredientFound},
+		MaxMissing:    &maxMissing,
+		IgnoreStaples: true,
+		Limit:         defaultMatchLimit,
+	}, matchRequest)
+	assert.Len(t, result, 1)
+	assert.Equal(t, 0.75, result[0].Coverage)
+	assert.Equal(t, []m.IngredientDTO{{ID: ingredientNotFound, Name: "saffron"}}, result[0].Missing)
+}
+
+func TestPantryMatch_Pantry(t *testing.T) {
+	s := newPantryService()
+
+	_, err := s.Match(subjectFound, m.PantryMatchRequestDTO{Limit: 5})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{ingredientFound}, matchRequest.IngredientIDs)
+	assert.Equal(t, 5, matchRequest.Limit)
+	assert.Nil(t, matchRequest.MaxMissing)
+}
+
+func TestPantryMatch_EmptyPantryErr(t *testing.T) {
+	s := newPantryService()
+
+	_, err := s.Match(subjectEmpty, m.PantryMatchRequestDTO{})
+
+	assert.EqualError(t, err, "no ingredients provided")
+}
+
+func TestPantryMatch_MaxMissingErr(t *testing.T) {
+	s := newPantryService()
+	maxMissing := -1
+
+	_, err := s.Match(subjectFound, m.PantryMatchRequestDTO{MaxMissing: &maxMissing})
+
+	assert.EqualError(t, err, "max missing must not be negative")
+}
+
+func TestPantryMatch_Err(t *testing.T) {
+	s := newPantryService()
+
+	_, err := s.Match(subjectError, m.PantryMatchRequestDTO{})
+	assert.EqualError(t, err, "internal server error")
+
+	_, err = s.Match(subjectFound, m.PantryMatchRequestDTO{IngredientIDs: []uuid.UUID{ingredientError}})
+	assert.EqualError(t, err, "internal server error")
+}
@@ -0,0 +1,349 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"ingredient-service/internal/conversion"
+	m "ingredient-service/internal/models"
+	"ingredient-service/internal/quantity"
+
+	"github.com/google/uuid"
+)
+
+type RecipeIngredientRepository interface {
+	FindByRecipe(recipeID uuid.UUID) ([]m.RecipeIngredient, error)
+	FindByRecipes(recipeIDs []uuid.UUID) ([]m.RecipeIngredient, error)
+	FindSingle(recipeIngredient m.RecipeIngredient) (m.RecipeIngredient, error)
+	Create(recipeIngredient m.RecipeIngredient) (m.RecipeIngredient, error)
+	Update(recipeIngredient m.RecipeIngredient) (m.RecipeIngredient, error)
+	Replace(recipeID uuid.UUID, recipeIngredients []m.RecipeIngredient) error
+	Delete(recipeIngredient m.RecipeIngredient) error
+	DeleteByRecipe(recipeID uuid.UUID) error
+	RestoreByRecipe(recipeID uuid.UUID, since time.Time) error
+	PurgeByRecipe(recipeID uuid.UUID) error
+}
+
+type IngredientRepository interface {
+	FindSingle(ingredient m.Ingredient) (m.Ingredient, error)
+}
+
+type UnitRepository interface {
+	FindAll() ([]m.Unit, error)
+	FindSingle(unit m.Unit) (m.Unit, error)
+}
+
+type RecipeIngredientService struct {
+	repo           RecipeIngredientRepository
+	ingredientRepo IngredientRepository
+	unitRepo       UnitRepository
+}
+
+// NewRecipeIngredientService creates a new RecipeIngredientService instance
+func NewRecipeIngredientService(recipeIngredientRepo RecipeIngredientRepository, ingredientRepo IngredientRepository, unitRepo UnitRepository) *RecipeIngredientService {
+	return &RecipeIngredientService{
+		repo:           recipeIngredientRepo,
+		ingredientRepo: ingredientRepo,
+		unitRepo:       unitRepo,
+	}
+}
+
+func (s RecipeIngredientService) FindByRecipe(recipeID uuid.UUID) ([]m.RecipeIngredientDTO, error) {
+
+	recipeIngredients, err := s.repo.FindByRecipe(recipeID)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return nil, err
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	return m.RecipeIngredient{}.ConvertAllToDTO(recipeIngredients), nil
+}
+
+// FindByRecipeInSystem returns the ingredient lines of a recipe with their quantities converted into the measurement
+// system as well. Lines in a unit that cannot be converted are returned without.
+func (s RecipeIngredientService) FindByRecipeInSystem(recipeID uuid.UUID, system string) ([]m.RecipeIngredientDTO, error) {
+
+	if system != m.SystemMetric && system != m.SystemImperial {
+		return nil, errors.New("unknown measurement system")
+	}
+
+	recipeIngredients, err := s.repo.FindByRecipe(recipeID)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return nil, err
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	units, err := s.unitRepo.FindAll()
+	if err != nil {
+		return nil, errors.New("internal server error")
+	}
+
+	var result []m.RecipeIngredientDTO
+	for _, recipeIngredient := range recipeIngredients {
+		recipeIngredientDTO := recipeIngredient.ConvertToDTO()
+
+		if converted, err := convertAmount(recipeIngredient, units, system); err == nil {
+			recipeIngredientDTO.Converted = &converted
+		}
+
+		result = append(result, recipeIngredientDTO)
+	}
+
+	return result, nil
+}
+
+// convertAmount converts the quantity or range of an ingredient line into the measurement system, and writes it the
+// way a cook would in that system
+func convertAmount(recipeIngredient m.RecipeIngredient, units []m.Unit, system string) (m.ConversionDTO, error) {
+
+	if recipeIngredient.Quantity.IsZero() {
+		return m.ConversionDTO{}, errors.New("nothing to convert")
+	}
+
+	density := recipeIngredient.Ingredient.Density
+	min, unit, err := conversion.ToSystem(recipeIngredient.Quantity.Float(), recipeIngredient.Unit, units, system, density)
+	if err != nil {
+		return m.ConversionDTO{}, err
+	}
+
+	amount := quantity.Amount{Min: quantity.Approximate(min, unit.System), ToTaste: recipeIngredient.ToTaste}
+	converted := m.ConversionDTO{Quantity: conversion.Round(min), Unit: unit.ConvertToDTO()}
+
+	if !recipeIngredient.MaxQuantity.IsZero() {
+		max, err := conversion.Convert(recipeIngredient.MaxQuantity.Float(), recipeIngredient.Unit, unit, density)
+		if err != nil {
+			return m.ConversionDTO{}, err
+		}
+
+		amount.Max = quantity.Approximate(max, unit.System)
+		converted.MaxQuantity = conversion.Round(max)
+	}
+
+	converted.Amount = amount.Format(unit.System)
+	return converted, nil
+}
+
+// FindByRecipes returns the ingredient lines of several recipes at once, so callers can avoid a request per recipe
+func (s RecipeIngredientService) FindByRecipes(recipeIDs []uuid.UUID) ([]m.RecipeIngredientDTO, error) {
+
+	if len(recipeIDs) <= 0 {
+		return nil, errors.New("no recipe IDs provided")
+	}
+
+	recipeIngredients, err := s.repo.FindByRecipes(recipeIDs)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return nil, err
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	return m.RecipeIngredient{}.ConvertAllToDTO(recipeIngredients), nil
+}
+
+func (s RecipeIngredientService) FindSingle(recipeIngredientDTO m.RecipeIngredientDTO) (m.RecipeIngredientDTO, error) {
+
+	recipeIngredient, err := s.repo.FindSingle(recipeIngredientKey(recipeIngredientDTO))
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return m.RecipeIngredientDTO{}, err
+		default:
+			return m.RecipeIngredientDTO{}, errors.New("internal server error")
+		}
+	}
+
+	return recipeIngredient.ConvertToDTO(), nil
+}
+
+func (s RecipeIngredientService) Create(recipeIngredientDTO m.RecipeIngredientDTO) (m.RecipeIngredientDTO, error) {
+
+	if err := s.validate(recipeIngredientDTO); err != nil {
+		return m.RecipeIngredientDTO{}, err
+	}
+
+	_, err := s.repo.FindSingle(recipeIngredientKey(recipeIngredientDTO))
+	if err == nil {
+		return m.RecipeIngredientDTO{}, errors.New("ingredient already part of recipe")
+	} else if err.Error() != "not found" {
+		return m.RecipeIngredientDTO{}, errors.New("internal server error")
+	}
+
+	recipeIngredient, err := recipeIngredientDTO.ConvertFromDTO()
+	if err != nil {
+		return m.RecipeIngredientDTO{}, err
+	}
+
+	if _, err = s.repo.Create(recipeIngredient); err != nil {
+		return m.RecipeIngredientDTO{}, errors.New("internal server error")
+	}
+
+	return s.FindSingle(recipeIngredientDTO)
+}
+
+func (s RecipeIngredientService) Update(recipeIngredientDTO m.RecipeIngredientDTO) (m.RecipeIngredientDTO, error) {
+
+	_, err := s.FindSingle(recipeIngredientDTO)
+	if err != nil {
+		return m.RecipeIngredientDTO{}, errors.New("recipe ingredient does not exist. nothing to update")
+	}
+
+	if err := s.validate(recipeIngredientDTO); err != nil {
+		return m.RecipeIngredientDTO{}, err
+	}
+
+	recipeIngredient, err := recipeIngredientDTO.ConvertFromDTO()
+	if err != nil {
+		return m.RecipeIngredientDTO{}, err
+	}
+
+	if _, err = s.repo.Update(recipeIngredient); err != nil {
+		return m.RecipeIngredientDTO{}, errors.New("internal server error")
+	}
+
+	return s.FindSingle(recipeIngredientDTO)
+}
+
+// Replace sets the complete ingredient list of a recipe. Lines that are not part of the new list are removed.
+func (s RecipeIngredientService) Replace(recipeID uuid.UUID, recipeIngredientDTOs []m.RecipeIngredientDTO) ([]m.RecipeIngredientDTO, error) {
+	seen := make(map[uuid.UUID]bool, len(recipeIngredientDTOs))
+
+	for i := range recipeIngredientDTOs {
+		recipeIngredientDTOs[i].RecipeID = recipeID
+
+		if seen[recipeIngredientDTOs[i].IngredientID] {
+			return nil, errors.New("duplicate ingredient in list")
+		}
+		seen[recipeIngredientDTOs[i].IngredientID] = true
+
+		if err := s.validate(recipeIngredientDTOs[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	recipeIngredients, err := m.RecipeIngredientDTO{}.ConvertAllFromDTO(recipeIngredientDTOs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Replace(recipeID, recipeIngredients); err != nil {
+		return nil, errors.New("internal server error")
+	}
+
+	recipeIngredientDTOs, err = s.FindByRecipe(recipeID)
+	if err != nil && err.Error() == "not found" {
+		return []m.RecipeIngredientDTO{}, nil
+	}
+
+	return recipeIngredientDTOs, err
+}
+
+func (s RecipeIngredientService) Delete(recipeIngredientDTO m.RecipeIngredientDTO) error {
+
+	_, err := s.FindSingle(recipeIngredientDTO)
+	if err != nil {
+		return errors.New("recipe ingredient does not exist. nothing to delete")
+	}
+
+	if err = s.repo.Delete(recipeIngredientKey(recipeIngredientDTO)); err != nil {
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
+
+// DeleteByRecipe removes the ingredient lines of a deleted recipe. Deleting a recipe without lines is not an error,
+// so the recipe service can safely repeat the call.
+func (s RecipeIngredientService) DeleteByRecipe(recipeID uuid.UUID) error {
+
+	if err := s.repo.DeleteByRecipe(recipeID); err != nil {
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
+
+// RestoreByRecipe brings back the ingredient lines that were deleted along with the recipe since the given time
+func (s RecipeIngredientService) RestoreByRecipe(recipeID uuid.UUID, since time.Time) error {
+
+	if err := s.repo.RestoreByRecipe(recipeID, since); err != nil {
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
+
+// PurgeByRecipe permanently removes the ingredient lines of a deleted recipe once its restore window has passed
+func (s RecipeIngredientService) PurgeByRecipe(recipeID uuid.UUID) error {
+
+	if err := s.repo.PurgeByRecipe(recipeID); err != nil {
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
+
+// recipeIngredientKey returns the ingredient line a DTO refers to, for lookups that don't need its amount
+func recipeIngredientKey(recipeIngredientDTO m.RecipeIngredientDTO) m.RecipeIngredient {
+	return m.RecipeIngredient{RecipeID: recipeIngredientDTO.RecipeID, IngredientID: recipeIngredientDTO.IngredientID}
+}
+
+// validate checks the quantity and makes sure the referenced ingredient and unit exist
+func (s RecipeIngredientService) validate(recipeIngredientDTO m.RecipeIngredientDTO) error {
+
+	if recipeIngredientDTO.RecipeID == uuid.Nil {
+		return errors.New("invalid recipe ID")
+	}
+
+	amount, err := recipeIngredientDTO.ParseAmount()
+	if err != nil {
+		return err
+	}
+
+	if amount.Min.IsZero() && !amount.ToTaste {
+		return errors.New("quantity must be greater than zero")
+	}
+
+	if !amount.Max.IsZero() && !amount.Min.Less(amount.Max) {
+		return errors.New("maximum quantity must be greater than the quantity")
+	}
+
+	// FindSingle without an ID would return the first record, so an empty ID is rejected up front
+	if recipeIngredientDTO.IngredientID == uuid.Nil {
+		return errors.New("ingredient does not exist")
+	}
+
+	if _, err := s.ingredientRepo.FindSingle(m.Ingredient{ID: recipeIngredientDTO.IngredientID}); err != nil {
+		switch err.Error() {
+		case "not found":
+			return errors.New("ingredient does not exist")
+		default:
+			return errors.New("internal server error")
+		}
+	}
+
+	if recipeIngredientDTO.Unit.ID == uuid.Nil {
+		return errors.New("unit does not exist")
+	}
+
+	if _, err := s.unitRepo.FindSingle(m.Unit{ID: recipeIngredientDTO.Unit.ID}); err != nil {
+		switch err.Error() {
+		case "not found":
+			return errors.New("unit does not exist")
+		default:
+			return errors.New("internal server error")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,447 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	m "ingredient-service/internal/models"
+	"ingredient-service/internal/quantity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	recipeFound    uuid.UUID = uuid.New()
+	recipeNotFound uuid.UUID = uuid.New()
+	recipeError    uuid.UUID = uuid.New()
+
+	ingredientFound    uuid.UUID = uuid.New()
+	ingredientNew      uuid.UUID = uuid.New()
+	ingredientNotFound uuid.UUID = uuid.New()
+
+	unitFound    uuid.UUID = uuid.New()
+	unitNotFound uuid.UUID = uuid.New()
+
+	recipeIngredient m.RecipeIngredient = m.RecipeIngredient{
+		RecipeID:     recipeFound,
+		IngredientID: ingredientFound,
+		Ingredient:   m.Ingredient{ID: ingredientFound, Name: "ingredient"},
+		Quantity:     quantity.New(2, 1),
+		MaxQuantity:  quantity.New(3, 1),
+		UnitID:       unitFound,
+		Unit:         gram,
+	}
+
+	gram  m.Unit = m.Unit{ID: unitFound, FullName: "gram", ShortName: "g", Dimension: m.DimensionMass, System: m.SystemMetric, Factor: 1}
+	ounce m.Unit = m.Unit{ID: uuid.New(), FullName: "ounce", ShortName: "oz", Dimension: m.DimensionMass, System: m.SystemImperial, Factor: 28.349523125}
+
+	createdRecipeIngredient   m.RecipeIngredient
+	replacedRecipeIngredients []m.RecipeIngredient
+)
+
+type RecipeIngredientRepositoryMock struct{}
+
+func (RecipeIngredientRepositoryMock) FindByRecipe(recipeID uuid.UUID) ([]m.RecipeIngredient, error) {
+	switch recipeID {
+	case recipeFound:
+		return []m.RecipeIngredient{recipeIngredient}, nil
+	case recipeNotFound:
+		return nil, errors.New("not found")
+	default:
+		return nil, errors.New("error")
+	}
+}
+
+func (RecipeIngredientRepositoryMock) FindByRecipes(recipeIDs []uuid.UUID) ([]m.RecipeIngredient, error) {
+	return RecipeIngredientRepositoryMock{}.FindByRecipe(recipeIDs[0])
+}
+
+func (RecipeIngredientRepositoryMock) FindSingle(recipeIngredientInput m.RecipeIngredient) (m.RecipeIngredient, error) {
+	switch {
+	case recipeIngredientInput.RecipeID == recipeError:
+		return m.RecipeIngredient{}, errors.New("error")
+	case recipeIngredientInput.IngredientID == ingredientFound:
+		return recipeIngredient, nil
+	case recipeIngredientInput.IngredientID == createdRecipeIngredient.IngredientID:
+		return createdRecipeIngredient, nil
+	default:
+		return m.RecipeIngredient{}, errors.New("not found")
+	}
+}
+
+func (RecipeIngredientRepositoryMock) Create(recipeIngredientInput m.RecipeIngredient) (m.RecipeIngredient, error) {
+	createdRecipeIngredient = recipeIngredientInput
+	return recipeIngredientInput, nil
+}
+
+func (RecipeIngredientRepositoryMock) Update(recipeIngredientInput m.RecipeIngredient) (m.RecipeIngredient, error) {
+	return recipeIngredientInput, nil
+}
+
+func (RecipeIngredientRepositoryMock) Replace(recipeID uuid.UUID, recipeIngredients []m.RecipeIngredient) error {
+	replacedRecipeIngredients = recipeIngredients
+	return nil
+}
+
+func (RecipeIngredientRepositoryMock) Delete(recipeIngredientInput m.RecipeIngredient) error {
+	return nil
+}
+
+func (RecipeIngredientRepositoryMock) DeleteByRecipe(recipeID uuid.UUID) error {
+	if recipeID == recipeError {
+		return errors.New("error")
+	}
+	return nil
+}
+
+func (RecipeIngredientRepositoryMock) RestoreByRecipe(recipeID uuid.UUID, since time.Time) error {
+	if recipeID == recipeError {
+		return errors.New("error")
+	}
+	return nil
+}
+
+func (RecipeIngredientRepositoryMock) PurgeByRecipe(recipeID uuid.UUID) error {
+	if recipeID == recipeError {
+		return errors.New("error")
+	}
+	return nil
+}
+
+type IngredientRepositoryMock struct{}
+
+func (IngredientRepositoryMock) FindSingle(ingredientInput m.Ingredient) (m.Ingredient, error) {
+	switch ingredientInput.ID {
+	case ingredientFound, ingredientNew:
+		return m.Ingredient{ID: ingredientInput.ID}, nil
+	case ingredientNotFound:
+		return m.Ingredient{}, errors.New("not found")
+	default:
+		return m.Ingredient{}, errors.New("error")
+	}
+}
+
+type UnitRepositoryMock struct{}
+
+func (UnitRepositoryMock) FindAll() ([]m.Unit, error) {
+	return []m.Unit{gram, ounce}, nil
+}
+
+func (UnitRepositoryMock) FindSingle(unitInput m.Unit) (m.Unit, error) {
+	switch unitInput.ID {
+	case unitFound:
+		return m.Unit{ID: unitInput.ID}, nil
+	case unitNotFound:
+		return m.Unit{}, errors.New("not found")
+	default:
+		return m.Unit{}, errors.New("error")
+	}
+}
+
+func newRecipeIngredientService() *RecipeIngredientService {
+	return NewRecipeIngredientService(&RecipeIngredientRepositoryMock{}, &IngredientRepositoryMock{}, &UnitRepositoryMock{})
+}
+
+func newRecipeIngredientDTO(ingredientID uuid.UUID, unitID uuid.UUID, quantity float64) m.RecipeIngredientDTO {
+	return m.RecipeIngredientDTO{
+		RecipeID:     recipeFound,
+		IngredientID: ingredientID,
+		Quantity:     quantity,
+		Unit:         m.UnitDTO{ID: unitID},
+	}
+}
+
+func TestRecipeIngredientFindByRecipe_OK(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.FindByRecipe(recipeFound)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "ingredient", result[0].IngredientName)
+	assert.Equal(t, "g", result[0].Unit.ShortName)
+}
+
+func TestRecipeIngredientFindByRecipe_NotFound(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.FindByRecipe(recipeNotFound)
+
+	assert.EqualError(t, err, "not found")
+	assert.Nil(t, result)
+}
+
+func TestRecipeIngredientFindByRecipe_Err(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.FindByRecipe(recipeError)
+
+	assert.EqualError(t, err, "internal server error")
+	assert.Nil(t, result)
+}
+
+func TestRecipeIngredientFindByRecipeInSystem_OK(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.FindByRecipeInSystem(recipeFound, m.SystemImperial)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, float64(2), result[0].Quantity)
+	assert.Equal(t, "2–3", result[0].Amount)
+	assert.Equal(t, "g", result[0].Unit.ShortName)
+	assert.Equal(t, 0.07, result[0].Converted.Quantity)
+	assert.Equal(t, 0.11, result[0].Converted.MaxQuantity)
+	assert.Equal(t, "⅛", result[0].Converted.Amount)
+	assert.Equal(t, "oz", result[0].Converted.Unit.ShortName)
+}
+
+func TestRecipeIngredientFindByRecipeInSystem_SameSystem(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.FindByRecipeInSystem(recipeFound, m.SystemMetric)
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), result[0].Converted.Quantity)
+	assert.Equal(t, "2–3", result[0].Converted.Amount)
+	assert.Equal(t, "g", result[0].Converted.Unit.ShortName)
+}
+
+func TestRecipeIngredientFindByRecipeInSystem_SystemErr(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.FindByRecipeInSystem(recipeFound, "nautical")
+
+	assert.EqualError(t, err, "unknown measurement system")
+	assert.Nil(t, result)
+}
+
+func TestRecipeIngredientFindByRecipeInSystem_NotFound(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.FindByRecipeInSystem(recipeNotFound, m.SystemMetric)
+
+	assert.EqualError(t, err, "not found")
+	assert.Nil(t, result)
+}
+
+func TestRecipeIngredientFindByRecipes_NoIDsErr(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.FindByRecipes([]uuid.UUID{})
+
+	assert.EqualError(t, err, "no recipe IDs provided")
+	assert.Nil(t, result)
+}
+
+func TestRecipeIngredientCreate_OK(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.Create(newRecipeIngredientDTO(ingredientNew, unitFound, 3))
+
+	assert.NoError(t, err)
+	assert.Equal(t, ingredientNew, result.IngredientID)
+	assert.Equal(t, float64(3), result.Quantity)
+}
+
+func TestRecipeIngredientCreate_Amount(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	tests := map[string]m.RecipeIngredientDTO{
+		"1 1/2":    {Quantity: 1.5, Amount: "1½"},
+		"0.75":     {Quantity: 0.75, Amount: "¾"},
+		"2–3":      {Quantity: 2, MaxQuantity: 3, Amount: "2–3"},
+		"to taste": {ToTaste: true, Amount: "to taste"},
+	}
+
+	for amount, expected := range tests {
+		createdRecipeIngredient = m.RecipeIngredient{}
+		input := newRecipeIngredientDTO(ingredientNew, unitFound, 0)
+		input.Amount = amount
+
+		result, err := s.Create(input)
+
+		assert.NoError(t, err, amount)
+		assert.Equal(t, expected.Quantity, result.Quantity, amount)
+		assert.Equal(t, expected.MaxQuantity, result.MaxQuantity, amount)
+		assert.Equal(t, expected.ToTaste, result.ToTaste, amount)
+		assert.Equal(t, expected.Amount, result.Amount, amount)
+		assert.Equal(t, quantity.Amount{Min: quantity.FromFloat(expected.Quantity), Max: quantity.FromFloat(expected.MaxQuantity), ToTaste: expected.ToTaste}, createdRecipeIngredient.Amount(), amount)
+	}
+
+	createdRecipeIngredient = m.RecipeIngredient{}
+}
+
+func TestRecipeIngredientCreate_ExistsErr(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.Create(newRecipeIngredientDTO(ingredientFound, unitFound, 3))
+
+	assert.EqualError(t, err, "ingredient already part of recipe")
+	assert.Equal(t, m.RecipeIngredientDTO{}, result)
+}
+
+func TestRecipeIngredientCreate_ValidationErr(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	tests := []struct {
+		input m.RecipeIngredientDTO
+		err   string
+	}{
+		{newRecipeIngredientDTO(ingredientNew, unitFound, 0), "quantity must be greater than zero"},
+		{m.RecipeIngredientDTO{RecipeID: recipeFound, IngredientID: ingredientNew, Quantity: 3, MaxQuantity: 2, Unit: m.UnitDTO{ID: unitFound}}, "maximum quantity must be greater than the quantity"},
+		{m.RecipeIngredientDTO{RecipeID: recipeFound, IngredientID: ingredientNew, Amount: "a few", Unit: m.UnitDTO{ID: unitFound}}, "invalid amount"},
+		{newRecipeIngredientDTO(uuid.Nil, unitFound, 1), "ingredient does not exist"},
+		{newRecipeIngredientDTO(ingredientNotFound, unitFound, 1), "ingredient does not exist"},
+		{newRecipeIngredientDTO(ingredientNew, uuid.Nil, 1), "unit does not exist"},
+		{newRecipeIngredientDTO(ingredientNew, unitNotFound, 1), "unit does not exist"},
+		{newRecipeIngredientDTO(ingredientNew, uuid.New(), 1), "internal server error"},
+		{m.RecipeIngredientDTO{IngredientID: ingredientNew, Quantity: 1, Unit: m.UnitDTO{ID: unitFound}}, "invalid recipe ID"},
+	}
+
+	for _, test := range tests {
+		result, err := s.Create(test.input)
+
+		assert.EqualError(t, err, test.err)
+		assert.Equal(t, m.RecipeIngredientDTO{}, result)
+	}
+}
+
+func TestRecipeIngredientUpdate_OK(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.Update(newRecipeIngredientDTO(ingredientFound, unitFound, 5))
+
+	assert.NoError(t, err)
+	assert.Equal(t, ingredientFound, result.IngredientID)
+}
+
+func TestRecipeIngredientUpdate_NotFoundErr(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.Update(newRecipeIngredientDTO(ingredientNotFound, unitFound, 5))
+
+	assert.EqualError(t, err, "recipe ingredient does not exist. nothing to update")
+	assert.Equal(t, m.RecipeIngredientDTO{}, result)
+}
+
+func TestRecipeIngredientUpdate_AmountErr(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.Update(m.RecipeIngredientDTO{RecipeID: recipeFound, IngredientID: ingredientFound, Amount: "a few", Unit: m.UnitDTO{ID: unitFound}})
+
+	assert.EqualError(t, err, "invalid amount")
+	assert.Equal(t, m.RecipeIngredientDTO{}, result)
+}
+
+func TestRecipeIngredientReplace_OK(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.Replace(recipeFound, []m.RecipeIngredientDTO{
+		{IngredientID: ingredientFound, Quantity: 1, Unit: m.UnitDTO{ID: unitFound}},
+		{IngredientID: ingredientNew, Quantity: 2, Unit: m.UnitDTO{ID: unitFound}},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Len(t, replacedRecipeIngredients, 2)
+	assert.Equal(t, recipeFound, replacedRecipeIngredients[1].RecipeID)
+}
+
+func TestRecipeIngredientReplace_Empty(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.Replace(recipeNotFound, []m.RecipeIngredientDTO{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []m.RecipeIngredientDTO{}, result)
+}
+
+func TestRecipeIngredientReplace_DuplicateErr(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.Replace(recipeFound, []m.RecipeIngredientDTO{
+		{IngredientID: ingredientNew, Quantity: 1, Unit: m.UnitDTO{ID: unitFound}},
+		{IngredientID: ingredientNew, Quantity: 2, Unit: m.UnitDTO{ID: unitFound}},
+	})
+
+	assert.EqualError(t, err, "duplicate ingredient in list")
+	assert.Nil(t, result)
+}
+
+func TestRecipeIngredientReplace_ValidationErr(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	result, err := s.Replace(recipeFound, []m.RecipeIngredientDTO{
+		{IngredientID: ingredientNotFound, Quantity: 1, Unit: m.UnitDTO{ID: unitFound}},
+	})
+
+	assert.EqualError(t, err, "ingredient does not exist")
+	assert.Nil(t, result)
+}
+
+func TestRecipeIngredientDelete_OK(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	err := s.Delete(newRecipeIngredientDTO(ingredientFound, uuid.Nil, 0))
+
+	assert.NoError(t, err)
+}
+
+func TestRecipeIngredientDelete_NotFoundErr(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	err := s.Delete(newRecipeIngredientDTO(ingredientNotFound, uuid.Nil, 0))
+
+	assert.EqualError(t, err, "recipe ingredient does not exist. nothing to delete")
+}
+
+func TestRecipeIngredientDeleteByRecipe_OK(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	err := s.DeleteByRecipe(recipeFound)
+
+	assert.NoError(t, err)
+}
+
+func TestRecipeIngredientDeleteByRecipe_Err(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	err := s.DeleteByRecipe(recipeError)
+
+	assert.EqualError(t, err, "internal server error")
+}
+
+func TestRecipeIngredientRestoreByRecipe_OK(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	err := s.RestoreByRecipe(recipeFound, time.Now())
+
+	assert.NoError(t, err)
+}
+
+func TestRecipeIngredientRestoreByRecipe_Err(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	err := s.RestoreByRecipe(recipeError, time.Now())
+
+	assert.EqualError(t, err, "internal server error")
+}
+
+func TestRecipeIngredientPurgeByRecipe_OK(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	err := s.PurgeByRecipe(recipeFound)
+
+	assert.NoError(t, err)
+}
+
+func TestRecipeIngredientPurgeByRecipe_Err(t *testing.T) {
+	s := newRecipeIngredientService()
+
+	err := s.PurgeByRecipe(recipeError)
+
+	assert.EqualError(t, err, "internal server error")
+}
@@ -3,7 +3,9 @@ package services
 import (
 	"errors"
 
+	"ingredient-service/internal/conversion"
 	m "ingredient-service/internal/models"
+	"ingredient-service/internal/quantity"
 
 	"github.com/google/uuid"
 )
@@ -14,15 +16,23 @@ type UnitRepository interface {
 	Create(unit m.Unit) (m.Unit, error)
 	Update(unit m.Unit) (m.Unit, error)
 	Delete(unit m.Unit) error
+	Reassign(unit m.Unit, replacement m.Unit) error
 }
+
+type IngredientRepository interface {
+	FindSingle(ingredient m.Ingredient) (m.Ingredient, error)
+}
+
 type UnitService struct {
-	repo UnitRepository
+	repo           UnitRepository
+	ingredientRepo IngredientRepository
 }
 
 // NewUnitService creates a new UnitService instance
-func NewUnitService(unitRepo UnitRepository) *UnitService {
+func NewUnitService(unitRepo UnitRepository, ingredientRepo IngredientRepository) *UnitService {
 	return &UnitService{
-		repo: unitRepo,
+		repo:           unitRepo,
+		ingredientRepo: ingredientRepo,
 	}
 }
 
@@ -73,7 +83,12 @@ func (s UnitService) Create(unitDTO m.UnitDTO) (m.UnitDTO, error) {
 		return m.UnitDTO{}, errors.New("unit already exists")
 	}
 
-	unit, err = s.repo.Create(unitDTO.ConvertFromDTO())
+	unit, err = classify(unitDTO)
+	if err != nil {
+		return m.UnitDTO{}, err
+	}
+
+	unit, err = s.repo.Create(unit)
 	if err != nil {
 		return m.UnitDTO{}, err
 	}
@@ -89,7 +104,12 @@ func (s UnitService) Update(unitDTO m.UnitDTO) (m.UnitDTO, error) {
 		return m.UnitDTO{}, errors.New("unit does not exist. nothing to update")
 	}
 
-	unit, err = s.repo.Update(unitDTO.ConvertFromDTO())
+	unit, err = classify(unitDTO)
+	if err != nil {
+		return m.UnitDTO{}, err
+	}
+
+	unit, err = s.repo.Update(unit)
 	if err != nil {
 		return m.UnitDTO{}, err
 	}
@@ -104,7 +124,6 @@ func (s UnitService) Delete(unitDTO m.UnitDTO) error {
 		return errors.New("unit does not exist. nothing to delete")
 	}
 
-	// TODO: check if there are recipies using the unit. If so, an error should be returned and the unit should not be deleted.
 	err = s.repo.Delete(unitDTO.ConvertFromDTO())
 	if err != nil {
 		return err
@@ -112,3 +131,120 @@ func (s UnitService) Delete(unitDTO m.UnitDTO) error {
 
 	return nil
 }
+
+// Reassign moves the references to a unit to the replacement before deleting it
+func (s UnitService) Reassign(unitDTO m.UnitDTO, replacementDTO m.UnitDTO) error {
+
+	if replacementDTO.ID == unitDTO.ID {
+		return errors.New("unit cannot be reassigned to itself")
+	}
+
+	_, err := s.FindSingle(unitDTO)
+	if err != nil {
+		return errors.New("unit does not exist. nothing to delete")
+	}
+
+	err = s.repo.Reassign(unitDTO.ConvertFromDTO(), replacementDTO.ConvertFromDTO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Convert converts a quantity into another unit, or into the unit it reads best in within a measurement system. With
+// an ingredient that has a density, volumes and weights can be converted into each other.
+func (s UnitService) Convert(request m.ConversionRequest) (m.ConversionDTO, error) {
+
+	if (request.To == "") == (request.System == "") {
+		return m.ConversionDTO{}, errors.New("either a unit or a measurement system to convert into is required")
+	}
+
+	if request.System != "" && request.System != m.SystemMetric && request.System != m.SystemImperial {
+		return m.ConversionDTO{}, errors.New("unknown measurement system")
+	}
+
+	units, err := s.repo.FindAll()
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return m.ConversionDTO{}, errors.New("unit not found")
+		default:
+			return m.ConversionDTO{}, errors.New("internal server error")
+		}
+	}
+
+	from, ok := conversion.Find(units, request.From)
+	if !ok {
+		return m.ConversionDTO{}, errors.New("unit not found")
+	}
+
+	var density float64
+	if request.IngredientID != uuid.Nil {
+		ingredient, err := s.ingredientRepo.FindSingle(m.Ingredient{ID: request.IngredientID})
+		if err != nil {
+			switch err.Error() {
+			case "not found":
+				return m.ConversionDTO{}, errors.New("ingredient not found")
+			default:
+				return m.ConversionDTO{}, errors.New("internal server error")
+			}
+		}
+		density = ingredient.Density
+	}
+
+	if request.To != "" {
+		to, ok := conversion.Find(units, request.To)
+		if !ok {
+			return m.ConversionDTO{}, errors.New("unit not found")
+		}
+
+		value, err := conversion.Convert(request.Quantity, from, to, density)
+		if err != nil {
+			return m.ConversionDTO{}, err
+		}
+
+		return newConversionDTO(value, to), nil
+	}
+
+	value, unit, err := conversion.ToSystem(request.Quantity, from, units, request.System, density)
+	if err != nil {
+		return m.ConversionDTO{}, err
+	}
+
+	return newConversionDTO(value, unit), nil
+}
+
+// newConversionDTO holds a converted quantity, along with how a cook would write it in the system of the unit
+func newConversionDTO(value float64, unit m.Unit) m.ConversionDTO {
+	amount := quantity.Amount{Min: quantity.Approximate(value, unit.System)}
+
+	return m.ConversionDTO{Quantity: value, Amount: amount.Format(unit.System), Unit: unit.ConvertToDTO()}
+}
+
+// classify checks the dimension, system and factor of a unit. A unit without a dimension gets those of the
+// well-known unit by its name, if there is one.
+func classify(unitDTO m.UnitDTO) (m.Unit, error) {
+	unit := unitDTO.ConvertFromDTO()
+
+	if unit.Dimension == "" {
+		unit, _ = conversion.Classify(unit)
+		return unit, nil
+	}
+
+	switch unit.Dimension {
+	case m.DimensionMass, m.DimensionVolume, m.DimensionCount, m.DimensionLength, m.DimensionTemperature:
+	default:
+		return m.Unit{}, errors.New("unknown dimension")
+	}
+
+	if unit.System != "" && unit.System != m.SystemMetric && unit.System != m.SystemImperial {
+		return m.Unit{}, errors.New("unknown measurement system")
+	}
+
+	if unit.Factor <= 0 {
+		return m.Unit{}, errors.New("factor must be greater than zero")
+	}
+
+	return unit, nil
+}
@@ -21,16 +21,38 @@ var (
 		FullName:  "unit",
 		ShortName: "u",
 	}
+
+	gram       m.Unit = m.Unit{ID: uuid.New(), FullName: "Gram", ShortName: "g", Dimension: m.DimensionMass, System: m.SystemMetric, Factor: 1}
+	millilitre m.Unit = m.Unit{ID: uuid.New(), FullName: "Millilitre", ShortName: "ml", Dimension: m.DimensionVolume, System: m.SystemMetric, Factor: 1}
+	cup        m.Unit = m.Unit{ID: uuid.New(), FullName: "Cup", ShortName: "cup", Dimension: m.DimensionVolume, System: m.SystemImperial, Factor: 236.5882365}
+
+	flour           m.Ingredient = m.Ingredient{ID: uuid.New(), Name: "flour", Density: 0.507}
+	ingredientErrID uuid.UUID    = uuid.New()
 )
 
 type UnitRepositoryMock struct{}
 
+type IngredientRepositoryMock struct{}
+
+func (IngredientRepositoryMock) FindSingle(ingredientInput m.Ingredient) (m.Ingredient, error) {
+	switch ingredientInput.ID {
+	case flour.ID:
+		return flour, nil
+	case ingredientErrID:
+		return m.Ingredient{}, errors.New("error")
+	default:
+		return m.Ingredient{}, errors.New("not found")
+	}
+}
+
 func (UnitRepositoryMock) FindAll() ([]m.Unit, error) {
 	switch findAllUnit.FullName {
 	case "findall": // OK
 		var units []m.Unit
 		units = append(units, unit)
 		return units, nil
+	case "convert":
+		return []m.Unit{gram, millilitre, cup}, nil
 	case "notfound":
 		return nil, errors.New("not found")
 	default: // ERR
@@ -63,6 +85,8 @@ func (UnitRepositoryMock) Create(unitInput m.Unit) (m.Unit, error) {
 	switch unitInput.FullName {
 	case "create":
 		return unit, nil
+	case "Cup":
+		return unitInput, nil
 	default:
 		return m.Unit{}, errors.New("error")
 	}
@@ -88,8 +112,17 @@ func (UnitRepositoryMock) Delete(unitInput m.Unit) error {
 	}
 }
 
+func (UnitRepositoryMock) Reassign(unitInput m.Unit, replacement m.Unit) error {
+	switch unitInput.FullName {
+	case "delete":
+		return nil
+	default:
+		return errors.New("error")
+	}
+}
+
 func TestUnitFindAll_OK(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	findAllUnit.FullName = "findall"
 
@@ -101,7 +134,7 @@ func TestUnitFindAll_OK(t *testing.T) {
 }
 
 func TestUnitFindAll_Err(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	findAllUnit.FullName = "error"
 
@@ -113,7 +146,7 @@ func TestUnitFindAll_Err(t *testing.T) {
 }
 
 func TestUnitFindAll_NotFound(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	findAllUnit.FullName = "notfound"
 
@@ -125,7 +158,7 @@ func TestUnitFindAll_NotFound(t *testing.T) {
 }
 
 func TestUnitFindSingle_OK(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	unitDTO := m.UnitDTO{
 		ID:       unit.ID,
@@ -140,7 +173,7 @@ func TestUnitFindSingle_OK(t *testing.T) {
 }
 
 func TestUnitFindSingle_FindErr(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	unitDTO := m.UnitDTO{
 		ID:       unit.ID,
@@ -154,7 +187,7 @@ func TestUnitFindSingle_FindErr(t *testing.T) {
 }
 
 func TestUnitFindSingle_NotFoundErr(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	unitDTO := m.UnitDTO{
 		ID:       unit.ID,
@@ -168,7 +201,7 @@ func TestUnitFindSingle_NotFoundErr(t *testing.T) {
 }
 
 func TestUnitCreate_OK(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	unitDTO := m.UnitDTO{
 		FullName: "create",
@@ -181,7 +214,7 @@ func TestUnitCreate_OK(t *testing.T) {
 }
 
 func TestUnitCreate_IDErr(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	unitDTO := m.UnitDTO{
 		ID:       unit.ID,
@@ -195,7 +228,7 @@ func TestUnitCreate_IDErr(t *testing.T) {
 }
 
 func TestUnitCreate_ExistsErr(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	unitDTO := m.UnitDTO{
 		FullName: "find",
@@ -208,7 +241,7 @@ func TestUnitCreate_ExistsErr(t *testing.T) {
 }
 
 func TestUnitCreate_Err(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	unitDTO := m.UnitDTO{
 		FullName: "error",
@@ -221,7 +254,7 @@ func TestUnitCreate_Err(t *testing.T) {
 }
 
 func TestUnitCreate_NoName(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	unitDTO := m.UnitDTO{
 		FullName: "",
@@ -234,7 +267,7 @@ func TestUnitCreate_NoName(t *testing.T) {
 }
 
 func TestUnitUpdate_Ok(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	unitDTO := m.UnitDTO{
 		ID:       unit.ID,
@@ -248,7 +281,7 @@ func TestUnitUpdate_Ok(t *testing.T) {
 }
 
 func TestUnitUpdate_NotFoundErr(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	unitDTO := m.UnitDTO{
 		ID:       unit.ID,
@@ -262,7 +295,7 @@ func TestUnitUpdate_NotFoundErr(t *testing.T) {
 }
 
 func TestUnitUpdate_Err(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	unitDTO := m.UnitDTO{
 		ID:       unit.ID,
@@ -276,7 +309,7 @@ func TestUnitUpdate_Err(t *testing.T) {
 }
 
 func TestUnitDelete_Ok(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	unitDTO := m.UnitDTO{
 		ID:       unit.ID,
@@ -288,7 +321,7 @@ func TestUnitDelete_Ok(t *testing.T) {
 }
 
 func TestUnitDelete_NotFoundErr(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	unitDTO := m.UnitDTO{
 		ID:       unit.ID,
@@ -301,7 +334,7 @@ func TestUnitDelete_NotFoundErr(t *testing.T) {
 }
 
 func TestUnitDelete_Err(t *testing.T) {
-	s := NewUnitService(&UnitRepositoryMock{})
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
 
 	unitDTO := m.UnitDTO{
 		ID:       unit.ID,
@@ -312,3 +345,196 @@ func TestUnitDelete_Err(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualError(t, err, "error")
 }
+
+func TestUnitReassign_Ok(t *testing.T) {
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
+
+	unitDTO := m.UnitDTO{
+		ID:       unit.ID,
+		FullName: "delete",
+	}
+	err := s.Reassign(unitDTO, m.UnitDTO{ID: uuid.New()})
+
+	assert.NoError(t, err)
+}
+
+func TestUnitReassign_SelfErr(t *testing.T) {
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
+
+	unitDTO := m.UnitDTO{
+		ID:       unit.ID,
+		FullName: "delete",
+	}
+	err := s.Reassign(unitDTO, m.UnitDTO{ID: unit.ID})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "unit cannot be reassigned to itself")
+}
+
+func TestUnitReassign_NotFoundErr(t *testing.T) {
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
+
+	unitDTO := m.UnitDTO{
+		ID:       unit.ID,
+		FullName: "notfound",
+	}
+	err := s.Reassign(unitDTO, m.UnitDTO{ID: uuid.New()})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "unit does not exist. nothing to delete")
+}
+
+func TestUnitReassign_Err(t *testing.T) {
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
+
+	unitDTO := m.UnitDTO{
+		ID:       unit.ID,
+		FullName: "deleteerror",
+	}
+	err := s.Reassign(unitDTO, m.UnitDTO{ID: uuid.New()})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "error")
+}
+
+func TestUnitCreate_Classified(t *testing.T) {
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
+
+	unitDTO := m.UnitDTO{
+		FullName:  "Cup",
+		ShortName: "cup",
+	}
+	result, err := s.Create(unitDTO)
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.DimensionVolume, result.Dimension)
+	assert.Equal(t, m.SystemImperial, result.System)
+	assert.InDelta(t, 236.59, result.Factor, 0.01)
+}
+
+func TestUnitCreate_DimensionErr(t *testing.T) {
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
+
+	unitDTO := m.UnitDTO{
+		FullName:  "create",
+		Dimension: "weight",
+		Factor:    1,
+	}
+	_, err := s.Create(unitDTO)
+
+	assert.EqualError(t, err, "unknown dimension")
+}
+
+func TestUnitCreate_FactorErr(t *testing.T) {
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
+
+	unitDTO := m.UnitDTO{
+		FullName:  "create",
+		Dimension: m.DimensionVolume,
+	}
+	_, err := s.Create(unitDTO)
+
+	assert.EqualError(t, err, "factor must be greater than zero")
+}
+
+func TestUnitUpdate_SystemErr(t *testing.T) {
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
+
+	unitDTO := m.UnitDTO{
+		ID:        unit.ID,
+		FullName:  "update",
+		Dimension: m.DimensionVolume,
+		System:    "nautical",
+		Factor:    1,
+	}
+	_, err := s.Update(unitDTO)
+
+	assert.EqualError(t, err, "unknown measurement system")
+}
+
+func TestUnitConvert_OK(t *testing.T) {
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
+
+	findAllUnit.FullName = "convert"
+
+	result, err := s.Convert(m.ConversionRequest{Quantity: 1, From: "cups", To: "ml"})
+
+	assert.NoError(t, err)
+	assert.InDelta(t, 236.59, result.Quantity, 0.01)
+	assert.Equal(t, "237", result.Amount)
+	assert.Equal(t, millilitre.ID, result.Unit.ID)
+}
+
+func TestUnitConvert_DensityOK(t *testing.T) {
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
+
+	findAllUnit.FullName = "convert"
+
+	result, err := s.Convert(m.ConversionRequest{Quantity: 1, From: "cup", To: "g", IngredientID: flour.ID})
+
+	assert.NoError(t, err)
+	assert.InDelta(t, 119.95, result.Quantity, 0.01)
+	assert.Equal(t, "120", result.Amount)
+	assert.Equal(t, gram.ID, result.Unit.ID)
+}
+
+func TestUnitConvert_SystemOK(t *testing.T) {
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
+
+	findAllUnit.FullName = "convert"
+
+	result, err := s.Convert(m.ConversionRequest{Quantity: 500, From: millilitre.ID.String(), System: m.SystemImperial})
+
+	assert.NoError(t, err)
+	assert.InDelta(t, 2.11, result.Quantity, 0.01)
+	assert.Equal(t, "2⅛", result.Amount)
+	assert.Equal(t, cup.ID, result.Unit.ID)
+}
+
+func TestUnitConvert_TargetErr(t *testing.T) {
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
+
+	_, err := s.Convert(m.ConversionRequest{Quantity: 1, From: "cup", To: "ml", System: m.SystemMetric})
+
+	assert.EqualError(t, err, "either a unit or a measurement system to convert into is required")
+}
+
+func TestUnitConvert_UnitNotFoundErr(t *testing.T) {
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
+
+	findAllUnit.FullName = "convert"
+
+	_, err := s.Convert(m.ConversionRequest{Quantity: 1, From: "handful", To: "g"})
+
+	assert.EqualError(t, err, "unit not found")
+}
+
+func TestUnitConvert_IngredientNotFoundErr(t *testing.T) {
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
+
+	findAllUnit.FullName = "convert"
+
+	_, err := s.Convert(m.ConversionRequest{Quantity: 1, From: "cup", To: "g", IngredientID: uuid.New()})
+
+	assert.EqualError(t, err, "ingredient not found")
+}
+
+func TestUnitConvert_DensityErr(t *testing.T) {
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
+
+	findAllUnit.FullName = "convert"
+
+	_, err := s.Convert(m.ConversionRequest{Quantity: 1, From: "cup", To: "g"})
+
+	assert.EqualError(t, err, "density is unknown")
+}
+
+func TestUnitConvert_Err(t *testing.T) {
+	s := NewUnitService(&UnitRepositoryMock{}, &IngredientRepositoryMock{})
+
+	findAllUnit.FullName = "convert"
+
+	_, err := s.Convert(m.ConversionRequest{Quantity: 1, From: "cup", To: "g", IngredientID: ingredientErrID})
+
+	assert.EqualError(t, err, "internal server error")
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"time"
+
+	"cookbook/pkg/outbox"
 	m "instruction-service/internal/models"
 
 	ih "instruction-service/internal/handlers/instructions"
@@ -30,6 +33,10 @@ var (
 	InstructionRepository *ir.InstructionRepository
 	SearchRepository      *sr.SearchRepository
 
+	// Events
+	Relay         *outbox.Relay
+	RelayInterval time.Duration
+
 	// Services
 	InstructionService *is.InstructionService
 	SearchService      *ss.SearchService
@@ -59,6 +66,10 @@ func init() {
 	InstructionRepository = ir.NewInstructionRepository(DatabaseClient)
 	SearchRepository = sr.NewSearchRepository(DatabaseClient)
 
+	// Init events
+	Relay = outbox.NewRelay(DatabaseClient, eventBroker(), "/instruction-service", relayBatchSize(), Logger)
+	RelayInterval = relayInterval()
+
 	// Init services
 	InstructionService = is.NewInstructionService(InstructionRepository)
 	SearchService = ss.NewSearchService(SearchRepository)
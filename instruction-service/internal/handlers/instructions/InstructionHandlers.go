@@ -3,6 +3,7 @@ package handlers
 import (
 	m "instruction-service/internal/models"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -10,9 +11,13 @@ import (
 
 type InstructionService interface {
 	Find(instruction m.InstructionDTO) (m.InstructionDTO, error)
+	FindByRecipe(recipeID uuid.UUID) ([]m.InstructionDTO, error)
 	Create(instruction m.InstructionDTO) (m.InstructionDTO, error)
 	Update(instruction m.InstructionDTO) (m.InstructionDTO, error)
 	Delete(instruction m.InstructionDTO) error
+	DeleteByRecipe(recipeID uuid.UUID) error
+	RestoreByRecipe(recipeID uuid.UUID, since time.Time) error
+	PurgeByRecipe(recipeID uuid.UUID) error
 }
 
 type InstructionHandlers struct {
@@ -52,6 +57,28 @@ func (h InstructionHandlers) Get(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, instructionDTO)
 }
 
+func (h InstructionHandlers) GetByRecipe(ctx *gin.Context) {
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	instructionDTOs, err := h.instructionService.FindByRecipe(recipeID)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "no instructions found"})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, instructionDTOs)
+}
+
 func (h InstructionHandlers) Create(ctx *gin.Context) {
 	var instructionDTO m.InstructionDTO
 	var err error
@@ -114,3 +141,59 @@ func (h InstructionHandlers) Delete(ctx *gin.Context) {
 
 	ctx.Status(http.StatusOK)
 }
+
+func (h InstructionHandlers) DeleteByRecipe(ctx *gin.Context) {
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	if err = h.instructionService.DeleteByRecipe(recipeID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// RestoreByRecipe adds the instructions back to a restored recipe. The optional since parameter limits the restore to
+// the instructions removed along with the recipe.
+func (h InstructionHandlers) RestoreByRecipe(ctx *gin.Context) {
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	var since time.Time
+	if value := ctx.Query("since"); value != "" {
+		if since, err = time.Parse(time.RFC3339, value); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid since parameter"})
+			return
+		}
+	}
+
+	if err = h.instructionService.RestoreByRecipe(recipeID, since); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// PurgeByRecipe permanently removes the instructions from a deleted recipe that can no longer be restored
+func (h InstructionHandlers) PurgeByRecipe(ctx *gin.Context) {
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	if err = h.instructionService.PurgeByRecipe(recipeID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -38,6 +39,17 @@ func (s *InstructionServiceMock) Find(instructionDTO m.InstructionDTO) (m.Instru
 	}
 }
 
+func (s *InstructionServiceMock) FindByRecipe(recipeID uuid.UUID) ([]m.InstructionDTO, error) {
+	switch instruction.Description {
+	case "find":
+		return []m.InstructionDTO{instruction}, nil
+	case "notfound":
+		return nil, errors.New("not found")
+	default:
+		return nil, errors.New("error")
+	}
+}
+
 func (s *InstructionServiceMock) Create(instructionDTO m.InstructionDTO) (m.InstructionDTO, error) {
 	switch instructionDTO.Description {
 	case "create":
@@ -65,6 +77,33 @@ func (s *InstructionServiceMock) Delete(instructionDTO m.InstructionDTO) error {
 	}
 }
 
+func (s *InstructionServiceMock) DeleteByRecipe(recipeID uuid.UUID) error {
+	switch instruction.Description {
+	case "delete":
+		return nil
+	default:
+		return errors.New("error")
+	}
+}
+
+func (s *InstructionServiceMock) RestoreByRecipe(recipeID uuid.UUID, since time.Time) error {
+	switch instruction.Description {
+	case "restore":
+		return nil
+	default:
+		return errors.New("error")
+	}
+}
+
+func (s *InstructionServiceMock) PurgeByRecipe(recipeID uuid.UUID) error {
+	switch instruction.Description {
+	case "purge":
+		return nil
+	default:
+		return errors.New("error")
+	}
+}
+
 // ========================================================================================================
 
 func TestGetInstruction_OK(t *testing.T) {
@@ -158,6 +197,95 @@ func TestGetInstruction_FindErr(t *testing.T) {
 	assert.Equal(t, `{"error":"error"}`, string(body))
 }
 
+func TestGetInstructionsByRecipe_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewInstructionHandlers(&InstructionServiceMock{}, &m.LoggerInterfaceMock{})
+
+	instruction.Description = "find"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/instruction/recipe/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: uuid.New().String()},
+	}
+
+	h.GetByRecipe(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal([]m.InstructionDTO{instruction})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestGetInstructionsByRecipe_IDErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewInstructionHandlers(&InstructionServiceMock{}, &m.LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/instruction/recipe/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.GetByRecipe(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid recipe ID"}`, string(body))
+}
+
+func TestGetInstructionsByRecipe_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewInstructionHandlers(&InstructionServiceMock{}, &m.LoggerInterfaceMock{})
+
+	instruction.Description = "notfound"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/instruction/recipe/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: uuid.New().String()},
+	}
+
+	h.GetByRecipe(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"no instructions found"}`, string(body))
+}
+
+func TestGetInstructionsByRecipe_FindErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewInstructionHandlers(&InstructionServiceMock{}, &m.LoggerInterfaceMock{})
+
+	instruction.Description = "error"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/instruction/recipe/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: uuid.New().String()},
+	}
+
+	h.GetByRecipe(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, `{"error":"error"}`, string(body))
+}
+
 func TestCreateInstruction_OK(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	h := NewInstructionHandlers(&InstructionServiceMock{}, &m.LoggerInterfaceMock{})
@@ -376,3 +504,130 @@ func TestDeleteInstruction_UpdateErr(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	assert.Equal(t, `{"error":"error"}`, string(body))
 }
+
+func TestDeleteInstructionsByRecipe_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewInstructionHandlers(&InstructionServiceMock{}, &m.LoggerInterfaceMock{})
+
+	instruction.Description = "delete"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/instruction/recipe/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: uuid.New().String()},
+	}
+
+	h.DeleteByRecipe(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestDeleteInstructionsByRecipe_IDErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewInstructionHandlers(&InstructionServiceMock{}, &m.LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/instruction/recipe/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.DeleteByRecipe(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid recipe ID"}`, string(body))
+}
+
+func TestDeleteInstructionsByRecipe_DeleteErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewInstructionHandlers(&InstructionServiceMock{}, &m.LoggerInterfaceMock{})
+
+	instruction.Description = "error"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/instruction/recipe/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: uuid.New().String()},
+	}
+
+	h.DeleteByRecipe(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, `{"error":"error"}`, string(body))
+}
+
+func TestRestoreInstructionsByRecipe_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewInstructionHandlers(&InstructionServiceMock{}, &m.LoggerInterfaceMock{})
+
+	instruction.Description = "restore"
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/instruction/recipe/1/restore?since=2024-05-01T10:00:00Z", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: uuid.New().String()},
+	}
+
+	h.RestoreByRecipe(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestRestoreInstructionsByRecipe_SinceErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewInstructionHandlers(&InstructionServiceMock{}, &m.LoggerInterfaceMock{})
+
+	instruction.Description = "restore"
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/instruction/recipe/1/restore?since=yesterday", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: uuid.New().String()},
+	}
+
+	h.RestoreByRecipe(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid since parameter"}`, string(body))
+}
+
+func TestPurgeInstructionsByRecipe_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewInstructionHandlers(&InstructionServiceMock{}, &m.LoggerInterfaceMock{})
+
+	instruction.Description = "purge"
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/instruction/recipe/1/purge", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: uuid.New().String()},
+	}
+
+	h.PurgeByRecipe(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
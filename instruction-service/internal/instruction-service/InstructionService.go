@@ -38,12 +38,15 @@ func InstructionService(ctx context.Context) {
 			readInstruction.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
 			{
 				readInstruction.GET(":id", c.InstructionHandlers.Get)
+				readInstruction.GET("recipe/:id", c.InstructionHandlers.GetByRecipe)
 			}
 
 			createInstruction := recipe.Group("")
 			createInstruction.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
 			{
 				createInstruction.POST(":id", c.InstructionHandlers.Create)
+				createInstruction.POST("recipe/:id/restore", c.InstructionHandlers.RestoreByRecipe)
+				createInstruction.POST("recipe/:id/purge", c.InstructionHandlers.PurgeByRecipe)
 			}
 
 			updateInstruction := recipe.Group("")
@@ -56,10 +59,14 @@ func InstructionService(ctx context.Context) {
 			deleteInstruction.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
 			{
 				deleteInstruction.DELETE(":id", c.InstructionHandlers.Delete)
+				deleteInstruction.DELETE("recipe/:id", c.InstructionHandlers.DeleteByRecipe)
 			}
 		}
 	}
 
+	// Outbox relay
+	go c.Relay.Run(ctx, c.RelayInterval)
+
 	// Server startup
 	srv := &http.Server{
 		Handler:      router,
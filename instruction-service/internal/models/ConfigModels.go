@@ -5,6 +5,7 @@ type Config struct {
 	Cors     CorsConfig
 	Oauth    OauthConfig
 	Database DatabaseConfig
+	Events   EventsConfig
 }
 
 // GlobalConfig holds global configuration items
@@ -12,6 +13,19 @@ type GlobalConfig struct {
 	LogLevel string
 }
 
+// EventsConfig holds the broker the changes are published to. The http broker posts them to the subscribers,
+// authenticated with the client credentials
+type EventsConfig struct {
+	Broker       string   // nats or http, defaults to http
+	NatsUrl      string   // e.g. nats://nats:4222
+	Subscribers  []string // urls the events are posted to, e.g. http://search-service:8080/api/v2/search/events
+	Timeout      int      // in seconds
+	ClientID     string
+	ClientSecret string
+	Interval     int // in milliseconds, between the runs of the outbox relay
+	BatchSize    int // number of events the outbox relay publishes per run
+}
+
 // DatabaseConfig holds database configuration items
 type DatabaseConfig struct {
 	Host     string
@@ -2,9 +2,12 @@ package repositories
 
 import (
 	"errors"
+	"time"
 
+	"cookbook/pkg/outbox"
 	m "instruction-service/internal/models"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -31,6 +34,25 @@ func (r InstructionRepository) Find(instruction m.Instruction) (m.Instruction, e
 	return instruction, nil
 }
 
+// FindByRecipe retrieves all instructions associated with a recipe, ordered by their sequence
+func (r InstructionRepository) FindByRecipe(recipeID uuid.UUID) ([]m.Instruction, error) {
+	var instructions []m.Instruction
+
+	if err := r.db.
+		Joins("JOIN recipe_instructions ON recipe_instructions.instruction_id = instructions.id AND recipe_instructions.deleted_at IS NULL").
+		Where("recipe_instructions.recipe_id = ?", recipeID).
+		Order("instructions.sequence").
+		Find(&instructions).Error; err != nil {
+		return nil, err
+	}
+
+	if len(instructions) <= 0 {
+		return nil, errors.New("not found")
+	}
+
+	return instructions, nil
+}
+
 func (r InstructionRepository) Create(instruction m.Instruction) (m.Instruction, error) {
 
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
@@ -39,7 +61,7 @@ func (r InstructionRepository) Create(instruction m.Instruction) (m.Instruction,
 		if err = tx.Create(&instruction).Error; err != nil {
 			return err
 		}
-		return nil
+		return outbox.Record(tx, m.NewChangeEvent(m.EventCreated, m.EntityInstruction, instruction.ID))
 	}); err != nil {
 		return instruction, err
 	}
@@ -54,7 +76,7 @@ func (r InstructionRepository) Update(instruction m.Instruction) (m.Instruction,
 			return err
 		}
 
-		return nil
+		return r.record(tx, m.EventUpdated, instruction.ID)
 	}); err != nil {
 		return instruction, err
 	}
@@ -69,10 +91,64 @@ func (r InstructionRepository) Delete(instruction m.Instruction) error {
 			return err
 		}
 
-		return nil
+		return r.record(tx, m.EventDeleted, instruction.ID)
 	}); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// DeleteByRecipe soft-deletes the links between a deleted recipe and its instructions, so they can be restored along
+// with the recipe. The instructions themselves are left untouched.
+func (r InstructionRepository) DeleteByRecipe(recipeID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Where("recipe_id = ?", recipeID).Delete(&m.RecipeInstruction{}).Error; err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventDeleted, m.EntityRecipeInstructions, recipeID))
+	})
+}
+
+// RestoreByRecipe restores the links between a recipe and its instructions that were deleted since the given time
+func (r InstructionRepository) RestoreByRecipe(recipeID uuid.UUID, since time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Unscoped().Model(&m.RecipeInstruction{}).
+			Where("recipe_id = ? AND deleted_at >= ?", recipeID, since).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventCreated, m.EntityRecipeInstructions, recipeID))
+	})
+}
+
+// PurgeByRecipe permanently removes the links between a deleted recipe and its instructions once the recipe can no
+// longer be restored. The links of a recipe that was restored in the meantime are kept.
+func (r InstructionRepository) PurgeByRecipe(recipeID uuid.UUID) error {
+	return r.db.Unscoped().
+		Where("recipe_id = ? AND deleted_at IS NOT NULL", recipeID).
+		Delete(&m.RecipeInstruction{}).Error
+}
+
+// record writes the change of an instruction to the outbox, along with a change to the instructions of every recipe
+// the instruction is part of, as those recipes are what other services keep track of
+func (r InstructionRepository) record(tx *gorm.DB, eventType string, instructionID uuid.UUID) error {
+	var recipeIDs []uuid.UUID
+
+	if err := tx.Model(&m.RecipeInstruction{}).
+		Where("instruction_id = ?", instructionID).
+		Pluck("recipe_id", &recipeIDs).Error; err != nil {
+		return err
+	}
+
+	events := []m.ChangeEvent{m.NewChangeEvent(eventType, m.EntityInstruction, instructionID)}
+	for _, recipeID := range recipeIDs {
+		events = append(events, m.NewChangeEvent(m.EventUpdated, m.EntityRecipeInstructions, recipeID))
+	}
+
+	return outbox.Record(tx, events...)
+}
@@ -70,6 +70,24 @@ func timeFunc() time.Time {
 
 // ========================================================================================================
 
+func expectOutbox(mock sqlmock.Sqlmock, eventType string) {
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "sequence"`)).
+		WithArgs(sqlmock.AnyArg(), m.EntityInstruction, instruction.ID, eventType, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "").
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1))
+}
+
+func expectRecipeOutbox(mock sqlmock.Sqlmock, eventType string, recipeID uuid.UUID) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "recipe_id" FROM "recipe_instructions" WHERE instruction_id = $1 AND "recipe_instructions"."deleted_at" IS NULL`)).
+		WithArgs(instruction.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(recipeID))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8),($9,$10,$11,$12,$13,$14,$15,$16) RETURNING "sequence"`)).
+		WithArgs(
+			sqlmock.AnyArg(), m.EntityInstruction, instruction.ID, eventType, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+			sqlmock.AnyArg(), m.EntityRecipeInstructions, recipeID, m.EventUpdated, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1).AddRow(2))
+}
+
 func TestFindInstruction_OK(t *testing.T) {
 	db, mock := newMockDatabase(t)
 	r := NewInstructionRepository(db)
@@ -118,6 +136,55 @@ func TestFindInstruction_FindErr(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestFindInstructionsByRecipe_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewInstructionRepository(db)
+	recipeID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "instructions"."id","instructions"."sequence","instructions"."description","instructions"."media_id","instructions"."created_at","instructions"."updated_at","instructions"."deleted_at" FROM "instructions" JOIN recipe_instructions ON recipe_instructions.instruction_id = instructions.id AND recipe_instructions.deleted_at IS NULL WHERE recipe_instructions.recipe_id = $1 AND "instructions"."deleted_at" IS NULL ORDER BY instructions.sequence`)).
+		WithArgs(recipeID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sequence", "description", "media_id"}).
+			AddRow(
+				instruction.ID,
+				instruction.Sequence,
+				instruction.Description,
+				instruction.MediaID,
+			))
+
+	result, err := r.FindByRecipe(recipeID)
+
+	assert.NoError(t, err)
+	assert.IsType(t, []m.Instruction{}, result)
+	assert.Len(t, result, 1)
+	assert.Equal(t, instruction.ID, result[0].ID)
+}
+
+func TestFindInstructionsByRecipe_NotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewInstructionRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "instructions"."id","instructions"."sequence","instructions"."description","instructions"."media_id","instructions"."created_at","instructions"."updated_at","instructions"."deleted_at" FROM "instructions" JOIN recipe_instructions`)).
+		WillReturnRows(&sqlmock.Rows{})
+
+	result, err := r.FindByRecipe(uuid.New())
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "not found")
+}
+
+func TestFindInstructionsByRecipe_FindErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewInstructionRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "instructions"."id","instructions"."sequence","instructions"."description","instructions"."media_id","instructions"."created_at","instructions"."updated_at","instructions"."deleted_at" FROM "instructions" JOIN recipe_instructions`)).
+		WillReturnError(errors.New("error"))
+
+	result, err := r.FindByRecipe(uuid.New())
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "error")
+}
+
 func TestCreateInstruction_OK(t *testing.T) {
 	db, mock := newMockDatabase(t)
 	r := NewInstructionRepository(db)
@@ -135,12 +202,14 @@ func TestCreateInstruction_OK(t *testing.T) {
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).
 			AddRow(instruction.ID))
+	expectOutbox(mock, m.EventCreated)
 	mock.ExpectCommit()
 
 	result, err := r.Create(instruction)
 
 	assert.NoError(t, err)
 	assert.IsType(t, m.Instruction{}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestCreateInstruction_Err(t *testing.T) {
@@ -180,12 +249,14 @@ func TestUpdateInstruction_Ok(t *testing.T) {
 			instruction.ID,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectRecipeOutbox(mock, m.EventUpdated, uuid.New())
 	mock.ExpectCommit()
 
 	result, err := r.Update(instruction)
 
 	assert.NoError(t, err)
 	assert.IsType(t, m.Instruction{}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestUpdateInstruction_Err(t *testing.T) {
@@ -222,11 +293,13 @@ func TestDeleteInstruction_Ok(t *testing.T) {
 			instruction.ID,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectRecipeOutbox(mock, m.EventDeleted, uuid.New())
 	mock.ExpectCommit()
 
 	err := r.Delete(instruction)
 
 	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestDeleteInstruction_Err(t *testing.T) {
@@ -247,3 +320,80 @@ func TestDeleteInstruction_Err(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualError(t, err, "error")
 }
+
+func expectRecipeInstructionsOutbox(mock sqlmock.Sqlmock, eventType string, recipeID uuid.UUID) {
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "sequence"`)).
+		WithArgs(sqlmock.AnyArg(), m.EntityRecipeInstructions, recipeID, eventType, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "").
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1))
+}
+
+func TestDeleteInstructionsByRecipe_Ok(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewInstructionRepository(db)
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_instructions" SET "deleted_at"=$1 WHERE recipe_id = $2 AND "recipe_instructions"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), recipeID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	expectRecipeInstructionsOutbox(mock, m.EventDeleted, recipeID)
+	mock.ExpectCommit()
+
+	err := r.DeleteByRecipe(recipeID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteInstructionsByRecipe_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewInstructionRepository(db)
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_instructions" SET "deleted_at"=$1 WHERE recipe_id = $2 AND "recipe_instructions"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), recipeID).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	err := r.DeleteByRecipe(recipeID)
+
+	assert.EqualError(t, err, "error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreInstructionsByRecipe_Ok(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewInstructionRepository(db)
+	recipeID := uuid.New()
+	since := timeFunc()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_instructions" SET "deleted_at"=$1 WHERE recipe_id = $2 AND deleted_at >= $3`)).
+		WithArgs(nil, recipeID, since).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	expectRecipeInstructionsOutbox(mock, m.EventCreated, recipeID)
+	mock.ExpectCommit()
+
+	err := r.RestoreByRecipe(recipeID, since)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeInstructionsByRecipe_Ok(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewInstructionRepository(db)
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_instructions" WHERE recipe_id = $1 AND deleted_at IS NOT NULL`)).
+		WithArgs(recipeID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := r.PurgeByRecipe(recipeID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
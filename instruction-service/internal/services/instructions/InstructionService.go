@@ -3,13 +3,20 @@ package services
 import (
 	"errors"
 	m "instruction-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type InstructionRepository interface {
 	Find(instruction m.Instruction) (m.Instruction, error)
+	FindByRecipe(recipeID uuid.UUID) ([]m.Instruction, error)
 	Create(instruction m.Instruction) (m.Instruction, error)
 	Update(instruction m.Instruction) (m.Instruction, error)
 	Delete(instruction m.Instruction) error
+	DeleteByRecipe(recipeID uuid.UUID) error
+	RestoreByRecipe(recipeID uuid.UUID, since time.Time) error
+	PurgeByRecipe(recipeID uuid.UUID) error
 }
 
 type InstructionService struct {
@@ -38,6 +45,21 @@ func (s InstructionService) Find(instructionDTO m.InstructionDTO) (m.Instruction
 	return instruction.ConvertToDTO(), nil
 }
 
+// FindByRecipe contains the business logic to get the ordered instructions of a recipe
+func (s InstructionService) FindByRecipe(recipeID uuid.UUID) ([]m.InstructionDTO, error) {
+	instructions, err := s.repo.FindByRecipe(recipeID)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return nil, err
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	return m.Instruction{}.ConvertAllToDTO(instructions), nil
+}
+
 func (s InstructionService) Create(instructionDTO m.InstructionDTO) (m.InstructionDTO, error) {
 	// TODO create logic
 	instruction, err := s.repo.Create(instructionDTO.ConvertFromDTO())
@@ -77,3 +99,19 @@ func (s InstructionService) Delete(instructionDTO m.InstructionDTO) error {
 
 	return nil
 }
+
+// DeleteByRecipe removes the instructions from a deleted recipe. It can safely be repeated, as the recipe service
+// retries until every service has cleaned up.
+func (s InstructionService) DeleteByRecipe(recipeID uuid.UUID) error {
+	return s.repo.DeleteByRecipe(recipeID)
+}
+
+// RestoreByRecipe adds the instructions that were removed since the given time back to a restored recipe
+func (s InstructionService) RestoreByRecipe(recipeID uuid.UUID, since time.Time) error {
+	return s.repo.RestoreByRecipe(recipeID, since)
+}
+
+// PurgeByRecipe permanently removes the instructions from a deleted recipe once its restore window has passed
+func (s InstructionService) PurgeByRecipe(recipeID uuid.UUID) error {
+	return s.repo.PurgeByRecipe(recipeID)
+}
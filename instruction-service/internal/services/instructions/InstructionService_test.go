@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"testing"
+	"time"
 
 	m "instruction-service/internal/models"
 
@@ -17,6 +18,9 @@ var (
 		Description: "instruction",
 		MediaID:     uuid.New(),
 	}
+
+	recipeFound    uuid.UUID = uuid.New()
+	recipeNotFound uuid.UUID = uuid.New()
 )
 
 type InstructionRepositoryMock struct{}
@@ -42,6 +46,17 @@ func (InstructionRepositoryMock) Find(instructionInput m.Instruction) (m.Instruc
 	}
 }
 
+func (InstructionRepositoryMock) FindByRecipe(recipeID uuid.UUID) ([]m.Instruction, error) {
+	switch recipeID {
+	case recipeFound:
+		return []m.Instruction{instruction}, nil
+	case recipeNotFound:
+		return nil, errors.New("not found")
+	default:
+		return nil, errors.New("error")
+	}
+}
+
 func (InstructionRepositoryMock) Create(instructionInput m.Instruction) (m.Instruction, error) {
 	switch instructionInput.Description {
 	case "create":
@@ -69,6 +84,27 @@ func (InstructionRepositoryMock) Delete(instructionInput m.Instruction) error {
 	}
 }
 
+func (InstructionRepositoryMock) DeleteByRecipe(recipeID uuid.UUID) error {
+	if recipeID == recipeFound {
+		return nil
+	}
+	return errors.New("error")
+}
+
+func (InstructionRepositoryMock) RestoreByRecipe(recipeID uuid.UUID, since time.Time) error {
+	if recipeID == recipeFound {
+		return nil
+	}
+	return errors.New("error")
+}
+
+func (InstructionRepositoryMock) PurgeByRecipe(recipeID uuid.UUID) error {
+	if recipeID == recipeFound {
+		return nil
+	}
+	return errors.New("error")
+}
+
 // ========================================================================================================
 
 func TestFindInstruction_OK(t *testing.T) {
@@ -114,6 +150,35 @@ func TestFindInstruction_Err(t *testing.T) {
 
 }
 
+func TestFindInstructionsByRecipe_OK(t *testing.T) {
+	s := NewInstructionService(&InstructionRepositoryMock{})
+
+	result, err := s.FindByRecipe(recipeFound)
+
+	assert.NoError(t, err)
+	assert.IsType(t, []m.InstructionDTO{}, result)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "instruction", result[0].Description)
+}
+
+func TestFindInstructionsByRecipe_NotFoundErr(t *testing.T) {
+	s := NewInstructionService(&InstructionRepositoryMock{})
+
+	result, err := s.FindByRecipe(recipeNotFound)
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "not found")
+}
+
+func TestFindInstructionsByRecipe_Err(t *testing.T) {
+	s := NewInstructionService(&InstructionRepositoryMock{})
+
+	result, err := s.FindByRecipe(uuid.New())
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "internal server error")
+}
+
 func TestCreateInstruction_OK(t *testing.T) {
 	s := NewInstructionService(&InstructionRepositoryMock{})
 
@@ -224,3 +289,51 @@ func TestDeleteInstruction_DeleteErr(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualError(t, err, "error")
 }
+
+func TestDeleteInstructionsByRecipe_OK(t *testing.T) {
+	s := NewInstructionService(&InstructionRepositoryMock{})
+
+	err := s.DeleteByRecipe(recipeFound)
+
+	assert.NoError(t, err)
+}
+
+func TestDeleteInstructionsByRecipe_Err(t *testing.T) {
+	s := NewInstructionService(&InstructionRepositoryMock{})
+
+	err := s.DeleteByRecipe(recipeNotFound)
+
+	assert.EqualError(t, err, "error")
+}
+
+func TestRestoreInstructionsByRecipe_OK(t *testing.T) {
+	s := NewInstructionService(&InstructionRepositoryMock{})
+
+	err := s.RestoreByRecipe(recipeFound, time.Now())
+
+	assert.NoError(t, err)
+}
+
+func TestRestoreInstructionsByRecipe_Err(t *testing.T) {
+	s := NewInstructionService(&InstructionRepositoryMock{})
+
+	err := s.RestoreByRecipe(recipeNotFound, time.Now())
+
+	assert.EqualError(t, err, "error")
+}
+
+func TestPurgeInstructionsByRecipe_OK(t *testing.T) {
+	s := NewInstructionService(&InstructionRepositoryMock{})
+
+	err := s.PurgeByRecipe(recipeFound)
+
+	assert.NoError(t, err)
+}
+
+func TestPurgeInstructionsByRecipe_Err(t *testing.T) {
+	s := NewInstructionService(&InstructionRepositoryMock{})
+
+	err := s.PurgeByRecipe(recipeNotFound)
+
+	assert.EqualError(t, err, "error")
+}
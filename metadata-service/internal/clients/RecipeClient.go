@@ -0,0 +1,80 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	m "metadata-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// RecipeClient retrieves the recipes the search results are sorted on from the recipe service
+type RecipeClient struct {
+	httpClient HTTPClient
+	url        string
+}
+
+// NewRecipeClient creates a client for the recipe service at url. Authentication is left to the given http client.
+func NewRecipeClient(httpClient HTTPClient, url string) *RecipeClient {
+	return &RecipeClient{
+		httpClient: httpClient,
+		url:        strings.TrimSuffix(url, "/"),
+	}
+}
+
+// GetRecipes retrieves all recipes
+func (c RecipeClient) GetRecipes(ctx context.Context) ([]m.RecipeDTO, error) {
+	var recipes []m.RecipeDTO
+
+	if err := c.get(ctx, fmt.Sprintf("%s/api/v2/recipes", c.url), &recipes); err != nil {
+		return nil, err
+	}
+
+	return recipes, nil
+}
+
+// GetRecipe retrieves a single recipe
+func (c RecipeClient) GetRecipe(ctx context.Context, recipeID uuid.UUID) (m.RecipeDTO, error) {
+	var recipe m.RecipeDTO
+
+	if err := c.get(ctx, fmt.Sprintf("%s/api/v2/recipes/%s", c.url, recipeID), &recipe); err != nil {
+		return m.RecipeDTO{}, err
+	}
+
+	return recipe, nil
+}
+
+func (c RecipeClient) get(ctx context.Context, endpoint string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+			return fmt.Errorf("unable to decode response: %w", err)
+		}
+		return nil
+	case http.StatusNotFound:
+		return errors.New("not found")
+	default:
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+}
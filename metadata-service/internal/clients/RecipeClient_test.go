@@ -0,0 +1,90 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	recipeID  uuid.UUID = uuid.New()
+	createdAt           = time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)
+)
+
+func newTestServer(t *testing.T, path string, status int, body interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, path, r.URL.Path)
+
+		w.WriteHeader(status)
+		if body != nil {
+			json.NewEncoder(w).Encode(body)
+		}
+	}))
+}
+
+func TestGetRecipes_OK(t *testing.T) {
+	srv := newTestServer(t, "/api/v2/recipes", http.StatusOK, []map[string]interface{}{{"ID": recipeID, "name": "pasta", "created_at": createdAt}})
+	defer srv.Close()
+
+	c := NewRecipeClient(http.DefaultClient, srv.URL+"/")
+
+	result, err := c.GetRecipes(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, recipeID, result[0].ID)
+	assert.Equal(t, createdAt, result[0].CreatedAt)
+}
+
+func TestGetRecipes_NotFound(t *testing.T) {
+	srv := newTestServer(t, "/api/v2/recipes", http.StatusNotFound, map[string]string{"error": "no recipes found"})
+	defer srv.Close()
+
+	c := NewRecipeClient(http.DefaultClient, srv.URL)
+
+	result, err := c.GetRecipes(context.Background())
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "not found")
+}
+
+func TestGetRecipe_OK(t *testing.T) {
+	srv := newTestServer(t, "/api/v2/recipes/"+recipeID.String(), http.StatusOK, map[string]interface{}{"ID": recipeID, "name": "pasta", "created_at": createdAt})
+	defer srv.Close()
+
+	c := NewRecipeClient(http.DefaultClient, srv.URL)
+
+	result, err := c.GetRecipe(context.Background(), recipeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "pasta", result.Name)
+	assert.Equal(t, createdAt, result.CreatedAt)
+}
+
+func TestGetRecipe_NotFound(t *testing.T) {
+	srv := newTestServer(t, "/api/v2/recipes/"+recipeID.String(), http.StatusNotFound, nil)
+	defer srv.Close()
+
+	c := NewRecipeClient(http.DefaultClient, srv.URL)
+
+	_, err := c.GetRecipe(context.Background(), recipeID)
+
+	assert.EqualError(t, err, "not found")
+}
+
+func TestGetRecipe_Err(t *testing.T) {
+	srv := newTestServer(t, "/api/v2/recipes/"+recipeID.String(), http.StatusUnauthorized, nil)
+	defer srv.Close()
+
+	c := NewRecipeClient(http.DefaultClient, srv.URL)
+
+	_, err := c.GetRecipe(context.Background(), recipeID)
+
+	assert.EqualError(t, err, "unexpected status code 401")
+}
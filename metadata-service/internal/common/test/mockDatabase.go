@@ -39,8 +39,9 @@ func NewMockDatabase(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
 	})
 
 	mockDB, err = gorm.Open(dialector, &gorm.Config{
-		NowFunc: timeFunc,
-		Logger:  newLogger,
+		NowFunc:        timeFunc,
+		Logger:         newLogger,
+		TranslateError: true,
 	})
 	if err != nil {
 		t.Fatalf("gorm mock init failed: %v", err.Error())
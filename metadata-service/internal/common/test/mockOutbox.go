@@ -0,0 +1,43 @@
+package test
+
+import (
+	"regexp"
+
+	m "metadata-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+// ExpectOutbox expects a change event to be written to the outbox within the transaction of a repository
+func ExpectOutbox(mock sqlmock.Sqlmock, entityType string, entityID uuid.UUID, eventType string) {
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "sequence"`)).
+		WithArgs(sqlmock.AnyArg(), entityType, entityID, eventType, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "").
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1))
+}
+
+// ExpectUsage expects the recipes referencing an entity to be counted and sampled, e.g. in recipe_tags by tag_id,
+// those of deleted recipes apart
+func ExpectUsage(mock sqlmock.Sqlmock, table string, column string, id uuid.UUID, recipeIDs []uuid.UUID, deletedRecipeIDs []uuid.UUID) {
+	expectSample(mock, table, column+` = $1 AND "`+table+`"."deleted_at" IS NULL`, id, recipeIDs)
+	expectSample(mock, table, column+` = $1 AND deleted_at IS NOT NULL`, id, deletedRecipeIDs)
+}
+
+func expectSample(mock sqlmock.Sqlmock, table string, where string, id uuid.UUID, recipeIDs []uuid.UUID) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(DISTINCT("recipe_id")) FROM "` + table + `" WHERE ` + where)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(recipeIDs)))
+
+	if len(recipeIDs) == 0 {
+		return
+	}
+
+	rows := sqlmock.NewRows([]string{"recipe_id"})
+	for _, recipeID := range recipeIDs {
+		rows.AddRow(recipeID)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "` + table + `" WHERE ` + where + ` ORDER BY recipe_id LIMIT $2`)).
+		WithArgs(id, m.UsageSampleSize).
+		WillReturnRows(rows)
+}
@@ -1,19 +1,27 @@
 package config
 
 import (
+	"time"
+
 	ch "metadata-service/internal/handlers/category"
 	cuh "metadata-service/internal/handlers/cuisinetype"
 	dh "metadata-service/internal/handlers/difficultylevel"
+	eh "metadata-service/internal/handlers/event"
 	ph "metadata-service/internal/handlers/preparationtime"
+	rh "metadata-service/internal/handlers/recipemetadata"
 	sh "metadata-service/internal/handlers/search"
 	th "metadata-service/internal/handlers/tag"
 
+	"cookbook/pkg/outbox"
+	cl "metadata-service/internal/clients"
 	m "metadata-service/internal/models"
 
 	cr "metadata-service/internal/repositories/category"
 	cur "metadata-service/internal/repositories/cuisinetype"
 	dr "metadata-service/internal/repositories/difficultylevel"
 	pr "metadata-service/internal/repositories/preparationtime"
+	recr "metadata-service/internal/repositories/recipe"
+	rr "metadata-service/internal/repositories/recipemetadata"
 	sr "metadata-service/internal/repositories/search"
 	tr "metadata-service/internal/repositories/tag"
 
@@ -21,6 +29,8 @@ import (
 	cus "metadata-service/internal/services/cuisinetype"
 	ds "metadata-service/internal/services/difficultylevel"
 	ps "metadata-service/internal/services/preparationtime"
+	recs "metadata-service/internal/services/recipe"
+	rs "metadata-service/internal/services/recipemetadata"
 	ss "metadata-service/internal/services/search"
 	ts "metadata-service/internal/services/tag"
 
@@ -43,14 +53,26 @@ var (
 	CuisineTypeRepository     *cur.CuisineTypeRepository
 	DifficultyLevelRepository *dr.DifficultyLevelRepository
 	PreparationTimeRepository *pr.PreparationTimeRepository
+	RecipeRepository          *recr.RecipeRepository
+	RecipeMetadataRepository  *rr.RecipeMetadataRepository
 	SearchRepository          *sr.SearcRepository
 	TagRepository             *tr.TagRepository
 
+	// Clients
+	RecipeClient *cl.RecipeClient
+
+	// Events
+	Relay           *outbox.Relay
+	RelayInterval   time.Duration
+	EventSubscriber *cl.EventSubscriber
+
 	// Services
 	CategoryService        *cs.CategoryService
 	CuisineTypeService     *cus.CuisineTypeService
 	DifficultyLevelService *ds.DifficultyLevelService
 	PreparationTimeService *ps.PreparationTimeService
+	RecipeService          *recs.RecipeService
+	RecipeMetadataService  *rs.RecipeMetadataService
 	SearchService          *ss.SearchService
 	TagService             *ts.TagService
 
@@ -58,7 +80,9 @@ var (
 	CategoryHandlers        *ch.CategoryHandlers
 	CuisineTypeHandlers     *cuh.CuisineTypeHandlers
 	DifficultyLevelHandlers *dh.DifficultyLevelHandlers
+	EventHandlers           *eh.EventHandlers
 	PreparationTimeHandlers *ph.PreparationTimeHandlers
+	RecipeMetadataHandlers  *rh.RecipeMetadataHandlers
 	SearchHandlers          *sh.SearchHandlers
 	TagHandlers             *th.TagHandlers
 )
@@ -84,14 +108,38 @@ func init() {
 	CuisineTypeRepository = cur.NewCuisineTypeRepository(DatabaseClient)
 	DifficultyLevelRepository = dr.NewDifficultyLevelRepository(DatabaseClient)
 	PreparationTimeRepository = pr.NewPreparationTimeRepository(DatabaseClient)
+	RecipeRepository = recr.NewRecipeRepository(DatabaseClient)
+	RecipeMetadataRepository = rr.NewRecipeMetadataRepository(DatabaseClient)
 	SearchRepository = sr.NewSearchRepository(DatabaseClient)
 	TagRepository = tr.NewTagRepository(DatabaseClient)
 
+	if normalized, err := TagRepository.NormalizeNames(); err != nil {
+		Logger.Fatalf("Error while normalizing tag names: %s", err.Error())
+	} else if normalized > 0 {
+		Logger.Infof("normalized the names of %d tags", normalized)
+	}
+
+	if normalized, err := CategoryRepository.NormalizeNames(); err != nil {
+		Logger.Fatalf("Error while normalizing category names: %s", err.Error())
+	} else if normalized > 0 {
+		Logger.Infof("normalized the names of %d categories", normalized)
+	}
+
+	// Init clients
+	RecipeClient = cl.NewRecipeClient(recipeHttpClient(), Configuration.Services.RecipeServiceUrl)
+
+	// Init events
+	Relay = outbox.NewRelay(DatabaseClient, eventBroker(), "/metadata-service", relayBatchSize(), Logger)
+	RelayInterval = relayInterval()
+	initEvents()
+
 	// Init services
 	CategoryService = cs.NewCategoryService(CategoryRepository)
 	CuisineTypeService = cus.NewCuisineTypeService(CuisineTypeRepository)
 	DifficultyLevelService = ds.NewDifficultyLevelService(DifficultyLevelRepository)
 	PreparationTimeService = ps.NewPreparationTimeService(PreparationTimeRepository)
+	RecipeService = recs.NewRecipeService(RecipeClient, RecipeRepository, Logger)
+	RecipeMetadataService = rs.NewRecipeMetadataService(RecipeMetadataRepository, CategoryRepository, TagRepository, CuisineTypeRepository, DifficultyLevelRepository, PreparationTimeRepository)
 	SearchService = ss.NewSearchService(SearchRepository)
 	TagService = ts.NewTagService(TagRepository)
 
@@ -99,7 +147,9 @@ func init() {
 	CategoryHandlers = ch.NewCategoryHandlers(CategoryService, Logger)
 	CuisineTypeHandlers = cuh.NewCuisineTypeHandlers(CuisineTypeService, Logger)
 	DifficultyLevelHandlers = dh.NewDifficultyLevelHandlers(DifficultyLevelService, Logger)
+	EventHandlers = eh.NewEventHandlers(RecipeService, Logger)
 	PreparationTimeHandlers = ph.NewPreparationTimeHandlers(PreparationTimeService, Logger)
+	RecipeMetadataHandlers = rh.NewRecipeMetadataHandlers(RecipeMetadataService, Logger)
 	SearchHandlers = sh.NewSearchHandlers(SearchService, Logger)
 	TagHandlers = th.NewTagHandlers(TagService, Logger)
 }
@@ -1,15 +1,20 @@
 package config
 
 import (
+	"context"
+	"cookbook/pkg/outbox"
 	"fmt"
+	cl "metadata-service/internal/clients"
 	"metadata-service/internal/helpers"
 	m "metadata-service/internal/models"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"golang.org/x/oauth2/clientcredentials"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -74,7 +79,8 @@ func initDatabase() {
 		Configuration.Database.Timezone)
 
 	DatabaseClient, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Warn),
+		Logger:         logger.Default.LogMode(logger.Warn),
+		TranslateError: true, // a violated unique index is reported as gorm.ErrDuplicatedKey
 	})
 
 	if err != nil {
@@ -84,7 +90,9 @@ func initDatabase() {
 	Logger.Info("performing database migrations")
 	if err := DatabaseClient.AutoMigrate(
 		&m.Tag{},
+		&m.TagAlias{},
 		&m.Category{},
+		&m.CategoryAlias{},
 		&m.CuisineType{},
 		&m.PreparationTime{},
 		&m.DifficultyLevel{},
@@ -93,10 +101,25 @@ func initDatabase() {
 		&m.RecipeCuisineType{},
 		&m.RecipePreparationTime{},
 		&m.RecipeDifficultyLevel{},
+		&m.Recipe{},
+		&outbox.Message{},
 	); err != nil {
 		Logger.Fatalf("Error while automigrating database: %s", err.Error())
 	}
 
+	// the normalized names were indexed without being unique before
+	if DatabaseClient.Migrator().HasIndex(&m.Tag{}, "idx_tags_normalized_name") {
+		if err := DatabaseClient.Migrator().DropIndex(&m.Tag{}, "idx_tags_normalized_name"); err != nil {
+			Logger.Fatalf("Error while dropping the index of the normalized names: %s", err.Error())
+		}
+	}
+
+	if DatabaseClient.Migrator().HasIndex(&m.Category{}, "idx_categories_normalized_name") {
+		if err := DatabaseClient.Migrator().DropIndex(&m.Category{}, "idx_categories_normalized_name"); err != nil {
+			Logger.Fatalf("Error while dropping the index of the normalized names: %s", err.Error())
+		}
+	}
+
 	Logger.Info("connected!")
 }
 
@@ -110,3 +133,115 @@ func initCors() {
 		MaxAge:           12 * time.Hour,
 	}
 }
+
+func eventTimeout() time.Duration {
+	if Configuration.Events.Timeout <= 0 {
+		Logger.Warn("no or invalid event timeout specified. Assuming default value of 30 seconds")
+		return 30 * time.Second
+	}
+
+	return time.Duration(Configuration.Events.Timeout) * time.Second
+}
+
+// eventHttpClient returns the client the events are sent with. With a client configured it authenticates
+// with the client credentials at keycloak
+func eventHttpClient() *http.Client {
+	if Configuration.Events.ClientID == "" {
+		if len(Configuration.Events.Subscribers) > 0 {
+			Logger.Warn("no event client specified. Events are sent without authentication")
+		}
+		return &http.Client{Timeout: eventTimeout()}
+	}
+
+	credentials := clientcredentials.Config{
+		ClientID:     Configuration.Events.ClientID,
+		ClientSecret: Configuration.Events.ClientSecret,
+		TokenURL:     fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", strings.TrimSuffix(Configuration.Oauth.Url, "/"), Configuration.Oauth.Realm),
+	}
+
+	client := credentials.Client(context.Background())
+	client.Timeout = eventTimeout()
+
+	return client
+}
+
+// eventBroker returns the broker the outbox relay publishes the events to
+func eventBroker() outbox.Broker {
+	switch Configuration.Events.Broker {
+	case "nats":
+		broker, err := outbox.NewNatsBroker(Configuration.Events.NatsUrl)
+		if err != nil {
+			Logger.Fatalf("unable to connect to the event broker at %s: %v", Configuration.Events.NatsUrl, err)
+		}
+		return broker
+	case "", "http":
+		return outbox.NewHttpBroker(eventHttpClient(), Configuration.Events.Subscribers, Logger)
+	default:
+		Logger.Fatalf("unknown event broker %s. Use nats or http", Configuration.Events.Broker)
+		return nil
+	}
+}
+
+// relayInterval returns the time between the runs of the outbox relay
+func relayInterval() time.Duration {
+	if Configuration.Events.Interval <= 0 {
+		Logger.Warn("no or invalid relay interval specified. Assuming default value of 1000 milliseconds")
+		return time.Second
+	}
+
+	return time.Duration(Configuration.Events.Interval) * time.Millisecond
+}
+
+// relayBatchSize returns the number of events the outbox relay publishes per run
+func relayBatchSize() int {
+	if Configuration.Events.BatchSize <= 0 {
+		Logger.Warn("no or invalid relay batch size specified. Assuming default value of 100")
+		return 100
+	}
+
+	return Configuration.Events.BatchSize
+}
+
+// initEvents connects to the broker the changes of the recipe service are consumed from. With the http broker
+// they are received on the events endpoint instead
+func initEvents() {
+	if Configuration.Events.Broker != "nats" {
+		Logger.Info("no nats broker specified. Changes to recipes are only received on the events endpoint")
+		return
+	}
+
+	subscriber, err := cl.NewEventSubscriber(Configuration.Events.NatsUrl, Logger)
+	if err != nil {
+		Logger.Fatalf("unable to connect to the event broker at %s: %v", Configuration.Events.NatsUrl, err)
+	}
+	EventSubscriber = subscriber
+}
+
+func serviceTimeout() time.Duration {
+	if Configuration.Services.Timeout <= 0 {
+		Logger.Warn("no or invalid service timeout specified. Assuming default value of 5 seconds")
+		return 5 * time.Second
+	}
+
+	return time.Duration(Configuration.Services.Timeout) * time.Second
+}
+
+// recipeHttpClient returns the client the recipes are retrieved with. With a client configured it authenticates
+// with the client credentials at keycloak
+func recipeHttpClient() *http.Client {
+	if Configuration.Services.ClientID == "" {
+		Logger.Warn("no service client specified. Recipes are retrieved without authentication")
+		return &http.Client{Timeout: serviceTimeout()}
+	}
+
+	credentials := clientcredentials.Config{
+		ClientID:     Configuration.Services.ClientID,
+		ClientSecret: Configuration.Services.ClientSecret,
+		TokenURL:     fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", strings.TrimSuffix(Configuration.Oauth.Url, "/"), Configuration.Oauth.Realm),
+	}
+
+	client := credentials.Client(context.Background())
+	client.Timeout = serviceTimeout()
+
+	return client
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	m "metadata-service/internal/models"
 
@@ -12,9 +14,12 @@ import (
 type CategoryService interface {
 	FindAll() ([]m.CategoryDTO, error)
 	FindSingle(CategoryDTO m.CategoryDTO) (m.CategoryDTO, error)
+	Lookup(name string) (m.CategoryDTO, error)
 	Create(CategoryDTO m.CategoryDTO) (m.CategoryDTO, error)
 	Update(CategoryDTO m.CategoryDTO) (m.CategoryDTO, error)
 	Delete(CategoryDTO m.CategoryDTO) error
+	Reassign(CategoryDTO m.CategoryDTO, replacementDTO m.CategoryDTO) error
+	Merge(mergeDTO m.MergeRequestDTO, preview bool) (m.MergeResultDTO, error)
 }
 
 type CategoryHandlers struct {
@@ -71,6 +76,29 @@ func (h *CategoryHandlers) Get(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, categoryDTO)
 }
 
+// Lookup the category a name refers to, by its normalized name or one of its aliases
+func (h *CategoryHandlers) Lookup(ctx *gin.Context) {
+	name := ctx.Query("name")
+	if name == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	categoryDTO, err := h.categoryService.Lookup(name)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, categoryDTO)
+}
+
 func (h *CategoryHandlers) Create(ctx *gin.Context) {
 	var categoryDTO m.CategoryDTO
 	var err error
@@ -82,8 +110,18 @@ func (h *CategoryHandlers) Create(ctx *gin.Context) {
 
 	categoryDTO, err = h.categoryService.Create(categoryDTO)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		switch err.Error() {
+		case "category already exists":
+			// the name refers to an existing category, which is returned instead of creating a duplicate
+			ctx.JSON(http.StatusOK, categoryDTO)
+			return
+		case "alias is already used by another category", "name is already used":
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	ctx.JSON(http.StatusCreated, categoryDTO)
@@ -110,8 +148,14 @@ func (h *CategoryHandlers) Update(ctx *gin.Context) {
 
 	categoryDTO, err = h.categoryService.Update(categoryDTO)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		switch err.Error() {
+		case "category already exists", "alias is already used by another category", "name is already used":
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	ctx.JSON(http.StatusOK, categoryDTO)
@@ -127,11 +171,72 @@ func (h *CategoryHandlers) Delete(ctx *gin.Context) {
 		return
 	}
 
-	err = h.categoryService.Delete(categoryDTO)
+	// with reassignTo the recipes are moved to the replacement, instead of refusing the delete
+	if reassignTo := ctx.Query("reassignTo"); reassignTo != "" {
+		var replacementDTO m.CategoryDTO
+
+		replacementDTO.ID, err = uuid.Parse(reassignTo)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid reassignTo parameter"})
+			return
+		}
+
+		err = h.categoryService.Reassign(categoryDTO, replacementDTO)
+	} else {
+		err = h.categoryService.Delete(categoryDTO)
+	}
+
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		var inUse m.InUseError
+		switch {
+		case errors.As(err, &inUse):
+			ctx.JSON(http.StatusConflict, inUse.ConvertToDTO())
+			return
+		case err.Error() == "replacement not found", err.Error() == "category cannot be reassigned to itself":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// Merge duplicate categories into one. With preview=true nothing is changed, the impacted recipes are only reported.
+func (h *CategoryHandlers) Merge(ctx *gin.Context) {
+	var mergeDTO m.MergeRequestDTO
+	var err error
+
+	if err = ctx.ShouldBindJSON(&mergeDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unexpected JSON input"})
+		return
+	}
+
+	preview := false
+	if value := ctx.Query("preview"); value != "" {
+		preview, err = strconv.ParseBool(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid preview parameter"})
+			return
+		}
+	}
+
+	resultDTO, err := h.categoryService.Merge(mergeDTO, preview)
+	if err != nil {
+		switch err.Error() {
+		case "target not found", "source not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case "category cannot be merged into itself", "no categories to merge":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, resultDTO)
+}
@@ -51,10 +51,27 @@ func (s *CategoryServiceMock) FindSingle(categoryDTO m.CategoryDTO) (m.CategoryD
 	}
 }
 
+func (s *CategoryServiceMock) Lookup(name string) (m.CategoryDTO, error) {
+	switch name {
+	case "Vegetables":
+		return category, nil
+	case "unknown":
+		return m.CategoryDTO{}, errors.New("not found")
+	default:
+		return m.CategoryDTO{}, errors.New("error")
+	}
+}
+
 func (s *CategoryServiceMock) Create(categoryDTO m.CategoryDTO) (m.CategoryDTO, error) {
 	switch categoryDTO.Name {
 	case "create":
 		return category, nil
+	case "Vegetables":
+		return category, errors.New("category already exists")
+	case "taken":
+		return m.CategoryDTO{}, errors.New("alias is already used by another category")
+	case "used":
+		return m.CategoryDTO{}, errors.New("name is already used")
 	default:
 		return m.CategoryDTO{}, errors.New("error")
 	}
@@ -64,6 +81,8 @@ func (s *CategoryServiceMock) Update(categoryDTO m.CategoryDTO) (m.CategoryDTO,
 	switch categoryDTO.Name {
 	case "update":
 		return category, nil
+	case "taken":
+		return m.CategoryDTO{}, errors.New("category already exists")
 	default:
 		return m.CategoryDTO{}, errors.New("error")
 	}
@@ -73,11 +92,39 @@ func (s *CategoryServiceMock) Delete(categoryDTO m.CategoryDTO) error {
 	switch category.Name {
 	case "delete":
 		return nil
+	case "inuse":
+		return m.NewInUseError("category", inUseUsage)
+	default:
+		return errors.New("error")
+	}
+}
+
+func (s *CategoryServiceMock) Reassign(categoryDTO m.CategoryDTO, replacementDTO m.CategoryDTO) error {
+	switch category.Name {
+	case "reassign":
+		return nil
+	case "noreplacement":
+		return errors.New("replacement not found")
 	default:
 		return errors.New("error")
 	}
 }
 
+func (s *CategoryServiceMock) Merge(mergeDTO m.MergeRequestDTO, preview bool) (m.MergeResultDTO, error) {
+	switch category.Name {
+	case "merge":
+		return m.MergeResultDTO{TargetID: mergeDTO.TargetID, Merged: mergeDTO.SourceIDs, Aliases: []string{"duplicate"}, RecipeIDs: []uuid.UUID{}, Preview: preview}, nil
+	case "notfound":
+		return m.MergeResultDTO{}, errors.New("source not found")
+	default:
+		return m.MergeResultDTO{}, errors.New("error")
+	}
+}
+
+var (
+	inUseUsage m.Usage = m.Usage{Count: 12, RecipeIDs: []uuid.UUID{uuid.New(), uuid.New()}}
+)
+
 func TestCategoryGetAll_OK(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	categories = append(categories, category)
@@ -448,3 +495,324 @@ func TestCategoryDelete_DeleteErr(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	assert.Equal(t, `{"error":"error"}`, string(body))
 }
+
+func TestCategoryDelete_InUseErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCategoryHandlers(&CategoryServiceMock{}, &m.LoggerInterfaceMock{})
+
+	category.Name = "inuse"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/category/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: category.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(m.InUseDTO{Error: "category is used by 12 recipes", Count: 12, RecipeIDs: inUseUsage.RecipeIDs, DeletedRecipeIDs: []uuid.UUID{}})
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestCategoryDelete_ReassignOK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCategoryHandlers(&CategoryServiceMock{}, &m.LoggerInterfaceMock{})
+
+	category.Name = "reassign"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/category/1?reassignTo="+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: category.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []byte(``), body)
+}
+
+func TestCategoryDelete_ReassignInvalidErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCategoryHandlers(&CategoryServiceMock{}, &m.LoggerInterfaceMock{})
+
+	category.Name = "reassign"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/category/1?reassignTo=1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: category.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"invalid reassignTo parameter"}`), body)
+}
+
+func TestCategoryDelete_ReassignNotFoundErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCategoryHandlers(&CategoryServiceMock{}, &m.LoggerInterfaceMock{})
+
+	category.Name = "noreplacement"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/category/1?reassignTo="+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: category.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"replacement not found"}`), body)
+}
+
+func TestCategoryMerge_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCategoryHandlers(&CategoryServiceMock{}, &m.LoggerInterfaceMock{})
+
+	category.Name = "merge"
+	mergeDTO := m.MergeRequestDTO{TargetID: category.ID, SourceIDs: []uuid.UUID{uuid.New()}}
+	jsonValue, _ := json.Marshal(mergeDTO)
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/category/merge?preview=true", bytes.NewBuffer(jsonValue))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Merge(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(m.MergeResultDTO{TargetID: category.ID, Merged: mergeDTO.SourceIDs, Aliases: []string{"duplicate"}, RecipeIDs: []uuid.UUID{}, Preview: true})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestCategoryMerge_UnmarshalErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCategoryHandlers(&CategoryServiceMock{}, &m.LoggerInterfaceMock{})
+
+	category.Name = "merge"
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/category/merge", bytes.NewBufferString(`{"source_ids": []}`))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Merge(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"unexpected JSON input"}`, string(body))
+}
+
+func TestCategoryMerge_PreviewErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCategoryHandlers(&CategoryServiceMock{}, &m.LoggerInterfaceMock{})
+
+	category.Name = "merge"
+	jsonValue, _ := json.Marshal(m.MergeRequestDTO{TargetID: category.ID, SourceIDs: []uuid.UUID{uuid.New()}})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/category/merge?preview=maybe", bytes.NewBuffer(jsonValue))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Merge(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid preview parameter"}`, string(body))
+}
+
+func TestCategoryMerge_NotFoundErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCategoryHandlers(&CategoryServiceMock{}, &m.LoggerInterfaceMock{})
+
+	category.Name = "notfound"
+	jsonValue, _ := json.Marshal(m.MergeRequestDTO{TargetID: category.ID, SourceIDs: []uuid.UUID{uuid.New()}})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/category/merge", bytes.NewBuffer(jsonValue))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Merge(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"source not found"}`, string(body))
+}
+
+func TestCategoryLookup_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCategoryHandlers(&CategoryServiceMock{}, &m.LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/category/lookup?name=Vegetables", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Lookup(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(category)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestCategoryLookup_NameRequiredErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCategoryHandlers(&CategoryServiceMock{}, &m.LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/category/lookup", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Lookup(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"name is required"}`, string(body))
+}
+
+func TestCategoryLookup_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCategoryHandlers(&CategoryServiceMock{}, &m.LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/category/lookup?name=unknown", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Lookup(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"category not found"}`, string(body))
+}
+
+func TestCategoryCreate_Existing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCategoryHandlers(&CategoryServiceMock{}, &m.LoggerInterfaceMock{})
+
+	reqBody, _ := json.Marshal(m.CategoryDTO{Name: "Vegetables"})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/category", bytes.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(category)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestCategoryCreate_AliasTakenErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCategoryHandlers(&CategoryServiceMock{}, &m.LoggerInterfaceMock{})
+
+	reqBody, _ := json.Marshal(m.CategoryDTO{Name: "taken"})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/category", bytes.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, `{"error":"alias is already used by another category"}`, string(body))
+}
+
+func TestCategoryCreate_NameUsedErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCategoryHandlers(&CategoryServiceMock{}, &m.LoggerInterfaceMock{})
+
+	reqBody, _ := json.Marshal(m.CategoryDTO{Name: "used"})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/category", bytes.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, `{"error":"name is already used"}`, string(body))
+}
+
+func TestCategoryUpdate_ExistsErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCategoryHandlers(&CategoryServiceMock{}, &m.LoggerInterfaceMock{})
+
+	reqBody, _ := json.Marshal(m.CategoryDTO{Name: "taken"})
+
+	req := httptest.NewRequest("PUT", "http://example.com/api/v2/category/1", bytes.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: category.ID.String()},
+	}
+
+	h.Update(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, `{"error":"category already exists"}`, string(body))
+}
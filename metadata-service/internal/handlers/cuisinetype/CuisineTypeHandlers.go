@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	m "metadata-service/internal/models"
@@ -15,6 +16,7 @@ type CuisineTypeService interface {
 	Create(cuisineTypeDTO m.CuisineTypeDTO) (m.CuisineTypeDTO, error)
 	Update(cuisineTypeDTO m.CuisineTypeDTO) (m.CuisineTypeDTO, error)
 	Delete(cuisineTypeDTO m.CuisineTypeDTO) error
+	Reassign(cuisineTypeDTO m.CuisineTypeDTO, replacementDTO m.CuisineTypeDTO) error
 }
 
 type CuisineTypeHandlers struct {
@@ -126,10 +128,34 @@ func (h *CuisineTypeHandlers) Delete(ctx *gin.Context) {
 		return
 	}
 
-	err = h.cuisineTypeService.Delete(cuisineTypeDTO)
+	// with reassignTo the recipes are moved to the replacement, instead of refusing the delete
+	if reassignTo := ctx.Query("reassignTo"); reassignTo != "" {
+		var replacementDTO m.CuisineTypeDTO
+
+		replacementDTO.ID, err = uuid.Parse(reassignTo)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid reassignTo parameter"})
+			return
+		}
+
+		err = h.cuisineTypeService.Reassign(cuisineTypeDTO, replacementDTO)
+	} else {
+		err = h.cuisineTypeService.Delete(cuisineTypeDTO)
+	}
+
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		var inUse m.InUseError
+		switch {
+		case errors.As(err, &inUse):
+			ctx.JSON(http.StatusConflict, inUse.ConvertToDTO())
+			return
+		case err.Error() == "replacement not found", err.Error() == "cuisine type cannot be reassigned to itself":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	ctx.Status(http.StatusNoContent)
@@ -73,11 +73,28 @@ func (s *CuisineTypeServiceMock) Delete(cuisineTypeDTO m.CuisineTypeDTO) error {
 	switch cuisineType.Name {
 	case "delete":
 		return nil
+	case "inuse":
+		return m.NewInUseError("cuisine type", inUseUsage)
 	default:
 		return errors.New("error")
 	}
 }
 
+func (s *CuisineTypeServiceMock) Reassign(cuisineTypeDTO m.CuisineTypeDTO, replacementDTO m.CuisineTypeDTO) error {
+	switch cuisineType.Name {
+	case "reassign":
+		return nil
+	case "noreplacement":
+		return errors.New("replacement not found")
+	default:
+		return errors.New("error")
+	}
+}
+
+var (
+	inUseUsage m.Usage = m.Usage{Count: 12, RecipeIDs: []uuid.UUID{uuid.New(), uuid.New()}}
+)
+
 func TestCuisineTypeGetAll_OK(t *testing.T) {
 	cuisineTypes = append(cuisineTypes, cuisineType)
 	h := NewCuisineTypeHandlers(&CuisineTypeServiceMock{}, &m.LoggerInterfaceMock{})
@@ -448,3 +465,97 @@ func TestCuisineTypeDelete_DeleteErr(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	assert.Equal(t, []byte(`{"error":"error"}`), body)
 }
+
+func TestCuisineTypeDelete_InUseErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCuisineTypeHandlers(&CuisineTypeServiceMock{}, &m.LoggerInterfaceMock{})
+
+	cuisineType.Name = "inuse"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/cuisineType/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: cuisineType.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(m.InUseDTO{Error: "cuisine type is used by 12 recipes", Count: 12, RecipeIDs: inUseUsage.RecipeIDs, DeletedRecipeIDs: []uuid.UUID{}})
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestCuisineTypeDelete_ReassignOK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCuisineTypeHandlers(&CuisineTypeServiceMock{}, &m.LoggerInterfaceMock{})
+
+	cuisineType.Name = "reassign"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/cuisineType/1?reassignTo="+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: cuisineType.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []byte(``), body)
+}
+
+func TestCuisineTypeDelete_ReassignInvalidErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCuisineTypeHandlers(&CuisineTypeServiceMock{}, &m.LoggerInterfaceMock{})
+
+	cuisineType.Name = "reassign"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/cuisineType/1?reassignTo=1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: cuisineType.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"invalid reassignTo parameter"}`), body)
+}
+
+func TestCuisineTypeDelete_ReassignNotFoundErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCuisineTypeHandlers(&CuisineTypeServiceMock{}, &m.LoggerInterfaceMock{})
+
+	cuisineType.Name = "noreplacement"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/cuisineType/1?reassignTo="+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: cuisineType.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"replacement not found"}`), body)
+}
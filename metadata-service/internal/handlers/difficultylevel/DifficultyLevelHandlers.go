@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	m "metadata-service/internal/models"
@@ -15,6 +16,7 @@ type DifficultyLevelService interface {
 	Create(difficultyLevelDTO m.DifficultyLevelDTO) (m.DifficultyLevelDTO, error)
 	Update(difficultyLevelDTO m.DifficultyLevelDTO) (m.DifficultyLevelDTO, error)
 	Delete(difficultyLevelDTO m.DifficultyLevelDTO) error
+	Reassign(difficultyLevelDTO m.DifficultyLevelDTO, replacementDTO m.DifficultyLevelDTO) error
 }
 
 type DifficultyLevelHandlers struct {
@@ -126,10 +128,34 @@ func (h *DifficultyLevelHandlers) Delete(ctx *gin.Context) {
 		return
 	}
 
-	err = h.difficultyLevelService.Delete(difficultyLevelDTO)
+	// with reassignTo the recipes are moved to the replacement, instead of refusing the delete
+	if reassignTo := ctx.Query("reassignTo"); reassignTo != "" {
+		var replacementDTO m.DifficultyLevelDTO
+
+		replacementDTO.ID, err = uuid.Parse(reassignTo)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid reassignTo parameter"})
+			return
+		}
+
+		err = h.difficultyLevelService.Reassign(difficultyLevelDTO, replacementDTO)
+	} else {
+		err = h.difficultyLevelService.Delete(difficultyLevelDTO)
+	}
+
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		var inUse m.InUseError
+		switch {
+		case errors.As(err, &inUse):
+			ctx.JSON(http.StatusConflict, inUse.ConvertToDTO())
+			return
+		case err.Error() == "replacement not found", err.Error() == "difficulty level cannot be reassigned to itself":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	ctx.Status(http.StatusNoContent)
@@ -73,11 +73,28 @@ func (s *DifficultyLevelServiceMock) Delete(difficultyLevelDTO m.DifficultyLevel
 	switch difficultyLevel.Level {
 	case 1:
 		return nil
+	case 4:
+		return m.NewInUseError("difficulty level", inUseUsage)
 	default:
 		return errors.New("error")
 	}
 }
 
+func (s *DifficultyLevelServiceMock) Reassign(difficultyLevelDTO m.DifficultyLevelDTO, replacementDTO m.DifficultyLevelDTO) error {
+	switch difficultyLevel.Level {
+	case 1:
+		return nil
+	case 5:
+		return errors.New("replacement not found")
+	default:
+		return errors.New("error")
+	}
+}
+
+var (
+	inUseUsage m.Usage = m.Usage{Count: 12, RecipeIDs: []uuid.UUID{uuid.New(), uuid.New()}}
+)
+
 func TestDifficultyLevelGetAll_OK(t *testing.T) {
 	difficultyLevels = append(difficultyLevels, difficultyLevel)
 	h := NewDifficultyLevelHandlers(&DifficultyLevelServiceMock{}, &m.LoggerInterfaceMock{})
@@ -448,3 +465,97 @@ func TestDifficultyLevelDelete_DeleteErr(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	assert.Equal(t, []byte(`{"error":"error"}`), body)
 }
+
+func TestDifficultyLevelDelete_InUseErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewDifficultyLevelHandlers(&DifficultyLevelServiceMock{}, &m.LoggerInterfaceMock{})
+
+	difficultyLevel.Level = 4
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/difficultyLevel/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: difficultyLevel.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(m.InUseDTO{Error: "difficulty level is used by 12 recipes", Count: 12, RecipeIDs: inUseUsage.RecipeIDs, DeletedRecipeIDs: []uuid.UUID{}})
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestDifficultyLevelDelete_ReassignOK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewDifficultyLevelHandlers(&DifficultyLevelServiceMock{}, &m.LoggerInterfaceMock{})
+
+	difficultyLevel.Level = 1
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/difficultyLevel/1?reassignTo="+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: difficultyLevel.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []byte(``), body)
+}
+
+func TestDifficultyLevelDelete_ReassignInvalidErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewDifficultyLevelHandlers(&DifficultyLevelServiceMock{}, &m.LoggerInterfaceMock{})
+
+	difficultyLevel.Level = 1
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/difficultyLevel/1?reassignTo=1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: difficultyLevel.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"invalid reassignTo parameter"}`), body)
+}
+
+func TestDifficultyLevelDelete_ReassignNotFoundErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewDifficultyLevelHandlers(&DifficultyLevelServiceMock{}, &m.LoggerInterfaceMock{})
+
+	difficultyLevel.Level = 5
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/difficultyLevel/1?reassignTo="+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: difficultyLevel.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"replacement not found"}`), body)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	m "metadata-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecipeService interface {
+	Handle(ctx context.Context, eventDTO m.ChangeEventDTO) error
+}
+
+type EventHandlers struct {
+	recipeService RecipeService
+	logger        m.LoggerInterface
+}
+
+func NewEventHandlers(recipeService RecipeService, logger m.LoggerInterface) *EventHandlers {
+	return &EventHandlers{
+		recipeService: recipeService,
+		logger:        logger,
+	}
+}
+
+// Handle updates the copy of a recipe on a change reported by the recipe service, such as
+// {"type":"updated","entity_type":"recipe","entity_id":"...","occurred_at":"..."}
+func (h EventHandlers) Handle(ctx *gin.Context) {
+	var eventDTO m.ChangeEventDTO
+
+	if err := ctx.ShouldBindJSON(&eventDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unexpected JSON input"})
+		return
+	}
+
+	if err := h.recipeService.Handle(ctx.Request.Context(), eventDTO); err != nil {
+		switch err.Error() {
+		case "invalid event type", "invalid entity type", "invalid entity ID":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Consume updates the copy of a recipe on a change consumed from the event broker. Events that can never be handled
+// are skipped. Any other error has the event delivered again.
+func (h EventHandlers) Consume(ctx context.Context, event m.CloudEventDTO) error {
+	var eventDTO m.ChangeEventDTO
+
+	if err := json.Unmarshal(event.Data, &eventDTO); err != nil {
+		h.logger.Warnf("skipping event %s (%s): unexpected JSON input", event.ID, event.Type)
+		return nil
+	}
+
+	if err := h.recipeService.Handle(ctx, eventDTO); err != nil {
+		switch err.Error() {
+		case "invalid event type", "invalid entity type", "invalid entity ID":
+			h.logger.Debugf("skipping event %s (%s): %s", event.ID, event.Type, err.Error())
+			return nil
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	m "metadata-service/internal/models"
@@ -15,6 +16,7 @@ type PreparationTimeService interface {
 	Create(preparationTimeDTO m.PreparationTimeDTO) (m.PreparationTimeDTO, error)
 	Update(preparationTimeDTO m.PreparationTimeDTO) (m.PreparationTimeDTO, error)
 	Delete(preparationTimeDTO m.PreparationTimeDTO) error
+	Reassign(preparationTimeDTO m.PreparationTimeDTO, replacementDTO m.PreparationTimeDTO) error
 }
 
 type PreparationTimeHandlers struct {
@@ -126,10 +128,34 @@ func (h *PreparationTimeHandlers) Delete(ctx *gin.Context) {
 		return
 	}
 
-	err = h.preparationTimeService.Delete(preparationTimeDTO)
+	// with reassignTo the recipes are moved to the replacement, instead of refusing the delete
+	if reassignTo := ctx.Query("reassignTo"); reassignTo != "" {
+		var replacementDTO m.PreparationTimeDTO
+
+		replacementDTO.ID, err = uuid.Parse(reassignTo)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid reassignTo parameter"})
+			return
+		}
+
+		err = h.preparationTimeService.Reassign(preparationTimeDTO, replacementDTO)
+	} else {
+		err = h.preparationTimeService.Delete(preparationTimeDTO)
+	}
+
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		var inUse m.InUseError
+		switch {
+		case errors.As(err, &inUse):
+			ctx.JSON(http.StatusConflict, inUse.ConvertToDTO())
+			return
+		case err.Error() == "replacement not found", err.Error() == "preparation time cannot be reassigned to itself":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	ctx.Status(http.StatusNoContent)
@@ -73,11 +73,28 @@ func (s *PreparationTimeServiceMock) Delete(preparationTimeDTO m.PreparationTime
 	switch preparationTime.Duration {
 	case 1:
 		return nil
+	case 4:
+		return m.NewInUseError("preparation time", inUseUsage)
 	default:
 		return errors.New("error")
 	}
 }
 
+func (s *PreparationTimeServiceMock) Reassign(preparationTimeDTO m.PreparationTimeDTO, replacementDTO m.PreparationTimeDTO) error {
+	switch preparationTime.Duration {
+	case 1:
+		return nil
+	case 5:
+		return errors.New("replacement not found")
+	default:
+		return errors.New("error")
+	}
+}
+
+var (
+	inUseUsage m.Usage = m.Usage{Count: 12, RecipeIDs: []uuid.UUID{uuid.New(), uuid.New()}}
+)
+
 func TestPreparationTimeGetAll_OK(t *testing.T) {
 	preparationTimes = append(preparationTimes, preparationTime)
 	h := NewPreparationTimeHandlers(&PreparationTimeServiceMock{}, &m.LoggerInterfaceMock{})
@@ -448,3 +465,97 @@ func TestPreparationTimeDelete_DeleteErr(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	assert.Equal(t, []byte(`{"error":"error"}`), body)
 }
+
+func TestPreparationTimeDelete_InUseErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewPreparationTimeHandlers(&PreparationTimeServiceMock{}, &m.LoggerInterfaceMock{})
+
+	preparationTime.Duration = 4
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/preparationTime/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: preparationTime.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(m.InUseDTO{Error: "preparation time is used by 12 recipes", Count: 12, RecipeIDs: inUseUsage.RecipeIDs, DeletedRecipeIDs: []uuid.UUID{}})
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestPreparationTimeDelete_ReassignOK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewPreparationTimeHandlers(&PreparationTimeServiceMock{}, &m.LoggerInterfaceMock{})
+
+	preparationTime.Duration = 1
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/preparationTime/1?reassignTo="+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: preparationTime.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []byte(``), body)
+}
+
+func TestPreparationTimeDelete_ReassignInvalidErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewPreparationTimeHandlers(&PreparationTimeServiceMock{}, &m.LoggerInterfaceMock{})
+
+	preparationTime.Duration = 1
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/preparationTime/1?reassignTo=1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: preparationTime.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"invalid reassignTo parameter"}`), body)
+}
+
+func TestPreparationTimeDelete_ReassignNotFoundErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewPreparationTimeHandlers(&PreparationTimeServiceMock{}, &m.LoggerInterfaceMock{})
+
+	preparationTime.Duration = 5
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/preparationTime/1?reassignTo="+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: preparationTime.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"replacement not found"}`), body)
+}
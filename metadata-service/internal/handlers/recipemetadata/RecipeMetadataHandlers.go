@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	m "metadata-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RecipeMetadataService interface {
+	FindByRecipe(recipeID uuid.UUID) (m.RecipeMetadataDTO, error)
+	FindByRecipes(recipeIDs []uuid.UUID) ([]m.RecipeMetadataDTO, error)
+	Replace(recipeID uuid.UUID, requestDTO m.RecipeMetadataRequestDTO) (m.RecipeMetadataDTO, error)
+	DeleteByRecipe(recipeID uuid.UUID) error
+	RestoreByRecipe(recipeID uuid.UUID, since time.Time) error
+	PurgeByRecipe(recipeID uuid.UUID) error
+}
+
+type RecipeMetadataHandlers struct {
+	recipeMetadataService RecipeMetadataService
+	logger                m.LoggerInterface
+}
+
+func NewRecipeMetadataHandlers(recipeMetadata RecipeMetadataService, logger m.LoggerInterface) *RecipeMetadataHandlers {
+	return &RecipeMetadataHandlers{
+		recipeMetadataService: recipeMetadata,
+		logger:                logger,
+	}
+}
+
+func (h *RecipeMetadataHandlers) Get(ctx *gin.Context) {
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	metadataDTO, err := h.recipeMetadataService.FindByRecipe(recipeID)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "no metadata found"})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, metadataDTO)
+}
+
+// GetAll returns the metadata of the recipes passed as ?recipe_ids=<id>,<id>
+func (h *RecipeMetadataHandlers) GetAll(ctx *gin.Context) {
+	var recipeIDs []uuid.UUID
+
+	for _, value := range strings.Split(ctx.Query("recipe_ids"), ",") {
+		if value == "" {
+			continue
+		}
+
+		recipeID, err := uuid.Parse(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+			return
+		}
+		recipeIDs = append(recipeIDs, recipeID)
+	}
+
+	if len(recipeIDs) <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "no recipe IDs provided"})
+		return
+	}
+
+	metadataDTOs, err := h.recipeMetadataService.FindByRecipes(recipeIDs)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "no metadata found"})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, metadataDTOs)
+}
+
+func (h *RecipeMetadataHandlers) Replace(ctx *gin.Context) {
+	var requestDTO m.RecipeMetadataRequestDTO
+
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	if err = ctx.ShouldBindJSON(&requestDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unexpected JSON input"})
+		return
+	}
+
+	metadataDTO, err := h.recipeMetadataService.Replace(recipeID, requestDTO)
+	if err != nil {
+		switch err.Error() {
+		case "internal server error":
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		default:
+			// everything else is a violation of the metadata rules
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, metadataDTO)
+}
+
+// Delete removes all metadata from a deleted recipe
+func (h *RecipeMetadataHandlers) Delete(ctx *gin.Context) {
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	if err = h.recipeMetadataService.DeleteByRecipe(recipeID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Restore brings back the metadata of a restored recipe. The optional since parameter limits the restore to the
+// metadata removed along with the recipe.
+func (h *RecipeMetadataHandlers) Restore(ctx *gin.Context) {
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	var since time.Time
+	if value := ctx.Query("since"); value != "" {
+		if since, err = time.Parse(time.RFC3339, value); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid since parameter"})
+			return
+		}
+	}
+
+	if err = h.recipeMetadataService.RestoreByRecipe(recipeID, since); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Purge permanently removes the metadata of a deleted recipe that can no longer be restored
+func (h *RecipeMetadataHandlers) Purge(ctx *gin.Context) {
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	if err = h.recipeMetadataService.PurgeByRecipe(recipeID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	m "metadata-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type RecipeMetadataServiceMock struct{}
+
+var (
+	recipeFound    uuid.UUID = uuid.New()
+	recipeNotFound uuid.UUID = uuid.New()
+	recipeInvalid  uuid.UUID = uuid.New()
+
+	metadata m.RecipeMetadataDTO = m.RecipeMetadataDTO{
+		RecipeID:        recipeFound,
+		Categories:      []m.CategoryDTO{{ID: uuid.New(), Name: "dessert"}},
+		Tags:            []m.TagDTO{},
+		CuisineType:     m.CuisineTypeDTO{ID: uuid.New(), Name: "french"},
+		DifficultyLevel: m.DifficultyLevelDTO{ID: uuid.New(), Level: 2},
+		PreparationTime: m.PreparationTimeDTO{ID: uuid.New(), Duration: 30},
+	}
+
+	requestedRecipeIDs []uuid.UUID
+)
+
+// ====== RecipeMetadataService ======
+
+func (s *RecipeMetadataServiceMock) FindByRecipe(recipeID uuid.UUID) (m.RecipeMetadataDTO, error) {
+	switch recipeID {
+	case recipeFound:
+		return metadata, nil
+	case recipeNotFound:
+		return m.RecipeMetadataDTO{}, errors.New("not found")
+	default:
+		return m.RecipeMetadataDTO{}, errors.New("internal server error")
+	}
+}
+
+func (s *RecipeMetadataServiceMock) FindByRecipes(recipeIDs []uuid.UUID) ([]m.RecipeMetadataDTO, error) {
+	requestedRecipeIDs = recipeIDs
+
+	result, err := s.FindByRecipe(recipeIDs[0])
+	if err != nil {
+		return nil, err
+	}
+	return []m.RecipeMetadataDTO{result}, nil
+}
+
+func (s *RecipeMetadataServiceMock) Replace(recipeID uuid.UUID, requestDTO m.RecipeMetadataRequestDTO) (m.RecipeMetadataDTO, error) {
+	if recipeID == recipeInvalid {
+		return m.RecipeMetadataDTO{}, errors.New("exactly one cuisine type is required")
+	}
+	return s.FindByRecipe(recipeID)
+}
+
+func (s *RecipeMetadataServiceMock) DeleteByRecipe(recipeID uuid.UUID) error {
+	if recipeID != recipeFound {
+		return errors.New("internal server error")
+	}
+	return nil
+}
+
+func (s *RecipeMetadataServiceMock) RestoreByRecipe(recipeID uuid.UUID, since time.Time) error {
+	return s.DeleteByRecipe(recipeID)
+}
+
+func (s *RecipeMetadataServiceMock) PurgeByRecipe(recipeID uuid.UUID) error {
+	return s.DeleteByRecipe(recipeID)
+}
+
+func newTestContext(method string, url string, body io.Reader, params gin.Params) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, url, body)
+	c.Params = params
+
+	return c, w
+}
+
+// ====== Tests ======
+
+func TestRecipeMetadataGet_OK(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	c, w := newTestContext("GET", "http://example.com/api/v2/metadata/recipe/1", nil, gin.Params{{Key: "id", Value: recipeFound.String()}})
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+	expectedBody, _ := json.Marshal(metadata)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestRecipeMetadataGet_IDErr(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	c, w := newTestContext("GET", "http://example.com/api/v2/metadata/recipe/1", nil, gin.Params{{Key: "id", Value: "1"}})
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid recipe ID"}`, string(body))
+}
+
+func TestRecipeMetadataGet_NotFound(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	c, w := newTestContext("GET", "http://example.com/api/v2/metadata/recipe/1", nil, gin.Params{{Key: "id", Value: recipeNotFound.String()}})
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"no metadata found"}`, string(body))
+}
+
+func TestRecipeMetadataGet_Err(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	c, w := newTestContext("GET", "http://example.com/api/v2/metadata/recipe/1", nil, gin.Params{{Key: "id", Value: uuid.NewString()}})
+
+	h.Get(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestRecipeMetadataGetAll_OK(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	otherRecipeID := uuid.New()
+	c, w := newTestContext("GET", "http://example.com/api/v2/metadata/recipe?recipe_ids="+recipeFound.String()+","+otherRecipeID.String(), nil, nil)
+
+	h.GetAll(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+	expectedBody, _ := json.Marshal([]m.RecipeMetadataDTO{metadata})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+	assert.Equal(t, []uuid.UUID{recipeFound, otherRecipeID}, requestedRecipeIDs)
+}
+
+func TestRecipeMetadataGetAll_MissingErr(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	c, w := newTestContext("GET", "http://example.com/api/v2/metadata/recipe", nil, nil)
+
+	h.GetAll(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"no recipe IDs provided"}`, string(body))
+}
+
+func TestRecipeMetadataReplace_OK(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	reqBody, _ := json.Marshal(m.RecipeMetadataRequestDTO{CategoryIDs: []uuid.UUID{uuid.New()}})
+	c, w := newTestContext("PUT", "http://example.com/api/v2/metadata/recipe/1", bytes.NewReader(reqBody), gin.Params{{Key: "id", Value: recipeFound.String()}})
+
+	h.Replace(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+	expectedBody, _ := json.Marshal(metadata)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestRecipeMetadataReplace_UnmarshalErr(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	c, w := newTestContext("PUT", "http://example.com/api/v2/metadata/recipe/1", bytes.NewReader([]byte(`{"category_ids": "1"}`)), gin.Params{{Key: "id", Value: recipeFound.String()}})
+
+	h.Replace(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"unexpected JSON input"}`, string(body))
+}
+
+func TestRecipeMetadataReplace_ValidationErr(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	c, w := newTestContext("PUT", "http://example.com/api/v2/metadata/recipe/1", bytes.NewReader([]byte(`{}`)), gin.Params{{Key: "id", Value: recipeInvalid.String()}})
+
+	h.Replace(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"exactly one cuisine type is required"}`, string(body))
+}
+
+func TestRecipeMetadataReplace_Err(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	c, w := newTestContext("PUT", "http://example.com/api/v2/metadata/recipe/1", bytes.NewReader([]byte(`{}`)), gin.Params{{Key: "id", Value: uuid.NewString()}})
+
+	h.Replace(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestRecipeMetadataDelete_OK(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	c, w := newTestContext("DELETE", "http://example.com/api/v2/metadata/recipe/1", nil, gin.Params{{Key: "id", Value: recipeFound.String()}})
+
+	h.Delete(c)
+
+	assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+	assert.Empty(t, w.Body.String())
+}
+
+func TestRecipeMetadataDelete_IDErr(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	c, w := newTestContext("DELETE", "http://example.com/api/v2/metadata/recipe/1", nil, gin.Params{{Key: "id", Value: "1"}})
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid recipe ID"}`, string(body))
+}
+
+func TestRecipeMetadataDelete_Err(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	c, w := newTestContext("DELETE", "http://example.com/api/v2/metadata/recipe/1", nil, gin.Params{{Key: "id", Value: uuid.NewString()}})
+
+	h.Delete(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestRecipeMetadataRestore_OK(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	c, _ := newTestContext("POST", "http://example.com/api/v2/metadata/recipe/1/restore?since=2024-05-01T10:00:00Z", nil, gin.Params{{Key: "id", Value: recipeFound.String()}})
+
+	h.Restore(c)
+
+	assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+}
+
+func TestRecipeMetadataRestore_SinceErr(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	c, w := newTestContext("POST", "http://example.com/api/v2/metadata/recipe/1/restore?since=yesterday", nil, gin.Params{{Key: "id", Value: recipeFound.String()}})
+
+	h.Restore(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid since parameter"}`, string(body))
+}
+
+func TestRecipeMetadataPurge_OK(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	c, _ := newTestContext("POST", "http://example.com/api/v2/metadata/recipe/1/purge", nil, gin.Params{{Key: "id", Value: recipeFound.String()}})
+
+	h.Purge(c)
+
+	assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+}
+
+func TestRecipeMetadataPurge_Err(t *testing.T) {
+	h := NewRecipeMetadataHandlers(&RecipeMetadataServiceMock{}, &m.LoggerInterfaceMock{})
+	c, w := newTestContext("POST", "http://example.com/api/v2/metadata/recipe/1/purge", nil, gin.Params{{Key: "id", Value: uuid.NewString()}})
+
+	h.Purge(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
@@ -8,7 +8,7 @@ import (
 )
 
 type SearchService interface {
-	SearchMetadata(m.MetadataSearchRequestDTO) ([]m.MetadataSearchResultDTO, error)
+	SearchMetadata(m.MetadataSearchRequestDTO) (m.MetadataSearchResponseDTO, error)
 }
 
 type SearchHandlers struct {
@@ -32,11 +32,18 @@ func (h *SearchHandlers) SearchMetadata(ctx *gin.Context) {
 		return
 	}
 
-	searchResultDTO, err := h.searchService.SearchMetadata(searchRequestDTO)
+	searchResponseDTO, err := h.searchService.SearchMetadata(searchRequestDTO)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		switch err.Error() {
+		case "internal server error":
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		default:
+			// everything else is an invalid cursor or filter
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
-	ctx.JSON(http.StatusOK, searchResultDTO)
+	ctx.JSON(http.StatusOK, searchResponseDTO)
 }
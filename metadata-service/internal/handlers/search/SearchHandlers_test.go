@@ -24,11 +24,11 @@ var (
 	id      uuid.UUID = uuid.New()
 
 	searchRequestDTO m.MetadataSearchRequestDTO = m.MetadataSearchRequestDTO{
-		RecipeID:          id,
-		CategoryID:        id,
-		TagID:             id,
-		DifficultyLevelID: id,
-		CuisineTypeID:     id,
+		CategoryIDs:       []uuid.UUID{id},
+		TagIDs:            []uuid.UUID{id},
+		TagMode:           m.TagModeAny,
+		CuisineTypeIDs:    []uuid.UUID{id},
+		DifficultyLevelID: &id,
 		MinPrepTime:       &minTime,
 		MaxPrepTime:       &maxTime,
 	}
@@ -41,18 +41,31 @@ var (
 		PreparationTimeID: id,
 		CuisineTypeID:     id,
 	}
+
+	searchResponseDTO m.MetadataSearchResponseDTO = m.MetadataSearchResponseDTO{
+		Results:    []m.MetadataSearchResultDTO{searchResultDTO},
+		Total:      1,
+		NextCursor: m.MetadataSearchCursor{Sort: m.SearchSortNewest, RecipeID: id}.Encode(),
+		Facets: m.MetadataFacetsDTO{
+			Categories:       []m.MetadataFacetDTO{{ID: id, Name: "Dinner", Count: 1}},
+			Tags:             []m.MetadataFacetDTO{},
+			CuisineTypes:     []m.MetadataFacetDTO{},
+			DifficultyLevels: []m.MetadataFacetDTO{},
+			PreparationTimes: []m.PreparationTimeFacetDTO{{Name: "over 60 min", MinPrepTime: 61, Count: 1}},
+		},
+	}
 )
 
 // ====== SearchService ======
 
-func (s *SearchServiceMock) SearchMetadata(request m.MetadataSearchRequestDTO) ([]m.MetadataSearchResultDTO, error) {
+func (s *SearchServiceMock) SearchMetadata(request m.MetadataSearchRequestDTO) (m.MetadataSearchResponseDTO, error) {
 	switch *request.MinPrepTime {
 	case 1:
-		var response []m.MetadataSearchResultDTO
-		response = append(response, searchResultDTO)
-		return response, nil
+		return searchResponseDTO, nil
+	case 3:
+		return m.MetadataSearchResponseDTO{}, errors.New("invalid cursor")
 	default:
-		return nil, errors.New("error")
+		return m.MetadataSearchResponseDTO{}, errors.New("internal server error")
 	}
 }
 
@@ -73,7 +86,7 @@ func TestSearch_OK(t *testing.T) {
 	resp := w.Result()
 	body, _ := io.ReadAll(resp.Body)
 
-	expectedBody, _ := json.Marshal([]m.MetadataSearchResultDTO{searchResultDTO})
+	expectedBody, _ := json.Marshal(searchResponseDTO)
 
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 	assert.Equal(t, expectedBody, body)
@@ -114,5 +127,41 @@ func TestSearch_SearchErr(t *testing.T) {
 	body, _ := io.ReadAll(resp.Body)
 
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
-	assert.Equal(t, `{"error":"error"}`, string(body))
+	assert.Equal(t, `{"error":"internal server error"}`, string(body))
+}
+
+func TestSearch_CursorErr(t *testing.T) {
+	h := NewSearchHandlers(&SearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	minTime = 3
+
+	reqBody, _ := json.Marshal(searchRequestDTO)
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/tag/1", bytes.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.SearchMetadata(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid cursor"}`, string(body))
+}
+
+func TestSearch_BindingErr(t *testing.T) {
+	h := NewSearchHandlers(&SearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/tag/1", bytes.NewReader([]byte(`{"sort":"rating"}`)))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.SearchMetadata(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	m "metadata-service/internal/models"
 
@@ -12,9 +14,12 @@ import (
 type TagService interface {
 	FindAll() ([]m.TagDTO, error)
 	FindSingle(tagDTO m.TagDTO) (m.TagDTO, error)
+	Lookup(name string) (m.TagDTO, error)
 	Create(tagDTO m.TagDTO) (m.TagDTO, error)
 	Update(tagDTO m.TagDTO) (m.TagDTO, error)
 	Delete(tagDTO m.TagDTO) error
+	Reassign(tagDTO m.TagDTO, replacementDTO m.TagDTO) error
+	Merge(mergeDTO m.MergeRequestDTO, preview bool) (m.MergeResultDTO, error)
 }
 
 type TagHandlers struct {
@@ -70,6 +75,29 @@ func (h *TagHandlers) Get(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, tagDTO)
 }
 
+// Lookup the tag a name refers to, by its normalized name or one of its aliases
+func (h *TagHandlers) Lookup(ctx *gin.Context) {
+	name := ctx.Query("name")
+	if name == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	tagDTO, err := h.tagService.Lookup(name)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, tagDTO)
+}
+
 func (h *TagHandlers) Create(ctx *gin.Context) {
 	var tagDTO m.TagDTO
 	var err error
@@ -81,8 +109,18 @@ func (h *TagHandlers) Create(ctx *gin.Context) {
 
 	tagDTO, err = h.tagService.Create(tagDTO)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		switch err.Error() {
+		case "tag already exists":
+			// the name refers to an existing tag, which is returned instead of creating a duplicate
+			ctx.JSON(http.StatusOK, tagDTO)
+			return
+		case "alias is already used by another tag", "name is already used":
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	ctx.JSON(http.StatusCreated, tagDTO)
@@ -109,8 +147,14 @@ func (h *TagHandlers) Update(ctx *gin.Context) {
 
 	tagDTO, err = h.tagService.Update(tagDTO)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		switch err.Error() {
+		case "tag already exists", "alias is already used by another tag", "name is already used":
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	ctx.JSON(http.StatusOK, tagDTO)
@@ -126,11 +170,72 @@ func (h *TagHandlers) Delete(ctx *gin.Context) {
 		return
 	}
 
-	err = h.tagService.Delete(tagDTO)
+	// with reassignTo the recipes are moved to the replacement, instead of refusing the delete
+	if reassignTo := ctx.Query("reassignTo"); reassignTo != "" {
+		var replacementDTO m.TagDTO
+
+		replacementDTO.ID, err = uuid.Parse(reassignTo)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid reassignTo parameter"})
+			return
+		}
+
+		err = h.tagService.Reassign(tagDTO, replacementDTO)
+	} else {
+		err = h.tagService.Delete(tagDTO)
+	}
+
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		var inUse m.InUseError
+		switch {
+		case errors.As(err, &inUse):
+			ctx.JSON(http.StatusConflict, inUse.ConvertToDTO())
+			return
+		case err.Error() == "replacement not found", err.Error() == "tag cannot be reassigned to itself":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// Merge duplicate tags into one. With preview=true nothing is changed, the impacted recipes are only reported.
+func (h *TagHandlers) Merge(ctx *gin.Context) {
+	var mergeDTO m.MergeRequestDTO
+	var err error
+
+	if err = ctx.ShouldBindJSON(&mergeDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unexpected JSON input"})
+		return
+	}
+
+	preview := false
+	if value := ctx.Query("preview"); value != "" {
+		preview, err = strconv.ParseBool(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid preview parameter"})
+			return
+		}
+	}
+
+	resultDTO, err := h.tagService.Merge(mergeDTO, preview)
+	if err != nil {
+		switch err.Error() {
+		case "target not found", "source not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case "tag cannot be merged into itself", "no tags to merge":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, resultDTO)
+}
@@ -51,10 +51,27 @@ func (s *TagServiceMock) FindSingle(tagDTO m.TagDTO) (m.TagDTO, error) {
 	}
 }
 
+func (s *TagServiceMock) Lookup(name string) (m.TagDTO, error) {
+	switch name {
+	case "Vegetables":
+		return tag, nil
+	case "unknown":
+		return m.TagDTO{}, errors.New("not found")
+	default:
+		return m.TagDTO{}, errors.New("error")
+	}
+}
+
 func (s *TagServiceMock) Create(tagDTO m.TagDTO) (m.TagDTO, error) {
 	switch tagDTO.Name {
 	case "create":
 		return tag, nil
+	case "Vegetables":
+		return tag, errors.New("tag already exists")
+	case "taken":
+		return m.TagDTO{}, errors.New("alias is already used by another tag")
+	case "used":
+		return m.TagDTO{}, errors.New("name is already used")
 	default:
 		return m.TagDTO{}, errors.New("error")
 	}
@@ -64,6 +81,8 @@ func (s *TagServiceMock) Update(tagDTO m.TagDTO) (m.TagDTO, error) {
 	switch tagDTO.Name {
 	case "update":
 		return tag, nil
+	case "taken":
+		return m.TagDTO{}, errors.New("tag already exists")
 	default:
 		return m.TagDTO{}, errors.New("error")
 	}
@@ -73,11 +92,39 @@ func (s *TagServiceMock) Delete(tagDTO m.TagDTO) error {
 	switch tag.Name {
 	case "delete":
 		return nil
+	case "inuse":
+		return m.NewInUseError("tag", inUseUsage)
+	default:
+		return errors.New("error")
+	}
+}
+
+func (s *TagServiceMock) Reassign(tagDTO m.TagDTO, replacementDTO m.TagDTO) error {
+	switch tag.Name {
+	case "reassign":
+		return nil
+	case "noreplacement":
+		return errors.New("replacement not found")
 	default:
 		return errors.New("error")
 	}
 }
 
+func (s *TagServiceMock) Merge(mergeDTO m.MergeRequestDTO, preview bool) (m.MergeResultDTO, error) {
+	switch tag.Name {
+	case "merge":
+		return m.MergeResultDTO{TargetID: mergeDTO.TargetID, Merged: mergeDTO.SourceIDs, Aliases: []string{"duplicate"}, RecipeIDs: []uuid.UUID{}, Preview: preview}, nil
+	case "notfound":
+		return m.MergeResultDTO{}, errors.New("source not found")
+	default:
+		return m.MergeResultDTO{}, errors.New("error")
+	}
+}
+
+var (
+	inUseUsage m.Usage = m.Usage{Count: 12, RecipeIDs: []uuid.UUID{uuid.New(), uuid.New()}}
+)
+
 func TestTagGetAll_OK(t *testing.T) {
 	tags = append(tags, tag)
 	h := NewTagHandlers(&TagServiceMock{}, &m.LoggerInterfaceMock{})
@@ -448,3 +495,324 @@ func TestTagDelete_DeleteErr(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	assert.Equal(t, []byte(`{"error":"error"}`), body)
 }
+
+func TestTagDelete_InUseErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTagHandlers(&TagServiceMock{}, &m.LoggerInterfaceMock{})
+
+	tag.Name = "inuse"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/tag/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: tag.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(m.InUseDTO{Error: "tag is used by 12 recipes", Count: 12, RecipeIDs: inUseUsage.RecipeIDs, DeletedRecipeIDs: []uuid.UUID{}})
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestTagDelete_ReassignOK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTagHandlers(&TagServiceMock{}, &m.LoggerInterfaceMock{})
+
+	tag.Name = "reassign"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/tag/1?reassignTo="+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: tag.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []byte(``), body)
+}
+
+func TestTagDelete_ReassignInvalidErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTagHandlers(&TagServiceMock{}, &m.LoggerInterfaceMock{})
+
+	tag.Name = "reassign"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/tag/1?reassignTo=1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: tag.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"invalid reassignTo parameter"}`), body)
+}
+
+func TestTagDelete_ReassignNotFoundErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTagHandlers(&TagServiceMock{}, &m.LoggerInterfaceMock{})
+
+	tag.Name = "noreplacement"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/tag/1?reassignTo="+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: tag.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"replacement not found"}`), body)
+}
+
+func TestTagMerge_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTagHandlers(&TagServiceMock{}, &m.LoggerInterfaceMock{})
+
+	tag.Name = "merge"
+	mergeDTO := m.MergeRequestDTO{TargetID: tag.ID, SourceIDs: []uuid.UUID{uuid.New()}}
+	jsonValue, _ := json.Marshal(mergeDTO)
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/tag/merge?preview=true", bytes.NewBuffer(jsonValue))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Merge(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(m.MergeResultDTO{TargetID: tag.ID, Merged: mergeDTO.SourceIDs, Aliases: []string{"duplicate"}, RecipeIDs: []uuid.UUID{}, Preview: true})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestTagMerge_UnmarshalErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTagHandlers(&TagServiceMock{}, &m.LoggerInterfaceMock{})
+
+	tag.Name = "merge"
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/tag/merge", bytes.NewBufferString(`{"source_ids": []}`))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Merge(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"unexpected JSON input"}`, string(body))
+}
+
+func TestTagMerge_PreviewErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTagHandlers(&TagServiceMock{}, &m.LoggerInterfaceMock{})
+
+	tag.Name = "merge"
+	jsonValue, _ := json.Marshal(m.MergeRequestDTO{TargetID: tag.ID, SourceIDs: []uuid.UUID{uuid.New()}})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/tag/merge?preview=maybe", bytes.NewBuffer(jsonValue))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Merge(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid preview parameter"}`, string(body))
+}
+
+func TestTagMerge_NotFoundErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTagHandlers(&TagServiceMock{}, &m.LoggerInterfaceMock{})
+
+	tag.Name = "notfound"
+	jsonValue, _ := json.Marshal(m.MergeRequestDTO{TargetID: tag.ID, SourceIDs: []uuid.UUID{uuid.New()}})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/tag/merge", bytes.NewBuffer(jsonValue))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Merge(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"source not found"}`, string(body))
+}
+
+func TestTagLookup_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTagHandlers(&TagServiceMock{}, &m.LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/tag/lookup?name=Vegetables", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Lookup(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(tag)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestTagLookup_NameRequiredErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTagHandlers(&TagServiceMock{}, &m.LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/tag/lookup", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Lookup(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"name is required"}`, string(body))
+}
+
+func TestTagLookup_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTagHandlers(&TagServiceMock{}, &m.LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/tag/lookup?name=unknown", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Lookup(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"tag not found"}`, string(body))
+}
+
+func TestTagCreate_Existing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTagHandlers(&TagServiceMock{}, &m.LoggerInterfaceMock{})
+
+	reqBody, _ := json.Marshal(m.TagDTO{Name: "Vegetables"})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/tag", bytes.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(tag)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestTagCreate_AliasTakenErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTagHandlers(&TagServiceMock{}, &m.LoggerInterfaceMock{})
+
+	reqBody, _ := json.Marshal(m.TagDTO{Name: "taken"})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/tag", bytes.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, `{"error":"alias is already used by another tag"}`, string(body))
+}
+
+func TestTagCreate_NameUsedErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTagHandlers(&TagServiceMock{}, &m.LoggerInterfaceMock{})
+
+	reqBody, _ := json.Marshal(m.TagDTO{Name: "used"})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/tag", bytes.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, `{"error":"name is already used"}`, string(body))
+}
+
+func TestTagUpdate_ExistsErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTagHandlers(&TagServiceMock{}, &m.LoggerInterfaceMock{})
+
+	reqBody, _ := json.Marshal(m.TagDTO{Name: "taken"})
+
+	req := httptest.NewRequest("PUT", "http://example.com/api/v2/tag/1", bytes.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: tag.ID.String()},
+	}
+
+	h.Update(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, `{"error":"tag already exists"}`, string(body))
+}
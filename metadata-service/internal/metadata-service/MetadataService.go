@@ -40,6 +40,7 @@ func MetadataService(ctx context.Context) {
 			readTag.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
 			{
 				readTag.GET("", c.TagHandlers.GetAll)
+				readTag.GET("lookup", c.TagHandlers.Lookup)
 				readTag.GET(":id", c.TagHandlers.Get)
 			}
 
@@ -59,6 +60,7 @@ func MetadataService(ctx context.Context) {
 			deleteTag.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
 			{
 				deleteTag.DELETE(":id", c.TagHandlers.Delete)
+				deleteTag.POST("merge", c.TagHandlers.Merge)
 			}
 		}
 
@@ -69,6 +71,7 @@ func MetadataService(ctx context.Context) {
 			readCategory.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
 			{
 				readCategory.GET("", c.CategoryHandlers.GetAll)
+				readCategory.GET("lookup", c.CategoryHandlers.Lookup)
 				readCategory.GET(":id", c.CategoryHandlers.Get)
 			}
 
@@ -88,6 +91,7 @@ func MetadataService(ctx context.Context) {
 			deleteCategory.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
 			{
 				deleteCategory.DELETE(":id", c.CategoryHandlers.Delete)
+				deleteCategory.POST("merge", c.CategoryHandlers.Merge)
 			}
 		}
 
@@ -178,6 +182,31 @@ func MetadataService(ctx context.Context) {
 			}
 		}
 
+		// Recipe metadata routes
+		recipe := v1.Group("/recipe")
+		{
+			readRecipe := recipe.Group("")
+			readRecipe.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
+			{
+				readRecipe.GET("", c.RecipeMetadataHandlers.GetAll)
+				readRecipe.GET(":id", c.RecipeMetadataHandlers.Get)
+			}
+
+			updateRecipe := recipe.Group("")
+			updateRecipe.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
+			{
+				updateRecipe.PUT(":id", c.RecipeMetadataHandlers.Replace)
+				updateRecipe.POST(":id/restore", c.RecipeMetadataHandlers.Restore)
+				updateRecipe.POST(":id/purge", c.RecipeMetadataHandlers.Purge)
+			}
+
+			deleteRecipe := recipe.Group("")
+			deleteRecipe.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
+			{
+				deleteRecipe.DELETE(":id", c.RecipeMetadataHandlers.Delete)
+			}
+		}
+
 		// Search routes
 		search := v1.Group("/search")
 		{
@@ -187,8 +216,36 @@ func MetadataService(ctx context.Context) {
 				createSearch.POST("", c.SearchHandlers.SearchMetadata)
 			}
 		}
+
+		// Event routes
+		events := v1.Group("/events")
+		{
+			createEvent := events.Group("")
+			createEvent.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
+			{
+				createEvent.POST("", c.EventHandlers.Handle)
+			}
+		}
 	}
 
+	// Outbox relay
+	go c.Relay.Run(ctx, c.RelayInterval)
+
+	// Changes to recipes published to the event broker are consumed in the background
+	if c.EventSubscriber != nil {
+		go c.EventSubscriber.Run(ctx, c.EventHandlers.Consume)
+	}
+
+	// The recipes created before their changes were received are copied in the background
+	go func() {
+		synced, err := c.RecipeService.Sync(ctx)
+		if err != nil {
+			log.Warnf("unable to copy the recipes of the recipe service: %v", err)
+			return
+		}
+		log.Infof("copied %d recipes of the recipe service", synced)
+	}()
+
 	// Server startup
 	srv := &http.Server{
 		Handler:      router,
@@ -3,17 +3,22 @@ package models
 import (
 	"time"
 
+	"cookbook/pkg/normalize"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 // Database model
 type Category struct {
-	ID        uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	Name      string         `gorm:"type:varchar(100);unique;not null"`
-	CreatedAt time.Time      `gorm:"autoCreateTime"`
-	UpdatedAt time.Time      `gorm:"autoUpdateTime"`
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	ID             uuid.UUID       `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Name           string          `gorm:"type:varchar(100);unique;not null"`
+	NormalizedName string          `gorm:"type:varchar(100);not null;default:'';uniqueIndex:idx_categories_normalized_name_active,where:deleted_at IS NULL AND normalized_name <> ''"` // the name as matched, see normalize.Name, unique among the ones not deleted
+	Plural         string          `gorm:"type:varchar(100);not null;default:''"`
+	Aliases        []CategoryAlias `gorm:"foreignKey:CategoryID"`
+	CreatedAt      time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time       `gorm:"autoUpdateTime"`
+	DeletedAt      gorm.DeletedAt  `gorm:"index"`
 }
 
 func (category *Category) BeforeCreate(tx *gorm.DB) (err error) {
@@ -29,17 +34,43 @@ type RecipeCategory struct {
 	DeletedAt  gorm.DeletedAt `gorm:"index"`
 }
 
+// Alias model, another name a category is known by, such as the name of a duplicate merged into it. As it is keyed
+// by the normalized name, a name can point to one category only.
+type CategoryAlias struct {
+	NormalizedName string    `gorm:"type:varchar(100);primaryKey"`
+	Name           string    `gorm:"type:varchar(100);not null"`
+	CategoryID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+}
+
+func NewCategoryAlias(name string, categoryID uuid.UUID) CategoryAlias {
+	return CategoryAlias{
+		NormalizedName: normalize.Name(name),
+		Name:           name,
+		CategoryID:     categoryID,
+	}
+}
+
 // DTO model
 type CategoryDTO struct {
-	ID   uuid.UUID `json:"id,omitempty" binding:"uuid"` // ID can be omitted for create operations
-	Name string    `json:"name" binding:"required,min=1,max=255"`
+	ID      uuid.UUID `json:"id,omitempty" binding:"uuid"` // ID can be omitted for create operations
+	Name    string    `json:"name" binding:"required,min=1,max=255"`
+	Plural  string    `json:"plural,omitempty" binding:"max=255"`
+	Aliases []string  `json:"aliases,omitempty"` // on update the aliases are kept when omitted, and replaced otherwise
 }
 
 func (c Category) ConvertToDTO() CategoryDTO {
-	return CategoryDTO{
-		ID:   c.ID,
-		Name: c.Name,
+	dto := CategoryDTO{
+		ID:     c.ID,
+		Name:   c.Name,
+		Plural: c.Plural,
+	}
+
+	for _, alias := range c.Aliases {
+		dto.Aliases = append(dto.Aliases, alias.Name)
 	}
+
+	return dto
 }
 
 func (c Category) ConvertAllToDTO(categories []Category) []CategoryDTO {
@@ -52,11 +83,32 @@ func (c Category) ConvertAllToDTO(categories []Category) []CategoryDTO {
 	return data
 }
 
+// ConvertFromDTO converts the DTO, leaving out the aliases that match the name or another alias
 func (c CategoryDTO) ConvertFromDTO() Category {
-	return Category{
-		ID:   c.ID,
-		Name: c.Name,
+	category := Category{
+		ID:             c.ID,
+		Name:           c.Name,
+		NormalizedName: normalize.Name(c.Name),
+		Plural:         c.Plural,
+	}
+
+	if c.Aliases == nil {
+		return category
 	}
+
+	category.Aliases = []CategoryAlias{}
+	seen := map[string]bool{category.NormalizedName: true}
+	for _, name := range c.Aliases {
+		alias := NewCategoryAlias(name, c.ID)
+		if alias.NormalizedName == "" || seen[alias.NormalizedName] {
+			continue
+		}
+
+		seen[alias.NormalizedName] = true
+		category.Aliases = append(category.Aliases, alias)
+	}
+
+	return category
 }
 
 func (t CategoryDTO) ConvertAllFromDTO(categories []CategoryDTO) []Category {
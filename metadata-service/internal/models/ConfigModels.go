@@ -5,6 +5,8 @@ type Config struct {
 	Cors     CorsConfig
 	Oauth    OauthConfig
 	Database DatabaseConfig
+	Events   EventsConfig
+	Services ServicesConfig
 }
 
 // GlobalConfig holds global configuration items
@@ -12,6 +14,28 @@ type GlobalConfig struct {
 	LogLevel string
 }
 
+// EventsConfig holds the broker the changes are published to. The http broker posts them to the subscribers,
+// authenticated with the client credentials. With nats the changes of the recipe service are consumed from the
+// broker as well, otherwise they are received on the events endpoint
+type EventsConfig struct {
+	Broker       string   // nats or http, defaults to http
+	NatsUrl      string   // e.g. nats://nats:4222
+	Subscribers  []string // urls the events are posted to, e.g. http://search-service:8080/api/v2/search/events
+	Timeout      int      // in seconds
+	ClientID     string
+	ClientSecret string
+	Interval     int // in milliseconds, between the runs of the outbox relay
+	BatchSize    int // number of events the outbox relay publishes per run
+}
+
+// ServicesConfig holds the location of the recipe service and the client credentials used to read recipes from it
+type ServicesConfig struct {
+	RecipeServiceUrl string
+	Timeout          int // in seconds
+	ClientID         string
+	ClientSecret     string
+}
+
 // DatabaseConfig holds database configuration items
 type DatabaseConfig struct {
 	Host     string
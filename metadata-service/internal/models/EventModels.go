@@ -0,0 +1,50 @@
+package models
+
+import (
+	"cookbook/pkg/outbox"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The kinds of changes that are reported
+const (
+	EventCreated = "created"
+	EventUpdated = "updated"
+	EventDeleted = "deleted"
+)
+
+// The entities this service reports changes on. The metadata of a recipe
+// is reported as a whole, by the ID of the recipe.
+const (
+	EntityTag             = "tag"
+	EntityCategory        = "category"
+	EntityCuisineType     = "cuisine_type"
+	EntityDifficultyLevel = "difficulty_level"
+	EntityPreparationTime = "preparation_time"
+	EntityRecipeMetadata  = "recipe_metadata"
+)
+
+// The entities of the other services this service keeps track of
+const (
+	EntityRecipe = "recipe"
+)
+
+// ChangeEvent tells other services, such as the search service, that an entity was created, updated or deleted
+type ChangeEvent = outbox.ChangeEvent
+
+func NewChangeEvent(eventType string, entityType string, entityID uuid.UUID) ChangeEvent {
+	return outbox.NewChangeEvent(eventType, entityType, entityID)
+}
+
+// ChangeEventDTO is sent by another service after it created, updated or deleted one of its entities
+type ChangeEventDTO struct {
+	Type       string    `json:"type" binding:"required"`
+	EntityType string    `json:"entity_type" binding:"required"`
+	EntityID   uuid.UUID `json:"entity_id" binding:"required"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// CloudEventDTO is a change event as the other services publish it to the event broker. The data holds the
+// ChangeEventDTO.
+type CloudEventDTO = outbox.CloudEvent
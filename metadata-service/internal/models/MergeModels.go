@@ -0,0 +1,44 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// MergeRequestDTO asks to fold duplicates, e.g. of a tag, into the one that is kept
+type MergeRequestDTO struct {
+	TargetID  uuid.UUID   `json:"target_id" binding:"required" example:"23582396-12a3-425b-a597-8a22052823da"`
+	SourceIDs []uuid.UUID `json:"source_ids" binding:"required,min=1"`
+}
+
+// MergeResult describes a merge, or what a merge would do when previewed
+type MergeResult struct {
+	TargetID  uuid.UUID
+	SourceIDs []uuid.UUID
+	Aliases   []string    // the names of the sources, kept as aliases of the target
+	RecipeIDs []uuid.UUID // the recipes whose associations are moved to the target
+}
+
+func (r MergeResult) ConvertToDTO(preview bool) MergeResultDTO {
+	dto := MergeResultDTO{
+		TargetID:  r.TargetID,
+		Merged:    []uuid.UUID{},
+		Aliases:   []string{},
+		RecipeIDs: []uuid.UUID{},
+		Preview:   preview,
+	}
+
+	dto.Merged = append(dto.Merged, r.SourceIDs...)
+	dto.Aliases = append(dto.Aliases, r.Aliases...)
+	dto.RecipeIDs = append(dto.RecipeIDs, r.RecipeIDs...)
+
+	return dto
+}
+
+// MergeResultDTO reports a merge. With preview set nothing was changed yet.
+type MergeResultDTO struct {
+	TargetID  uuid.UUID   `json:"target_id" example:"23582396-12a3-425b-a597-8a22052823da"`
+	Merged    []uuid.UUID `json:"merged"`
+	Aliases   []string    `json:"aliases" example:"veggie"`
+	RecipeIDs []uuid.UUID `json:"recipe_ids"`
+	Preview   bool        `json:"preview" example:"true"`
+}
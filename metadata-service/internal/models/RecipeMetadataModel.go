@@ -3,11 +3,80 @@ package models
 import "github.com/google/uuid"
 
 // This is the combination model for all metadata associated to a recipe
+type RecipeMetadata struct {
+	RecipeID        uuid.UUID
+	Categories      []Category
+	Tags            []Tag
+	CuisineType     CuisineType
+	DifficultyLevel DifficultyLevel
+	PreparationTime PreparationTime
+}
+
 type RecipeMetadataDTO struct {
-	RecipeID        uuid.UUID `json:"recipe_id"`
-	CategoryName    string    `json:"category_name"`
-	CuisineTypeName string    `json:"cuisine_type_name"`
-	Tags            []string  `json:"tags"`
-	DifficultyLevel int       `json:"difficulty_level"`
-	PreparationTime int       `json:"preparation_time"`
+	RecipeID        uuid.UUID          `json:"recipe_id"`
+	Categories      []CategoryDTO      `json:"categories"`
+	Tags            []TagDTO           `json:"tags"`
+	CuisineType     CuisineTypeDTO     `json:"cuisine_type"`
+	DifficultyLevel DifficultyLevelDTO `json:"difficulty_level"`
+	PreparationTime PreparationTimeDTO `json:"preparation_time"`
+}
+
+func (r RecipeMetadata) ConvertToDTO() RecipeMetadataDTO {
+	dto := RecipeMetadataDTO{
+		RecipeID:        r.RecipeID,
+		Categories:      Category{}.ConvertAllToDTO(r.Categories),
+		Tags:            Tag{}.ConvertAllToDTO(r.Tags),
+		CuisineType:     r.CuisineType.ConvertToDTO(),
+		DifficultyLevel: r.DifficultyLevel.ConvertToDTO(),
+		PreparationTime: r.PreparationTime.ConvertToDTO(),
+	}
+
+	// always return lists, also when a recipe has no tags
+	if dto.Categories == nil {
+		dto.Categories = []CategoryDTO{}
+	}
+	if dto.Tags == nil {
+		dto.Tags = []TagDTO{}
+	}
+
+	return dto
+}
+
+func (r RecipeMetadata) ConvertAllToDTO(recipeMetadata []RecipeMetadata) []RecipeMetadataDTO {
+	var data []RecipeMetadataDTO
+
+	for _, metadata := range recipeMetadata {
+		data = append(data, metadata.ConvertToDTO())
+	}
+
+	return data
+}
+
+// Request model used to assign metadata to a recipe. A recipe has at least one category,
+// any number of tags and exactly one cuisine type, difficulty level and preparation time.
+type RecipeMetadataRequestDTO struct {
+	CategoryIDs       []uuid.UUID `json:"category_ids"`
+	TagIDs            []uuid.UUID `json:"tag_ids"`
+	CuisineTypeID     uuid.UUID   `json:"cuisine_type_id"`
+	DifficultyLevelID uuid.UUID   `json:"difficulty_level_id"`
+	PreparationTimeID uuid.UUID   `json:"preparation_time_id"`
+}
+
+func (r RecipeMetadataRequestDTO) ConvertFromDTO(recipeID uuid.UUID) RecipeMetadata {
+	metadata := RecipeMetadata{
+		RecipeID:        recipeID,
+		CuisineType:     CuisineType{ID: r.CuisineTypeID},
+		DifficultyLevel: DifficultyLevel{ID: r.DifficultyLevelID},
+		PreparationTime: PreparationTime{ID: r.PreparationTimeID},
+	}
+
+	for _, categoryID := range r.CategoryIDs {
+		metadata.Categories = append(metadata.Categories, Category{ID: categoryID})
+	}
+
+	for _, tagID := range r.TagIDs {
+		metadata.Tags = append(metadata.Tags, Tag{ID: tagID})
+	}
+
+	return metadata
 }
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Recipe is the copy of a recipe of the recipe service the search results are sorted on. It is kept up to date
+// with the changes the recipe service reports.
+type Recipe struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;index:idx_recipes_name,priority:2;index:idx_recipes_created_at,priority:2"`
+	Name      string    `gorm:"not null;index:idx_recipes_name,priority:1"`
+	CreatedAt time.Time `gorm:"autoCreateTime:false;not null;index:idx_recipes_created_at,priority:1"` // when the recipe was created at the recipe service
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// RecipeDTO is a recipe as the recipe service returns it
+type RecipeDTO struct {
+	ID        uuid.UUID
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (r RecipeDTO) ConvertFromDTO() Recipe {
+	return Recipe{
+		ID:        r.ID,
+		Name:      r.Name,
+		CreatedAt: r.CreatedAt,
+	}
+}
@@ -1,38 +1,82 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sort orders of the search results
+const (
+	SearchSortNewest   = "newest"    // most recently created recipes first
+	SearchSortPrepTime = "prep_time" // shortest preparation time first
+	SearchSortName     = "name"      // by recipe name, from the copy of the recipes kept by the recipe events
+)
+
+// how recipes have to match a list of tags
+const (
+	TagModeAll = "all"
+	TagModeAny = "any"
+)
 
 // request
 type MetadataSearchRequest struct {
-	RecipeID          *uuid.UUID `json:"recipe_id,omitempty"`
-	CategoryID        *uuid.UUID `json:"category_id,omitempty"`
-	TagID             *uuid.UUID `json:"tag_id,omitempty"`
-	DifficultyLevelID *uuid.UUID `json:"difficulty_level_id,omitempty"`
-	CuisineTypeID     *uuid.UUID `json:"cuisine_type_id,omitempty"`
-	MinPrepTime       *int       `json:"min_prep_time,omitempty"` // in minutes
-	MaxPrepTime       *int       `json:"max_prep_time,omitempty"` // in minutes
+	CategoryIDs       []uuid.UUID // recipes in any of the categories
+	TagIDs            []uuid.UUID // recipes with all or any of the tags, see TagMode
+	TagMode           string
+	CuisineTypeIDs    []uuid.UUID // recipes of any of the cuisine types
+	DifficultyLevelID *uuid.UUID
+	MinPrepTime       *int // in minutes
+	MaxPrepTime       *int // in minutes
+	Sort              string
+	Limit             int
+	Cursor            *MetadataSearchCursor // position after which the page starts, nil for the first page
 }
 
 type MetadataSearchRequestDTO struct {
-	RecipeID          uuid.UUID `json:"recipe_id,omitempty"`
-	CategoryID        uuid.UUID `json:"category_id,omitempty"`
-	TagID             uuid.UUID `json:"tag_id,omitempty"`
-	DifficultyLevelID uuid.UUID `json:"difficulty_level,omitempty"`
-	CuisineTypeID     uuid.UUID `json:"cuisine_type,omitempty"`
-	MinPrepTime       *int      `json:"min_prep_time,omitempty"` // in minutes
-	MaxPrepTime       *int      `json:"max_prep_time,omitempty"` // in minutes
-}
-
-func (s MetadataSearchRequestDTO) ConvertFromDTO() MetadataSearchRequest {
-	return MetadataSearchRequest{
-		RecipeID:          &s.RecipeID,
-		CategoryID:        &s.CategoryID,
-		TagID:             &s.TagID,
-		DifficultyLevelID: &s.DifficultyLevelID,
-		CuisineTypeID:     &s.CuisineTypeID,
-		MinPrepTime:       s.MinPrepTime,
-		MaxPrepTime:       s.MaxPrepTime,
+	CategoryIDs       []uuid.UUID `json:"category_ids,omitempty"`
+	TagIDs            []uuid.UUID `json:"tag_ids,omitempty"`
+	TagMode           string      `json:"tag_mode,omitempty" binding:"omitempty,oneof=all any"`
+	CuisineTypeIDs    []uuid.UUID `json:"cuisine_type_ids,omitempty"`
+	DifficultyLevelID *uuid.UUID  `json:"difficulty_level_id,omitempty"`
+	MinPrepTime       *int        `json:"min_prep_time,omitempty"` // in minutes
+	MaxPrepTime       *int        `json:"max_prep_time,omitempty"` // in minutes
+	Sort              string      `json:"sort,omitempty" binding:"omitempty,oneof=newest prep_time name"`
+	Limit             int         `json:"limit,omitempty" binding:"omitempty,min=1,max=100"`
+	Cursor            string      `json:"cursor,omitempty"`
+}
+
+// MetadataSearchCursor marks the last recipe of a page by the values it was sorted on
+type MetadataSearchCursor struct {
+	Sort      string    `json:"sort"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	Duration  int       `json:"duration,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	RecipeID  uuid.UUID `json:"recipe_id"`
+}
+
+// Encode turns the cursor into an opaque string clients pass back to get the next page
+func (c MetadataSearchCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeMetadataSearchCursor(cursor string) (MetadataSearchCursor, error) {
+	var c MetadataSearchCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return MetadataSearchCursor{}, errors.New("invalid cursor")
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil || c.RecipeID == uuid.Nil {
+		return MetadataSearchCursor{}, errors.New("invalid cursor")
 	}
+
+	return c, nil
 }
 
 // result
@@ -55,7 +99,7 @@ type MetadataSearchResultDTO struct {
 }
 
 func (s MetadataSearchResult) ConvertToDTO() MetadataSearchResultDTO {
-	return MetadataSearchResultDTO{
+	dto := MetadataSearchResultDTO{
 		RecipeID:          s.RecipeID,
 		CategoryIDs:       s.CategoryIDs,
 		TagIDs:            s.TagIDs,
@@ -63,10 +107,20 @@ func (s MetadataSearchResult) ConvertToDTO() MetadataSearchResultDTO {
 		PreparationTimeID: s.PreparationTimeID,
 		CuisineTypeID:     s.CuisineTypeID,
 	}
+
+	// always return lists, also when a recipe has no tags
+	if dto.CategoryIDs == nil {
+		dto.CategoryIDs = []uuid.UUID{}
+	}
+	if dto.TagIDs == nil {
+		dto.TagIDs = []uuid.UUID{}
+	}
+
+	return dto
 }
 
 func (s MetadataSearchResult) ConvertAllToDTO(searchResults []MetadataSearchResult) []MetadataSearchResultDTO {
-	var data []MetadataSearchResultDTO
+	data := []MetadataSearchResultDTO{}
 
 	for _, searchResult := range searchResults {
 		data = append(data, searchResult.ConvertToDTO())
@@ -74,3 +128,115 @@ func (s MetadataSearchResult) ConvertAllToDTO(searchResults []MetadataSearchResu
 
 	return data
 }
+
+// MetadataSearchPage is a single page of search results
+type MetadataSearchPage struct {
+	Results []MetadataSearchResult
+	Total   int64                 // number of matching recipes over all pages
+	Next    *MetadataSearchCursor // nil on the last page
+}
+
+// response
+type MetadataSearchResponseDTO struct {
+	Results    []MetadataSearchResultDTO `json:"results"`
+	Total      int64                     `json:"total"`
+	NextCursor string                    `json:"next_cursor,omitempty"`
+	Facets     MetadataFacetsDTO         `json:"facets"`
+}
+
+// facets
+type MetadataFacet struct {
+	ID    uuid.UUID
+	Name  string
+	Count int64
+}
+
+type MetadataFacetDTO struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Count int64     `json:"count"`
+}
+
+type PreparationTimeFacet struct {
+	Name        string
+	MinPrepTime int
+	MaxPrepTime *int // nil for the open ended last bucket
+	Count       int64
+}
+
+type PreparationTimeFacetDTO struct {
+	Name        string `json:"name"`
+	MinPrepTime int    `json:"min_prep_time"`           // in minutes
+	MaxPrepTime *int   `json:"max_prep_time,omitempty"` // in minutes
+	Count       int64  `json:"count"`
+}
+
+type MetadataFacets struct {
+	Categories       []MetadataFacet
+	Tags             []MetadataFacet
+	CuisineTypes     []MetadataFacet
+	DifficultyLevels []MetadataFacet
+	PreparationTimes []PreparationTimeFacet
+}
+
+type MetadataFacetsDTO struct {
+	Categories       []MetadataFacetDTO        `json:"categories"`
+	Tags             []MetadataFacetDTO        `json:"tags"`
+	CuisineTypes     []MetadataFacetDTO        `json:"cuisine_types"`
+	DifficultyLevels []MetadataFacetDTO        `json:"difficulty_levels"`
+	PreparationTimes []PreparationTimeFacetDTO `json:"preparation_times"`
+}
+
+// PreparationTimeBuckets are the ranges recipes are counted in for the preparation time facet, the last one is open ended
+var PreparationTimeBuckets = []PreparationTimeFacet{
+	{Name: "up to 15 min", MinPrepTime: 0, MaxPrepTime: intPointer(15)},
+	{Name: "16 to 30 min", MinPrepTime: 16, MaxPrepTime: intPointer(30)},
+	{Name: "31 to 60 min", MinPrepTime: 31, MaxPrepTime: intPointer(60)},
+	{Name: "over 60 min", MinPrepTime: 61},
+}
+
+func intPointer(i int) *int {
+	return &i
+}
+
+func (f MetadataFacet) ConvertToDTO() MetadataFacetDTO {
+	return MetadataFacetDTO{
+		ID:    f.ID,
+		Name:  f.Name,
+		Count: f.Count,
+	}
+}
+
+func (f MetadataFacet) ConvertAllToDTO(facets []MetadataFacet) []MetadataFacetDTO {
+	data := []MetadataFacetDTO{}
+
+	for _, facet := range facets {
+		data = append(data, facet.ConvertToDTO())
+	}
+
+	return data
+}
+
+func (f PreparationTimeFacet) ConvertToDTO() PreparationTimeFacetDTO {
+	return PreparationTimeFacetDTO{
+		Name:        f.Name,
+		MinPrepTime: f.MinPrepTime,
+		MaxPrepTime: f.MaxPrepTime,
+		Count:       f.Count,
+	}
+}
+
+func (f MetadataFacets) ConvertToDTO() MetadataFacetsDTO {
+	preparationTimes := []PreparationTimeFacetDTO{}
+	for _, facet := range f.PreparationTimes {
+		preparationTimes = append(preparationTimes, facet.ConvertToDTO())
+	}
+
+	return MetadataFacetsDTO{
+		Categories:       MetadataFacet{}.ConvertAllToDTO(f.Categories),
+		Tags:             MetadataFacet{}.ConvertAllToDTO(f.Tags),
+		CuisineTypes:     MetadataFacet{}.ConvertAllToDTO(f.CuisineTypes),
+		DifficultyLevels: MetadataFacet{}.ConvertAllToDTO(f.DifficultyLevels),
+		PreparationTimes: preparationTimes,
+	}
+}
@@ -3,17 +3,22 @@ package models
 import (
 	"time"
 
+	"cookbook/pkg/normalize"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 // Database model
 type Tag struct {
-	ID        uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	Name      string         `gorm:"type:varchar(100);unique;not null"`
-	CreatedAt time.Time      `gorm:"autoCreateTime"`
-	UpdatedAt time.Time      `gorm:"autoUpdateTime"`
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	ID             uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Name           string         `gorm:"type:varchar(100);unique;not null"`
+	NormalizedName string         `gorm:"type:varchar(100);not null;default:'';uniqueIndex:idx_tags_normalized_name_active,where:deleted_at IS NULL AND normalized_name <> ''"` // the name as matched, see normalize.Name, unique among the ones not deleted
+	Plural         string         `gorm:"type:varchar(100);not null;default:''"`
+	Aliases        []TagAlias     `gorm:"foreignKey:TagID"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
 }
 
 func (tag *Tag) BeforeCreate(tx *gorm.DB) (err error) {
@@ -29,17 +34,43 @@ type RecipeTag struct {
 	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
+// Alias model, another name a tag is known by, such as the name of a duplicate merged into it. As it is keyed
+// by the normalized name, a name can point to one tag only.
+type TagAlias struct {
+	NormalizedName string    `gorm:"type:varchar(100);primaryKey"`
+	Name           string    `gorm:"type:varchar(100);not null"`
+	TagID          uuid.UUID `gorm:"type:uuid;not null;index"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+}
+
+func NewTagAlias(name string, tagID uuid.UUID) TagAlias {
+	return TagAlias{
+		NormalizedName: normalize.Name(name),
+		Name:           name,
+		TagID:          tagID,
+	}
+}
+
 // DTO model
 type TagDTO struct {
-	ID   uuid.UUID `json:"id,omitempty" binding:"uuid"` // ID can be omitted for create operations
-	Name string    `json:"name" binding:"required,min=1,max=255"`
+	ID      uuid.UUID `json:"id,omitempty" binding:"uuid"` // ID can be omitted for create operations
+	Name    string    `json:"name" binding:"required,min=1,max=255"`
+	Plural  string    `json:"plural,omitempty" binding:"max=255"`
+	Aliases []string  `json:"aliases,omitempty"` // on update the aliases are kept when omitted, and replaced otherwise
 }
 
 func (t Tag) ConvertToDTO() TagDTO {
-	return TagDTO{
-		ID:   t.ID,
-		Name: t.Name,
+	dto := TagDTO{
+		ID:     t.ID,
+		Name:   t.Name,
+		Plural: t.Plural,
 	}
+
+	for _, alias := range t.Aliases {
+		dto.Aliases = append(dto.Aliases, alias.Name)
+	}
+
+	return dto
 }
 
 func (t Tag) ConvertAllToDTO(tags []Tag) []TagDTO {
@@ -52,11 +83,32 @@ func (t Tag) ConvertAllToDTO(tags []Tag) []TagDTO {
 	return data
 }
 
+// ConvertFromDTO converts the DTO, leaving out the aliases that match the name or another alias
 func (t TagDTO) ConvertFromDTO() Tag {
-	return Tag{
-		ID:   t.ID,
-		Name: t.Name,
+	tag := Tag{
+		ID:             t.ID,
+		Name:           t.Name,
+		NormalizedName: normalize.Name(t.Name),
+		Plural:         t.Plural,
+	}
+
+	if t.Aliases == nil {
+		return tag
 	}
+
+	tag.Aliases = []TagAlias{}
+	seen := map[string]bool{tag.NormalizedName: true}
+	for _, name := range t.Aliases {
+		alias := NewTagAlias(name, t.ID)
+		if alias.NormalizedName == "" || seen[alias.NormalizedName] {
+			continue
+		}
+
+		seen[alias.NormalizedName] = true
+		tag.Aliases = append(tag.Aliases, alias)
+	}
+
+	return tag
 }
 
 func (t TagDTO) ConvertAllFromDTO(tags []TagDTO) []Tag {
@@ -3,9 +3,14 @@ package repositories
 import (
 	"errors"
 
+	"cookbook/pkg/normalize"
+	"cookbook/pkg/outbox"
 	m "metadata-service/internal/models"
+	"metadata-service/internal/repositories/references"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CategoryRepository struct {
@@ -21,7 +26,7 @@ func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
 func (r *CategoryRepository) FindAll() ([]m.Category, error) {
 	var categories []m.Category
 
-	if err := r.db.Find(&categories).Error; err != nil {
+	if err := r.db.Preload("Aliases", orderAliases).Find(&categories).Error; err != nil {
 		return nil, err
 	}
 
@@ -34,7 +39,7 @@ func (r *CategoryRepository) FindAll() ([]m.Category, error) {
 
 func (r *CategoryRepository) FindSingle(category m.Category) (m.Category, error) {
 
-	result := r.db.First(&category)
+	result := r.db.Preload("Aliases", orderAliases).First(&category)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return m.Category{}, errors.New("not found")
@@ -46,49 +51,299 @@ func (r *CategoryRepository) FindSingle(category m.Category) (m.Category, error)
 	return category, nil
 }
 
+// FindByName finds the category a name refers to, by its normalized name or one of its aliases
+func (r *CategoryRepository) FindByName(name string) (m.Category, error) {
+	var category m.Category
+
+	normalized := normalize.Name(name)
+
+	result := r.db.Preload("Aliases", orderAliases).
+		Where("normalized_name = ?", normalized).
+		Or("id IN (?)", r.db.Model(&m.CategoryAlias{}).Select("category_id").Where("normalized_name = ?", normalized)).
+		First(&category)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return m.Category{}, errors.New("not found")
+		} else {
+			return m.Category{}, result.Error
+		}
+	}
+
+	return category, nil
+}
+
+// Create stores a category with its aliases. An "already exists" error is returned when the name or an alias is
+// taken by another category.
 func (r *CategoryRepository) Create(category m.Category) (m.Category, error) {
 
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
 		var err error
 
-		if err = tx.Create(&category).Error; err != nil {
+		if err = tx.Omit("Aliases").Create(&category).Error; err != nil {
 			return err
 		}
 
-		return nil
-	}); err != nil {
+		if err = createCategoryAliases(tx, &category); err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventCreated, m.EntityCategory, category.ID))
+	}); errors.Is(err, gorm.ErrDuplicatedKey) {
+		return m.Category{}, errors.New("already exists")
+	} else if err != nil {
 		return m.Category{}, err
 	}
 
 	return category, nil
 }
 
+// Update stores the changes to a category, replacing its aliases when they are given. An "already exists" error is
+// returned when the name or an alias is taken by another category.
 func (r *CategoryRepository) Update(category m.Category) (m.Category, error) {
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
 		var err error
 
-		if err = tx.Updates(&category).Error; err != nil {
+		// plural is selected explicitly, as Updates would skip it when it is reset
+		if err = tx.Select("name", "normalized_name", "plural").Updates(&category).Error; err != nil {
 			return err
 		}
-		return nil
-	}); err != nil {
+
+		// without aliases given the ones the category has are kept
+		if category.Aliases != nil {
+			if err = tx.Where("category_id = ?", category.ID).Delete(&m.CategoryAlias{}).Error; err != nil {
+				return err
+			}
+
+			if err = createCategoryAliases(tx, &category); err != nil {
+				return err
+			}
+		}
+		return outbox.Record(tx, m.NewChangeEvent(m.EventUpdated, m.EntityCategory, category.ID))
+	}); errors.Is(err, gorm.ErrDuplicatedKey) {
+		return m.Category{}, errors.New("already exists")
+	} else if err != nil {
 		return m.Category{}, err
 	}
 
 	return category, nil
 }
 
+// Delete removes a category that is not used by any recipe, otherwise an InUseError is returned. Its aliases are
+// removed as well, so the names can be used again.
 func (r *CategoryRepository) Delete(category m.Category) error {
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
 
+		usage, err := references.Usage(tx, &m.RecipeCategory{}, "category_id", category.ID)
+		if err != nil {
+			return err
+		}
+
+		if usage.InUse() {
+			return m.NewInUseError("category", usage)
+		}
+
+		if err := tx.Where("category_id = ?", category.ID).Delete(&m.CategoryAlias{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&category).Error; err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventDeleted, m.EntityCategory, category.ID))
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reassign moves the recipes associated with a category to the replacement and removes the category and its aliases
+// afterwards
+func (r *CategoryRepository) Reassign(category m.Category, replacement m.Category) error {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.First(&replacement).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("replacement not found")
+			}
+			return err
+		}
+
+		recipeIDs, err := references.Reassign(tx, &m.RecipeCategory{}, "category_id", category.ID, replacement.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("category_id = ?", category.ID).Delete(&m.CategoryAlias{}).Error; err != nil {
+			return err
+		}
+
 		if err := tx.Delete(&category).Error; err != nil {
 			return err
 		}
 
-		return nil
+		events := []m.ChangeEvent{m.NewChangeEvent(m.EventDeleted, m.EntityCategory, category.ID)}
+		for _, recipeID := range recipeIDs {
+			events = append(events, m.NewChangeEvent(m.EventUpdated, m.EntityRecipeMetadata, recipeID))
+		}
+
+		return outbox.Record(tx, events...)
 	}); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// Merge folds duplicate categories into the target. The recipes associated with the sources are moved to the target,
+// their names and aliases become aliases of the target and the sources are removed, all at once. With preview
+// nothing is changed, the result only tells what the merge would do.
+func (r *CategoryRepository) Merge(target m.Category, sources []m.Category, preview bool) (m.MergeResult, error) {
+	var result m.MergeResult
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.First(&target).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("target not found")
+			}
+			return err
+		}
+
+		var sourceIDs []uuid.UUID
+		for _, source := range sources {
+			sourceIDs = append(sourceIDs, source.ID)
+		}
+
+		sources = nil
+		if err := tx.Where("id IN ?", sourceIDs).Order("name").Find(&sources).Error; err != nil {
+			return err
+		}
+
+		if len(sources) != len(sourceIDs) {
+			return errors.New("source not found")
+		}
+
+		result = m.MergeResult{TargetID: target.ID}
+		for _, source := range sources {
+			result.SourceIDs = append(result.SourceIDs, source.ID)
+			result.Aliases = append(result.Aliases, source.Name)
+		}
+
+		seen := map[uuid.UUID]bool{}
+		for _, source := range sources {
+			recipeIDs, err := references.Recipes(tx, &m.RecipeCategory{}, "category_id", source.ID)
+			if err != nil {
+				return err
+			}
+
+			for _, recipeID := range recipeIDs {
+				if !seen[recipeID] {
+					seen[recipeID] = true
+					result.RecipeIDs = append(result.RecipeIDs, recipeID)
+				}
+			}
+		}
+
+		if preview {
+			return nil
+		}
+
+		events := []m.ChangeEvent{m.NewChangeEvent(m.EventUpdated, m.EntityCategory, target.ID)}
+		for _, source := range sources {
+			if _, err := references.Reassign(tx, &m.RecipeCategory{}, "category_id", source.ID, target.ID); err != nil {
+				return err
+			}
+
+			if err := tx.Model(&m.CategoryAlias{}).Where("category_id = ?", source.ID).Update("category_id", target.ID).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Delete(&source).Error; err != nil {
+				return err
+			}
+
+			events = append(events, m.NewChangeEvent(m.EventDeleted, m.EntityCategory, source.ID))
+		}
+
+		var aliases []m.CategoryAlias
+		for _, name := range result.Aliases {
+			if alias := m.NewCategoryAlias(name, target.ID); alias.NormalizedName != target.NormalizedName {
+				aliases = append(aliases, alias)
+			}
+		}
+
+		// a name that already is an alias keeps pointing where it does
+		if len(aliases) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&aliases).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, recipeID := range result.RecipeIDs {
+			events = append(events, m.NewChangeEvent(m.EventUpdated, m.EntityRecipeMetadata, recipeID))
+		}
+
+		return outbox.Record(tx, events...)
+	}); err != nil {
+		return m.MergeResult{}, err
+	}
+
+	return result, nil
+}
+
+// NormalizeNames sets the normalized name of the categories stored before names were normalized. A category whose
+// normalized name is already taken is left as it is to be merged.
+func (r *CategoryRepository) NormalizeNames() (int, error) {
+	var categories []m.Category
+
+	if err := r.db.Unscoped().Where("normalized_name = ''").Find(&categories).Error; err != nil {
+		return 0, err
+	}
+
+	normalized := 0
+	for _, category := range categories {
+		err := r.db.Unscoped().Model(&category).UpdateColumn("normalized_name", normalize.Name(category.Name)).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+
+		normalized++
+	}
+
+	return normalized, nil
+}
+
+// createCategoryAliases stores the aliases of a category. An alias is keyed by its normalized name, so names that
+// normalize to the name of the category or to an earlier alias are stored only once.
+func createCategoryAliases(tx *gorm.DB, category *m.Category) error {
+	if len(category.Aliases) == 0 {
+		return nil
+	}
+
+	aliases := []m.CategoryAlias{}
+	seen := map[string]bool{normalize.Name(category.Name): true}
+	for _, alias := range category.Aliases {
+		alias = m.NewCategoryAlias(alias.Name, category.ID)
+		if alias.NormalizedName == "" || seen[alias.NormalizedName] {
+			continue
+		}
+
+		seen[alias.NormalizedName] = true
+		aliases = append(aliases, alias)
+	}
+
+	category.Aliases = aliases
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	return tx.Create(&category.Aliases).Error
+}
+
+func orderAliases(db *gorm.DB) *gorm.DB {
+	return db.Order("name")
+}
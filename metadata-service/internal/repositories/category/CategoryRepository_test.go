@@ -9,18 +9,31 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 
 	co "metadata-service/internal/common/test"
 )
 
 var (
 	category m.Category = m.Category{
-		ID:   uuid.New(),
-		Name: "category",
+		ID:             uuid.New(),
+		Name:           "category",
+		NormalizedName: "category",
 	}
 )
 
+func expectCategoryAliases(mock sqlmock.Sqlmock, names ...string) {
+	rows := sqlmock.NewRows([]string{"normalized_name", "name", "category_id"})
+	for _, name := range names {
+		rows.AddRow(category.NormalizedName, name, category.ID)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "category_aliases" WHERE "category_aliases"."category_id" = $1 ORDER BY name`)).
+		WithArgs(category.ID).
+		WillReturnRows(rows)
+}
+
 // ========================================================================================================
 
 func TestCategoryFindAll_OK(t *testing.T) {
@@ -29,6 +42,7 @@ func TestCategoryFindAll_OK(t *testing.T) {
 
 	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE "categories"."deleted_at" IS NULL`)).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(category.ID, category.Name))
+	expectCategoryAliases(mock, "categories")
 
 	result, err := r.FindAll()
 
@@ -39,6 +53,8 @@ func TestCategoryFindAll_OK(t *testing.T) {
 		t.Errorf("expected category name %v, but got %v", category.Name, result[0].Name)
 	}
 
+	assert.Equal(t, "categories", result[0].Aliases[0].Name)
+
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %v", err)
 	}
@@ -68,6 +84,7 @@ func TestCategoryFindSingle_OK(t *testing.T) {
 			1,
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(category.ID, category.Name))
+	expectCategoryAliases(mock)
 
 	result, err := r.FindSingle(category)
 
@@ -98,14 +115,17 @@ func TestCategoryCreate_OK(t *testing.T) {
 	r := NewCategoryRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "categories" ("name","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "categories" ("name","normalized_name","plural","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
 		WithArgs(
 			category.Name,
+			category.NormalizedName,
+			category.Plural,
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
 		).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(category.ID, category.Name))
+	co.ExpectOutbox(mock, m.EntityCategory, category.ID, m.EventCreated)
 	mock.ExpectCommit()
 
 	result, err := r.Create(category)
@@ -114,6 +134,9 @@ func TestCategoryCreate_OK(t *testing.T) {
 	assert.IsType(t, m.Category{}, result)
 	assert.Equal(t, category.ID, result.ID)
 
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
 }
 
 func TestCategoryCreate_Err(t *testing.T) {
@@ -121,9 +144,11 @@ func TestCategoryCreate_Err(t *testing.T) {
 	r := NewCategoryRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "categories" ("name","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "categories" ("name","normalized_name","plural","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
 		WithArgs(
 			category.Name,
+			category.NormalizedName,
+			category.Plural,
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
@@ -139,17 +164,35 @@ func TestCategoryCreate_Err(t *testing.T) {
 
 }
 
+func TestCategoryCreate_Duplicate(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "categories" ("name","normalized_name","plural","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err := r.Create(category)
+
+	assert.EqualError(t, err, "already exists")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestCategoryUpdate_OK(t *testing.T) {
 	db, mock := co.NewMockDatabase(t)
 	r := NewCategoryRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "name"=$1,"updated_at"=$2 WHERE "categories"."deleted_at" IS NULL AND "id" = $3`)).
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "name"=$1,"normalized_name"=$2,"plural"=$3,"updated_at"=$4 WHERE "categories"."deleted_at" IS NULL AND "id" = $5`)).
 		WithArgs(
 			category.Name,
+			category.NormalizedName,
+			category.Plural,
 			sqlmock.AnyArg(),
 			category.ID,
 		).WillReturnResult(sqlmock.NewResult(1, 1))
+	co.ExpectOutbox(mock, m.EntityCategory, category.ID, m.EventUpdated)
 	mock.ExpectCommit()
 
 	result, err := r.Update(category)
@@ -158,6 +201,9 @@ func TestCategoryUpdate_OK(t *testing.T) {
 	assert.IsType(t, m.Category{}, result)
 	assert.Equal(t, category.ID, result.ID)
 
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
 }
 
 func TestCategoryUpdate_Err(t *testing.T) {
@@ -165,9 +211,11 @@ func TestCategoryUpdate_Err(t *testing.T) {
 	r := NewCategoryRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "name"=$1,"updated_at"=$2 WHERE "categories"."deleted_at" IS NULL AND "id" = $3`)).
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "name"=$1,"normalized_name"=$2,"plural"=$3,"updated_at"=$4 WHERE "categories"."deleted_at" IS NULL AND "id" = $5`)).
 		WithArgs(
 			category.Name,
+			category.NormalizedName,
+			category.Plural,
 			sqlmock.AnyArg(),
 			category.ID,
 		).WillReturnError(errors.New("error"))
@@ -181,21 +229,45 @@ func TestCategoryUpdate_Err(t *testing.T) {
 
 }
 
+func TestCategoryUpdate_Duplicate(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "name"=$1,"normalized_name"=$2,"plural"=$3,"updated_at"=$4 WHERE "categories"."deleted_at" IS NULL AND "id" = $5`)).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err := r.Update(category)
+
+	assert.EqualError(t, err, "already exists")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestCategoryDelete_OK(t *testing.T) {
 	db, mock := co.NewMockDatabase(t)
 	r := NewCategoryRepository(db)
 
 	mock.ExpectBegin()
+	co.ExpectUsage(mock, "recipe_categories", "category_id", category.ID, nil, nil)
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "category_aliases" WHERE category_id = $1`)).
+		WithArgs(category.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "deleted_at"=$1 WHERE "categories"."id" = $2 AND "categories"."deleted_at" IS NULL`)).
 		WithArgs(
 			sqlmock.AnyArg(),
 			category.ID,
 		).WillReturnResult(sqlmock.NewResult(1, 1))
+	co.ExpectOutbox(mock, m.EntityCategory, category.ID, m.EventDeleted)
 	mock.ExpectCommit()
 
 	err := r.Delete(category)
 
 	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
 }
 
 func TestCategoryDelete_Err(t *testing.T) {
@@ -203,6 +275,10 @@ func TestCategoryDelete_Err(t *testing.T) {
 	r := NewCategoryRepository(db)
 
 	mock.ExpectBegin()
+	co.ExpectUsage(mock, "recipe_categories", "category_id", category.ID, nil, nil)
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "category_aliases" WHERE category_id = $1`)).
+		WithArgs(category.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "deleted_at"=$1 WHERE "categories"."id" = $2 AND "categories"."deleted_at" IS NULL`)).
 		WithArgs(
 			sqlmock.AnyArg(),
@@ -216,3 +292,356 @@ func TestCategoryDelete_Err(t *testing.T) {
 	assert.EqualError(t, err, "error")
 
 }
+
+func TestCategoryDelete_InUseErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	co.ExpectUsage(mock, "recipe_categories", "category_id", category.ID, []uuid.UUID{recipeID}, nil)
+	mock.ExpectRollback()
+
+	err := r.Delete(category)
+
+	var inUse m.InUseError
+	assert.ErrorAs(t, err, &inUse)
+	assert.EqualError(t, err, "category is used by 1 recipes")
+	assert.Equal(t, []uuid.UUID{recipeID}, inUse.Usage.RecipeIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryReassign_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	replacement := m.Category{ID: uuid.New()}
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE "categories"."deleted_at" IS NULL AND "categories"."id" = $1 ORDER BY "categories"."id" LIMIT $2`)).
+		WithArgs(replacement.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(replacement.ID))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "recipe_categories" WHERE category_id = $1`)).
+		WithArgs(category.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(recipeID))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_categories" WHERE category_id = $1 AND recipe_id IN (SELECT "recipe_id" FROM "recipe_categories" WHERE category_id = $2)`)).
+		WithArgs(category.ID, replacement.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_categories" SET "category_id"=$1 WHERE category_id = $2`)).
+		WithArgs(replacement.ID, category.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "category_aliases" WHERE category_id = $1`)).
+		WithArgs(category.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "deleted_at"=$1 WHERE "categories"."id" = $2 AND "categories"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), category.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8),($9,$10,$11,$12,$13,$14,$15,$16) RETURNING "sequence"`)).
+		WithArgs(
+			sqlmock.AnyArg(), m.EntityCategory, category.ID, m.EventDeleted, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+			sqlmock.AnyArg(), m.EntityRecipeMetadata, recipeID, m.EventUpdated, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1).AddRow(2))
+	mock.ExpectCommit()
+
+	err := r.Reassign(category, replacement)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryReassign_ReplacementNotFoundErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	replacement := m.Category{ID: uuid.New()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE "categories"."deleted_at" IS NULL AND "categories"."id" = $1 ORDER BY "categories"."id" LIMIT $2`)).
+		WithArgs(replacement.ID, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectRollback()
+
+	err := r.Reassign(category, replacement)
+
+	assert.EqualError(t, err, "replacement not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func expectCategoryMergeLookup(mock sqlmock.Sqlmock, target m.Category, sources ...m.Category) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE "categories"."deleted_at" IS NULL AND "categories"."id" = $1 ORDER BY "categories"."id" LIMIT $2`)).
+		WithArgs(target.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(target.ID, target.Name))
+
+	rows := sqlmock.NewRows([]string{"id", "name"})
+	for _, source := range sources {
+		rows.AddRow(source.ID, source.Name)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE id IN ($1) AND "categories"."deleted_at" IS NULL ORDER BY name`)).
+		WithArgs(category.ID).
+		WillReturnRows(rows)
+}
+
+func TestCategoryMerge_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	target := m.Category{ID: uuid.New(), Name: "target"}
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	expectCategoryMergeLookup(mock, target, category)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "recipe_categories" WHERE category_id = $1`)).
+		WithArgs(category.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(recipeID))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "recipe_categories" WHERE category_id = $1`)).
+		WithArgs(category.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(recipeID))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_categories" WHERE category_id = $1 AND recipe_id IN (SELECT "recipe_id" FROM "recipe_categories" WHERE category_id = $2)`)).
+		WithArgs(category.ID, target.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_categories" SET "category_id"=$1 WHERE category_id = $2`)).
+		WithArgs(target.ID, category.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "category_aliases" SET "category_id"=$1 WHERE category_id = $2`)).
+		WithArgs(target.ID, category.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "deleted_at"=$1 WHERE "categories"."id" = $2 AND "categories"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), category.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "category_aliases" ("normalized_name","name","category_id","created_at") VALUES ($1,$2,$3,$4) ON CONFLICT DO NOTHING`)).
+		WithArgs(category.NormalizedName, category.Name, target.ID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8),($9,$10,$11,$12,$13,$14,$15,$16),($17,$18,$19,$20,$21,$22,$23,$24) RETURNING "sequence"`)).
+		WithArgs(
+			sqlmock.AnyArg(), m.EntityCategory, target.ID, m.EventUpdated, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+			sqlmock.AnyArg(), m.EntityCategory, category.ID, m.EventDeleted, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+			sqlmock.AnyArg(), m.EntityRecipeMetadata, recipeID, m.EventUpdated, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1).AddRow(2).AddRow(3))
+	mock.ExpectCommit()
+
+	result, err := r.Merge(target, []m.Category{{ID: category.ID}}, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, target.ID, result.TargetID)
+	assert.Equal(t, []uuid.UUID{category.ID}, result.SourceIDs)
+	assert.Equal(t, []string{category.Name}, result.Aliases)
+	assert.Equal(t, []uuid.UUID{recipeID}, result.RecipeIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryMerge_Preview(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	target := m.Category{ID: uuid.New(), Name: "target"}
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	expectCategoryMergeLookup(mock, target, category)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "recipe_categories" WHERE category_id = $1`)).
+		WithArgs(category.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(recipeID))
+	mock.ExpectCommit()
+
+	result, err := r.Merge(target, []m.Category{{ID: category.ID}}, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{category.Name}, result.Aliases)
+	assert.Equal(t, []uuid.UUID{recipeID}, result.RecipeIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryMerge_TargetNotFoundErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	target := m.Category{ID: uuid.New()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE "categories"."deleted_at" IS NULL AND "categories"."id" = $1 ORDER BY "categories"."id" LIMIT $2`)).
+		WithArgs(target.ID, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectRollback()
+
+	_, err := r.Merge(target, []m.Category{{ID: category.ID}}, false)
+
+	assert.EqualError(t, err, "target not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryMerge_SourceNotFoundErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	target := m.Category{ID: uuid.New(), Name: "target"}
+
+	mock.ExpectBegin()
+	expectCategoryMergeLookup(mock, target)
+	mock.ExpectRollback()
+
+	_, err := r.Merge(target, []m.Category{{ID: category.ID}}, false)
+
+	assert.EqualError(t, err, "source not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryFindByName_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE (normalized_name = $1 OR id IN (SELECT "category_id" FROM "category_aliases" WHERE normalized_name = $2)) AND "categories"."deleted_at" IS NULL ORDER BY "categories"."id" LIMIT $3`)).
+		WithArgs("category", "category", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "normalized_name"}).AddRow(category.ID, category.Name, category.NormalizedName))
+	expectCategoryAliases(mock)
+
+	result, err := r.FindByName(" Categorys")
+
+	assert.NoError(t, err)
+	assert.Equal(t, category.ID, result.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryFindByName_NotFoundErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE (normalized_name = $1 OR id IN (SELECT "category_id" FROM "category_aliases" WHERE normalized_name = $2)) AND "categories"."deleted_at" IS NULL ORDER BY "categories"."id" LIMIT $3`)).
+		WithArgs("vegetable", "vegetable", 1).
+		WillReturnRows(&sqlmock.Rows{})
+
+	_, err := r.FindByName("Vegetables")
+
+	assert.EqualError(t, err, "not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryCreate_Aliases(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	input := m.CategoryDTO{Name: "Vegetable", Plural: "vegetables", Aliases: []string{"Vegetables", "veggie"}}.ConvertFromDTO()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "categories" ("name","normalized_name","plural","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
+		WithArgs("Vegetable", "vegetable", "vegetables", sqlmock.AnyArg(), sqlmock.AnyArg(), nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(category.ID))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "category_aliases" ("normalized_name","name","category_id","created_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs("veggie", "veggie", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1))
+	mock.ExpectCommit()
+
+	result, err := r.Create(input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"veggie"}, result.ConvertToDTO().Aliases)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryCreate_DuplicateAliases(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	input := m.Category{Name: "Vegetable", NormalizedName: "vegetable", Aliases: []m.CategoryAlias{
+		m.NewCategoryAlias("Veggie", uuid.Nil),
+		m.NewCategoryAlias("veggie", uuid.Nil),
+		m.NewCategoryAlias("VEGETABLES", uuid.Nil),
+	}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "categories"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(category.ID))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "category_aliases" ("normalized_name","name","category_id","created_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs("veggie", "Veggie", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1))
+	mock.ExpectCommit()
+
+	result, err := r.Create(input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Veggie"}, result.ConvertToDTO().Aliases)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryUpdate_Aliases(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	input := category
+	input.Aliases = []m.CategoryAlias{m.NewCategoryAlias("label", category.ID)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "name"=$1,"normalized_name"=$2,"plural"=$3,"updated_at"=$4 WHERE "categories"."deleted_at" IS NULL AND "id" = $5`)).
+		WithArgs(category.Name, category.NormalizedName, category.Plural, sqlmock.AnyArg(), category.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "category_aliases" WHERE category_id = $1`)).
+		WithArgs(category.ID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "category_aliases" ("normalized_name","name","category_id","created_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs("label", "label", category.ID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	co.ExpectOutbox(mock, m.EntityCategory, category.ID, m.EventUpdated)
+	mock.ExpectCommit()
+
+	_, err := r.Update(input)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryNormalizeNames_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE normalized_name = ''`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(category.ID, "Vegetables"))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "normalized_name"=$1 WHERE "id" = $2`)).
+		WithArgs("vegetable", category.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	normalized, err := r.NormalizeNames()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, normalized)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryNormalizeNames_Duplicate(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE normalized_name = ''`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(category.ID, "Vegetables"))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "normalized_name"=$1 WHERE "id" = $2`)).
+		WithArgs("vegetable", category.ID).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	normalized, err := r.NormalizeNames()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, normalized)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryNormalizeNames_Err(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCategoryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE normalized_name = ''`)).
+		WillReturnError(errors.New("error"))
+
+	_, err := r.NormalizeNames()
+
+	assert.EqualError(t, err, "error")
+}
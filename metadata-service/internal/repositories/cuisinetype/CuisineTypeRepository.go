@@ -3,7 +3,9 @@ package repositories
 import (
 	"errors"
 
+	"cookbook/pkg/outbox"
 	m "metadata-service/internal/models"
+	"metadata-service/internal/repositories/references"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -56,7 +58,7 @@ func (r *CuisineTypeRepository) Create(cuisineType m.CuisineType) (m.CuisineType
 			return err
 		}
 
-		return nil
+		return outbox.Record(tx, m.NewChangeEvent(m.EventCreated, m.EntityCuisineType, cuisineType.ID))
 	}); err != nil {
 		return m.CuisineType{}, err
 	}
@@ -71,7 +73,7 @@ func (r *CuisineTypeRepository) Update(cuisineType m.CuisineType) (m.CuisineType
 		if err = tx.Updates(&cuisineType).Error; err != nil {
 			return err
 		}
-		return nil
+		return outbox.Record(tx, m.NewChangeEvent(m.EventUpdated, m.EntityCuisineType, cuisineType.ID))
 	}); err != nil {
 		return m.CuisineType{}, err
 	}
@@ -79,14 +81,57 @@ func (r *CuisineTypeRepository) Update(cuisineType m.CuisineType) (m.CuisineType
 	return cuisineType, nil
 }
 
+// Delete removes a cuisine type that is not used by any recipe, otherwise an InUseError is returned
 func (r *CuisineTypeRepository) Delete(cuisineType m.CuisineType) error {
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
 
+		usage, err := references.Usage(tx, &m.RecipeCuisineType{}, "cuisine_type_id", cuisineType.ID)
+		if err != nil {
+			return err
+		}
+
+		if usage.InUse() {
+			return m.NewInUseError("cuisine type", usage)
+		}
+
+		if err := tx.Delete(&cuisineType).Error; err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventDeleted, m.EntityCuisineType, cuisineType.ID))
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reassign moves the recipes associated with a cuisine type to the replacement and removes the cuisine type afterwards
+func (r *CuisineTypeRepository) Reassign(cuisineType m.CuisineType, replacement m.CuisineType) error {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.First(&replacement).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("replacement not found")
+			}
+			return err
+		}
+
+		recipeIDs, err := references.Reassign(tx, &m.RecipeCuisineType{}, "cuisine_type_id", cuisineType.ID, replacement.ID)
+		if err != nil {
+			return err
+		}
+
 		if err := tx.Delete(&cuisineType).Error; err != nil {
 			return err
 		}
 
-		return nil
+		events := []m.ChangeEvent{m.NewChangeEvent(m.EventDeleted, m.EntityCuisineType, cuisineType.ID)}
+		for _, recipeID := range recipeIDs {
+			events = append(events, m.NewChangeEvent(m.EventUpdated, m.EntityRecipeMetadata, recipeID))
+		}
+
+		return outbox.Record(tx, events...)
 	}); err != nil {
 		return err
 	}
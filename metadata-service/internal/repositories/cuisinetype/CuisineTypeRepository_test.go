@@ -10,6 +10,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 
 	co "metadata-service/internal/common/test"
 )
@@ -107,6 +108,7 @@ func TestCuisineTypeCreate_OK(t *testing.T) {
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "name"}).AddRow(cuisineType.ID, cuisineType.Name),
 		)
+	co.ExpectOutbox(mock, m.EntityCuisineType, cuisineType.ID, m.EventCreated)
 	mock.ExpectCommit()
 
 	result, err := r.Create(cuisineType)
@@ -114,6 +116,10 @@ func TestCuisineTypeCreate_OK(t *testing.T) {
 	assert.NoError(t, err)
 	assert.IsType(t, m.CuisineType{}, result)
 	assert.Equal(t, cuisineType.ID, result.ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
 }
 
 func TestCuisineTypeCreate_Err(t *testing.T) {
@@ -150,6 +156,7 @@ func TestCuisineTypeUpdate_OK(t *testing.T) {
 			sqlmock.AnyArg(),
 			cuisineType.ID,
 		).WillReturnResult(sqlmock.NewResult(1, 1))
+	co.ExpectOutbox(mock, m.EntityCuisineType, cuisineType.ID, m.EventUpdated)
 	mock.ExpectCommit()
 
 	result, err := r.Update(cuisineType)
@@ -158,6 +165,9 @@ func TestCuisineTypeUpdate_OK(t *testing.T) {
 	assert.IsType(t, m.CuisineType{}, result)
 	assert.Equal(t, cuisineType.ID, result.ID)
 
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
 }
 
 func TestCuisineTypeUpdate_Err(t *testing.T) {
@@ -186,16 +196,22 @@ func TestCuisineTypeDelete_OK(t *testing.T) {
 	r := NewCuisineTypeRepository(db)
 
 	mock.ExpectBegin()
+	co.ExpectUsage(mock, "recipe_cuisine_types", "cuisine_type_id", cuisineType.ID, nil, nil)
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "cuisine_types" SET "deleted_at"=$1 WHERE "cuisine_types"."id" = $2 AND "cuisine_types"."deleted_at" IS NULL`)).
 		WithArgs(
 			sqlmock.AnyArg(),
 			cuisineType.ID,
 		).WillReturnResult(sqlmock.NewResult(1, 1))
+	co.ExpectOutbox(mock, m.EntityCuisineType, cuisineType.ID, m.EventDeleted)
 	mock.ExpectCommit()
 
 	err := r.Delete(cuisineType)
 
 	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
 }
 
 func TestCuisineTypeDelete_Err(t *testing.T) {
@@ -203,6 +219,7 @@ func TestCuisineTypeDelete_Err(t *testing.T) {
 	r := NewCuisineTypeRepository(db)
 
 	mock.ExpectBegin()
+	co.ExpectUsage(mock, "recipe_cuisine_types", "cuisine_type_id", cuisineType.ID, nil, nil)
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "cuisine_types" SET "deleted_at"=$1 WHERE "cuisine_types"."id" = $2 AND "cuisine_types"."deleted_at" IS NULL`)).
 		WithArgs(
 			sqlmock.AnyArg(),
@@ -216,3 +233,77 @@ func TestCuisineTypeDelete_Err(t *testing.T) {
 	assert.EqualError(t, err, "error")
 
 }
+
+func TestCuisineTypeDelete_InUseErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCuisineTypeRepository(db)
+
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	co.ExpectUsage(mock, "recipe_cuisine_types", "cuisine_type_id", cuisineType.ID, []uuid.UUID{recipeID}, nil)
+	mock.ExpectRollback()
+
+	err := r.Delete(cuisineType)
+
+	var inUse m.InUseError
+	assert.ErrorAs(t, err, &inUse)
+	assert.EqualError(t, err, "cuisine type is used by 1 recipes")
+	assert.Equal(t, []uuid.UUID{recipeID}, inUse.Usage.RecipeIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCuisineTypeReassign_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCuisineTypeRepository(db)
+
+	replacement := m.CuisineType{ID: uuid.New()}
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "cuisine_types" WHERE "cuisine_types"."deleted_at" IS NULL AND "cuisine_types"."id" = $1 ORDER BY "cuisine_types"."id" LIMIT $2`)).
+		WithArgs(replacement.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(replacement.ID))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "recipe_cuisine_types" WHERE cuisine_type_id = $1`)).
+		WithArgs(cuisineType.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(recipeID))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_cuisine_types" WHERE cuisine_type_id = $1 AND recipe_id IN (SELECT "recipe_id" FROM "recipe_cuisine_types" WHERE cuisine_type_id = $2)`)).
+		WithArgs(cuisineType.ID, replacement.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_cuisine_types" SET "cuisine_type_id"=$1 WHERE cuisine_type_id = $2`)).
+		WithArgs(replacement.ID, cuisineType.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "cuisine_types" SET "deleted_at"=$1 WHERE "cuisine_types"."id" = $2 AND "cuisine_types"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), cuisineType.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8),($9,$10,$11,$12,$13,$14,$15,$16) RETURNING "sequence"`)).
+		WithArgs(
+			sqlmock.AnyArg(), m.EntityCuisineType, cuisineType.ID, m.EventDeleted, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+			sqlmock.AnyArg(), m.EntityRecipeMetadata, recipeID, m.EventUpdated, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1).AddRow(2))
+	mock.ExpectCommit()
+
+	err := r.Reassign(cuisineType, replacement)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCuisineTypeReassign_ReplacementNotFoundErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewCuisineTypeRepository(db)
+
+	replacement := m.CuisineType{ID: uuid.New()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "cuisine_types" WHERE "cuisine_types"."deleted_at" IS NULL AND "cuisine_types"."id" = $1 ORDER BY "cuisine_types"."id" LIMIT $2`)).
+		WithArgs(replacement.ID, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectRollback()
+
+	err := r.Reassign(cuisineType, replacement)
+
+	assert.EqualError(t, err, "replacement not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
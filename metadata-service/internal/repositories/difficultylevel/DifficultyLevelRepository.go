@@ -3,7 +3,9 @@ package repositories
 import (
 	"errors"
 
+	"cookbook/pkg/outbox"
 	m "metadata-service/internal/models"
+	"metadata-service/internal/repositories/references"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -56,7 +58,7 @@ func (r *DifficultyLevelRepository) Create(difficultyLevel m.DifficultyLevel) (m
 			return err
 		}
 
-		return nil
+		return outbox.Record(tx, m.NewChangeEvent(m.EventCreated, m.EntityDifficultyLevel, difficultyLevel.ID))
 	}); err != nil {
 		return m.DifficultyLevel{}, err
 	}
@@ -71,7 +73,7 @@ func (r *DifficultyLevelRepository) Update(difficultyLevel m.DifficultyLevel) (m
 		if err = tx.Updates(&difficultyLevel).Error; err != nil {
 			return err
 		}
-		return nil
+		return outbox.Record(tx, m.NewChangeEvent(m.EventUpdated, m.EntityDifficultyLevel, difficultyLevel.ID))
 	}); err != nil {
 		return m.DifficultyLevel{}, err
 	}
@@ -79,14 +81,57 @@ func (r *DifficultyLevelRepository) Update(difficultyLevel m.DifficultyLevel) (m
 	return difficultyLevel, nil
 }
 
+// Delete removes a difficulty level that is not used by any recipe, otherwise an InUseError is returned
 func (r *DifficultyLevelRepository) Delete(difficultyLevel m.DifficultyLevel) error {
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
 
+		usage, err := references.Usage(tx, &m.RecipeDifficultyLevel{}, "difficulty_level_id", difficultyLevel.ID)
+		if err != nil {
+			return err
+		}
+
+		if usage.InUse() {
+			return m.NewInUseError("difficulty level", usage)
+		}
+
+		if err := tx.Delete(&difficultyLevel).Error; err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventDeleted, m.EntityDifficultyLevel, difficultyLevel.ID))
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reassign moves the recipes associated with a difficulty level to the replacement and removes the difficulty level afterwards
+func (r *DifficultyLevelRepository) Reassign(difficultyLevel m.DifficultyLevel, replacement m.DifficultyLevel) error {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.First(&replacement).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("replacement not found")
+			}
+			return err
+		}
+
+		recipeIDs, err := references.Reassign(tx, &m.RecipeDifficultyLevel{}, "difficulty_level_id", difficultyLevel.ID, replacement.ID)
+		if err != nil {
+			return err
+		}
+
 		if err := tx.Delete(&difficultyLevel).Error; err != nil {
 			return err
 		}
 
-		return nil
+		events := []m.ChangeEvent{m.NewChangeEvent(m.EventDeleted, m.EntityDifficultyLevel, difficultyLevel.ID)}
+		for _, recipeID := range recipeIDs {
+			events = append(events, m.NewChangeEvent(m.EventUpdated, m.EntityRecipeMetadata, recipeID))
+		}
+
+		return outbox.Record(tx, events...)
 	}); err != nil {
 		return err
 	}
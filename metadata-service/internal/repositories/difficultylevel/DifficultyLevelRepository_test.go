@@ -10,6 +10,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 
 	co "metadata-service/internal/common/test"
 )
@@ -107,6 +108,7 @@ func TestDifficultyLevelCreate_OK(t *testing.T) {
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "level"}).AddRow(difficultyLevel.ID, difficultyLevel.Level),
 		)
+	co.ExpectOutbox(mock, m.EntityDifficultyLevel, difficultyLevel.ID, m.EventCreated)
 	mock.ExpectCommit()
 
 	result, err := r.Create(difficultyLevel)
@@ -114,6 +116,10 @@ func TestDifficultyLevelCreate_OK(t *testing.T) {
 	assert.NoError(t, err)
 	assert.IsType(t, m.DifficultyLevel{}, result)
 	assert.Equal(t, difficultyLevel.ID, result.ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
 }
 
 func TestDifficultyLevelCreate_Err(t *testing.T) {
@@ -150,6 +156,7 @@ func TestDifficultyLevelUpdate_OK(t *testing.T) {
 			sqlmock.AnyArg(),
 			difficultyLevel.ID,
 		).WillReturnResult(sqlmock.NewResult(1, 1))
+	co.ExpectOutbox(mock, m.EntityDifficultyLevel, difficultyLevel.ID, m.EventUpdated)
 	mock.ExpectCommit()
 
 	result, err := r.Update(difficultyLevel)
@@ -158,6 +165,9 @@ func TestDifficultyLevelUpdate_OK(t *testing.T) {
 	assert.IsType(t, m.DifficultyLevel{}, result)
 	assert.Equal(t, difficultyLevel.ID, result.ID)
 
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
 }
 
 func TestDifficultyLevelUpdate_Err(t *testing.T) {
@@ -186,16 +196,22 @@ func TestDifficultyLevelDelete_OK(t *testing.T) {
 	r := NewDifficultyLevelRepository(db)
 
 	mock.ExpectBegin()
+	co.ExpectUsage(mock, "recipe_difficulty_levels", "difficulty_level_id", difficultyLevel.ID, nil, nil)
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "difficulty_levels" SET "deleted_at"=$1 WHERE "difficulty_levels"."id" = $2 AND "difficulty_levels"."deleted_at" IS NULL`)).
 		WithArgs(
 			sqlmock.AnyArg(),
 			difficultyLevel.ID,
 		).WillReturnResult(sqlmock.NewResult(1, 1))
+	co.ExpectOutbox(mock, m.EntityDifficultyLevel, difficultyLevel.ID, m.EventDeleted)
 	mock.ExpectCommit()
 
 	err := r.Delete(difficultyLevel)
 
 	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
 }
 
 func TestDifficultyLevelDelete_Err(t *testing.T) {
@@ -203,6 +219,7 @@ func TestDifficultyLevelDelete_Err(t *testing.T) {
 	r := NewDifficultyLevelRepository(db)
 
 	mock.ExpectBegin()
+	co.ExpectUsage(mock, "recipe_difficulty_levels", "difficulty_level_id", difficultyLevel.ID, nil, nil)
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "difficulty_levels" SET "deleted_at"=$1 WHERE "difficulty_levels"."id" = $2 AND "difficulty_levels"."deleted_at" IS NULL`)).
 		WithArgs(
 			sqlmock.AnyArg(),
@@ -216,3 +233,77 @@ func TestDifficultyLevelDelete_Err(t *testing.T) {
 	assert.EqualError(t, err, "error")
 
 }
+
+func TestDifficultyLevelDelete_InUseErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewDifficultyLevelRepository(db)
+
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	co.ExpectUsage(mock, "recipe_difficulty_levels", "difficulty_level_id", difficultyLevel.ID, []uuid.UUID{recipeID}, nil)
+	mock.ExpectRollback()
+
+	err := r.Delete(difficultyLevel)
+
+	var inUse m.InUseError
+	assert.ErrorAs(t, err, &inUse)
+	assert.EqualError(t, err, "difficulty level is used by 1 recipes")
+	assert.Equal(t, []uuid.UUID{recipeID}, inUse.Usage.RecipeIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDifficultyLevelReassign_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewDifficultyLevelRepository(db)
+
+	replacement := m.DifficultyLevel{ID: uuid.New()}
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "difficulty_levels" WHERE "difficulty_levels"."deleted_at" IS NULL AND "difficulty_levels"."id" = $1 ORDER BY "difficulty_levels"."id" LIMIT $2`)).
+		WithArgs(replacement.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(replacement.ID))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "recipe_difficulty_levels" WHERE difficulty_level_id = $1`)).
+		WithArgs(difficultyLevel.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(recipeID))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_difficulty_levels" WHERE difficulty_level_id = $1 AND recipe_id IN (SELECT "recipe_id" FROM "recipe_difficulty_levels" WHERE difficulty_level_id = $2)`)).
+		WithArgs(difficultyLevel.ID, replacement.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_difficulty_levels" SET "difficulty_level_id"=$1 WHERE difficulty_level_id = $2`)).
+		WithArgs(replacement.ID, difficultyLevel.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "difficulty_levels" SET "deleted_at"=$1 WHERE "difficulty_levels"."id" = $2 AND "difficulty_levels"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), difficultyLevel.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8),($9,$10,$11,$12,$13,$14,$15,$16) RETURNING "sequence"`)).
+		WithArgs(
+			sqlmock.AnyArg(), m.EntityDifficultyLevel, difficultyLevel.ID, m.EventDeleted, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+			sqlmock.AnyArg(), m.EntityRecipeMetadata, recipeID, m.EventUpdated, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1).AddRow(2))
+	mock.ExpectCommit()
+
+	err := r.Reassign(difficultyLevel, replacement)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDifficultyLevelReassign_ReplacementNotFoundErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewDifficultyLevelRepository(db)
+
+	replacement := m.DifficultyLevel{ID: uuid.New()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "difficulty_levels" WHERE "difficulty_levels"."deleted_at" IS NULL AND "difficulty_levels"."id" = $1 ORDER BY "difficulty_levels"."id" LIMIT $2`)).
+		WithArgs(replacement.ID, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectRollback()
+
+	err := r.Reassign(difficultyLevel, replacement)
+
+	assert.EqualError(t, err, "replacement not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
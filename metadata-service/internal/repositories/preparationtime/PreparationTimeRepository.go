@@ -3,7 +3,9 @@ package repositories
 import (
 	"errors"
 
+	"cookbook/pkg/outbox"
 	m "metadata-service/internal/models"
+	"metadata-service/internal/repositories/references"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -56,7 +58,7 @@ func (r *PreparationTimeRepository) Create(preparationTime m.PreparationTime) (m
 			return err
 		}
 
-		return nil
+		return outbox.Record(tx, m.NewChangeEvent(m.EventCreated, m.EntityPreparationTime, preparationTime.ID))
 	}); err != nil {
 		return m.PreparationTime{}, err
 	}
@@ -71,7 +73,7 @@ func (r *PreparationTimeRepository) Update(preparationTime m.PreparationTime) (m
 		if err = tx.Updates(&preparationTime).Error; err != nil {
 			return err
 		}
-		return nil
+		return outbox.Record(tx, m.NewChangeEvent(m.EventUpdated, m.EntityPreparationTime, preparationTime.ID))
 	}); err != nil {
 		return m.PreparationTime{}, err
 	}
@@ -79,14 +81,57 @@ func (r *PreparationTimeRepository) Update(preparationTime m.PreparationTime) (m
 	return preparationTime, nil
 }
 
+// Delete removes a preparation time that is not used by any recipe, otherwise an InUseError is returned
 func (r *PreparationTimeRepository) Delete(preparationTime m.PreparationTime) error {
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
 
+		usage, err := references.Usage(tx, &m.RecipePreparationTime{}, "preparation_time_id", preparationTime.ID)
+		if err != nil {
+			return err
+		}
+
+		if usage.InUse() {
+			return m.NewInUseError("preparation time", usage)
+		}
+
+		if err := tx.Delete(&preparationTime).Error; err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventDeleted, m.EntityPreparationTime, preparationTime.ID))
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reassign moves the recipes associated with a preparation time to the replacement and removes the preparation time afterwards
+func (r *PreparationTimeRepository) Reassign(preparationTime m.PreparationTime, replacement m.PreparationTime) error {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.First(&replacement).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("replacement not found")
+			}
+			return err
+		}
+
+		recipeIDs, err := references.Reassign(tx, &m.RecipePreparationTime{}, "preparation_time_id", preparationTime.ID, replacement.ID)
+		if err != nil {
+			return err
+		}
+
 		if err := tx.Delete(&preparationTime).Error; err != nil {
 			return err
 		}
 
-		return nil
+		events := []m.ChangeEvent{m.NewChangeEvent(m.EventDeleted, m.EntityPreparationTime, preparationTime.ID)}
+		for _, recipeID := range recipeIDs {
+			events = append(events, m.NewChangeEvent(m.EventUpdated, m.EntityRecipeMetadata, recipeID))
+		}
+
+		return outbox.Record(tx, events...)
 	}); err != nil {
 		return err
 	}
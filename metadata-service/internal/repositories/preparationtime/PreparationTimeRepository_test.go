@@ -10,6 +10,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 
 	co "metadata-service/internal/common/test"
 )
@@ -107,6 +108,7 @@ func TestPreparationTimeCreate_OK(t *testing.T) {
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "duration"}).AddRow(preparationTime.ID, preparationTime.Duration),
 		)
+	co.ExpectOutbox(mock, m.EntityPreparationTime, preparationTime.ID, m.EventCreated)
 	mock.ExpectCommit()
 
 	result, err := r.Create(preparationTime)
@@ -114,6 +116,10 @@ func TestPreparationTimeCreate_OK(t *testing.T) {
 	assert.NoError(t, err)
 	assert.IsType(t, m.PreparationTime{}, result)
 	assert.Equal(t, preparationTime.ID, result.ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
 }
 
 func TestPreparationTimeCreate_Err(t *testing.T) {
@@ -150,6 +156,7 @@ func TestPreparationTimeUpdate_OK(t *testing.T) {
 			sqlmock.AnyArg(),
 			preparationTime.ID,
 		).WillReturnResult(sqlmock.NewResult(1, 1))
+	co.ExpectOutbox(mock, m.EntityPreparationTime, preparationTime.ID, m.EventUpdated)
 	mock.ExpectCommit()
 
 	result, err := r.Update(preparationTime)
@@ -158,6 +165,9 @@ func TestPreparationTimeUpdate_OK(t *testing.T) {
 	assert.IsType(t, m.PreparationTime{}, result)
 	assert.Equal(t, preparationTime.ID, result.ID)
 
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
 }
 
 func TestPreparationTimeUpdate_Err(t *testing.T) {
@@ -186,16 +196,22 @@ func TestPreparationTimeDelete_OK(t *testing.T) {
 	r := NewPreparationTimeRepository(db)
 
 	mock.ExpectBegin()
+	co.ExpectUsage(mock, "recipe_preparation_times", "preparation_time_id", preparationTime.ID, nil, nil)
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "preparation_times" SET "deleted_at"=$1 WHERE "preparation_times"."id" = $2 AND "preparation_times"."deleted_at" IS NULL`)).
 		WithArgs(
 			sqlmock.AnyArg(),
 			preparationTime.ID,
 		).WillReturnResult(sqlmock.NewResult(1, 1))
+	co.ExpectOutbox(mock, m.EntityPreparationTime, preparationTime.ID, m.EventDeleted)
 	mock.ExpectCommit()
 
 	err := r.Delete(preparationTime)
 
 	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
 }
 
 func TestPreparationTimeDelete_Err(t *testing.T) {
@@ -203,6 +219,7 @@ func TestPreparationTimeDelete_Err(t *testing.T) {
 	r := NewPreparationTimeRepository(db)
 
 	mock.ExpectBegin()
+	co.ExpectUsage(mock, "recipe_preparation_times", "preparation_time_id", preparationTime.ID, nil, nil)
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "preparation_times" SET "deleted_at"=$1 WHERE "preparation_times"."id" = $2 AND "preparation_times"."deleted_at" IS NULL`)).
 		WithArgs(
 			sqlmock.AnyArg(),
@@ -216,3 +233,77 @@ func TestPreparationTimeDelete_Err(t *testing.T) {
 	assert.EqualError(t, err, "error")
 
 }
+
+func TestPreparationTimeDelete_InUseErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewPreparationTimeRepository(db)
+
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	co.ExpectUsage(mock, "recipe_preparation_times", "preparation_time_id", preparationTime.ID, []uuid.UUID{recipeID}, nil)
+	mock.ExpectRollback()
+
+	err := r.Delete(preparationTime)
+
+	var inUse m.InUseError
+	assert.ErrorAs(t, err, &inUse)
+	assert.EqualError(t, err, "preparation time is used by 1 recipes")
+	assert.Equal(t, []uuid.UUID{recipeID}, inUse.Usage.RecipeIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPreparationTimeReassign_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewPreparationTimeRepository(db)
+
+	replacement := m.PreparationTime{ID: uuid.New()}
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "preparation_times" WHERE "preparation_times"."deleted_at" IS NULL AND "preparation_times"."id" = $1 ORDER BY "preparation_times"."id" LIMIT $2`)).
+		WithArgs(replacement.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(replacement.ID))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "recipe_preparation_times" WHERE preparation_time_id = $1`)).
+		WithArgs(preparationTime.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(recipeID))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_preparation_times" WHERE preparation_time_id = $1 AND recipe_id IN (SELECT "recipe_id" FROM "recipe_preparation_times" WHERE preparation_time_id = $2)`)).
+		WithArgs(preparationTime.ID, replacement.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_preparation_times" SET "preparation_time_id"=$1 WHERE preparation_time_id = $2`)).
+		WithArgs(replacement.ID, preparationTime.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "preparation_times" SET "deleted_at"=$1 WHERE "preparation_times"."id" = $2 AND "preparation_times"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), preparationTime.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8),($9,$10,$11,$12,$13,$14,$15,$16) RETURNING "sequence"`)).
+		WithArgs(
+			sqlmock.AnyArg(), m.EntityPreparationTime, preparationTime.ID, m.EventDeleted, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+			sqlmock.AnyArg(), m.EntityRecipeMetadata, recipeID, m.EventUpdated, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1).AddRow(2))
+	mock.ExpectCommit()
+
+	err := r.Reassign(preparationTime, replacement)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPreparationTimeReassign_ReplacementNotFoundErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewPreparationTimeRepository(db)
+
+	replacement := m.PreparationTime{ID: uuid.New()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "preparation_times" WHERE "preparation_times"."deleted_at" IS NULL AND "preparation_times"."id" = $1 ORDER BY "preparation_times"."id" LIMIT $2`)).
+		WithArgs(replacement.ID, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectRollback()
+
+	err := r.Reassign(preparationTime, replacement)
+
+	assert.EqualError(t, err, "replacement not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	m "metadata-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecipeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecipeRepository(db *gorm.DB) *RecipeRepository {
+	return &RecipeRepository{
+		db: db,
+	}
+}
+
+// Save stores the copy of a recipe, replacing the name and creation time of a copy kept before
+func (r *RecipeRepository) Save(recipe m.Recipe) error {
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "created_at", "updated_at"}),
+	}).Create(&recipe).Error
+}
+
+// Delete removes the copy of a recipe. Removing a copy that isn't kept is not an error, the recipe may have been
+// deleted before this service ever received it.
+func (r *RecipeRepository) Delete(recipeID uuid.UUID) error {
+
+	return r.db.Where("id = ?", recipeID).Delete(&m.Recipe{}).Error
+}
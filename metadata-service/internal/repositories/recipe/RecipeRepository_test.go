@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	co "metadata-service/internal/common/test"
+	m "metadata-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	recipe = m.Recipe{
+		ID:        uuid.New(),
+		Name:      "apple pie",
+		CreatedAt: time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC),
+	}
+)
+
+func TestRecipeSave_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewRecipeRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipes" ("id","name","created_at","updated_at") VALUES ($1,$2,$3,$4) ON CONFLICT ("id") DO UPDATE SET "name"="excluded"."name","created_at"="excluded"."created_at","updated_at"="excluded"."updated_at"`)).
+		WithArgs(recipe.ID, recipe.Name, recipe.CreatedAt, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := r.Save(recipe)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeSave_Err(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewRecipeRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipes"`)).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	err := r.Save(recipe)
+
+	assert.EqualError(t, err, "error")
+}
+
+func TestRecipeDelete_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewRecipeRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipes" WHERE id = $1`)).
+		WithArgs(recipe.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := r.Delete(recipe.ID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeDelete_Err(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewRecipeRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipes"`)).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	err := r.Delete(recipe.ID)
+
+	assert.EqualError(t, err, "error")
+}
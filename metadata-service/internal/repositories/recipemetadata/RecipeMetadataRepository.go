@@ -0,0 +1,266 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"cookbook/pkg/outbox"
+	m "metadata-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RecipeMetadataRepository struct {
+	db *gorm.DB
+}
+
+func NewRecipeMetadataRepository(db *gorm.DB) *RecipeMetadataRepository {
+	return &RecipeMetadataRepository{
+		db: db,
+	}
+}
+
+// The rows below combine a metadata record with the recipe it is associated to
+type recipeCategory struct {
+	RecipeID uuid.UUID
+	m.Category
+}
+
+type recipeTag struct {
+	RecipeID uuid.UUID
+	m.Tag
+}
+
+type recipeCuisineType struct {
+	RecipeID uuid.UUID
+	m.CuisineType
+}
+
+type recipeDifficultyLevel struct {
+	RecipeID uuid.UUID
+	m.DifficultyLevel
+}
+
+type recipePreparationTime struct {
+	RecipeID uuid.UUID
+	m.PreparationTime
+}
+
+func (r *RecipeMetadataRepository) FindByRecipe(recipeID uuid.UUID) (m.RecipeMetadata, error) {
+
+	metadata, err := r.FindByRecipes([]uuid.UUID{recipeID})
+	if err != nil {
+		return m.RecipeMetadata{}, err
+	}
+
+	return metadata[0], nil
+}
+
+// FindByRecipes collects the metadata of several recipes with a single query per metadata type.
+// Recipes without any metadata are left out of the result.
+func (r *RecipeMetadataRepository) FindByRecipes(recipeIDs []uuid.UUID) ([]m.RecipeMetadata, error) {
+	var categories []recipeCategory
+	var tags []recipeTag
+	var cuisineTypes []recipeCuisineType
+	var difficultyLevels []recipeDifficultyLevel
+	var preparationTimes []recipePreparationTime
+
+	if err := r.findAssociated("categories", "recipe_categories", "category_id", recipeIDs, &categories); err != nil {
+		return nil, err
+	}
+
+	if err := r.findAssociated("tags", "recipe_tags", "tag_id", recipeIDs, &tags); err != nil {
+		return nil, err
+	}
+
+	if err := r.findAssociated("cuisine_types", "recipe_cuisine_types", "cuisine_type_id", recipeIDs, &cuisineTypes); err != nil {
+		return nil, err
+	}
+
+	if err := r.findAssociated("difficulty_levels", "recipe_difficulty_levels", "difficulty_level_id", recipeIDs, &difficultyLevels); err != nil {
+		return nil, err
+	}
+
+	if err := r.findAssociated("preparation_times", "recipe_preparation_times", "preparation_time_id", recipeIDs, &preparationTimes); err != nil {
+		return nil, err
+	}
+
+	byRecipe := make(map[uuid.UUID]*m.RecipeMetadata, len(recipeIDs))
+	get := func(recipeID uuid.UUID) *m.RecipeMetadata {
+		if _, found := byRecipe[recipeID]; !found {
+			byRecipe[recipeID] = &m.RecipeMetadata{RecipeID: recipeID}
+		}
+		return byRecipe[recipeID]
+	}
+
+	for _, category := range categories {
+		metadata := get(category.RecipeID)
+		metadata.Categories = append(metadata.Categories, category.Category)
+	}
+
+	for _, tag := range tags {
+		metadata := get(tag.RecipeID)
+		metadata.Tags = append(metadata.Tags, tag.Tag)
+	}
+
+	for _, cuisineType := range cuisineTypes {
+		get(cuisineType.RecipeID).CuisineType = cuisineType.CuisineType
+	}
+
+	for _, difficultyLevel := range difficultyLevels {
+		get(difficultyLevel.RecipeID).DifficultyLevel = difficultyLevel.DifficultyLevel
+	}
+
+	for _, preparationTime := range preparationTimes {
+		get(preparationTime.RecipeID).PreparationTime = preparationTime.PreparationTime
+	}
+
+	// keep the order of the requested recipes
+	var results []m.RecipeMetadata
+	for _, recipeID := range recipeIDs {
+		if metadata, found := byRecipe[recipeID]; found {
+			results = append(results, *metadata)
+			delete(byRecipe, recipeID)
+		}
+	}
+
+	if len(results) <= 0 {
+		return nil, errors.New("not found")
+	}
+
+	return results, nil
+}
+
+func (r *RecipeMetadataRepository) findAssociated(table string, associationTable string, foreignKey string, recipeIDs []uuid.UUID, dest interface{}) error {
+	return r.db.Table(table).
+		Select(associationTable+".recipe_id, "+table+".*").
+		Joins("JOIN "+associationTable+" ON "+associationTable+"."+foreignKey+" = "+table+".id AND "+associationTable+".deleted_at IS NULL").
+		Where(associationTable+".recipe_id IN ?", recipeIDs).
+		Where(table + ".deleted_at IS NULL").
+		Order(associationTable + ".created_at").
+		Scan(dest).Error
+}
+
+// Replace swaps all metadata of a recipe in a single transaction. The old associations are removed permanently,
+// as they would otherwise collide with the new ones on the primary keys. The preparation time keeps the time the
+// recipe was first given metadata, which the search sorts the newest recipes on.
+func (r *RecipeMetadataRepository) Replace(metadata m.RecipeMetadata) error {
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		var recipeCategories []m.RecipeCategory
+		var recipeTags []m.RecipeTag
+		var createdAt []time.Time
+
+		if err := tx.Unscoped().Model(&m.RecipePreparationTime{}).
+			Where("recipe_id = ?", metadata.RecipeID).
+			Pluck("created_at", &createdAt).Error; err != nil {
+			return err
+		}
+
+		// zero for a new recipe, which is then set to now
+		preparationTime := m.RecipePreparationTime{RecipeID: metadata.RecipeID, PreparationTimeID: metadata.PreparationTime.ID}
+		if len(createdAt) > 0 {
+			preparationTime.CreatedAt = createdAt[0]
+		}
+
+		for _, model := range associations() {
+			if err := tx.Unscoped().Where("recipe_id = ?", metadata.RecipeID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, category := range metadata.Categories {
+			recipeCategories = append(recipeCategories, m.RecipeCategory{RecipeID: metadata.RecipeID, CategoryID: category.ID})
+		}
+
+		for _, tag := range metadata.Tags {
+			recipeTags = append(recipeTags, m.RecipeTag{RecipeID: metadata.RecipeID, TagID: tag.ID})
+		}
+
+		if len(recipeCategories) > 0 {
+			if err := tx.Create(&recipeCategories).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(recipeTags) > 0 {
+			if err := tx.Create(&recipeTags).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, association := range []interface{}{
+			&m.RecipeCuisineType{RecipeID: metadata.RecipeID, CuisineTypeID: metadata.CuisineType.ID},
+			&m.RecipeDifficultyLevel{RecipeID: metadata.RecipeID, DifficultyLevelID: metadata.DifficultyLevel.ID},
+			&preparationTime,
+		} {
+			if err := tx.Create(association).Error; err != nil {
+				return err
+			}
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventUpdated, m.EntityRecipeMetadata, metadata.RecipeID))
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteByRecipe soft-deletes all metadata associations of a deleted recipe, so they can be restored along with it
+func (r *RecipeMetadataRepository) DeleteByRecipe(recipeID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+
+		for _, model := range associations() {
+			if err := tx.Where("recipe_id = ?", recipeID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventDeleted, m.EntityRecipeMetadata, recipeID))
+	})
+}
+
+// RestoreByRecipe restores the metadata associations of a recipe that were deleted since the given time. Replace
+// removes associations permanently, so only the ones deleted along with the recipe are restored.
+func (r *RecipeMetadataRepository) RestoreByRecipe(recipeID uuid.UUID, since time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+
+		for _, model := range associations() {
+			if err := tx.Unscoped().Model(model).
+				Where("recipe_id = ? AND deleted_at >= ?", recipeID, since).
+				Update("deleted_at", nil).Error; err != nil {
+				return err
+			}
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventCreated, m.EntityRecipeMetadata, recipeID))
+	})
+}
+
+// PurgeByRecipe permanently removes the metadata associations of a deleted recipe once it can no longer be
+// restored. The associations of a recipe that was restored in the meantime are kept.
+func (r *RecipeMetadataRepository) PurgeByRecipe(recipeID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+
+		for _, model := range associations() {
+			if err := tx.Unscoped().Where("recipe_id = ? AND deleted_at IS NOT NULL", recipeID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// associations lists the models linking metadata to a recipe
+func associations() []interface{} {
+	return []interface{}{
+		&m.RecipeCategory{},
+		&m.RecipeTag{},
+		&m.RecipeCuisineType{},
+		&m.RecipeDifficultyLevel{},
+		&m.RecipePreparationTime{},
+	}
+}
@@ -0,0 +1,283 @@
+package repositories
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	m "metadata-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	co "metadata-service/internal/common/test"
+)
+
+var (
+	recipeID uuid.UUID = uuid.New()
+
+	metadata m.RecipeMetadata = m.RecipeMetadata{
+		RecipeID:        recipeID,
+		Categories:      []m.Category{{ID: uuid.New(), Name: "dessert"}},
+		Tags:            []m.Tag{{ID: uuid.New(), Name: "weeknight"}, {ID: uuid.New(), Name: "vegan"}},
+		CuisineType:     m.CuisineType{ID: uuid.New(), Name: "french"},
+		DifficultyLevel: m.DifficultyLevel{ID: uuid.New(), Level: 2},
+		PreparationTime: m.PreparationTime{ID: uuid.New(), Duration: 30},
+	}
+)
+
+func expectAssociated(mock sqlmock.Sqlmock, table string, associationTable string, foreignKey string, rows *sqlmock.Rows) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT ` + associationTable + `.recipe_id, ` + table + `.* FROM "` + table + `" ` +
+		`JOIN ` + associationTable + ` ON ` + associationTable + `.` + foreignKey + ` = ` + table + `.id AND ` + associationTable + `.deleted_at IS NULL ` +
+		`WHERE ` + associationTable + `.recipe_id IN ($1) AND ` + table + `.deleted_at IS NULL ORDER BY ` + associationTable + `.created_at`)).
+		WithArgs(recipeID).
+		WillReturnRows(rows)
+}
+
+func TestRecipeMetadataFindByRecipe_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewRecipeMetadataRepository(db)
+
+	expectAssociated(mock, "categories", "recipe_categories", "category_id", sqlmock.NewRows([]string{"recipe_id", "id", "name"}).
+		AddRow(recipeID, metadata.Categories[0].ID, metadata.Categories[0].Name))
+	expectAssociated(mock, "tags", "recipe_tags", "tag_id", sqlmock.NewRows([]string{"recipe_id", "id", "name"}).
+		AddRow(recipeID, metadata.Tags[0].ID, metadata.Tags[0].Name).
+		AddRow(recipeID, metadata.Tags[1].ID, metadata.Tags[1].Name))
+	expectAssociated(mock, "cuisine_types", "recipe_cuisine_types", "cuisine_type_id", sqlmock.NewRows([]string{"recipe_id", "id", "name"}).
+		AddRow(recipeID, metadata.CuisineType.ID, metadata.CuisineType.Name))
+	expectAssociated(mock, "difficulty_levels", "recipe_difficulty_levels", "difficulty_level_id", sqlmock.NewRows([]string{"recipe_id", "id", "level"}).
+		AddRow(recipeID, metadata.DifficultyLevel.ID, metadata.DifficultyLevel.Level))
+	expectAssociated(mock, "preparation_times", "recipe_preparation_times", "preparation_time_id", sqlmock.NewRows([]string{"recipe_id", "id", "duration"}).
+		AddRow(recipeID, metadata.PreparationTime.ID, metadata.PreparationTime.Duration))
+
+	result, err := r.FindByRecipe(recipeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, metadata, result)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRecipeMetadataFindByRecipe_NotFound(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewRecipeMetadataRepository(db)
+
+	expectAssociated(mock, "categories", "recipe_categories", "category_id", &sqlmock.Rows{})
+	expectAssociated(mock, "tags", "recipe_tags", "tag_id", &sqlmock.Rows{})
+	expectAssociated(mock, "cuisine_types", "recipe_cuisine_types", "cuisine_type_id", &sqlmock.Rows{})
+	expectAssociated(mock, "difficulty_levels", "recipe_difficulty_levels", "difficulty_level_id", &sqlmock.Rows{})
+	expectAssociated(mock, "preparation_times", "recipe_preparation_times", "preparation_time_id", &sqlmock.Rows{})
+
+	result, err := r.FindByRecipe(recipeID)
+
+	assert.EqualError(t, err, "not found")
+	assert.Equal(t, m.RecipeMetadata{}, result)
+}
+
+func TestRecipeMetadataFindByRecipe_Err(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewRecipeMetadataRepository(db)
+
+	expectAssociated(mock, "categories", "recipe_categories", "category_id", &sqlmock.Rows{})
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "tags"`)).
+		WillReturnError(errors.New("error"))
+
+	result, err := r.FindByRecipe(recipeID)
+
+	assert.EqualError(t, err, "error")
+	assert.Equal(t, m.RecipeMetadata{}, result)
+}
+
+func TestRecipeMetadataReplace_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewRecipeMetadataRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "created_at" FROM "recipe_preparation_times" WHERE recipe_id = $1`)).
+		WithArgs(recipeID).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}))
+	for _, table := range []string{"recipe_categories", "recipe_tags", "recipe_cuisine_types", "recipe_difficulty_levels", "recipe_preparation_times"} {
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "` + table + `" WHERE recipe_id = $1`)).
+			WithArgs(recipeID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_categories" ("recipe_id","category_id","created_at","deleted_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs(recipeID, metadata.Categories[0].ID, sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_tags" ("recipe_id","tag_id","created_at","deleted_at") VALUES ($1,$2,$3,$4),($5,$6,$7,$8)`)).
+		WithArgs(recipeID, metadata.Tags[0].ID, sqlmock.AnyArg(), nil, recipeID, metadata.Tags[1].ID, sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_cuisine_types" ("recipe_id","cuisine_type_id","created_at","deleted_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs(recipeID, metadata.CuisineType.ID, sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_difficulty_levels" ("recipe_id","difficulty_level_id","created_at","deleted_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs(recipeID, metadata.DifficultyLevel.ID, sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_preparation_times" ("recipe_id","preparation_time_id","created_at","deleted_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs(recipeID, metadata.PreparationTime.ID, sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	co.ExpectOutbox(mock, m.EntityRecipeMetadata, recipeID, m.EventUpdated)
+	mock.ExpectCommit()
+
+	err := r.Replace(metadata)
+
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRecipeMetadataReplace_KeepsCreatedAt(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewRecipeMetadataRepository(db)
+
+	// replacing the metadata doesn't make a recipe any newer
+	createdAt := time.Date(2023, 2, 4, 18, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "created_at" FROM "recipe_preparation_times" WHERE recipe_id = $1`)).
+		WithArgs(recipeID).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+	for _, table := range []string{"recipe_categories", "recipe_tags", "recipe_cuisine_types", "recipe_difficulty_levels", "recipe_preparation_times"} {
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "` + table + `" WHERE recipe_id = $1`)).
+			WithArgs(recipeID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_categories" ("recipe_id","category_id","created_at","deleted_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs(recipeID, metadata.Categories[0].ID, sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_tags" ("recipe_id","tag_id","created_at","deleted_at") VALUES ($1,$2,$3,$4),($5,$6,$7,$8)`)).
+		WithArgs(recipeID, metadata.Tags[0].ID, sqlmock.AnyArg(), nil, recipeID, metadata.Tags[1].ID, sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_cuisine_types" ("recipe_id","cuisine_type_id","created_at","deleted_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs(recipeID, metadata.CuisineType.ID, sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_difficulty_levels" ("recipe_id","difficulty_level_id","created_at","deleted_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs(recipeID, metadata.DifficultyLevel.ID, sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_preparation_times" ("recipe_id","preparation_time_id","created_at","deleted_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs(recipeID, metadata.PreparationTime.ID, createdAt, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	co.ExpectOutbox(mock, m.EntityRecipeMetadata, recipeID, m.EventUpdated)
+	mock.ExpectCommit()
+
+	err := r.Replace(metadata)
+
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRecipeMetadataReplace_Err(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewRecipeMetadataRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "created_at" FROM "recipe_preparation_times" WHERE recipe_id = $1`)).
+		WithArgs(recipeID).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_categories" WHERE recipe_id = $1`)).
+		WithArgs(recipeID).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	err := r.Replace(metadata)
+
+	assert.EqualError(t, err, "error")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRecipeMetadataDeleteByRecipe_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewRecipeMetadataRepository(db)
+
+	mock.ExpectBegin()
+	for _, table := range []string{"recipe_categories", "recipe_tags", "recipe_cuisine_types", "recipe_difficulty_levels", "recipe_preparation_times"} {
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "`+table+`" SET "deleted_at"=$1 WHERE recipe_id = $2 AND "`+table+`"."deleted_at" IS NULL`)).
+			WithArgs(sqlmock.AnyArg(), recipeID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	co.ExpectOutbox(mock, m.EntityRecipeMetadata, recipeID, m.EventDeleted)
+	mock.ExpectCommit()
+
+	err := r.DeleteByRecipe(recipeID)
+
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRecipeMetadataDeleteByRecipe_Err(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewRecipeMetadataRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_categories" SET "deleted_at"=$1 WHERE recipe_id = $2 AND "recipe_categories"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), recipeID).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	err := r.DeleteByRecipe(recipeID)
+
+	assert.EqualError(t, err, "error")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRecipeMetadataRestoreByRecipe_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewRecipeMetadataRepository(db)
+	since := time.Now()
+
+	mock.ExpectBegin()
+	for _, table := range []string{"recipe_categories", "recipe_tags", "recipe_cuisine_types", "recipe_difficulty_levels", "recipe_preparation_times"} {
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "`+table+`" SET "deleted_at"=$1 WHERE recipe_id = $2 AND deleted_at >= $3`)).
+			WithArgs(nil, recipeID, since).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	co.ExpectOutbox(mock, m.EntityRecipeMetadata, recipeID, m.EventCreated)
+	mock.ExpectCommit()
+
+	err := r.RestoreByRecipe(recipeID, since)
+
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRecipeMetadataPurgeByRecipe_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewRecipeMetadataRepository(db)
+
+	mock.ExpectBegin()
+	for _, table := range []string{"recipe_categories", "recipe_tags", "recipe_cuisine_types", "recipe_difficulty_levels", "recipe_preparation_times"} {
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "`+table+`" WHERE recipe_id = $1 AND deleted_at IS NOT NULL`)).
+			WithArgs(recipeID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+
+	err := r.PurgeByRecipe(recipeID)
+
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
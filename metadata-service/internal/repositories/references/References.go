@@ -0,0 +1,95 @@
+package references
+
+import (
+	m "metadata-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Usage counts the recipes referencing an entity through an association, e.g. m.RecipeTag with column tag_id,
+// and samples a few of them. The associations of deleted recipes are counted apart, as they come back when such a
+// recipe is restored. They are kept until the restore window of the recipe has passed and the recipe service
+// purges them.
+func Usage(tx *gorm.DB, association interface{}, column string, id uuid.UUID) (m.Usage, error) {
+	var usage m.Usage
+	var err error
+
+	usage.Count, usage.RecipeIDs, err = sample(func() *gorm.DB {
+		return tx.Model(association).Where(column+" = ?", id)
+	})
+	if err != nil {
+		return m.Usage{}, err
+	}
+
+	usage.DeletedCount, usage.DeletedRecipeIDs, err = sample(func() *gorm.DB {
+		return tx.Unscoped().Model(association).Where(column+" = ? AND deleted_at IS NOT NULL", id)
+	})
+	if err != nil {
+		return m.Usage{}, err
+	}
+
+	return usage, nil
+}
+
+// sample counts the recipes of the associations a query selects, and returns the first few of them
+func sample(associations func() *gorm.DB) (int64, []uuid.UUID, error) {
+	var count int64
+	var recipeIDs []uuid.UUID
+
+	if err := associations().Distinct("recipe_id").Count(&count).Error; err != nil {
+		return 0, nil, err
+	}
+
+	if count == 0 {
+		return 0, nil, nil
+	}
+
+	if err := associations().Distinct().Order("recipe_id").Limit(m.UsageSampleSize).Pluck("recipe_id", &recipeIDs).Error; err != nil {
+		return 0, nil, err
+	}
+
+	return count, recipeIDs, nil
+}
+
+// Recipes returns all recipes associated with an entity, including deleted recipes
+func Recipes(tx *gorm.DB, association interface{}, column string, id uuid.UUID) ([]uuid.UUID, error) {
+	var recipeIDs []uuid.UUID
+
+	if err := tx.Unscoped().Model(association).
+		Where(column+" = ?", id).
+		Distinct().
+		Pluck("recipe_id", &recipeIDs).Error; err != nil {
+		return nil, err
+	}
+
+	return recipeIDs, nil
+}
+
+// Reassign moves the associations of an entity to the replacement and returns the recipes involved. A recipe
+// already associated with the replacement just loses the association. The associations of deleted recipes are
+// moved as well, so they are still complete when such a recipe is restored.
+func Reassign(tx *gorm.DB, association interface{}, column string, from uuid.UUID, to uuid.UUID) ([]uuid.UUID, error) {
+	recipeIDs, err := Recipes(tx, association, column, from)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recipeIDs) == 0 {
+		return recipeIDs, nil
+	}
+
+	if err := tx.Unscoped().
+		Where(column+" = ? AND recipe_id IN (?)", from, tx.Unscoped().Model(association).Select("recipe_id").Where(column+" = ?", to)).
+		Delete(association).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Unscoped().Model(association).
+		Where(column+" = ?", from).
+		Update(column, to).Error; err != nil {
+		return nil, err
+	}
+
+	return recipeIDs, nil
+}
@@ -1,13 +1,22 @@
 package repositories
 
 import (
-	"errors"
+	"time"
+
 	m "metadata-service/internal/models"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// The values recipes are sorted on by name and by creation time come from the copy of the recipes kept by the
+// recipe events. Until the copy of a recipe arrives it sorts first by name, and by the time it was first given
+// metadata, which the preparation time keeps when it is replaced.
+const (
+	recipeName      = "COALESCE(recipes.name, '')"
+	recipeCreatedAt = "COALESCE(recipes.created_at, recipe_preparation_times.created_at)"
+)
+
 type SearcRepository struct {
 	db *gorm.DB
 }
@@ -18,93 +27,253 @@ func NewSearchRepository(db *gorm.DB) *SearcRepository {
 	}
 }
 
-func (r *SearcRepository) SearchMetadata(request m.MetadataSearchRequest) ([]m.MetadataSearchResult, error) {
-	var results []m.MetadataSearchResult
+// searchRow is a matching recipe with the metadata every recipe has exactly one of and the values it is sorted on
+type searchRow struct {
+	RecipeID          uuid.UUID
+	CuisineTypeID     uuid.UUID
+	DifficultyLevelID uuid.UUID
+	PreparationTimeID uuid.UUID
+	Duration          int
+	Name              string
+	CreatedAt         time.Time
+}
 
-	// Start with base query. we do this on categories as all recipes need to have a category
-	query := r.db.Table("recipe_categories").
-		Select("recipe_categories.recipe_id").
-		Joins("LEFT JOIN recipe_tags ON recipe_categories.recipe_id = recipe_tags.recipe_id").
-		Joins("LEFT JOIN recipe_difficulty_levels ON recipe_categories.recipe_id = recipe_difficulty_levels.recipe_id").
-		Joins("LEFT JOIN recipe_preparation_times ON recipe_categories.recipe_id = recipe_preparation_times.recipe_id").
-		Joins("LEFT JOIN recipe_cuisine_types ON recipe_categories.recipe_id = recipe_cuisine_types.recipe_id").
-		Group("recipe_categories.recipe_id")
+type recipeAssociation struct {
+	RecipeID uuid.UUID
+	ID       uuid.UUID
+}
 
-		// Apply filters based on request
-	if *request.CategoryID != uuid.Nil {
-		query = query.Where("recipe_categories.category_id = ?", *request.CategoryID)
+// SearchMetadata returns a page of the recipes matching the request together with the total number of matches.
+// Regardless of the page size this takes four queries: the count, the page and the categories and tags of the page.
+func (r *SearcRepository) SearchMetadata(request m.MetadataSearchRequest) (m.MetadataSearchPage, error) {
+	var page m.MetadataSearchPage
+	var rows []searchRow
+
+	if err := r.matching(request).Count(&page.Total).Error; err != nil {
+		return m.MetadataSearchPage{}, err
 	}
 
-	if *request.TagID != uuid.Nil {
-		query = query.Where("recipe_tags.tag_id = ?", *request.TagID)
+	query := r.matching(request).
+		Select("recipe_preparation_times.recipe_id, recipe_cuisine_types.cuisine_type_id, recipe_difficulty_levels.difficulty_level_id, " +
+			"recipe_preparation_times.preparation_time_id, preparation_times.duration, " + recipeName + " AS name, " + recipeCreatedAt + " AS created_at")
+
+	// Order on a unique combination of columns so pages neither overlap nor skip recipes
+	switch request.Sort {
+	case m.SearchSortPrepTime:
+		query = query.Order("preparation_times.duration, recipe_preparation_times.recipe_id")
+		if request.Cursor != nil {
+			query = query.Where("(preparation_times.duration, recipe_preparation_times.recipe_id) > (?, ?)", request.Cursor.Duration, request.Cursor.RecipeID)
+		}
+	case m.SearchSortName:
+		query = query.Order(recipeName + ", recipe_preparation_times.recipe_id")
+		if request.Cursor != nil {
+			query = query.Where("("+recipeName+", recipe_preparation_times.recipe_id) > (?, ?)", request.Cursor.Name, request.Cursor.RecipeID)
+		}
+	default:
+		query = query.Order(recipeCreatedAt + " DESC, recipe_preparation_times.recipe_id DESC")
+		if request.Cursor != nil {
+			query = query.Where("("+recipeCreatedAt+", recipe_preparation_times.recipe_id) < (?, ?)", request.Cursor.CreatedAt, request.Cursor.RecipeID)
+		}
 	}
 
-	if *request.DifficultyLevelID != uuid.Nil {
-		query = query.Where("recipe_difficulty_levels.difficulty_level_id = ?", *request.DifficultyLevelID)
+	// Fetch a single extra row to find out whether there is a next page
+	if err := query.Limit(request.Limit + 1).Scan(&rows).Error; err != nil {
+		return m.MetadataSearchPage{}, err
 	}
 
-	if *request.CuisineTypeID != uuid.Nil {
-		query = query.Where("recipe_cuisine_types.cuisine_type_id = ?", *request.CuisineTypeID)
+	if len(rows) > request.Limit {
+		rows = rows[:request.Limit]
+		last := rows[len(rows)-1]
+
+		page.Next = &m.MetadataSearchCursor{
+			Sort:      request.Sort,
+			CreatedAt: last.CreatedAt,
+			Duration:  last.Duration,
+			Name:      last.Name,
+			RecipeID:  last.RecipeID,
+		}
 	}
 
-	// Handle preparation time range
-	if request.MinPrepTime != nil || request.MaxPrepTime != nil {
-		var prepTimeIDs []uuid.UUID
+	if len(rows) <= 0 {
+		return page, nil
+	}
 
-		prepTimeQuery := r.db.Table("preparation_times").Select("id")
+	recipeIDs := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		recipeIDs = append(recipeIDs, row.RecipeID)
+	}
 
-		if request.MinPrepTime != nil {
-			prepTimeQuery = prepTimeQuery.Where("duration >= ?", *request.MinPrepTime)
-		}
+	categories, err := r.associated("recipe_categories", "category_id", recipeIDs)
+	if err != nil {
+		return m.MetadataSearchPage{}, err
+	}
 
-		if request.MaxPrepTime != nil {
-			prepTimeQuery = prepTimeQuery.Where("duration <= ?", *request.MaxPrepTime)
-		}
+	tags, err := r.associated("recipe_tags", "tag_id", recipeIDs)
+	if err != nil {
+		return m.MetadataSearchPage{}, err
+	}
+
+	for _, row := range rows {
+		page.Results = append(page.Results, m.MetadataSearchResult{
+			RecipeID:          row.RecipeID,
+			CategoryIDs:       categories[row.RecipeID],
+			TagIDs:            tags[row.RecipeID],
+			DifficultyLevelID: row.DifficultyLevelID,
+			PreparationTimeID: row.PreparationTimeID,
+			CuisineTypeID:     row.CuisineTypeID,
+		})
+	}
+
+	return page, nil
+}
+
+// associated collects the ids a many to many association table links to each of the recipes
+func (r *SearcRepository) associated(association string, column string, recipeIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	var rows []recipeAssociation
+
+	err := r.db.Table(association).
+		Select("recipe_id, "+column+" AS id").
+		Where("recipe_id IN ?", recipeIDs).
+		Order("created_at").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byRecipe := make(map[uuid.UUID][]uuid.UUID, len(recipeIDs))
+	for _, row := range rows {
+		byRecipe[row.RecipeID] = append(byRecipe[row.RecipeID], row.ID)
+	}
+
+	return byRecipe, nil
+}
+
+// SearchFacets counts the recipes matching the request per category, tag, cuisine type, difficulty level and
+// preparation time bucket. Every facet is a single aggregate query over the matching recipes.
+func (r *SearcRepository) SearchFacets(request m.MetadataSearchRequest) (m.MetadataFacets, error) {
+	var facets m.MetadataFacets
+	var err error
+
+	recipes := r.matching(request).Select("recipe_preparation_times.recipe_id")
 
-		if err := prepTimeQuery.Pluck("id", &prepTimeIDs).Error; err != nil {
-			return nil, err
+	if facets.Categories, err = r.facet("recipe_categories", "categories", "category_id", "categories.name", recipes); err != nil {
+		return m.MetadataFacets{}, err
+	}
+
+	if facets.Tags, err = r.facet("recipe_tags", "tags", "tag_id", "tags.name", recipes); err != nil {
+		return m.MetadataFacets{}, err
+	}
+
+	if facets.CuisineTypes, err = r.facet("recipe_cuisine_types", "cuisine_types", "cuisine_type_id", "cuisine_types.name", recipes); err != nil {
+		return m.MetadataFacets{}, err
+	}
+
+	if facets.DifficultyLevels, err = r.facet("recipe_difficulty_levels", "difficulty_levels", "difficulty_level_id", "CAST(difficulty_levels.level AS text)", recipes); err != nil {
+		return m.MetadataFacets{}, err
+	}
+
+	// Durations are few, so count per duration and sum them up into the buckets
+	var durations []struct {
+		Duration int
+		Count    int64
+	}
+
+	err = r.db.Table("recipe_preparation_times").
+		Select("preparation_times.duration, count(DISTINCT recipe_preparation_times.recipe_id) AS count").
+		Joins("JOIN preparation_times ON preparation_times.id = recipe_preparation_times.preparation_time_id").
+		Where("recipe_preparation_times.recipe_id IN (?)", recipes).
+		Group("preparation_times.duration").
+		Scan(&durations).Error
+	if err != nil {
+		return m.MetadataFacets{}, err
+	}
+
+	for _, bucket := range m.PreparationTimeBuckets {
+		for _, duration := range durations {
+			if duration.Duration >= bucket.MinPrepTime && (bucket.MaxPrepTime == nil || duration.Duration <= *bucket.MaxPrepTime) {
+				bucket.Count += duration.Count
+			}
 		}
 
-		query = query.Where("recipe_preparation_times.preparation_time_id IN ?", prepTimeIDs)
+		facets.PreparationTimes = append(facets.PreparationTimes, bucket)
 	}
 
-	// Scan results into a slice of recipe IDs
-	var recipeIDs []uuid.UUID
-	if err := query.Pluck("recipe_categories.recipe_id", &recipeIDs).Error; err != nil {
+	return facets, nil
+}
+
+// facet counts the matching recipes per entity of a table linked to them through an association table
+func (r *SearcRepository) facet(association string, table string, column string, name string, recipes *gorm.DB) ([]m.MetadataFacet, error) {
+	var facets []m.MetadataFacet
+
+	err := r.db.Table(association).
+		Select(table+".id, "+name+" AS name, count(DISTINCT "+association+".recipe_id) AS count").
+		Joins("JOIN "+table+" ON "+table+".id = "+association+"."+column).
+		Where(association+".recipe_id IN (?)", recipes).
+		Where(table + ".deleted_at IS NULL").
+		Group(table + ".id").
+		Order("count DESC, name").
+		Scan(&facets).Error
+	if err != nil {
 		return nil, err
 	}
 
-	// Collect detailed metadata for each recipe ID
-	for _, recipeID := range recipeIDs {
-		var categoryID, tagID, difficultyLevelID, preparationTimeID, cuisineTypeID []uuid.UUID
+	return facets, nil
+}
 
-		r.db.Table("recipe_categories").Where("recipe_id = ?", recipeID).Pluck("category_id", &categoryID)
-		r.db.Table("recipe_tags").Where("recipe_id = ?", recipeID).Pluck("tag_id", &tagID)
-		r.db.Table("recipe_difficulty_levels").Where("recipe_id = ?", recipeID).Pluck("difficulty_level_id", &difficultyLevelID)
-		r.db.Table("recipe_preparation_times").Where("recipe_id = ?", recipeID).Pluck("preparation_time_id", &preparationTimeID)
-		r.db.Table("recipe_cuisine_types").Where("recipe_id = ?", recipeID).Pluck("cuisine_type_id", &cuisineTypeID)
+// matching builds the query for the recipes matching the filters of the request. Every recipe has exactly one
+// preparation time, difficulty level and cuisine type, so joining these yields a single row per recipe. The
+// associations of deleted recipes are soft-deleted together, so the joins leave those recipes out. The copy of the
+// recipe is joined for sorting only, a recipe without one still matches.
+func (r *SearcRepository) matching(request m.MetadataSearchRequest) *gorm.DB {
+	query := r.db.Table("recipe_preparation_times").
+		Joins("JOIN preparation_times ON preparation_times.id = recipe_preparation_times.preparation_time_id").
+		Joins("JOIN recipe_difficulty_levels ON recipe_difficulty_levels.recipe_id = recipe_preparation_times.recipe_id AND recipe_difficulty_levels.deleted_at IS NULL").
+		Joins("JOIN recipe_cuisine_types ON recipe_cuisine_types.recipe_id = recipe_preparation_times.recipe_id AND recipe_cuisine_types.deleted_at IS NULL").
+		Joins("LEFT JOIN recipes ON recipes.id = recipe_preparation_times.recipe_id")
 
-		if len(difficultyLevelID) < 1 {
-			return nil, errors.New("no difficulty level associated with a recipe. this should be impossible")
-		}
+	if len(request.CategoryIDs) > 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM recipe_categories WHERE recipe_categories.recipe_id = recipe_preparation_times.recipe_id AND recipe_categories.category_id IN ?)", request.CategoryIDs)
+	}
 
-		if len(preparationTimeID) < 1 {
-			return nil, errors.New("no preparation time associated with a recipe. this should be impossible")
+	if tagIDs := unique(request.TagIDs); len(tagIDs) > 0 {
+		switch request.TagMode {
+		case m.TagModeAny:
+			query = query.Where("EXISTS (SELECT 1 FROM recipe_tags WHERE recipe_tags.recipe_id = recipe_preparation_times.recipe_id AND recipe_tags.tag_id IN ?)", tagIDs)
+		default:
+			query = query.Where("(SELECT count(*) FROM recipe_tags WHERE recipe_tags.recipe_id = recipe_preparation_times.recipe_id AND recipe_tags.tag_id IN ?) = ?", tagIDs, len(tagIDs))
 		}
+	}
 
-		if len(cuisineTypeID) < 1 {
-			return nil, errors.New("no cuisine type associated with a recipe. this should be impossible")
-		}
+	if len(request.CuisineTypeIDs) > 0 {
+		query = query.Where("recipe_cuisine_types.cuisine_type_id IN ?", request.CuisineTypeIDs)
+	}
 
-		results = append(results, m.MetadataSearchResult{
-			RecipeID:          recipeID,
-			CategoryIDs:       categoryID,
-			TagIDs:            tagID,
-			DifficultyLevelID: difficultyLevelID[0],
-			PreparationTimeID: preparationTimeID[0],
-			CuisineTypeID:     cuisineTypeID[0],
-		})
+	if request.DifficultyLevelID != nil {
+		query = query.Where("recipe_difficulty_levels.difficulty_level_id = ?", *request.DifficultyLevelID)
+	}
+
+	if request.MinPrepTime != nil {
+		query = query.Where("preparation_times.duration >= ?", *request.MinPrepTime)
+	}
+
+	if request.MaxPrepTime != nil {
+		query = query.Where("preparation_times.duration <= ?", *request.MaxPrepTime)
+	}
+
+	return query
+}
+
+// unique drops duplicate ids, as a recipe can have a tag only once
+func unique(ids []uuid.UUID) []uuid.UUID {
+	var result []uuid.UUID
+	seen := make(map[uuid.UUID]bool, len(ids))
+
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
 	}
 
-	return results, nil
+	return result
 }
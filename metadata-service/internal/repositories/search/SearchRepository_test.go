@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	m "metadata-service/internal/models"
 
@@ -16,17 +17,45 @@ import (
 
 var (
 	id      uuid.UUID = uuid.New()
+	tagID   uuid.UUID = uuid.New()
 	minprep int       = 1
 	maxprep int       = 2
 
+	from = `FROM "recipe_preparation_times" ` +
+		`JOIN preparation_times ON preparation_times.id = recipe_preparation_times.preparation_time_id ` +
+		`JOIN recipe_difficulty_levels ON recipe_difficulty_levels.recipe_id = recipe_preparation_times.recipe_id AND recipe_difficulty_levels.deleted_at IS NULL ` +
+		`JOIN recipe_cuisine_types ON recipe_cuisine_types.recipe_id = recipe_preparation_times.recipe_id AND recipe_cuisine_types.deleted_at IS NULL ` +
+		`LEFT JOIN recipes ON recipes.id = recipe_preparation_times.recipe_id`
+
+	filters = `WHERE (EXISTS (SELECT 1 FROM recipe_categories WHERE recipe_categories.recipe_id = recipe_preparation_times.recipe_id AND recipe_categories.category_id IN ($1))) ` +
+		`AND ((SELECT count(*) FROM recipe_tags WHERE recipe_tags.recipe_id = recipe_preparation_times.recipe_id AND recipe_tags.tag_id IN ($2,$3)) = $4) ` +
+		`AND recipe_cuisine_types.cuisine_type_id IN ($5) ` +
+		`AND recipe_difficulty_levels.difficulty_level_id = $6 ` +
+		`AND preparation_times.duration >= $7 ` +
+		`AND preparation_times.duration <= $8`
+
+	columns = `SELECT recipe_preparation_times.recipe_id, recipe_cuisine_types.cuisine_type_id, recipe_difficulty_levels.difficulty_level_id, ` +
+		`recipe_preparation_times.preparation_time_id, preparation_times.duration, COALESCE(recipes.name, '') AS name, ` +
+		`COALESCE(recipes.created_at, recipe_preparation_times.created_at) AS created_at `
+
+	newest = `COALESCE(recipes.created_at, recipe_preparation_times.created_at)`
+
 	searchRequest m.MetadataSearchRequest = m.MetadataSearchRequest{
-		RecipeID:          &id,
-		CategoryID:        &id,
-		TagID:             &id,
+		CategoryIDs:       []uuid.UUID{id},
+		TagIDs:            []uuid.UUID{id, tagID, id},
+		TagMode:           m.TagModeAll,
+		CuisineTypeIDs:    []uuid.UUID{id},
 		DifficultyLevelID: &id,
-		CuisineTypeID:     &id,
 		MinPrepTime:       &minprep,
 		MaxPrepTime:       &maxprep,
+		Sort:              m.SearchSortPrepTime,
+		Limit:             1,
+		Cursor:            &m.MetadataSearchCursor{Sort: m.SearchSortPrepTime, Duration: 1, RecipeID: id},
+	}
+
+	categoryID   uuid.UUID               = uuid.New()
+	facetRequest m.MetadataSearchRequest = m.MetadataSearchRequest{
+		CategoryIDs: []uuid.UUID{categoryID},
 	}
 )
 
@@ -34,52 +63,199 @@ func TestSearch_OK(t *testing.T) {
 	db, mock := co.NewMockDatabase(t)
 	r := NewSearchRepository(db)
 
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM "preparation_times" WHERE duration >= $1 AND duration <= $2`)).
-		WithArgs(
-			searchRequest.MinPrepTime,
-			searchRequest.MaxPrepTime,
-		).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
-
-	// Define expected query
-	mock.ExpectQuery(regexp.QuoteMeta(`
-        SELECT recipe_categories.recipe_id FROM "recipe_categories" 
-				LEFT JOIN recipe_tags ON recipe_categories.recipe_id = recipe_tags.recipe_id 
-				LEFT JOIN recipe_difficulty_levels ON recipe_categories.recipe_id = recipe_difficulty_levels.recipe_id 
-				LEFT JOIN recipe_preparation_times ON recipe_categories.recipe_id = recipe_preparation_times.recipe_id 
-				LEFT JOIN recipe_cuisine_types ON recipe_categories.recipe_id = recipe_cuisine_types.recipe_id 
-				WHERE recipe_categories.category_id = $1 
-				AND recipe_tags.tag_id = $2 
-				AND recipe_difficulty_levels.difficulty_level_id = $3 
-				AND recipe_cuisine_types.cuisine_type_id = $4 
-				AND recipe_preparation_times.preparation_time_id IN ($5) 
-				GROUP BY "recipe_categories"."recipe_id"
-    `)).
-		WithArgs(
-			searchRequest.CategoryID,
-			searchRequest.TagID,
-			searchRequest.DifficultyLevelID,
-			searchRequest.CuisineTypeID,
-			id,
-		).
-		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(id))
-
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "category_id" FROM "recipe_categories" WHERE recipe_id = $1`)).WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "tag_id" FROM "recipe_tags" WHERE recipe_id = $1`)).WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "difficulty_level_id" FROM "recipe_difficulty_levels" WHERE recipe_id = $1`)).WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "preparation_time_id" FROM "recipe_preparation_times" WHERE recipe_id = $1`)).WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "cuisine_type_id" FROM "recipe_cuisine_types" WHERE recipe_id = $1`)).WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+	first, second := uuid.New(), uuid.New()
+	created := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) `+from+` `+filters)).
+		WithArgs(id, id, tagID, 2, id, id, minprep, maxprep).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+	mock.ExpectQuery(regexp.QuoteMeta(columns+from+` `+filters+
+		` AND (preparation_times.duration, recipe_preparation_times.recipe_id) > ($9, $10) ORDER BY preparation_times.duration, recipe_preparation_times.recipe_id LIMIT $11`)).
+		WithArgs(id, id, tagID, 2, id, id, minprep, maxprep, 1, id, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "cuisine_type_id", "difficulty_level_id", "preparation_time_id", "duration", "created_at"}).
+			AddRow(first, id, id, id, 2, created).
+			AddRow(second, id, id, id, 2, created))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT recipe_id, category_id AS id FROM "recipe_categories" WHERE recipe_id IN ($1) ORDER BY created_at`)).
+		WithArgs(first).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "id"}).AddRow(first, id).AddRow(first, categoryID))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT recipe_id, tag_id AS id FROM "recipe_tags" WHERE recipe_id IN ($1) ORDER BY created_at`)).
+		WithArgs(first).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "id"}))
 
 	result, err := r.SearchMetadata(searchRequest)
 
 	assert.NoError(t, err)
-	assert.IsType(t, []m.MetadataSearchResult{}, result)
-	assert.Len(t, result, 1)
-	fmt.Printf("%+v\n", result[0])
-	assert.Len(t, result[0].CategoryIDs, 1)
-	assert.Len(t, result[0].TagIDs, 1)
-	assert.Equal(t, result[0].RecipeID, id)
-	assert.Equal(t, result[0].DifficultyLevelID, id)
-	assert.Equal(t, result[0].CuisineTypeID, id)
-	assert.Equal(t, result[0].PreparationTimeID, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, int64(5), result.Total)
+	assert.Len(t, result.Results, 1)
+	assert.Equal(t, first, result.Results[0].RecipeID)
+	assert.Equal(t, []uuid.UUID{id, categoryID}, result.Results[0].CategoryIDs)
+	assert.Empty(t, result.Results[0].TagIDs)
+	assert.Equal(t, id, result.Results[0].DifficultyLevelID)
+	assert.Equal(t, id, result.Results[0].CuisineTypeID)
+	assert.Equal(t, id, result.Results[0].PreparationTimeID)
+	assert.Equal(t, &m.MetadataSearchCursor{Sort: m.SearchSortPrepTime, CreatedAt: created, Duration: 2, RecipeID: first}, result.Next)
+}
+
+func TestSearch_NoFilters(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) ` + from)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	mock.ExpectQuery(regexp.QuoteMeta(columns + from + ` ORDER BY ` + newest + ` DESC, recipe_preparation_times.recipe_id DESC LIMIT $1`)).
+		WithArgs(21).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}))
+
+	result, err := r.SearchMetadata(m.MetadataSearchRequest{Sort: m.SearchSortNewest, Limit: 20})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, int64(0), result.Total)
+	assert.Empty(t, result.Results)
+	assert.Nil(t, result.Next)
+}
+
+func TestSearch_AnyTagAfterCursor(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db)
+
+	created := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) ` + from + ` WHERE EXISTS (SELECT 1 FROM recipe_tags WHERE recipe_tags.recipe_id = recipe_preparation_times.recipe_id AND recipe_tags.tag_id IN ($1))`)).
+		WithArgs(tagID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	mock.ExpectQuery(regexp.QuoteMeta(columns+from+` WHERE (EXISTS (SELECT 1 FROM recipe_tags WHERE recipe_tags.recipe_id = recipe_preparation_times.recipe_id AND recipe_tags.tag_id IN ($1))) `+
+		`AND (`+newest+`, recipe_preparation_times.recipe_id) < ($2, $3) ORDER BY `+newest+` DESC, recipe_preparation_times.recipe_id DESC LIMIT $4`)).
+		WithArgs(tagID, created, id, 11).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}))
+
+	result, err := r.SearchMetadata(m.MetadataSearchRequest{
+		TagIDs:  []uuid.UUID{tagID},
+		TagMode: m.TagModeAny,
+		Sort:    m.SearchSortNewest,
+		Limit:   10,
+		Cursor:  &m.MetadataSearchCursor{Sort: m.SearchSortNewest, CreatedAt: created, RecipeID: id},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Empty(t, result.Results)
+	assert.Nil(t, result.Next)
+}
+
+func TestSearch_NameAfterCursor(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db)
+
+	first, second := uuid.New(), uuid.New()
+	created := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) ` + from)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	mock.ExpectQuery(regexp.QuoteMeta(columns+from+` WHERE (COALESCE(recipes.name, ''), recipe_preparation_times.recipe_id) > ($1, $2) `+
+		`ORDER BY COALESCE(recipes.name, ''), recipe_preparation_times.recipe_id LIMIT $3`)).
+		WithArgs("apple pie", id, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "cuisine_type_id", "difficulty_level_id", "preparation_time_id", "duration", "name", "created_at"}).
+			AddRow(first, id, id, id, 10, "banana bread", created).
+			AddRow(second, id, id, id, 20, "carrot cake", created))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT recipe_id, category_id AS id FROM "recipe_categories" WHERE recipe_id IN ($1) ORDER BY created_at`)).
+		WithArgs(first).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT recipe_id, tag_id AS id FROM "recipe_tags" WHERE recipe_id IN ($1) ORDER BY created_at`)).
+		WithArgs(first).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "id"}))
+
+	result, err := r.SearchMetadata(m.MetadataSearchRequest{
+		Sort:   m.SearchSortName,
+		Limit:  1,
+		Cursor: &m.MetadataSearchCursor{Sort: m.SearchSortName, Name: "apple pie", RecipeID: id},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, result.Results, 1)
+	assert.Equal(t, first, result.Results[0].RecipeID)
+	assert.Equal(t, &m.MetadataSearchCursor{Sort: m.SearchSortName, CreatedAt: created, Duration: 10, Name: "banana bread", RecipeID: first}, result.Next)
+}
+
+func TestSearch_Err(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*)`)).
+		WillReturnError(fmt.Errorf("database error"))
+
+	_, err := r.SearchMetadata(searchRequest)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "database error")
+}
+
+func TestSearchFacets_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db)
+
+	recipes := `SELECT recipe_preparation_times.recipe_id ` + from + ` WHERE EXISTS (SELECT 1 FROM recipe_categories WHERE recipe_categories.recipe_id = recipe_preparation_times.recipe_id AND recipe_categories.category_id IN ($1))`
+
+	facets := []struct {
+		association string
+		table       string
+		column      string
+		name        string
+	}{
+		{"recipe_categories", "categories", "category_id", "categories.name"},
+		{"recipe_tags", "tags", "tag_id", "tags.name"},
+		{"recipe_cuisine_types", "cuisine_types", "cuisine_type_id", "cuisine_types.name"},
+		{"recipe_difficulty_levels", "difficulty_levels", "difficulty_level_id", "CAST(difficulty_levels.level AS text)"},
+	}
+
+	for _, f := range facets {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT ` + f.table + `.id, ` + f.name + ` AS name, count(DISTINCT ` + f.association + `.recipe_id) AS count FROM "` + f.association + `" JOIN ` + f.table + ` ON ` + f.table + `.id = ` + f.association + `.` + f.column + ` WHERE ` + f.association + `.recipe_id IN (` + recipes + `) AND ` + f.table + `.deleted_at IS NULL GROUP BY "` + f.table + `"."id" ORDER BY count DESC, name`)).
+			WithArgs(categoryID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "count"}).AddRow(id, "Dinner", 2).AddRow(uuid.New(), "Lunch", 1))
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT preparation_times.duration, count(DISTINCT recipe_preparation_times.recipe_id) AS count FROM "recipe_preparation_times" JOIN preparation_times ON preparation_times.id = recipe_preparation_times.preparation_time_id WHERE recipe_preparation_times.recipe_id IN (` + recipes + `) GROUP BY "preparation_times"."duration"`)).
+		WithArgs(categoryID).
+		WillReturnRows(sqlmock.NewRows([]string{"duration", "count"}).AddRow(10, 1).AddRow(15, 2).AddRow(45, 1).AddRow(90, 3))
+
+	result, err := r.SearchFacets(facetRequest)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, result.Categories, 2)
+	assert.Len(t, result.Tags, 2)
+	assert.Len(t, result.CuisineTypes, 2)
+	assert.Len(t, result.DifficultyLevels, 2)
+	assert.Equal(t, m.MetadataFacet{ID: id, Name: "Dinner", Count: 2}, result.Categories[0])
+
+	assert.Len(t, result.PreparationTimes, len(m.PreparationTimeBuckets))
+	assert.Equal(t, int64(3), result.PreparationTimes[0].Count)
+	assert.Equal(t, int64(0), result.PreparationTimes[1].Count)
+	assert.Equal(t, int64(1), result.PreparationTimes[2].Count)
+	assert.Equal(t, int64(3), result.PreparationTimes[3].Count)
+	assert.Equal(t, int64(0), m.PreparationTimeBuckets[0].Count)
+}
+
+func TestSearchFacets_Err(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT categories.id`)).
+		WithArgs(categoryID).
+		WillReturnError(fmt.Errorf("database error"))
+
+	_, err := r.SearchFacets(facetRequest)
 
+	assert.Error(t, err)
+	assert.EqualError(t, err, "database error")
 }
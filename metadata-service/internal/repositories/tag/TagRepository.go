@@ -3,9 +3,14 @@ package repositories
 import (
 	"errors"
 
+	"cookbook/pkg/normalize"
+	"cookbook/pkg/outbox"
 	m "metadata-service/internal/models"
+	"metadata-service/internal/repositories/references"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TagRepository struct {
@@ -21,7 +26,7 @@ func NewTagRepository(db *gorm.DB) *TagRepository {
 func (r *TagRepository) FindAll() ([]m.Tag, error) {
 	var tags []m.Tag
 
-	if err := r.db.Find(&tags).Error; err != nil {
+	if err := r.db.Preload("Aliases", orderAliases).Find(&tags).Error; err != nil {
 		return nil, err
 	}
 
@@ -34,7 +39,7 @@ func (r *TagRepository) FindAll() ([]m.Tag, error) {
 
 func (r *TagRepository) FindSingle(tag m.Tag) (m.Tag, error) {
 
-	result := r.db.First(&tag)
+	result := r.db.Preload("Aliases", orderAliases).First(&tag)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return m.Tag{}, errors.New("not found")
@@ -46,49 +51,299 @@ func (r *TagRepository) FindSingle(tag m.Tag) (m.Tag, error) {
 	return tag, nil
 }
 
+// FindByName finds the tag a name refers to, by its normalized name or one of its aliases
+func (r *TagRepository) FindByName(name string) (m.Tag, error) {
+	var tag m.Tag
+
+	normalized := normalize.Name(name)
+
+	result := r.db.Preload("Aliases", orderAliases).
+		Where("normalized_name = ?", normalized).
+		Or("id IN (?)", r.db.Model(&m.TagAlias{}).Select("tag_id").Where("normalized_name = ?", normalized)).
+		First(&tag)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return m.Tag{}, errors.New("not found")
+		} else {
+			return m.Tag{}, result.Error
+		}
+	}
+
+	return tag, nil
+}
+
+// Create stores a tag with its aliases. An "already exists" error is returned when the name or an alias is
+// taken by another tag.
 func (r *TagRepository) Create(tag m.Tag) (m.Tag, error) {
 
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
 		var err error
 
-		if err = tx.Create(&tag).Error; err != nil {
+		if err = tx.Omit("Aliases").Create(&tag).Error; err != nil {
 			return err
 		}
 
-		return nil
-	}); err != nil {
+		if err = createTagAliases(tx, &tag); err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventCreated, m.EntityTag, tag.ID))
+	}); errors.Is(err, gorm.ErrDuplicatedKey) {
+		return m.Tag{}, errors.New("already exists")
+	} else if err != nil {
 		return m.Tag{}, err
 	}
 
 	return tag, nil
 }
 
+// Update stores the changes to a tag, replacing its aliases when they are given. An "already exists" error is
+// returned when the name or an alias is taken by another tag.
 func (r *TagRepository) Update(tag m.Tag) (m.Tag, error) {
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
 		var err error
 
-		if err = tx.Updates(&tag).Error; err != nil {
+		// plural is selected explicitly, as Updates would skip it when it is reset
+		if err = tx.Select("name", "normalized_name", "plural").Updates(&tag).Error; err != nil {
 			return err
 		}
-		return nil
-	}); err != nil {
+
+		// without aliases given the ones the tag has are kept
+		if tag.Aliases != nil {
+			if err = tx.Where("tag_id = ?", tag.ID).Delete(&m.TagAlias{}).Error; err != nil {
+				return err
+			}
+
+			if err = createTagAliases(tx, &tag); err != nil {
+				return err
+			}
+		}
+		return outbox.Record(tx, m.NewChangeEvent(m.EventUpdated, m.EntityTag, tag.ID))
+	}); errors.Is(err, gorm.ErrDuplicatedKey) {
+		return m.Tag{}, errors.New("already exists")
+	} else if err != nil {
 		return m.Tag{}, err
 	}
 
 	return tag, nil
 }
 
+// Delete removes a tag that is not used by any recipe, otherwise an InUseError is returned. Its aliases are
+// removed as well, so the names can be used again.
 func (r *TagRepository) Delete(tag m.Tag) error {
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
 
+		usage, err := references.Usage(tx, &m.RecipeTag{}, "tag_id", tag.ID)
+		if err != nil {
+			return err
+		}
+
+		if usage.InUse() {
+			return m.NewInUseError("tag", usage)
+		}
+
+		if err := tx.Where("tag_id = ?", tag.ID).Delete(&m.TagAlias{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&tag).Error; err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventDeleted, m.EntityTag, tag.ID))
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reassign moves the recipes associated with a tag to the replacement and removes the tag and its aliases
+// afterwards
+func (r *TagRepository) Reassign(tag m.Tag, replacement m.Tag) error {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.First(&replacement).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("replacement not found")
+			}
+			return err
+		}
+
+		recipeIDs, err := references.Reassign(tx, &m.RecipeTag{}, "tag_id", tag.ID, replacement.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("tag_id = ?", tag.ID).Delete(&m.TagAlias{}).Error; err != nil {
+			return err
+		}
+
 		if err := tx.Delete(&tag).Error; err != nil {
 			return err
 		}
 
-		return nil
+		events := []m.ChangeEvent{m.NewChangeEvent(m.EventDeleted, m.EntityTag, tag.ID)}
+		for _, recipeID := range recipeIDs {
+			events = append(events, m.NewChangeEvent(m.EventUpdated, m.EntityRecipeMetadata, recipeID))
+		}
+
+		return outbox.Record(tx, events...)
 	}); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// Merge folds duplicate tags into the target. The recipes associated with the sources are moved to the target,
+// their names and aliases become aliases of the target and the sources are removed, all at once. With preview
+// nothing is changed, the result only tells what the merge would do.
+func (r *TagRepository) Merge(target m.Tag, sources []m.Tag, preview bool) (m.MergeResult, error) {
+	var result m.MergeResult
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.First(&target).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("target not found")
+			}
+			return err
+		}
+
+		var sourceIDs []uuid.UUID
+		for _, source := range sources {
+			sourceIDs = append(sourceIDs, source.ID)
+		}
+
+		sources = nil
+		if err := tx.Where("id IN ?", sourceIDs).Order("name").Find(&sources).Error; err != nil {
+			return err
+		}
+
+		if len(sources) != len(sourceIDs) {
+			return errors.New("source not found")
+		}
+
+		result = m.MergeResult{TargetID: target.ID}
+		for _, source := range sources {
+			result.SourceIDs = append(result.SourceIDs, source.ID)
+			result.Aliases = append(result.Aliases, source.Name)
+		}
+
+		seen := map[uuid.UUID]bool{}
+		for _, source := range sources {
+			recipeIDs, err := references.Recipes(tx, &m.RecipeTag{}, "tag_id", source.ID)
+			if err != nil {
+				return err
+			}
+
+			for _, recipeID := range recipeIDs {
+				if !seen[recipeID] {
+					seen[recipeID] = true
+					result.RecipeIDs = append(result.RecipeIDs, recipeID)
+				}
+			}
+		}
+
+		if preview {
+			return nil
+		}
+
+		events := []m.ChangeEvent{m.NewChangeEvent(m.EventUpdated, m.EntityTag, target.ID)}
+		for _, source := range sources {
+			if _, err := references.Reassign(tx, &m.RecipeTag{}, "tag_id", source.ID, target.ID); err != nil {
+				return err
+			}
+
+			if err := tx.Model(&m.TagAlias{}).Where("tag_id = ?", source.ID).Update("tag_id", target.ID).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Delete(&source).Error; err != nil {
+				return err
+			}
+
+			events = append(events, m.NewChangeEvent(m.EventDeleted, m.EntityTag, source.ID))
+		}
+
+		var aliases []m.TagAlias
+		for _, name := range result.Aliases {
+			if alias := m.NewTagAlias(name, target.ID); alias.NormalizedName != target.NormalizedName {
+				aliases = append(aliases, alias)
+			}
+		}
+
+		// a name that already is an alias keeps pointing where it does
+		if len(aliases) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&aliases).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, recipeID := range result.RecipeIDs {
+			events = append(events, m.NewChangeEvent(m.EventUpdated, m.EntityRecipeMetadata, recipeID))
+		}
+
+		return outbox.Record(tx, events...)
+	}); err != nil {
+		return m.MergeResult{}, err
+	}
+
+	return result, nil
+}
+
+// NormalizeNames sets the normalized name of the tags stored before names were normalized. A tag whose
+// normalized name is already taken is left as it is to be merged.
+func (r *TagRepository) NormalizeNames() (int, error) {
+	var tags []m.Tag
+
+	if err := r.db.Unscoped().Where("normalized_name = ''").Find(&tags).Error; err != nil {
+		return 0, err
+	}
+
+	normalized := 0
+	for _, tag := range tags {
+		err := r.db.Unscoped().Model(&tag).UpdateColumn("normalized_name", normalize.Name(tag.Name)).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+
+		normalized++
+	}
+
+	return normalized, nil
+}
+
+// createTagAliases stores the aliases of a tag. An alias is keyed by its normalized name, so names that
+// normalize to the name of the tag or to an earlier alias are stored only once.
+func createTagAliases(tx *gorm.DB, tag *m.Tag) error {
+	if len(tag.Aliases) == 0 {
+		return nil
+	}
+
+	aliases := []m.TagAlias{}
+	seen := map[string]bool{normalize.Name(tag.Name): true}
+	for _, alias := range tag.Aliases {
+		alias = m.NewTagAlias(alias.Name, tag.ID)
+		if alias.NormalizedName == "" || seen[alias.NormalizedName] {
+			continue
+		}
+
+		seen[alias.NormalizedName] = true
+		aliases = append(aliases, alias)
+	}
+
+	tag.Aliases = aliases
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	return tx.Create(&tag.Aliases).Error
+}
+
+func orderAliases(db *gorm.DB) *gorm.DB {
+	return db.Order("name")
+}
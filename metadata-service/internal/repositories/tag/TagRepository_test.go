@@ -9,24 +9,38 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 
 	co "metadata-service/internal/common/test"
 )
 
 var (
 	tag m.Tag = m.Tag{
-		ID:   uuid.New(),
-		Name: "tag",
+		ID:             uuid.New(),
+		Name:           "tag",
+		NormalizedName: "tag",
 	}
 )
 
+func expectTagAliases(mock sqlmock.Sqlmock, names ...string) {
+	rows := sqlmock.NewRows([]string{"normalized_name", "name", "tag_id"})
+	for _, name := range names {
+		rows.AddRow(tag.NormalizedName, name, tag.ID)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tag_aliases" WHERE "tag_aliases"."tag_id" = $1 ORDER BY name`)).
+		WithArgs(tag.ID).
+		WillReturnRows(rows)
+}
+
 func TestTagFindAll_OK(t *testing.T) {
 	db, mock := co.NewMockDatabase(t)
 	r := NewTagRepository(db)
 
 	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" WHERE "tags"."deleted_at" IS NULL`)).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(tag.ID, tag.Name))
+	expectTagAliases(mock, "tags")
 
 	result, err := r.FindAll()
 
@@ -37,6 +51,8 @@ func TestTagFindAll_OK(t *testing.T) {
 		t.Errorf("expected tag name %v, but got %v", tag.Name, result[0].Name)
 	}
 
+	assert.Equal(t, "tags", result[0].Aliases[0].Name)
+
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %v", err)
 	}
@@ -66,6 +82,7 @@ func TestTagFindSingle_OK(t *testing.T) {
 			1,
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(tag.ID, tag.Name))
+	expectTagAliases(mock)
 
 	result, err := r.FindSingle(tag)
 
@@ -96,9 +113,11 @@ func TestTagCreate_OK(t *testing.T) {
 	r := NewTagRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "tags" ("name","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "tags" ("name","normalized_name","plural","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
 		WithArgs(
 			tag.Name,
+			tag.NormalizedName,
+			tag.Plural,
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
@@ -107,6 +126,7 @@ func TestTagCreate_OK(t *testing.T) {
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "name"}).AddRow(tag.ID, tag.Name),
 		)
+	co.ExpectOutbox(mock, m.EntityTag, tag.ID, m.EventCreated)
 	mock.ExpectCommit()
 
 	result, err := r.Create(tag)
@@ -114,6 +134,10 @@ func TestTagCreate_OK(t *testing.T) {
 	assert.NoError(t, err)
 	assert.IsType(t, m.Tag{}, result)
 	assert.Equal(t, tag.ID, result.ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
 }
 
 func TestTagCreate_Err(t *testing.T) {
@@ -121,9 +145,11 @@ func TestTagCreate_Err(t *testing.T) {
 	r := NewTagRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "tags" ("name","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "tags" ("name","normalized_name","plural","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
 		WithArgs(
 			tag.Name,
+			tag.NormalizedName,
+			tag.Plural,
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
@@ -139,17 +165,35 @@ func TestTagCreate_Err(t *testing.T) {
 
 }
 
+func TestTagCreate_Duplicate(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "tags" ("name","normalized_name","plural","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err := r.Create(tag)
+
+	assert.EqualError(t, err, "already exists")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestTagUpdate_OK(t *testing.T) {
 	db, mock := co.NewMockDatabase(t)
 	r := NewTagRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tags" SET "name"=$1,"updated_at"=$2 WHERE "tags"."deleted_at" IS NULL AND "id" = $3`)).
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tags" SET "name"=$1,"normalized_name"=$2,"plural"=$3,"updated_at"=$4 WHERE "tags"."deleted_at" IS NULL AND "id" = $5`)).
 		WithArgs(
 			tag.Name,
+			tag.NormalizedName,
+			tag.Plural,
 			sqlmock.AnyArg(),
 			tag.ID,
 		).WillReturnResult(sqlmock.NewResult(1, 1))
+	co.ExpectOutbox(mock, m.EntityTag, tag.ID, m.EventUpdated)
 	mock.ExpectCommit()
 
 	result, err := r.Update(tag)
@@ -158,6 +202,9 @@ func TestTagUpdate_OK(t *testing.T) {
 	assert.IsType(t, m.Tag{}, result)
 	assert.Equal(t, tag.ID, result.ID)
 
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
 }
 
 func TestTagUpdate_Err(t *testing.T) {
@@ -165,9 +212,11 @@ func TestTagUpdate_Err(t *testing.T) {
 	r := NewTagRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tags" SET "name"=$1,"updated_at"=$2 WHERE "tags"."deleted_at" IS NULL AND "id" = $3`)).
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tags" SET "name"=$1,"normalized_name"=$2,"plural"=$3,"updated_at"=$4 WHERE "tags"."deleted_at" IS NULL AND "id" = $5`)).
 		WithArgs(
 			tag.Name,
+			tag.NormalizedName,
+			tag.Plural,
 			sqlmock.AnyArg(),
 			tag.ID,
 		).WillReturnError(errors.New("error"))
@@ -181,21 +230,45 @@ func TestTagUpdate_Err(t *testing.T) {
 
 }
 
+func TestTagUpdate_Duplicate(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tags" SET "name"=$1,"normalized_name"=$2,"plural"=$3,"updated_at"=$4 WHERE "tags"."deleted_at" IS NULL AND "id" = $5`)).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err := r.Update(tag)
+
+	assert.EqualError(t, err, "already exists")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestTagDelete_OK(t *testing.T) {
 	db, mock := co.NewMockDatabase(t)
 	r := NewTagRepository(db)
 
 	mock.ExpectBegin()
+	co.ExpectUsage(mock, "recipe_tags", "tag_id", tag.ID, nil, nil)
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "tag_aliases" WHERE tag_id = $1`)).
+		WithArgs(tag.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tags" SET "deleted_at"=$1 WHERE "tags"."id" = $2 AND "tags"."deleted_at" IS NULL`)).
 		WithArgs(
 			sqlmock.AnyArg(),
 			tag.ID,
 		).WillReturnResult(sqlmock.NewResult(1, 1))
+	co.ExpectOutbox(mock, m.EntityTag, tag.ID, m.EventDeleted)
 	mock.ExpectCommit()
 
 	err := r.Delete(tag)
 
 	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
 }
 
 func TestTagDelete_Err(t *testing.T) {
@@ -203,6 +276,10 @@ func TestTagDelete_Err(t *testing.T) {
 	r := NewTagRepository(db)
 
 	mock.ExpectBegin()
+	co.ExpectUsage(mock, "recipe_tags", "tag_id", tag.ID, nil, nil)
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "tag_aliases" WHERE tag_id = $1`)).
+		WithArgs(tag.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tags" SET "deleted_at"=$1 WHERE "tags"."id" = $2 AND "tags"."deleted_at" IS NULL`)).
 		WithArgs(
 			sqlmock.AnyArg(),
@@ -216,3 +293,375 @@ func TestTagDelete_Err(t *testing.T) {
 	assert.EqualError(t, err, "error")
 
 }
+
+func TestTagDelete_InUseErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	co.ExpectUsage(mock, "recipe_tags", "tag_id", tag.ID, []uuid.UUID{recipeID}, nil)
+	mock.ExpectRollback()
+
+	err := r.Delete(tag)
+
+	var inUse m.InUseError
+	assert.ErrorAs(t, err, &inUse)
+	assert.EqualError(t, err, "tag is used by 1 recipes")
+	assert.Equal(t, []uuid.UUID{recipeID}, inUse.Usage.RecipeIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagDelete_DeletedRecipesErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	co.ExpectUsage(mock, "recipe_tags", "tag_id", tag.ID, nil, []uuid.UUID{recipeID})
+	mock.ExpectRollback()
+
+	err := r.Delete(tag)
+
+	var inUse m.InUseError
+	assert.ErrorAs(t, err, &inUse)
+	assert.EqualError(t, err, "tag is used by 1 deleted recipes that can still be restored")
+	assert.Equal(t, []uuid.UUID{recipeID}, inUse.Usage.DeletedRecipeIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagReassign_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	replacement := m.Tag{ID: uuid.New()}
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" WHERE "tags"."deleted_at" IS NULL AND "tags"."id" = $1 ORDER BY "tags"."id" LIMIT $2`)).
+		WithArgs(replacement.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(replacement.ID))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "recipe_tags" WHERE tag_id = $1`)).
+		WithArgs(tag.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(recipeID))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_tags" WHERE tag_id = $1 AND recipe_id IN (SELECT "recipe_id" FROM "recipe_tags" WHERE tag_id = $2)`)).
+		WithArgs(tag.ID, replacement.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_tags" SET "tag_id"=$1 WHERE tag_id = $2`)).
+		WithArgs(replacement.ID, tag.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "tag_aliases" WHERE tag_id = $1`)).
+		WithArgs(tag.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tags" SET "deleted_at"=$1 WHERE "tags"."id" = $2 AND "tags"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), tag.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8),($9,$10,$11,$12,$13,$14,$15,$16) RETURNING "sequence"`)).
+		WithArgs(
+			sqlmock.AnyArg(), m.EntityTag, tag.ID, m.EventDeleted, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+			sqlmock.AnyArg(), m.EntityRecipeMetadata, recipeID, m.EventUpdated, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1).AddRow(2))
+	mock.ExpectCommit()
+
+	err := r.Reassign(tag, replacement)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagReassign_ReplacementNotFoundErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	replacement := m.Tag{ID: uuid.New()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" WHERE "tags"."deleted_at" IS NULL AND "tags"."id" = $1 ORDER BY "tags"."id" LIMIT $2`)).
+		WithArgs(replacement.ID, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectRollback()
+
+	err := r.Reassign(tag, replacement)
+
+	assert.EqualError(t, err, "replacement not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func expectTagMergeLookup(mock sqlmock.Sqlmock, target m.Tag, sources ...m.Tag) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" WHERE "tags"."deleted_at" IS NULL AND "tags"."id" = $1 ORDER BY "tags"."id" LIMIT $2`)).
+		WithArgs(target.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(target.ID, target.Name))
+
+	rows := sqlmock.NewRows([]string{"id", "name"})
+	for _, source := range sources {
+		rows.AddRow(source.ID, source.Name)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" WHERE id IN ($1) AND "tags"."deleted_at" IS NULL ORDER BY name`)).
+		WithArgs(tag.ID).
+		WillReturnRows(rows)
+}
+
+func TestTagMerge_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	target := m.Tag{ID: uuid.New(), Name: "target"}
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	expectTagMergeLookup(mock, target, tag)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "recipe_tags" WHERE tag_id = $1`)).
+		WithArgs(tag.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(recipeID))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "recipe_tags" WHERE tag_id = $1`)).
+		WithArgs(tag.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(recipeID))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_tags" WHERE tag_id = $1 AND recipe_id IN (SELECT "recipe_id" FROM "recipe_tags" WHERE tag_id = $2)`)).
+		WithArgs(tag.ID, target.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_tags" SET "tag_id"=$1 WHERE tag_id = $2`)).
+		WithArgs(target.ID, tag.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tag_aliases" SET "tag_id"=$1 WHERE tag_id = $2`)).
+		WithArgs(target.ID, tag.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tags" SET "deleted_at"=$1 WHERE "tags"."id" = $2 AND "tags"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), tag.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "tag_aliases" ("normalized_name","name","tag_id","created_at") VALUES ($1,$2,$3,$4) ON CONFLICT DO NOTHING`)).
+		WithArgs(tag.NormalizedName, tag.Name, target.ID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8),($9,$10,$11,$12,$13,$14,$15,$16),($17,$18,$19,$20,$21,$22,$23,$24) RETURNING "sequence"`)).
+		WithArgs(
+			sqlmock.AnyArg(), m.EntityTag, target.ID, m.EventUpdated, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+			sqlmock.AnyArg(), m.EntityTag, tag.ID, m.EventDeleted, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+			sqlmock.AnyArg(), m.EntityRecipeMetadata, recipeID, m.EventUpdated, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "",
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1).AddRow(2).AddRow(3))
+	mock.ExpectCommit()
+
+	result, err := r.Merge(target, []m.Tag{{ID: tag.ID}}, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, target.ID, result.TargetID)
+	assert.Equal(t, []uuid.UUID{tag.ID}, result.SourceIDs)
+	assert.Equal(t, []string{tag.Name}, result.Aliases)
+	assert.Equal(t, []uuid.UUID{recipeID}, result.RecipeIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagMerge_Preview(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	target := m.Tag{ID: uuid.New(), Name: "target"}
+	recipeID := uuid.New()
+
+	mock.ExpectBegin()
+	expectTagMergeLookup(mock, target, tag)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "recipe_id" FROM "recipe_tags" WHERE tag_id = $1`)).
+		WithArgs(tag.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(recipeID))
+	mock.ExpectCommit()
+
+	result, err := r.Merge(target, []m.Tag{{ID: tag.ID}}, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{tag.Name}, result.Aliases)
+	assert.Equal(t, []uuid.UUID{recipeID}, result.RecipeIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagMerge_TargetNotFoundErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	target := m.Tag{ID: uuid.New()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" WHERE "tags"."deleted_at" IS NULL AND "tags"."id" = $1 ORDER BY "tags"."id" LIMIT $2`)).
+		WithArgs(target.ID, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectRollback()
+
+	_, err := r.Merge(target, []m.Tag{{ID: tag.ID}}, false)
+
+	assert.EqualError(t, err, "target not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagMerge_SourceNotFoundErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	target := m.Tag{ID: uuid.New(), Name: "target"}
+
+	mock.ExpectBegin()
+	expectTagMergeLookup(mock, target)
+	mock.ExpectRollback()
+
+	_, err := r.Merge(target, []m.Tag{{ID: tag.ID}}, false)
+
+	assert.EqualError(t, err, "source not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagFindByName_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" WHERE (normalized_name = $1 OR id IN (SELECT "tag_id" FROM "tag_aliases" WHERE normalized_name = $2)) AND "tags"."deleted_at" IS NULL ORDER BY "tags"."id" LIMIT $3`)).
+		WithArgs("tag", "tag", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "normalized_name"}).AddRow(tag.ID, tag.Name, tag.NormalizedName))
+	expectTagAliases(mock)
+
+	result, err := r.FindByName(" Tags")
+
+	assert.NoError(t, err)
+	assert.Equal(t, tag.ID, result.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagFindByName_NotFoundErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" WHERE (normalized_name = $1 OR id IN (SELECT "tag_id" FROM "tag_aliases" WHERE normalized_name = $2)) AND "tags"."deleted_at" IS NULL ORDER BY "tags"."id" LIMIT $3`)).
+		WithArgs("vegetable", "vegetable", 1).
+		WillReturnRows(&sqlmock.Rows{})
+
+	_, err := r.FindByName("Vegetables")
+
+	assert.EqualError(t, err, "not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagCreate_Aliases(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	input := m.TagDTO{Name: "Vegetable", Plural: "vegetables", Aliases: []string{"Vegetables", "veggie"}}.ConvertFromDTO()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "tags" ("name","normalized_name","plural","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
+		WithArgs("Vegetable", "vegetable", "vegetables", sqlmock.AnyArg(), sqlmock.AnyArg(), nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(tag.ID))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "tag_aliases" ("normalized_name","name","tag_id","created_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs("veggie", "veggie", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1))
+	mock.ExpectCommit()
+
+	result, err := r.Create(input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"veggie"}, result.ConvertToDTO().Aliases)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagCreate_DuplicateAliases(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	input := m.Tag{Name: "Vegetable", NormalizedName: "vegetable", Aliases: []m.TagAlias{
+		m.NewTagAlias("Veggie", uuid.Nil),
+		m.NewTagAlias("veggie", uuid.Nil),
+		m.NewTagAlias("VEGETABLES", uuid.Nil),
+	}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "tags"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(tag.ID))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "tag_aliases" ("normalized_name","name","tag_id","created_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs("veggie", "Veggie", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1))
+	mock.ExpectCommit()
+
+	result, err := r.Create(input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Veggie"}, result.ConvertToDTO().Aliases)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagUpdate_Aliases(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	input := tag
+	input.Aliases = []m.TagAlias{m.NewTagAlias("label", tag.ID)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tags" SET "name"=$1,"normalized_name"=$2,"plural"=$3,"updated_at"=$4 WHERE "tags"."deleted_at" IS NULL AND "id" = $5`)).
+		WithArgs(tag.Name, tag.NormalizedName, tag.Plural, sqlmock.AnyArg(), tag.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "tag_aliases" WHERE tag_id = $1`)).
+		WithArgs(tag.ID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "tag_aliases" ("normalized_name","name","tag_id","created_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs("label", "label", tag.ID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	co.ExpectOutbox(mock, m.EntityTag, tag.ID, m.EventUpdated)
+	mock.ExpectCommit()
+
+	_, err := r.Update(input)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagNormalizeNames_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" WHERE normalized_name = ''`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(tag.ID, "Vegetables"))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tags" SET "normalized_name"=$1 WHERE "id" = $2`)).
+		WithArgs("vegetable", tag.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	normalized, err := r.NormalizeNames()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, normalized)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagNormalizeNames_Duplicate(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" WHERE normalized_name = ''`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(tag.ID, "Vegetables"))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tags" SET "normalized_name"=$1 WHERE "id" = $2`)).
+		WithArgs("vegetable", tag.ID).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	normalized, err := r.NormalizeNames()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, normalized)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagNormalizeNames_Err(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewTagRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" WHERE normalized_name = ''`)).
+		WillReturnError(errors.New("error"))
+
+	_, err := r.NormalizeNames()
+
+	assert.EqualError(t, err, "error")
+}
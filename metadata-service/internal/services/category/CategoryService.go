@@ -11,9 +11,12 @@ import (
 type CategoryRepository interface {
 	FindAll() ([]m.Category, error)
 	FindSingle(recipe m.Category) (m.Category, error)
+	FindByName(name string) (m.Category, error)
 	Create(recipe m.Category) (m.Category, error)
 	Update(recipe m.Category) (m.Category, error)
 	Delete(recipe m.Category) error
+	Reassign(category m.Category, replacement m.Category) error
+	Merge(target m.Category, sources []m.Category, preview bool) (m.MergeResult, error)
 }
 type CategoryService struct {
 	repo CategoryRepository
@@ -57,18 +60,51 @@ func (s CategoryService) FindSingle(categoryDTO m.CategoryDTO) (m.CategoryDTO, e
 	return category.ConvertToDTO(), nil
 }
 
+// Lookup finds the category a name refers to, by its normalized name or one of its aliases
+func (s CategoryService) Lookup(name string) (m.CategoryDTO, error) {
+
+	category, err := s.repo.FindByName(name)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return m.CategoryDTO{}, err
+		default:
+			return m.CategoryDTO{}, errors.New("internal server error")
+		}
+	}
+
+	return category.ConvertToDTO(), nil
+}
+
+// Create adds a category. When the name already refers to a category, that one is returned along with a
+// "category already exists" error instead of adding a duplicate.
 func (s CategoryService) Create(categoryDTO m.CategoryDTO) (m.CategoryDTO, error) {
 
 	if categoryDTO.ID != uuid.Nil {
 		return m.CategoryDTO{}, errors.New("existing id on new element is not allowed")
 	}
 
-	if categoryDTO.Name == "" {
+	category := categoryDTO.ConvertFromDTO()
+	if category.NormalizedName == "" {
 		return m.CategoryDTO{}, errors.New("name is empty")
 	}
 
-	category, err := s.repo.Create(categoryDTO.ConvertFromDTO())
-	if err != nil {
+	found, err := s.repo.FindByName(category.Name)
+	if err == nil {
+		return found.ConvertToDTO(), errors.New("category already exists")
+	} else if err.Error() != "not found" {
+		return m.CategoryDTO{}, errors.New("internal server error")
+	}
+
+	if err := s.checkAliases(category); err != nil {
+		return m.CategoryDTO{}, err
+	}
+
+	category, err = s.repo.Create(category)
+	if err != nil && err.Error() == "already exists" {
+		found, err := s.conflict(category)
+		return found.ConvertToDTO(), err
+	} else if err != nil {
 		return m.CategoryDTO{}, err
 	}
 
@@ -77,12 +113,27 @@ func (s CategoryService) Create(categoryDTO m.CategoryDTO) (m.CategoryDTO, error
 
 func (s CategoryService) Update(categoryDTO m.CategoryDTO) (m.CategoryDTO, error) {
 
-	if categoryDTO.Name == "" {
+	category := categoryDTO.ConvertFromDTO()
+	if category.NormalizedName == "" {
 		return m.CategoryDTO{}, errors.New("name is empty")
 	}
 
-	category, err := s.repo.Update(categoryDTO.ConvertFromDTO())
-	if err != nil {
+	found, err := s.repo.FindByName(category.Name)
+	if err == nil && found.ID != category.ID {
+		return m.CategoryDTO{}, errors.New("category already exists")
+	} else if err != nil && err.Error() != "not found" {
+		return m.CategoryDTO{}, errors.New("internal server error")
+	}
+
+	if err := s.checkAliases(category); err != nil {
+		return m.CategoryDTO{}, err
+	}
+
+	category, err = s.repo.Update(category)
+	if err != nil && err.Error() == "already exists" {
+		_, err := s.conflict(category)
+		return m.CategoryDTO{}, err
+	} else if err != nil {
 		return m.CategoryDTO{}, err
 	}
 
@@ -98,3 +149,78 @@ func (s CategoryService) Delete(categoryDTO m.CategoryDTO) error {
 
 	return nil
 }
+
+// Reassign moves the recipes associated with a category to the replacement before deleting it
+func (s CategoryService) Reassign(categoryDTO m.CategoryDTO, replacementDTO m.CategoryDTO) error {
+
+	if replacementDTO.ID == categoryDTO.ID {
+		return errors.New("category cannot be reassigned to itself")
+	}
+
+	err := s.repo.Reassign(categoryDTO.ConvertFromDTO(), replacementDTO.ConvertFromDTO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Merge folds duplicate categories into the target, or with preview only tells what doing so would change
+func (s CategoryService) Merge(mergeDTO m.MergeRequestDTO, preview bool) (m.MergeResultDTO, error) {
+	var sources []m.Category
+
+	seen := map[uuid.UUID]bool{}
+	for _, sourceID := range mergeDTO.SourceIDs {
+		if sourceID == mergeDTO.TargetID {
+			return m.MergeResultDTO{}, errors.New("category cannot be merged into itself")
+		}
+
+		if !seen[sourceID] {
+			seen[sourceID] = true
+			sources = append(sources, m.Category{ID: sourceID})
+		}
+	}
+
+	if len(sources) == 0 {
+		return m.MergeResultDTO{}, errors.New("no categories to merge")
+	}
+
+	result, err := s.repo.Merge(m.Category{ID: mergeDTO.TargetID}, sources, preview)
+	if err != nil {
+		return m.MergeResultDTO{}, err
+	}
+
+	return result.ConvertToDTO(preview), nil
+}
+
+// checkAliases makes sure the aliases of a category do not already refer to another category
+func (s CategoryService) checkAliases(category m.Category) error {
+
+	for _, alias := range category.Aliases {
+		found, err := s.repo.FindByName(alias.Name)
+		if err == nil && found.ID != category.ID {
+			return errors.New("alias is already used by another category")
+		} else if err != nil && err.Error() != "not found" {
+			return errors.New("internal server error")
+		}
+	}
+
+	return nil
+}
+
+// conflict tells which name a category collided with when storing it violated a unique name. Another category may
+// have been stored with the name or one of the aliases after they were checked, otherwise the name is still held
+// by a deleted category.
+func (s CategoryService) conflict(category m.Category) (m.Category, error) {
+
+	found, err := s.repo.FindByName(category.Name)
+	if err == nil && found.ID != category.ID {
+		return found, errors.New("category already exists")
+	}
+
+	if err := s.checkAliases(category); err != nil {
+		return m.Category{}, err
+	}
+
+	return m.Category{}, errors.New("name is already used")
+}
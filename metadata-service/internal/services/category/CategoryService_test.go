@@ -47,12 +47,27 @@ func (*CategoryRepositoryMock) FindSingle(category m.Category) (m.Category, erro
 	}
 }
 
+func (*CategoryRepositoryMock) FindByName(name string) (m.Category, error) {
+	switch name {
+	case "find", "Vegetables":
+		return category, nil
+	case "taken":
+		return m.Category{ID: uuid.New(), Name: "taken"}, nil
+	case "lookuperror":
+		return m.Category{}, errors.New("error")
+	default:
+		return m.Category{}, errors.New("not found")
+	}
+}
+
 func (*CategoryRepositoryMock) Create(category m.Category) (m.Category, error) {
 	categoryC := category
 	switch category.Name {
 	case "create":
 		categoryC.Name = "create"
 		return categoryC, nil
+	case "duplicate":
+		return m.Category{}, errors.New("already exists")
 	default:
 		return m.Category{}, errors.New("error")
 	}
@@ -62,6 +77,8 @@ func (*CategoryRepositoryMock) Update(category m.Category) (m.Category, error) {
 	switch category.Name {
 	case "update":
 		return category, nil
+	case "duplicate":
+		return m.Category{}, errors.New("already exists")
 	default:
 		return m.Category{}, errors.New("error")
 	}
@@ -76,6 +93,28 @@ func (*CategoryRepositoryMock) Delete(category m.Category) error {
 	}
 }
 
+func (*CategoryRepositoryMock) Reassign(category m.Category, replacement m.Category) error {
+	switch category.Name {
+	case "delete":
+		return nil
+	default:
+		return errors.New("error")
+	}
+}
+
+func (*CategoryRepositoryMock) Merge(target m.Category, sources []m.Category, preview bool) (m.MergeResult, error) {
+	switch {
+	case target.ID == category.ID:
+		result := m.MergeResult{TargetID: target.ID, Aliases: []string{"duplicate"}}
+		for _, source := range sources {
+			result.SourceIDs = append(result.SourceIDs, source.ID)
+		}
+		return result, nil
+	default:
+		return m.MergeResult{}, errors.New("target not found")
+	}
+}
+
 // ======================================================================
 
 func TestCategoryFindAll_OK(t *testing.T) {
@@ -206,6 +245,18 @@ func TestCategoryCreate_CreateErr(t *testing.T) {
 	assert.EqualError(t, err, "error")
 }
 
+func TestCategoryCreate_DuplicateErr(t *testing.T) {
+	s := NewCategoryService(&CategoryRepositoryMock{})
+
+	categoryDTO := m.CategoryDTO{
+		Name: "duplicate",
+	}
+	result, err := s.Create(categoryDTO)
+
+	assert.Equal(t, uuid.Nil, result.ID)
+	assert.EqualError(t, err, "name is already used")
+}
+
 func TestCategoryUpdate_OK(t *testing.T) {
 	s := NewCategoryService(&CategoryRepositoryMock{})
 
@@ -246,6 +297,18 @@ func TestCategoryUpdate_UpdateErr(t *testing.T) {
 	assert.EqualError(t, err, "error")
 }
 
+func TestCategoryUpdate_DuplicateErr(t *testing.T) {
+	s := NewCategoryService(&CategoryRepositoryMock{})
+
+	categoryDTO := m.CategoryDTO{
+		ID:   category.ID,
+		Name: "duplicate",
+	}
+	_, err := s.Update(categoryDTO)
+
+	assert.EqualError(t, err, "name is already used")
+}
+
 func TestCategoryDelete_OK(t *testing.T) {
 	s := NewCategoryService(&CategoryRepositoryMock{})
 
@@ -270,3 +333,148 @@ func TestCategoryDelete_DeleteErr(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualError(t, err, "error")
 }
+
+func TestCategoryReassign_OK(t *testing.T) {
+	s := NewCategoryService(&CategoryRepositoryMock{})
+
+	categoryDTO := m.CategoryDTO{
+		ID:   category.ID,
+		Name: "delete",
+	}
+	err := s.Reassign(categoryDTO, m.CategoryDTO{ID: uuid.New()})
+
+	assert.NoError(t, err)
+}
+
+func TestCategoryReassign_SelfErr(t *testing.T) {
+	s := NewCategoryService(&CategoryRepositoryMock{})
+
+	categoryDTO := m.CategoryDTO{
+		ID:   category.ID,
+		Name: "delete",
+	}
+	err := s.Reassign(categoryDTO, m.CategoryDTO{ID: category.ID})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "category cannot be reassigned to itself")
+}
+
+func TestCategoryReassign_Err(t *testing.T) {
+	s := NewCategoryService(&CategoryRepositoryMock{})
+
+	categoryDTO := m.CategoryDTO{
+		ID:   category.ID,
+		Name: "error",
+	}
+	err := s.Reassign(categoryDTO, m.CategoryDTO{ID: uuid.New()})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "error")
+}
+
+func TestCategoryMerge_OK(t *testing.T) {
+	s := NewCategoryService(&CategoryRepositoryMock{})
+
+	sourceID := uuid.New()
+
+	result, err := s.Merge(m.MergeRequestDTO{TargetID: category.ID, SourceIDs: []uuid.UUID{sourceID, sourceID}}, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, category.ID, result.TargetID)
+	assert.Equal(t, []uuid.UUID{sourceID}, result.Merged)
+	assert.Equal(t, []string{"duplicate"}, result.Aliases)
+	assert.Equal(t, []uuid.UUID{}, result.RecipeIDs)
+	assert.True(t, result.Preview)
+}
+
+func TestCategoryMerge_SelfErr(t *testing.T) {
+	s := NewCategoryService(&CategoryRepositoryMock{})
+
+	_, err := s.Merge(m.MergeRequestDTO{TargetID: category.ID, SourceIDs: []uuid.UUID{category.ID}}, false)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "category cannot be merged into itself")
+}
+
+func TestCategoryMerge_EmptyErr(t *testing.T) {
+	s := NewCategoryService(&CategoryRepositoryMock{})
+
+	_, err := s.Merge(m.MergeRequestDTO{TargetID: category.ID}, false)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "no categories to merge")
+}
+
+func TestCategoryMerge_Err(t *testing.T) {
+	s := NewCategoryService(&CategoryRepositoryMock{})
+
+	_, err := s.Merge(m.MergeRequestDTO{TargetID: uuid.New(), SourceIDs: []uuid.UUID{uuid.New()}}, false)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "target not found")
+}
+
+func TestCategoryCreate_ExistsErr(t *testing.T) {
+	s := NewCategoryService(&CategoryRepositoryMock{})
+
+	categoryDTO := m.CategoryDTO{
+		Name: "Vegetables",
+	}
+	result, err := s.Create(categoryDTO)
+
+	assert.Error(t, err)
+	assert.Equal(t, category.ID, result.ID)
+	assert.EqualError(t, err, "category already exists")
+}
+
+func TestCategoryCreate_AliasTakenErr(t *testing.T) {
+	s := NewCategoryService(&CategoryRepositoryMock{})
+
+	categoryDTO := m.CategoryDTO{
+		Name:    "create",
+		Aliases: []string{"taken"},
+	}
+	result, err := s.Create(categoryDTO)
+
+	assert.Error(t, err)
+	assert.Equal(t, m.CategoryDTO{}, result)
+	assert.EqualError(t, err, "alias is already used by another category")
+}
+
+func TestCategoryUpdate_ExistsErr(t *testing.T) {
+	s := NewCategoryService(&CategoryRepositoryMock{})
+
+	categoryDTO := m.CategoryDTO{
+		ID:   category.ID,
+		Name: "taken",
+	}
+	_, err := s.Update(categoryDTO)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "category already exists")
+}
+
+func TestCategoryLookup_OK(t *testing.T) {
+	s := NewCategoryService(&CategoryRepositoryMock{})
+
+	result, err := s.Lookup("Vegetables")
+
+	assert.NoError(t, err)
+	assert.Equal(t, category.ID, result.ID)
+}
+
+func TestCategoryLookup_NotFoundErr(t *testing.T) {
+	s := NewCategoryService(&CategoryRepositoryMock{})
+
+	_, err := s.Lookup("unknown")
+
+	assert.EqualError(t, err, "not found")
+}
+
+func TestCategoryLookup_Err(t *testing.T) {
+	s := NewCategoryService(&CategoryRepositoryMock{})
+
+	_, err := s.Lookup("lookuperror")
+
+	assert.EqualError(t, err, "internal server error")
+}
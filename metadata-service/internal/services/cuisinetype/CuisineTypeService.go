@@ -14,6 +14,7 @@ type CuisineTypeRepository interface {
 	Create(cuisineType m.CuisineType) (m.CuisineType, error)
 	Update(cuisineType m.CuisineType) (m.CuisineType, error)
 	Delete(cuisineType m.CuisineType) error
+	Reassign(cuisineType m.CuisineType, replacement m.CuisineType) error
 }
 type CuisineTypeService struct {
 	repo CuisineTypeRepository
@@ -98,3 +99,18 @@ func (s CuisineTypeService) Delete(cuisineTypeDTO m.CuisineTypeDTO) error {
 
 	return nil
 }
+
+// Reassign moves the recipes associated with a cuisine type to the replacement before deleting it
+func (s CuisineTypeService) Reassign(cuisineTypeDTO m.CuisineTypeDTO, replacementDTO m.CuisineTypeDTO) error {
+
+	if replacementDTO.ID == cuisineTypeDTO.ID {
+		return errors.New("cuisine type cannot be reassigned to itself")
+	}
+
+	err := s.repo.Reassign(cuisineTypeDTO.ConvertFromDTO(), replacementDTO.ConvertFromDTO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
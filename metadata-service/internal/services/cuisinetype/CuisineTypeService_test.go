@@ -74,6 +74,15 @@ func (*CuisineTypeRepositoryMock) Delete(cuisineType m.CuisineType) error {
 	}
 }
 
+func (*CuisineTypeRepositoryMock) Reassign(cuisineType m.CuisineType, replacement m.CuisineType) error {
+	switch cuisineType.Name {
+	case "delete":
+		return nil
+	default:
+		return errors.New("error")
+	}
+}
+
 // ======================================================================
 
 func TestCuisineTypeFindAll_OK(t *testing.T) {
@@ -269,3 +278,41 @@ func TestCuisineTypeDelete_DeleteErr(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualError(t, err, "error")
 }
+
+func TestCuisineTypeReassign_OK(t *testing.T) {
+	s := NewCuisineTypeService(&CuisineTypeRepositoryMock{})
+
+	cuisineTypeDTO := m.CuisineTypeDTO{
+		ID:   cuisineType.ID,
+		Name: "delete",
+	}
+	err := s.Reassign(cuisineTypeDTO, m.CuisineTypeDTO{ID: uuid.New()})
+
+	assert.NoError(t, err)
+}
+
+func TestCuisineTypeReassign_SelfErr(t *testing.T) {
+	s := NewCuisineTypeService(&CuisineTypeRepositoryMock{})
+
+	cuisineTypeDTO := m.CuisineTypeDTO{
+		ID:   cuisineType.ID,
+		Name: "delete",
+	}
+	err := s.Reassign(cuisineTypeDTO, m.CuisineTypeDTO{ID: cuisineType.ID})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "cuisine type cannot be reassigned to itself")
+}
+
+func TestCuisineTypeReassign_Err(t *testing.T) {
+	s := NewCuisineTypeService(&CuisineTypeRepositoryMock{})
+
+	cuisineTypeDTO := m.CuisineTypeDTO{
+		ID:   cuisineType.ID,
+		Name: "error",
+	}
+	err := s.Reassign(cuisineTypeDTO, m.CuisineTypeDTO{ID: uuid.New()})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "error")
+}
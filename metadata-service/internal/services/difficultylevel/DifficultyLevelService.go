@@ -14,6 +14,7 @@ type DifficultyLevelRepository interface {
 	Create(difficultyLevel m.DifficultyLevel) (m.DifficultyLevel, error)
 	Update(difficultyLevel m.DifficultyLevel) (m.DifficultyLevel, error)
 	Delete(difficultyLevel m.DifficultyLevel) error
+	Reassign(difficultyLevel m.DifficultyLevel, replacement m.DifficultyLevel) error
 }
 type DifficultyLevelService struct {
 	repo DifficultyLevelRepository
@@ -98,3 +99,18 @@ func (s DifficultyLevelService) Delete(difficultyLevelDTO m.DifficultyLevelDTO)
 
 	return nil
 }
+
+// Reassign moves the recipes associated with a difficulty level to the replacement before deleting it
+func (s DifficultyLevelService) Reassign(difficultyLevelDTO m.DifficultyLevelDTO, replacementDTO m.DifficultyLevelDTO) error {
+
+	if replacementDTO.ID == difficultyLevelDTO.ID {
+		return errors.New("difficulty level cannot be reassigned to itself")
+	}
+
+	err := s.repo.Reassign(difficultyLevelDTO.ConvertFromDTO(), replacementDTO.ConvertFromDTO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -74,6 +74,15 @@ func (*DifficultyLevelRepositoryMock) Delete(difficultyLevel m.DifficultyLevel)
 	}
 }
 
+func (*DifficultyLevelRepositoryMock) Reassign(difficultyLevel m.DifficultyLevel, replacement m.DifficultyLevel) error {
+	switch difficultyLevel.Level {
+	case 1:
+		return nil
+	default:
+		return errors.New("error")
+	}
+}
+
 // ======================================================================
 
 func TestDifficultyLevelFindAll_OK(t *testing.T) {
@@ -269,3 +278,41 @@ func TestDifficultyLevelDelete_DeleteErr(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualError(t, err, "error")
 }
+
+func TestDifficultyLevelReassign_OK(t *testing.T) {
+	s := NewDifficultyLevelService(&DifficultyLevelRepositoryMock{})
+
+	difficultyLevelDTO := m.DifficultyLevelDTO{
+		ID:    difficultyLevel.ID,
+		Level: 1,
+	}
+	err := s.Reassign(difficultyLevelDTO, m.DifficultyLevelDTO{ID: uuid.New()})
+
+	assert.NoError(t, err)
+}
+
+func TestDifficultyLevelReassign_SelfErr(t *testing.T) {
+	s := NewDifficultyLevelService(&DifficultyLevelRepositoryMock{})
+
+	difficultyLevelDTO := m.DifficultyLevelDTO{
+		ID:    difficultyLevel.ID,
+		Level: 1,
+	}
+	err := s.Reassign(difficultyLevelDTO, m.DifficultyLevelDTO{ID: difficultyLevel.ID})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "difficulty level cannot be reassigned to itself")
+}
+
+func TestDifficultyLevelReassign_Err(t *testing.T) {
+	s := NewDifficultyLevelService(&DifficultyLevelRepositoryMock{})
+
+	difficultyLevelDTO := m.DifficultyLevelDTO{
+		ID:    difficultyLevel.ID,
+		Level: 3,
+	}
+	err := s.Reassign(difficultyLevelDTO, m.DifficultyLevelDTO{ID: uuid.New()})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "error")
+}
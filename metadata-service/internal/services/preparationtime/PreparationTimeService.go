@@ -14,6 +14,7 @@ type PreparationTimeRepository interface {
 	Create(preparationTime m.PreparationTime) (m.PreparationTime, error)
 	Update(preparationTime m.PreparationTime) (m.PreparationTime, error)
 	Delete(preparationTime m.PreparationTime) error
+	Reassign(preparationTime m.PreparationTime, replacement m.PreparationTime) error
 }
 type PreparationTimeService struct {
 	repo PreparationTimeRepository
@@ -98,3 +99,18 @@ func (s PreparationTimeService) Delete(preparationTimeDTO m.PreparationTimeDTO)
 
 	return nil
 }
+
+// Reassign moves the recipes associated with a preparation time to the replacement before deleting it
+func (s PreparationTimeService) Reassign(preparationTimeDTO m.PreparationTimeDTO, replacementDTO m.PreparationTimeDTO) error {
+
+	if replacementDTO.ID == preparationTimeDTO.ID {
+		return errors.New("preparation time cannot be reassigned to itself")
+	}
+
+	err := s.repo.Reassign(preparationTimeDTO.ConvertFromDTO(), replacementDTO.ConvertFromDTO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
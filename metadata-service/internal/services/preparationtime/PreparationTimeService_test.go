@@ -74,6 +74,15 @@ func (*PreparationTimeRepositoryMock) Delete(preparationTime m.PreparationTime)
 	}
 }
 
+func (*PreparationTimeRepositoryMock) Reassign(preparationTime m.PreparationTime, replacement m.PreparationTime) error {
+	switch preparationTime.Duration {
+	case 1:
+		return nil
+	default:
+		return errors.New("error")
+	}
+}
+
 // ======================================================================
 
 func TestPreparationTimeFindAll_OK(t *testing.T) {
@@ -269,3 +278,41 @@ func TestPreparationTimeDelete_DeleteErr(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualError(t, err, "error")
 }
+
+func TestPreparationTimeReassign_OK(t *testing.T) {
+	s := NewPreparationTimeService(&PreparationTimeRepositoryMock{})
+
+	preparationTimeDTO := m.PreparationTimeDTO{
+		ID:       preparationTime.ID,
+		Duration: 1,
+	}
+	err := s.Reassign(preparationTimeDTO, m.PreparationTimeDTO{ID: uuid.New()})
+
+	assert.NoError(t, err)
+}
+
+func TestPreparationTimeReassign_SelfErr(t *testing.T) {
+	s := NewPreparationTimeService(&PreparationTimeRepositoryMock{})
+
+	preparationTimeDTO := m.PreparationTimeDTO{
+		ID:       preparationTime.ID,
+		Duration: 1,
+	}
+	err := s.Reassign(preparationTimeDTO, m.PreparationTimeDTO{ID: preparationTime.ID})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "preparation time cannot be reassigned to itself")
+}
+
+func TestPreparationTimeReassign_Err(t *testing.T) {
+	s := NewPreparationTimeService(&PreparationTimeRepositoryMock{})
+
+	preparationTimeDTO := m.PreparationTimeDTO{
+		ID:       preparationTime.ID,
+		Duration: 3,
+	}
+	err := s.Reassign(preparationTimeDTO, m.PreparationTimeDTO{ID: uuid.New()})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "error")
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	m "metadata-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type RecipeClient interface {
+	GetRecipes(ctx context.Context) ([]m.RecipeDTO, error)
+	GetRecipe(ctx context.Context, recipeID uuid.UUID) (m.RecipeDTO, error)
+}
+
+type RecipeRepository interface {
+	Save(recipe m.Recipe) error
+	Delete(recipeID uuid.UUID) error
+}
+
+// RecipeService keeps the copies of the recipes the search results are sorted on up to date with the recipe service
+type RecipeService struct {
+	client RecipeClient
+	repo   RecipeRepository
+	logger m.LoggerInterface
+}
+
+// NewRecipeService creates a new RecipeService instance
+func NewRecipeService(client RecipeClient, repo RecipeRepository, logger m.LoggerInterface) *RecipeService {
+	return &RecipeService{
+		client: client,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Handle updates the copy of a recipe on a change reported by the recipe service. The recipe is retrieved rather
+// than taken from the event, so a change that is delivered late doesn't overwrite a newer one.
+func (s RecipeService) Handle(ctx context.Context, eventDTO m.ChangeEventDTO) error {
+
+	switch eventDTO.Type {
+	case m.EventCreated, m.EventUpdated, m.EventDeleted:
+	default:
+		return errors.New("invalid event type")
+	}
+
+	if eventDTO.EntityType != m.EntityRecipe {
+		return errors.New("invalid entity type")
+	}
+
+	if eventDTO.EntityID == uuid.Nil {
+		return errors.New("invalid entity ID")
+	}
+
+	if eventDTO.Type == m.EventDeleted {
+		return s.remove(eventDTO.EntityID)
+	}
+
+	recipeDTO, err := s.client.GetRecipe(ctx, eventDTO.EntityID)
+	if err != nil {
+		// the recipe was deleted after the change
+		if err.Error() == "not found" {
+			return s.remove(eventDTO.EntityID)
+		}
+		s.logger.Warnf("unable to retrieve recipe %s: %v", eventDTO.EntityID, err)
+		return errors.New("internal server error")
+	}
+
+	if err := s.repo.Save(recipeDTO.ConvertFromDTO()); err != nil {
+		s.logger.Warnf("unable to save recipe %s: %v", eventDTO.EntityID, err)
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
+
+// Sync copies all recipes of the recipe service, including those created before this service kept track of them.
+// It returns the number of recipes copied.
+func (s RecipeService) Sync(ctx context.Context) (int, error) {
+	recipes, err := s.client.GetRecipes(ctx)
+	if err != nil {
+		if err.Error() == "not found" {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	for i, recipeDTO := range recipes {
+		if err := s.repo.Save(recipeDTO.ConvertFromDTO()); err != nil {
+			return i, err
+		}
+	}
+
+	return len(recipes), nil
+}
+
+func (s RecipeService) remove(recipeID uuid.UUID) error {
+	if err := s.repo.Delete(recipeID); err != nil {
+		s.logger.Warnf("unable to remove recipe %s: %v", recipeID, err)
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
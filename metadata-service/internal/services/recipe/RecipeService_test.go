@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	m "metadata-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	recipeFound   = uuid.New()
+	recipeMissing = uuid.New()
+	recipeError   = uuid.New()
+	createdAt     = time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)
+)
+
+type RecipeClientMock struct {
+	recipes []m.RecipeDTO
+	err     error
+}
+
+func (c *RecipeClientMock) GetRecipes(ctx context.Context) ([]m.RecipeDTO, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.recipes, nil
+}
+
+func (c *RecipeClientMock) GetRecipe(ctx context.Context, recipeID uuid.UUID) (m.RecipeDTO, error) {
+	switch recipeID {
+	case recipeFound:
+		return m.RecipeDTO{ID: recipeID, Name: "apple pie", CreatedAt: createdAt}, nil
+	case recipeMissing:
+		return m.RecipeDTO{}, errors.New("not found")
+	default:
+		return m.RecipeDTO{}, errors.New("unexpected status code 500")
+	}
+}
+
+type RecipeRepositoryMock struct {
+	saved   []m.Recipe
+	deleted []uuid.UUID
+	err     error
+}
+
+func (r *RecipeRepositoryMock) Save(recipe m.Recipe) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.saved = append(r.saved, recipe)
+	return nil
+}
+
+func (r *RecipeRepositoryMock) Delete(recipeID uuid.UUID) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.deleted = append(r.deleted, recipeID)
+	return nil
+}
+
+func TestRecipeHandle_Updated(t *testing.T) {
+	repo := &RecipeRepositoryMock{}
+	s := NewRecipeService(&RecipeClientMock{}, repo, &m.LoggerInterfaceMock{})
+
+	err := s.Handle(context.Background(), m.ChangeEventDTO{Type: m.EventUpdated, EntityType: m.EntityRecipe, EntityID: recipeFound})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []m.Recipe{{ID: recipeFound, Name: "apple pie", CreatedAt: createdAt}}, repo.saved)
+}
+
+func TestRecipeHandle_Deleted(t *testing.T) {
+	repo := &RecipeRepositoryMock{}
+	s := NewRecipeService(&RecipeClientMock{}, repo, &m.LoggerInterfaceMock{})
+
+	err := s.Handle(context.Background(), m.ChangeEventDTO{Type: m.EventDeleted, EntityType: m.EntityRecipe, EntityID: recipeFound})
+
+	assert.NoError(t, err)
+	assert.Empty(t, repo.saved)
+	assert.Equal(t, []uuid.UUID{recipeFound}, repo.deleted)
+}
+
+func TestRecipeHandle_DeletedSince(t *testing.T) {
+	repo := &RecipeRepositoryMock{}
+	s := NewRecipeService(&RecipeClientMock{}, repo, &m.LoggerInterfaceMock{})
+
+	err := s.Handle(context.Background(), m.ChangeEventDTO{Type: m.EventCreated, EntityType: m.EntityRecipe, EntityID: recipeMissing})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{recipeMissing}, repo.deleted)
+}
+
+func TestRecipeHandle_InvalidErr(t *testing.T) {
+	s := NewRecipeService(&RecipeClientMock{}, &RecipeRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	err := s.Handle(context.Background(), m.ChangeEventDTO{Type: "renamed", EntityType: m.EntityRecipe, EntityID: recipeFound})
+	assert.EqualError(t, err, "invalid event type")
+
+	err = s.Handle(context.Background(), m.ChangeEventDTO{Type: m.EventUpdated, EntityType: m.EntityTag, EntityID: recipeFound})
+	assert.EqualError(t, err, "invalid entity type")
+
+	err = s.Handle(context.Background(), m.ChangeEventDTO{Type: m.EventUpdated, EntityType: m.EntityRecipe})
+	assert.EqualError(t, err, "invalid entity ID")
+}
+
+func TestRecipeHandle_ClientErr(t *testing.T) {
+	repo := &RecipeRepositoryMock{}
+	s := NewRecipeService(&RecipeClientMock{}, repo, &m.LoggerInterfaceMock{})
+
+	err := s.Handle(context.Background(), m.ChangeEventDTO{Type: m.EventUpdated, EntityType: m.EntityRecipe, EntityID: recipeError})
+
+	assert.EqualError(t, err, "internal server error")
+	assert.Empty(t, repo.saved)
+	assert.Empty(t, repo.deleted)
+}
+
+func TestRecipeHandle_RepositoryErr(t *testing.T) {
+	s := NewRecipeService(&RecipeClientMock{}, &RecipeRepositoryMock{err: errors.New("error")}, &m.LoggerInterfaceMock{})
+
+	err := s.Handle(context.Background(), m.ChangeEventDTO{Type: m.EventUpdated, EntityType: m.EntityRecipe, EntityID: recipeFound})
+
+	assert.EqualError(t, err, "internal server error")
+}
+
+func TestRecipeSync_OK(t *testing.T) {
+	repo := &RecipeRepositoryMock{}
+	client := &RecipeClientMock{recipes: []m.RecipeDTO{{ID: recipeFound, Name: "apple pie", CreatedAt: createdAt}, {ID: recipeMissing, Name: "pasta"}}}
+	s := NewRecipeService(client, repo, &m.LoggerInterfaceMock{})
+
+	synced, err := s.Sync(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, synced)
+	assert.Len(t, repo.saved, 2)
+}
+
+func TestRecipeSync_NoRecipes(t *testing.T) {
+	s := NewRecipeService(&RecipeClientMock{err: errors.New("not found")}, &RecipeRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	synced, err := s.Sync(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, synced)
+}
+
+func TestRecipeSync_Err(t *testing.T) {
+	s := NewRecipeService(&RecipeClientMock{err: errors.New("unexpected status code 401")}, &RecipeRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	_, err := s.Sync(context.Background())
+
+	assert.EqualError(t, err, "unexpected status code 401")
+}
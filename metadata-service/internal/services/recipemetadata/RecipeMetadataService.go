@@ -0,0 +1,251 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	m "metadata-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type RecipeMetadataRepository interface {
+	FindByRecipe(recipeID uuid.UUID) (m.RecipeMetadata, error)
+	FindByRecipes(recipeIDs []uuid.UUID) ([]m.RecipeMetadata, error)
+	Replace(metadata m.RecipeMetadata) error
+	DeleteByRecipe(recipeID uuid.UUID) error
+	RestoreByRecipe(recipeID uuid.UUID, since time.Time) error
+	PurgeByRecipe(recipeID uuid.UUID) error
+}
+
+type CategoryRepository interface {
+	FindSingle(category m.Category) (m.Category, error)
+}
+
+type TagRepository interface {
+	FindSingle(tag m.Tag) (m.Tag, error)
+}
+
+type CuisineTypeRepository interface {
+	FindSingle(cuisineType m.CuisineType) (m.CuisineType, error)
+}
+
+type DifficultyLevelRepository interface {
+	FindSingle(difficultyLevel m.DifficultyLevel) (m.DifficultyLevel, error)
+}
+
+type PreparationTimeRepository interface {
+	FindSingle(preparationTime m.PreparationTime) (m.PreparationTime, error)
+}
+
+type RecipeMetadataService struct {
+	repo                RecipeMetadataRepository
+	categoryRepo        CategoryRepository
+	tagRepo             TagRepository
+	cuisineTypeRepo     CuisineTypeRepository
+	difficultyLevelRepo DifficultyLevelRepository
+	preparationTimeRepo PreparationTimeRepository
+}
+
+// NewRecipeMetadataService creates a new RecipeMetadataService instance
+func NewRecipeMetadataService(
+	repo RecipeMetadataRepository,
+	categoryRepo CategoryRepository,
+	tagRepo TagRepository,
+	cuisineTypeRepo CuisineTypeRepository,
+	difficultyLevelRepo DifficultyLevelRepository,
+	preparationTimeRepo PreparationTimeRepository,
+) *RecipeMetadataService {
+	return &RecipeMetadataService{
+		repo:                repo,
+		categoryRepo:        categoryRepo,
+		tagRepo:             tagRepo,
+		cuisineTypeRepo:     cuisineTypeRepo,
+		difficultyLevelRepo: difficultyLevelRepo,
+		preparationTimeRepo: preparationTimeRepo,
+	}
+}
+
+func (s RecipeMetadataService) FindByRecipe(recipeID uuid.UUID) (m.RecipeMetadataDTO, error) {
+
+	metadata, err := s.repo.FindByRecipe(recipeID)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return m.RecipeMetadataDTO{}, err
+		default:
+			return m.RecipeMetadataDTO{}, errors.New("internal server error")
+		}
+	}
+
+	return metadata.ConvertToDTO(), nil
+}
+
+func (s RecipeMetadataService) FindByRecipes(recipeIDs []uuid.UUID) ([]m.RecipeMetadataDTO, error) {
+
+	if len(recipeIDs) <= 0 {
+		return nil, errors.New("no recipe IDs provided")
+	}
+
+	metadata, err := s.repo.FindByRecipes(recipeIDs)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return nil, err
+		default:
+			return nil, errors.New("internal server error")
+		}
+	}
+
+	return m.RecipeMetadata{}.ConvertAllToDTO(metadata), nil
+}
+
+// Replace sets all metadata of a recipe at once. Metadata that is not part of the request is removed from the recipe.
+func (s RecipeMetadataService) Replace(recipeID uuid.UUID, requestDTO m.RecipeMetadataRequestDTO) (m.RecipeMetadataDTO, error) {
+
+	if recipeID == uuid.Nil {
+		return m.RecipeMetadataDTO{}, errors.New("invalid recipe ID")
+	}
+
+	if err := s.validate(requestDTO); err != nil {
+		return m.RecipeMetadataDTO{}, err
+	}
+
+	if err := s.repo.Replace(requestDTO.ConvertFromDTO(recipeID)); err != nil {
+		return m.RecipeMetadataDTO{}, errors.New("internal server error")
+	}
+
+	return s.FindByRecipe(recipeID)
+}
+
+// DeleteByRecipe removes all metadata from a deleted recipe. Deleting it again is not an error, so the recipe
+// service can safely retry.
+func (s RecipeMetadataService) DeleteByRecipe(recipeID uuid.UUID) error {
+
+	if err := s.repo.DeleteByRecipe(recipeID); err != nil {
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
+
+// RestoreByRecipe brings back the metadata that was removed from a recipe since the given time
+func (s RecipeMetadataService) RestoreByRecipe(recipeID uuid.UUID, since time.Time) error {
+
+	if err := s.repo.RestoreByRecipe(recipeID, since); err != nil {
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
+
+// PurgeByRecipe permanently removes the metadata of a deleted recipe once its restore window has passed
+func (s RecipeMetadataService) PurgeByRecipe(recipeID uuid.UUID) error {
+
+	if err := s.repo.PurgeByRecipe(recipeID); err != nil {
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
+
+// validate enforces the metadata rules of a recipe and makes sure all referenced metadata exists
+func (s RecipeMetadataService) validate(requestDTO m.RecipeMetadataRequestDTO) error {
+
+	if len(requestDTO.CategoryIDs) <= 0 {
+		return errors.New("at least one category is required")
+	}
+
+	if requestDTO.CuisineTypeID == uuid.Nil {
+		return errors.New("exactly one cuisine type is required")
+	}
+
+	if requestDTO.DifficultyLevelID == uuid.Nil {
+		return errors.New("exactly one difficulty level is required")
+	}
+
+	if requestDTO.PreparationTimeID == uuid.Nil {
+		return errors.New("exactly one preparation time is required")
+	}
+
+	if hasDuplicates(requestDTO.CategoryIDs) {
+		return errors.New("duplicate category in list")
+	}
+
+	if hasDuplicates(requestDTO.TagIDs) {
+		return errors.New("duplicate tag in list")
+	}
+
+	for _, categoryID := range requestDTO.CategoryIDs {
+		if err := checkExists(categoryID, "category does not exist", func(id uuid.UUID) error {
+			_, err := s.categoryRepo.FindSingle(m.Category{ID: id})
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, tagID := range requestDTO.TagIDs {
+		if err := checkExists(tagID, "tag does not exist", func(id uuid.UUID) error {
+			_, err := s.tagRepo.FindSingle(m.Tag{ID: id})
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := checkExists(requestDTO.CuisineTypeID, "cuisine type does not exist", func(id uuid.UUID) error {
+		_, err := s.cuisineTypeRepo.FindSingle(m.CuisineType{ID: id})
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if err := checkExists(requestDTO.DifficultyLevelID, "difficulty level does not exist", func(id uuid.UUID) error {
+		_, err := s.difficultyLevelRepo.FindSingle(m.DifficultyLevel{ID: id})
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if err := checkExists(requestDTO.PreparationTimeID, "preparation time does not exist", func(id uuid.UUID) error {
+		_, err := s.preparationTimeRepo.FindSingle(m.PreparationTime{ID: id})
+		return err
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkExists runs the given lookup and translates its result. An empty ID never exists,
+// so it is rejected before the lookup as FindSingle would return the first record.
+func checkExists(id uuid.UUID, message string, find func(id uuid.UUID) error) error {
+	if id == uuid.Nil {
+		return errors.New(message)
+	}
+
+	if err := find(id); err != nil {
+		switch err.Error() {
+		case "not found":
+			return errors.New(message)
+		default:
+			return errors.New("internal server error")
+		}
+	}
+
+	return nil
+}
+
+func hasDuplicates(ids []uuid.UUID) bool {
+	seen := make(map[uuid.UUID]bool, len(ids))
+
+	for _, id := range ids {
+		if seen[id] {
+			return true
+		}
+		seen[id] = true
+	}
+
+	return false
+}
@@ -0,0 +1,302 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	m "metadata-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	recipeFound    uuid.UUID = uuid.New()
+	recipeNotFound uuid.UUID = uuid.New()
+	recipeError    uuid.UUID = uuid.New()
+
+	existing uuid.UUID = uuid.New()
+	missing  uuid.UUID = uuid.New()
+
+	replacedMetadata m.RecipeMetadata
+	replaceErr       error
+)
+
+// ====== Mocks ======
+
+type RecipeMetadataRepositoryMock struct{}
+
+func (RecipeMetadataRepositoryMock) FindByRecipe(recipeID uuid.UUID) (m.RecipeMetadata, error) {
+	switch recipeID {
+	case recipeFound:
+		return m.RecipeMetadata{RecipeID: recipeID, Categories: []m.Category{{ID: existing, Name: "dessert"}}}, nil
+	case recipeNotFound:
+		return m.RecipeMetadata{}, errors.New("not found")
+	default:
+		return m.RecipeMetadata{}, errors.New("error")
+	}
+}
+
+func (r RecipeMetadataRepositoryMock) FindByRecipes(recipeIDs []uuid.UUID) ([]m.RecipeMetadata, error) {
+	metadata, err := r.FindByRecipe(recipeIDs[0])
+	if err != nil {
+		return nil, err
+	}
+	return []m.RecipeMetadata{metadata}, nil
+}
+
+func (RecipeMetadataRepositoryMock) Replace(metadata m.RecipeMetadata) error {
+	replacedMetadata = metadata
+	return replaceErr
+}
+
+func (RecipeMetadataRepositoryMock) DeleteByRecipe(recipeID uuid.UUID) error {
+	if recipeID == recipeError {
+		return errors.New("error")
+	}
+	return nil
+}
+
+func (RecipeMetadataRepositoryMock) RestoreByRecipe(recipeID uuid.UUID, since time.Time) error {
+	if recipeID == recipeError {
+		return errors.New("error")
+	}
+	return nil
+}
+
+func (RecipeMetadataRepositoryMock) PurgeByRecipe(recipeID uuid.UUID) error {
+	if recipeID == recipeError {
+		return errors.New("error")
+	}
+	return nil
+}
+
+// lookup mocks the FindSingle of the metadata repositories
+func lookup(id uuid.UUID) error {
+	switch id {
+	case existing:
+		return nil
+	case missing:
+		return errors.New("not found")
+	default:
+		return errors.New("error")
+	}
+}
+
+type CategoryRepositoryMock struct{}
+
+func (CategoryRepositoryMock) FindSingle(category m.Category) (m.Category, error) {
+	return category, lookup(category.ID)
+}
+
+type TagRepositoryMock struct{}
+
+func (TagRepositoryMock) FindSingle(tag m.Tag) (m.Tag, error) {
+	return tag, lookup(tag.ID)
+}
+
+type CuisineTypeRepositoryMock struct{}
+
+func (CuisineTypeRepositoryMock) FindSingle(cuisineType m.CuisineType) (m.CuisineType, error) {
+	return cuisineType, lookup(cuisineType.ID)
+}
+
+type DifficultyLevelRepositoryMock struct{}
+
+func (DifficultyLevelRepositoryMock) FindSingle(difficultyLevel m.DifficultyLevel) (m.DifficultyLevel, error) {
+	return difficultyLevel, lookup(difficultyLevel.ID)
+}
+
+type PreparationTimeRepositoryMock struct{}
+
+func (PreparationTimeRepositoryMock) FindSingle(preparationTime m.PreparationTime) (m.PreparationTime, error) {
+	return preparationTime, lookup(preparationTime.ID)
+}
+
+func newRecipeMetadataService() *RecipeMetadataService {
+	replacedMetadata = m.RecipeMetadata{}
+	replaceErr = nil
+
+	return NewRecipeMetadataService(
+		&RecipeMetadataRepositoryMock{},
+		&CategoryRepositoryMock{},
+		&TagRepositoryMock{},
+		&CuisineTypeRepositoryMock{},
+		&DifficultyLevelRepositoryMock{},
+		&PreparationTimeRepositoryMock{},
+	)
+}
+
+func validRequest() m.RecipeMetadataRequestDTO {
+	return m.RecipeMetadataRequestDTO{
+		CategoryIDs:       []uuid.UUID{existing},
+		TagIDs:            []uuid.UUID{existing},
+		CuisineTypeID:     existing,
+		DifficultyLevelID: existing,
+		PreparationTimeID: existing,
+	}
+}
+
+// ====== Tests ======
+
+func TestRecipeMetadataFindByRecipe_OK(t *testing.T) {
+	s := newRecipeMetadataService()
+
+	result, err := s.FindByRecipe(recipeFound)
+
+	assert.NoError(t, err)
+	assert.Equal(t, recipeFound, result.RecipeID)
+	assert.Len(t, result.Categories, 1)
+	assert.Equal(t, []m.TagDTO{}, result.Tags)
+}
+
+func TestRecipeMetadataFindByRecipe_NotFound(t *testing.T) {
+	s := newRecipeMetadataService()
+
+	result, err := s.FindByRecipe(recipeNotFound)
+
+	assert.EqualError(t, err, "not found")
+	assert.Equal(t, m.RecipeMetadataDTO{}, result)
+}
+
+func TestRecipeMetadataFindByRecipe_Err(t *testing.T) {
+	s := newRecipeMetadataService()
+
+	result, err := s.FindByRecipe(recipeError)
+
+	assert.EqualError(t, err, "internal server error")
+	assert.Equal(t, m.RecipeMetadataDTO{}, result)
+}
+
+func TestRecipeMetadataFindByRecipes_OK(t *testing.T) {
+	s := newRecipeMetadataService()
+
+	result, err := s.FindByRecipes([]uuid.UUID{recipeFound})
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+}
+
+func TestRecipeMetadataFindByRecipes_NoIDsErr(t *testing.T) {
+	s := newRecipeMetadataService()
+
+	result, err := s.FindByRecipes(nil)
+
+	assert.EqualError(t, err, "no recipe IDs provided")
+	assert.Nil(t, result)
+}
+
+func TestRecipeMetadataReplace_OK(t *testing.T) {
+	s := newRecipeMetadataService()
+
+	result, err := s.Replace(recipeFound, validRequest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, recipeFound, result.RecipeID)
+	assert.Equal(t, recipeFound, replacedMetadata.RecipeID)
+	assert.Equal(t, []m.Category{{ID: existing}}, replacedMetadata.Categories)
+	assert.Equal(t, existing, replacedMetadata.PreparationTime.ID)
+}
+
+func TestRecipeMetadataReplace_RepositoryErr(t *testing.T) {
+	s := newRecipeMetadataService()
+	replaceErr = errors.New("error")
+
+	result, err := s.Replace(recipeFound, validRequest())
+
+	assert.EqualError(t, err, "internal server error")
+	assert.Equal(t, m.RecipeMetadataDTO{}, result)
+}
+
+func TestRecipeMetadataReplace_ValidationErr(t *testing.T) {
+	s := newRecipeMetadataService()
+
+	tests := []struct {
+		modify func(request *m.RecipeMetadataRequestDTO)
+		err    string
+	}{
+		{func(r *m.RecipeMetadataRequestDTO) { r.CategoryIDs = nil }, "at least one category is required"},
+		{func(r *m.RecipeMetadataRequestDTO) { r.CuisineTypeID = uuid.Nil }, "exactly one cuisine type is required"},
+		{func(r *m.RecipeMetadataRequestDTO) { r.DifficultyLevelID = uuid.Nil }, "exactly one difficulty level is required"},
+		{func(r *m.RecipeMetadataRequestDTO) { r.PreparationTimeID = uuid.Nil }, "exactly one preparation time is required"},
+		{func(r *m.RecipeMetadataRequestDTO) { r.CategoryIDs = []uuid.UUID{existing, existing} }, "duplicate category in list"},
+		{func(r *m.RecipeMetadataRequestDTO) { r.TagIDs = []uuid.UUID{existing, existing} }, "duplicate tag in list"},
+		{func(r *m.RecipeMetadataRequestDTO) { r.CategoryIDs = []uuid.UUID{existing, missing} }, "category does not exist"},
+		{func(r *m.RecipeMetadataRequestDTO) { r.TagIDs = []uuid.UUID{uuid.Nil} }, "tag does not exist"},
+		{func(r *m.RecipeMetadataRequestDTO) { r.CuisineTypeID = missing }, "cuisine type does not exist"},
+		{func(r *m.RecipeMetadataRequestDTO) { r.DifficultyLevelID = missing }, "difficulty level does not exist"},
+		{func(r *m.RecipeMetadataRequestDTO) { r.PreparationTimeID = missing }, "preparation time does not exist"},
+		{func(r *m.RecipeMetadataRequestDTO) { r.PreparationTimeID = uuid.New() }, "internal server error"},
+	}
+
+	for _, test := range tests {
+		request := validRequest()
+		test.modify(&request)
+
+		result, err := s.Replace(recipeFound, request)
+
+		assert.EqualError(t, err, test.err)
+		assert.Equal(t, m.RecipeMetadataDTO{}, result)
+	}
+
+	// nothing should have been stored
+	assert.Equal(t, m.RecipeMetadata{}, replacedMetadata)
+}
+
+func TestRecipeMetadataReplace_RecipeIDErr(t *testing.T) {
+	s := newRecipeMetadataService()
+
+	result, err := s.Replace(uuid.Nil, validRequest())
+
+	assert.EqualError(t, err, "invalid recipe ID")
+	assert.Equal(t, m.RecipeMetadataDTO{}, result)
+}
+
+func TestRecipeMetadataDeleteByRecipe_OK(t *testing.T) {
+	s := newRecipeMetadataService()
+
+	err := s.DeleteByRecipe(recipeFound)
+
+	assert.NoError(t, err)
+}
+
+func TestRecipeMetadataDeleteByRecipe_Err(t *testing.T) {
+	s := newRecipeMetadataService()
+
+	err := s.DeleteByRecipe(recipeError)
+
+	assert.EqualError(t, err, "internal server error")
+}
+
+func TestRecipeMetadataRestoreByRecipe_OK(t *testing.T) {
+	s := newRecipeMetadataService()
+
+	err := s.RestoreByRecipe(recipeFound, time.Now())
+
+	assert.NoError(t, err)
+}
+
+func TestRecipeMetadataRestoreByRecipe_Err(t *testing.T) {
+	s := newRecipeMetadataService()
+
+	err := s.RestoreByRecipe(recipeError, time.Now())
+
+	assert.EqualError(t, err, "internal server error")
+}
+
+func TestRecipeMetadataPurgeByRecipe_OK(t *testing.T) {
+	s := newRecipeMetadataService()
+
+	err := s.PurgeByRecipe(recipeFound)
+
+	assert.NoError(t, err)
+}
+
+func TestRecipeMetadataPurgeByRecipe_Err(t *testing.T) {
+	s := newRecipeMetadataService()
+
+	err := s.PurgeByRecipe(recipeError)
+
+	assert.EqualError(t, err, "internal server error")
+}
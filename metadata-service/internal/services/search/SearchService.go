@@ -1,11 +1,16 @@
 package services
 
 import (
+	"errors"
+
 	m "metadata-service/internal/models"
 )
 
+const defaultLimit = 20
+
 type SearchRepository interface {
-	SearchMetadata(request m.MetadataSearchRequest) ([]m.MetadataSearchResult, error)
+	SearchMetadata(request m.MetadataSearchRequest) (m.MetadataSearchPage, error)
+	SearchFacets(request m.MetadataSearchRequest) (m.MetadataFacets, error)
 }
 
 type SearchService struct {
@@ -19,26 +24,66 @@ func NewSearchService(repo SearchRepository) *SearchService {
 	}
 }
 
-func (s SearchService) SearchMetadata(searchRequestDTO m.MetadataSearchRequestDTO) ([]m.MetadataSearchResultDTO, error) {
+func (s SearchService) SearchMetadata(searchRequestDTO m.MetadataSearchRequestDTO) (m.MetadataSearchResponseDTO, error) {
 	var searchRequest m.MetadataSearchRequest = m.MetadataSearchRequest{
-		CategoryID:        &searchRequestDTO.CategoryID,
-		TagID:             &searchRequestDTO.TagID,
-		DifficultyLevelID: &searchRequestDTO.DifficultyLevelID,
-		CuisineTypeID:     &searchRequestDTO.CuisineTypeID,
+		CategoryIDs:       searchRequestDTO.CategoryIDs,
+		TagIDs:            searchRequestDTO.TagIDs,
+		TagMode:           searchRequestDTO.TagMode,
+		CuisineTypeIDs:    searchRequestDTO.CuisineTypeIDs,
+		DifficultyLevelID: searchRequestDTO.DifficultyLevelID,
+		MinPrepTime:       searchRequestDTO.MinPrepTime,
+		MaxPrepTime:       searchRequestDTO.MaxPrepTime,
+		Sort:              searchRequestDTO.Sort,
+		Limit:             searchRequestDTO.Limit,
+	}
+
+	if searchRequest.MinPrepTime != nil && searchRequest.MaxPrepTime != nil && *searchRequest.MinPrepTime > *searchRequest.MaxPrepTime {
+		return m.MetadataSearchResponseDTO{}, errors.New("min_prep_time exceeds max_prep_time")
+	}
+
+	if searchRequest.TagMode == "" {
+		searchRequest.TagMode = m.TagModeAll
+	}
+	if searchRequest.Sort == "" {
+		searchRequest.Sort = m.SearchSortNewest
+	}
+	if searchRequest.Limit <= 0 {
+		searchRequest.Limit = defaultLimit
 	}
 
-	// do this separate, as otherwise both min and max will be 0 and we want nils
-	if searchRequestDTO.MinPrepTime != nil {
-		searchRequest.MinPrepTime = searchRequestDTO.MinPrepTime
+	if searchRequestDTO.Cursor != "" {
+		cursor, err := m.DecodeMetadataSearchCursor(searchRequestDTO.Cursor)
+		if err != nil {
+			return m.MetadataSearchResponseDTO{}, err
+		}
+
+		// a cursor only points to a position within the order it was created for
+		if cursor.Sort != searchRequest.Sort {
+			return m.MetadataSearchResponseDTO{}, errors.New("invalid cursor")
+		}
+
+		searchRequest.Cursor = &cursor
 	}
-	if searchRequestDTO.MaxPrepTime != nil {
-		searchRequest.MaxPrepTime = searchRequestDTO.MaxPrepTime
+
+	page, err := s.repo.SearchMetadata(searchRequest)
+	if err != nil {
+		return m.MetadataSearchResponseDTO{}, errors.New("internal server error")
 	}
 
-	results, err := s.repo.SearchMetadata(searchRequest)
+	facets, err := s.repo.SearchFacets(searchRequest)
 	if err != nil {
-		return nil, err
+		return m.MetadataSearchResponseDTO{}, errors.New("internal server error")
+	}
+
+	response := m.MetadataSearchResponseDTO{
+		Results: m.MetadataSearchResult{}.ConvertAllToDTO(page.Results),
+		Total:   page.Total,
+		Facets:  facets.ConvertToDTO(),
+	}
+
+	if page.Next != nil {
+		response.NextCursor = page.Next.Encode()
 	}
 
-	return m.MetadataSearchResult{}.ConvertAllToDTO(results), nil
+	return response, nil
 }
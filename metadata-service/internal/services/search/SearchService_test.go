@@ -15,33 +15,50 @@ var (
 	id      uuid.UUID = uuid.New()
 
 	searchRequest m.MetadataSearchRequestDTO = m.MetadataSearchRequestDTO{
-		RecipeID:          id,
-		CategoryID:        id,
-		TagID:             id,
-		DifficultyLevelID: id,
-		CuisineTypeID:     id,
+		CategoryIDs:       []uuid.UUID{id},
+		TagIDs:            []uuid.UUID{id},
+		CuisineTypeIDs:    []uuid.UUID{id},
+		DifficultyLevelID: &id,
 		MinPrepTime:       &minTime,
 		MaxPrepTime:       &maxTime,
 	}
+
+	// the last request the repository received
+	received m.MetadataSearchRequest
 )
 
 type searchRepositoryMock struct{}
 
-func (*searchRepositoryMock) SearchMetadata(request m.MetadataSearchRequest) ([]m.MetadataSearchResult, error) {
+func (*searchRepositoryMock) SearchMetadata(request m.MetadataSearchRequest) (m.MetadataSearchPage, error) {
+	received = request
+
+	switch *request.MinPrepTime {
+	case 1, 3:
+		return m.MetadataSearchPage{
+			Results: []m.MetadataSearchResult{{
+				RecipeID:          id,
+				CategoryIDs:       []uuid.UUID{id},
+				DifficultyLevelID: id,
+				CuisineTypeID:     id,
+				PreparationTimeID: id,
+			}},
+			Total: 2,
+			Next:  &m.MetadataSearchCursor{Sort: request.Sort, RecipeID: id},
+		}, nil
+	default:
+		return m.MetadataSearchPage{}, errors.New("error")
+	}
+}
+
+func (*searchRepositoryMock) SearchFacets(request m.MetadataSearchRequest) (m.MetadataFacets, error) {
 	switch *request.MinPrepTime {
 	case 1:
-		var response []m.MetadataSearchResult
-		response = append(response, m.MetadataSearchResult{
-			RecipeID:          id,
-			CategoryIDs:       []uuid.UUID{id},
-			TagIDs:            []uuid.UUID{id},
-			DifficultyLevelID: id,
-			CuisineTypeID:     id,
-			PreparationTimeID: id,
-		})
-		return response, nil
+		return m.MetadataFacets{
+			Categories:       []m.MetadataFacet{{ID: id, Name: "Dinner", Count: 1}},
+			PreparationTimes: []m.PreparationTimeFacet{{Name: "up to 15 min", MinPrepTime: 0, MaxPrepTime: &maxTime, Count: 1}},
+		}, nil
 	default:
-		return nil, errors.New("error")
+		return m.MetadataFacets{}, errors.New("error")
 	}
 }
 
@@ -53,16 +70,108 @@ func TestSearchMetadata_OK(t *testing.T) {
 	result, err := s.SearchMetadata(searchRequest)
 
 	assert.NoError(t, err)
-	assert.IsType(t, []m.MetadataSearchResultDTO{}, result)
+	assert.IsType(t, m.MetadataSearchResponseDTO{}, result)
+	assert.Len(t, result.Results, 1)
+	assert.Equal(t, []uuid.UUID{}, result.Results[0].TagIDs)
+	assert.Equal(t, int64(2), result.Total)
+	assert.NotEmpty(t, result.NextCursor)
+	assert.Equal(t, []m.MetadataFacetDTO{{ID: id, Name: "Dinner", Count: 1}}, result.Facets.Categories)
+	assert.Equal(t, []m.MetadataFacetDTO{}, result.Facets.Tags)
+	assert.Len(t, result.Facets.PreparationTimes, 1)
+
+	// defaults
+	assert.Equal(t, m.TagModeAll, received.TagMode)
+	assert.Equal(t, m.SearchSortNewest, received.Sort)
+	assert.Equal(t, defaultLimit, received.Limit)
+	assert.Nil(t, received.Cursor)
+}
+
+func TestSearchMetadata_Cursor(t *testing.T) {
+	s := NewSearchService(&searchRepositoryMock{})
+
+	request := searchRequest
+	request.Sort = m.SearchSortPrepTime
+	request.TagMode = m.TagModeAny
+	request.Limit = 5
+	request.Cursor = m.MetadataSearchCursor{Sort: m.SearchSortPrepTime, Duration: 30, RecipeID: id}.Encode()
+
+	result, err := s.SearchMetadata(request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.TagModeAny, received.TagMode)
+	assert.Equal(t, 5, received.Limit)
+	assert.Equal(t, &m.MetadataSearchCursor{Sort: m.SearchSortPrepTime, Duration: 30, RecipeID: id}, received.Cursor)
+
+	next, err := m.DecodeMetadataSearchCursor(result.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, m.MetadataSearchCursor{Sort: m.SearchSortPrepTime, RecipeID: id}, next)
+}
+
+func TestSearchMetadata_InvalidCursor(t *testing.T) {
+	s := NewSearchService(&searchRepositoryMock{})
+
+	for _, cursor := range []string{
+		"not a cursor",
+		m.MetadataSearchCursor{Sort: m.SearchSortNewest}.Encode(),
+		m.MetadataSearchCursor{Sort: m.SearchSortPrepTime, RecipeID: id}.Encode(), // created for another order
+	} {
+		request := searchRequest
+		request.Cursor = cursor
+
+		_, err := s.SearchMetadata(request)
+
+		assert.EqualError(t, err, "invalid cursor")
+	}
+}
+
+func TestSearchMetadata_PrepTimeRangeErr(t *testing.T) {
+	s := NewSearchService(&searchRepositoryMock{})
+
+	request := searchRequest
+	request.MinPrepTime = &maxTime
+	request.MaxPrepTime = &minTime
+
+	_, err := s.SearchMetadata(request)
+
+	assert.EqualError(t, err, "min_prep_time exceeds max_prep_time")
+}
+
+func TestSearchMetadata_NameSort(t *testing.T) {
+	s := NewSearchService(&searchRepositoryMock{})
+
+	request := searchRequest
+	request.Sort = m.SearchSortName
+	request.Cursor = m.MetadataSearchCursor{Sort: m.SearchSortName, Name: "apple pie", RecipeID: id}.Encode()
+
+	_, err := s.SearchMetadata(request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.SearchSortName, received.Sort)
+	assert.Equal(t, &m.MetadataSearchCursor{Sort: m.SearchSortName, Name: "apple pie", RecipeID: id}, received.Cursor)
 }
 
 func TestSearchMetadata_Error(t *testing.T) {
 	s := NewSearchService(&searchRepositoryMock{})
 
-	minTime = 2
+	request := searchRequest
+	request.MinPrepTime = &maxTime
+
+	_, err := s.SearchMetadata(request)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "internal server error")
+}
+
+func TestSearchMetadata_FacetsErr(t *testing.T) {
+	s := NewSearchService(&searchRepositoryMock{})
+
+	facetsErrTime := 3
+	request := searchRequest
+	request.MinPrepTime = &facetsErrTime
+	request.MaxPrepTime = &facetsErrTime
 
-	_, err := s.SearchMetadata(searchRequest)
+	_, err := s.SearchMetadata(request)
 
 	assert.Error(t, err)
-	assert.EqualError(t, err, "error")
+	assert.EqualError(t, err, "internal server error")
 }
@@ -11,9 +11,12 @@ import (
 type TagRepository interface {
 	FindAll() ([]m.Tag, error)
 	FindSingle(tag m.Tag) (m.Tag, error)
+	FindByName(name string) (m.Tag, error)
 	Create(tag m.Tag) (m.Tag, error)
 	Update(tag m.Tag) (m.Tag, error)
 	Delete(tag m.Tag) error
+	Reassign(tag m.Tag, replacement m.Tag) error
+	Merge(target m.Tag, sources []m.Tag, preview bool) (m.MergeResult, error)
 }
 type TagService struct {
 	repo TagRepository
@@ -57,18 +60,51 @@ func (s TagService) FindSingle(tagDTO m.TagDTO) (m.TagDTO, error) {
 	return tag.ConvertToDTO(), nil
 }
 
+// Lookup finds the tag a name refers to, by its normalized name or one of its aliases
+func (s TagService) Lookup(name string) (m.TagDTO, error) {
+
+	tag, err := s.repo.FindByName(name)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return m.TagDTO{}, err
+		default:
+			return m.TagDTO{}, errors.New("internal server error")
+		}
+	}
+
+	return tag.ConvertToDTO(), nil
+}
+
+// Create adds a tag. When the name already refers to a tag, that one is returned along with a
+// "tag already exists" error instead of adding a duplicate.
 func (s TagService) Create(tagDTO m.TagDTO) (m.TagDTO, error) {
 
 	if tagDTO.ID != uuid.Nil {
 		return m.TagDTO{}, errors.New("existing id on new element is not allowed")
 	}
 
-	if tagDTO.Name == "" {
+	tag := tagDTO.ConvertFromDTO()
+	if tag.NormalizedName == "" {
 		return m.TagDTO{}, errors.New("name is empty")
 	}
 
-	created, err := s.repo.Create(tagDTO.ConvertFromDTO())
-	if err != nil {
+	found, err := s.repo.FindByName(tag.Name)
+	if err == nil {
+		return found.ConvertToDTO(), errors.New("tag already exists")
+	} else if err.Error() != "not found" {
+		return m.TagDTO{}, errors.New("internal server error")
+	}
+
+	if err := s.checkAliases(tag); err != nil {
+		return m.TagDTO{}, err
+	}
+
+	created, err := s.repo.Create(tag)
+	if err != nil && err.Error() == "already exists" {
+		found, err := s.conflict(tag)
+		return found.ConvertToDTO(), err
+	} else if err != nil {
 		return m.TagDTO{}, err
 	}
 
@@ -77,12 +113,27 @@ func (s TagService) Create(tagDTO m.TagDTO) (m.TagDTO, error) {
 
 func (s TagService) Update(tagDTO m.TagDTO) (m.TagDTO, error) {
 
-	if tagDTO.Name == "" {
+	tag := tagDTO.ConvertFromDTO()
+	if tag.NormalizedName == "" {
 		return m.TagDTO{}, errors.New("name is empty")
 	}
 
-	updatedTag, err := s.repo.Update(tagDTO.ConvertFromDTO())
-	if err != nil {
+	found, err := s.repo.FindByName(tag.Name)
+	if err == nil && found.ID != tag.ID {
+		return m.TagDTO{}, errors.New("tag already exists")
+	} else if err != nil && err.Error() != "not found" {
+		return m.TagDTO{}, errors.New("internal server error")
+	}
+
+	if err := s.checkAliases(tag); err != nil {
+		return m.TagDTO{}, err
+	}
+
+	updatedTag, err := s.repo.Update(tag)
+	if err != nil && err.Error() == "already exists" {
+		_, err := s.conflict(tag)
+		return m.TagDTO{}, err
+	} else if err != nil {
 		return m.TagDTO{}, err
 	}
 
@@ -98,3 +149,78 @@ func (s TagService) Delete(tagDTO m.TagDTO) error {
 
 	return nil
 }
+
+// Reassign moves the recipes associated with a tag to the replacement before deleting it
+func (s TagService) Reassign(tagDTO m.TagDTO, replacementDTO m.TagDTO) error {
+
+	if replacementDTO.ID == tagDTO.ID {
+		return errors.New("tag cannot be reassigned to itself")
+	}
+
+	err := s.repo.Reassign(tagDTO.ConvertFromDTO(), replacementDTO.ConvertFromDTO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Merge folds duplicate tags into the target, or with preview only tells what doing so would change
+func (s TagService) Merge(mergeDTO m.MergeRequestDTO, preview bool) (m.MergeResultDTO, error) {
+	var sources []m.Tag
+
+	seen := map[uuid.UUID]bool{}
+	for _, sourceID := range mergeDTO.SourceIDs {
+		if sourceID == mergeDTO.TargetID {
+			return m.MergeResultDTO{}, errors.New("tag cannot be merged into itself")
+		}
+
+		if !seen[sourceID] {
+			seen[sourceID] = true
+			sources = append(sources, m.Tag{ID: sourceID})
+		}
+	}
+
+	if len(sources) == 0 {
+		return m.MergeResultDTO{}, errors.New("no tags to merge")
+	}
+
+	result, err := s.repo.Merge(m.Tag{ID: mergeDTO.TargetID}, sources, preview)
+	if err != nil {
+		return m.MergeResultDTO{}, err
+	}
+
+	return result.ConvertToDTO(preview), nil
+}
+
+// checkAliases makes sure the aliases of a tag do not already refer to another tag
+func (s TagService) checkAliases(tag m.Tag) error {
+
+	for _, alias := range tag.Aliases {
+		found, err := s.repo.FindByName(alias.Name)
+		if err == nil && found.ID != tag.ID {
+			return errors.New("alias is already used by another tag")
+		} else if err != nil && err.Error() != "not found" {
+			return errors.New("internal server error")
+		}
+	}
+
+	return nil
+}
+
+// conflict tells which name a tag collided with when storing it violated a unique name. Another tag may
+// have been stored with the name or one of the aliases after they were checked, otherwise the name is still held
+// by a deleted tag.
+func (s TagService) conflict(tag m.Tag) (m.Tag, error) {
+
+	found, err := s.repo.FindByName(tag.Name)
+	if err == nil && found.ID != tag.ID {
+		return found, errors.New("tag already exists")
+	}
+
+	if err := s.checkAliases(tag); err != nil {
+		return m.Tag{}, err
+	}
+
+	return m.Tag{}, errors.New("name is already used")
+}
@@ -47,10 +47,25 @@ func (*TagRepositoryMock) FindSingle(tag m.Tag) (m.Tag, error) {
 	}
 }
 
+func (*TagRepositoryMock) FindByName(name string) (m.Tag, error) {
+	switch name {
+	case "find", "Vegetables":
+		return tag, nil
+	case "taken":
+		return m.Tag{ID: uuid.New(), Name: "taken"}, nil
+	case "lookuperror":
+		return m.Tag{}, errors.New("error")
+	default:
+		return m.Tag{}, errors.New("not found")
+	}
+}
+
 func (*TagRepositoryMock) Create(tag m.Tag) (m.Tag, error) {
 	switch tag.Name {
 	case "create":
 		return tag, nil
+	case "duplicate":
+		return m.Tag{}, errors.New("already exists")
 	default:
 		return m.Tag{}, errors.New("error")
 	}
@@ -60,6 +75,8 @@ func (*TagRepositoryMock) Update(tag m.Tag) (m.Tag, error) {
 	switch tag.Name {
 	case "update":
 		return tag, nil
+	case "duplicate":
+		return m.Tag{}, errors.New("already exists")
 	default:
 		return m.Tag{}, errors.New("error")
 	}
@@ -74,6 +91,28 @@ func (*TagRepositoryMock) Delete(tag m.Tag) error {
 	}
 }
 
+func (*TagRepositoryMock) Reassign(tag m.Tag, replacement m.Tag) error {
+	switch tag.Name {
+	case "delete":
+		return nil
+	default:
+		return errors.New("error")
+	}
+}
+
+func (*TagRepositoryMock) Merge(target m.Tag, sources []m.Tag, preview bool) (m.MergeResult, error) {
+	switch {
+	case target.ID == tag.ID:
+		result := m.MergeResult{TargetID: target.ID, Aliases: []string{"duplicate"}}
+		for _, source := range sources {
+			result.SourceIDs = append(result.SourceIDs, source.ID)
+		}
+		return result, nil
+	default:
+		return m.MergeResult{}, errors.New("target not found")
+	}
+}
+
 // ======================================================================
 
 func TestTagFindAll_OK(t *testing.T) {
@@ -204,6 +243,18 @@ func TestTagCreate_CreateErr(t *testing.T) {
 	assert.EqualError(t, err, "error")
 }
 
+func TestTagCreate_DuplicateErr(t *testing.T) {
+	s := NewTagService(&TagRepositoryMock{})
+
+	tagDTO := m.TagDTO{
+		Name: "duplicate",
+	}
+	result, err := s.Create(tagDTO)
+
+	assert.Equal(t, uuid.Nil, result.ID)
+	assert.EqualError(t, err, "name is already used")
+}
+
 func TestTagUpdate_OK(t *testing.T) {
 	s := NewTagService(&TagRepositoryMock{})
 
@@ -245,6 +296,18 @@ func TestTagUpdate_UpdateErr(t *testing.T) {
 	assert.EqualError(t, err, "error")
 }
 
+func TestTagUpdate_DuplicateErr(t *testing.T) {
+	s := NewTagService(&TagRepositoryMock{})
+
+	tagDTO := m.TagDTO{
+		ID:   tag.ID,
+		Name: "duplicate",
+	}
+	_, err := s.Update(tagDTO)
+
+	assert.EqualError(t, err, "name is already used")
+}
+
 func TestTagDelete_OK(t *testing.T) {
 	s := NewTagService(&TagRepositoryMock{})
 
@@ -269,3 +332,148 @@ func TestTagDelete_DeleteErr(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualError(t, err, "error")
 }
+
+func TestTagReassign_OK(t *testing.T) {
+	s := NewTagService(&TagRepositoryMock{})
+
+	tagDTO := m.TagDTO{
+		ID:   tag.ID,
+		Name: "delete",
+	}
+	err := s.Reassign(tagDTO, m.TagDTO{ID: uuid.New()})
+
+	assert.NoError(t, err)
+}
+
+func TestTagReassign_SelfErr(t *testing.T) {
+	s := NewTagService(&TagRepositoryMock{})
+
+	tagDTO := m.TagDTO{
+		ID:   tag.ID,
+		Name: "delete",
+	}
+	err := s.Reassign(tagDTO, m.TagDTO{ID: tag.ID})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "tag cannot be reassigned to itself")
+}
+
+func TestTagReassign_Err(t *testing.T) {
+	s := NewTagService(&TagRepositoryMock{})
+
+	tagDTO := m.TagDTO{
+		ID:   tag.ID,
+		Name: "error",
+	}
+	err := s.Reassign(tagDTO, m.TagDTO{ID: uuid.New()})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "error")
+}
+
+func TestTagMerge_OK(t *testing.T) {
+	s := NewTagService(&TagRepositoryMock{})
+
+	sourceID := uuid.New()
+
+	result, err := s.Merge(m.MergeRequestDTO{TargetID: tag.ID, SourceIDs: []uuid.UUID{sourceID, sourceID}}, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, tag.ID, result.TargetID)
+	assert.Equal(t, []uuid.UUID{sourceID}, result.Merged)
+	assert.Equal(t, []string{"duplicate"}, result.Aliases)
+	assert.Equal(t, []uuid.UUID{}, result.RecipeIDs)
+	assert.True(t, result.Preview)
+}
+
+func TestTagMerge_SelfErr(t *testing.T) {
+	s := NewTagService(&TagRepositoryMock{})
+
+	_, err := s.Merge(m.MergeRequestDTO{TargetID: tag.ID, SourceIDs: []uuid.UUID{tag.ID}}, false)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "tag cannot be merged into itself")
+}
+
+func TestTagMerge_EmptyErr(t *testing.T) {
+	s := NewTagService(&TagRepositoryMock{})
+
+	_, err := s.Merge(m.MergeRequestDTO{TargetID: tag.ID}, false)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "no tags to merge")
+}
+
+func TestTagMerge_Err(t *testing.T) {
+	s := NewTagService(&TagRepositoryMock{})
+
+	_, err := s.Merge(m.MergeRequestDTO{TargetID: uuid.New(), SourceIDs: []uuid.UUID{uuid.New()}}, false)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "target not found")
+}
+
+func TestTagCreate_ExistsErr(t *testing.T) {
+	s := NewTagService(&TagRepositoryMock{})
+
+	tagDTO := m.TagDTO{
+		Name: "Vegetables",
+	}
+	result, err := s.Create(tagDTO)
+
+	assert.Error(t, err)
+	assert.Equal(t, tag.ID, result.ID)
+	assert.EqualError(t, err, "tag already exists")
+}
+
+func TestTagCreate_AliasTakenErr(t *testing.T) {
+	s := NewTagService(&TagRepositoryMock{})
+
+	tagDTO := m.TagDTO{
+		Name:    "create",
+		Aliases: []string{"taken"},
+	}
+	result, err := s.Create(tagDTO)
+
+	assert.Error(t, err)
+	assert.Equal(t, m.TagDTO{}, result)
+	assert.EqualError(t, err, "alias is already used by another tag")
+}
+
+func TestTagUpdate_ExistsErr(t *testing.T) {
+	s := NewTagService(&TagRepositoryMock{})
+
+	tagDTO := m.TagDTO{
+		ID:   tag.ID,
+		Name: "taken",
+	}
+	_, err := s.Update(tagDTO)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "tag already exists")
+}
+
+func TestTagLookup_OK(t *testing.T) {
+	s := NewTagService(&TagRepositoryMock{})
+
+	result, err := s.Lookup("Vegetables")
+
+	assert.NoError(t, err)
+	assert.Equal(t, tag.ID, result.ID)
+}
+
+func TestTagLookup_NotFoundErr(t *testing.T) {
+	s := NewTagService(&TagRepositoryMock{})
+
+	_, err := s.Lookup("unknown")
+
+	assert.EqualError(t, err, "not found")
+}
+
+func TestTagLookup_Err(t *testing.T) {
+	s := NewTagService(&TagRepositoryMock{})
+
+	_, err := s.Lookup("lookuperror")
+
+	assert.EqualError(t, err, "internal server error")
+}
@@ -0,0 +1,87 @@
+// Package normalize reduces names to the form they are matched by, so that "Eggs", "egg" and "ＥＧＧ" are the same
+package normalize
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// irregular maps the plurals the suffix rules of Singular get wrong to their singular
+var irregular = map[string]string{
+	"brioches":   "brioche",
+	"brownies":   "brownie",
+	"calves":     "calf",
+	"chilies":    "chili",
+	"cookies":    "cookie",
+	"ganaches":   "ganache",
+	"geese":      "goose",
+	"halves":     "half",
+	"knives":     "knife",
+	"leaves":     "leaf",
+	"loaves":     "loaf",
+	"mice":       "mouse",
+	"pies":       "pie",
+	"quiches":    "quiche",
+	"smoothies":  "smoothie",
+	"teeth":      "tooth",
+	"veggies":    "veggie",
+	"wolves":     "wolf",
+	"zucchinis":  "zucchini",
+	"zucchinies": "zucchini",
+}
+
+// uncountable holds the words ending in s that have no singular
+var uncountable = map[string]bool{
+	"grits":    true,
+	"molasses": true,
+	"news":     true,
+	"series":   true,
+	"species":  true,
+	"swiss":    true,
+}
+
+// Name normalizes a name for matching: Unicode compatibility normalization, case folding, collapsed whitespace and
+// the last word, which names the thing in English, in singular. "Green  Onions" becomes "green onion".
+func Name(name string) string {
+	// a caser keeps state and is not safe for concurrent use, so each call gets its own
+	words := strings.Fields(cases.Fold().String(norm.NFKC.String(name)))
+	if len(words) == 0 {
+		return ""
+	}
+
+	words[len(words)-1] = Singular(words[len(words)-1])
+
+	return strings.Join(words, " ")
+}
+
+// Singular returns the singular of a case folded English word. It applies the common suffix rules, so it is a
+// best guess for words it does not know, which can be matched by an alias instead.
+func Singular(word string) string {
+	if singular, ok := irregular[word]; ok {
+		return singular
+	}
+
+	switch {
+	case uncountable[word], len(word) <= 3:
+		return word
+	case strings.HasSuffix(word, "ies"):
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "oes"),
+		strings.HasSuffix(word, "ches"),
+		strings.HasSuffix(word, "shes"),
+		strings.HasSuffix(word, "sses"),
+		strings.HasSuffix(word, "xes"),
+		strings.HasSuffix(word, "zzes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "ss"),
+		strings.HasSuffix(word, "us"),
+		strings.HasSuffix(word, "is"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return strings.TrimSuffix(word, "s")
+	default:
+		return word
+	}
+}
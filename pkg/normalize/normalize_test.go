@@ -0,0 +1,51 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestName(t *testing.T) {
+	tests := map[string]string{
+		"egg":             "egg",
+		"Eggs":            "egg",
+		"EGGS":            "egg",
+		"ＥＧＧ":             "egg",
+		"  Green  Onions": "green onion",
+		"Crème Fraîche":   "crème fraîche",
+		"Crème":          "crème",
+		"Straße":          "strasse",
+		"":                "",
+		"   ":             "",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, Name(input), input)
+	}
+}
+
+func TestSingular(t *testing.T) {
+	tests := map[string]string{
+		"tomatoes":  "tomato",
+		"berries":   "berry",
+		"peaches":   "peach",
+		"radishes":  "radish",
+		"glasses":   "glass",
+		"boxes":     "box",
+		"carrots":   "carrot",
+		"cheeses":   "cheese",
+		"leaves":    "leaf",
+		"cookies":   "cookie",
+		"asparagus": "asparagus",
+		"hummus":    "hummus",
+		"molasses":  "molasses",
+		"peas":      "pea",
+		"gas":       "gas",
+		"rice":      "rice",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, Singular(input), input)
+	}
+}
@@ -0,0 +1,65 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+)
+
+// Broker delivers published events to their consumers. Publish only returns without an error once the broker
+// accepted the event, the relay keeps the message in the outbox until then.
+type Broker interface {
+	Publish(ctx context.Context, event CloudEvent) error
+}
+
+// MemoryBroker is an embedded broker delivering events to in-process handlers, for tests and single instance setups.
+// Events published again with an ID it has seen before are dropped, like the NATS broker does.
+type MemoryBroker struct {
+	mu       sync.Mutex
+	seen     map[string]bool
+	events   []CloudEvent
+	handlers []func(event CloudEvent) error
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		seen: make(map[string]bool),
+	}
+}
+
+// Subscribe registers a handler for all events published from now on
+func (b *MemoryBroker) Subscribe(handler func(event CloudEvent) error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish hands the event to the handlers in order. The event is not marked as seen when a handler fails,
+// so it is delivered again when the relay retries.
+func (b *MemoryBroker) Publish(ctx context.Context, event CloudEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.seen[event.ID] {
+		return nil
+	}
+
+	for _, handler := range b.handlers {
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+
+	b.seen[event.ID] = true
+	b.events = append(b.events, event)
+
+	return nil
+}
+
+// Events returns the events delivered so far, in the order they were published
+func (b *MemoryBroker) Events() []CloudEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]CloudEvent(nil), b.events...)
+}
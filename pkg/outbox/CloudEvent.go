@@ -0,0 +1,42 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	// TypePrefix namespaces the event types, a deleted recipe is published as cookbook.recipe.deleted
+	TypePrefix = "cookbook"
+
+	cloudEventsVersion = "1.0"
+	// ContentType is the content type of a CloudEvent in structured mode
+	ContentType = "application/cloudevents+json"
+)
+
+// CloudEvent is an outbox message in the CloudEvents 1.0 JSON format. The data holds the change event as recorded.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// NewCloudEvent converts an outbox message. The subject is the ID of the aggregate the message belongs to.
+func NewCloudEvent(source string, message Message) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     cloudEventsVersion,
+		ID:              message.ID.String(),
+		Source:          source,
+		Type:            fmt.Sprintf("%s.%s.%s", TypePrefix, message.AggregateType, message.Type),
+		Subject:         message.AggregateID.String(),
+		Time:            message.OccurredAt,
+		DataContentType: "application/json",
+		Data:            message.Data,
+	}
+}
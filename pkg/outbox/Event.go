@@ -0,0 +1,30 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChangeEvent tells other services, such as the search service, that an entity was created, updated or deleted
+type ChangeEvent struct {
+	Type       string    `json:"type"`
+	EntityType string    `json:"entity_type"`
+	EntityID   uuid.UUID `json:"entity_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func NewChangeEvent(eventType string, entityType string, entityID uuid.UUID) ChangeEvent {
+	return ChangeEvent{
+		Type:       eventType,
+		EntityType: entityType,
+		EntityID:   entityID,
+		OccurredAt: time.Now(),
+	}
+}
+
+// Logger is the part of the service logger the relay reports to
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+}
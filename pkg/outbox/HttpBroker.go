@@ -0,0 +1,70 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// HTTPClient sends the requests of the HttpBroker, authenticating them when needed
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// HttpBroker publishes events by posting them to the services subscribed to them
+type HttpBroker struct {
+	httpClient  HTTPClient
+	subscribers []string
+	logger      Logger
+}
+
+// NewHttpBroker creates a broker posting events to the given urls. Authentication is left to the given http client.
+func NewHttpBroker(httpClient HTTPClient, subscribers []string, logger Logger) *HttpBroker {
+	return &HttpBroker{
+		httpClient:  httpClient,
+		subscribers: subscribers,
+		logger:      logger,
+	}
+}
+
+// Publish posts an event to all subscribers in the CloudEvents binary mode: the change event is the body and the
+// event attributes are sent as headers. It fails when a subscriber does not accept the event, the relay then
+// publishes it to all subscribers again. A subscriber can therefore receive an event more than once, and has to
+// handle it in a way that can be repeated, as the search service does by reindexing from the current state.
+func (b HttpBroker) Publish(ctx context.Context, event CloudEvent) error {
+	for _, subscriber := range b.subscribers {
+		if err := b.send(ctx, subscriber, event); err != nil {
+			return fmt.Errorf("%s: %w", subscriber, err)
+		}
+	}
+
+	return nil
+}
+
+func (b HttpBroker) send(ctx context.Context, endpoint string, event CloudEvent) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(event.Data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", event.DataContentType)
+	req.Header.Set("Ce-Specversion", event.SpecVersion)
+	req.Header.Set("Ce-Id", event.ID)
+	req.Header.Set("Ce-Source", event.Source)
+	req.Header.Set("Ce-Type", event.Type)
+	req.Header.Set("Ce-Subject", event.Subject)
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	b.logger.Debugf("sent event %s (%s) to %s", event.ID, event.Type, endpoint)
+
+	return nil
+}
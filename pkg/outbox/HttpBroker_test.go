@@ -0,0 +1,72 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCloudEvent(eventType string) (CloudEvent, ChangeEvent) {
+	change := NewChangeEvent(eventType, "recipe", uuid.New())
+	data, _ := json.Marshal(change)
+
+	return NewCloudEvent("/recipe-service", Message{
+		ID:            uuid.New(),
+		AggregateType: change.EntityType,
+		AggregateID:   change.EntityID,
+		Type:          change.Type,
+		Data:          data,
+		OccurredAt:    change.OccurredAt,
+	}), change
+}
+
+func TestHttpPublish_OK(t *testing.T) {
+	var received []string
+
+	event, change := newCloudEvent("updated")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result ChangeEvent
+
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, event.ID, r.Header.Get("Ce-Id"))
+		assert.Equal(t, "cookbook.recipe.updated", r.Header.Get("Ce-Type"))
+		assert.Equal(t, "/recipe-service", r.Header.Get("Ce-Source"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&result))
+
+		assert.Equal(t, change.Type, result.Type)
+		assert.Equal(t, "recipe", result.EntityType)
+		assert.Equal(t, change.EntityID, result.EntityID)
+
+		received = append(received, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	b := NewHttpBroker(http.DefaultClient, []string{srv.URL + "/first", srv.URL + "/second"}, &LoggerMock{})
+
+	err := b.Publish(context.Background(), event)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/first", "/second"}, received)
+}
+
+func TestHttpPublish_Err(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	b := NewHttpBroker(http.DefaultClient, []string{srv.URL}, &LoggerMock{})
+	event, _ := newCloudEvent("deleted")
+
+	err := b.Publish(context.Background(), event)
+
+	assert.EqualError(t, err, srv.URL+": unexpected status code 401")
+}
@@ -0,0 +1,72 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/nats-io/nats.go"
+)
+
+// StreamName is the JetStream stream keeping the events of all services
+const StreamName = "COOKBOOK"
+
+// NatsBroker publishes events to a NATS JetStream stream, on the subject named after the event type.
+// The event ID is sent as message ID, so JetStream drops events the relay publishes again within its duplicate window.
+type NatsBroker struct {
+	conn *nats.Conn
+	js   nats.JetStreamContext
+}
+
+// NewNatsBroker connects to NATS and creates the stream when it does not exist yet
+func NewNatsBroker(url string) (*NatsBroker, error) {
+	conn, err := nats.Connect(url, nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if _, err := js.StreamInfo(StreamName); err != nil {
+		if !errors.Is(err, nats.ErrStreamNotFound) {
+			conn.Close()
+			return nil, err
+		}
+
+		if _, err := js.AddStream(&nats.StreamConfig{
+			Name:     StreamName,
+			Subjects: []string{TypePrefix + ".>"},
+		}); err != nil && !errors.Is(err, nats.ErrStreamNameAlreadyInUse) {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return &NatsBroker{
+		conn: conn,
+		js:   js,
+	}, nil
+}
+
+func (b NatsBroker) Publish(ctx context.Context, event CloudEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(event.Type)
+	msg.Header.Set("Content-Type", ContentType)
+	msg.Data = body
+
+	_, err = b.js.PublishMsg(msg, nats.MsgId(event.ID), nats.Context(ctx))
+	return err
+}
+
+// Close drains the connection
+func (b NatsBroker) Close() error {
+	return b.conn.Drain()
+}
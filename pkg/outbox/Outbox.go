@@ -0,0 +1,58 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Message is a change event waiting in the outbox until the relay published it. The sequence orders the messages
+// of an aggregate, the ID is passed on as the event ID so consumers can recognise redelivered events.
+type Message struct {
+	Sequence      int64     `gorm:"primaryKey;autoIncrement"`
+	ID            uuid.UUID `gorm:"type:uuid;uniqueIndex"`
+	AggregateType string    `gorm:"index:idx_outbox_aggregate"`
+	AggregateID   uuid.UUID `gorm:"type:uuid;index:idx_outbox_aggregate"`
+	Type          string
+	Data          []byte `gorm:"type:jsonb"`
+	OccurredAt    time.Time
+	Attempts      int
+	LastError     string
+	NextAttemptAt *time.Time `gorm:"index"` // set when publishing failed, the aggregate waits until then
+	LeaseUntil    *time.Time `gorm:"index"` // set while a relay publishes the message
+}
+
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+// Record writes events to the outbox. Pass the transaction of the change the events report, so they are only
+// published when the change is committed and are never lost when it is.
+func Record(tx *gorm.DB, events ...ChangeEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	messages := make([]Message, 0, len(events))
+
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		messages = append(messages, Message{
+			ID:            uuid.New(),
+			AggregateType: event.EntityType,
+			AggregateID:   event.EntityID,
+			Type:          event.Type,
+			Data:          data,
+			OccurredAt:    event.OccurredAt,
+		})
+	}
+
+	// the columns of the relay are left empty until it claims the message
+	return tx.Omit("NextAttemptAt", "LeaseUntil").Create(&messages).Error
+}
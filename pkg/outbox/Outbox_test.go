@@ -0,0 +1,130 @@
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	aggregateID uuid.UUID = uuid.New()
+)
+
+func newMockDatabase(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+
+	var mockDB *gorm.DB
+
+	newLogger := logger.New(
+		log.New(os.Stdout, "\r\n", log.LstdFlags), // io writer
+		logger.Config{
+			SlowThreshold:             time.Second, // Slow SQL threshold
+			LogLevel:                  logger.Info, // Log level
+			IgnoreRecordNotFoundError: true,        // Ignore ErrRecordNotFound error for logger
+			Colorful:                  false,       // Disable color
+		},
+	)
+
+	sqlMockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sql mock init failed: %v", err.Error())
+	}
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 sqlMockDB,
+		PreferSimpleProtocol: true,
+	})
+
+	mockDB, err = gorm.Open(dialector, &gorm.Config{
+		Logger: newLogger,
+	})
+	if err != nil {
+		t.Fatalf("gorm mock init failed: %v", err.Error())
+	}
+
+	return mockDB, mock
+}
+
+func TestRecord_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	event := NewChangeEvent("updated", "recipe", aggregateID)
+	data, _ := json.Marshal(event)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "sequence"`)).
+		WithArgs(sqlmock.AnyArg(), "recipe", aggregateID, "updated", data, event.OccurredAt, 0, "").
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1))
+	mock.ExpectCommit()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return Record(tx, event)
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecord_None(t *testing.T) {
+	db, mock := newMockDatabase(t)
+
+	err := Record(db)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecord_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages"`)).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return Record(tx, NewChangeEvent("deleted", "recipe", aggregateID))
+	})
+
+	assert.EqualError(t, err, "error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNewCloudEvent(t *testing.T) {
+	message := Message{
+		Sequence:      1,
+		ID:            uuid.New(),
+		AggregateType: "recipe",
+		AggregateID:   aggregateID,
+		Type:          "deleted",
+		Data:          []byte(`{"type":"deleted"}`),
+		OccurredAt:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	event := NewCloudEvent("/recipe-service", message)
+	body, _ := json.Marshal(event)
+
+	assert.Equal(t, "cookbook.recipe.deleted", event.Type)
+	assert.Equal(t, aggregateID.String(), event.Subject)
+	assert.JSONEq(t, `{
+		"specversion": "1.0",
+		"id": "`+message.ID.String()+`",
+		"source": "/recipe-service",
+		"type": "cookbook.recipe.deleted",
+		"subject": "`+aggregateID.String()+`",
+		"time": "2024-05-01T12:00:00Z",
+		"datacontenttype": "application/json",
+		"data": {"type": "deleted"}
+	}`, string(body))
+}
@@ -0,0 +1,187 @@
+package outbox
+
+import (
+	"context"
+	"hash/fnv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Relay publishes the messages in the outbox to the broker, oldest first, and removes them once the broker accepted
+// them. A message is published again when removing it fails, so delivery is at least once. When publishing fails
+// the message is tried again after a backoff, and the later messages of the same aggregate wait for it, so the
+// events of an aggregate stay in order while the other aggregates are published.
+type Relay struct {
+	db        *gorm.DB
+	broker    Broker
+	source    string
+	batchSize int
+	lockKey   int64
+	logger    Logger
+}
+
+const (
+	// leaseDuration is how long the messages claimed by a relay are left to it. Messages it neither published nor
+	// released by then, for instance because the instance stopped, are claimed again.
+	leaseDuration = 5 * time.Minute
+	// maxBackoff is the longest wait before a message that could not be published is tried again
+	maxBackoff = 10 * time.Minute
+)
+
+type aggregate struct {
+	aggregateType string
+	aggregateID   uuid.UUID
+}
+
+// NewRelay creates a relay publishing the outbox of the given database with the given source, e.g. /recipe-service
+func NewRelay(db *gorm.DB, broker Broker, source string, batchSize int, logger Logger) *Relay {
+	hash := fnv.New64a()
+	hash.Write([]byte("outbox:" + source))
+
+	return &Relay{
+		db:        db,
+		broker:    broker,
+		source:    source,
+		batchSize: batchSize,
+		lockKey:   int64(hash.Sum64()),
+		logger:    logger,
+	}
+}
+
+// Run publishes the outbox every interval until the context is cancelled. A full batch is followed by the next
+// one right away, so a backlog does not wait for the interval.
+func (r Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				published, err := r.Flush(ctx)
+				if err != nil {
+					r.logger.Warnf("unable to relay the outbox: %v", err)
+				}
+				if err != nil || published < r.batchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Flush publishes a single batch and returns the number of messages published. The batch is claimed in a short
+// transaction, the broker is only called once that is committed.
+func (r Relay) Flush(ctx context.Context) (int, error) {
+	messages, err := r.claim(time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	blocked := make(map[aggregate]bool)
+	var released []int64
+
+	for _, message := range messages {
+		key := aggregate{message.AggregateType, message.AggregateID}
+		if blocked[key] {
+			released = append(released, message.Sequence)
+			continue
+		}
+
+		if err := r.broker.Publish(ctx, NewCloudEvent(r.source, message)); err != nil {
+			r.logger.Warnf("unable to publish the %s event of %s %s: %v", message.Type, message.AggregateType, message.AggregateID, err)
+			blocked[key] = true
+
+			if err := r.db.Model(&message).Updates(map[string]interface{}{
+				"attempts":        gorm.Expr("attempts + 1"),
+				"last_error":      err.Error(),
+				"next_attempt_at": time.Now().Add(backoff(message.Attempts + 1)),
+				"lease_until":     nil,
+			}).Error; err != nil {
+				return published, err
+			}
+			continue
+		}
+
+		if err := r.db.Delete(&message).Error; err != nil {
+			return published, err
+		}
+		published++
+	}
+
+	// the later messages of an aggregate that failed are released, they are claimed again once it is published
+	if len(released) > 0 {
+		if err := r.db.Model(&Message{}).Where("sequence IN ?", released).Update("lease_until", nil).Error; err != nil {
+			return published, err
+		}
+	}
+
+	if published > 0 {
+		r.logger.Debugf("published %d events from the outbox", published)
+	}
+
+	return published, nil
+}
+
+// claim leases the next batch of messages to this relay. Messages waiting to be tried again or leased to another
+// relay are left out, and so are the messages following them in their aggregate, so a failing aggregate never
+// holds up the others. The lock only serialises claiming, so two relays can't lease messages of an aggregate out of
+// order; the other instances return without a batch while it is held.
+func (r Relay) claim(now time.Time) ([]Message, error) {
+	var messages []Message
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		var locked bool
+
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", r.lockKey).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		waiting := tx.Table("outbox_messages AS earlier").Select("1").
+			Where("earlier.aggregate_type = outbox_messages.aggregate_type AND earlier.aggregate_id = outbox_messages.aggregate_id AND earlier.sequence < outbox_messages.sequence").
+			Where("earlier.next_attempt_at > ? OR earlier.lease_until > ?", now, now)
+
+		if err := tx.Where("next_attempt_at IS NULL OR next_attempt_at <= ?", now).
+			Where("lease_until IS NULL OR lease_until <= ?", now).
+			Where("NOT EXISTS (?)", waiting).
+			Order("sequence").Limit(r.batchSize).Find(&messages).Error; err != nil {
+			return err
+		}
+
+		if len(messages) == 0 {
+			return nil
+		}
+
+		sequences := make([]int64, len(messages))
+		for i, message := range messages {
+			sequences[i] = message.Sequence
+		}
+
+		return tx.Model(&Message{}).Where("sequence IN ?", sequences).Update("lease_until", now.Add(leaseDuration)).Error
+	}); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// backoff is the wait before a message is published again, it doubles with every attempt
+func backoff(attempts int) time.Duration {
+	if attempts >= 20 {
+		return maxBackoff
+	}
+
+	backoff := time.Second << attempts
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,213 @@
+package outbox
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type LoggerMock struct{}
+
+func (l *LoggerMock) Debugf(format string, args ...interface{}) {}
+func (l *LoggerMock) Warnf(format string, args ...interface{})  {}
+
+func newMessage(sequence int64, aggregateID uuid.UUID, eventType string) Message {
+	return Message{
+		Sequence:      sequence,
+		ID:            uuid.New(),
+		AggregateType: "recipe",
+		AggregateID:   aggregateID,
+		Type:          eventType,
+		Data:          []byte(`{}`),
+		OccurredAt:    time.Now(),
+	}
+}
+
+// expectClaim expects the messages to be selected and leased to the relay
+func expectClaim(mock sqlmock.Sqlmock, messages ...Message) {
+	rows := sqlmock.NewRows([]string{"sequence", "id", "aggregate_type", "aggregate_id", "type", "data", "occurred_at", "attempts", "last_error"})
+	args := []driver.Value{sqlmock.AnyArg()}
+	for _, message := range messages {
+		rows.AddRow(message.Sequence, message.ID, message.AggregateType, message.AggregateID, message.Type, message.Data, message.OccurredAt, message.Attempts, message.LastError)
+		args = append(args, message.Sequence)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT pg_try_advisory_xact_lock($1)`)).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(true))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "outbox_messages" WHERE (next_attempt_at IS NULL OR next_attempt_at <= $1) AND (lease_until IS NULL OR lease_until <= $2) AND NOT EXISTS (SELECT 1 FROM outbox_messages AS earlier WHERE (earlier.aggregate_type = outbox_messages.aggregate_type AND earlier.aggregate_id = outbox_messages.aggregate_id AND earlier.sequence < outbox_messages.sequence) AND (earlier.next_attempt_at > $3 OR earlier.lease_until > $4)) ORDER BY sequence LIMIT $5`)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 10).
+		WillReturnRows(rows)
+	if len(messages) > 0 {
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox_messages" SET "lease_until"=$1 WHERE sequence IN (`)).
+			WithArgs(args...).
+			WillReturnResult(sqlmock.NewResult(0, int64(len(messages))))
+	}
+	mock.ExpectCommit()
+}
+
+func expectDelete(mock sqlmock.Sqlmock, message Message) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "outbox_messages" WHERE "outbox_messages"."sequence" = $1`)).
+		WithArgs(message.Sequence).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+}
+
+func TestFlush_OK(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	broker := NewMemoryBroker()
+	r := NewRelay(db, broker, "/recipe-service", 10, &LoggerMock{})
+
+	created := newMessage(1, aggregateID, "created")
+	updated := newMessage(2, aggregateID, "updated")
+
+	expectClaim(mock, created, updated)
+	expectDelete(mock, created)
+	expectDelete(mock, updated)
+
+	published, err := r.Flush(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	events := broker.Events()
+	assert.Len(t, events, 2)
+	assert.Equal(t, created.ID.String(), events[0].ID)
+	assert.Equal(t, "cookbook.recipe.created", events[0].Type)
+	assert.Equal(t, "/recipe-service", events[0].Source)
+	assert.Equal(t, updated.ID.String(), events[1].ID)
+}
+
+func TestFlush_Empty(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	broker := NewMemoryBroker()
+	r := NewRelay(db, broker, "/recipe-service", 10, &LoggerMock{})
+
+	expectClaim(mock)
+
+	published, err := r.Flush(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, published)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFlush_KeepsAggregateOrder(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	broker := NewMemoryBroker()
+	r := NewRelay(db, broker, "/recipe-service", 10, &LoggerMock{})
+
+	otherID := uuid.New()
+	failing := newMessage(1, aggregateID, "created")
+	other := newMessage(2, otherID, "created")
+	waiting := newMessage(3, aggregateID, "updated")
+
+	broker.Subscribe(func(event CloudEvent) error {
+		if event.ID == failing.ID.String() {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+
+	expectClaim(mock, failing, other, waiting)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox_messages" SET "attempts"=attempts + 1,"last_error"=$1,"lease_until"=$2,"next_attempt_at"=$3 WHERE "sequence" = $4`)).
+		WithArgs("unavailable", nil, sqlmock.AnyArg(), failing.Sequence).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectDelete(mock, other)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox_messages" SET "lease_until"=$1 WHERE sequence IN ($2)`)).
+		WithArgs(nil, waiting.Sequence).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	published, err := r.Flush(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	events := broker.Events()
+	assert.Len(t, events, 1)
+	assert.Equal(t, otherID.String(), events[0].Subject)
+}
+
+func TestFlush_Locked(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	broker := NewMemoryBroker()
+	r := NewRelay(db, broker, "/recipe-service", 10, &LoggerMock{})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT pg_try_advisory_xact_lock($1)`)).
+		WithArgs(r.lockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(false))
+	mock.ExpectCommit()
+
+	published, err := r.Flush(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, published)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFlush_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	broker := NewMemoryBroker()
+	r := NewRelay(db, broker, "/recipe-service", 10, &LoggerMock{})
+
+	message := newMessage(1, aggregateID, "deleted")
+
+	expectClaim(mock, message)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "outbox_messages"`)).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	published, err := r.Flush(context.Background())
+
+	assert.EqualError(t, err, "error")
+	assert.Equal(t, 0, published)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 2*time.Second, backoff(1))
+	assert.Equal(t, 8*time.Second, backoff(3))
+	assert.Equal(t, maxBackoff, backoff(10))
+	assert.Equal(t, maxBackoff, backoff(100))
+}
+
+func TestMemoryBroker_Deduplicates(t *testing.T) {
+	broker := NewMemoryBroker()
+	delivered := 0
+	fail := true
+
+	broker.Subscribe(func(event CloudEvent) error {
+		if fail {
+			return errors.New("unavailable")
+		}
+		delivered++
+		return nil
+	})
+
+	event := NewCloudEvent("/recipe-service", newMessage(1, aggregateID, "created"))
+
+	assert.EqualError(t, broker.Publish(context.Background(), event), "unavailable")
+
+	fail = false
+	assert.NoError(t, broker.Publish(context.Background(), event))
+	assert.NoError(t, broker.Publish(context.Background(), event))
+
+	assert.Equal(t, 1, delivered)
+	assert.Len(t, broker.Events(), 1)
+}
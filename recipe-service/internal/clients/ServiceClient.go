@@ -0,0 +1,186 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	m "recipe-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ServiceClient retrieves, deletes, restores and purges the parts of a recipe that are owned by the other services
+type ServiceClient struct {
+	httpClient HTTPClient
+	config     m.ServicesConfig
+}
+
+func NewServiceClient(httpClient HTTPClient, config m.ServicesConfig) *ServiceClient {
+	return &ServiceClient{
+		httpClient: httpClient,
+		config:     config,
+	}
+}
+
+// GetRecipeIngredients retrieves the ingredient lines of a recipe from the ingredient service
+func (c ServiceClient) GetRecipeIngredients(ctx context.Context, authorization string, recipeID uuid.UUID) ([]m.RecipeIngredientDTO, error) {
+	var ingredients []m.RecipeIngredientDTO
+
+	endpoint := fmt.Sprintf("%s/api/v2/ingredient/recipe/%s", strings.TrimSuffix(c.config.IngredientServiceUrl, "/"), recipeID)
+	if err := c.get(ctx, authorization, endpoint, &ingredients); err != nil {
+		return nil, err
+	}
+
+	return ingredients, nil
+}
+
+// GetRecipeInstructions retrieves the ordered instructions of a recipe from the instruction service
+func (c ServiceClient) GetRecipeInstructions(ctx context.Context, authorization string, recipeID uuid.UUID) ([]m.InstructionDTO, error) {
+	var instructions []m.InstructionDTO
+
+	endpoint := fmt.Sprintf("%s/api/v2/instruction/recipe/%s", strings.TrimSuffix(c.config.InstructionServiceUrl, "/"), recipeID)
+	if err := c.get(ctx, authorization, endpoint, &instructions); err != nil {
+		return nil, err
+	}
+
+	return instructions, nil
+}
+
+// GetRecipeMetadata retrieves the category, tags, cuisine type, difficulty level and preparation time of a recipe from the metadata service
+func (c ServiceClient) GetRecipeMetadata(ctx context.Context, authorization string, recipeID uuid.UUID) (m.RecipeMetadataDTO, error) {
+	var metadata m.RecipeMetadataDTO
+
+	endpoint := fmt.Sprintf("%s/api/v2/metadata/recipe/%s", strings.TrimSuffix(c.config.MetadataServiceUrl, "/"), recipeID)
+	if err := c.get(ctx, authorization, endpoint, &metadata); err != nil {
+		return m.RecipeMetadataDTO{}, err
+	}
+
+	return metadata, nil
+}
+
+// GetRecipeImages retrieves the images linked to a recipe from the image service
+func (c ServiceClient) GetRecipeImages(ctx context.Context, authorization string, recipeID uuid.UUID) ([]m.ImageDTO, error) {
+	var images []m.ImageDTO
+
+	query := url.Values{}
+	query.Set("entity_type", "recipe")
+	query.Set("entity_id", recipeID.String())
+
+	endpoint := fmt.Sprintf("%s/api/v2/image?%s", strings.TrimSuffix(c.config.ImageServiceUrl, "/"), query.Encode())
+	if err := c.get(ctx, authorization, endpoint, &images); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+// DeleteRecipe deletes the parts of a recipe owned by one of the other services
+func (c ServiceClient) DeleteRecipe(ctx context.Context, service string, recipeID uuid.UUID) error {
+	endpoint, err := c.recipeEndpoint(service, recipeID)
+	if err != nil {
+		return err
+	}
+
+	return c.send(ctx, http.MethodDelete, endpoint)
+}
+
+// RestoreRecipe restores the parts of a recipe owned by one of the other services that were deleted since the given time
+func (c ServiceClient) RestoreRecipe(ctx context.Context, service string, recipeID uuid.UUID, since time.Time) error {
+	endpoint, err := c.recipeEndpoint(service, recipeID)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("since", since.UTC().Format(time.RFC3339))
+
+	return c.send(ctx, http.MethodPost, fmt.Sprintf("%s/restore?%s", endpoint, query.Encode()))
+}
+
+// PurgeRecipe removes the deleted parts of a recipe owned by one of the other services for good
+func (c ServiceClient) PurgeRecipe(ctx context.Context, service string, recipeID uuid.UUID) error {
+	endpoint, err := c.recipeEndpoint(service, recipeID)
+	if err != nil {
+		return err
+	}
+
+	return c.send(ctx, http.MethodPost, endpoint+"/purge")
+}
+
+// recipeEndpoint returns the endpoint under which a service deletes, restores and purges the parts of a recipe
+func (c ServiceClient) recipeEndpoint(service string, recipeID uuid.UUID) (string, error) {
+	switch service {
+	case m.ServiceIngredients:
+		return fmt.Sprintf("%s/api/v2/ingredient/recipe/%s", strings.TrimSuffix(c.config.IngredientServiceUrl, "/"), recipeID), nil
+	case m.ServiceInstructions:
+		return fmt.Sprintf("%s/api/v2/instruction/recipe/%s", strings.TrimSuffix(c.config.InstructionServiceUrl, "/"), recipeID), nil
+	case m.ServiceMetadata:
+		return fmt.Sprintf("%s/api/v2/metadata/recipe/%s", strings.TrimSuffix(c.config.MetadataServiceUrl, "/"), recipeID), nil
+	case m.ServiceImages:
+		return fmt.Sprintf("%s/api/v2/image/entity/recipe/%s", strings.TrimSuffix(c.config.ImageServiceUrl, "/"), recipeID), nil
+	default:
+		return "", fmt.Errorf("unknown service %s", service)
+	}
+}
+
+// send performs a request without a body, authorized by the http client itself, and only checks the response status
+func (c ServiceClient) send(ctx context.Context, method string, endpoint string) error {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	default:
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+}
+
+// get performs the request on behalf of the caller by passing on its authorization header, and decodes the response into target
+func (c ServiceClient) get(ctx context.Context, authorization string, endpoint string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+			return fmt.Errorf("unable to decode response: %w", err)
+		}
+		return nil
+	case http.StatusNotFound:
+		return errors.New("not found")
+	default:
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+}
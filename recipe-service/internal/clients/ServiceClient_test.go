@@ -0,0 +1,255 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	m "recipe-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	recipeID uuid.UUID = uuid.New()
+)
+
+func newTestServer(t *testing.T, status int, body interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		w.WriteHeader(status)
+		if body != nil {
+			json.NewEncoder(w).Encode(body)
+		}
+	}))
+}
+
+func newTestClient(url string) *ServiceClient {
+	return NewServiceClient(http.DefaultClient, m.ServicesConfig{
+		IngredientServiceUrl:  url,
+		InstructionServiceUrl: url,
+		MetadataServiceUrl:    url,
+		ImageServiceUrl:       url,
+	})
+}
+
+func TestGetRecipeIngredients_OK(t *testing.T) {
+	var path string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewEncoder(w).Encode([]m.RecipeIngredientDTO{{RecipeID: recipeID, Quantity: 1.5, Amount: "1½"}})
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	result, err := c.GetRecipeIngredients(context.Background(), "", recipeID)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, 1.5, result[0].Quantity)
+	assert.Equal(t, "1½", result[0].Amount)
+	assert.Equal(t, "/api/v2/ingredient/recipe/"+recipeID.String(), path)
+}
+
+func TestGetRecipeIngredients_NotFound(t *testing.T) {
+	srv := newTestServer(t, http.StatusNotFound, map[string]string{"error": "no ingredients found"})
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	result, err := c.GetRecipeIngredients(context.Background(), "Bearer token", recipeID)
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "not found")
+}
+
+func TestGetRecipeInstructions_OK(t *testing.T) {
+	srv := newTestServer(t, http.StatusOK, []m.InstructionDTO{{Sequence: 1}, {Sequence: 2}})
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	result, err := c.GetRecipeInstructions(context.Background(), "Bearer token", recipeID)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+}
+
+func TestGetRecipeInstructions_Err(t *testing.T) {
+	srv := newTestServer(t, http.StatusInternalServerError, map[string]string{"error": "error"})
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	result, err := c.GetRecipeInstructions(context.Background(), "Bearer token", recipeID)
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "unexpected status code 500")
+}
+
+func TestGetRecipeMetadata_OK(t *testing.T) {
+	srv := newTestServer(t, http.StatusOK, m.RecipeMetadataDTO{RecipeID: recipeID, Tags: []m.TagDTO{{Name: "tag"}}})
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	result, err := c.GetRecipeMetadata(context.Background(), "Bearer token", recipeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, recipeID, result.RecipeID)
+	assert.Len(t, result.Tags, 1)
+}
+
+func TestGetRecipeMetadata_DecodeErr(t *testing.T) {
+	srv := newTestServer(t, http.StatusOK, "metadata")
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	result, err := c.GetRecipeMetadata(context.Background(), "Bearer token", recipeID)
+
+	assert.Error(t, err)
+	assert.Equal(t, m.RecipeMetadataDTO{}, result)
+}
+
+func TestGetRecipeImages_OK(t *testing.T) {
+	var query string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		json.NewEncoder(w).Encode([]m.ImageDTO{{EntityID: recipeID, URL: "https://example.com/img.jpg"}})
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	result, err := c.GetRecipeImages(context.Background(), "", recipeID)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "entity_id="+recipeID.String()+"&entity_type=recipe", query)
+}
+
+func TestGetRecipeImages_ConnectionErr(t *testing.T) {
+	srv := newTestServer(t, http.StatusOK, nil)
+	srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	result, err := c.GetRecipeImages(context.Background(), "Bearer token", recipeID)
+
+	assert.Nil(t, result)
+	assert.Error(t, err)
+}
+
+func TestDeleteRecipe_OK(t *testing.T) {
+	var method, path string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	err := c.DeleteRecipe(context.Background(), m.ServiceImages, recipeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodDelete, method)
+	assert.Equal(t, "/api/v2/image/entity/recipe/"+recipeID.String(), path)
+}
+
+func TestDeleteRecipe_Err(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	err := c.DeleteRecipe(context.Background(), m.ServiceIngredients, recipeID)
+
+	assert.EqualError(t, err, "unexpected status code 500")
+}
+
+func TestDeleteRecipe_UnknownServiceErr(t *testing.T) {
+	c := newTestClient("http://localhost")
+
+	err := c.DeleteRecipe(context.Background(), "unknown", recipeID)
+
+	assert.EqualError(t, err, "unknown service unknown")
+}
+
+func TestRestoreRecipe_OK(t *testing.T) {
+	var method, path, since string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		since = r.URL.Query().Get("since")
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	err := c.RestoreRecipe(context.Background(), m.ServiceMetadata, recipeID, time.Date(2023, 2, 4, 18, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "/api/v2/metadata/recipe/"+recipeID.String()+"/restore", path)
+	assert.Equal(t, "2023-02-04T18:00:00Z", since)
+}
+
+func TestRestoreRecipe_Err(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	err := c.RestoreRecipe(context.Background(), m.ServiceInstructions, recipeID, time.Now())
+
+	assert.EqualError(t, err, "unexpected status code 403")
+}
+
+func TestPurgeRecipe_OK(t *testing.T) {
+	var method, path string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	err := c.PurgeRecipe(context.Background(), m.ServiceIngredients, recipeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "/api/v2/ingredient/recipe/"+recipeID.String()+"/purge", path)
+}
+
+func TestPurgeRecipe_Err(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	err := c.PurgeRecipe(context.Background(), m.ServiceMetadata, recipeID)
+
+	assert.EqualError(t, err, "unexpected status code 500")
+}
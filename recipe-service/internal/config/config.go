@@ -1,6 +1,11 @@
 package config
 
 import (
+	"net/http"
+	"time"
+
+	"cookbook/pkg/outbox"
+	cl "recipe-service/internal/clients"
 	h "recipe-service/internal/handlers"
 	m "recipe-service/internal/models"
 	r "recipe-service/internal/repositories"
@@ -21,13 +26,27 @@ var (
 	Cors           cors.Config
 
 	// Repositories
-	RecipeRepository *r.RecipeRepository
+	RecipeRepository   *r.RecipeRepository
+	DeletionRepository *r.DeletionRepository
+
+	// Clients
+	ServiceClient *cl.ServiceClient
+	CascadeClient *cl.ServiceClient
+
+	// Events
+	Relay         *outbox.Relay
+	RelayInterval time.Duration
 
 	// Services
-	RecipeService *s.RecipeService
+	RecipeService       *s.RecipeService
+	FullRecipeService   *s.FullRecipeService
+	DeletionCoordinator *s.DeletionCoordinator
+	DeletionInterval    time.Duration
 
 	// Handlers
-	RecipeHandlers *h.RecipeHandlers
+	RecipeHandlers     *h.RecipeHandlers
+	FullRecipeHandlers *h.FullRecipeHandlers
+	DeletionHandlers   *h.DeletionHandlers
 )
 
 func init() {
@@ -48,10 +67,25 @@ func init() {
 
 	// Init repositories
 	RecipeRepository = r.NewRecipeRepository(DatabaseClient)
+	DeletionRepository = r.NewDeletionRepository(DatabaseClient)
+
+	// Init clients
+	ServiceClient = cl.NewServiceClient(&http.Client{Timeout: serviceTimeout()}, Configuration.Services)
+	CascadeClient = cl.NewServiceClient(serviceHttpClient(), Configuration.Services)
+
+	// Init events
+	Relay = outbox.NewRelay(DatabaseClient, eventBroker(), "/recipe-service", relayBatchSize(), Logger)
+	RelayInterval = relayInterval()
 
 	// Init services
-	RecipeService = s.NewRecipeService(RecipeRepository)
+	window := restoreWindow()
+	RecipeService = s.NewRecipeService(RecipeRepository, window)
+	FullRecipeService = s.NewFullRecipeService(RecipeRepository, ServiceClient)
+	DeletionCoordinator = s.NewDeletionCoordinator(DeletionRepository, CascadeClient, window, Logger)
+	DeletionInterval = deletionInterval()
 
 	// Init handlers
 	RecipeHandlers = h.NewRecipeHandlers(RecipeService, Logger)
+	FullRecipeHandlers = h.NewFullRecipeHandlers(FullRecipeService, Logger)
+	DeletionHandlers = h.NewDeletionHandlers(DeletionCoordinator, Logger)
 }
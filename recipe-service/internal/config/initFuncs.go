@@ -1,7 +1,10 @@
 package config
 
 import (
+	"context"
+	"cookbook/pkg/outbox"
 	"fmt"
+	"net/http"
 	"recipe-service/internal/helpers"
 	m "recipe-service/internal/models"
 	"strings"
@@ -10,6 +13,7 @@ import (
 	"github.com/gin-contrib/cors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"golang.org/x/oauth2/clientcredentials"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -84,6 +88,9 @@ func initDatabase() {
 	Logger.Info("performing database migrations")
 	if err := DatabaseClient.AutoMigrate(
 		&m.Recipe{},
+		&m.RecipeDeletion{},
+		&m.DeletionStep{},
+		&outbox.Message{},
 	); err != nil {
 		Logger.Fatalf("Error while automigrating database: %s", err.Error())
 	}
@@ -101,3 +108,121 @@ func initCors() {
 		MaxAge:           12 * time.Hour,
 	}
 }
+
+func serviceTimeout() time.Duration {
+	if Configuration.Services.Timeout <= 0 {
+		Logger.Warn("no or invalid service timeout specified. Assuming default value of 5 seconds")
+		return 5 * time.Second
+	}
+
+	return time.Duration(Configuration.Services.Timeout) * time.Second
+}
+
+// serviceHttpClient returns the client the deletion coordinator calls the other services with. Unlike retrieving
+// a recipe there is no user to act on behalf of, so with a client configured it authenticates with the client
+// credentials at keycloak
+func serviceHttpClient() *http.Client {
+	if Configuration.Services.ClientID == "" {
+		Logger.Warn("no service client specified. Deleted recipes are deleted at the other services without authentication")
+		return &http.Client{Timeout: serviceTimeout()}
+	}
+
+	credentials := clientcredentials.Config{
+		ClientID:     Configuration.Services.ClientID,
+		ClientSecret: Configuration.Services.ClientSecret,
+		TokenURL:     fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", strings.TrimSuffix(Configuration.Oauth.Url, "/"), Configuration.Oauth.Realm),
+	}
+
+	client := credentials.Client(context.Background())
+	client.Timeout = serviceTimeout()
+
+	return client
+}
+
+// deletionInterval returns the time between the runs of the deletion coordinator
+func deletionInterval() time.Duration {
+	if Configuration.Services.Interval <= 0 {
+		Logger.Warn("no or invalid deletion interval specified. Assuming default value of 5000 milliseconds")
+		return 5 * time.Second
+	}
+
+	return time.Duration(Configuration.Services.Interval) * time.Millisecond
+}
+
+// restoreWindow returns how long a deleted recipe can be restored, afterwards it is purged
+func restoreWindow() time.Duration {
+	if Configuration.Services.RestoreWindow <= 0 {
+		Logger.Warn("no or invalid restore window specified. Assuming default value of 720 hours")
+		return 30 * 24 * time.Hour
+	}
+
+	return time.Duration(Configuration.Services.RestoreWindow) * time.Hour
+}
+
+func eventTimeout() time.Duration {
+	if Configuration.Events.Timeout <= 0 {
+		Logger.Warn("no or invalid event timeout specified. Assuming default value of 30 seconds")
+		return 30 * time.Second
+	}
+
+	return time.Duration(Configuration.Events.Timeout) * time.Second
+}
+
+// eventHttpClient returns the client the events are sent with. With a client configured it authenticates
+// with the client credentials at keycloak
+func eventHttpClient() *http.Client {
+	if Configuration.Events.ClientID == "" {
+		if len(Configuration.Events.Subscribers) > 0 {
+			Logger.Warn("no event client specified. Events are sent without authentication")
+		}
+		return &http.Client{Timeout: eventTimeout()}
+	}
+
+	credentials := clientcredentials.Config{
+		ClientID:     Configuration.Events.ClientID,
+		ClientSecret: Configuration.Events.ClientSecret,
+		TokenURL:     fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", strings.TrimSuffix(Configuration.Oauth.Url, "/"), Configuration.Oauth.Realm),
+	}
+
+	client := credentials.Client(context.Background())
+	client.Timeout = eventTimeout()
+
+	return client
+}
+
+// eventBroker returns the broker the outbox relay publishes the events to
+func eventBroker() outbox.Broker {
+	switch Configuration.Events.Broker {
+	case "nats":
+		broker, err := outbox.NewNatsBroker(Configuration.Events.NatsUrl)
+		if err != nil {
+			Logger.Fatalf("unable to connect to the event broker at %s: %v", Configuration.Events.NatsUrl, err)
+		}
+		return broker
+	case "", "http":
+		return outbox.NewHttpBroker(eventHttpClient(), Configuration.Events.Subscribers, Logger)
+	default:
+		Logger.Fatalf("unknown event broker %s. Use nats or http", Configuration.Events.Broker)
+		return nil
+	}
+}
+
+// relayInterval returns the time between the runs of the outbox relay
+func relayInterval() time.Duration {
+	if Configuration.Events.Interval <= 0 {
+		Logger.Warn("no or invalid relay interval specified. Assuming default value of 1000 milliseconds")
+		return time.Second
+	}
+
+	return time.Duration(Configuration.Events.Interval) * time.Millisecond
+}
+
+// relayBatchSize returns the number of events the outbox relay publishes per run
+func relayBatchSize() int {
+	if Configuration.Events.BatchSize <= 0 {
+		Logger.Warn("no or invalid relay batch size specified. Assuming default value of 100")
+		return 100
+	}
+
+	return Configuration.Events.BatchSize
+}
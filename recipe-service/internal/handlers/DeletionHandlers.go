@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+
+	m "recipe-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DeletionService interface {
+	Status(recipe m.RecipeDTO) (m.RecipeDeletionDTO, error)
+}
+
+type DeletionHandlers struct {
+	deletionService DeletionService
+	logger          m.LoggerInterface
+}
+
+func NewDeletionHandlers(deletions DeletionService, logger m.LoggerInterface) *DeletionHandlers {
+	return &DeletionHandlers{
+		deletionService: deletions,
+		logger:          logger,
+	}
+}
+
+// Get reports how far deleting or restoring a recipe at the other services has come
+func (h DeletionHandlers) Get(ctx *gin.Context) {
+	var recipeDTO m.RecipeDTO
+	var err error
+
+	recipeDTO.ID, err = uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	deletionDTO, err := h.deletionService.Status(recipeDTO)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "no deletion found"})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, deletionDTO)
+}
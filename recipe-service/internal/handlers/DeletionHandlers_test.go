@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	m "recipe-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	deletion m.RecipeDeletionDTO = m.RecipeDeletionDTO{
+		RecipeID: uuid.New(),
+		Status:   m.DeletionStatusDeleting,
+		Steps: []m.DeletionStepDTO{
+			{Service: m.ServiceIngredients, Status: m.StepDone, Attempts: 1},
+			{Service: m.ServiceImages, Status: m.StepPending, Attempts: 2, Error: "unexpected status code 500"},
+		},
+	}
+	deletionErr error
+)
+
+type DeletionServiceMock struct{}
+
+func (s *DeletionServiceMock) Status(recipeDTO m.RecipeDTO) (m.RecipeDeletionDTO, error) {
+	if deletionErr != nil {
+		return m.RecipeDeletionDTO{}, deletionErr
+	}
+	return deletion, nil
+}
+
+func TestDeletionGet_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewDeletionHandlers(&DeletionServiceMock{}, &LoggerInterfaceMock{})
+
+	deletionErr = nil
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/recipes/1/deletion", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: deletion.RecipeID.String()},
+	}
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(deletion)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestDeletionGet_IDRequiredErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewDeletionHandlers(&DeletionServiceMock{}, &LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/recipes/1/deletion", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"invalid recipe ID"}`), body)
+}
+
+func TestDeletionGet_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewDeletionHandlers(&DeletionServiceMock{}, &LoggerInterfaceMock{})
+
+	deletionErr = errors.New("not found")
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/recipes/1/deletion", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: deletion.RecipeID.String()},
+	}
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"no deletion found"}`), body)
+}
+
+func TestDeletionGet_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewDeletionHandlers(&DeletionServiceMock{}, &LoggerInterfaceMock{})
+
+	deletionErr = errors.New("internal server error")
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/recipes/1/deletion", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: deletion.RecipeID.String()},
+	}
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"internal server error"}`), body)
+}
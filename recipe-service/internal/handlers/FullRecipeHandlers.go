@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	m "recipe-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type FullRecipeService interface {
+	FindFull(ctx context.Context, recipe m.RecipeDTO, authorization string) (m.FullRecipeDTO, error)
+}
+
+type FullRecipeHandlers struct {
+	fullRecipeService FullRecipeService
+	logger            m.LoggerInterface
+}
+
+func NewFullRecipeHandlers(fullRecipes FullRecipeService, logger m.LoggerInterface) *FullRecipeHandlers {
+	return &FullRecipeHandlers{
+		fullRecipeService: fullRecipes,
+		logger:            logger,
+	}
+}
+
+func (h FullRecipeHandlers) Get(ctx *gin.Context) {
+	var recipeDTO m.RecipeDTO
+	var err error
+
+	recipeDTO.ID, err = uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	fullRecipeDTO, err := h.fullRecipeService.FindFull(ctx.Request.Context(), recipeDTO, ctx.GetHeader("Authorization"))
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "recipe not found"})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	for section, sectionErr := range fullRecipeDTO.Errors {
+		h.logger.Warnf("unable to retrieve %s for recipe %s: %s", section, recipeDTO.ID, sectionErr)
+	}
+
+	ctx.JSON(http.StatusOK, fullRecipeDTO)
+}
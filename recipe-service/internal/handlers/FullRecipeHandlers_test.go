@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	m "recipe-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type FullRecipeServiceMock struct{}
+
+var (
+	authorization string
+	fullRecipe    m.FullRecipeDTO = m.FullRecipeDTO{
+		Recipe:       recipe,
+		Ingredients:  []m.RecipeIngredientDTO{{RecipeID: recipe.ID, Quantity: 1}},
+		Instructions: []m.InstructionDTO{},
+		Images:       []m.ImageDTO{},
+		Errors:       map[string]string{"metadata": "unexpected status code 500"},
+	}
+)
+
+func (s *FullRecipeServiceMock) FindFull(ctx context.Context, recipeDTO m.RecipeDTO, auth string) (m.FullRecipeDTO, error) {
+	authorization = auth
+
+	switch recipe.Name {
+	case "find":
+		return fullRecipe, nil
+	case "notfound":
+		return m.FullRecipeDTO{}, errors.New("not found")
+	default:
+		return m.FullRecipeDTO{}, errors.New("error")
+	}
+}
+
+// ==================================================================================================
+func TestFullRecipeGet_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewFullRecipeHandlers(&FullRecipeServiceMock{}, &LoggerInterfaceMock{})
+
+	recipe.Name = "find"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/recipe/1/full", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: recipe.ID.String()},
+	}
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(fullRecipe)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+	assert.Equal(t, "Bearer token", authorization)
+}
+
+func TestFullRecipeGet_IDErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewFullRecipeHandlers(&FullRecipeServiceMock{}, &LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/recipe/1/full", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: "1"},
+	}
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid recipe ID"}`, string(body))
+}
+
+func TestFullRecipeGet_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewFullRecipeHandlers(&FullRecipeServiceMock{}, &LoggerInterfaceMock{})
+
+	recipe.Name = "notfound"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/recipe/1/full", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: recipe.ID.String()},
+	}
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"recipe not found"}`, string(body))
+}
+
+func TestFullRecipeGet_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewFullRecipeHandlers(&FullRecipeServiceMock{}, &LoggerInterfaceMock{})
+
+	recipe.Name = "error"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/recipe/1/full", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: recipe.ID.String()},
+	}
+
+	h.Get(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, `{"error":"error"}`, string(body))
+}
@@ -15,6 +15,7 @@ type RecipeService interface {
 	Create(recipe m.RecipeDTO) (m.RecipeDTO, error)
 	Update(recipe m.RecipeDTO) (m.RecipeDTO, error)
 	Delete(recipe m.RecipeDTO) error
+	Restore(recipe m.RecipeDTO) error
 }
 
 type RecipeHandlers struct {
@@ -129,9 +130,43 @@ func (h RecipeHandlers) Delete(ctx *gin.Context) {
 
 	err = h.recipeService.Delete(recipeDTO)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "recipe not found"})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (h RecipeHandlers) Restore(ctx *gin.Context) {
+	var recipeDTO m.RecipeDTO
+	var err error
+
+	recipeDTO.ID, err = uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
 		return
 	}
 
+	err = h.recipeService.Restore(recipeDTO)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "no deleted recipe found"})
+			return
+		case "restore window passed":
+			ctx.JSON(http.StatusGone, gin.H{"error": "the recipe can no longer be restored"})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	ctx.Status(http.StatusNoContent)
 }
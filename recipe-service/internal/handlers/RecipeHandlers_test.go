@@ -75,6 +75,21 @@ func (s *RecipeServiceMock) Delete(recipeDTO m.RecipeDTO) error {
 	switch recipe.Name {
 	case "delete":
 		return nil
+	case "notfound":
+		return errors.New("not found")
+	default:
+		return errors.New("error")
+	}
+}
+
+func (s *RecipeServiceMock) Restore(recipeDTO m.RecipeDTO) error {
+	switch recipe.Name {
+	case "restore":
+		return nil
+	case "notfound":
+		return errors.New("not found")
+	case "expired":
+		return errors.New("restore window passed")
 	default:
 		return errors.New("error")
 	}
@@ -452,3 +467,136 @@ func TestRecipeDelete_DeleteErr(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	assert.Equal(t, []byte(`{"error":"error"}`), body)
 }
+
+func TestRecipeDelete_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewRecipeHandlers(&RecipeServiceMock{}, &LoggerInterfaceMock{})
+
+	recipe.Name = "notfound"
+
+	req := httptest.NewRequest("DELETE", "http://example.com/api/v2/recipe/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: recipe.ID.String()},
+	}
+
+	h.Delete(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"recipe not found"}`), body)
+}
+
+func TestRecipeRestore_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewRecipeHandlers(&RecipeServiceMock{}, &LoggerInterfaceMock{})
+
+	recipe.Name = "restore"
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/recipe/1/restore", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: recipe.ID.String()},
+	}
+
+	h.Restore(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, body)
+}
+
+func TestRecipeRestore_IDRequiredErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewRecipeHandlers(&RecipeServiceMock{}, &LoggerInterfaceMock{})
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/recipe/1/restore", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Restore(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"invalid recipe ID"}`), body)
+}
+
+func TestRecipeRestore_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewRecipeHandlers(&RecipeServiceMock{}, &LoggerInterfaceMock{})
+
+	recipe.Name = "notfound"
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/recipe/1/restore", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: recipe.ID.String()},
+	}
+
+	h.Restore(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"no deleted recipe found"}`), body)
+}
+
+func TestRecipeRestore_WindowPassed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewRecipeHandlers(&RecipeServiceMock{}, &LoggerInterfaceMock{})
+
+	recipe.Name = "expired"
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/recipe/1/restore", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: recipe.ID.String()},
+	}
+
+	h.Restore(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusGone, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"the recipe can no longer be restored"}`), body)
+}
+
+func TestRecipeRestore_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewRecipeHandlers(&RecipeServiceMock{}, &LoggerInterfaceMock{})
+
+	recipe.Name = "error"
+
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/recipe/1/restore", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{
+		gin.Param{Key: "id", Value: recipe.ID.String()},
+	}
+
+	h.Restore(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, []byte(`{"error":"error"}`), body)
+}
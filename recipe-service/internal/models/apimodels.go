@@ -24,6 +24,7 @@ func (r Recipe) ConvertToDTO() RecipeDTO {
 		Name:         r.Name,
 		Description:  r.Description,
 		ServingCount: r.ServingCount,
+		CreatedAt:    r.CreatedAt,
 	}
 }
 
@@ -39,9 +40,10 @@ func (c Recipe) ConvertAllToDTO(recipes []Recipe) []RecipeDTO {
 
 type RecipeDTO struct {
 	ID           uuid.UUID
-	Name         string `gorm:"not null" json:"name" example:"apple pie"`
-	Description  string `gorm:"size:65535;not null" json:"description" example:"pie with apples"`
-	ServingCount int    `gorm:"default:0" json:"servingcount" example:"4"`
+	Name         string    `gorm:"not null" json:"name" example:"apple pie"`
+	Description  string    `gorm:"size:65535;not null" json:"description" example:"pie with apples"`
+	ServingCount int       `gorm:"default:0" json:"servingcount" example:"4"`
+	CreatedAt    time.Time `json:"created_at"` // set by the service, ignored on create and update
 }
 
 func (r RecipeDTO) ConvertFromDTO() Recipe {
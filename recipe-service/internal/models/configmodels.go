@@ -5,6 +5,8 @@ type Config struct {
 	Cors     CorsConfig
 	Oauth    OauthConfig
 	Database DatabaseConfig
+	Events   EventsConfig
+	Services ServicesConfig
 }
 
 // GlobalConfig holds global configuration items
@@ -12,6 +14,19 @@ type GlobalConfig struct {
 	LogLevel string
 }
 
+// EventsConfig holds the broker the changes are published to. The http broker posts them to the subscribers,
+// authenticated with the client credentials
+type EventsConfig struct {
+	Broker       string   // nats or http, defaults to http
+	NatsUrl      string   // e.g. nats://nats:4222
+	Subscribers  []string // urls the events are posted to, e.g. http://search-service:8080/api/v2/search/events
+	Timeout      int      // in seconds
+	ClientID     string
+	ClientSecret string
+	Interval     int // in milliseconds, between the runs of the outbox relay
+	BatchSize    int // number of events the outbox relay publishes per run
+}
+
 // DatabaseConfig holds database configuration items
 type DatabaseConfig struct {
 	Host     string
@@ -23,6 +38,20 @@ type DatabaseConfig struct {
 	Timezone string
 }
 
+// ServicesConfig holds the base urls of the services a recipe is composed from. A deleted recipe is deleted
+// at these services too, authenticated with the client credentials, and purged there once it can no longer be restored
+type ServicesConfig struct {
+	IngredientServiceUrl  string
+	InstructionServiceUrl string
+	MetadataServiceUrl    string
+	ImageServiceUrl       string
+	Timeout               int // in seconds
+	ClientID              string
+	ClientSecret          string
+	Interval              int // in milliseconds, between the runs of the deletion coordinator
+	RestoreWindow         int // in hours, a deleted recipe can be restored for this long before it is purged
+}
+
 type OauthConfig struct {
 	Service              string
 	Url                  string
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The services holding parts of a recipe, which are deleted and restored along with it
+const (
+	ServiceIngredients  = "ingredients"
+	ServiceInstructions = "instructions"
+	ServiceMetadata     = "metadata"
+	ServiceImages       = "images"
+)
+
+var DeletionServices = []string{ServiceIngredients, ServiceInstructions, ServiceMetadata, ServiceImages}
+
+// PurgeServices remove the parts of a recipe for good once it can no longer be restored. The image service purges
+// deleted images itself once its grace period has passed.
+var PurgeServices = []string{ServiceIngredients, ServiceInstructions, ServiceMetadata}
+
+// What is done with the parts of a recipe at the other services
+const (
+	DeletionActionDelete  = "delete"
+	DeletionActionRestore = "restore"
+	DeletionActionPurge   = "purge"
+)
+
+// The states of a single step
+const (
+	StepPending = "pending"
+	StepDone    = "done"
+)
+
+// The states of a deletion as reported to the client
+const (
+	DeletionStatusDeleting  = "deleting"
+	DeletionStatusDeleted   = "deleted"
+	DeletionStatusRestoring = "restoring"
+	DeletionStatusRestored  = "restored"
+	DeletionStatusPurging   = "purging"
+	DeletionStatusPurged    = "purged"
+)
+
+// RecipeDeletion keeps track of cleaning up a deleted recipe at the other services, or of restoring it there
+type RecipeDeletion struct {
+	RecipeID    uuid.UUID      `gorm:"type:uuid;primaryKey"`
+	Action      string         `gorm:"type:varchar(10);not null"`
+	RequestedAt time.Time      `gorm:"not null"`       // when the recipe was deleted, the parts deleted since are restored with it
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime"` // moved forward on every attempt, so the deletions take turns
+	Steps       []DeletionStep `gorm:"foreignKey:RecipeID"`
+}
+
+// DeletionStep is the cleanup or restore of a recipe at one of the other services
+type DeletionStep struct {
+	RecipeID      uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	Service       string     `gorm:"type:varchar(20);primaryKey"`
+	Action        string     `gorm:"type:varchar(10);not null"`
+	Status        string     `gorm:"type:varchar(10);not null;index"`
+	Attempts      int        `gorm:"not null;default:0"`
+	LastError     string     `gorm:"type:text"`
+	NextAttemptAt *time.Time `gorm:"index"` // set when the step failed, it is not tried again before then
+	LeaseUntil    *time.Time // set while an instance of the service carries out the step, no other instance claims it before then
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime"`
+}
+
+// NewRecipeDeletion creates the deletion of a recipe with a pending step for every service
+func NewRecipeDeletion(recipeID uuid.UUID, requestedAt time.Time) RecipeDeletion {
+	deletion := RecipeDeletion{
+		RecipeID:    recipeID,
+		Action:      DeletionActionDelete,
+		RequestedAt: requestedAt,
+	}
+
+	for _, service := range DeletionServices {
+		deletion.Steps = append(deletion.Steps, DeletionStep{
+			RecipeID: recipeID,
+			Service:  service,
+			Action:   DeletionActionDelete,
+			Status:   StepPending,
+		})
+	}
+
+	return deletion
+}
+
+// NewPurgeSteps creates a pending step for every service the parts of a recipe are purged at
+func NewPurgeSteps(recipeID uuid.UUID) []DeletionStep {
+	var steps []DeletionStep
+
+	for _, service := range PurgeServices {
+		steps = append(steps, DeletionStep{
+			RecipeID: recipeID,
+			Service:  service,
+			Action:   DeletionActionPurge,
+			Status:   StepPending,
+		})
+	}
+
+	return steps
+}
+
+func (d RecipeDeletion) ConvertToDTO() RecipeDeletionDTO {
+	dto := RecipeDeletionDTO{
+		RecipeID:  d.RecipeID,
+		DeletedAt: d.RequestedAt,
+		Steps:     []DeletionStepDTO{},
+	}
+
+	pending := false
+	for _, step := range d.Steps {
+		pending = pending || step.Status == StepPending
+
+		dto.Steps = append(dto.Steps, DeletionStepDTO{
+			Service:  step.Service,
+			Status:   step.Status,
+			Attempts: step.Attempts,
+			Error:    step.LastError,
+		})
+	}
+
+	switch {
+	case d.Action == DeletionActionPurge && pending:
+		dto.Status = DeletionStatusPurging
+	case d.Action == DeletionActionPurge:
+		dto.Status = DeletionStatusPurged
+	case d.Action == DeletionActionRestore && pending:
+		dto.Status = DeletionStatusRestoring
+	case d.Action == DeletionActionRestore:
+		dto.Status = DeletionStatusRestored
+	case pending:
+		dto.Status = DeletionStatusDeleting
+	default:
+		dto.Status = DeletionStatusDeleted
+	}
+
+	return dto
+}
+
+// RecipeDeletionDTO reports how far deleting or restoring a recipe at the other services has come
+type RecipeDeletionDTO struct {
+	RecipeID  uuid.UUID         `json:"recipe_id"`
+	Status    string            `json:"status" example:"deleting"`
+	DeletedAt time.Time         `json:"deleted_at"`
+	Steps     []DeletionStepDTO `json:"steps"`
+}
+
+type DeletionStepDTO struct {
+	Service  string `json:"service" example:"ingredients"`
+	Status   string `json:"status" example:"pending"`
+	Attempts int    `json:"attempts" example:"1"`
+	Error    string `json:"error,omitempty"`
+}
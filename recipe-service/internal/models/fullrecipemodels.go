@@ -0,0 +1,87 @@
+package models
+
+import "github.com/google/uuid"
+
+// FullRecipeDTO is the composed view of a recipe and all data the other services hold on it.
+// Sections that could not be retrieved are left empty and the reason is reported in Errors, keyed by section name.
+type FullRecipeDTO struct {
+	Recipe       RecipeDTO             `json:"recipe"`
+	Ingredients  []RecipeIngredientDTO `json:"ingredients"`
+	Instructions []InstructionDTO      `json:"instructions"`
+	Metadata     *RecipeMetadataDTO    `json:"metadata"`
+	Images       []ImageDTO            `json:"images"`
+	Errors       map[string]string     `json:"errors,omitempty"`
+}
+
+// The models below mirror the DTOs as returned by the downstream services
+
+// ingredient-service
+type UnitDTO struct {
+	ID        uuid.UUID `json:"ID"`
+	FullName  string    `json:"FullName" example:"Fluid ounce"`
+	ShortName string    `json:"ShortName" example:"fl oz"`
+}
+
+type RecipeIngredientDTO struct {
+	RecipeID       uuid.UUID `json:"RecipeID"`
+	IngredientID   uuid.UUID `json:"IngredientID"`
+	IngredientName string    `json:"IngredientName" example:"asparagus"`
+	Quantity       float64   `json:"Quantity" example:"1.5"`
+	MaxQuantity    float64   `json:"MaxQuantity,omitempty" example:"2"`
+	ToTaste        bool      `json:"ToTaste,omitempty"`
+	Amount         string    `json:"Amount,omitempty" example:"1½–2"`
+	Unit           UnitDTO   `json:"unit"`
+}
+
+// instruction-service
+type InstructionDTO struct {
+	ID          uuid.UUID `json:"id"`
+	Sequence    int       `json:"sequence" example:"1"`
+	Description string    `json:"description" example:"description"`
+	MediaID     uuid.UUID `json:"media_url"`
+}
+
+// metadata-service
+type CategoryDTO struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type TagDTO struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type CuisineTypeDTO struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type DifficultyLevelDTO struct {
+	ID    uuid.UUID `json:"id"`
+	Level int       `json:"name"`
+}
+
+type PreparationTimeDTO struct {
+	ID       uuid.UUID `json:"id"`
+	Duration int       `json:"name"`
+}
+
+type RecipeMetadataDTO struct {
+	RecipeID        uuid.UUID          `json:"recipe_id"`
+	Categories      []CategoryDTO      `json:"categories"`
+	Tags            []TagDTO           `json:"tags"`
+	CuisineType     CuisineTypeDTO     `json:"cuisine_type"`
+	DifficultyLevel DifficultyLevelDTO `json:"difficulty_level"`
+	PreparationTime PreparationTimeDTO `json:"preparation_time"`
+}
+
+// image-service
+type ImageDTO struct {
+	ID         uuid.UUID `json:"id"`
+	EntityType string    `json:"entity_type"`
+	EntityID   uuid.UUID `json:"entity_id"`
+	Size       int64     `json:"size"`
+	Type       string    `json:"type"`
+	URL        string    `json:"url"`
+}
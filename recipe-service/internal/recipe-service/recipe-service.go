@@ -5,6 +5,7 @@ import (
 	"net/http"
 	c "recipe-service/internal/config"
 	m "recipe-service/internal/middleware"
+	"recipe-service/internal/routes"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -29,38 +30,18 @@ func RecipeService(ctx context.Context) {
 	// Cors handler
 	router.Use(cors.New(c.Cors))
 
-	v1 := router.Group("/api/v2")
-	{
-		recipe := v1.Group("/recipes")
-		{
-			readRecipe := recipe.Group("")
-			readRecipe.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
-			{
-				readRecipe.GET("", c.RecipeHandlers.GetAll)
-				readRecipe.GET(":id", c.RecipeHandlers.Get)
-			}
+	// Routes
+	routes.Register(router, ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build(), routes.Handlers{
+		Recipe:     c.RecipeHandlers,
+		FullRecipe: c.FullRecipeHandlers,
+		Deletion:   c.DeletionHandlers,
+	})
 
-			createRecipe := recipe.Group("")
-			readRecipe.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
-			{
-				createRecipe.POST("", c.RecipeHandlers.Create)
-			}
+	// Outbox relay
+	go c.Relay.Run(ctx, c.RelayInterval)
 
-			updateRecipe := recipe.Group("")
-			readRecipe.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
-			{
-
-				updateRecipe.PUT(":id", c.RecipeHandlers.Update)
-			}
-
-			adminRecipe := recipe.Group("")
-			readRecipe.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
-			{
-				adminRecipe.DELETE(":id", c.RecipeHandlers.Delete)
-			}
-		}
-
-	}
+	// Cascading recipe deletions to the other services
+	go c.DeletionCoordinator.Run(ctx, c.DeletionInterval)
 
 	// Server startup
 	srv := &http.Server{
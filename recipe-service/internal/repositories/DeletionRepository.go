@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	m "recipe-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DeletionRepository struct {
+	db *gorm.DB
+}
+
+func NewDeletionRepository(db *gorm.DB) *DeletionRepository {
+	return &DeletionRepository{
+		db: db,
+	}
+}
+
+// FindByRecipe retrieves the deletion of a recipe together with its steps
+func (r DeletionRepository) FindByRecipe(recipeID uuid.UUID) (m.RecipeDeletion, error) {
+	var deletion m.RecipeDeletion
+
+	result := r.db.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("service")
+	}).Where("recipe_id = ?", recipeID).First(&deletion)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return m.RecipeDeletion{}, errors.New("not found")
+		} else {
+			return m.RecipeDeletion{}, result.Error
+		}
+	}
+
+	return deletion, nil
+}
+
+// dueSteps selects the pending steps whose backoff has passed and that aren't claimed by an instance of the service
+const dueSteps = "deletion_steps.status = ? AND (deletion_steps.next_attempt_at IS NULL OR deletion_steps.next_attempt_at <= ?) AND (deletion_steps.lease_until IS NULL OR deletion_steps.lease_until <= ?)"
+
+// Claim retrieves the deletions that have steps due at the given time, together with those steps, and leases the
+// steps until leaseUntil. Until then no other instance of the service claims them, so the steps can be carried out
+// without holding a transaction open. Deletions being claimed by another instance are skipped. The deletions
+// attempted least recently come first and move to the back of the queue, so deletions that keep failing don't hold
+// up newer ones.
+func (r DeletionRepository) Claim(limit int, now time.Time, leaseUntil time.Time) ([]m.RecipeDeletion, error) {
+	var deletions []m.RecipeDeletion
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("EXISTS (SELECT 1 FROM deletion_steps WHERE deletion_steps.recipe_id = recipe_deletions.recipe_id AND "+dueSteps+")", m.StepPending, now, now).
+			Order("updated_at").
+			Limit(limit).
+			Find(&deletions).Error; err != nil {
+			return err
+		}
+
+		if len(deletions) <= 0 {
+			return nil
+		}
+
+		var recipeIDs []uuid.UUID
+		for _, deletion := range deletions {
+			recipeIDs = append(recipeIDs, deletion.RecipeID)
+		}
+
+		var steps []m.DeletionStep
+		if err := tx.Where("deletion_steps.recipe_id IN ?", recipeIDs).
+			Where(dueSteps, m.StepPending, now, now).
+			Order("service").
+			Find(&steps).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&m.DeletionStep{}).
+			Where("deletion_steps.recipe_id IN ?", recipeIDs).
+			Where(dueSteps, m.StepPending, now, now).
+			Update("lease_until", leaseUntil).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&m.RecipeDeletion{}).Where("recipe_id IN ?", recipeIDs).Update("updated_at", now).Error; err != nil {
+			return err
+		}
+
+		for i := range deletions {
+			for _, step := range steps {
+				if step.RecipeID == deletions[i].RecipeID {
+					step.LeaseUntil = &leaseUntil
+					deletions[i].Steps = append(deletions[i].Steps, step)
+				}
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if len(deletions) <= 0 {
+		return nil, errors.New("not found")
+	}
+
+	return deletions, nil
+}
+
+// Expire turns the finished deletions of recipes deleted before the given time into purges. The recipes are removed
+// for good and can no longer be restored, their parts at the other services are purged by the new steps.
+func (r DeletionRepository) Expire(before time.Time, limit int) error {
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var recipeIDs []uuid.UUID
+
+		// the deletions are locked, so none of the recipes is restored at the same time. Deletions being restored
+		// or claimed are skipped, they expire on a later run
+		if err := tx.Model(&m.RecipeDeletion{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("action = ? AND requested_at < ?", m.DeletionActionDelete, before).
+			Where("NOT EXISTS (SELECT 1 FROM deletion_steps WHERE deletion_steps.recipe_id = recipe_deletions.recipe_id AND deletion_steps.status = ?)", m.StepPending).
+			Order("requested_at").
+			Limit(limit).
+			Pluck("recipe_id", &recipeIDs).Error; err != nil {
+			return err
+		}
+
+		if len(recipeIDs) <= 0 {
+			return nil
+		}
+
+		var steps []m.DeletionStep
+		for _, recipeID := range recipeIDs {
+			steps = append(steps, m.NewPurgeSteps(recipeID)...)
+		}
+
+		if err := tx.Where("recipe_id IN ?", recipeIDs).Delete(&m.DeletionStep{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&steps).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&m.RecipeDeletion{}).Where("recipe_id IN ?", recipeIDs).Update("action", m.DeletionActionPurge).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Where("id IN ? AND deleted_at IS NOT NULL", recipeIDs).Delete(&m.Recipe{}).Error
+	})
+}
+
+// UpdateStep stores the outcome of a claimed step and releases it. The step is only updated while it is still
+// claimed with the same lease and meant for the same action, so a delete finishing after the recipe was restored
+// doesn't overwrite the restore, and a step that took longer than its lease doesn't overwrite the outcome of the
+// instance that claimed it next.
+func (r DeletionRepository) UpdateStep(step m.DeletionStep) error {
+
+	return r.db.Model(&m.DeletionStep{}).
+		Where("recipe_id = ? AND service = ? AND action = ? AND lease_until = ?", step.RecipeID, step.Service, step.Action, step.LeaseUntil).
+		Updates(map[string]interface{}{
+			"status":          step.Status,
+			"attempts":        step.Attempts,
+			"last_error":      step.LastError,
+			"next_attempt_at": step.NextAttemptAt,
+			"lease_until":     nil,
+		}).Error
+}
@@ -0,0 +1,227 @@
+package repositories
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"recipe-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	deletionColumns = []string{"recipe_id", "action", "requested_at", "updated_at"}
+	stepColumns     = []string{"recipe_id", "service", "action", "status", "attempts", "last_error", "updated_at"}
+)
+
+func TestDeletionFindByRecipe_Ok(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewDeletionRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_deletions" WHERE recipe_id = $1 ORDER BY "recipe_deletions"."recipe_id" LIMIT $2`)).
+		WithArgs(recipe.ID, 1).
+		WillReturnRows(sqlmock.NewRows(deletionColumns).AddRow(recipe.ID, models.DeletionActionDelete, timeFunc(), timeFunc()))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "deletion_steps" WHERE "deletion_steps"."recipe_id" = $1 ORDER BY service`)).
+		WithArgs(recipe.ID).
+		WillReturnRows(sqlmock.NewRows(stepColumns).
+			AddRow(recipe.ID, models.ServiceImages, models.DeletionActionDelete, models.StepDone, 1, "", timeFunc()).
+			AddRow(recipe.ID, models.ServiceIngredients, models.DeletionActionDelete, models.StepPending, 2, "error", timeFunc()))
+
+	deletion, err := r.FindByRecipe(recipe.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, recipe.ID, deletion.RecipeID)
+	assert.Equal(t, models.DeletionActionDelete, deletion.Action)
+	assert.Len(t, deletion.Steps, 2)
+	assert.Equal(t, "error", deletion.Steps[1].LastError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeletionFindByRecipe_NotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewDeletionRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_deletions" WHERE recipe_id = $1 ORDER BY "recipe_deletions"."recipe_id" LIMIT $2`)).
+		WithArgs(recipe.ID, 1).
+		WillReturnRows(sqlmock.NewRows(deletionColumns))
+
+	_, err := r.FindByRecipe(recipe.ID)
+
+	assert.EqualError(t, err, "not found")
+}
+
+func TestDeletionFindByRecipe_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewDeletionRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_deletions" WHERE recipe_id = $1 ORDER BY "recipe_deletions"."recipe_id" LIMIT $2`)).
+		WithArgs(recipe.ID, 1).
+		WillReturnError(errors.New("error"))
+
+	_, err := r.FindByRecipe(recipe.ID)
+
+	assert.EqualError(t, err, "error")
+}
+
+func TestDeletionClaim_Ok(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewDeletionRepository(db)
+	leaseUntil := timeFunc().Add(5 * time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_deletions" WHERE EXISTS (SELECT 1 FROM deletion_steps WHERE deletion_steps.recipe_id = recipe_deletions.recipe_id AND deletion_steps.status = $1 AND (deletion_steps.next_attempt_at IS NULL OR deletion_steps.next_attempt_at <= $2) AND (deletion_steps.lease_until IS NULL OR deletion_steps.lease_until <= $3)) ORDER BY updated_at LIMIT $4 FOR UPDATE SKIP LOCKED`)).
+		WithArgs(models.StepPending, timeFunc(), timeFunc(), 10).
+		WillReturnRows(sqlmock.NewRows(deletionColumns).AddRow(recipe.ID, models.DeletionActionDelete, timeFunc(), timeFunc()))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "deletion_steps" WHERE deletion_steps.recipe_id IN ($1) AND (deletion_steps.status = $2 AND (deletion_steps.next_attempt_at IS NULL OR deletion_steps.next_attempt_at <= $3) AND (deletion_steps.lease_until IS NULL OR deletion_steps.lease_until <= $4)) ORDER BY service`)).
+		WithArgs(recipe.ID, models.StepPending, timeFunc(), timeFunc()).
+		WillReturnRows(sqlmock.NewRows(stepColumns).
+			AddRow(recipe.ID, models.ServiceMetadata, models.DeletionActionDelete, models.StepPending, 0, "", timeFunc()))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "deletion_steps" SET "lease_until"=$1,"updated_at"=$2 WHERE deletion_steps.recipe_id IN ($3) AND (deletion_steps.status = $4 AND (deletion_steps.next_attempt_at IS NULL OR deletion_steps.next_attempt_at <= $5) AND (deletion_steps.lease_until IS NULL OR deletion_steps.lease_until <= $6))`)).
+		WithArgs(leaseUntil, sqlmock.AnyArg(), recipe.ID, models.StepPending, timeFunc(), timeFunc()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_deletions" SET "updated_at"=$1 WHERE recipe_id IN ($2)`)).
+		WithArgs(timeFunc(), recipe.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	deletions, err := r.Claim(10, timeFunc(), leaseUntil)
+
+	assert.NoError(t, err)
+	assert.Len(t, deletions, 1)
+	assert.Len(t, deletions[0].Steps, 1)
+	assert.Equal(t, models.ServiceMetadata, deletions[0].Steps[0].Service)
+	assert.Equal(t, leaseUntil, *deletions[0].Steps[0].LeaseUntil)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeletionClaim_NotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewDeletionRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_deletions" WHERE EXISTS`)).
+		WithArgs(models.StepPending, timeFunc(), timeFunc(), 10).
+		WillReturnRows(sqlmock.NewRows(deletionColumns))
+	mock.ExpectCommit()
+
+	_, err := r.Claim(10, timeFunc(), timeFunc().Add(5*time.Minute))
+
+	assert.EqualError(t, err, "not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeletionClaim_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewDeletionRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_deletions" WHERE EXISTS`)).
+		WithArgs(models.StepPending, timeFunc(), timeFunc(), 10).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	_, err := r.Claim(10, timeFunc(), timeFunc().Add(5*time.Minute))
+
+	assert.EqualError(t, err, "error")
+}
+
+func TestDeletionExpire_Ok(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewDeletionRepository(db)
+	before := timeFunc().Add(-24 * time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "recipe_id" FROM "recipe_deletions" WHERE (action = $1 AND requested_at < $2) AND (NOT EXISTS (SELECT 1 FROM deletion_steps WHERE deletion_steps.recipe_id = recipe_deletions.recipe_id AND deletion_steps.status = $3)) ORDER BY requested_at LIMIT $4 FOR UPDATE SKIP LOCKED`)).
+		WithArgs(models.DeletionActionDelete, before, models.StepPending, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(recipe.ID))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "deletion_steps" WHERE recipe_id IN ($1)`)).
+		WithArgs(recipe.ID).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "deletion_steps" ("recipe_id","service","action","status","attempts","last_error","next_attempt_at","lease_until","updated_at") VALUES ` +
+		`($1,$2,$3,$4,$5,$6,$7,$8,$9),($10,$11,$12,$13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24,$25,$26,$27)`)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_deletions" SET "action"=$1,"updated_at"=$2 WHERE recipe_id IN ($3)`)).
+		WithArgs(models.DeletionActionPurge, sqlmock.AnyArg(), recipe.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipes" WHERE id IN ($1) AND deleted_at IS NOT NULL`)).
+		WithArgs(recipe.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := r.Expire(before, 10)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeletionExpire_NothingExpired(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewDeletionRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "recipe_id" FROM "recipe_deletions"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}))
+	mock.ExpectCommit()
+
+	err := r.Expire(timeFunc(), 10)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeletionExpire_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewDeletionRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "recipe_id" FROM "recipe_deletions"`)).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	err := r.Expire(timeFunc(), 10)
+
+	assert.EqualError(t, err, "error")
+}
+
+func TestDeletionUpdateStep_Ok(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewDeletionRepository(db)
+	leaseUntil := timeFunc()
+
+	step := models.DeletionStep{
+		RecipeID:   recipe.ID,
+		Service:    models.ServiceImages,
+		Action:     models.DeletionActionRestore,
+		Status:     models.StepDone,
+		Attempts:   3,
+		LeaseUntil: &leaseUntil,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "deletion_steps" SET "attempts"=$1,"last_error"=$2,"lease_until"=$3,"next_attempt_at"=$4,"status"=$5,"updated_at"=$6 WHERE recipe_id = $7 AND service = $8 AND action = $9 AND lease_until = $10`)).
+		WithArgs(3, "", nil, nil, models.StepDone, sqlmock.AnyArg(), recipe.ID, models.ServiceImages, models.DeletionActionRestore, leaseUntil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := r.UpdateStep(step)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeletionUpdateStep_Err(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewDeletionRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "deletion_steps"`)).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	err := r.UpdateStep(models.DeletionStep{RecipeID: recipe.ID})
+
+	assert.EqualError(t, err, "error")
+}
@@ -2,10 +2,14 @@ package repositories
 
 import (
 	"errors"
+	"time"
 
+	"cookbook/pkg/outbox"
 	m "recipe-service/internal/models"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type RecipeRepository struct {
@@ -57,7 +61,7 @@ func (r RecipeRepository) Create(recipe m.Recipe) (m.Recipe, error) {
 			return err
 		}
 
-		return nil
+		return outbox.Record(tx, m.NewChangeEvent(m.EventCreated, m.EntityRecipe, recipe.ID))
 	}); err != nil {
 		return recipe, err
 	}
@@ -74,25 +78,90 @@ func (r RecipeRepository) Update(recipe m.Recipe) (m.Recipe, error) {
 			return err
 		}
 
-		return nil
+		return outbox.Record(tx, m.NewChangeEvent(m.EventUpdated, m.EntityRecipe, recipe.ID))
 	}); err != nil {
 		return recipe, err
 	}
 	return recipe, nil
 }
 
+// Delete soft-deletes the recipe and registers its deletion, so the deletion coordinator cleans up its parts at the
+// other services. A previous deletion of the recipe that was restored since is replaced.
 func (r RecipeRepository) Delete(recipe m.Recipe) error {
 
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		deletion := m.NewRecipeDeletion(recipe.ID, time.Now())
 
 		if err := tx.Delete(&recipe).Error; err != nil {
 			return err
 		}
 
-		return nil
+		if err := tx.Where("recipe_id = ?", recipe.ID).Delete(&m.DeletionStep{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("recipe_id = ?", recipe.ID).Delete(&m.RecipeDeletion{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&deletion).Error; err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventDeleted, m.EntityRecipe, recipe.ID))
 	}); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// Restore brings back a deleted recipe and turns its deletion around, so the deletion coordinator restores its parts
+// at the other services. Restoring a recipe that is already being restored does nothing. A recipe deleted before
+// deletedAfter, or already being purged, can no longer be restored.
+func (r RecipeRepository) Restore(recipeID uuid.UUID, deletedAfter time.Time) error {
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var deletion m.RecipeDeletion
+
+		// the deletion is locked, so it isn't turned into a purge at the same time
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("recipe_id = ?", recipeID).First(&deletion).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("not found")
+			}
+			return err
+		}
+
+		switch {
+		case deletion.Action == m.DeletionActionRestore:
+			return nil
+		case deletion.Action == m.DeletionActionPurge || deletion.RequestedAt.Before(deletedAfter):
+			return errors.New("restore window passed")
+		}
+
+		if err := tx.Unscoped().Model(&m.Recipe{}).
+			Where("id = ? AND deleted_at IS NOT NULL", recipeID).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&deletion).Update("action", m.DeletionActionRestore).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&m.DeletionStep{}).
+			Where("recipe_id = ?", recipeID).
+			Updates(map[string]interface{}{
+				"action":          m.DeletionActionRestore,
+				"status":          m.StepPending,
+				"attempts":        0,
+				"last_error":      "",
+				"next_attempt_at": nil,
+				"lease_until":     nil,
+			}).Error; err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, m.NewChangeEvent(m.EventCreated, m.EntityRecipe, recipeID))
+	})
+}
@@ -25,6 +25,7 @@ var (
 		Description:  "description",
 		ServingCount: 1,
 	}
+	deletedAfter = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
 )
 
 func newMockDatabase(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
@@ -69,6 +70,12 @@ func timeFunc() time.Time {
 	return time
 }
 
+func expectOutbox(mock sqlmock.Sqlmock, eventType string) {
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("id","aggregate_type","aggregate_id","type","data","occurred_at","attempts","last_error") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "sequence"`)).
+		WithArgs(sqlmock.AnyArg(), models.EntityRecipe, recipe.ID, eventType, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "").
+		WillReturnRows(sqlmock.NewRows([]string{"sequence"}).AddRow(1))
+}
+
 func TestRecipeFindAll_OK(t *testing.T) {
 	db, mock := newMockDatabase(t)
 	r := NewRecipeRepository(db)
@@ -157,12 +164,14 @@ func TestRecipeCreate_Ok(t *testing.T) {
 			recipe.ID,
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(recipe.ID))
+	expectOutbox(mock, models.EventCreated)
 	mock.ExpectCommit()
 
 	result, err := r.Create(recipe)
 
 	assert.NoError(t, err)
 	assert.IsType(t, result, models.Recipe{})
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestRecipeCreate_Err(t *testing.T) {
@@ -204,12 +213,14 @@ func TestRecipeUpdate_Ok(t *testing.T) {
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
+	expectOutbox(mock, models.EventUpdated)
 	mock.ExpectCommit()
 
 	result, err := r.Update(recipe)
 
 	assert.NoError(t, err)
 	assert.IsType(t, result, models.Recipe{})
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestRecipeUpdate_Err(t *testing.T) {
@@ -246,11 +257,31 @@ func TestRecipeDelete_Ok(t *testing.T) {
 			recipe.ID,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "deletion_steps" WHERE recipe_id = $1`)).
+		WithArgs(recipe.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_deletions" WHERE recipe_id = $1`)).
+		WithArgs(recipe.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_deletions" ("recipe_id","action","requested_at","updated_at") VALUES ($1,$2,$3,$4)`)).
+		WithArgs(recipe.ID, models.DeletionActionDelete, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "deletion_steps" ("recipe_id","service","action","status","attempts","last_error","next_attempt_at","lease_until","updated_at") VALUES `+
+		`($1,$2,$3,$4,$5,$6,$7,$8,$9),($10,$11,$12,$13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24,$25,$26,$27),($28,$29,$30,$31,$32,$33,$34,$35,$36) ON CONFLICT`)).
+		WithArgs(
+			recipe.ID, models.ServiceIngredients, models.DeletionActionDelete, models.StepPending, 0, "", nil, nil, sqlmock.AnyArg(),
+			recipe.ID, models.ServiceInstructions, models.DeletionActionDelete, models.StepPending, 0, "", nil, nil, sqlmock.AnyArg(),
+			recipe.ID, models.ServiceMetadata, models.DeletionActionDelete, models.StepPending, 0, "", nil, nil, sqlmock.AnyArg(),
+			recipe.ID, models.ServiceImages, models.DeletionActionDelete, models.StepPending, 0, "", nil, nil, sqlmock.AnyArg(),
+		).
+		WillReturnResult(sqlmock.NewResult(4, 4))
+	expectOutbox(mock, models.EventDeleted)
 	mock.ExpectCommit()
 
 	err := r.Delete(recipe)
 
 	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestRecipeDelete_Err(t *testing.T) {
@@ -271,3 +302,93 @@ func TestRecipeDelete_Err(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualError(t, err, "error")
 }
+
+func TestRecipeRestore_Ok(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_deletions" WHERE recipe_id = $1 ORDER BY "recipe_deletions"."recipe_id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(recipe.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "action", "requested_at"}).AddRow(recipe.ID, models.DeletionActionDelete, deletedAfter.Add(time.Hour)))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipes" SET "deleted_at"=$1,"updated_at"=$2 WHERE id = $3 AND deleted_at IS NOT NULL`)).
+		WithArgs(nil, sqlmock.AnyArg(), recipe.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "recipe_deletions" SET "action"=$1,"updated_at"=$2 WHERE "recipe_id" = $3`)).
+		WithArgs(models.DeletionActionRestore, sqlmock.AnyArg(), recipe.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "deletion_steps" SET "action"=$1,"attempts"=$2,"last_error"=$3,"lease_until"=$4,"next_attempt_at"=$5,"status"=$6,"updated_at"=$7 WHERE recipe_id = $8`)).
+		WithArgs(models.DeletionActionRestore, 0, "", nil, nil, models.StepPending, sqlmock.AnyArg(), recipe.ID).
+		WillReturnResult(sqlmock.NewResult(4, 4))
+	expectOutbox(mock, models.EventCreated)
+	mock.ExpectCommit()
+
+	err := r.Restore(recipe.ID, deletedAfter)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeRestore_AlreadyRestoring(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_deletions" WHERE recipe_id = $1 ORDER BY "recipe_deletions"."recipe_id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(recipe.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "action", "requested_at"}).AddRow(recipe.ID, models.DeletionActionRestore, deletedAfter.Add(time.Hour)))
+	mock.ExpectCommit()
+
+	err := r.Restore(recipe.ID, deletedAfter)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeRestore_NotFoundErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_deletions" WHERE recipe_id = $1 ORDER BY "recipe_deletions"."recipe_id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(recipe.ID, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectRollback()
+
+	err := r.Restore(recipe.ID, deletedAfter)
+
+	assert.EqualError(t, err, "not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeRestore_WindowPassedErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_deletions" WHERE recipe_id = $1 ORDER BY "recipe_deletions"."recipe_id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(recipe.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "action", "requested_at"}).AddRow(recipe.ID, models.DeletionActionDelete, deletedAfter.Add(-time.Hour)))
+	mock.ExpectRollback()
+
+	err := r.Restore(recipe.ID, deletedAfter)
+
+	assert.EqualError(t, err, "restore window passed")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeRestore_PurgingErr(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	r := NewRecipeRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "recipe_deletions" WHERE recipe_id = $1 ORDER BY "recipe_deletions"."recipe_id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(recipe.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "action", "requested_at"}).AddRow(recipe.ID, models.DeletionActionPurge, deletedAfter.Add(time.Hour)))
+	mock.ExpectRollback()
+
+	err := r.Restore(recipe.ID, deletedAfter)
+
+	assert.EqualError(t, err, "restore window passed")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,52 @@
+package routes
+
+import (
+	h "recipe-service/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handlers are the handlers the routes of the service are served by
+type Handlers struct {
+	Recipe     *h.RecipeHandlers
+	FullRecipe *h.FullRecipeHandlers
+	Deletion   *h.DeletionHandlers
+}
+
+// Register adds the routes of the service to the router. Every group is guarded by the auth middleware, which is
+// attached to the group itself, as a middleware added to one group does not carry over to its siblings.
+func Register(router *gin.Engine, auth gin.HandlerFunc, handlers Handlers) {
+	v1 := router.Group("/api/v2")
+	{
+		recipe := v1.Group("/recipes")
+		{
+			readRecipe := recipe.Group("")
+			readRecipe.Use(auth)
+			{
+				readRecipe.GET("", handlers.Recipe.GetAll)
+				readRecipe.GET(":id", handlers.Recipe.Get)
+				readRecipe.GET(":id/full", handlers.FullRecipe.Get)
+				readRecipe.GET(":id/deletion", handlers.Deletion.Get)
+			}
+
+			createRecipe := recipe.Group("")
+			createRecipe.Use(auth)
+			{
+				createRecipe.POST("", handlers.Recipe.Create)
+			}
+
+			updateRecipe := recipe.Group("")
+			updateRecipe.Use(auth)
+			{
+				updateRecipe.PUT(":id", handlers.Recipe.Update)
+			}
+
+			adminRecipe := recipe.Group("")
+			adminRecipe.Use(auth)
+			{
+				adminRecipe.DELETE(":id", handlers.Recipe.Delete)
+				adminRecipe.POST(":id/restore", handlers.Recipe.Restore)
+			}
+		}
+	}
+}
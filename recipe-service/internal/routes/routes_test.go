@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	h "recipe-service/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/tbaehler/gin-keycloak/pkg/ginkeycloak"
+)
+
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	auth := ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig{Url: "http://keycloak.invalid", Realm: "test"}).RestrictButForRole("administrator").Build()
+	Register(router, auth, Handlers{
+		Recipe:     &h.RecipeHandlers{},
+		FullRecipe: &h.FullRecipeHandlers{},
+		Deletion:   &h.DeletionHandlers{},
+	})
+
+	return router
+}
+
+func TestRegister_RestoreUnauthorized(t *testing.T) {
+	router := newRouter()
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/v2/recipes/"+uuid.New().String()+"/restore", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRegister_AllUnauthorized(t *testing.T) {
+	router := newRouter()
+
+	for _, route := range router.Routes() {
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, httptest.NewRequest(route.Method, strings.Replace(route.Path, ":id", uuid.New().String(), 1), nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "%s %s", route.Method, route.Path)
+	}
+}
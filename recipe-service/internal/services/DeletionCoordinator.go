@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	m "recipe-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// deletionBatchSize is the number of deletions handled per run
+	deletionBatchSize = 50
+	// stepLease is how long the steps of a batch are claimed, steps not carried out by then are left to the next run
+	stepLease = 5 * time.Minute
+	// maxStepBackoff caps the wait before a failed step is tried again
+	maxStepBackoff = 10 * time.Minute
+	// restoreMargin is subtracted from the deletion time when restoring, to allow for clock differences between the services
+	restoreMargin = 5 * time.Second
+)
+
+type DeletionRepository interface {
+	FindByRecipe(recipeID uuid.UUID) (m.RecipeDeletion, error)
+	Claim(limit int, now time.Time, leaseUntil time.Time) ([]m.RecipeDeletion, error)
+	Expire(before time.Time, limit int) error
+	UpdateStep(step m.DeletionStep) error
+}
+
+type CascadeClient interface {
+	DeleteRecipe(ctx context.Context, service string, recipeID uuid.UUID) error
+	RestoreRecipe(ctx context.Context, service string, recipeID uuid.UUID, since time.Time) error
+	PurgeRecipe(ctx context.Context, service string, recipeID uuid.UUID) error
+}
+
+// DeletionCoordinator carries out the deletion or restore of a recipe at the other services. Every service is a
+// separate step, which is retried with an increasing backoff until it succeeds. Once the restore window of a deleted
+// recipe has passed, its parts are purged at the other services.
+type DeletionCoordinator struct {
+	repo          DeletionRepository
+	client        CascadeClient
+	restoreWindow time.Duration
+	logger        m.LoggerInterface
+	now           func() time.Time
+}
+
+// NewDeletionCoordinator creates a new DeletionCoordinator instance
+func NewDeletionCoordinator(repo DeletionRepository, client CascadeClient, restoreWindow time.Duration, logger m.LoggerInterface) *DeletionCoordinator {
+	return &DeletionCoordinator{
+		repo:          repo,
+		client:        client,
+		restoreWindow: restoreWindow,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// Run processes the pending steps every interval until the context is cancelled
+func (c DeletionCoordinator) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Process(ctx); err != nil {
+				c.logger.Warnf("unable to process recipe deletions: %v", err)
+			}
+		}
+	}
+}
+
+// Process turns the deletions whose restore window has passed into purges, and carries out the pending steps of a
+// single batch of deletions. Steps that failed before are only returned once their backoff has passed. The steps are
+// claimed for a while before they are carried out, so several instances of the service process different deletions.
+func (c DeletionCoordinator) Process(ctx context.Context) error {
+	if err := c.repo.Expire(c.now().Add(-c.restoreWindow), deletionBatchSize); err != nil {
+		return err
+	}
+
+	now := c.now()
+	leaseUntil := now.Add(stepLease)
+
+	deletions, err := c.repo.Claim(deletionBatchSize, now, leaseUntil)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil
+		}
+		return err
+	}
+
+	for _, deletion := range deletions {
+		for _, step := range deletion.Steps {
+			if step.Status != m.StepPending {
+				continue
+			}
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			// another instance may claim the step once the lease has passed
+			if !c.now().Before(leaseUntil) {
+				return nil
+			}
+
+			step.Attempts++
+			if err := c.carryOut(ctx, deletion, step); err != nil {
+				c.logger.Warnf("unable to %s recipe %s at the %s service: %v", step.Action, step.RecipeID, step.Service, err)
+				nextAttemptAt := c.now().Add(backoff(step.Attempts))
+				step.LastError = err.Error()
+				step.NextAttemptAt = &nextAttemptAt
+			} else {
+				step.Status = m.StepDone
+				step.LastError = ""
+				step.NextAttemptAt = nil
+			}
+
+			if err := c.repo.UpdateStep(step); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Status reports how far deleting or restoring a recipe at the other services has come
+func (c DeletionCoordinator) Status(recipeDTO m.RecipeDTO) (m.RecipeDeletionDTO, error) {
+	deletion, err := c.repo.FindByRecipe(recipeDTO.ID)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return m.RecipeDeletionDTO{}, err
+		default:
+			return m.RecipeDeletionDTO{}, errors.New("internal server error")
+		}
+	}
+
+	return deletion.ConvertToDTO(), nil
+}
+
+func (c DeletionCoordinator) carryOut(ctx context.Context, deletion m.RecipeDeletion, step m.DeletionStep) error {
+	switch step.Action {
+	case m.DeletionActionDelete:
+		return c.client.DeleteRecipe(ctx, step.Service, step.RecipeID)
+	case m.DeletionActionRestore:
+		return c.client.RestoreRecipe(ctx, step.Service, step.RecipeID, deletion.RequestedAt.Add(-restoreMargin))
+	case m.DeletionActionPurge:
+		return c.client.PurgeRecipe(ctx, step.Service, step.RecipeID)
+	default:
+		return errors.New("unknown action " + step.Action)
+	}
+}
+
+// backoff is the wait before a failed step is tried again, it doubles with every attempt
+func backoff(attempts int) time.Duration {
+	if attempts >= 20 {
+		return maxStepBackoff
+	}
+
+	backoff := time.Second << attempts
+	if backoff > maxStepBackoff {
+		return maxStepBackoff
+	}
+	return backoff
+}
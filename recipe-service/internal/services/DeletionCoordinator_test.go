@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	m "recipe-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type LoggerMock struct{}
+
+func (l *LoggerMock) Debugf(format string, args ...interface{}) {}
+func (l *LoggerMock) Warnf(format string, args ...interface{})  {}
+
+type DeletionRepositoryMock struct {
+	deletions []m.RecipeDeletion
+	findErr   error
+	updateErr error
+	updated   []m.DeletionStep
+	claimedAt time.Time
+	leasedTo  time.Time
+	expireErr error
+	expiredAt time.Time
+}
+
+func (r *DeletionRepositoryMock) FindByRecipe(recipeID uuid.UUID) (m.RecipeDeletion, error) {
+	if r.findErr != nil {
+		return m.RecipeDeletion{}, r.findErr
+	}
+	for _, deletion := range r.deletions {
+		if deletion.RecipeID == recipeID {
+			return deletion, nil
+		}
+	}
+	return m.RecipeDeletion{}, errors.New("not found")
+}
+
+func (r *DeletionRepositoryMock) Claim(limit int, now time.Time, leaseUntil time.Time) ([]m.RecipeDeletion, error) {
+	r.claimedAt = now
+	r.leasedTo = leaseUntil
+	if r.findErr != nil {
+		return nil, r.findErr
+	}
+	if len(r.deletions) == 0 {
+		return nil, errors.New("not found")
+	}
+	return r.deletions, nil
+}
+
+func (r *DeletionRepositoryMock) Expire(before time.Time, limit int) error {
+	r.expiredAt = before
+	return r.expireErr
+}
+
+func (r *DeletionRepositoryMock) UpdateStep(step m.DeletionStep) error {
+	if r.updateErr != nil {
+		return r.updateErr
+	}
+	r.updated = append(r.updated, step)
+	return nil
+}
+
+type cascadeCall struct {
+	action  string
+	service string
+	since   time.Time
+}
+
+type CascadeClientMock struct {
+	failing map[string]error
+	calls   []cascadeCall
+}
+
+func (c *CascadeClientMock) DeleteRecipe(ctx context.Context, service string, recipeID uuid.UUID) error {
+	c.calls = append(c.calls, cascadeCall{action: m.DeletionActionDelete, service: service})
+	return c.failing[service]
+}
+
+func (c *CascadeClientMock) RestoreRecipe(ctx context.Context, service string, recipeID uuid.UUID, since time.Time) error {
+	c.calls = append(c.calls, cascadeCall{action: m.DeletionActionRestore, service: service, since: since})
+	return c.failing[service]
+}
+
+func (c *CascadeClientMock) PurgeRecipe(ctx context.Context, service string, recipeID uuid.UUID) error {
+	c.calls = append(c.calls, cascadeCall{action: m.DeletionActionPurge, service: service})
+	return c.failing[service]
+}
+
+var (
+	deletedAt = time.Date(2023, 2, 4, 18, 0, 0, 0, time.UTC)
+)
+
+func newDeletionCoordinator(repo *DeletionRepositoryMock, client *CascadeClientMock) *DeletionCoordinator {
+	c := NewDeletionCoordinator(repo, client, 24*time.Hour, &LoggerMock{})
+	c.now = func() time.Time {
+		return deletedAt.Add(time.Minute)
+	}
+	return c
+}
+
+func TestDeletionProcess_OK(t *testing.T) {
+	repo := &DeletionRepositoryMock{deletions: []m.RecipeDeletion{m.NewRecipeDeletion(recipe.ID, deletedAt)}}
+	client := &CascadeClientMock{}
+	c := newDeletionCoordinator(repo, client)
+
+	err := c.Process(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, client.calls, 4)
+	assert.Len(t, repo.updated, 4)
+	for _, step := range repo.updated {
+		assert.Equal(t, m.StepDone, step.Status)
+		assert.Equal(t, 1, step.Attempts)
+		assert.Nil(t, step.NextAttemptAt)
+	}
+}
+
+func TestDeletionProcess_StepErr(t *testing.T) {
+	repo := &DeletionRepositoryMock{deletions: []m.RecipeDeletion{m.NewRecipeDeletion(recipe.ID, deletedAt)}}
+	client := &CascadeClientMock{failing: map[string]error{m.ServiceImages: errors.New("unexpected status code 500")}}
+	c := newDeletionCoordinator(repo, client)
+
+	err := c.Process(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, repo.updated, 4)
+	for _, step := range repo.updated {
+		if step.Service == m.ServiceImages {
+			assert.Equal(t, m.StepPending, step.Status)
+			assert.Equal(t, "unexpected status code 500", step.LastError)
+		} else {
+			assert.Equal(t, m.StepDone, step.Status)
+		}
+	}
+}
+
+func TestDeletionProcess_Restore(t *testing.T) {
+	deletion := m.NewRecipeDeletion(recipe.ID, deletedAt)
+	deletion.Action = m.DeletionActionRestore
+	deletion.Steps = []m.DeletionStep{{RecipeID: recipe.ID, Service: m.ServiceMetadata, Action: m.DeletionActionRestore, Status: m.StepPending}}
+
+	repo := &DeletionRepositoryMock{deletions: []m.RecipeDeletion{deletion}}
+	client := &CascadeClientMock{}
+	c := newDeletionCoordinator(repo, client)
+
+	err := c.Process(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, client.calls, 1)
+	assert.Equal(t, m.DeletionActionRestore, client.calls[0].action)
+	assert.Equal(t, deletedAt.Add(-restoreMargin), client.calls[0].since)
+}
+
+func TestDeletionProcess_Purge(t *testing.T) {
+	deletion := m.NewRecipeDeletion(recipe.ID, deletedAt)
+	deletion.Action = m.DeletionActionPurge
+	deletion.Steps = m.NewPurgeSteps(recipe.ID)
+
+	repo := &DeletionRepositoryMock{deletions: []m.RecipeDeletion{deletion}}
+	client := &CascadeClientMock{}
+	c := newDeletionCoordinator(repo, client)
+
+	err := c.Process(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, c.now().Add(-24*time.Hour), repo.expiredAt)
+	assert.Len(t, client.calls, 3)
+	for _, call := range client.calls {
+		assert.Equal(t, m.DeletionActionPurge, call.action)
+		assert.NotEqual(t, m.ServiceImages, call.service)
+	}
+}
+
+func TestDeletionProcess_ExpireErr(t *testing.T) {
+	repo := &DeletionRepositoryMock{deletions: []m.RecipeDeletion{m.NewRecipeDeletion(recipe.ID, deletedAt)}, expireErr: errors.New("error")}
+	client := &CascadeClientMock{}
+	c := newDeletionCoordinator(repo, client)
+
+	err := c.Process(context.Background())
+
+	assert.EqualError(t, err, "error")
+	assert.Empty(t, client.calls)
+}
+
+func TestDeletionProcess_Backoff(t *testing.T) {
+	deletion := m.NewRecipeDeletion(recipe.ID, deletedAt)
+	deletion.Steps = []m.DeletionStep{
+		{RecipeID: recipe.ID, Service: m.ServiceIngredients, Action: m.DeletionActionDelete, Status: m.StepPending, Attempts: 5},
+		{RecipeID: recipe.ID, Service: m.ServiceImages, Action: m.DeletionActionDelete, Status: m.StepPending},
+	}
+
+	repo := &DeletionRepositoryMock{deletions: []m.RecipeDeletion{deletion}}
+	client := &CascadeClientMock{failing: map[string]error{
+		m.ServiceIngredients: errors.New("unexpected status code 503"),
+		m.ServiceImages:      errors.New("unexpected status code 503"),
+	}}
+	c := newDeletionCoordinator(repo, client)
+
+	err := c.Process(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, c.now(), repo.claimedAt)
+	assert.Equal(t, c.now().Add(stepLease), repo.leasedTo)
+	assert.Len(t, repo.updated, 2)
+	// failed 6 times, waits 64 seconds
+	assert.Equal(t, 6, repo.updated[0].Attempts)
+	assert.Equal(t, c.now().Add(64*time.Second), *repo.updated[0].NextAttemptAt)
+	// failed once, waits 2 seconds
+	assert.Equal(t, 1, repo.updated[1].Attempts)
+	assert.Equal(t, c.now().Add(2*time.Second), *repo.updated[1].NextAttemptAt)
+}
+
+func TestDeletionProcess_LeasePassed(t *testing.T) {
+	repo := &DeletionRepositoryMock{deletions: []m.RecipeDeletion{m.NewRecipeDeletion(recipe.ID, deletedAt)}}
+	client := &CascadeClientMock{}
+	c := newDeletionCoordinator(repo, client)
+
+	// every step takes two minutes, the lease has passed after the second
+	now := deletedAt
+	c.now = func() time.Time {
+		now = now.Add(2 * time.Minute)
+		return now
+	}
+
+	err := c.Process(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, client.calls, 2)
+	assert.Len(t, repo.updated, 2)
+}
+func TestDeletionProcess_NothingPending(t *testing.T) {
+	repo := &DeletionRepositoryMock{}
+	client := &CascadeClientMock{}
+	c := newDeletionCoordinator(repo, client)
+
+	err := c.Process(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, client.calls)
+}
+
+func TestDeletionProcess_FindErr(t *testing.T) {
+	repo := &DeletionRepositoryMock{findErr: errors.New("error")}
+	c := newDeletionCoordinator(repo, &CascadeClientMock{})
+
+	err := c.Process(context.Background())
+
+	assert.EqualError(t, err, "error")
+}
+
+func TestDeletionProcess_UpdateErr(t *testing.T) {
+	repo := &DeletionRepositoryMock{
+		deletions: []m.RecipeDeletion{m.NewRecipeDeletion(recipe.ID, deletedAt)},
+		updateErr: errors.New("error"),
+	}
+	client := &CascadeClientMock{}
+	c := newDeletionCoordinator(repo, client)
+
+	err := c.Process(context.Background())
+
+	assert.EqualError(t, err, "error")
+	assert.Len(t, client.calls, 1)
+}
+
+func TestDeletionStatus_OK(t *testing.T) {
+	deletion := m.NewRecipeDeletion(recipe.ID, deletedAt)
+	deletion.Steps[0].Status = m.StepDone
+
+	repo := &DeletionRepositoryMock{deletions: []m.RecipeDeletion{deletion}}
+	c := newDeletionCoordinator(repo, &CascadeClientMock{})
+
+	result, err := c.Status(m.RecipeDTO{ID: recipe.ID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.DeletionStatusDeleting, result.Status)
+	assert.Equal(t, deletedAt, result.DeletedAt)
+	assert.Len(t, result.Steps, 4)
+}
+
+func TestDeletionStatus_NotFound(t *testing.T) {
+	c := newDeletionCoordinator(&DeletionRepositoryMock{}, &CascadeClientMock{})
+
+	_, err := c.Status(m.RecipeDTO{ID: recipe.ID})
+
+	assert.EqualError(t, err, "not found")
+}
+
+func TestDeletionStatus_Err(t *testing.T) {
+	c := newDeletionCoordinator(&DeletionRepositoryMock{findErr: errors.New("error")}, &CascadeClientMock{})
+
+	_, err := c.Status(m.RecipeDTO{ID: recipe.ID})
+
+	assert.EqualError(t, err, "internal server error")
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	m "recipe-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type ServiceClient interface {
+	GetRecipeIngredients(ctx context.Context, authorization string, recipeID uuid.UUID) ([]m.RecipeIngredientDTO, error)
+	GetRecipeInstructions(ctx context.Context, authorization string, recipeID uuid.UUID) ([]m.InstructionDTO, error)
+	GetRecipeMetadata(ctx context.Context, authorization string, recipeID uuid.UUID) (m.RecipeMetadataDTO, error)
+	GetRecipeImages(ctx context.Context, authorization string, recipeID uuid.UUID) ([]m.ImageDTO, error)
+}
+
+type FullRecipeService struct {
+	repo   RecipeRepository
+	client ServiceClient
+}
+
+// NewFullRecipeService creates a new FullRecipeService instance
+func NewFullRecipeService(recipeRepo RecipeRepository, client ServiceClient) *FullRecipeService {
+	return &FullRecipeService{
+		repo:   recipeRepo,
+		client: client,
+	}
+}
+
+// FindFull composes a recipe with its ingredients, instructions, metadata and images. The other services are queried concurrently.
+// A failing service does not fail the whole recipe; the affected section stays empty and the error is reported in the result.
+func (s FullRecipeService) FindFull(ctx context.Context, recipeDTO m.RecipeDTO, authorization string) (m.FullRecipeDTO, error) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	recipe, err := s.repo.FindSingle(recipeDTO.ConvertFromDTO())
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return m.FullRecipeDTO{}, err
+		default:
+			return m.FullRecipeDTO{}, errors.New("internal server error")
+		}
+	}
+
+	full := m.FullRecipeDTO{
+		Recipe:       recipe.ConvertToDTO(),
+		Ingredients:  []m.RecipeIngredientDTO{},
+		Instructions: []m.InstructionDTO{},
+		Images:       []m.ImageDTO{},
+	}
+
+	// a section the other service has no data for is not an error, it is just empty
+	fail := func(section string, err error) {
+		if err.Error() == "not found" {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if full.Errors == nil {
+			full.Errors = make(map[string]string)
+		}
+		full.Errors[section] = err.Error()
+	}
+
+	wg.Add(4)
+
+	go func() {
+		defer wg.Done()
+
+		ingredients, err := s.client.GetRecipeIngredients(ctx, authorization, recipe.ID)
+		if err != nil {
+			fail("ingredients", err)
+			return
+		}
+		if ingredients != nil {
+			full.Ingredients = ingredients
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		instructions, err := s.client.GetRecipeInstructions(ctx, authorization, recipe.ID)
+		if err != nil {
+			fail("instructions", err)
+			return
+		}
+		if instructions != nil {
+			full.Instructions = instructions
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		metadata, err := s.client.GetRecipeMetadata(ctx, authorization, recipe.ID)
+		if err != nil {
+			fail("metadata", err)
+			return
+		}
+		full.Metadata = &metadata
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		images, err := s.client.GetRecipeImages(ctx, authorization, recipe.ID)
+		if err != nil {
+			fail("images", err)
+			return
+		}
+		if images != nil {
+			full.Images = images
+		}
+	}()
+
+	wg.Wait()
+
+	return full, nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	m "recipe-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ingredientsErr  error
+	instructionsErr error
+	metadataErr     error
+	imagesErr       error
+)
+
+type ServiceClientMock struct{}
+
+func (ServiceClientMock) GetRecipeIngredients(ctx context.Context, authorization string, recipeID uuid.UUID) ([]m.RecipeIngredientDTO, error) {
+	if ingredientsErr != nil {
+		return nil, ingredientsErr
+	}
+	return []m.RecipeIngredientDTO{{RecipeID: recipeID}}, nil
+}
+
+func (ServiceClientMock) GetRecipeInstructions(ctx context.Context, authorization string, recipeID uuid.UUID) ([]m.InstructionDTO, error) {
+	if instructionsErr != nil {
+		return nil, instructionsErr
+	}
+	return []m.InstructionDTO{{Sequence: 1}, {Sequence: 2}}, nil
+}
+
+func (ServiceClientMock) GetRecipeMetadata(ctx context.Context, authorization string, recipeID uuid.UUID) (m.RecipeMetadataDTO, error) {
+	if metadataErr != nil {
+		return m.RecipeMetadataDTO{}, metadataErr
+	}
+	return m.RecipeMetadataDTO{RecipeID: recipeID}, nil
+}
+
+func (ServiceClientMock) GetRecipeImages(ctx context.Context, authorization string, recipeID uuid.UUID) ([]m.ImageDTO, error) {
+	if imagesErr != nil {
+		return nil, imagesErr
+	}
+	return []m.ImageDTO{{EntityID: recipeID}}, nil
+}
+
+func resetServiceClientMock() {
+	ingredientsErr = nil
+	instructionsErr = nil
+	metadataErr = nil
+	imagesErr = nil
+}
+
+func TestFindFull_OK(t *testing.T) {
+	s := NewFullRecipeService(&RecipeRepositoryMock{}, &ServiceClientMock{})
+	resetServiceClientMock()
+
+	result, err := s.FindFull(context.Background(), m.RecipeDTO{Name: "find"}, "")
+
+	assert.NoError(t, err)
+	assert.IsType(t, m.FullRecipeDTO{}, result)
+	assert.Equal(t, recipe.ID, result.Recipe.ID)
+	assert.Len(t, result.Ingredients, 1)
+	assert.Len(t, result.Instructions, 2)
+	assert.Equal(t, recipe.ID, result.Metadata.RecipeID)
+	assert.Len(t, result.Images, 1)
+	assert.Nil(t, result.Errors)
+}
+
+func TestFindFull_PartialErr(t *testing.T) {
+	s := NewFullRecipeService(&RecipeRepositoryMock{}, &ServiceClientMock{})
+	resetServiceClientMock()
+
+	ingredientsErr = errors.New("unexpected status code 500")
+	metadataErr = errors.New("unexpected status code 503")
+
+	result, err := s.FindFull(context.Background(), m.RecipeDTO{Name: "find"}, "")
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Ingredients)
+	assert.NotNil(t, result.Ingredients)
+	assert.Nil(t, result.Metadata)
+	assert.Len(t, result.Instructions, 2)
+	assert.Len(t, result.Images, 1)
+	assert.Equal(t, map[string]string{
+		"ingredients": "unexpected status code 500",
+		"metadata":    "unexpected status code 503",
+	}, result.Errors)
+}
+
+func TestFindFull_SectionNotFound(t *testing.T) {
+	s := NewFullRecipeService(&RecipeRepositoryMock{}, &ServiceClientMock{})
+	resetServiceClientMock()
+
+	imagesErr = errors.New("not found")
+
+	result, err := s.FindFull(context.Background(), m.RecipeDTO{Name: "find"}, "")
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Images)
+	assert.Nil(t, result.Errors)
+}
+
+func TestFindFull_NotFound(t *testing.T) {
+	s := NewFullRecipeService(&RecipeRepositoryMock{}, &ServiceClientMock{})
+	resetServiceClientMock()
+
+	result, err := s.FindFull(context.Background(), m.RecipeDTO{Name: "notfound"}, "")
+
+	assert.EqualError(t, err, "not found")
+	assert.Equal(t, m.FullRecipeDTO{}, result)
+}
+
+func TestFindFull_Err(t *testing.T) {
+	s := NewFullRecipeService(&RecipeRepositoryMock{}, &ServiceClientMock{})
+	resetServiceClientMock()
+
+	result, err := s.FindFull(context.Background(), m.RecipeDTO{Name: "error"}, "")
+
+	assert.EqualError(t, err, "internal server error")
+	assert.Equal(t, m.FullRecipeDTO{}, result)
+}
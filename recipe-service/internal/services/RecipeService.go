@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"time"
 
 	m "recipe-service/internal/models"
 
@@ -14,16 +15,21 @@ type RecipeRepository interface {
 	Create(recipe m.Recipe) (m.Recipe, error)
 	Update(recipe m.Recipe) (m.Recipe, error)
 	Delete(recipe m.Recipe) error
+	Restore(recipeID uuid.UUID, deletedAfter time.Time) error
 }
 
 type RecipeService struct {
-	repo RecipeRepository
+	repo          RecipeRepository
+	restoreWindow time.Duration
+	now           func() time.Time
 }
 
-// NewRecipeService creates a new RecipeService instance
-func NewRecipeService(recipeRepo RecipeRepository) *RecipeService {
+// NewRecipeService creates a new RecipeService instance. A deleted recipe can be restored for the restore window.
+func NewRecipeService(recipeRepo RecipeRepository, restoreWindow time.Duration) *RecipeService {
 	return &RecipeService{
-		repo: recipeRepo,
+		repo:          recipeRepo,
+		restoreWindow: restoreWindow,
+		now:           time.Now,
 	}
 }
 
@@ -117,11 +123,36 @@ func (s RecipeService) Update(recipeDTO m.RecipeDTO) (m.RecipeDTO, error) {
 	return updatedRecipe.ConvertToDTO(), nil
 }
 
+// Delete soft-deletes a recipe, its parts at the other services are deleted afterwards by the deletion coordinator
 func (s RecipeService) Delete(recipeDTO m.RecipeDTO) error {
-	// TODO create safety logic
+
+	if _, err := s.repo.FindSingle(recipeDTO.ConvertFromDTO()); err != nil {
+		switch err.Error() {
+		case "not found":
+			return err
+		default:
+			return errors.New("internal server error")
+		}
+	}
+
 	if err := s.repo.Delete(recipeDTO.ConvertFromDTO()); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// Restore brings back a deleted recipe, its parts at the other services are restored afterwards by the deletion coordinator
+func (s RecipeService) Restore(recipeDTO m.RecipeDTO) error {
+
+	if err := s.repo.Restore(recipeDTO.ID, s.now().Add(-s.restoreWindow)); err != nil {
+		switch err.Error() {
+		case "not found", "restore window passed":
+			return err
+		default:
+			return errors.New("internal server error")
+		}
+	}
+
+	return nil
+}
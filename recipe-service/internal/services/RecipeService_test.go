@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"testing"
+	"time"
 
 	m "recipe-service/internal/models"
 
@@ -23,6 +24,8 @@ var (
 		Description:  "description",
 		ServingCount: 1,
 	}
+	expiredRecipeID = uuid.New()
+	restoreNow      = time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC)
 )
 
 type RecipeRepositoryMock struct{}
@@ -48,6 +51,10 @@ func (RecipeRepositoryMock) FindSingle(recipeInput m.Recipe) (m.Recipe, error) {
 		return recipe, nil
 	case "updateerror":
 		return recipe, nil
+	case "delete":
+		return recipe, nil
+	case "deleteerror":
+		return recipe, nil
 	case "":
 		return recipe, nil
 	case "notfound":
@@ -86,8 +93,24 @@ func (RecipeRepositoryMock) Delete(recipeInput m.Recipe) error {
 	}
 }
 
+func (RecipeRepositoryMock) Restore(recipeID uuid.UUID, deletedAfter time.Time) error {
+	switch recipeID {
+	case recipe.ID:
+		if !deletedAfter.Equal(restoreNow.Add(-24 * time.Hour)) {
+			return errors.New("error")
+		}
+		return nil
+	case expiredRecipeID:
+		return errors.New("restore window passed")
+	case findAllRecipe.ID:
+		return errors.New("not found")
+	default:
+		return errors.New("error")
+	}
+}
+
 func TestRecipeFindAll_OK(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	findAllRecipe.Name = "findall"
 
@@ -99,7 +122,7 @@ func TestRecipeFindAll_OK(t *testing.T) {
 }
 
 func TestRecipeFindAll_Err(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	findAllRecipe.Name = "error"
 
@@ -111,7 +134,7 @@ func TestRecipeFindAll_Err(t *testing.T) {
 }
 
 func TestRecipeFindAll_NotFound(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	findAllRecipe.Name = "notfound"
 
@@ -123,7 +146,7 @@ func TestRecipeFindAll_NotFound(t *testing.T) {
 }
 
 func TestRecipeFindSingle_OK(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		ID:   recipe.ID,
@@ -138,7 +161,7 @@ func TestRecipeFindSingle_OK(t *testing.T) {
 }
 
 func TestRecipeFindSingle_Err(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		ID:   recipe.ID,
@@ -152,7 +175,7 @@ func TestRecipeFindSingle_Err(t *testing.T) {
 }
 
 func TestRecipeFindSingle_NotFound(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		ID:   recipe.ID,
@@ -166,7 +189,7 @@ func TestRecipeFindSingle_NotFound(t *testing.T) {
 }
 
 func TestRecipeCreate_OK(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		Name:         "create",
@@ -182,7 +205,7 @@ func TestRecipeCreate_OK(t *testing.T) {
 }
 
 func TestRecipeCreate_IDErr(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		ID:   recipe.ID,
@@ -196,7 +219,7 @@ func TestRecipeCreate_IDErr(t *testing.T) {
 }
 
 func TestRecipeCreate_NoName(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		Name: "",
@@ -209,7 +232,7 @@ func TestRecipeCreate_NoName(t *testing.T) {
 }
 
 func TestRecipeCreate_NoDescription(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		Name:        recipe.Name,
@@ -223,7 +246,7 @@ func TestRecipeCreate_NoDescription(t *testing.T) {
 }
 
 func TestRecipeCreate_NoServingCount(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		Name:         recipe.Name,
@@ -238,7 +261,7 @@ func TestRecipeCreate_NoServingCount(t *testing.T) {
 }
 
 func TestRecipeCreate_CreateErr(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		Name:         "error",
@@ -253,7 +276,7 @@ func TestRecipeCreate_CreateErr(t *testing.T) {
 }
 
 func TestRecipeUpdate_Ok(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		ID:           recipe.ID,
@@ -272,7 +295,7 @@ func TestRecipeUpdate_Ok(t *testing.T) {
 }
 
 func TestRecipeUpdate_NoNameErr(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		ID:           recipe.ID,
@@ -287,7 +310,7 @@ func TestRecipeUpdate_NoNameErr(t *testing.T) {
 }
 
 func TestRecipeUpdate_NoDescription(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		ID:           recipe.ID,
@@ -302,7 +325,7 @@ func TestRecipeUpdate_NoDescription(t *testing.T) {
 }
 
 func TestRecipeUpdate_NoServingCount(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		ID:           recipe.ID,
@@ -317,7 +340,7 @@ func TestRecipeUpdate_NoServingCount(t *testing.T) {
 }
 
 func TestRecipeUpdate_FindErr(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		ID:           recipe.ID,
@@ -333,7 +356,7 @@ func TestRecipeUpdate_FindErr(t *testing.T) {
 }
 
 func TestRecipeUpdate_UpdateErr(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		ID:           recipe.ID,
@@ -349,7 +372,7 @@ func TestRecipeUpdate_UpdateErr(t *testing.T) {
 }
 
 func TestRecipeDelete_Ok(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		ID:   recipe.ID,
@@ -361,14 +384,75 @@ func TestRecipeDelete_Ok(t *testing.T) {
 }
 
 func TestRecipeDelete_DeleteErr(t *testing.T) {
-	s := NewRecipeService(&RecipeRepositoryMock{})
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
 
 	recipeDTO := m.RecipeDTO{
 		ID:   recipe.ID,
-		Name: "error",
+		Name: "deleteerror",
 	}
 	err := s.Delete(recipeDTO)
 
 	assert.Error(t, err)
 	assert.EqualError(t, err, "error")
 }
+
+func TestRecipeDelete_NotFound(t *testing.T) {
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
+
+	recipeDTO := m.RecipeDTO{
+		ID:   recipe.ID,
+		Name: "notfound",
+	}
+	err := s.Delete(recipeDTO)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "not found")
+}
+
+func TestRecipeDelete_FindErr(t *testing.T) {
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
+
+	recipeDTO := m.RecipeDTO{
+		ID:   recipe.ID,
+		Name: "error",
+	}
+	err := s.Delete(recipeDTO)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "internal server error")
+}
+
+func TestRecipeRestore_Ok(t *testing.T) {
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
+	s.now = func() time.Time { return restoreNow }
+
+	err := s.Restore(m.RecipeDTO{ID: recipe.ID})
+
+	assert.NoError(t, err)
+}
+
+func TestRecipeRestore_WindowPassed(t *testing.T) {
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
+
+	err := s.Restore(m.RecipeDTO{ID: expiredRecipeID})
+
+	assert.EqualError(t, err, "restore window passed")
+}
+
+func TestRecipeRestore_NotFound(t *testing.T) {
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
+
+	err := s.Restore(m.RecipeDTO{ID: findAllRecipe.ID})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "not found")
+}
+
+func TestRecipeRestore_Err(t *testing.T) {
+	s := NewRecipeService(&RecipeRepositoryMock{}, 24*time.Hour)
+
+	err := s.Restore(m.RecipeDTO{ID: uuid.New()})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, "internal server error")
+}
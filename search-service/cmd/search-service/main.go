@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	c "search-service/internal/config"
+	s "search-service/internal/search-service"
+	"syscall"
+)
+
+var (
+	log = c.Logger
+)
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sys := make(chan os.Signal, 1)
+	signal.Notify(sys, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	defer func() {
+		signal.Stop(sys)
+		cancel()
+	}()
+
+	go func() {
+		select {
+		case <-sys:
+			log.Info("sigterm received. Exiting.")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// "search-service reindex" rebuilds the index and exits. Changes handled by running instances during the rebuild
+	// are only picked up afterwards when the rebuild is started through the API instead.
+	if len(os.Args) > 1 && os.Args[1] == "reindex" {
+		status, err := c.IndexService.Reindex(ctx)
+		if err != nil {
+			log.Fatalf("reindex failed: %v", err)
+		}
+
+		log.Infof("reindex finished: %d of %d recipes indexed, %d failed %v", status.Indexed, status.Total, len(status.Failed), status.Failed)
+		return
+	}
+
+	s.SearchService(ctx)
+
+	ctx.Done()
+
+}
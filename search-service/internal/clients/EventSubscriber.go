@@ -0,0 +1,109 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	m "search-service/internal/models"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	// the stream and subjects the other services publish their events to
+	streamName    = "COOKBOOK"
+	eventSubjects = "cookbook.>"
+
+	// the durable consumer keeps track of the events handled by this service across restarts
+	durableName = "search-service"
+
+	// time before an event that could not be handled is delivered again
+	retryDelay = 5 * time.Second
+)
+
+// EventSubscriber consumes the events the other services publish to NATS JetStream
+type EventSubscriber struct {
+	conn   *nats.Conn
+	js     nats.JetStreamContext
+	logger m.LoggerInterface
+}
+
+// NewEventSubscriber connects to NATS and creates the stream when it does not exist yet, as the other services
+// may not have published anything so far
+func NewEventSubscriber(url string, logger m.LoggerInterface) (*EventSubscriber, error) {
+	conn, err := nats.Connect(url, nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if _, err := js.StreamInfo(streamName); err != nil {
+		if !errors.Is(err, nats.ErrStreamNotFound) {
+			conn.Close()
+			return nil, err
+		}
+
+		if _, err := js.AddStream(&nats.StreamConfig{
+			Name:     streamName,
+			Subjects: []string{eventSubjects},
+		}); err != nil && !errors.Is(err, nats.ErrStreamNameAlreadyInUse) {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return &EventSubscriber{
+		conn:   conn,
+		js:     js,
+		logger: logger,
+	}, nil
+}
+
+// Run hands the events to the handler until the context is done. Only one event is delivered at a time, so the
+// changes to an entity are handled in the order they were made. An event is acknowledged once the handler returns
+// without an error, and delivered again otherwise.
+func (s EventSubscriber) Run(ctx context.Context, handler func(ctx context.Context, event m.CloudEventDTO) error) {
+	sub, err := s.js.Subscribe(eventSubjects, func(msg *nats.Msg) {
+		var event m.CloudEventDTO
+
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			// the event would never be readable, so it is not delivered again
+			s.logger.Warnf("dropping unreadable event on %s: %v", msg.Subject, err)
+			msg.Term()
+			return
+		}
+
+		if err := handler(ctx, event); err != nil {
+			s.logger.Warnf("unable to handle event %s (%s), retrying: %v", event.ID, event.Type, err)
+			msg.NakWithDelay(retryDelay)
+			return
+		}
+
+		msg.Ack()
+	},
+		nats.BindStream(streamName),
+		nats.Durable(durableName),
+		nats.DeliverAll(),
+		nats.ManualAck(),
+		nats.AckExplicit(),
+		nats.MaxAckPending(1),
+	)
+	if err != nil {
+		s.logger.Errorf("unable to subscribe to the events: %v", err)
+		return
+	}
+
+	s.logger.Infof("consuming events on %s as %s", sub.Subject, durableName)
+
+	<-ctx.Done()
+
+	// closing instead of unsubscribing keeps the durable consumer, and with it the position in the stream
+	s.conn.Close()
+}
@@ -0,0 +1,100 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	m "search-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	recipeID uuid.UUID = uuid.New()
+)
+
+func newTestServer(t *testing.T, path string, status int, body interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, path, r.URL.Path)
+
+		w.WriteHeader(status)
+		if body != nil {
+			json.NewEncoder(w).Encode(body)
+		}
+	}))
+}
+
+func TestGetRecipes_OK(t *testing.T) {
+	srv := newTestServer(t, "/api/v2/recipes", http.StatusOK, []m.RecipeDTO{{ID: recipeID, Name: "pasta"}})
+	defer srv.Close()
+
+	c := NewRecipeClient(http.DefaultClient, srv.URL+"/")
+
+	result, err := c.GetRecipes(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, recipeID, result[0].ID)
+}
+
+func TestGetRecipes_NotFound(t *testing.T) {
+	srv := newTestServer(t, "/api/v2/recipes", http.StatusNotFound, map[string]string{"error": "no recipes found"})
+	defer srv.Close()
+
+	c := NewRecipeClient(http.DefaultClient, srv.URL)
+
+	result, err := c.GetRecipes(context.Background())
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "not found")
+}
+
+func TestGetFullRecipe_OK(t *testing.T) {
+	srv := newTestServer(t, "/api/v2/recipes/"+recipeID.String()+"/full", http.StatusOK, map[string]interface{}{
+		"recipe":       map[string]interface{}{"ID": recipeID, "name": "pasta"},
+		"ingredients":  []map[string]interface{}{{"IngredientName": "penne"}},
+		"instructions": []map[string]interface{}{{"sequence": 1, "description": "boil"}},
+		"metadata":     map[string]interface{}{"tags": []map[string]interface{}{{"name": "quick"}}},
+	})
+	defer srv.Close()
+
+	c := NewRecipeClient(http.DefaultClient, srv.URL)
+
+	result, err := c.GetFullRecipe(context.Background(), recipeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, recipeID, result.Recipe.ID)
+	assert.Equal(t, "pasta", result.Recipe.Name)
+	assert.Equal(t, "penne", result.Ingredients[0].IngredientName)
+	assert.Equal(t, "boil", result.Instructions[0].Description)
+	assert.Equal(t, "quick", result.Metadata.Tags[0].Name)
+}
+
+func TestGetFullRecipe_StatusErr(t *testing.T) {
+	srv := newTestServer(t, "/api/v2/recipes/"+recipeID.String()+"/full", http.StatusInternalServerError, nil)
+	defer srv.Close()
+
+	c := NewRecipeClient(http.DefaultClient, srv.URL)
+
+	result, err := c.GetFullRecipe(context.Background(), recipeID)
+
+	assert.Equal(t, m.FullRecipeDTO{}, result)
+	assert.EqualError(t, err, "unexpected status code 500")
+}
+
+func TestGetFullRecipe_DecodeErr(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not json"))
+	}))
+	defer srv.Close()
+
+	c := NewRecipeClient(http.DefaultClient, srv.URL)
+
+	_, err := c.GetFullRecipe(context.Background(), recipeID)
+
+	assert.ErrorContains(t, err, "unable to decode response")
+}
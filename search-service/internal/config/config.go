@@ -0,0 +1,85 @@
+package config
+
+import (
+	cl "search-service/internal/clients"
+	h "search-service/internal/handlers"
+	m "search-service/internal/models"
+	s "search-service/internal/services"
+
+	sr "search-service/internal/repositories/search"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gin-contrib/cors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+var (
+	Configuration m.Config
+
+	Logger         *log.Logger = log.New()
+	DatabaseClient *gorm.DB
+	Cors           cors.Config
+
+	// Repositories
+	SearchRepository      *sr.SearchRepository
+	SavedSearchRepository *sr.SavedSearchRepository
+
+	// Clients
+	RecipeClient    *cl.RecipeClient
+	EventSubscriber *cl.EventSubscriber
+
+	// Services
+	SearchService        *s.SearchService
+	IndexService         *s.IndexService
+	SavedSearchService   *s.SavedSearchService
+	SavedSearchEvaluator *s.SavedSearchEvaluator
+	EventService         *s.EventService
+
+	// Handlers
+	SearchHandlers      *h.SearchHandlers
+	IndexHandlers       *h.IndexHandlers
+	SavedSearchHandlers *h.SavedSearchHandlers
+	EventHandlers       *h.EventHandlers
+)
+
+func init() {
+	initViper()
+	initConfig()
+	initLogging()
+
+	viper.WatchConfig()
+	viper.OnConfigChange(func(e fsnotify.Event) {
+		log.Infof("config file changed: %s", e.Name)
+
+		initConfig()
+		initLogging()
+	})
+
+	initDatabase()
+	initCors()
+
+	// Init repositories
+	SearchRepository = sr.NewSearchRepository(DatabaseClient, searchLanguage())
+	SavedSearchRepository = sr.NewSavedSearchRepository(DatabaseClient, searchLanguage())
+
+	// Init clients
+	RecipeClient = cl.NewRecipeClient(recipeHttpClient(), Configuration.Services.RecipeServiceUrl)
+
+	// Init services
+	SearchService = s.NewSearchService(SearchRepository, Logger)
+	SavedSearchService = s.NewSavedSearchService(SavedSearchRepository, Logger)
+	SavedSearchEvaluator = s.NewSavedSearchEvaluator(SavedSearchRepository, Logger)
+	IndexService = s.NewIndexService(RecipeClient, SearchRepository, SavedSearchEvaluator, Logger)
+	EventService = s.NewEventService(IndexService, SearchRepository, Logger)
+
+	// Init handlers
+	SearchHandlers = h.NewSearchHandlers(SearchService, Logger)
+	IndexHandlers = h.NewIndexHandlers(IndexService, Logger)
+	SavedSearchHandlers = h.NewSavedSearchHandlers(SavedSearchService, Logger)
+	EventHandlers = h.NewEventHandlers(EventService, Logger)
+
+	// Init events
+	initEvents()
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	cl "search-service/internal/clients"
+	"search-service/internal/helpers"
+	m "search-service/internal/models"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"golang.org/x/oauth2/clientcredentials"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func initLogging() {
+	log.Info("setting up the logging framework")
+
+	Logger.SetFormatter(&log.TextFormatter{
+		DisableColors: false,
+		FullTimestamp: true,
+	})
+
+	logLevels := helpers.SetupLogLevels()
+
+	if i, found := logLevels[strings.ToUpper(Configuration.Global.LogLevel)]; found {
+		Logger.SetLevel(i)
+		Logger.Infof("loglevel set to %s", strings.ToUpper(Logger.Level.String()))
+
+	} else {
+		Logger.Warn("no or invalid loglevel specified. Assuming default value of INFO. \n valid loglevels are: PANIC FATAL ERROR WARN INFO DEBUG TRACE")
+		Logger.SetLevel(logLevels["INFO"])
+	}
+}
+
+func initViper() {
+	viper.SetConfigName("config")
+	viper.SetConfigType("yaml")
+	viper.AddConfigPath(".")
+	viper.AddConfigPath("/config")
+}
+
+func initConfig() {
+	Logger.Info("loading config")
+
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			Logger.Fatalf("config file not found: %v", err)
+		} else {
+			Logger.Fatalf("unknown error occured while reading config. error: %v", err)
+		}
+	}
+
+	if err := viper.Unmarshal(&Configuration); err != nil {
+		Logger.Fatalf("error unmarshaling config: %v", err)
+	}
+
+	Logger.Info("config file loaded")
+}
+
+func initDatabase() {
+	Logger.Info("connecting to the database")
+	var err error
+
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=%s",
+		Configuration.Database.Host,
+		Configuration.Database.Username,
+		Configuration.Database.Password,
+		Configuration.Database.Database,
+		Configuration.Database.Port,
+		Configuration.Database.SSLMode,
+		Configuration.Database.Timezone)
+
+	DatabaseClient, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+
+	if err != nil {
+		Logger.Fatalf("Unable to connect to the database. Exiting..\n%v\n", err)
+	}
+
+	Logger.Info("performing database migrations")
+	// trigram matching is used to suggest names despite typos
+	if err := DatabaseClient.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		Logger.Fatalf("Error while enabling the pg_trgm extension: %s", err.Error())
+	}
+
+	if err := DatabaseClient.AutoMigrate(
+		&m.RecipeDocument{},
+		&m.RecipeTerm{},
+		&m.SavedSearch{},
+		&m.SavedSearchMatch{},
+	); err != nil {
+		Logger.Fatalf("Error while automigrating database: %s", err.Error())
+	}
+
+	Logger.Info("connected!")
+}
+
+func initCors() {
+	Cors = cors.Config{
+		AllowOrigins:     Configuration.Cors.AllowedOrigins,
+		AllowMethods:     Configuration.Cors.AllowedMethods,
+		AllowHeaders:     Configuration.Cors.AllowedHeaders,
+		ExposeHeaders:    []string{"Content-Length"},
+		AllowCredentials: Configuration.Cors.AllowCredentials,
+		MaxAge:           12 * time.Hour,
+	}
+}
+
+func serviceTimeout() time.Duration {
+	if Configuration.Services.Timeout <= 0 {
+		Logger.Warn("no or invalid service timeout specified. Assuming default value of 5 seconds")
+		return 5 * time.Second
+	}
+
+	return time.Duration(Configuration.Services.Timeout) * time.Second
+}
+
+// recipeHttpClient returns the client the recipes are retrieved with. With a client configured it authenticates
+// with the client credentials at keycloak
+func recipeHttpClient() *http.Client {
+	if Configuration.Services.ClientID == "" {
+		Logger.Warn("no service client specified. Recipes are retrieved without authentication")
+		return &http.Client{Timeout: serviceTimeout()}
+	}
+
+	credentials := clientcredentials.Config{
+		ClientID:     Configuration.Services.ClientID,
+		ClientSecret: Configuration.Services.ClientSecret,
+		TokenURL:     fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", strings.TrimSuffix(Configuration.Oauth.Url, "/"), Configuration.Oauth.Realm),
+	}
+
+	client := credentials.Client(context.Background())
+	client.Timeout = serviceTimeout()
+
+	return client
+}
+
+func searchLanguage() string {
+	if Configuration.Search.Language == "" {
+		Logger.Warn("no search language specified. Assuming default value of english")
+		return "english"
+	}
+
+	return Configuration.Search.Language
+}
+
+// initEvents connects to the broker the changes are consumed from, if any
+func initEvents() {
+	switch Configuration.Events.Broker {
+	case "nats":
+		subscriber, err := cl.NewEventSubscriber(Configuration.Events.NatsUrl, Logger)
+		if err != nil {
+			Logger.Fatalf("unable to connect to the event broker at %s: %v", Configuration.Events.NatsUrl, err)
+		}
+		EventSubscriber = subscriber
+	case "":
+		Logger.Info("no event broker specified. Changes are only received on the events endpoint")
+	default:
+		Logger.Fatalf("unknown event broker %s. Use nats or leave it empty", Configuration.Events.Broker)
+	}
+}
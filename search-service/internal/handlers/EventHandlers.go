@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	m "search-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventService interface {
+	Handle(ctx context.Context, eventDTO m.ChangeEventDTO) (m.IndexReportDTO, error)
+}
+
+type EventHandlers struct {
+	eventService EventService
+	logger       m.LoggerInterface
+}
+
+func NewEventHandlers(eventService EventService, logger m.LoggerInterface) *EventHandlers {
+	return &EventHandlers{
+		eventService: eventService,
+		logger:       logger,
+	}
+}
+
+// Handle updates the index on a change reported by another service, such as
+// {"type":"updated","entity_type":"tag","entity_id":"...","occurred_at":"..."}
+func (h EventHandlers) Handle(ctx *gin.Context) {
+	var eventDTO m.ChangeEventDTO
+
+	if err := ctx.ShouldBindJSON(&eventDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unexpected JSON input"})
+		return
+	}
+
+	reportDTO, err := h.eventService.Handle(ctx.Request.Context(), eventDTO)
+	if err != nil {
+		switch err.Error() {
+		case "invalid event type", "invalid entity type", "invalid entity ID":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, reportDTO)
+}
+
+// Consume updates the index on a change consumed from the event broker. Events that can never be handled, such as
+// changes to entities the index does not keep track of, are skipped. Any other error has the event delivered again.
+func (h EventHandlers) Consume(ctx context.Context, event m.CloudEventDTO) error {
+	var eventDTO m.ChangeEventDTO
+
+	if err := json.Unmarshal(event.Data, &eventDTO); err != nil {
+		h.logger.Warnf("skipping event %s (%s): unexpected JSON input", event.ID, event.Type)
+		return nil
+	}
+
+	reportDTO, err := h.eventService.Handle(ctx, eventDTO)
+	if err != nil {
+		switch err.Error() {
+		case "invalid event type", "invalid entity type", "invalid entity ID":
+			h.logger.Debugf("skipping event %s (%s): %s", event.ID, event.Type, err.Error())
+			return nil
+		default:
+			return err
+		}
+	}
+
+	if len(reportDTO.Failed) > 0 {
+		h.logger.Warnf("event %s (%s): unable to reindex recipes %v", event.ID, event.Type, reportDTO.Failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	m "search-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type EventServiceMock struct {
+	event m.ChangeEventDTO
+}
+
+func (s *EventServiceMock) Handle(ctx context.Context, eventDTO m.ChangeEventDTO) (m.IndexReportDTO, error) {
+	s.event = eventDTO
+
+	switch mode {
+	case "event":
+		return indexReport, nil
+	case "invalid":
+		return m.IndexReportDTO{}, errors.New("invalid entity type")
+	default:
+		return m.IndexReportDTO{}, errors.New("internal server error")
+	}
+}
+
+func newEventContext(body string) (*gin.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest("POST", "http://example.com/api/v2/search/events", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	return c, w
+}
+
+// ====== Tests ======
+
+func TestHandleEvent_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := &EventServiceMock{}
+	h := NewEventHandlers(service, &m.LoggerInterfaceMock{})
+	tagID := uuid.New()
+
+	mode = "event"
+
+	c, w := newEventContext(`{"type":"updated","entity_type":"tag","entity_id":"` + tagID.String() + `","occurred_at":"2023-02-04T18:00:00Z"}`)
+
+	h.Handle(c)
+
+	expectedBody, _ := json.Marshal(indexReport)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+	assert.Equal(t, m.EventUpdated, service.event.Type)
+	assert.Equal(t, m.EntityTag, service.event.EntityType)
+	assert.Equal(t, tagID, service.event.EntityID)
+}
+
+func TestHandleEvent_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewEventHandlers(&EventServiceMock{}, &m.LoggerInterfaceMock{})
+	event := `{"type":"updated","entity_type":"unit","entity_id":"` + uuid.New().String() + `"}`
+
+	for _, test := range []struct {
+		mode   string
+		body   string
+		status int
+		error  string
+	}{
+		{"event", `{"type":"updated"}`, http.StatusBadRequest, `{"error":"unexpected JSON input"}`},
+		{"event", `{"type":"updated","entity_type":"tag","entity_id":"invalid"}`, http.StatusBadRequest, `{"error":"unexpected JSON input"}`},
+		{"invalid", event, http.StatusBadRequest, `{"error":"invalid entity type"}`},
+		{"error", event, http.StatusInternalServerError, `{"error":"internal server error"}`},
+	} {
+		mode = test.mode
+
+		c, w := newEventContext(test.body)
+
+		h.Handle(c)
+
+		assert.Equal(t, test.status, w.Code, test.body)
+		assert.Equal(t, test.error, w.Body.String(), test.body)
+	}
+}
+
+func TestConsumeEvent_OK(t *testing.T) {
+	service := &EventServiceMock{}
+	h := NewEventHandlers(service, &m.LoggerInterfaceMock{})
+	tagID := uuid.New()
+
+	mode = "event"
+
+	err := h.Consume(context.Background(), m.CloudEventDTO{
+		ID:   uuid.New().String(),
+		Type: "cookbook.tag.updated",
+		Data: json.RawMessage(`{"type":"updated","entity_type":"tag","entity_id":"` + tagID.String() + `","occurred_at":"2023-02-04T18:00:00Z"}`),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.EventUpdated, service.event.Type)
+	assert.Equal(t, m.EntityTag, service.event.EntityType)
+	assert.Equal(t, tagID, service.event.EntityID)
+}
+
+func TestConsumeEvent_Skipped(t *testing.T) {
+	h := NewEventHandlers(&EventServiceMock{}, &m.LoggerInterfaceMock{})
+
+	for _, test := range []struct {
+		mode string
+		data string
+	}{
+		{"event", `{"type":"updated","entity_type":"tag","entity_id":"invalid"}`},
+		{"invalid", `{"type":"created","entity_type":"image","entity_id":"` + uuid.New().String() + `"}`},
+	} {
+		mode = test.mode
+
+		err := h.Consume(context.Background(), m.CloudEventDTO{ID: uuid.New().String(), Data: json.RawMessage(test.data)})
+
+		assert.NoError(t, err, test.data)
+	}
+}
+
+func TestConsumeEvent_Err(t *testing.T) {
+	h := NewEventHandlers(&EventServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "error"
+
+	err := h.Consume(context.Background(), m.CloudEventDTO{
+		ID:   uuid.New().String(),
+		Data: json.RawMessage(`{"type":"deleted","entity_type":"recipe","entity_id":"` + uuid.New().String() + `"}`),
+	})
+
+	assert.EqualError(t, err, "internal server error")
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	m "search-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type IndexService interface {
+	Index(ctx context.Context, recipeID uuid.UUID) (m.RecipeDocumentDTO, error)
+	Remove(recipeID uuid.UUID) error
+	IndexAll(ctx context.Context) (m.IndexReportDTO, error)
+	StartReindex() (m.ReindexStatusDTO, error)
+	ReindexStatus() m.ReindexStatusDTO
+}
+
+type IndexHandlers struct {
+	indexService IndexService
+	logger       m.LoggerInterface
+}
+
+func NewIndexHandlers(indexService IndexService, logger m.LoggerInterface) *IndexHandlers {
+	return &IndexHandlers{
+		indexService: indexService,
+		logger:       logger,
+	}
+}
+
+// Index (re)indexes a single recipe
+func (h IndexHandlers) Index(ctx *gin.Context) {
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	documentDTO, err := h.indexService.Index(ctx.Request.Context(), recipeID)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "recipe not found"})
+			return
+		case "recipe service unavailable", "recipe incomplete":
+			ctx.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, documentDTO)
+}
+
+// Remove takes a recipe out of the index
+func (h IndexHandlers) Remove(ctx *gin.Context) {
+	recipeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe ID"})
+		return
+	}
+
+	if err := h.indexService.Remove(recipeID); err != nil {
+		switch err.Error() {
+		case "not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "recipe not indexed"})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// IndexAll indexes all recipes and reports the ones that failed
+func (h IndexHandlers) IndexAll(ctx *gin.Context) {
+	reportDTO, err := h.indexService.IndexAll(ctx.Request.Context())
+	if err != nil {
+		switch err.Error() {
+		case "recipe service unavailable":
+			ctx.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, reportDTO)
+}
+
+// Reindex starts rebuilding the index from scratch. Searches keep using the current index until the new one replaces it.
+func (h IndexHandlers) Reindex(ctx *gin.Context) {
+	statusDTO, err := h.indexService.StartReindex()
+	if err != nil {
+		switch err.Error() {
+		case "reindex already running":
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error(), "status": statusDTO})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusAccepted, statusDTO)
+}
+
+// ReindexStatus reports the progress of the running or last rebuild of the index
+func (h IndexHandlers) ReindexStatus(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, h.indexService.ReindexStatus())
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	m "search-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type IndexServiceMock struct{}
+
+var (
+	document m.RecipeDocumentDTO = m.RecipeDocumentDTO{
+		RecipeID:    recipeID,
+		Name:        "Creamy chicken pasta",
+		Ingredients: []string{"chicken", "penne"},
+	}
+
+	indexReport m.IndexReportDTO = m.IndexReportDTO{
+		Indexed: 3,
+		Failed:  []uuid.UUID{uuid.New()},
+	}
+
+	reindexStatus m.ReindexStatusDTO = m.ReindexStatusDTO{
+		State:   m.ReindexRunning,
+		Total:   10,
+		Indexed: 4,
+		Failed:  []uuid.UUID{},
+	}
+)
+
+func (s *IndexServiceMock) Index(ctx context.Context, id uuid.UUID) (m.RecipeDocumentDTO, error) {
+	switch mode {
+	case "index":
+		return document, nil
+	case "notfound":
+		return m.RecipeDocumentDTO{}, errors.New("not found")
+	case "unavailable":
+		return m.RecipeDocumentDTO{}, errors.New("recipe service unavailable")
+	case "incomplete":
+		return m.RecipeDocumentDTO{}, errors.New("recipe incomplete")
+	default:
+		return m.RecipeDocumentDTO{}, errors.New("internal server error")
+	}
+}
+
+func (s *IndexServiceMock) Remove(id uuid.UUID) error {
+	switch mode {
+	case "remove":
+		return nil
+	case "notfound":
+		return errors.New("not found")
+	default:
+		return errors.New("internal server error")
+	}
+}
+
+func (s *IndexServiceMock) IndexAll(ctx context.Context) (m.IndexReportDTO, error) {
+	switch mode {
+	case "indexall":
+		return indexReport, nil
+	case "unavailable":
+		return m.IndexReportDTO{}, errors.New("recipe service unavailable")
+	default:
+		return m.IndexReportDTO{}, errors.New("internal server error")
+	}
+}
+
+func (s *IndexServiceMock) StartReindex() (m.ReindexStatusDTO, error) {
+	switch mode {
+	case "reindex":
+		return reindexStatus, nil
+	case "running":
+		return reindexStatus, errors.New("reindex already running")
+	default:
+		return m.ReindexStatusDTO{}, errors.New("internal server error")
+	}
+}
+
+func (s *IndexServiceMock) ReindexStatus() m.ReindexStatusDTO {
+	return reindexStatus
+}
+
+func newIndexContext(method string, id string) (*gin.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, "http://example.com/api/v2/search/recipes/"+id, nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = []gin.Param{{Key: "id", Value: id}}
+
+	return c, w
+}
+
+// ====== Tests ======
+
+func TestIndex_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIndexHandlers(&IndexServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "index"
+
+	c, w := newIndexContext("PUT", recipeID.String())
+
+	h.Index(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(document)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestIndex_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIndexHandlers(&IndexServiceMock{}, &m.LoggerInterfaceMock{})
+
+	for _, test := range []struct {
+		mode   string
+		id     string
+		status int
+		body   string
+	}{
+		{"index", "invalid", http.StatusBadRequest, `{"error":"invalid recipe ID"}`},
+		{"notfound", recipeID.String(), http.StatusNotFound, `{"error":"recipe not found"}`},
+		{"unavailable", recipeID.String(), http.StatusBadGateway, `{"error":"recipe service unavailable"}`},
+		{"incomplete", recipeID.String(), http.StatusBadGateway, `{"error":"recipe incomplete"}`},
+		{"error", recipeID.String(), http.StatusInternalServerError, `{"error":"internal server error"}`},
+	} {
+		mode = test.mode
+
+		c, w := newIndexContext("PUT", test.id)
+
+		h.Index(c)
+
+		resp := w.Result()
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, test.status, resp.StatusCode, test.mode)
+		assert.Equal(t, test.body, string(body), test.mode)
+	}
+}
+
+func TestRemove_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIndexHandlers(&IndexServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "remove"
+
+	c, w := newIndexContext("DELETE", recipeID.String())
+
+	h.Remove(c)
+	c.Writer.WriteHeaderNow()
+
+	assert.Equal(t, http.StatusNoContent, w.Result().StatusCode)
+}
+
+func TestRemove_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIndexHandlers(&IndexServiceMock{}, &m.LoggerInterfaceMock{})
+
+	for _, test := range []struct {
+		mode   string
+		id     string
+		status int
+		body   string
+	}{
+		{"remove", "invalid", http.StatusBadRequest, `{"error":"invalid recipe ID"}`},
+		{"notfound", recipeID.String(), http.StatusNotFound, `{"error":"recipe not indexed"}`},
+		{"error", recipeID.String(), http.StatusInternalServerError, `{"error":"internal server error"}`},
+	} {
+		mode = test.mode
+
+		c, w := newIndexContext("DELETE", test.id)
+
+		h.Remove(c)
+
+		resp := w.Result()
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, test.status, resp.StatusCode, test.mode)
+		assert.Equal(t, test.body, string(body), test.mode)
+	}
+}
+
+func TestIndexAll_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIndexHandlers(&IndexServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "indexall"
+
+	c, w := newIndexContext("POST", "")
+
+	h.IndexAll(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(indexReport)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestIndexAll_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIndexHandlers(&IndexServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "unavailable"
+
+	c, w := newIndexContext("POST", "")
+
+	h.IndexAll(c)
+
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Equal(t, `{"error":"recipe service unavailable"}`, w.Body.String())
+
+	mode = "error"
+
+	c, w = newIndexContext("POST", "")
+
+	h.IndexAll(c)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestReindex_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIndexHandlers(&IndexServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "reindex"
+
+	c, w := newIndexContext("POST", "")
+
+	h.Reindex(c)
+
+	expectedBody, _ := json.Marshal(reindexStatus)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
+
+func TestReindex_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIndexHandlers(&IndexServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "running"
+
+	c, w := newIndexContext("POST", "")
+
+	h.Reindex(c)
+
+	expectedStatus, _ := json.Marshal(reindexStatus)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, `{"error":"reindex already running","status":`+string(expectedStatus)+`}`, w.Body.String())
+
+	mode = "error"
+
+	c, w = newIndexContext("POST", "")
+
+	h.Reindex(c)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestReindexStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewIndexHandlers(&IndexServiceMock{}, &m.LoggerInterfaceMock{})
+
+	c, w := newIndexContext("GET", "")
+
+	h.ReindexStatus(c)
+
+	expectedBody, _ := json.Marshal(reindexStatus)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, string(expectedBody), w.Body.String())
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	m "search-service/internal/models"
+	"search-service/internal/query"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tbaehler/gin-keycloak/pkg/ginkeycloak"
+)
+
+type SavedSearchService interface {
+	FindBySubject(subject string) ([]m.SavedSearchDTO, error)
+	FindSingle(subject string, id uuid.UUID) (m.SavedSearchDTO, error)
+	Create(subject string, savedSearchDTO m.SavedSearchDTO) (m.SavedSearchDTO, error)
+	Update(subject string, id uuid.UUID, savedSearchDTO m.SavedSearchDTO) (m.SavedSearchDTO, error)
+	Delete(subject string, id uuid.UUID) error
+	Feed(subject string, requestDTO m.FeedRequestDTO) (m.FeedDTO, error)
+}
+
+type SavedSearchHandlers struct {
+	savedSearchService SavedSearchService
+	logger             m.LoggerInterface
+}
+
+func NewSavedSearchHandlers(savedSearchService SavedSearchService, logger m.LoggerInterface) *SavedSearchHandlers {
+	return &SavedSearchHandlers{
+		savedSearchService: savedSearchService,
+		logger:             logger,
+	}
+}
+
+// GetAll returns the saved searches of the current user
+func (h SavedSearchHandlers) GetAll(ctx *gin.Context) {
+	subject, ok := subject(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unknown user"})
+		return
+	}
+
+	savedSearchDTOs, err := h.savedSearchService.FindBySubject(subject)
+	if err != nil {
+		h.respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, savedSearchDTOs)
+}
+
+// Get returns a single saved search of the current user
+func (h SavedSearchHandlers) Get(ctx *gin.Context) {
+	subject, ok := subject(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unknown user"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid saved search ID"})
+		return
+	}
+
+	savedSearchDTO, err := h.savedSearchService.FindSingle(subject, id)
+	if err != nil {
+		h.respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, savedSearchDTO)
+}
+
+// Create saves a search such as {"name":"quick chicken","query":"chicken time:<30"} for the current user
+func (h SavedSearchHandlers) Create(ctx *gin.Context) {
+	var savedSearchDTO m.SavedSearchDTO
+
+	subject, ok := subject(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unknown user"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&savedSearchDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unexpected JSON input"})
+		return
+	}
+
+	savedSearchDTO, err := h.savedSearchService.Create(subject, savedSearchDTO)
+	if err != nil {
+		h.respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, savedSearchDTO)
+}
+
+// Update changes the name or query of a saved search of the current user
+func (h SavedSearchHandlers) Update(ctx *gin.Context) {
+	var savedSearchDTO m.SavedSearchDTO
+
+	subject, ok := subject(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unknown user"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid saved search ID"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&savedSearchDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unexpected JSON input"})
+		return
+	}
+
+	savedSearchDTO, err = h.savedSearchService.Update(subject, id, savedSearchDTO)
+	if err != nil {
+		h.respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, savedSearchDTO)
+}
+
+// Delete removes a saved search of the current user
+func (h SavedSearchHandlers) Delete(ctx *gin.Context) {
+	subject, ok := subject(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unknown user"})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid saved search ID"})
+		return
+	}
+
+	if err := h.savedSearchService.Delete(subject, id); err != nil {
+		h.respondWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Feed returns the recipes that newly matched the saved searches of the current user, read on with ?after=<next>
+func (h SavedSearchHandlers) Feed(ctx *gin.Context) {
+	subject, ok := subject(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unknown user"})
+		return
+	}
+
+	h.feed(ctx, subject)
+}
+
+// FeedAll returns the new matches of all users, for notification channels to pick up
+func (h SavedSearchHandlers) FeedAll(ctx *gin.Context) {
+	h.feed(ctx, "")
+}
+
+func (h SavedSearchHandlers) feed(ctx *gin.Context, subject string) {
+	var requestDTO m.FeedRequestDTO
+
+	if err := ctx.ShouldBindQuery(&requestDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid query parameters"})
+		return
+	}
+
+	feedDTO, err := h.savedSearchService.Feed(subject, requestDTO)
+	if err != nil {
+		h.respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, feedDTO)
+}
+
+func (h SavedSearchHandlers) respondWithError(ctx *gin.Context, err error) {
+	var syntaxErr query.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": syntaxErr.Error(), "position": syntaxErr.Position})
+		return
+	}
+
+	switch err.Error() {
+	case "not found":
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "saved search not found"})
+	case "name already in use":
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case "name must not be empty", "name too long", "query must not be empty", "query too long", "too many saved searches", "invalid limit":
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// subject returns the keycloak subject of the authenticated user, which the access check stores on the context
+func subject(ctx *gin.Context) (string, bool) {
+	value, found := ctx.Get("token")
+	if !found {
+		return "", false
+	}
+
+	token, ok := value.(ginkeycloak.KeyCloakToken)
+	if !ok || token.Sub == "" {
+		return "", false
+	}
+
+	return token.Sub, true
+}
@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	m "search-service/internal/models"
+	"search-service/internal/query"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/tbaehler/gin-keycloak/pkg/ginkeycloak"
+)
+
+type SavedSearchServiceMock struct {
+	subject string
+	feed    m.FeedRequestDTO
+}
+
+var (
+	userSubject   string    = "7c6a7ba4-5a4e-4b0a-9f5b-4d7f7f6b1f2a"
+	savedSearchID uuid.UUID = uuid.New()
+
+	savedSearch m.SavedSearchDTO = m.SavedSearchDTO{ID: savedSearchID, Name: "quick chicken", Query: "chicken time:<30"}
+
+	feed m.FeedDTO = m.FeedDTO{
+		Matches: []m.SavedSearchMatchDTO{{ID: 7, SavedSearchID: savedSearchID, SavedSearchName: "quick chicken", Subject: userSubject, RecipeID: recipeID, RecipeName: "Creamy chicken pasta"}},
+		Next:    7,
+	}
+)
+
+func savedSearchResult() error {
+	switch mode {
+	case "notfound":
+		return errors.New("not found")
+	case "conflict":
+		return errors.New("name already in use")
+	case "invalid":
+		return errors.New("too many saved searches")
+	case "syntax":
+		return query.SyntaxError{Position: 4, Message: "expected a value"}
+	case "error":
+		return errors.New("internal server error")
+	default:
+		return nil
+	}
+}
+
+func (s *SavedSearchServiceMock) FindBySubject(subject string) ([]m.SavedSearchDTO, error) {
+	s.subject = subject
+	if err := savedSearchResult(); err != nil {
+		return nil, err
+	}
+	return []m.SavedSearchDTO{savedSearch}, nil
+}
+
+func (s *SavedSearchServiceMock) FindSingle(subject string, id uuid.UUID) (m.SavedSearchDTO, error) {
+	if err := savedSearchResult(); err != nil {
+		return m.SavedSearchDTO{}, err
+	}
+	return savedSearch, nil
+}
+
+func (s *SavedSearchServiceMock) Create(subject string, savedSearchDTO m.SavedSearchDTO) (m.SavedSearchDTO, error) {
+	s.subject = subject
+	if err := savedSearchResult(); err != nil {
+		return m.SavedSearchDTO{}, err
+	}
+	return savedSearch, nil
+}
+
+func (s *SavedSearchServiceMock) Update(subject string, id uuid.UUID, savedSearchDTO m.SavedSearchDTO) (m.SavedSearchDTO, error) {
+	if err := savedSearchResult(); err != nil {
+		return m.SavedSearchDTO{}, err
+	}
+	return savedSearch, nil
+}
+
+func (s *SavedSearchServiceMock) Delete(subject string, id uuid.UUID) error {
+	return savedSearchResult()
+}
+
+func (s *SavedSearchServiceMock) Feed(subject string, requestDTO m.FeedRequestDTO) (m.FeedDTO, error) {
+	s.subject, s.feed = subject, requestDTO
+	if err := savedSearchResult(); err != nil {
+		return m.FeedDTO{}, err
+	}
+	return feed, nil
+}
+
+func newSavedSearchContext(method string, url string, body string, id string, authenticated bool) (*gin.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	if id != "" {
+		c.Params = []gin.Param{{Key: "id", Value: id}}
+	}
+
+	if authenticated {
+		c.Set("token", ginkeycloak.KeyCloakToken{Sub: userSubject})
+	}
+
+	return c, w
+}
+
+// ====== Tests ======
+
+func TestSavedSearchGetAll_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := &SavedSearchServiceMock{}
+	h := NewSavedSearchHandlers(service, &m.LoggerInterfaceMock{})
+
+	mode = "list"
+
+	c, w := newSavedSearchContext("GET", "http://example.com/api/v2/search/saved", "", "", true)
+
+	h.GetAll(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal([]m.SavedSearchDTO{savedSearch})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+	assert.Equal(t, userSubject, service.subject)
+}
+
+func TestSavedSearchGetAll_UnknownUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewSavedSearchHandlers(&SavedSearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "list"
+
+	c, w := newSavedSearchContext("GET", "http://example.com/api/v2/search/saved", "", "", false)
+
+	h.GetAll(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, `{"error":"unknown user"}`, string(body))
+}
+
+func TestSavedSearchGet_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewSavedSearchHandlers(&SavedSearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	for _, test := range []struct {
+		mode   string
+		id     string
+		status int
+		body   string
+	}{
+		{"get", "invalid", http.StatusBadRequest, `{"error":"invalid saved search ID"}`},
+		{"notfound", savedSearchID.String(), http.StatusNotFound, `{"error":"saved search not found"}`},
+		{"error", savedSearchID.String(), http.StatusInternalServerError, `{"error":"internal server error"}`},
+	} {
+		mode = test.mode
+
+		c, w := newSavedSearchContext("GET", "http://example.com/api/v2/search/saved/"+test.id, "", test.id, true)
+
+		h.Get(c)
+
+		resp := w.Result()
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, test.status, resp.StatusCode, test.mode)
+		assert.Equal(t, test.body, string(body), test.mode)
+	}
+}
+
+func TestSavedSearchCreate_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewSavedSearchHandlers(&SavedSearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "create"
+
+	c, w := newSavedSearchContext("POST", "http://example.com/api/v2/search/saved", `{"name":"quick chicken","query":"chicken time:<30"}`, "", true)
+
+	h.Create(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(savedSearch)
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+}
+
+func TestSavedSearchCreate_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewSavedSearchHandlers(&SavedSearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	for _, test := range []struct {
+		mode        string
+		requestBody string
+		status      int
+		body        string
+	}{
+		{"create", `{"name":"quick chicken"}`, http.StatusBadRequest, `{"error":"unexpected JSON input"}`},
+		{"conflict", `{"name":"quick chicken","query":"chicken"}`, http.StatusConflict, `{"error":"name already in use"}`},
+		{"invalid", `{"name":"quick chicken","query":"chicken"}`, http.StatusBadRequest, `{"error":"too many saved searches"}`},
+		{"syntax", `{"name":"quick chicken","query":"tag:"}`, http.StatusBadRequest, `{"error":"expected a value at position 4","position":4}`},
+		{"error", `{"name":"quick chicken","query":"chicken"}`, http.StatusInternalServerError, `{"error":"internal server error"}`},
+	} {
+		mode = test.mode
+
+		c, w := newSavedSearchContext("POST", "http://example.com/api/v2/search/saved", test.requestBody, "", true)
+
+		h.Create(c)
+
+		resp := w.Result()
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, test.status, resp.StatusCode, test.mode)
+		assert.Equal(t, test.body, string(body), test.mode)
+	}
+}
+
+func TestSavedSearchUpdate_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewSavedSearchHandlers(&SavedSearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "update"
+
+	c, w := newSavedSearchContext("PUT", "http://example.com/api/v2/search/saved/"+savedSearchID.String(), `{"name":"quick chicken","query":"chicken time:<30"}`, savedSearchID.String(), true)
+
+	h.Update(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestSavedSearchUpdate_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewSavedSearchHandlers(&SavedSearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "notfound"
+
+	c, w := newSavedSearchContext("PUT", "http://example.com/api/v2/search/saved/"+savedSearchID.String(), `{"name":"quick chicken","query":"chicken"}`, savedSearchID.String(), true)
+
+	h.Update(c)
+
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestSavedSearchDelete(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewSavedSearchHandlers(&SavedSearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	for scenario, status := range map[string]int{
+		"delete":   http.StatusNoContent,
+		"notfound": http.StatusNotFound,
+		"error":    http.StatusInternalServerError,
+	} {
+		mode = scenario
+
+		c, _ := newSavedSearchContext("DELETE", "http://example.com/api/v2/search/saved/"+savedSearchID.String(), "", savedSearchID.String(), true)
+
+		h.Delete(c)
+
+		assert.Equal(t, status, c.Writer.Status(), scenario)
+	}
+}
+
+func TestFeed_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := &SavedSearchServiceMock{}
+	h := NewSavedSearchHandlers(service, &m.LoggerInterfaceMock{})
+
+	mode = "feed"
+
+	c, w := newSavedSearchContext("GET", "http://example.com/api/v2/search/feed?after=5&limit=10", "", "", true)
+
+	h.Feed(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(feed)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+	assert.Equal(t, userSubject, service.subject)
+	assert.Equal(t, m.FeedRequestDTO{After: 5, Limit: 10}, service.feed)
+}
+
+func TestFeedAll_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := &SavedSearchServiceMock{subject: "unset"}
+	h := NewSavedSearchHandlers(service, &m.LoggerInterfaceMock{})
+
+	mode = "feed"
+
+	c, w := newSavedSearchContext("GET", "http://example.com/api/v2/search/feed/all", "", "", true)
+
+	h.FeedAll(c)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	assert.Equal(t, "", service.subject)
+}
+
+func TestFeed_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewSavedSearchHandlers(&SavedSearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "feed"
+
+	c, w := newSavedSearchContext("GET", "http://example.com/api/v2/search/feed?after=-1", "", "", true)
+
+	h.Feed(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid query parameters"}`, string(body))
+}
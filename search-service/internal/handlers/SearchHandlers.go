@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	m "search-service/internal/models"
+	"search-service/internal/query"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchService interface {
+	Search(request m.SearchRequestDTO) (m.SearchResultDTO, error)
+	Parse(text string) (m.SearchQueryDTO, error)
+	Suggest(request m.SuggestionRequestDTO) ([]m.SuggestionDTO, error)
+}
+
+type SearchHandlers struct {
+	searchService SearchService
+	logger        m.LoggerInterface
+}
+
+func NewSearchHandlers(searchService SearchService, logger m.LoggerInterface) *SearchHandlers {
+	return &SearchHandlers{
+		searchService: searchService,
+		logger:        logger,
+	}
+}
+
+// Search handles queries such as ?q=chicken tag:weeknight time:<30 -ingredient:peanut, paged with limit and offset
+func (h SearchHandlers) Search(ctx *gin.Context) {
+	var requestDTO m.SearchRequestDTO
+
+	if err := ctx.ShouldBindQuery(&requestDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid query parameters"})
+		return
+	}
+
+	resultDTO, err := h.searchService.Search(requestDTO)
+	if err != nil {
+		var syntaxErr query.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": syntaxErr.Error(), "position": syntaxErr.Position})
+			return
+		}
+
+		switch err.Error() {
+		case "query must not be empty", "query too long", "invalid limit", "invalid offset":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, resultDTO)
+}
+
+// Parse validates a query string and returns the filters it is made up of, pointing out the position of any mistake
+func (h SearchHandlers) Parse(ctx *gin.Context) {
+	queryDTO, err := h.searchService.Parse(ctx.Query("q"))
+	if err != nil {
+		var syntaxErr query.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": syntaxErr.Error(), "position": syntaxErr.Position})
+			return
+		}
+
+		switch err.Error() {
+		case "query must not be empty", "query too long":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, queryDTO)
+}
+
+// Suggest autocompletes names as they are typed, such as ?q=zuch&types=ingredient,tag
+func (h SearchHandlers) Suggest(ctx *gin.Context) {
+	var requestDTO m.SuggestionRequestDTO
+
+	if err := ctx.ShouldBindQuery(&requestDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid query parameters"})
+		return
+	}
+
+	suggestionDTOs, err := h.searchService.Suggest(requestDTO)
+	if err != nil {
+		switch err.Error() {
+		case "query must not be empty", "query too long", "invalid limit", "invalid type":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, suggestionDTOs)
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	m "search-service/internal/models"
+	"search-service/internal/query"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type SearchServiceMock struct {
+	request    m.SearchRequestDTO
+	suggestion m.SuggestionRequestDTO
+}
+
+var (
+	recipeID uuid.UUID = uuid.New()
+
+	searchResult m.SearchResultDTO = m.SearchResultDTO{
+		Query: "creamy chicken pasta",
+		Total: 1,
+		Limit: 20,
+		Results: []m.SearchHitDTO{{
+			RecipeDocumentDTO: m.RecipeDocumentDTO{RecipeID: recipeID, Name: "Creamy chicken pasta"},
+			Rank:              0.6,
+		}},
+	}
+
+	suggestions []m.SuggestionDTO = []m.SuggestionDTO{
+		{Type: "ingredient", EntityID: uuid.New(), Name: "zucchini", Recipes: 4, Score: 0.875},
+	}
+
+	mode string
+)
+
+func (s *SearchServiceMock) Search(request m.SearchRequestDTO) (m.SearchResultDTO, error) {
+	s.request = request
+
+	switch mode {
+	case "search":
+		return searchResult, nil
+	case "invalid":
+		return m.SearchResultDTO{}, errors.New("query must not be empty")
+	case "syntax":
+		return m.SearchResultDTO{}, query.SyntaxError{Position: 9, Message: `unknown field "colour"`}
+	default:
+		return m.SearchResultDTO{}, errors.New("internal server error")
+	}
+}
+
+func (s *SearchServiceMock) Parse(text string) (m.SearchQueryDTO, error) {
+	switch mode {
+	case "parse":
+		return m.SearchQueryDTO{Terms: []string{text}}, nil
+	case "invalid":
+		return m.SearchQueryDTO{}, errors.New("query must not be empty")
+	case "syntax":
+		return m.SearchQueryDTO{}, query.SyntaxError{Position: 1, Message: "unterminated quote"}
+	default:
+		return m.SearchQueryDTO{}, errors.New("internal server error")
+	}
+}
+
+func (s *SearchServiceMock) Suggest(request m.SuggestionRequestDTO) ([]m.SuggestionDTO, error) {
+	s.suggestion = request
+
+	switch mode {
+	case "suggest":
+		return suggestions, nil
+	case "invalid":
+		return nil, errors.New("invalid type")
+	default:
+		return nil, errors.New("internal server error")
+	}
+}
+
+// ====== Tests ======
+
+func TestSearch_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := &SearchServiceMock{}
+	h := NewSearchHandlers(service, &m.LoggerInterfaceMock{})
+
+	mode = "search"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/search/recipes?q=creamy+chicken+pasta&limit=10&offset=5", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Search(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(searchResult)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+	assert.Equal(t, m.SearchRequestDTO{Query: "creamy chicken pasta", Limit: 10, Offset: 5}, service.request)
+}
+
+func TestSearch_BindErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewSearchHandlers(&SearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "search"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/search/recipes?q=pasta&limit=ten", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Search(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid query parameters"}`, string(body))
+}
+
+func TestSearch_ValidationErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewSearchHandlers(&SearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "invalid"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/search/recipes", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Search(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"query must not be empty"}`, string(body))
+}
+
+func TestSearch_SyntaxErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewSearchHandlers(&SearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "syntax"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/search/recipes?q=chicken+colour:red", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Search(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"unknown field \"colour\" at position 9","position":9}`, string(body))
+}
+
+func TestSearch_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewSearchHandlers(&SearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "error"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/search/recipes?q=pasta", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Search(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, `{"error":"internal server error"}`, string(body))
+}
+
+func TestParse_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewSearchHandlers(&SearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "parse"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/search/parse?q=chicken", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Parse(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"terms":["chicken"]}`, string(body))
+}
+
+func TestParse_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewSearchHandlers(&SearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	for _, test := range []struct {
+		mode   string
+		status int
+		body   string
+	}{
+		{"syntax", http.StatusBadRequest, `{"error":"unterminated quote at position 1","position":1}`},
+		{"invalid", http.StatusBadRequest, `{"error":"query must not be empty"}`},
+		{"error", http.StatusInternalServerError, `{"error":"internal server error"}`},
+	} {
+		mode = test.mode
+
+		req := httptest.NewRequest("GET", "http://example.com/api/v2/search/parse?q=%22green", nil)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+
+		h.Parse(c)
+
+		resp := w.Result()
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, test.status, resp.StatusCode, test.mode)
+		assert.Equal(t, test.body, string(body), test.mode)
+	}
+}
+
+func TestSuggest_OK(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := &SearchServiceMock{}
+	h := NewSearchHandlers(service, &m.LoggerInterfaceMock{})
+
+	mode = "suggest"
+
+	req := httptest.NewRequest("GET", "http://example.com/api/v2/search/suggest?q=zuch&types=ingredient,tag&limit=5", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	h.Suggest(c)
+
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	expectedBody, _ := json.Marshal(suggestions)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, body)
+	assert.Equal(t, m.SuggestionRequestDTO{Query: "zuch", Types: "ingredient,tag", Limit: 5}, service.suggestion)
+}
+
+func TestSuggest_Err(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewSearchHandlers(&SearchServiceMock{}, &m.LoggerInterfaceMock{})
+
+	for _, test := range []struct {
+		mode   string
+		url    string
+		status int
+		body   string
+	}{
+		{"suggest", "/api/v2/search/suggest?q=zuch&limit=five", http.StatusBadRequest, `{"error":"invalid query parameters"}`},
+		{"invalid", "/api/v2/search/suggest?q=zuch&types=unit", http.StatusBadRequest, `{"error":"invalid type"}`},
+		{"error", "/api/v2/search/suggest?q=zuch", http.StatusInternalServerError, `{"error":"internal server error"}`},
+	} {
+		mode = test.mode
+
+		req := httptest.NewRequest("GET", "http://example.com"+test.url, nil)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+
+		h.Suggest(c)
+
+		resp := w.Result()
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, test.status, resp.StatusCode, test.mode)
+		assert.Equal(t, test.body, string(body), test.mode)
+	}
+}
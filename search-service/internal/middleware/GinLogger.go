@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Taken from gin-logrus github.com/toorop/gin-logrus, customized to align more with the app's logging output
+func Logger(logger logrus.FieldLogger, notLogged ...string) gin.HandlerFunc {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknow"
+	}
+
+	var skip map[string]struct{}
+
+	if length := len(notLogged); length > 0 {
+		skip = make(map[string]struct{}, length)
+
+		for _, p := range notLogged {
+			skip[p] = struct{}{}
+		}
+	}
+
+	return func(c *gin.Context) {
+		// other handler can change c.Path so:
+		path := c.Request.URL.Path
+		start := time.Now()
+		c.Next()
+		stop := time.Since(start)
+		latency := int(math.Ceil(float64(stop.Nanoseconds()) / 1000000.0))
+		statusCode := c.Writer.Status()
+		clientIP := c.ClientIP()
+		clientUserAgent := c.Request.UserAgent()
+		referer := c.Request.Referer()
+		dataLength := c.Writer.Size()
+		if dataLength < 0 {
+			dataLength = 0
+		}
+
+		if _, ok := skip[path]; ok {
+			return
+		}
+
+		entry := logger.WithFields(logrus.Fields{
+			"method":     c.Request.Method,
+			"path":       path,
+			"statusCode": statusCode,
+			"clientIP":   clientIP,
+			"hostname":   hostname,
+			"userAgent":  clientUserAgent,
+			"latency":    latency,
+			"referer":    referer,
+			"dataLength": dataLength,
+		})
+
+		if len(c.Errors) > 0 {
+			entry.Error(c.Errors.ByType(gin.ErrorTypePrivate).String())
+		} else {
+			if statusCode >= http.StatusInternalServerError {
+				entry.Error()
+			} else if statusCode >= http.StatusBadRequest {
+				entry.Warn()
+			} else {
+				entry.Info()
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package models
+
+type Config struct {
+	Global   GlobalConfig
+	Cors     CorsConfig
+	Oauth    OauthConfig
+	Database DatabaseConfig
+	Services ServicesConfig
+	Search   SearchConfig
+	Events   EventsConfig
+}
+
+// GlobalConfig holds global configuration items
+type GlobalConfig struct {
+	LogLevel string
+}
+
+// DatabaseConfig holds database configuration items
+type DatabaseConfig struct {
+	Host     string
+	Username string
+	Password string
+	Database string
+	Port     int
+	SSLMode  string
+	Timezone string
+}
+
+// ServicesConfig holds the location of the recipe service and the client credentials used to read recipes from it
+type ServicesConfig struct {
+	RecipeServiceUrl string
+	Timeout          int // in seconds
+	ClientID         string
+	ClientSecret     string
+}
+
+// SearchConfig holds the settings of the full-text index
+type SearchConfig struct {
+	Language string // PostgreSQL text search configuration used for stemming and stop words, e.g. english or dutch
+}
+
+// EventsConfig holds the broker the changes of the other services are consumed from. Without a broker the changes
+// are only received on the events endpoint
+type EventsConfig struct {
+	Broker  string // nats or empty
+	NatsUrl string // e.g. nats://nats:4222
+}
+
+type OauthConfig struct {
+	Service              string
+	Url                  string
+	Realm                string
+	FullCertsPath        *string
+	DisableSecurityCheck bool
+}
+
+type CorsConfig struct {
+	AllowedOrigins   []string
+	AllowCredentials bool
+	AllowedHeaders   []string
+	AllowedMethods   []string
+}
+
+type LoggerInterface interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The kinds of changes the other services report
+const (
+	EventCreated = "created"
+	EventUpdated = "updated"
+	EventDeleted = "deleted"
+)
+
+// The entities the other services report changes on. The ingredients, instructions and metadata of a recipe
+// are reported as a whole, by the ID of the recipe.
+const (
+	EntityRecipe             = "recipe"
+	EntityRecipeIngredients  = "recipe_ingredients"
+	EntityRecipeInstructions = "recipe_instructions"
+	EntityRecipeMetadata     = "recipe_metadata"
+
+	EntityIngredient      = "ingredient"
+	EntityTag             = "tag"
+	EntityCategory        = "category"
+	EntityCuisineType     = "cuisine_type"
+	EntityDifficultyLevel = "difficulty_level"
+	EntityPreparationTime = "preparation_time"
+)
+
+// ChangeEventDTO is sent by a service after it created, updated or deleted one of its entities
+type ChangeEventDTO struct {
+	Type       string    `json:"type" binding:"required"`
+	EntityType string    `json:"entity_type" binding:"required"`
+	EntityID   uuid.UUID `json:"entity_id" binding:"required"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// CloudEventDTO is a change event as the other services publish it to the event broker, in the CloudEvents 1.0
+// JSON format. The data holds the ChangeEventDTO.
+type CloudEventDTO struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
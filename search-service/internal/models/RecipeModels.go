@@ -0,0 +1,64 @@
+package models
+
+import "github.com/google/uuid"
+
+// The models below mirror the DTOs as returned by the recipe service
+
+type RecipeDTO struct {
+	ID           uuid.UUID
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	ServingCount int    `json:"servingcount"`
+}
+
+type FullRecipeDTO struct {
+	Recipe       RecipeDTO             `json:"recipe"`
+	Ingredients  []RecipeIngredientDTO `json:"ingredients"`
+	Instructions []InstructionDTO      `json:"instructions"`
+	Metadata     *RecipeMetadataDTO    `json:"metadata"`
+	Errors       map[string]string     `json:"errors,omitempty"`
+}
+
+type RecipeIngredientDTO struct {
+	IngredientID   uuid.UUID `json:"IngredientID"`
+	IngredientName string    `json:"IngredientName"`
+}
+
+type InstructionDTO struct {
+	Sequence    int    `json:"sequence"`
+	Description string `json:"description"`
+}
+
+type CategoryDTO struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type TagDTO struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type CuisineTypeDTO struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type DifficultyLevelDTO struct {
+	ID    uuid.UUID `json:"id"`
+	Level int       `json:"name"`
+}
+
+type PreparationTimeDTO struct {
+	ID       uuid.UUID `json:"id"`
+	Duration int       `json:"name"`
+}
+
+type RecipeMetadataDTO struct {
+	RecipeID        uuid.UUID          `json:"recipe_id"`
+	Categories      []CategoryDTO      `json:"categories"`
+	Tags            []TagDTO           `json:"tags"`
+	CuisineType     CuisineTypeDTO     `json:"cuisine_type"`
+	DifficultyLevel DifficultyLevelDTO `json:"difficulty_level"`
+	PreparationTime PreparationTimeDTO `json:"preparation_time"`
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SavedSearch is a query string a user stored under a name to be told about new recipes matching it.
+// Users are identified by the subject of their token.
+type SavedSearch struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Subject   string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_saved_searches_subject_name"`
+	Name      string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_saved_searches_subject_name"`
+	Query     string    `gorm:"type:varchar(256);not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+	Matches []SavedSearchMatch `gorm:"foreignKey:SavedSearchID;constraint:OnDelete:CASCADE"`
+}
+
+func (s SavedSearch) ConvertToDTO() SavedSearchDTO {
+	return SavedSearchDTO{
+		ID:        s.ID,
+		Name:      s.Name,
+		Query:     s.Query,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func (s SavedSearch) ConvertAllToDTO(savedSearches []SavedSearch) []SavedSearchDTO {
+	data := []SavedSearchDTO{}
+
+	for _, savedSearch := range savedSearches {
+		data = append(data, savedSearch.ConvertToDTO())
+	}
+
+	return data
+}
+
+type SavedSearchDTO struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name" binding:"required" example:"quick chicken"`
+	Query     string    `json:"query" binding:"required" example:"chicken time:<30 -ingredient:peanut"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SavedSearchMatch records that a recipe matched a saved search. Matches are numbered in the order they are found,
+// so the feed can be read from the last number seen onwards. A recipe matches a saved search only once.
+type SavedSearchMatch struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement"`
+	SavedSearchID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_saved_search_matches_recipe"`
+	Subject       string    `gorm:"type:varchar(64);not null;index"`
+	RecipeID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_saved_search_matches_recipe"`
+	RecipeName    string
+	Baseline      bool      `gorm:"not null;default:false"` // already matched when the search was saved, so not reported in the feed
+	MatchedAt     time.Time `gorm:"autoCreateTime"`
+
+	SavedSearchName string `gorm:"->;-:migration"` // only read along with the feed
+}
+
+func (s SavedSearchMatch) ConvertToDTO() SavedSearchMatchDTO {
+	return SavedSearchMatchDTO{
+		ID:              s.ID,
+		SavedSearchID:   s.SavedSearchID,
+		SavedSearchName: s.SavedSearchName,
+		Subject:         s.Subject,
+		RecipeID:        s.RecipeID,
+		RecipeName:      s.RecipeName,
+		MatchedAt:       s.MatchedAt,
+	}
+}
+
+type SavedSearchMatchDTO struct {
+	ID              uint64    `json:"id"`
+	SavedSearchID   uuid.UUID `json:"saved_search_id"`
+	SavedSearchName string    `json:"saved_search_name"`
+	Subject         string    `json:"subject"`
+	RecipeID        uuid.UUID `json:"recipe_id"`
+	RecipeName      string    `json:"recipe_name"`
+	MatchedAt       time.Time `json:"matched_at"`
+}
+
+// FeedRequest asks for the matches found after a given one, for a single user or, without a subject, for all users
+type FeedRequest struct {
+	Subject string
+	After   uint64
+	Limit   int
+}
+
+type FeedRequestDTO struct {
+	After uint64 `form:"after"`
+	Limit int    `form:"limit"`
+}
+
+// FeedDTO is a page of the feed. Next is the number to continue after, it equals the requested one when nothing new was found.
+type FeedDTO struct {
+	Matches []SavedSearchMatchDTO `json:"matches"`
+	Next    uint64                `json:"next"`
+}
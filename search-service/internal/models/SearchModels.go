@@ -0,0 +1,278 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Terms is a list of names that is stored as a JSON array
+type Terms []string
+
+func (t Terms) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+func (t *Terms) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, t)
+	case string:
+		return json.Unmarshal([]byte(v), t)
+	default:
+		return errors.New("unsupported type for terms")
+	}
+}
+
+// RecipeDocument is the denormalized view of a recipe that is searched on.
+// The Document column holds the weighted tsvector and is only ever written through SQL, see SearchRepository.Save.
+type RecipeDocument struct {
+	RecipeID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Name         string    `gorm:"not null"`
+	Description  string    `gorm:"type:text"`
+	Ingredients  Terms     `gorm:"type:jsonb"`
+	Instructions Terms     `gorm:"type:jsonb"`
+	Tags         Terms     `gorm:"type:jsonb"`
+	Categories   Terms     `gorm:"type:jsonb"`
+	CuisineType  string
+	Difficulty   *int      // level of 1 to 5, unknown when nil
+	PrepTime     *int      // in minutes, unknown when nil
+	Document     string    `gorm:"type:tsvector;index:,type:gin;->:false;<-:false"`
+	IndexedAt    time.Time `gorm:"autoUpdateTime"`
+
+	Terms []RecipeTerm `gorm:"foreignKey:RecipeID;constraint:OnDelete:CASCADE"`
+}
+
+// NewRecipeDocument flattens a full recipe into a document
+func NewRecipeDocument(recipe FullRecipeDTO) RecipeDocument {
+	document := RecipeDocument{
+		RecipeID:     recipe.Recipe.ID,
+		Name:         recipe.Recipe.Name,
+		Description:  recipe.Recipe.Description,
+		Ingredients:  Terms{},
+		Instructions: Terms{},
+		Tags:         Terms{},
+		Categories:   Terms{},
+	}
+
+	term := func(termType string, id uuid.UUID, name string) {
+		if id == uuid.Nil || strings.TrimSpace(name) == "" {
+			return
+		}
+
+		// an ingredient can occur more than once in a recipe, it is only a single term though
+		for _, existing := range document.Terms {
+			if existing.Type == termType && existing.EntityID == id {
+				return
+			}
+		}
+
+		document.Terms = append(document.Terms, RecipeTerm{RecipeID: recipe.Recipe.ID, Type: termType, EntityID: id, Name: name})
+	}
+
+	term(TermRecipe, recipe.Recipe.ID, recipe.Recipe.Name)
+
+	for _, ingredient := range recipe.Ingredients {
+		document.Ingredients = append(document.Ingredients, ingredient.IngredientName)
+		term(TermIngredient, ingredient.IngredientID, ingredient.IngredientName)
+	}
+
+	for _, instruction := range recipe.Instructions {
+		document.Instructions = append(document.Instructions, instruction.Description)
+	}
+
+	if recipe.Metadata != nil {
+		for _, tag := range recipe.Metadata.Tags {
+			document.Tags = append(document.Tags, tag.Name)
+			term(TermTag, tag.ID, tag.Name)
+		}
+
+		for _, category := range recipe.Metadata.Categories {
+			document.Categories = append(document.Categories, category.Name)
+			term(TermCategory, category.ID, category.Name)
+		}
+
+		document.CuisineType = recipe.Metadata.CuisineType.Name
+		term(TermCuisineType, recipe.Metadata.CuisineType.ID, recipe.Metadata.CuisineType.Name)
+
+		if level := recipe.Metadata.DifficultyLevel.Level; level > 0 {
+			document.Difficulty = &level
+			term(TermDifficultyLevel, recipe.Metadata.DifficultyLevel.ID, strconv.Itoa(level))
+		}
+
+		if duration := recipe.Metadata.PreparationTime.Duration; duration > 0 {
+			document.PrepTime = &duration
+			term(TermPreparationTime, recipe.Metadata.PreparationTime.ID, strconv.Itoa(duration))
+		}
+	}
+
+	return document
+}
+
+// Weighted returns the text of the document grouped by weight, from most (A) to least (D) relevant
+func (d RecipeDocument) Weighted() [4]string {
+	return [4]string{
+		d.Name,
+		strings.Join(append(append(append(Terms{}, d.Tags...), d.Categories...), d.CuisineType), " "),
+		strings.Join(append(Terms{d.Description}, d.Ingredients...), " "),
+		strings.Join(d.Instructions, " "),
+	}
+}
+
+func (d RecipeDocument) ConvertToDTO() RecipeDocumentDTO {
+	return RecipeDocumentDTO{
+		RecipeID:    d.RecipeID,
+		Name:        d.Name,
+		Description: d.Description,
+		Ingredients: d.Ingredients,
+		Tags:        d.Tags,
+		Categories:  d.Categories,
+		CuisineType: d.CuisineType,
+		Difficulty:  d.Difficulty,
+		PrepTime:    d.PrepTime,
+		IndexedAt:   d.IndexedAt,
+	}
+}
+
+type RecipeDocumentDTO struct {
+	RecipeID    uuid.UUID `json:"recipe_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Ingredients []string  `json:"ingredients"`
+	Tags        []string  `json:"tags"`
+	Categories  []string  `json:"categories"`
+	CuisineType string    `json:"cuisine_type"`
+	Difficulty  *int      `json:"difficulty"`
+	PrepTime    *int      `json:"prep_time"`
+	IndexedAt   time.Time `json:"indexed_at"`
+}
+
+// SearchQuery is the structured form of a query string such as `chicken tag:weeknight time:<30 -ingredient:peanut`.
+// Words and phrases are matched on the full text, the filters on the names of the tags, categories, cuisine and ingredients.
+// All of them have to match, except for the cuisines of which a recipe only has one.
+type SearchQuery struct {
+	Terms    []string
+	Excluded []string
+
+	Tags        []string
+	Categories  []string
+	Cuisines    []string
+	Ingredients []string
+
+	ExcludedTags        []string
+	ExcludedCategories  []string
+	ExcludedCuisines    []string
+	ExcludedIngredients []string
+
+	MinDifficulty *int
+	MaxDifficulty *int
+	MinPrepTime   *int // in minutes
+	MaxPrepTime   *int // in minutes
+}
+
+// HasText reports whether the query contains words or phrases to match on the full text
+func (q SearchQuery) HasText() bool {
+	return len(q.Terms) > 0 || len(q.Excluded) > 0
+}
+
+func (q SearchQuery) ConvertToDTO() SearchQueryDTO {
+	return SearchQueryDTO(q)
+}
+
+type SearchQueryDTO struct {
+	Terms    []string `json:"terms,omitempty"`
+	Excluded []string `json:"excluded,omitempty"`
+
+	Tags        []string `json:"tags,omitempty"`
+	Categories  []string `json:"categories,omitempty"`
+	Cuisines    []string `json:"cuisines,omitempty"`
+	Ingredients []string `json:"ingredients,omitempty"`
+
+	ExcludedTags        []string `json:"excluded_tags,omitempty"`
+	ExcludedCategories  []string `json:"excluded_categories,omitempty"`
+	ExcludedCuisines    []string `json:"excluded_cuisines,omitempty"`
+	ExcludedIngredients []string `json:"excluded_ingredients,omitempty"`
+
+	MinDifficulty *int `json:"min_difficulty,omitempty"`
+	MaxDifficulty *int `json:"max_difficulty,omitempty"`
+	MinPrepTime   *int `json:"min_prep_time,omitempty"` // in minutes
+	MaxPrepTime   *int `json:"max_prep_time,omitempty"` // in minutes
+}
+
+type SearchRequest struct {
+	Query  SearchQuery
+	Limit  int
+	Offset int
+}
+
+type SearchRequestDTO struct {
+	Query  string `form:"q"`
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+}
+
+// SearchHit is a matching document along with its relevance to the query
+type SearchHit struct {
+	RecipeDocument
+	Rank float64
+}
+
+type SearchResult struct {
+	Total int64
+	Hits  []SearchHit
+}
+
+type SearchHitDTO struct {
+	RecipeDocumentDTO
+	Rank float64 `json:"rank"`
+}
+
+type SearchResultDTO struct {
+	Query   string         `json:"query"`
+	Total   int64          `json:"total"`
+	Limit   int            `json:"limit"`
+	Offset  int            `json:"offset"`
+	Results []SearchHitDTO `json:"results"`
+}
+
+// IndexReportDTO summarizes a run over all recipes
+type IndexReportDTO struct {
+	Indexed int         `json:"indexed"`
+	Failed  []uuid.UUID `json:"failed"`
+}
+
+// The states of a rebuild of the index
+const (
+	ReindexIdle     = "idle"
+	ReindexRunning  = "running"
+	ReindexFinished = "finished"
+	ReindexFailed   = "failed"
+)
+
+// ReindexStatusDTO reports the progress of the last rebuild of the index
+type ReindexStatusDTO struct {
+	State      string      `json:"state"`
+	Total      int         `json:"total"`
+	Indexed    int         `json:"indexed"`
+	Failed     []uuid.UUID `json:"failed"`
+	StartedAt  *time.Time  `json:"started_at,omitempty"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// The kinds of names suggestions are made from
+const (
+	TermRecipe     = "recipe"
+	TermIngredient = "ingredient"
+	TermTag        = "tag"
+	TermCategory   = "category"
+)
+
+var TermTypes = []string{TermRecipe, TermIngredient, TermTag, TermCategory}
+
+// The kinds of metadata that are recorded as terms only to find the recipes using them when they change. They are not suggested.
+const (
+	TermCuisineType     = "cuisine_type"
+	TermDifficultyLevel = "difficulty_level"
+	TermPreparationTime = "preparation_time"
+)
+
+// RecipeTerm records that a recipe uses a name, such as its own name or that of one of its ingredients.
+// The names are matched on trigrams so misspelled and partially typed names are found.
+type RecipeTerm struct {
+	RecipeID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Type     string    `gorm:"type:varchar(16);primaryKey"`
+	EntityID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Name     string    `gorm:"not null;index:idx_recipe_terms_name,type:gin,expression:name gin_trgm_ops"`
+}
+
+type SuggestionRequest struct {
+	Query string
+	Types []string
+	Limit int
+}
+
+type SuggestionRequestDTO struct {
+	Query string `form:"q"`
+	Types string `form:"types"` // comma separated, defaults to all types
+	Limit int    `form:"limit"`
+}
+
+// Suggestion is a name matching the typed text, scored by how well it matches and counted by the recipes using it
+type Suggestion struct {
+	Type     string
+	EntityID uuid.UUID
+	Name     string
+	Recipes  int64
+	Score    float64
+}
+
+func (s Suggestion) ConvertToDTO() SuggestionDTO {
+	return SuggestionDTO(s)
+}
+
+type SuggestionDTO struct {
+	Type     string    `json:"type"`
+	EntityID uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Recipes  int64     `json:"recipes"`
+	Score    float64   `json:"score"`
+}
@@ -0,0 +1,314 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	m "search-service/internal/models"
+)
+
+// SyntaxError describes why a query string could not be parsed. Position is the 1-based character the problem starts at.
+type SyntaxError struct {
+	Position int
+	Message  string
+}
+
+func (e SyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+}
+
+const (
+	maxDifficulty = 5
+	maxPrepTime   = 24 * 60
+)
+
+// Parse turns a query string into a structured query. The syntax consists of whitespace separated clauses:
+//
+//	chicken              a word the recipe has to contain
+//	"green curry"        a phrase the recipe has to contain
+//	tag:weeknight        a filter on a field, quoted values such as tag:"one pot" may contain spaces
+//	time:<30             a comparison with <, <=, >, >= or =, or a range such as time:10..30
+//	-ingredient:peanut   a leading - excludes recipes matching the word, phrase or filter
+//
+// The fields are tag, category, cuisine and ingredient, which match names, and difficulty (1 to 5) and time, which
+// compare numbers. Times are in minutes unless suffixed with h, e.g. time:<=1h.
+func Parse(text string) (m.SearchQuery, error) {
+	p := parser{input: []rune(text)}
+
+	return p.parse()
+}
+
+type parser struct {
+	input []rune
+	pos   int // index of the next rune to read
+	query m.SearchQuery
+}
+
+func (p *parser) parse() (m.SearchQuery, error) {
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.input) {
+			break
+		}
+
+		if err := p.clause(); err != nil {
+			return m.SearchQuery{}, err
+		}
+	}
+
+	return p.query, nil
+}
+
+// clause parses a single (possibly negated) word, phrase or field filter
+func (p *parser) clause() error {
+	start := p.pos
+	negated := false
+
+	if p.input[p.pos] == '-' {
+		negated = true
+		p.pos++
+
+		if p.pos >= len(p.input) || unicode.IsSpace(p.input[p.pos]) {
+			return p.errorAt(start, "expected a word, phrase or filter after -")
+		}
+	}
+
+	if p.input[p.pos] == '"' {
+		phrase, err := p.quoted()
+		if err != nil {
+			return err
+		}
+
+		p.term(phrase, negated)
+		return nil
+	}
+
+	if field, ok := p.field(); ok {
+		return p.filter(start, field, negated)
+	}
+
+	p.term(p.word(), negated)
+	return nil
+}
+
+// field reads a field name followed by a colon, leaving the position untouched when there is none
+func (p *parser) field() (string, bool) {
+	end := p.pos
+	for end < len(p.input) && unicode.IsLetter(p.input[end]) {
+		end++
+	}
+
+	if end == p.pos || end >= len(p.input) || p.input[end] != ':' {
+		return "", false
+	}
+
+	name := string(p.input[p.pos:end])
+	p.pos = end + 1
+
+	return name, true
+}
+
+func (p *parser) filter(start int, field string, negated bool) error {
+	valueStart := p.pos
+
+	var value string
+	if p.pos < len(p.input) && p.input[p.pos] == '"' {
+		var err error
+		if value, err = p.quoted(); err != nil {
+			return err
+		}
+	} else {
+		value = p.word()
+	}
+
+	if value == "" {
+		return p.errorAt(valueStart, fmt.Sprintf("missing value for %s", field))
+	}
+
+	switch strings.ToLower(field) {
+	case "tag":
+		p.name(&p.query.Tags, &p.query.ExcludedTags, value, negated)
+	case "category":
+		p.name(&p.query.Categories, &p.query.ExcludedCategories, value, negated)
+	case "cuisine":
+		p.name(&p.query.Cuisines, &p.query.ExcludedCuisines, value, negated)
+	case "ingredient":
+		p.name(&p.query.Ingredients, &p.query.ExcludedIngredients, value, negated)
+	case "difficulty":
+		if negated {
+			return p.errorAt(start, "difficulty cannot be excluded")
+		}
+		return p.comparison(valueStart, "difficulty", value, 1, maxDifficulty, parseLevel, &p.query.MinDifficulty, &p.query.MaxDifficulty)
+	case "time":
+		if negated {
+			return p.errorAt(start, "time cannot be excluded")
+		}
+		return p.comparison(valueStart, "time", value, 1, maxPrepTime, parseDuration, &p.query.MinPrepTime, &p.query.MaxPrepTime)
+	default:
+		if negated {
+			start++
+		}
+		return p.errorAt(start, fmt.Sprintf("unknown field %q", field))
+	}
+
+	return nil
+}
+
+func (p *parser) name(included *[]string, excluded *[]string, value string, negated bool) {
+	if negated {
+		*excluded = append(*excluded, value)
+	} else {
+		*included = append(*included, value)
+	}
+}
+
+func (p *parser) term(term string, negated bool) {
+	if negated {
+		p.query.Excluded = append(p.query.Excluded, term)
+	} else {
+		p.query.Terms = append(p.query.Terms, term)
+	}
+}
+
+// comparison narrows the range [min, max] by an expression such as <30, >=2, 3 or 10..30
+func (p *parser) comparison(start int, field string, value string, lowest int, highest int, number func(string) (int, bool), min **int, max **int) error {
+	var from, to int
+
+	operators := []string{"<=", ">=", "<", ">", "="}
+	operator := ""
+	for _, candidate := range operators {
+		if strings.HasPrefix(value, candidate) {
+			operator = candidate
+			break
+		}
+	}
+
+	operand := strings.TrimPrefix(value, operator)
+	operandStart := start + len([]rune(operator))
+
+	if lower, upper, isRange := strings.Cut(operand, ".."); isRange && operator == "" {
+		var ok bool
+		if from, ok = number(lower); !ok {
+			return p.errorAt(operandStart, fmt.Sprintf("invalid %s %q", field, lower))
+		}
+		if to, ok = number(upper); !ok {
+			return p.errorAt(operandStart+len([]rune(lower))+2, fmt.Sprintf("invalid %s %q", field, upper))
+		}
+	} else {
+		n, ok := number(operand)
+		if !ok {
+			return p.errorAt(operandStart, fmt.Sprintf("invalid %s %q", field, operand))
+		}
+
+		switch operator {
+		case "<":
+			from, to = lowest, n-1
+		case "<=":
+			from, to = lowest, n
+		case ">":
+			from, to = n+1, highest
+		case ">=":
+			from, to = n, highest
+		default:
+			from, to = n, n
+		}
+	}
+
+	if from < lowest || to > highest || from > to {
+		return p.errorAt(start, fmt.Sprintf("%s must be between %d and %d", field, lowest, highest))
+	}
+
+	if *min == nil || from > **min {
+		*min = &from
+	}
+	if *max == nil || to < **max {
+		*max = &to
+	}
+
+	if **min > **max {
+		return p.errorAt(start, fmt.Sprintf("%s conflicts with an earlier %s filter", field, field))
+	}
+
+	return nil
+}
+
+// quoted reads a phrase between double quotes
+func (p *parser) quoted() (string, error) {
+	start := p.pos
+	p.pos++
+
+	end := p.pos
+	for end < len(p.input) && p.input[end] != '"' {
+		end++
+	}
+
+	if end >= len(p.input) {
+		return "", p.errorAt(start, "unterminated quote")
+	}
+
+	phrase := strings.TrimSpace(string(p.input[p.pos:end]))
+	p.pos = end + 1
+
+	if phrase == "" {
+		return "", p.errorAt(start, "empty phrase")
+	}
+
+	if p.pos < len(p.input) && !unicode.IsSpace(p.input[p.pos]) {
+		return "", p.errorAt(p.pos, "expected a space after the closing quote")
+	}
+
+	return phrase, nil
+}
+
+// word reads up to the next whitespace
+func (p *parser) word() string {
+	start := p.pos
+	for p.pos < len(p.input) && !unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+
+	return string(p.input[start:p.pos])
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *parser) errorAt(index int, message string) error {
+	return SyntaxError{Position: index + 1, Message: message}
+}
+
+func parseLevel(text string) (int, bool) {
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// parseDuration reads a number of minutes, optionally suffixed with m, min or h
+func parseDuration(text string) (int, bool) {
+	multiplier := 1
+
+	switch {
+	case strings.HasSuffix(text, "min"):
+		text = strings.TrimSuffix(text, "min")
+	case strings.HasSuffix(text, "m"):
+		text = strings.TrimSuffix(text, "m")
+	case strings.HasSuffix(text, "h"):
+		text = strings.TrimSuffix(text, "h")
+		multiplier = 60
+	}
+
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, false
+	}
+
+	return n * multiplier, true
+}
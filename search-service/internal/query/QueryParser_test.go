@@ -0,0 +1,107 @@
+package query
+
+import (
+	"testing"
+
+	m "search-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intPointer(i int) *int {
+	return &i
+}
+
+func TestParse_OK(t *testing.T) {
+	result, err := Parse(`chicken tag:weeknight cuisine:thai time:<30 difficulty:<=2 -ingredient:peanut`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.SearchQuery{
+		Terms:               []string{"chicken"},
+		Tags:                []string{"weeknight"},
+		Cuisines:            []string{"thai"},
+		ExcludedIngredients: []string{"peanut"},
+		MinDifficulty:       intPointer(1),
+		MaxDifficulty:       intPointer(2),
+		MinPrepTime:         intPointer(1),
+		MaxPrepTime:         intPointer(29),
+	}, result)
+}
+
+func TestParse_Phrases(t *testing.T) {
+	result, err := Parse(`  "green curry" -"fish sauce" -coconut Category:"main course" -tag:spicy `)
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.SearchQuery{
+		Terms:        []string{"green curry"},
+		Excluded:     []string{"fish sauce", "coconut"},
+		Categories:   []string{"main course"},
+		ExcludedTags: []string{"spicy"},
+	}, result)
+}
+
+func TestParse_Comparisons(t *testing.T) {
+	for text, expected := range map[string][2]int{
+		"time:30":                {30, 30},
+		"time:=30m":              {30, 30},
+		"time:>=1h":              {60, maxPrepTime},
+		"time:>45min":            {46, maxPrepTime},
+		"time:10..30":            {10, 30},
+		"time:>=10 time:<=20":    {10, 20},
+		"time:<=1h time:<=30":    {1, 30},
+		"time:5..50 time:10..60": {10, 50},
+	} {
+		result, err := Parse(text)
+
+		assert.NoError(t, err, text)
+		assert.Equal(t, expected[0], *result.MinPrepTime, text)
+		assert.Equal(t, expected[1], *result.MaxPrepTime, text)
+	}
+
+	result, err := Parse("difficulty:3..5")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, *result.MinDifficulty)
+	assert.Equal(t, 5, *result.MaxDifficulty)
+	assert.Nil(t, result.MinPrepTime)
+}
+
+func TestParse_Empty(t *testing.T) {
+	result, err := Parse("   ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.SearchQuery{}, result)
+	assert.False(t, result.HasText())
+}
+
+func TestParse_SyntaxErr(t *testing.T) {
+	for text, expected := range map[string]SyntaxError{
+		`chicken colour:red`:  {9, `unknown field "colour"`},
+		`chicken -colour:red`: {10, `unknown field "colour"`},
+		`pasta -`:             {7, "expected a word, phrase or filter after -"},
+		`tag: pasta`:          {5, "missing value for tag"},
+		`"green curry`:        {1, "unterminated quote"},
+		`tag:"one pot`:        {5, "unterminated quote"},
+		`"" pasta`:            {1, "empty phrase"},
+		`"green curry"s`:      {14, "expected a space after the closing quote"},
+		`time:<abc`:           {7, `invalid time "abc"`},
+		`time:10..x`:          {10, `invalid time "x"`},
+		`difficulty:7`:        {12, "difficulty must be between 1 and 5"},
+		`difficulty:<1`:       {12, "difficulty must be between 1 and 5"},
+		`time:30..10`:         {6, "time must be between 1 and 1440"},
+		`time:>60 time:<30`:   {15, "time conflicts with an earlier time filter"},
+		`soup -time:<30`:      {6, "time cannot be excluded"},
+		`soup -difficulty:1`:  {6, "difficulty cannot be excluded"},
+		`crème brûlée tag:`:   {18, "missing value for tag"},
+	} {
+		_, err := Parse(text)
+
+		assert.Equal(t, expected, err, text)
+	}
+}
+
+func TestSyntaxError(t *testing.T) {
+	err := SyntaxError{Position: 9, Message: `unknown field "colour"`}
+
+	assert.EqualError(t, err, `unknown field "colour" at position 9`)
+}
@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"errors"
+
+	m "search-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SavedSearchRepository stores the saved searches and their matches. It shares the matching of documents with the SearchRepository.
+type SavedSearchRepository struct {
+	SearchRepository
+}
+
+// NewSavedSearchRepository creates a repository that matches documents with the given PostgreSQL text search configuration
+func NewSavedSearchRepository(db *gorm.DB, language string) *SavedSearchRepository {
+	return &SavedSearchRepository{
+		SearchRepository: SearchRepository{
+			db:       db,
+			language: language,
+		},
+	}
+}
+
+// FindBySubject returns the saved searches of a user by name. Having none is not an error.
+func (r SavedSearchRepository) FindBySubject(subject string) ([]m.SavedSearch, error) {
+	var savedSearches []m.SavedSearch
+
+	if err := r.db.Where("subject = ?", subject).Order("name").Find(&savedSearches).Error; err != nil {
+		return nil, err
+	}
+
+	return savedSearches, nil
+}
+
+// FindSingle returns a saved search of a user
+func (r SavedSearchRepository) FindSingle(subject string, id uuid.UUID) (m.SavedSearch, error) {
+	var savedSearch m.SavedSearch
+
+	if err := r.db.Where("id = ? AND subject = ?", id, subject).Take(&savedSearch).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return m.SavedSearch{}, errors.New("not found")
+		}
+		return m.SavedSearch{}, err
+	}
+
+	return savedSearch, nil
+}
+
+// FindAll returns the saved searches of all users
+func (r SavedSearchRepository) FindAll() ([]m.SavedSearch, error) {
+	var savedSearches []m.SavedSearch
+
+	if err := r.db.Order("created_at").Find(&savedSearches).Error; err != nil {
+		return nil, err
+	}
+
+	return savedSearches, nil
+}
+
+// Create stores a saved search along with the recipes it already matches, so only later matches are reported
+func (r SavedSearchRepository) Create(savedSearch m.SavedSearch, query m.SearchQuery) (m.SavedSearch, error) {
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Omit(clause.Associations).Create(&savedSearch).Error; err != nil {
+			return err
+		}
+
+		return r.baseline(tx, savedSearch, query)
+	}); err != nil {
+		return m.SavedSearch{}, err
+	}
+
+	return savedSearch, nil
+}
+
+// Update changes the name and query of a saved search. The recipes matching the new query are taken as the new baseline,
+// matches that were already reported are kept.
+func (r SavedSearchRepository) Update(savedSearch m.SavedSearch, query m.SearchQuery) (m.SavedSearch, error) {
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+
+		result := tx.Model(&savedSearch).Where("subject = ?", savedSearch.Subject).Select("name", "query").Updates(&savedSearch)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("not found")
+		}
+
+		if err := tx.Where("saved_search_id = ? AND baseline", savedSearch.ID).Delete(&m.SavedSearchMatch{}).Error; err != nil {
+			return err
+		}
+
+		return r.baseline(tx, savedSearch, query)
+	}); err != nil {
+		return m.SavedSearch{}, err
+	}
+
+	return r.FindSingle(savedSearch.Subject, savedSearch.ID)
+}
+
+// Delete removes a saved search of a user along with its matches
+func (r SavedSearchRepository) Delete(subject string, id uuid.UUID) error {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND subject = ?", id, subject).Delete(&m.SavedSearch{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("not found")
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindMatching returns the document of a recipe when it matches the query
+func (r SavedSearchRepository) FindMatching(query m.SearchQuery, recipeID uuid.UUID) (m.RecipeDocument, error) {
+	var documents []m.RecipeDocument
+
+	if err := r.matching(query).
+		Select(documentColumns).
+		Where("recipe_documents.recipe_id = ?", recipeID).
+		Scan(&documents).Error; err != nil {
+		return m.RecipeDocument{}, err
+	}
+
+	if len(documents) <= 0 {
+		return m.RecipeDocument{}, errors.New("not found")
+	}
+
+	return documents[0], nil
+}
+
+// RecordMatch stores a match unless the recipe matched the saved search before, and reports whether it was stored
+func (r SavedSearchRepository) RecordMatch(match m.SavedSearchMatch) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&match)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Feed returns the reported matches after the given one in the order they were found
+func (r SavedSearchRepository) Feed(request m.FeedRequest) ([]m.SavedSearchMatch, error) {
+	var matches []m.SavedSearchMatch
+
+	query := r.db.Table("saved_search_matches").
+		Select("saved_search_matches.*, saved_searches.name AS saved_search_name").
+		Joins("JOIN saved_searches ON saved_searches.id = saved_search_matches.saved_search_id").
+		Where("saved_search_matches.id > ?", request.After).
+		Where("NOT saved_search_matches.baseline")
+
+	if request.Subject != "" {
+		query = query.Where("saved_search_matches.subject = ?", request.Subject)
+	}
+
+	if err := query.Order("saved_search_matches.id").Limit(request.Limit).Scan(&matches).Error; err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// baseline records the recipes currently matching a saved search without reporting them
+func (r SavedSearchRepository) baseline(tx *gorm.DB, savedSearch m.SavedSearch, query m.SearchQuery) error {
+	matching := r.matching(query).
+		Select("?, ?, recipe_documents.recipe_id, recipe_documents.name, true, now()", savedSearch.ID, savedSearch.Subject)
+
+	return tx.Exec("INSERT INTO saved_search_matches (saved_search_id, subject, recipe_id, recipe_name, baseline, matched_at) ? ON CONFLICT DO NOTHING", matching).Error
+}
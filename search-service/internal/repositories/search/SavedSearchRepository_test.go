@@ -0,0 +1,275 @@
+package repositories
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	m "search-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	co "search-service/internal/common/test"
+)
+
+var (
+	subject     string             = "7c6a7ba4-5a4e-4b0a-9f5b-4d7f7f6b1f2a"
+	savedSearch m.SavedSearch      = m.SavedSearch{ID: uuid.New(), Subject: subject, Name: "quick chicken", Query: "chicken tag:quick"}
+	savedQuery  m.SearchQuery      = m.SearchQuery{Terms: []string{"chicken"}, Tags: []string{"quick"}}
+	match       m.SavedSearchMatch = m.SavedSearchMatch{SavedSearchID: savedSearch.ID, Subject: subject, RecipeID: id, RecipeName: "Creamy chicken pasta"}
+)
+
+const (
+	baselineQuery = `INSERT INTO saved_search_matches (saved_search_id, subject, recipe_id, recipe_name, baseline, matched_at) SELECT $1, $2, recipe_documents.recipe_id, recipe_documents.name, true, now() FROM recipe_documents, (SELECT phraseto_tsquery($3::regconfig, $4) AS query) search WHERE recipe_documents.document @@ search.query AND (EXISTS (SELECT 1 FROM recipe_terms WHERE recipe_terms.recipe_id = recipe_documents.recipe_id AND recipe_terms.type = $5 AND lower(recipe_terms.name) = lower($6))) ON CONFLICT DO NOTHING`
+	feedQuery     = `SELECT saved_search_matches.*, saved_searches.name AS saved_search_name FROM "saved_search_matches" JOIN saved_searches ON saved_searches.id = saved_search_matches.saved_search_id WHERE saved_search_matches.id > $1 AND NOT saved_search_matches.baseline`
+)
+
+func TestSavedSearchFindBySubject_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSavedSearchRepository(db, "english")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "saved_searches" WHERE subject = $1 ORDER BY name`)).
+		WithArgs(subject).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subject", "name", "query"}).
+			AddRow(savedSearch.ID, subject, savedSearch.Name, savedSearch.Query))
+
+	result, err := r.FindBySubject(subject)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []m.SavedSearch{savedSearch}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedSearchFindSingle_NotFound(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSavedSearchRepository(db, "english")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "saved_searches" WHERE id = $1 AND subject = $2 LIMIT $3`)).
+		WithArgs(savedSearch.ID, subject, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := r.FindSingle(subject, savedSearch.ID)
+
+	assert.EqualError(t, err, "not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedSearchFindAll_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSavedSearchRepository(db, "english")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "saved_searches" ORDER BY created_at`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subject", "name", "query"}).
+			AddRow(savedSearch.ID, subject, savedSearch.Name, savedSearch.Query))
+
+	result, err := r.FindAll()
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedSearchCreate_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSavedSearchRepository(db, "english")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "saved_searches" ("id","subject","name","query","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6)`)).
+		WithArgs(savedSearch.ID, subject, savedSearch.Name, savedSearch.Query, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(baselineQuery)).
+		WithArgs(savedSearch.ID, subject, "english", "chicken", m.TermTag, "quick").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	result, err := r.Create(savedSearch, savedQuery)
+
+	assert.NoError(t, err)
+	assert.Equal(t, savedSearch.ID, result.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedSearchCreate_Err(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSavedSearchRepository(db, "english")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "saved_searches"`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(baselineQuery)).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	_, err := r.Create(savedSearch, savedQuery)
+
+	assert.EqualError(t, err, "error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedSearchUpdate_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSavedSearchRepository(db, "english")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "saved_searches" SET "name"=$1,"query"=$2,"updated_at"=$3 WHERE subject = $4 AND "id" = $5`)).
+		WithArgs(savedSearch.Name, savedSearch.Query, sqlmock.AnyArg(), subject, savedSearch.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "saved_search_matches" WHERE saved_search_id = $1 AND baseline`)).
+		WithArgs(savedSearch.ID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(baselineQuery)).
+		WithArgs(savedSearch.ID, subject, "english", "chicken", m.TermTag, "quick").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "saved_searches" WHERE id = $1 AND subject = $2 LIMIT $3`)).
+		WithArgs(savedSearch.ID, subject, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subject", "name", "query"}).
+			AddRow(savedSearch.ID, subject, savedSearch.Name, savedSearch.Query))
+
+	result, err := r.Update(savedSearch, savedQuery)
+
+	assert.NoError(t, err)
+	assert.Equal(t, savedSearch, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedSearchUpdate_NotFound(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSavedSearchRepository(db, "english")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "saved_searches"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err := r.Update(savedSearch, savedQuery)
+
+	assert.EqualError(t, err, "not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedSearchDelete_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSavedSearchRepository(db, "english")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "saved_searches" WHERE id = $1 AND subject = $2`)).
+		WithArgs(savedSearch.ID, subject).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := r.Delete(subject, savedSearch.ID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedSearchDelete_NotFound(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSavedSearchRepository(db, "english")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "saved_searches" WHERE id = $1 AND subject = $2`)).
+		WithArgs(savedSearch.ID, subject).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := r.Delete(subject, savedSearch.ID)
+
+	assert.EqualError(t, err, "not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedSearchFindMatching_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSavedSearchRepository(db, "english")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT `+documentColumns+` FROM recipe_documents, (SELECT phraseto_tsquery($1::regconfig, $2) AS query) search WHERE recipe_documents.document @@ search.query AND recipe_documents.recipe_id = $3`)).
+		WithArgs("english", "chicken", id).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "name"}).AddRow(id, "Creamy chicken pasta"))
+
+	result, err := r.FindMatching(m.SearchQuery{Terms: []string{"chicken"}}, id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, result.RecipeID)
+	assert.Equal(t, "Creamy chicken pasta", result.Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedSearchFindMatching_NotFound(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSavedSearchRepository(db, "english")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT `+documentColumns+` FROM "recipe_documents" WHERE recipe_documents.prep_time <= $1 AND recipe_documents.recipe_id = $2`)).
+		WithArgs(30, id).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "name"}))
+
+	_, err := r.FindMatching(m.SearchQuery{MaxPrepTime: intPointer(30)}, id)
+
+	assert.EqualError(t, err, "not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedSearchRecordMatch_OK(t *testing.T) {
+	for expected, rows := range map[bool]*sqlmock.Rows{
+		true:  sqlmock.NewRows([]string{"id"}).AddRow(12),
+		false: sqlmock.NewRows([]string{"id"}),
+	} {
+		db, mock := co.NewMockDatabase(t)
+		r := NewSavedSearchRepository(db, "english")
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "saved_search_matches" ("saved_search_id","subject","recipe_id","recipe_name","baseline","matched_at") VALUES ($1,$2,$3,$4,$5,$6) ON CONFLICT DO NOTHING RETURNING "id"`)).
+			WithArgs(savedSearch.ID, subject, id, "Creamy chicken pasta", false, sqlmock.AnyArg()).
+			WillReturnRows(rows)
+		mock.ExpectCommit()
+
+		recorded, err := r.RecordMatch(match)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, recorded)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	}
+}
+
+func TestSavedSearchFeed_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSavedSearchRepository(db, "english")
+	matchedAt := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(feedQuery+` AND saved_search_matches.subject = $2 ORDER BY saved_search_matches.id LIMIT $3`)).
+		WithArgs(4, subject, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "saved_search_id", "subject", "recipe_id", "recipe_name", "baseline", "matched_at", "saved_search_name"}).
+			AddRow(5, savedSearch.ID, subject, id, "Creamy chicken pasta", false, matchedAt, savedSearch.Name))
+
+	result, err := r.Feed(m.FeedRequest{Subject: subject, After: 4, Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []m.SavedSearchMatch{{
+		ID:              5,
+		SavedSearchID:   savedSearch.ID,
+		Subject:         subject,
+		RecipeID:        id,
+		RecipeName:      "Creamy chicken pasta",
+		MatchedAt:       matchedAt,
+		SavedSearchName: savedSearch.Name,
+	}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedSearchFeed_AllSubjects(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSavedSearchRepository(db, "english")
+
+	mock.ExpectQuery(regexp.QuoteMeta(feedQuery+` ORDER BY saved_search_matches.id LIMIT $2`)).
+		WithArgs(0, 50).
+		WillReturnError(errors.New("error"))
+
+	_, err := r.Feed(m.FeedRequest{Limit: 50})
+
+	assert.EqualError(t, err, "error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,316 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	m "search-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// the document is weighted so a match in the name of a recipe outranks one in its tags and categories,
+	// which in turn outranks a match in its description or ingredients, and finally its instructions
+	documentExpression = `setweight(to_tsvector(?::regconfig, ?), 'A') || setweight(to_tsvector(?::regconfig, ?), 'B') || setweight(to_tsvector(?::regconfig, ?), 'C') || setweight(to_tsvector(?::regconfig, ?), 'D')`
+
+	documentColumns = "recipe_documents.recipe_id, recipe_documents.name, recipe_documents.description, recipe_documents.ingredients, recipe_documents.tags, recipe_documents.categories, recipe_documents.cuisine_type, recipe_documents.difficulty, recipe_documents.prep_time, recipe_documents.indexed_at"
+
+	termExists       = "EXISTS (SELECT 1 FROM recipe_terms WHERE recipe_terms.recipe_id = recipe_documents.recipe_id AND recipe_terms.type = ? AND lower(recipe_terms.name) = lower(?))"
+	ingredientExists = "EXISTS (SELECT 1 FROM recipe_terms WHERE recipe_terms.recipe_id = recipe_documents.recipe_id AND recipe_terms.type = 'ingredient' AND recipe_terms.name ILIKE ?)"
+)
+
+// the tables a new index is built in before it replaces the current one
+const (
+	rebuildDocuments = "recipe_documents_rebuild"
+	rebuildTerms     = "recipe_terms_rebuild"
+)
+
+type SearchRepository struct {
+	db       *gorm.DB
+	language string
+}
+
+// NewSearchRepository creates a repository that stems and filters stop words according to the given PostgreSQL text search configuration
+func NewSearchRepository(db *gorm.DB, language string) *SearchRepository {
+	return &SearchRepository{
+		db:       db,
+		language: language,
+	}
+}
+
+// Save creates or replaces the document of a recipe and its terms, and recalculates its search vector
+func (r SearchRepository) Save(document m.RecipeDocument) (m.RecipeDocument, error) {
+	return r.save(document, "recipe_documents", "recipe_terms")
+}
+
+// SaveRebuild saves a document into the index that is being rebuilt, see PrepareRebuild
+func (r SearchRepository) SaveRebuild(document m.RecipeDocument) (m.RecipeDocument, error) {
+	return r.save(document, rebuildDocuments, rebuildTerms)
+}
+
+func (r SearchRepository) save(document m.RecipeDocument, documentTable string, termTable string) (m.RecipeDocument, error) {
+	weighted := document.Weighted()
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+
+		if err = tx.Table(documentTable).Omit(clause.Associations).Clauses(clause.OnConflict{UpdateAll: true}).Create(&document).Error; err != nil {
+			return err
+		}
+
+		if err = tx.Exec(`UPDATE `+documentTable+` SET document = `+documentExpression+` WHERE recipe_id = ?`,
+			r.language, weighted[0],
+			r.language, weighted[1],
+			r.language, weighted[2],
+			r.language, weighted[3],
+			document.RecipeID,
+		).Error; err != nil {
+			return err
+		}
+
+		if err = tx.Table(termTable).Where("recipe_id = ?", document.RecipeID).Delete(&m.RecipeTerm{}).Error; err != nil {
+			return err
+		}
+
+		if len(document.Terms) > 0 {
+			if err = tx.Table(termTable).Create(&document.Terms).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return m.RecipeDocument{}, err
+	}
+
+	return document, nil
+}
+
+// Delete removes the document of a recipe from the index
+func (r SearchRepository) Delete(recipeID uuid.UUID) error {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("recipe_id = ?", recipeID).Delete(&m.RecipeDocument{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("not found")
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindRecipesByTerm returns the recipes using an entity such as an ingredient or a tag
+func (r SearchRepository) FindRecipesByTerm(termType string, entityID uuid.UUID) ([]uuid.UUID, error) {
+	var recipeIDs []uuid.UUID
+
+	if err := r.db.Model(&m.RecipeTerm{}).
+		Where("type = ?", termType).
+		Where("entity_id = ?", entityID).
+		Order("recipe_id").
+		Pluck("recipe_id", &recipeIDs).Error; err != nil {
+		return nil, err
+	}
+
+	return recipeIDs, nil
+}
+
+// PrepareRebuild creates empty tables next to the index to build a new index in, using the current layout of the index
+func (r SearchRepository) PrepareRebuild() error {
+	for _, statement := range []string{
+		"DROP TABLE IF EXISTS " + rebuildTerms + ", " + rebuildDocuments,
+		"CREATE TABLE " + rebuildDocuments + " (LIKE recipe_documents INCLUDING ALL)",
+		"CREATE TABLE " + rebuildTerms + " (LIKE recipe_terms INCLUDING ALL)",
+	} {
+		if err := r.db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SwapRebuild replaces the index with the rebuilt one. The recipes to keep retain their current document, for when they could not be rebuilt.
+// The index is replaced in a single transaction, searches see the previous index until it is committed.
+func (r SearchRepository) SwapRebuild(keep []uuid.UUID) error {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+
+		if len(keep) > 0 {
+			if err = tx.Exec("INSERT INTO "+rebuildDocuments+" SELECT * FROM recipe_documents WHERE recipe_id IN ? ON CONFLICT DO NOTHING", keep).Error; err != nil {
+				return err
+			}
+
+			if err = tx.Exec("INSERT INTO "+rebuildTerms+" SELECT * FROM recipe_terms WHERE recipe_id IN ? ON CONFLICT DO NOTHING", keep).Error; err != nil {
+				return err
+			}
+		}
+
+		// the terms are removed along with their documents
+		if err = tx.Exec("DELETE FROM recipe_documents").Error; err != nil {
+			return err
+		}
+
+		if err = tx.Exec("INSERT INTO recipe_documents SELECT * FROM " + rebuildDocuments).Error; err != nil {
+			return err
+		}
+
+		if err = tx.Exec("INSERT INTO recipe_terms SELECT * FROM " + rebuildTerms).Error; err != nil {
+			return err
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return r.db.Exec("DROP TABLE IF EXISTS " + rebuildTerms + ", " + rebuildDocuments).Error
+}
+
+// Search returns the documents matching the query, ordered by relevance when the query contains text and by name otherwise
+func (r SearchRepository) Search(request m.SearchRequest) (m.SearchResult, error) {
+	var result m.SearchResult
+
+	if err := r.matching(request.Query).Count(&result.Total).Error; err != nil {
+		return m.SearchResult{}, err
+	}
+
+	rank := "0"
+	if request.Query.HasText() {
+		rank = "ts_rank(recipe_documents.document, search.query)"
+	}
+
+	if err := r.matching(request.Query).
+		Select(documentColumns + ", " + rank + " AS rank").
+		Order("rank DESC").
+		Order("recipe_documents.name").
+		Order("recipe_documents.recipe_id").
+		Limit(request.Limit).
+		Offset(request.Offset).
+		Scan(&result.Hits).Error; err != nil {
+		return m.SearchResult{}, err
+	}
+
+	return result, nil
+}
+
+// Suggest returns the names starting with or resembling the typed text. Names the text is a prefix of rank first,
+// the others by trigram word similarity, which tolerates typos such as "zuchini" and "parmasan".
+func (r SearchRepository) Suggest(request m.SuggestionRequest) ([]m.Suggestion, error) {
+	var suggestions []m.Suggestion
+
+	prefix := escapeLike(request.Query) + "%"
+
+	if err := r.db.Model(&m.RecipeTerm{}).
+		Select("recipe_terms.type, recipe_terms.entity_id, recipe_terms.name, count(recipe_terms.recipe_id) AS recipes, "+
+			"max(word_similarity(?, recipe_terms.name) + CASE WHEN recipe_terms.name ILIKE ? THEN 1 ELSE 0 END) AS score", request.Query, prefix).
+		Where("recipe_terms.name ILIKE ? OR ? <% recipe_terms.name", prefix, request.Query).
+		Where("recipe_terms.type IN ?", request.Types).
+		Group("recipe_terms.type, recipe_terms.entity_id, recipe_terms.name").
+		Order("score DESC").
+		Order("recipes DESC").
+		Order("recipe_terms.name").
+		Limit(request.Limit).
+		Scan(&suggestions).Error; err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+// escapeLike escapes the wildcards in text so it is matched literally in a LIKE pattern
+func escapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(text)
+}
+
+// matching starts a query on all documents matching the search query
+func (r SearchRepository) matching(query m.SearchQuery) *gorm.DB {
+	db := r.db.Table("recipe_documents")
+
+	if query.HasText() {
+		expression, args := r.textQuery(query)
+
+		db = r.db.Table("recipe_documents, (SELECT "+expression+" AS query) search", args...).
+			Where("recipe_documents.document @@ search.query")
+	}
+
+	for _, tag := range query.Tags {
+		db = db.Where(termExists, m.TermTag, tag)
+	}
+	for _, tag := range query.ExcludedTags {
+		db = db.Where("NOT "+termExists, m.TermTag, tag)
+	}
+
+	for _, category := range query.Categories {
+		db = db.Where(termExists, m.TermCategory, category)
+	}
+	for _, category := range query.ExcludedCategories {
+		db = db.Where("NOT "+termExists, m.TermCategory, category)
+	}
+
+	// ingredients match on part of their name, so excluding peanut also excludes peanut butter
+	for _, ingredient := range query.Ingredients {
+		db = db.Where(ingredientExists, "%"+escapeLike(ingredient)+"%")
+	}
+	for _, ingredient := range query.ExcludedIngredients {
+		db = db.Where("NOT "+ingredientExists, "%"+escapeLike(ingredient)+"%")
+	}
+
+	if len(query.Cuisines) > 0 {
+		db = db.Where("lower(recipe_documents.cuisine_type) IN ?", lower(query.Cuisines))
+	}
+	if len(query.ExcludedCuisines) > 0 {
+		db = db.Where("lower(recipe_documents.cuisine_type) NOT IN ?", lower(query.ExcludedCuisines))
+	}
+
+	if query.MinDifficulty != nil {
+		db = db.Where("recipe_documents.difficulty >= ?", *query.MinDifficulty)
+	}
+	if query.MaxDifficulty != nil {
+		db = db.Where("recipe_documents.difficulty <= ?", *query.MaxDifficulty)
+	}
+
+	if query.MinPrepTime != nil {
+		db = db.Where("recipe_documents.prep_time >= ?", *query.MinPrepTime)
+	}
+	if query.MaxPrepTime != nil {
+		db = db.Where("recipe_documents.prep_time <= ?", *query.MaxPrepTime)
+	}
+
+	return db
+}
+
+// textQuery combines the words and phrases of a query into a single tsquery expression
+func (r SearchRepository) textQuery(query m.SearchQuery) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+
+	for _, term := range query.Terms {
+		parts = append(parts, "phraseto_tsquery(?::regconfig, ?)")
+		args = append(args, r.language, term)
+	}
+
+	for _, term := range query.Excluded {
+		parts = append(parts, "!!phraseto_tsquery(?::regconfig, ?)")
+		args = append(args, r.language, term)
+	}
+
+	return strings.Join(parts, " && "), args
+}
+
+func lower(names []string) []string {
+	lowered := make([]string, 0, len(names))
+	for _, name := range names {
+		lowered = append(lowered, strings.ToLower(name))
+	}
+
+	return lowered
+}
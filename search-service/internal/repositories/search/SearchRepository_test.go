@@ -0,0 +1,420 @@
+package repositories
+
+import (
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	m "search-service/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	co "search-service/internal/common/test"
+)
+
+var (
+	id       uuid.UUID        = uuid.New()
+	document m.RecipeDocument = m.RecipeDocument{
+		RecipeID:     id,
+		Name:         "Creamy chicken pasta",
+		Description:  "weeknight dinner",
+		Ingredients:  m.Terms{"chicken", "penne", "cream"},
+		Instructions: m.Terms{"boil the pasta", "fry the chicken"},
+		Tags:         m.Terms{"quick"},
+		Categories:   m.Terms{"dinner"},
+		CuisineType:  "italian",
+		Difficulty:   intPointer(2),
+		PrepTime:     intPointer(45),
+		Terms: []m.RecipeTerm{
+			{RecipeID: id, Type: m.TermRecipe, EntityID: id, Name: "Creamy chicken pasta"},
+			{RecipeID: id, Type: m.TermIngredient, EntityID: ingredientID, Name: "chicken"},
+		},
+	}
+	ingredientID  uuid.UUID       = uuid.New()
+	searchRequest m.SearchRequest = m.SearchRequest{
+		Query:  m.SearchQuery{Terms: []string{"chicken", "creamy pasta"}, Excluded: []string{"peanut"}},
+		Limit:  20,
+		Offset: 0,
+	}
+)
+
+var (
+	textArgs []driver.Value = []driver.Value{"english", "chicken", "english", "creamy pasta", "english", "peanut"}
+)
+
+const (
+	countQuery   = `SELECT count(*) FROM recipe_documents, (SELECT phraseto_tsquery($1::regconfig, $2) && phraseto_tsquery($3::regconfig, $4) && !!phraseto_tsquery($5::regconfig, $6) AS query) search WHERE recipe_documents.document @@ search.query`
+	suggestQuery = `SELECT recipe_terms.type, recipe_terms.entity_id, recipe_terms.name, count(recipe_terms.recipe_id) AS recipes, max(word_similarity($1, recipe_terms.name) + CASE WHEN recipe_terms.name ILIKE $2 THEN 1 ELSE 0 END) AS score FROM "recipe_terms" WHERE (recipe_terms.name ILIKE $3 OR $4 <% recipe_terms.name) AND recipe_terms.type IN ($5,$6) GROUP BY recipe_terms.type, recipe_terms.entity_id, recipe_terms.name ORDER BY score DESC,recipes DESC,recipe_terms.name LIMIT $7`
+	searchQuery  = `SELECT recipe_documents.recipe_id, recipe_documents.name, recipe_documents.description, recipe_documents.ingredients, recipe_documents.tags, recipe_documents.categories, recipe_documents.cuisine_type, recipe_documents.difficulty, recipe_documents.prep_time, recipe_documents.indexed_at, ts_rank(recipe_documents.document, search.query) AS rank FROM recipe_documents, (SELECT phraseto_tsquery($1::regconfig, $2) && phraseto_tsquery($3::regconfig, $4) && !!phraseto_tsquery($5::regconfig, $6) AS query) search WHERE recipe_documents.document @@ search.query ORDER BY rank DESC,recipe_documents.name,recipe_documents.recipe_id LIMIT $7`
+)
+
+func TestSave_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_documents" ("recipe_id","name","description","ingredients","instructions","tags","categories","cuisine_type","difficulty","prep_time","indexed_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11) ON CONFLICT ("recipe_id") DO UPDATE SET "indexed_at"=$12,"name"="excluded"."name","description"="excluded"."description","ingredients"="excluded"."ingredients","instructions"="excluded"."instructions","tags"="excluded"."tags","categories"="excluded"."categories","cuisine_type"="excluded"."cuisine_type","difficulty"="excluded"."difficulty","prep_time"="excluded"."prep_time"`)).
+		WithArgs(id, document.Name, document.Description, `["chicken","penne","cream"]`, `["boil the pasta","fry the chicken"]`, `["quick"]`, `["dinner"]`, "italian", 2, 45, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE recipe_documents SET document = setweight(to_tsvector($1::regconfig, $2), 'A') || setweight(to_tsvector($3::regconfig, $4), 'B') || setweight(to_tsvector($5::regconfig, $6), 'C') || setweight(to_tsvector($7::regconfig, $8), 'D') WHERE recipe_id = $9`)).
+		WithArgs(
+			"english", "Creamy chicken pasta",
+			"english", "quick dinner italian",
+			"english", "weeknight dinner chicken penne cream",
+			"english", "boil the pasta fry the chicken",
+			id,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_terms" WHERE recipe_id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_terms" ("recipe_id","type","entity_id","name") VALUES ($1,$2,$3,$4),($5,$6,$7,$8)`)).
+		WithArgs(id, "recipe", id, "Creamy chicken pasta", id, "ingredient", ingredientID, "chicken").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	result, err := r.Save(document)
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, result.RecipeID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSave_Err(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_documents"`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE recipe_documents SET document`)).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	result, err := r.Save(document)
+
+	assert.EqualError(t, err, "error")
+	assert.Equal(t, m.RecipeDocument{}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveRebuild_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_documents_rebuild" ("recipe_id","name","description","ingredients","instructions","tags","categories","cuisine_type","difficulty","prep_time","indexed_at") VALUES`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE recipe_documents_rebuild SET document = setweight(to_tsvector($1::regconfig, $2), 'A')`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_terms_rebuild" WHERE recipe_id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "recipe_terms_rebuild" ("recipe_id","type","entity_id","name") VALUES ($1,$2,$3,$4),($5,$6,$7,$8)`)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	result, err := r.SaveRebuild(document)
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, result.RecipeID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPrepareRebuild_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectExec(regexp.QuoteMeta(`DROP TABLE IF EXISTS recipe_terms_rebuild, recipe_documents_rebuild`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE recipe_documents_rebuild (LIKE recipe_documents INCLUDING ALL)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE recipe_terms_rebuild (LIKE recipe_terms INCLUDING ALL)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, r.PrepareRebuild())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPrepareRebuild_Err(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectExec(regexp.QuoteMeta(`DROP TABLE IF EXISTS recipe_terms_rebuild, recipe_documents_rebuild`)).
+		WillReturnError(errors.New("error"))
+
+	assert.EqualError(t, r.PrepareRebuild(), "error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSwapRebuild_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+	keptID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_documents_rebuild SELECT * FROM recipe_documents WHERE recipe_id IN ($1) ON CONFLICT DO NOTHING`)).
+		WithArgs(keptID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_terms_rebuild SELECT * FROM recipe_terms WHERE recipe_id IN ($1) ON CONFLICT DO NOTHING`)).
+		WithArgs(keptID).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM recipe_documents`)).
+		WillReturnResult(sqlmock.NewResult(0, 12))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_documents SELECT * FROM recipe_documents_rebuild`)).
+		WillReturnResult(sqlmock.NewResult(0, 12))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_terms SELECT * FROM recipe_terms_rebuild`)).
+		WillReturnResult(sqlmock.NewResult(0, 40))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta(`DROP TABLE IF EXISTS recipe_terms_rebuild, recipe_documents_rebuild`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, r.SwapRebuild([]uuid.UUID{keptID}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSwapRebuild_Err(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM recipe_documents`)).
+		WillReturnResult(sqlmock.NewResult(0, 12))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_documents SELECT * FROM recipe_documents_rebuild`)).
+		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+
+	assert.EqualError(t, r.SwapRebuild(nil), "error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindRecipesByTerm_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "recipe_id" FROM "recipe_terms" WHERE type = $1 AND entity_id = $2 ORDER BY recipe_id`)).
+		WithArgs(m.TermIngredient, ingredientID).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(id))
+
+	result, err := r.FindRecipesByTerm(m.TermIngredient, ingredientID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{id}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindRecipesByTerm_Err(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "recipe_id" FROM "recipe_terms"`)).
+		WillReturnError(errors.New("error"))
+
+	_, err := r.FindRecipesByTerm(m.TermTag, ingredientID)
+
+	assert.EqualError(t, err, "error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_documents" WHERE recipe_id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := r.Delete(id)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "recipe_documents" WHERE recipe_id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := r.Delete(id)
+
+	assert.EqualError(t, err, "not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearch_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectQuery(regexp.QuoteMeta(countQuery)).
+		WithArgs(textArgs...).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(searchQuery)).
+		WithArgs(append(textArgs, searchRequest.Limit)...).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "name", "description", "ingredients", "tags", "categories", "cuisine_type", "difficulty", "prep_time", "indexed_at", "rank"}).
+			AddRow(id, document.Name, document.Description, `["chicken","penne","cream"]`, `["quick"]`, `["dinner"]`, "italian", 2, nil, time.Now(), 0.6))
+
+	result, err := r.Search(searchRequest)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.Total)
+	assert.Len(t, result.Hits, 1)
+	assert.Equal(t, id, result.Hits[0].RecipeID)
+	assert.Equal(t, m.Terms{"chicken", "penne", "cream"}, result.Hits[0].Ingredients)
+	assert.Equal(t, 0.6, result.Hits[0].Rank)
+	assert.Equal(t, 2, *result.Hits[0].Difficulty)
+	assert.Nil(t, result.Hits[0].PrepTime)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearch_Offset(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectQuery(regexp.QuoteMeta(countQuery)).
+		WithArgs(textArgs...).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(25))
+	mock.ExpectQuery(regexp.QuoteMeta(searchQuery + ` OFFSET $8`)).
+		WithArgs(append(textArgs, 20, 20)...).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "name", "rank"}))
+
+	result, err := r.Search(m.SearchRequest{Query: searchRequest.Query, Limit: 20, Offset: 20})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(25), result.Total)
+	assert.Len(t, result.Hits, 0)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearch_Filters(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	query := m.SearchQuery{
+		Tags:                []string{"Weeknight"},
+		ExcludedTags:        []string{"spicy"},
+		Categories:          []string{"dinner"},
+		ExcludedCategories:  []string{"dessert"},
+		Ingredients:         []string{"chicken"},
+		ExcludedIngredients: []string{"pea_nut"},
+		Cuisines:            []string{"Thai", "Vietnamese"},
+		ExcludedCuisines:    []string{"French"},
+		MinDifficulty:       intPointer(1),
+		MaxDifficulty:       intPointer(2),
+		MinPrepTime:         intPointer(10),
+		MaxPrepTime:         intPointer(29),
+	}
+	filters := `FROM "recipe_documents" WHERE ` +
+		`(EXISTS (SELECT 1 FROM recipe_terms WHERE recipe_terms.recipe_id = recipe_documents.recipe_id AND recipe_terms.type = $1 AND lower(recipe_terms.name) = lower($2))) AND ` +
+		`(NOT EXISTS (SELECT 1 FROM recipe_terms WHERE recipe_terms.recipe_id = recipe_documents.recipe_id AND recipe_terms.type = $3 AND lower(recipe_terms.name) = lower($4))) AND ` +
+		`(EXISTS (SELECT 1 FROM recipe_terms WHERE recipe_terms.recipe_id = recipe_documents.recipe_id AND recipe_terms.type = $5 AND lower(recipe_terms.name) = lower($6))) AND ` +
+		`(NOT EXISTS (SELECT 1 FROM recipe_terms WHERE recipe_terms.recipe_id = recipe_documents.recipe_id AND recipe_terms.type = $7 AND lower(recipe_terms.name) = lower($8))) AND ` +
+		`(EXISTS (SELECT 1 FROM recipe_terms WHERE recipe_terms.recipe_id = recipe_documents.recipe_id AND recipe_terms.type = 'ingredient' AND recipe_terms.name ILIKE $9)) AND ` +
+		`(NOT EXISTS (SELECT 1 FROM recipe_terms WHERE recipe_terms.recipe_id = recipe_documents.recipe_id AND recipe_terms.type = 'ingredient' AND recipe_terms.name ILIKE $10)) AND ` +
+		`lower(recipe_documents.cuisine_type) IN ($11,$12) AND lower(recipe_documents.cuisine_type) NOT IN ($13) AND ` +
+		`recipe_documents.difficulty >= $14 AND recipe_documents.difficulty <= $15 AND recipe_documents.prep_time >= $16 AND recipe_documents.prep_time <= $17`
+	args := []driver.Value{"tag", "Weeknight", "tag", "spicy", "category", "dinner", "category", "dessert", "%chicken%", `%pea\_nut%`, "thai", "vietnamese", "french", 1, 2, 10, 29}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) ` + filters)).
+		WithArgs(args...).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT ` + documentColumns + `, 0 AS rank ` + filters + ` ORDER BY rank DESC,recipe_documents.name,recipe_documents.recipe_id LIMIT $18`)).
+		WithArgs(append(args, 20)...).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "name", "rank"}).AddRow(id, document.Name, 0))
+
+	result, err := r.Search(m.SearchRequest{Query: query, Limit: 20})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.Total)
+	assert.Len(t, result.Hits, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearch_CountErr(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectQuery(regexp.QuoteMeta(countQuery)).
+		WithArgs(textArgs...).
+		WillReturnError(errors.New("error"))
+
+	result, err := r.Search(searchRequest)
+
+	assert.EqualError(t, err, "error")
+	assert.Equal(t, m.SearchResult{}, result)
+}
+
+func TestSearch_Err(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectQuery(regexp.QuoteMeta(countQuery)).
+		WithArgs(textArgs...).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(searchQuery)).
+		WithArgs(append(textArgs, searchRequest.Limit)...).
+		WillReturnError(errors.New("error"))
+
+	result, err := r.Search(searchRequest)
+
+	assert.EqualError(t, err, "error")
+	assert.Equal(t, m.SearchResult{}, result)
+}
+
+func TestSuggest_OK(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectQuery(regexp.QuoteMeta(suggestQuery)).
+		WithArgs("zuchini", "zuchini%", "zuchini%", "zuchini", "ingredient", "tag", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "entity_id", "name", "recipes", "score"}).
+			AddRow("ingredient", ingredientID, "zucchini", 4, 0.875))
+
+	result, err := r.Suggest(m.SuggestionRequest{Query: "zuchini", Types: []string{"ingredient", "tag"}, Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []m.Suggestion{{Type: "ingredient", EntityID: ingredientID, Name: "zucchini", Recipes: 4, Score: 0.875}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSuggest_Escaped(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectQuery(regexp.QuoteMeta(suggestQuery)).
+		WithArgs(`100%_`, `100\%\_%`, `100\%\_%`, `100%_`, "recipe", "category", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "entity_id", "name", "recipes", "score"}))
+
+	result, err := r.Suggest(m.SuggestionRequest{Query: `100%_`, Types: []string{"recipe", "category"}, Limit: 5})
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 0)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSuggest_Err(t *testing.T) {
+	db, mock := co.NewMockDatabase(t)
+	r := NewSearchRepository(db, "english")
+
+	mock.ExpectQuery(regexp.QuoteMeta(suggestQuery)).
+		WillReturnError(errors.New("error"))
+
+	result, err := r.Suggest(m.SuggestionRequest{Query: "zuc", Types: []string{"recipe", "category"}, Limit: 5})
+
+	assert.EqualError(t, err, "error")
+	assert.Nil(t, result)
+}
+
+func intPointer(i int) *int {
+	return &i
+}
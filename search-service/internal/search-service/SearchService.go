@@ -0,0 +1,95 @@
+package searchservice
+
+import (
+	"context"
+	"net/http"
+	c "search-service/internal/config"
+	m "search-service/internal/middleware"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/tbaehler/gin-keycloak/pkg/ginkeycloak"
+)
+
+var (
+	log = c.Logger
+)
+
+func SearchService(ctx context.Context) {
+	router := gin.New()
+	gin.SetMode(gin.ReleaseMode)
+
+	// Logging
+	router.Use(m.Logger(log))
+
+	// Panic recovery
+	router.Use(gin.Recovery())
+
+	// Cors handler
+	router.Use(cors.New(c.Cors))
+
+	// API versioning setup
+	v1 := router.Group("/api/v2")
+	{
+		search := v1.Group("/search")
+		{
+			readSearch := search.Group("")
+			readSearch.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
+			{
+				readSearch.GET("recipes", c.SearchHandlers.Search)
+				readSearch.GET("suggest", c.SearchHandlers.Suggest)
+				readSearch.GET("parse", c.SearchHandlers.Parse)
+			}
+
+			adminSearch := search.Group("")
+			adminSearch.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
+			{
+				adminSearch.POST("recipes/index", c.IndexHandlers.IndexAll)
+				adminSearch.PUT("recipes/:id", c.IndexHandlers.Index)
+				adminSearch.DELETE("recipes/:id", c.IndexHandlers.Remove)
+				adminSearch.GET("feed/all", c.SavedSearchHandlers.FeedAll)
+				adminSearch.POST("reindex", c.IndexHandlers.Reindex)
+				adminSearch.GET("reindex", c.IndexHandlers.ReindexStatus)
+				adminSearch.POST("events", c.EventHandlers.Handle)
+			}
+
+			savedSearch := search.Group("")
+			savedSearch.Use(ginkeycloak.NewAccessBuilder(ginkeycloak.BuilderConfig(c.Configuration.Oauth)).RestrictButForRole("administrator").Build())
+			{
+				savedSearch.GET("saved", c.SavedSearchHandlers.GetAll)
+				savedSearch.POST("saved", c.SavedSearchHandlers.Create)
+				savedSearch.GET("saved/:id", c.SavedSearchHandlers.Get)
+				savedSearch.PUT("saved/:id", c.SavedSearchHandlers.Update)
+				savedSearch.DELETE("saved/:id", c.SavedSearchHandlers.Delete)
+				savedSearch.GET("feed", c.SavedSearchHandlers.Feed)
+			}
+		}
+	}
+
+	// Saved searches are evaluated against created and updated recipes in the background
+	go c.SavedSearchEvaluator.Run(ctx)
+
+	// Changes published to the event broker are consumed in the background
+	if c.EventSubscriber != nil {
+		go c.EventSubscriber.Run(ctx, c.EventHandlers.Consume)
+	}
+
+	// Server startup
+	srv := &http.Server{
+		Handler:      router,
+		Addr:         ":8080",
+		WriteTimeout: 300 * time.Second,
+		ReadTimeout:  15 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(ctx)
+	}()
+
+	log.Info("search service available on port 8080")
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		log.Error(err)
+	}
+}
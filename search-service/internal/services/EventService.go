@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	m "search-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type RecipeIndexer interface {
+	Index(ctx context.Context, recipeID uuid.UUID) (m.RecipeDocumentDTO, error)
+	Remove(recipeID uuid.UUID) error
+}
+
+type TermRepository interface {
+	FindRecipesByTerm(termType string, entityID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// the entities shared by recipes, by the type of term they are recorded as in the documents of those recipes
+var entityTerms = map[string]string{
+	m.EntityIngredient:      m.TermIngredient,
+	m.EntityTag:             m.TermTag,
+	m.EntityCategory:        m.TermCategory,
+	m.EntityCuisineType:     m.TermCuisineType,
+	m.EntityDifficultyLevel: m.TermDifficultyLevel,
+	m.EntityPreparationTime: m.TermPreparationTime,
+}
+
+// EventService updates the index on the changes reported by the other services
+type EventService struct {
+	indexer RecipeIndexer
+	repo    TermRepository
+	logger  m.LoggerInterface
+}
+
+// NewEventService creates a new EventService instance
+func NewEventService(indexer RecipeIndexer, repo TermRepository, logger m.LoggerInterface) *EventService {
+	return &EventService{
+		indexer: indexer,
+		repo:    repo,
+		logger:  logger,
+	}
+}
+
+// Handle reindexes the recipes affected by a change. A change to a recipe, or to its ingredients, instructions or metadata,
+// affects that recipe only. A change to an entity shared by recipes, such as a tag, affects all recipes using it.
+func (s EventService) Handle(ctx context.Context, eventDTO m.ChangeEventDTO) (m.IndexReportDTO, error) {
+	report := m.IndexReportDTO{
+		Failed: []uuid.UUID{},
+	}
+
+	switch eventDTO.Type {
+	case m.EventCreated, m.EventUpdated, m.EventDeleted:
+	default:
+		return m.IndexReportDTO{}, errors.New("invalid event type")
+	}
+
+	if eventDTO.EntityID == uuid.Nil {
+		return m.IndexReportDTO{}, errors.New("invalid entity ID")
+	}
+
+	var recipeIDs []uuid.UUID
+
+	switch eventDTO.EntityType {
+	case m.EntityRecipe:
+		if eventDTO.Type == m.EventDeleted {
+			if err := s.indexer.Remove(eventDTO.EntityID); err != nil && err.Error() != "not found" {
+				return m.IndexReportDTO{}, err
+			}
+			return report, nil
+		}
+		recipeIDs = []uuid.UUID{eventDTO.EntityID}
+	case m.EntityRecipeIngredients, m.EntityRecipeInstructions, m.EntityRecipeMetadata:
+		recipeIDs = []uuid.UUID{eventDTO.EntityID}
+	default:
+		termType, found := entityTerms[eventDTO.EntityType]
+		if !found {
+			return m.IndexReportDTO{}, errors.New("invalid entity type")
+		}
+
+		// no recipe uses an entity that was just created
+		if eventDTO.Type == m.EventCreated {
+			return report, nil
+		}
+
+		var err error
+		if recipeIDs, err = s.repo.FindRecipesByTerm(termType, eventDTO.EntityID); err != nil {
+			s.logger.Errorf("unable to retrieve the recipes using %s %s: %v", eventDTO.EntityType, eventDTO.EntityID, err)
+			return m.IndexReportDTO{}, errors.New("internal server error")
+		}
+	}
+
+	s.logger.Debugf("%s %s %s, reindexing %d recipes", eventDTO.EntityType, eventDTO.EntityID, eventDTO.Type, len(recipeIDs))
+
+	for _, recipeID := range recipeIDs {
+		if _, err := s.indexer.Index(ctx, recipeID); err != nil {
+			// a recipe that no longer exists has been removed from the index
+			if err.Error() == "not found" {
+				continue
+			}
+			report.Failed = append(report.Failed, recipeID)
+			continue
+		}
+
+		report.Indexed++
+	}
+
+	return report, nil
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	m "search-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	tagID          uuid.UUID = uuid.New()
+	goneRecipeID   uuid.UUID = uuid.New()
+	brokenRecipeID uuid.UUID = uuid.New()
+)
+
+type RecipeIndexerMock struct {
+	indexed []uuid.UUID
+	removed []uuid.UUID
+}
+
+func (i *RecipeIndexerMock) Index(ctx context.Context, recipeID uuid.UUID) (m.RecipeDocumentDTO, error) {
+	switch recipeID {
+	case goneRecipeID:
+		return m.RecipeDocumentDTO{}, errors.New("not found")
+	case brokenRecipeID:
+		return m.RecipeDocumentDTO{}, errors.New("recipe incomplete")
+	default:
+		i.indexed = append(i.indexed, recipeID)
+		return m.RecipeDocumentDTO{RecipeID: recipeID}, nil
+	}
+}
+
+func (i *RecipeIndexerMock) Remove(recipeID uuid.UUID) error {
+	switch mode {
+	case "notindexed":
+		return errors.New("not found")
+	case "remove_error":
+		return errors.New("internal server error")
+	default:
+		i.removed = append(i.removed, recipeID)
+		return nil
+	}
+}
+
+type TermRepositoryMock struct {
+	termType string
+}
+
+func (r *TermRepositoryMock) FindRecipesByTerm(termType string, entityID uuid.UUID) ([]uuid.UUID, error) {
+	r.termType = termType
+
+	switch mode {
+	case "terms_error":
+		return nil, errors.New("error")
+	default:
+		return []uuid.UUID{recipeID, goneRecipeID, brokenRecipeID}, nil
+	}
+}
+
+func changeEvent(eventType string, entityType string, entityID uuid.UUID) m.ChangeEventDTO {
+	return m.ChangeEventDTO{Type: eventType, EntityType: entityType, EntityID: entityID}
+}
+
+// ====== Tests ======
+
+func TestHandle_Recipe(t *testing.T) {
+	for _, entityType := range []string{m.EntityRecipe, m.EntityRecipeIngredients, m.EntityRecipeInstructions, m.EntityRecipeMetadata} {
+		indexer := &RecipeIndexerMock{}
+		s := NewEventService(indexer, &TermRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+		mode = "event"
+
+		report, err := s.Handle(context.Background(), changeEvent(m.EventUpdated, entityType, recipeID))
+
+		assert.NoError(t, err, entityType)
+		assert.Equal(t, m.IndexReportDTO{Indexed: 1, Failed: []uuid.UUID{}}, report, entityType)
+		assert.Equal(t, []uuid.UUID{recipeID}, indexer.indexed, entityType)
+	}
+}
+
+func TestHandle_RecipeDeleted(t *testing.T) {
+	for _, scenario := range []string{"event", "notindexed"} {
+		indexer := &RecipeIndexerMock{}
+		s := NewEventService(indexer, &TermRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+		mode = scenario
+
+		report, err := s.Handle(context.Background(), changeEvent(m.EventDeleted, m.EntityRecipe, recipeID))
+
+		assert.NoError(t, err, scenario)
+		assert.Equal(t, 0, report.Indexed, scenario)
+		assert.Len(t, indexer.indexed, 0, scenario)
+	}
+}
+
+func TestHandle_SharedEntity(t *testing.T) {
+	for entityType, termType := range map[string]string{
+		m.EntityIngredient:      m.TermIngredient,
+		m.EntityTag:             m.TermTag,
+		m.EntityCategory:        m.TermCategory,
+		m.EntityCuisineType:     m.TermCuisineType,
+		m.EntityDifficultyLevel: m.TermDifficultyLevel,
+		m.EntityPreparationTime: m.TermPreparationTime,
+	} {
+		indexer := &RecipeIndexerMock{}
+		repo := &TermRepositoryMock{}
+		s := NewEventService(indexer, repo, &m.LoggerInterfaceMock{})
+
+		mode = "event"
+
+		report, err := s.Handle(context.Background(), changeEvent(m.EventUpdated, entityType, tagID))
+
+		assert.NoError(t, err, entityType)
+		assert.Equal(t, termType, repo.termType, entityType)
+		assert.Equal(t, m.IndexReportDTO{Indexed: 1, Failed: []uuid.UUID{brokenRecipeID}}, report, entityType)
+		assert.Equal(t, []uuid.UUID{recipeID}, indexer.indexed, entityType)
+	}
+}
+
+func TestHandle_SharedEntityCreated(t *testing.T) {
+	indexer := &RecipeIndexerMock{}
+	repo := &TermRepositoryMock{}
+	s := NewEventService(indexer, repo, &m.LoggerInterfaceMock{})
+
+	mode = "event"
+
+	report, err := s.Handle(context.Background(), changeEvent(m.EventCreated, m.EntityTag, tagID))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, report.Indexed)
+	assert.Equal(t, "", repo.termType)
+	assert.Len(t, indexer.indexed, 0)
+}
+
+func TestHandle_Err(t *testing.T) {
+	for _, test := range []struct {
+		mode     string
+		event    m.ChangeEventDTO
+		expected string
+	}{
+		{"event", changeEvent("renamed", m.EntityTag, tagID), "invalid event type"},
+		{"event", changeEvent(m.EventUpdated, "unit", tagID), "invalid entity type"},
+		{"event", changeEvent(m.EventUpdated, m.EntityTag, uuid.Nil), "invalid entity ID"},
+		{"terms_error", changeEvent(m.EventDeleted, m.EntityTag, tagID), "internal server error"},
+		{"remove_error", changeEvent(m.EventDeleted, m.EntityRecipe, recipeID), "internal server error"},
+	} {
+		s := NewEventService(&RecipeIndexerMock{}, &TermRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+		mode = test.mode
+
+		_, err := s.Handle(context.Background(), test.event)
+
+		assert.EqualError(t, err, test.expected, test.expected)
+	}
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	m "search-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type RecipeClient interface {
+	GetRecipes(ctx context.Context) ([]m.RecipeDTO, error)
+	GetFullRecipe(ctx context.Context, recipeID uuid.UUID) (m.FullRecipeDTO, error)
+}
+
+type IndexRepository interface {
+	Save(document m.RecipeDocument) (m.RecipeDocument, error)
+	Delete(recipeID uuid.UUID) error
+	PrepareRebuild() error
+	SaveRebuild(document m.RecipeDocument) (m.RecipeDocument, error)
+	SwapRebuild(keep []uuid.UUID) error
+}
+
+// reindexProgressInterval is the number of recipes after which the progress of a rebuild is logged
+const reindexProgressInterval = 100
+
+// MatchEvaluator is told about recipes that were created or updated, to find the saved searches they newly match
+type MatchEvaluator interface {
+	Enqueue(recipeID uuid.UUID)
+}
+
+// IndexService keeps the search index in line with the recipes held by the recipe service
+type IndexService struct {
+	client    RecipeClient
+	repo      IndexRepository
+	evaluator MatchEvaluator
+	rebuild   *rebuild
+	logger    m.LoggerInterface
+}
+
+// NewIndexService creates a new IndexService instance
+func NewIndexService(client RecipeClient, repo IndexRepository, evaluator MatchEvaluator, logger m.LoggerInterface) *IndexService {
+	return &IndexService{
+		client:    client,
+		repo:      repo,
+		evaluator: evaluator,
+		rebuild:   &rebuild{status: m.ReindexStatusDTO{State: m.ReindexIdle, Failed: []uuid.UUID{}}},
+		logger:    logger,
+	}
+}
+
+// Index retrieves the current state of a recipe and (re)places it in the index. A recipe that no longer exists is removed from it.
+// The saved searches are evaluated against the indexed recipe afterwards.
+func (s IndexService) Index(ctx context.Context, recipeID uuid.UUID) (m.RecipeDocumentDTO, error) {
+	documentDTO, err := s.index(ctx, recipeID)
+	if err != nil {
+		return m.RecipeDocumentDTO{}, err
+	}
+
+	s.evaluator.Enqueue(recipeID)
+
+	return documentDTO, nil
+}
+
+func (s IndexService) index(ctx context.Context, recipeID uuid.UUID) (m.RecipeDocumentDTO, error) {
+	s.rebuild.touch(recipeID)
+
+	document, err := s.document(ctx, recipeID)
+	if err != nil {
+		if err.Error() == "not found" {
+			if err := s.Remove(recipeID); err != nil && err.Error() != "not found" {
+				return m.RecipeDocumentDTO{}, err
+			}
+		}
+		return m.RecipeDocumentDTO{}, err
+	}
+
+	document, err = s.repo.Save(document)
+	if err != nil {
+		s.logger.Errorf("unable to index recipe %s: %v", recipeID, err)
+		return m.RecipeDocumentDTO{}, errors.New("internal server error")
+	}
+
+	return document.ConvertToDTO(), nil
+}
+
+// document retrieves the current state of a recipe and flattens it into a document
+func (s IndexService) document(ctx context.Context, recipeID uuid.UUID) (m.RecipeDocument, error) {
+	recipe, err := s.client.GetFullRecipe(ctx, recipeID)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return m.RecipeDocument{}, err
+		default:
+			s.logger.Errorf("unable to retrieve recipe %s: %v", recipeID, err)
+			return m.RecipeDocument{}, errors.New("recipe service unavailable")
+		}
+	}
+
+	// indexing a partial recipe would make it disappear from searches on the missing parts, so keep the previous document instead
+	if len(recipe.Errors) > 0 {
+		s.logger.Warnf("recipe %s is incomplete: %v", recipeID, recipe.Errors)
+		return m.RecipeDocument{}, errors.New("recipe incomplete")
+	}
+
+	return m.NewRecipeDocument(recipe), nil
+}
+
+// Remove takes a recipe out of the index
+func (s IndexService) Remove(recipeID uuid.UUID) error {
+	s.rebuild.touch(recipeID)
+
+	if err := s.repo.Delete(recipeID); err != nil {
+		switch err.Error() {
+		case "not found":
+			return err
+		default:
+			s.logger.Errorf("unable to remove recipe %s from the index: %v", recipeID, err)
+			return errors.New("internal server error")
+		}
+	}
+
+	return nil
+}
+
+// IndexAll indexes every recipe known to the recipe service. Recipes that fail are reported, they do not stop the run.
+// As nothing changed about the recipes themselves, the saved searches are not evaluated.
+func (s IndexService) IndexAll(ctx context.Context) (m.IndexReportDTO, error) {
+	report := m.IndexReportDTO{
+		Failed: []uuid.UUID{},
+	}
+
+	recipes, err := s.client.GetRecipes(ctx)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return report, nil
+		default:
+			s.logger.Errorf("unable to retrieve recipes: %v", err)
+			return m.IndexReportDTO{}, errors.New("recipe service unavailable")
+		}
+	}
+
+	for _, recipe := range recipes {
+		if _, err := s.index(ctx, recipe.ID); err != nil {
+			report.Failed = append(report.Failed, recipe.ID)
+			continue
+		}
+
+		report.Indexed++
+	}
+
+	return report, nil
+}
+
+// Reindex rebuilds the index from scratch and reports how it went. See StartReindex for how searches are affected.
+func (s IndexService) Reindex(ctx context.Context) (m.ReindexStatusDTO, error) {
+	if !s.rebuild.start() {
+		return s.rebuild.snapshot(), errors.New("reindex already running")
+	}
+
+	err := s.reindex(ctx)
+
+	return s.rebuild.snapshot(), err
+}
+
+// StartReindex rebuilds the index from scratch in the background, its progress is reported by ReindexStatus.
+// The recipes are indexed next to the current index, which is replaced once all of them are done, so searches keep working meanwhile.
+// Recipes that fail keep their current document. As nothing changed about the recipes themselves, the saved searches are not evaluated.
+func (s IndexService) StartReindex() (m.ReindexStatusDTO, error) {
+	if !s.rebuild.start() {
+		return s.rebuild.snapshot(), errors.New("reindex already running")
+	}
+
+	// the rebuild outlives the request that started it
+	go s.reindex(context.Background())
+
+	return s.rebuild.snapshot(), nil
+}
+
+// ReindexStatus reports the progress of the running or last rebuild of the index
+func (s IndexService) ReindexStatus() m.ReindexStatusDTO {
+	return s.rebuild.snapshot()
+}
+
+func (s IndexService) reindex(ctx context.Context) error {
+	if err := s.repo.PrepareRebuild(); err != nil {
+		s.logger.Errorf("unable to prepare the rebuild of the index: %v", err)
+		return s.rebuild.finish(errors.New("internal server error"))
+	}
+
+	recipes, err := s.client.GetRecipes(ctx)
+	if err != nil && err.Error() != "not found" {
+		s.logger.Errorf("unable to retrieve recipes: %v", err)
+		return s.rebuild.finish(errors.New("recipe service unavailable"))
+	}
+
+	s.rebuild.setTotal(len(recipes))
+	s.logger.Infof("rebuilding the index of %d recipes", len(recipes))
+
+	for i, recipe := range recipes {
+		if err := s.reindexRecipe(ctx, recipe.ID); err != nil {
+			s.rebuild.fail(recipe.ID)
+		} else {
+			s.rebuild.index()
+		}
+
+		if (i+1)%reindexProgressInterval == 0 {
+			status := s.rebuild.snapshot()
+			s.logger.Infof("rebuilt %d of %d recipes, %d failed", status.Indexed, status.Total, len(status.Failed))
+		}
+	}
+
+	if err := s.repo.SwapRebuild(s.rebuild.snapshot().Failed); err != nil {
+		s.logger.Errorf("unable to replace the index: %v", err)
+		return s.rebuild.finish(errors.New("internal server error"))
+	}
+
+	// the recipes that changed while the index was rebuilt may have been rebuilt from their previous state
+	for _, recipeID := range s.rebuild.untouch() {
+		if _, err := s.index(ctx, recipeID); err != nil && err.Error() != "not found" {
+			s.logger.Warnf("unable to reindex recipe %s that changed during the rebuild: %v", recipeID, err)
+		}
+	}
+
+	status := s.rebuild.snapshot()
+	s.logger.Infof("rebuilt the index of %d recipes, %d failed", status.Indexed, len(status.Failed))
+
+	return s.rebuild.finish(nil)
+}
+
+// reindexRecipe places a recipe in the index being rebuilt. A recipe removed since the recipes were listed is skipped.
+func (s IndexService) reindexRecipe(ctx context.Context, recipeID uuid.UUID) error {
+	document, err := s.document(ctx, recipeID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil
+		}
+		return err
+	}
+
+	if _, err := s.repo.SaveRebuild(document); err != nil {
+		s.logger.Errorf("unable to rebuild recipe %s: %v", recipeID, err)
+		return errors.New("internal server error")
+	}
+
+	return nil
+}
+
+// rebuild keeps track of the progress of a rebuild, and of the recipes changed while it runs. It is shared by all copies of the IndexService.
+type rebuild struct {
+	mutex   sync.Mutex
+	status  m.ReindexStatusDTO
+	touched map[uuid.UUID]bool
+}
+
+// start claims the rebuild, it reports false when one is running already
+func (r *rebuild) start() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.status.State == m.ReindexRunning {
+		return false
+	}
+
+	now := time.Now()
+	r.status = m.ReindexStatusDTO{State: m.ReindexRunning, Failed: []uuid.UUID{}, StartedAt: &now}
+	r.touched = map[uuid.UUID]bool{}
+
+	return true
+}
+
+func (r *rebuild) setTotal(total int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.status.Total = total
+}
+
+func (r *rebuild) index() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.status.Indexed++
+}
+
+func (r *rebuild) fail(recipeID uuid.UUID) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.status.Failed = append(r.status.Failed, recipeID)
+}
+
+// touch records a recipe that changed, as long as a rebuild is running
+func (r *rebuild) touch(recipeID uuid.UUID) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.touched != nil {
+		r.touched[recipeID] = true
+	}
+}
+
+// untouch returns the recipes that changed and stops recording them
+func (r *rebuild) untouch() []uuid.UUID {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	recipeIDs := make([]uuid.UUID, 0, len(r.touched))
+	for recipeID := range r.touched {
+		recipeIDs = append(recipeIDs, recipeID)
+	}
+	r.touched = nil
+
+	return recipeIDs
+}
+
+// finish ends the rebuild, failed when an error is given, and returns that error
+func (r *rebuild) finish(err error) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	r.status.FinishedAt = &now
+	r.status.State = m.ReindexFinished
+	r.touched = nil
+
+	if err != nil {
+		r.status.State = m.ReindexFailed
+		r.status.Error = err.Error()
+	}
+
+	return err
+}
+
+func (r *rebuild) snapshot() m.ReindexStatusDTO {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	status := r.status
+	status.Failed = append([]uuid.UUID{}, r.status.Failed...)
+
+	return status
+}
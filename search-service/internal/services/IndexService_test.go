@@ -0,0 +1,387 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	m "search-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	failingID uuid.UUID = uuid.New()
+	chickenID uuid.UUID = uuid.New()
+	quickID   uuid.UUID = uuid.New()
+
+	italianID  uuid.UUID = uuid.New()
+	easyID     uuid.UUID = uuid.New()
+	halfHourID uuid.UUID = uuid.New()
+
+	fullRecipe m.FullRecipeDTO = m.FullRecipeDTO{
+		Recipe:       m.RecipeDTO{ID: recipeID, Name: "Creamy chicken pasta", Description: "weeknight dinner"},
+		Ingredients:  []m.RecipeIngredientDTO{{IngredientID: chickenID, IngredientName: "chicken"}, {IngredientID: chickenID, IngredientName: "chicken"}, {IngredientName: "penne"}},
+		Instructions: []m.InstructionDTO{{Sequence: 1, Description: "boil the pasta"}},
+		Metadata: &m.RecipeMetadataDTO{
+			Categories:      []m.CategoryDTO{{Name: "dinner"}},
+			Tags:            []m.TagDTO{{ID: quickID, Name: "quick"}},
+			CuisineType:     m.CuisineTypeDTO{Name: "italian"},
+			DifficultyLevel: m.DifficultyLevelDTO{Level: 2},
+		},
+	}
+)
+
+type RecipeClientMock struct{}
+
+func (c *RecipeClientMock) GetRecipes(ctx context.Context) ([]m.RecipeDTO, error) {
+	switch mode {
+	case "all_error":
+		return nil, errors.New("error")
+	case "all_notfound":
+		return nil, errors.New("not found")
+	default:
+		return []m.RecipeDTO{{ID: recipeID}, {ID: failingID}}, nil
+	}
+}
+
+func (c *RecipeClientMock) GetFullRecipe(ctx context.Context, id uuid.UUID) (m.FullRecipeDTO, error) {
+	if id == failingID {
+		return m.FullRecipeDTO{}, errors.New("unexpected status code 500")
+	}
+
+	switch mode {
+	case "notfound", "notfound_unindexed":
+		return m.FullRecipeDTO{}, errors.New("not found")
+	case "unavailable":
+		return m.FullRecipeDTO{}, errors.New("unexpected status code 502")
+	case "incomplete":
+		return m.FullRecipeDTO{Recipe: fullRecipe.Recipe, Errors: map[string]string{"ingredients": "unexpected status code 500"}}, nil
+	case "metadata":
+		return m.FullRecipeDTO{Recipe: fullRecipe.Recipe, Metadata: &m.RecipeMetadataDTO{
+			CuisineType:     m.CuisineTypeDTO{ID: italianID, Name: "italian"},
+			DifficultyLevel: m.DifficultyLevelDTO{ID: easyID, Level: 2},
+			PreparationTime: m.PreparationTimeDTO{ID: halfHourID, Duration: 30},
+		}}, nil
+	default:
+		return fullRecipe, nil
+	}
+}
+
+type IndexRepositoryMock struct {
+	saved   []m.RecipeDocument
+	deleted []uuid.UUID
+	rebuilt []m.RecipeDocument
+	kept    []uuid.UUID
+	swapped bool
+
+	// called while a recipe is rebuilt, to change recipes during a rebuild
+	onRebuild func()
+}
+
+func (r *IndexRepositoryMock) Save(document m.RecipeDocument) (m.RecipeDocument, error) {
+	switch mode {
+	case "save_error":
+		return m.RecipeDocument{}, errors.New("error")
+	default:
+		r.saved = append(r.saved, document)
+		return document, nil
+	}
+}
+
+func (r *IndexRepositoryMock) Delete(id uuid.UUID) error {
+	switch mode {
+	case "notfound_unindexed":
+		return errors.New("not found")
+	case "delete_error":
+		return errors.New("error")
+	default:
+		r.deleted = append(r.deleted, id)
+		return nil
+	}
+}
+
+func (r *IndexRepositoryMock) PrepareRebuild() error {
+	switch mode {
+	case "prepare_error":
+		return errors.New("error")
+	default:
+		r.rebuilt = nil
+		return nil
+	}
+}
+
+func (r *IndexRepositoryMock) SaveRebuild(document m.RecipeDocument) (m.RecipeDocument, error) {
+	if r.onRebuild != nil {
+		r.onRebuild()
+	}
+
+	r.rebuilt = append(r.rebuilt, document)
+	return document, nil
+}
+
+func (r *IndexRepositoryMock) SwapRebuild(keep []uuid.UUID) error {
+	switch mode {
+	case "swap_error":
+		return errors.New("error")
+	default:
+		r.kept, r.swapped = keep, true
+		return nil
+	}
+}
+
+type MatchEvaluatorMock struct {
+	enqueued []uuid.UUID
+}
+
+func (e *MatchEvaluatorMock) Enqueue(recipeID uuid.UUID) {
+	e.enqueued = append(e.enqueued, recipeID)
+}
+
+// ====== Tests ======
+
+func TestIndex_OK(t *testing.T) {
+	repo := &IndexRepositoryMock{}
+	evaluator := &MatchEvaluatorMock{}
+	s := NewIndexService(&RecipeClientMock{}, repo, evaluator, &m.LoggerInterfaceMock{})
+	difficulty := 2
+
+	mode = "index"
+
+	result, err := s.Index(context.Background(), recipeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, recipeID, result.RecipeID)
+	assert.Len(t, repo.saved, 1)
+	assert.Equal(t, []uuid.UUID{recipeID}, evaluator.enqueued)
+	assert.Equal(t, m.RecipeDocument{
+		RecipeID:     recipeID,
+		Name:         "Creamy chicken pasta",
+		Description:  "weeknight dinner",
+		Ingredients:  m.Terms{"chicken", "chicken", "penne"},
+		Instructions: m.Terms{"boil the pasta"},
+		Tags:         m.Terms{"quick"},
+		Categories:   m.Terms{"dinner"},
+		CuisineType:  "italian",
+		Difficulty:   &difficulty,
+		Terms: []m.RecipeTerm{
+			{RecipeID: recipeID, Type: m.TermRecipe, EntityID: recipeID, Name: "Creamy chicken pasta"},
+			{RecipeID: recipeID, Type: m.TermIngredient, EntityID: chickenID, Name: "chicken"},
+			{RecipeID: recipeID, Type: m.TermTag, EntityID: quickID, Name: "quick"},
+		},
+	}, repo.saved[0])
+}
+
+func TestIndex_MetadataTerms(t *testing.T) {
+	repo := &IndexRepositoryMock{}
+	s := NewIndexService(&RecipeClientMock{}, repo, &MatchEvaluatorMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "metadata"
+
+	_, err := s.Index(context.Background(), recipeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []m.RecipeTerm{
+		{RecipeID: recipeID, Type: m.TermRecipe, EntityID: recipeID, Name: "Creamy chicken pasta"},
+		{RecipeID: recipeID, Type: m.TermCuisineType, EntityID: italianID, Name: "italian"},
+		{RecipeID: recipeID, Type: m.TermDifficultyLevel, EntityID: easyID, Name: "2"},
+		{RecipeID: recipeID, Type: m.TermPreparationTime, EntityID: halfHourID, Name: "30"},
+	}, repo.saved[0].Terms)
+}
+
+func TestIndex_NotFound(t *testing.T) {
+	for _, scenario := range []string{"notfound", "notfound_unindexed"} {
+		repo := &IndexRepositoryMock{}
+		evaluator := &MatchEvaluatorMock{}
+		s := NewIndexService(&RecipeClientMock{}, repo, evaluator, &m.LoggerInterfaceMock{})
+
+		mode = scenario
+
+		_, err := s.Index(context.Background(), recipeID)
+
+		assert.EqualError(t, err, "not found", scenario)
+		assert.Len(t, repo.saved, 0, scenario)
+	}
+}
+
+func TestIndex_Err(t *testing.T) {
+	for scenario, expected := range map[string]string{
+		"unavailable": "recipe service unavailable",
+		"incomplete":  "recipe incomplete",
+		"save_error":  "internal server error",
+	} {
+		repo := &IndexRepositoryMock{}
+		evaluator := &MatchEvaluatorMock{}
+		s := NewIndexService(&RecipeClientMock{}, repo, evaluator, &m.LoggerInterfaceMock{})
+
+		mode = scenario
+
+		result, err := s.Index(context.Background(), recipeID)
+
+		assert.EqualError(t, err, expected, scenario)
+		assert.Equal(t, m.RecipeDocumentDTO{}, result, scenario)
+		assert.Len(t, repo.saved, 0, scenario)
+		assert.Len(t, evaluator.enqueued, 0, scenario)
+	}
+}
+
+func TestRemove_OK(t *testing.T) {
+	repo := &IndexRepositoryMock{}
+	evaluator := &MatchEvaluatorMock{}
+	s := NewIndexService(&RecipeClientMock{}, repo, evaluator, &m.LoggerInterfaceMock{})
+
+	mode = "remove"
+
+	err := s.Remove(recipeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{recipeID}, repo.deleted)
+}
+
+func TestRemove_Err(t *testing.T) {
+	s := NewIndexService(&RecipeClientMock{}, &IndexRepositoryMock{}, &MatchEvaluatorMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "notfound_unindexed"
+	assert.EqualError(t, s.Remove(recipeID), "not found")
+
+	mode = "delete_error"
+	assert.EqualError(t, s.Remove(recipeID), "internal server error")
+}
+
+func TestIndexAll_OK(t *testing.T) {
+	repo := &IndexRepositoryMock{}
+	evaluator := &MatchEvaluatorMock{}
+	s := NewIndexService(&RecipeClientMock{}, repo, evaluator, &m.LoggerInterfaceMock{})
+
+	mode = "index"
+
+	result, err := s.IndexAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Indexed)
+	assert.Equal(t, []uuid.UUID{failingID}, result.Failed)
+	assert.Len(t, repo.saved, 1)
+	assert.Len(t, evaluator.enqueued, 0)
+}
+
+func TestIndexAll_NoRecipes(t *testing.T) {
+	s := NewIndexService(&RecipeClientMock{}, &IndexRepositoryMock{}, &MatchEvaluatorMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "all_notfound"
+
+	result, err := s.IndexAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Indexed)
+	assert.Len(t, result.Failed, 0)
+}
+
+func TestIndexAll_Err(t *testing.T) {
+	s := NewIndexService(&RecipeClientMock{}, &IndexRepositoryMock{}, &MatchEvaluatorMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "all_error"
+
+	_, err := s.IndexAll(context.Background())
+
+	assert.EqualError(t, err, "recipe service unavailable")
+}
+
+func TestReindex_OK(t *testing.T) {
+	repo := &IndexRepositoryMock{}
+	evaluator := &MatchEvaluatorMock{}
+	s := NewIndexService(&RecipeClientMock{}, repo, evaluator, &m.LoggerInterfaceMock{})
+
+	mode = "index"
+
+	status, err := s.Reindex(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.ReindexFinished, status.State)
+	assert.Equal(t, 2, status.Total)
+	assert.Equal(t, 1, status.Indexed)
+	assert.Equal(t, []uuid.UUID{failingID}, status.Failed)
+	assert.NotNil(t, status.StartedAt)
+	assert.NotNil(t, status.FinishedAt)
+	assert.Len(t, repo.rebuilt, 1)
+	assert.Len(t, repo.saved, 0)
+	assert.True(t, repo.swapped)
+	assert.Equal(t, []uuid.UUID{failingID}, repo.kept)
+	assert.Len(t, evaluator.enqueued, 0)
+	assert.Equal(t, status, s.ReindexStatus())
+}
+
+func TestReindex_ChangedDuringRebuild(t *testing.T) {
+	repo := &IndexRepositoryMock{}
+	s := NewIndexService(&RecipeClientMock{}, repo, &MatchEvaluatorMock{}, &m.LoggerInterfaceMock{})
+	removedID := uuid.New()
+
+	// the recipe is removed while it is rebuilt, so it is indexed again once the new index is in place
+	repo.onRebuild = func() {
+		assert.NoError(t, s.Remove(removedID))
+	}
+
+	mode = "index"
+
+	_, err := s.Reindex(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, repo.saved, 1)
+	assert.Equal(t, []uuid.UUID{removedID}, repo.deleted)
+
+	// changes after the rebuild are no longer tracked
+	repo.onRebuild = nil
+	assert.NoError(t, s.Remove(removedID))
+	assert.Len(t, repo.saved, 1)
+}
+
+func TestReindex_AlreadyRunning(t *testing.T) {
+	repo := &IndexRepositoryMock{}
+	s := NewIndexService(&RecipeClientMock{}, repo, &MatchEvaluatorMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "index"
+
+	assert.True(t, s.rebuild.start())
+
+	status, err := s.Reindex(context.Background())
+	assert.EqualError(t, err, "reindex already running")
+	assert.Equal(t, m.ReindexRunning, status.State)
+
+	_, err = s.StartReindex()
+	assert.EqualError(t, err, "reindex already running")
+	assert.False(t, repo.swapped)
+}
+
+func TestReindex_Err(t *testing.T) {
+	for scenario, expected := range map[string]string{
+		"prepare_error": "internal server error",
+		"all_error":     "recipe service unavailable",
+		"swap_error":    "internal server error",
+	} {
+		s := NewIndexService(&RecipeClientMock{}, &IndexRepositoryMock{}, &MatchEvaluatorMock{}, &m.LoggerInterfaceMock{})
+
+		mode = scenario
+
+		status, err := s.Reindex(context.Background())
+
+		assert.EqualError(t, err, expected, scenario)
+		assert.Equal(t, m.ReindexFailed, status.State, scenario)
+		assert.Equal(t, expected, status.Error, scenario)
+	}
+}
+
+func TestStartReindex(t *testing.T) {
+	repo := &IndexRepositoryMock{}
+	s := NewIndexService(&RecipeClientMock{}, repo, &MatchEvaluatorMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "index"
+
+	assert.Equal(t, m.ReindexIdle, s.ReindexStatus().State)
+
+	status, err := s.StartReindex()
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.ReindexRunning, status.State)
+	assert.Eventually(t, func() bool { return s.ReindexStatus().State == m.ReindexFinished }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+
+	m "search-service/internal/models"
+	"search-service/internal/query"
+
+	"github.com/google/uuid"
+)
+
+const evaluatorQueueSize = 256
+
+// SavedSearchEvaluator runs the saved searches against recipes after they are indexed and records the new matches.
+// Recipes are evaluated in the background so indexing does not wait for it.
+type SavedSearchEvaluator struct {
+	repo   SavedSearchRepository
+	queue  chan uuid.UUID
+	logger m.LoggerInterface
+}
+
+// NewSavedSearchEvaluator creates a new SavedSearchEvaluator instance. Nothing is evaluated until Run is started.
+func NewSavedSearchEvaluator(repo SavedSearchRepository, logger m.LoggerInterface) *SavedSearchEvaluator {
+	return &SavedSearchEvaluator{
+		repo:   repo,
+		queue:  make(chan uuid.UUID, evaluatorQueueSize),
+		logger: logger,
+	}
+}
+
+// Enqueue schedules a created or updated recipe for evaluation. It never blocks, a recipe that does not fit in the queue is skipped.
+func (e *SavedSearchEvaluator) Enqueue(recipeID uuid.UUID) {
+	select {
+	case e.queue <- recipeID:
+	default:
+		e.logger.Warnf("saved search queue is full, recipe %s is not evaluated", recipeID)
+	}
+}
+
+// Run evaluates the queued recipes until the context is cancelled
+func (e *SavedSearchEvaluator) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case recipeID := <-e.queue:
+			if _, err := e.Evaluate(recipeID); err != nil {
+				e.logger.Errorf("unable to evaluate the saved searches for recipe %s: %v", recipeID, err)
+			}
+		}
+	}
+}
+
+// Evaluate runs all saved searches against a single recipe and returns the number of searches it newly matched
+func (e *SavedSearchEvaluator) Evaluate(recipeID uuid.UUID) (int, error) {
+	savedSearches, err := e.repo.FindAll()
+	if err != nil {
+		return 0, err
+	}
+
+	recorded := 0
+
+	for _, savedSearch := range savedSearches {
+		// queries are validated when saved, so this only happens when the query syntax changed since
+		parsed, err := query.Parse(savedSearch.Query)
+		if err != nil {
+			e.logger.Warnf("saved search %s has an invalid query: %v", savedSearch.ID, err)
+			continue
+		}
+
+		document, err := e.repo.FindMatching(parsed, recipeID)
+		if err != nil {
+			if err.Error() == "not found" {
+				continue
+			}
+			return recorded, err
+		}
+
+		isNew, err := e.repo.RecordMatch(m.SavedSearchMatch{
+			SavedSearchID: savedSearch.ID,
+			Subject:       savedSearch.Subject,
+			RecipeID:      recipeID,
+			RecipeName:    document.Name,
+		})
+		if err != nil {
+			return recorded, err
+		}
+
+		if isNew {
+			e.logger.Debugf("recipe %s newly matches saved search %s", recipeID, savedSearch.ID)
+			recorded++
+		}
+	}
+
+	return recorded, nil
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	m "search-service/internal/models"
+	"search-service/internal/query"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxSavedSearches      = 50
+	maxSavedSearchNameLen = 64
+
+	defaultFeedLimit = 50
+	maxFeedLimit     = 500
+)
+
+type SavedSearchRepository interface {
+	FindBySubject(subject string) ([]m.SavedSearch, error)
+	FindSingle(subject string, id uuid.UUID) (m.SavedSearch, error)
+	FindAll() ([]m.SavedSearch, error)
+	Create(savedSearch m.SavedSearch, query m.SearchQuery) (m.SavedSearch, error)
+	Update(savedSearch m.SavedSearch, query m.SearchQuery) (m.SavedSearch, error)
+	Delete(subject string, id uuid.UUID) error
+	FindMatching(query m.SearchQuery, recipeID uuid.UUID) (m.RecipeDocument, error)
+	RecordMatch(match m.SavedSearchMatch) (bool, error)
+	Feed(request m.FeedRequest) ([]m.SavedSearchMatch, error)
+}
+
+// SavedSearchService manages the searches users saved and the feed of recipes newly matching them
+type SavedSearchService struct {
+	repo   SavedSearchRepository
+	logger m.LoggerInterface
+}
+
+// NewSavedSearchService creates a new SavedSearchService instance
+func NewSavedSearchService(repo SavedSearchRepository, logger m.LoggerInterface) *SavedSearchService {
+	return &SavedSearchService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s SavedSearchService) FindBySubject(subject string) ([]m.SavedSearchDTO, error) {
+	savedSearches, err := s.repo.FindBySubject(subject)
+	if err != nil {
+		s.logger.Errorf("unable to retrieve the saved searches of %s: %v", subject, err)
+		return nil, errors.New("internal server error")
+	}
+
+	return m.SavedSearch{}.ConvertAllToDTO(savedSearches), nil
+}
+
+func (s SavedSearchService) FindSingle(subject string, id uuid.UUID) (m.SavedSearchDTO, error) {
+	savedSearch, err := s.repo.FindSingle(subject, id)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return m.SavedSearchDTO{}, err
+		default:
+			s.logger.Errorf("unable to retrieve saved search %s: %v", id, err)
+			return m.SavedSearchDTO{}, errors.New("internal server error")
+		}
+	}
+
+	return savedSearch.ConvertToDTO(), nil
+}
+
+// Create saves a search for a user. Recipes it already matches are not reported in the feed, only the ones matching later on.
+func (s SavedSearchService) Create(subject string, savedSearchDTO m.SavedSearchDTO) (m.SavedSearchDTO, error) {
+	savedSearch := m.SavedSearch{
+		ID:      uuid.New(),
+		Subject: subject,
+		Name:    strings.TrimSpace(savedSearchDTO.Name),
+		Query:   strings.TrimSpace(savedSearchDTO.Query),
+	}
+
+	parsed, err := s.validate(savedSearch)
+	if err != nil {
+		return m.SavedSearchDTO{}, err
+	}
+
+	savedSearch, err = s.repo.Create(savedSearch, parsed)
+	if err != nil {
+		s.logger.Errorf("unable to save search %q of %s: %v", savedSearch.Name, subject, err)
+		return m.SavedSearchDTO{}, errors.New("internal server error")
+	}
+
+	return savedSearch.ConvertToDTO(), nil
+}
+
+// Update renames a saved search or changes its query
+func (s SavedSearchService) Update(subject string, id uuid.UUID, savedSearchDTO m.SavedSearchDTO) (m.SavedSearchDTO, error) {
+	savedSearch := m.SavedSearch{
+		ID:      id,
+		Subject: subject,
+		Name:    strings.TrimSpace(savedSearchDTO.Name),
+		Query:   strings.TrimSpace(savedSearchDTO.Query),
+	}
+
+	parsed, err := s.validate(savedSearch)
+	if err != nil {
+		return m.SavedSearchDTO{}, err
+	}
+
+	savedSearch, err = s.repo.Update(savedSearch, parsed)
+	if err != nil {
+		switch err.Error() {
+		case "not found":
+			return m.SavedSearchDTO{}, err
+		default:
+			s.logger.Errorf("unable to update saved search %s: %v", id, err)
+			return m.SavedSearchDTO{}, errors.New("internal server error")
+		}
+	}
+
+	return savedSearch.ConvertToDTO(), nil
+}
+
+// Delete removes a saved search along with its matches
+func (s SavedSearchService) Delete(subject string, id uuid.UUID) error {
+	if err := s.repo.Delete(subject, id); err != nil {
+		switch err.Error() {
+		case "not found":
+			return err
+		default:
+			s.logger.Errorf("unable to delete saved search %s: %v", id, err)
+			return errors.New("internal server error")
+		}
+	}
+
+	return nil
+}
+
+// Feed returns the recipes that newly matched the saved searches of a user, oldest first. Without a subject the matches of all users are returned.
+func (s SavedSearchService) Feed(subject string, requestDTO m.FeedRequestDTO) (m.FeedDTO, error) {
+	request := m.FeedRequest{
+		Subject: subject,
+		After:   requestDTO.After,
+		Limit:   requestDTO.Limit,
+	}
+
+	if request.Limit < 0 || request.Limit > maxFeedLimit {
+		return m.FeedDTO{}, errors.New("invalid limit")
+	}
+
+	if request.Limit == 0 {
+		request.Limit = defaultFeedLimit
+	}
+
+	matches, err := s.repo.Feed(request)
+	if err != nil {
+		s.logger.Errorf("unable to retrieve the feed after %d: %v", request.After, err)
+		return m.FeedDTO{}, errors.New("internal server error")
+	}
+
+	feedDTO := m.FeedDTO{
+		Matches: make([]m.SavedSearchMatchDTO, 0, len(matches)),
+		Next:    request.After,
+	}
+
+	for _, match := range matches {
+		feedDTO.Matches = append(feedDTO.Matches, match.ConvertToDTO())
+		feedDTO.Next = match.ID
+	}
+
+	return feedDTO, nil
+}
+
+// validate checks the name and query of a saved search and returns the parsed query. Syntax errors are returned as query.SyntaxError.
+func (s SavedSearchService) validate(savedSearch m.SavedSearch) (m.SearchQuery, error) {
+	switch {
+	case savedSearch.Name == "":
+		return m.SearchQuery{}, errors.New("name must not be empty")
+	case len(savedSearch.Name) > maxSavedSearchNameLen:
+		return m.SearchQuery{}, errors.New("name too long")
+	case savedSearch.Query == "":
+		return m.SearchQuery{}, errors.New("query must not be empty")
+	case len(savedSearch.Query) > maxQueryLength:
+		return m.SearchQuery{}, errors.New("query too long")
+	}
+
+	parsed, err := query.Parse(savedSearch.Query)
+	if err != nil {
+		return m.SearchQuery{}, err
+	}
+
+	existing, err := s.repo.FindBySubject(savedSearch.Subject)
+	if err != nil {
+		s.logger.Errorf("unable to retrieve the saved searches of %s: %v", savedSearch.Subject, err)
+		return m.SearchQuery{}, errors.New("internal server error")
+	}
+
+	others := 0
+	for _, other := range existing {
+		if other.ID == savedSearch.ID {
+			continue
+		}
+		if strings.EqualFold(other.Name, savedSearch.Name) {
+			return m.SearchQuery{}, errors.New("name already in use")
+		}
+		others++
+	}
+
+	if others >= maxSavedSearches {
+		return m.SearchQuery{}, errors.New("too many saved searches")
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	m "search-service/internal/models"
+	"search-service/internal/query"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	subject       string    = "7c6a7ba4-5a4e-4b0a-9f5b-4d7f7f6b1f2a"
+	savedSearchID uuid.UUID = uuid.New()
+	otherSearchID uuid.UUID = uuid.New()
+
+	savedSearch m.SavedSearch = m.SavedSearch{ID: savedSearchID, Subject: subject, Name: "quick chicken", Query: "chicken time:<30"}
+	otherSearch m.SavedSearch = m.SavedSearch{ID: otherSearchID, Subject: "someone else", Name: "vegan", Query: "tag:vegan"}
+)
+
+type SavedSearchRepositoryMock struct {
+	created  m.SavedSearch
+	query    m.SearchQuery
+	feed     m.FeedRequest
+	recorded []m.SavedSearchMatch
+	existing []m.SavedSearch
+}
+
+func (r *SavedSearchRepositoryMock) FindBySubject(subject string) ([]m.SavedSearch, error) {
+	switch mode {
+	case "error", "find_error":
+		return nil, errors.New("error")
+	default:
+		return r.existing, nil
+	}
+}
+
+func (r *SavedSearchRepositoryMock) FindSingle(subject string, id uuid.UUID) (m.SavedSearch, error) {
+	switch mode {
+	case "error":
+		return m.SavedSearch{}, errors.New("error")
+	case "notfound":
+		return m.SavedSearch{}, errors.New("not found")
+	default:
+		return savedSearch, nil
+	}
+}
+
+func (r *SavedSearchRepositoryMock) FindAll() ([]m.SavedSearch, error) {
+	switch mode {
+	case "error":
+		return nil, errors.New("error")
+	default:
+		return []m.SavedSearch{savedSearch, {ID: uuid.New(), Query: "tag:"}, otherSearch}, nil
+	}
+}
+
+func (r *SavedSearchRepositoryMock) Create(savedSearch m.SavedSearch, query m.SearchQuery) (m.SavedSearch, error) {
+	r.created, r.query = savedSearch, query
+
+	switch mode {
+	case "error":
+		return m.SavedSearch{}, errors.New("error")
+	default:
+		return savedSearch, nil
+	}
+}
+
+func (r *SavedSearchRepositoryMock) Update(savedSearch m.SavedSearch, query m.SearchQuery) (m.SavedSearch, error) {
+	r.created, r.query = savedSearch, query
+
+	switch mode {
+	case "error":
+		return m.SavedSearch{}, errors.New("error")
+	case "notfound":
+		return m.SavedSearch{}, errors.New("not found")
+	default:
+		return savedSearch, nil
+	}
+}
+
+func (r *SavedSearchRepositoryMock) Delete(subject string, id uuid.UUID) error {
+	switch mode {
+	case "error":
+		return errors.New("error")
+	case "notfound":
+		return errors.New("not found")
+	default:
+		return nil
+	}
+}
+
+func (r *SavedSearchRepositoryMock) FindMatching(query m.SearchQuery, id uuid.UUID) (m.RecipeDocument, error) {
+	switch {
+	case mode == "match_error":
+		return m.RecipeDocument{}, errors.New("error")
+	case len(query.Tags) > 0:
+		return m.RecipeDocument{}, errors.New("not found")
+	default:
+		return m.RecipeDocument{RecipeID: id, Name: "Creamy chicken pasta"}, nil
+	}
+}
+
+func (r *SavedSearchRepositoryMock) RecordMatch(match m.SavedSearchMatch) (bool, error) {
+	switch mode {
+	case "record_error":
+		return false, errors.New("error")
+	case "known":
+		return false, nil
+	default:
+		r.recorded = append(r.recorded, match)
+		return true, nil
+	}
+}
+
+func (r *SavedSearchRepositoryMock) Feed(request m.FeedRequest) ([]m.SavedSearchMatch, error) {
+	r.feed = request
+
+	switch mode {
+	case "error":
+		return nil, errors.New("error")
+	case "empty":
+		return nil, nil
+	default:
+		return []m.SavedSearchMatch{
+			{ID: 7, SavedSearchID: savedSearchID, SavedSearchName: savedSearch.Name, Subject: subject, RecipeID: recipeID, RecipeName: "Creamy chicken pasta"},
+			{ID: 9, SavedSearchID: savedSearchID, SavedSearchName: savedSearch.Name, Subject: subject, RecipeID: quickID, RecipeName: "Chicken wraps"},
+		}, nil
+	}
+}
+
+// ====== Tests ======
+
+func TestSavedSearchFindBySubject_OK(t *testing.T) {
+	s := NewSavedSearchService(&SavedSearchRepositoryMock{existing: []m.SavedSearch{savedSearch}}, &m.LoggerInterfaceMock{})
+
+	mode = "find"
+
+	result, err := s.FindBySubject(subject)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []m.SavedSearchDTO{savedSearch.ConvertToDTO()}, result)
+}
+
+func TestSavedSearchFindBySubject_Empty(t *testing.T) {
+	s := NewSavedSearchService(&SavedSearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "find"
+
+	result, err := s.FindBySubject(subject)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []m.SavedSearchDTO{}, result)
+}
+
+func TestSavedSearchFindSingle_Err(t *testing.T) {
+	s := NewSavedSearchService(&SavedSearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	for scenario, expected := range map[string]string{
+		"notfound": "not found",
+		"error":    "internal server error",
+	} {
+		mode = scenario
+
+		_, err := s.FindSingle(subject, savedSearchID)
+
+		assert.EqualError(t, err, expected, scenario)
+	}
+}
+
+func TestSavedSearchCreate_OK(t *testing.T) {
+	repo := &SavedSearchRepositoryMock{existing: []m.SavedSearch{otherSearch}}
+	s := NewSavedSearchService(repo, &m.LoggerInterfaceMock{})
+	maxPrepTime := 29
+
+	mode = "create"
+
+	result, err := s.Create(subject, m.SavedSearchDTO{Name: " quick chicken ", Query: " chicken time:<30 "})
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, result.ID)
+	assert.Equal(t, "quick chicken", result.Name)
+	assert.Equal(t, "chicken time:<30", result.Query)
+	assert.Equal(t, subject, repo.created.Subject)
+	assert.Equal(t, []string{"chicken"}, repo.query.Terms)
+	assert.Equal(t, &maxPrepTime, repo.query.MaxPrepTime)
+}
+
+func TestSavedSearchCreate_ValidationErr(t *testing.T) {
+	var tooMany []m.SavedSearch
+	for i := 0; i < maxSavedSearches; i++ {
+		tooMany = append(tooMany, m.SavedSearch{ID: uuid.New(), Name: uuid.NewString()})
+	}
+
+	for _, test := range []struct {
+		name     string
+		query    string
+		existing []m.SavedSearch
+		expected string
+	}{
+		{"", "chicken", nil, "name must not be empty"},
+		{strings.Repeat("a", maxSavedSearchNameLen+1), "chicken", nil, "name too long"},
+		{"chicken", " ", nil, "query must not be empty"},
+		{"chicken", strings.Repeat("a", maxQueryLength+1), nil, "query too long"},
+		{"Quick Chicken", "chicken", []m.SavedSearch{{ID: otherSearchID, Name: "quick chicken"}}, "name already in use"},
+		{"chicken", "chicken", tooMany, "too many saved searches"},
+	} {
+		repo := &SavedSearchRepositoryMock{existing: test.existing}
+		s := NewSavedSearchService(repo, &m.LoggerInterfaceMock{})
+
+		mode = "create"
+
+		_, err := s.Create(subject, m.SavedSearchDTO{Name: test.name, Query: test.query})
+
+		assert.EqualError(t, err, test.expected)
+		assert.Equal(t, m.SavedSearch{}, repo.created)
+	}
+}
+
+func TestSavedSearchCreate_SyntaxErr(t *testing.T) {
+	s := NewSavedSearchService(&SavedSearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "create"
+
+	_, err := s.Create(subject, m.SavedSearchDTO{Name: "broken", Query: "tag:"})
+
+	var syntaxErr query.SyntaxError
+	assert.ErrorAs(t, err, &syntaxErr)
+}
+
+func TestSavedSearchCreate_Err(t *testing.T) {
+	s := NewSavedSearchService(&SavedSearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	for _, scenario := range []string{"error", "find_error"} {
+		mode = scenario
+
+		_, err := s.Create(subject, m.SavedSearchDTO{Name: "quick chicken", Query: "chicken"})
+
+		assert.EqualError(t, err, "internal server error", scenario)
+	}
+}
+
+func TestSavedSearchUpdate_OK(t *testing.T) {
+	repo := &SavedSearchRepositoryMock{existing: []m.SavedSearch{savedSearch}}
+	s := NewSavedSearchService(repo, &m.LoggerInterfaceMock{})
+
+	mode = "update"
+
+	// keeping the name of the search itself is not a conflict
+	result, err := s.Update(subject, savedSearchID, m.SavedSearchDTO{Name: savedSearch.Name, Query: "chicken tag:quick"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, savedSearchID, result.ID)
+	assert.Equal(t, "chicken tag:quick", result.Query)
+	assert.Equal(t, []string{"quick"}, repo.query.Tags)
+}
+
+func TestSavedSearchUpdate_Err(t *testing.T) {
+	s := NewSavedSearchService(&SavedSearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "notfound"
+	_, err := s.Update(subject, savedSearchID, m.SavedSearchDTO{Name: "quick chicken", Query: "chicken"})
+	assert.EqualError(t, err, "not found")
+
+	mode = "error"
+	_, err = s.Update(subject, savedSearchID, m.SavedSearchDTO{Name: "quick chicken", Query: "chicken"})
+	assert.EqualError(t, err, "internal server error")
+}
+
+func TestSavedSearchDelete(t *testing.T) {
+	s := NewSavedSearchService(&SavedSearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "delete"
+	assert.NoError(t, s.Delete(subject, savedSearchID))
+
+	mode = "notfound"
+	assert.EqualError(t, s.Delete(subject, savedSearchID), "not found")
+
+	mode = "error"
+	assert.EqualError(t, s.Delete(subject, savedSearchID), "internal server error")
+}
+
+func TestFeed_OK(t *testing.T) {
+	repo := &SavedSearchRepositoryMock{}
+	s := NewSavedSearchService(repo, &m.LoggerInterfaceMock{})
+
+	mode = "feed"
+
+	result, err := s.Feed(subject, m.FeedRequestDTO{After: 5})
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.FeedRequest{Subject: subject, After: 5, Limit: defaultFeedLimit}, repo.feed)
+	assert.Len(t, result.Matches, 2)
+	assert.Equal(t, "quick chicken", result.Matches[0].SavedSearchName)
+	assert.Equal(t, uint64(9), result.Next)
+}
+
+func TestFeed_Empty(t *testing.T) {
+	s := NewSavedSearchService(&SavedSearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "empty"
+
+	result, err := s.Feed("", m.FeedRequestDTO{After: 9, Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.FeedDTO{Matches: []m.SavedSearchMatchDTO{}, Next: 9}, result)
+}
+
+func TestFeed_Err(t *testing.T) {
+	s := NewSavedSearchService(&SavedSearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "feed"
+	_, err := s.Feed(subject, m.FeedRequestDTO{Limit: maxFeedLimit + 1})
+	assert.EqualError(t, err, "invalid limit")
+
+	mode = "error"
+	_, err = s.Feed(subject, m.FeedRequestDTO{})
+	assert.EqualError(t, err, "internal server error")
+}
+
+func TestEvaluate_OK(t *testing.T) {
+	repo := &SavedSearchRepositoryMock{}
+	e := NewSavedSearchEvaluator(repo, &m.LoggerInterfaceMock{})
+
+	mode = "evaluate"
+
+	recorded, err := e.Evaluate(recipeID)
+
+	// the invalid query is skipped and the tag search does not match
+	assert.NoError(t, err)
+	assert.Equal(t, 1, recorded)
+	assert.Equal(t, []m.SavedSearchMatch{{SavedSearchID: savedSearchID, Subject: subject, RecipeID: recipeID, RecipeName: "Creamy chicken pasta"}}, repo.recorded)
+}
+
+func TestEvaluate_KnownMatch(t *testing.T) {
+	e := NewSavedSearchEvaluator(&SavedSearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "known"
+
+	recorded, err := e.Evaluate(recipeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, recorded)
+}
+
+func TestEvaluate_Err(t *testing.T) {
+	for _, scenario := range []string{"error", "match_error", "record_error"} {
+		e := NewSavedSearchEvaluator(&SavedSearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+		mode = scenario
+
+		_, err := e.Evaluate(recipeID)
+
+		assert.EqualError(t, err, "error", scenario)
+	}
+}
+
+func TestEvaluatorRun(t *testing.T) {
+	repo := &SavedSearchRepositoryMock{}
+	e := NewSavedSearchEvaluator(repo, &m.LoggerInterfaceMock{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mode = "evaluate"
+
+	e.Enqueue(recipeID)
+
+	done := make(chan struct{})
+	go func() {
+		e.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return len(e.queue) == 0 }, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Len(t, repo.recorded, 1)
+}
+
+func TestEvaluatorEnqueue_Full(t *testing.T) {
+	e := NewSavedSearchEvaluator(&SavedSearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	for i := 0; i < evaluatorQueueSize+1; i++ {
+		e.Enqueue(recipeID)
+	}
+
+	assert.Len(t, e.queue, evaluatorQueueSize)
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	m "search-service/internal/models"
+	"search-service/internal/query"
+)
+
+const (
+	defaultLimit   = 20
+	maxLimit       = 100
+	maxQueryLength = 256
+
+	defaultSuggestionLimit = 10
+	maxSuggestionLimit     = 50
+	maxSuggestionLength    = 64
+)
+
+type SearchRepository interface {
+	Search(request m.SearchRequest) (m.SearchResult, error)
+	Suggest(request m.SuggestionRequest) ([]m.Suggestion, error)
+}
+
+type SearchService struct {
+	repo   SearchRepository
+	logger m.LoggerInterface
+}
+
+// NewSearchService creates a new SearchService instance
+func NewSearchService(repo SearchRepository, logger m.LoggerInterface) *SearchService {
+	return &SearchService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Search finds the recipes matching the query string, most relevant first. Syntax errors are returned as query.SyntaxError.
+func (s SearchService) Search(requestDTO m.SearchRequestDTO) (m.SearchResultDTO, error) {
+	text := strings.TrimSpace(requestDTO.Query)
+
+	switch {
+	case text == "":
+		return m.SearchResultDTO{}, errors.New("query must not be empty")
+	case len(text) > maxQueryLength:
+		return m.SearchResultDTO{}, errors.New("query too long")
+	case requestDTO.Limit < 0 || requestDTO.Limit > maxLimit:
+		return m.SearchResultDTO{}, errors.New("invalid limit")
+	case requestDTO.Offset < 0:
+		return m.SearchResultDTO{}, errors.New("invalid offset")
+	}
+
+	parsed, err := query.Parse(text)
+	if err != nil {
+		return m.SearchResultDTO{}, err
+	}
+
+	request := m.SearchRequest{
+		Query:  parsed,
+		Limit:  requestDTO.Limit,
+		Offset: requestDTO.Offset,
+	}
+
+	if request.Limit == 0 {
+		request.Limit = defaultLimit
+	}
+
+	result, err := s.repo.Search(request)
+	if err != nil {
+		s.logger.Errorf("unable to search for %q: %v", text, err)
+		return m.SearchResultDTO{}, errors.New("internal server error")
+	}
+
+	resultDTO := m.SearchResultDTO{
+		Query:   text,
+		Total:   result.Total,
+		Limit:   request.Limit,
+		Offset:  request.Offset,
+		Results: make([]m.SearchHitDTO, 0, len(result.Hits)),
+	}
+
+	for _, hit := range result.Hits {
+		resultDTO.Results = append(resultDTO.Results, m.SearchHitDTO{
+			RecipeDocumentDTO: hit.ConvertToDTO(),
+			Rank:              hit.Rank,
+		})
+	}
+
+	return resultDTO, nil
+}
+
+// Parse validates a query string and returns its structured form
+func (s SearchService) Parse(text string) (m.SearchQueryDTO, error) {
+	text = strings.TrimSpace(text)
+
+	switch {
+	case text == "":
+		return m.SearchQueryDTO{}, errors.New("query must not be empty")
+	case len(text) > maxQueryLength:
+		return m.SearchQueryDTO{}, errors.New("query too long")
+	}
+
+	parsed, err := query.Parse(text)
+	if err != nil {
+		return m.SearchQueryDTO{}, err
+	}
+
+	return parsed.ConvertToDTO(), nil
+}
+
+// Suggest completes the partially typed text with the names of recipes, ingredients, tags and categories
+func (s SearchService) Suggest(requestDTO m.SuggestionRequestDTO) ([]m.SuggestionDTO, error) {
+	request := m.SuggestionRequest{
+		Query: strings.TrimSpace(requestDTO.Query),
+		Types: m.TermTypes,
+		Limit: requestDTO.Limit,
+	}
+
+	switch {
+	case request.Query == "":
+		return nil, errors.New("query must not be empty")
+	case len(request.Query) > maxSuggestionLength:
+		return nil, errors.New("query too long")
+	case request.Limit < 0 || request.Limit > maxSuggestionLimit:
+		return nil, errors.New("invalid limit")
+	}
+
+	if request.Limit == 0 {
+		request.Limit = defaultSuggestionLimit
+	}
+
+	if requestDTO.Types != "" {
+		request.Types = []string{}
+
+		for _, termType := range strings.Split(requestDTO.Types, ",") {
+			termType = strings.ToLower(strings.TrimSpace(termType))
+			if !isTermType(termType) {
+				return nil, errors.New("invalid type")
+			}
+
+			request.Types = append(request.Types, termType)
+		}
+	}
+
+	suggestions, err := s.repo.Suggest(request)
+	if err != nil {
+		s.logger.Errorf("unable to suggest for %q: %v", request.Query, err)
+		return nil, errors.New("internal server error")
+	}
+
+	suggestionDTOs := make([]m.SuggestionDTO, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		suggestionDTOs = append(suggestionDTOs, suggestion.ConvertToDTO())
+	}
+
+	return suggestionDTOs, nil
+}
+
+func isTermType(termType string) bool {
+	for _, known := range m.TermTypes {
+		if termType == known {
+			return true
+		}
+	}
+
+	return false
+}
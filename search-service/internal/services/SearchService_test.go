@@ -0,0 +1,211 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	m "search-service/internal/models"
+	"search-service/internal/query"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	recipeID uuid.UUID = uuid.New()
+
+	mode string
+)
+
+type SearchRepositoryMock struct {
+	request    m.SearchRequest
+	suggestion m.SuggestionRequest
+}
+
+func (r *SearchRepositoryMock) Search(request m.SearchRequest) (m.SearchResult, error) {
+	r.request = request
+
+	switch mode {
+	case "error":
+		return m.SearchResult{}, errors.New("error")
+	case "empty":
+		return m.SearchResult{}, nil
+	default:
+		return m.SearchResult{
+			Total: 1,
+			Hits: []m.SearchHit{{
+				RecipeDocument: m.RecipeDocument{RecipeID: recipeID, Name: "Creamy chicken pasta", Ingredients: m.Terms{"chicken"}},
+				Rank:           0.6,
+			}},
+		}, nil
+	}
+}
+
+func (r *SearchRepositoryMock) Suggest(request m.SuggestionRequest) ([]m.Suggestion, error) {
+	r.suggestion = request
+
+	switch mode {
+	case "error":
+		return nil, errors.New("error")
+	case "empty":
+		return nil, nil
+	default:
+		return []m.Suggestion{
+			{Type: m.TermIngredient, EntityID: recipeID, Name: "zucchini", Recipes: 4, Score: 0.875},
+		}, nil
+	}
+}
+
+// ====== Tests ======
+
+func TestSearch_OK(t *testing.T) {
+	repo := &SearchRepositoryMock{}
+	s := NewSearchService(repo, &m.LoggerInterfaceMock{})
+
+	mode = "search"
+
+	result, err := s.Search(m.SearchRequestDTO{Query: "  creamy chicken pasta tag:quick "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.SearchQuery{Terms: []string{"creamy", "chicken", "pasta"}, Tags: []string{"quick"}}, repo.request.Query)
+	assert.Equal(t, defaultLimit, repo.request.Limit)
+	assert.Equal(t, "creamy chicken pasta tag:quick", result.Query)
+	assert.Equal(t, int64(1), result.Total)
+	assert.Equal(t, defaultLimit, result.Limit)
+	assert.Len(t, result.Results, 1)
+	assert.Equal(t, recipeID, result.Results[0].RecipeID)
+	assert.Equal(t, []string{"chicken"}, result.Results[0].Ingredients)
+	assert.Equal(t, 0.6, result.Results[0].Rank)
+}
+
+func TestSearch_Paging(t *testing.T) {
+	repo := &SearchRepositoryMock{}
+	s := NewSearchService(repo, &m.LoggerInterfaceMock{})
+
+	mode = "empty"
+
+	result, err := s.Search(m.SearchRequestDTO{Query: "pasta", Limit: 5, Offset: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.SearchRequest{Query: m.SearchQuery{Terms: []string{"pasta"}}, Limit: 5, Offset: 10}, repo.request)
+	assert.NotNil(t, result.Results)
+	assert.Len(t, result.Results, 0)
+}
+
+func TestSearch_ValidationErr(t *testing.T) {
+	s := NewSearchService(&SearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "search"
+
+	for expected, request := range map[string]m.SearchRequestDTO{
+		"query must not be empty": {Query: " "},
+		"query too long":          {Query: strings.Repeat("pasta ", 50)},
+		"invalid limit":           {Query: "pasta", Limit: maxLimit + 1},
+		"invalid offset":          {Query: "pasta", Offset: -1},
+	} {
+		_, err := s.Search(request)
+
+		assert.EqualError(t, err, expected)
+	}
+}
+
+func TestSearch_SyntaxErr(t *testing.T) {
+	repo := &SearchRepositoryMock{}
+	s := NewSearchService(repo, &m.LoggerInterfaceMock{})
+
+	mode = "search"
+
+	_, err := s.Search(m.SearchRequestDTO{Query: "pasta colour:red"})
+
+	assert.Equal(t, query.SyntaxError{Position: 7, Message: `unknown field "colour"`}, err)
+	assert.Equal(t, m.SearchRequest{}, repo.request)
+}
+
+func TestSearch_Err(t *testing.T) {
+	s := NewSearchService(&SearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "error"
+
+	result, err := s.Search(m.SearchRequestDTO{Query: "pasta"})
+
+	assert.EqualError(t, err, "internal server error")
+	assert.Equal(t, m.SearchResultDTO{}, result)
+}
+
+func TestParse_OK(t *testing.T) {
+	s := NewSearchService(&SearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	result, err := s.Parse(" chicken -ingredient:peanut ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.SearchQueryDTO{Terms: []string{"chicken"}, ExcludedIngredients: []string{"peanut"}}, result)
+}
+
+func TestParse_Err(t *testing.T) {
+	s := NewSearchService(&SearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	_, err := s.Parse("")
+	assert.EqualError(t, err, "query must not be empty")
+
+	_, err = s.Parse(strings.Repeat("pasta ", 50))
+	assert.EqualError(t, err, "query too long")
+
+	_, err = s.Parse(`"green curry`)
+	assert.Equal(t, query.SyntaxError{Position: 1, Message: "unterminated quote"}, err)
+}
+
+func TestSuggest_OK(t *testing.T) {
+	repo := &SearchRepositoryMock{}
+	s := NewSearchService(repo, &m.LoggerInterfaceMock{})
+
+	mode = "suggest"
+
+	result, err := s.Suggest(m.SuggestionRequestDTO{Query: " zuchini "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.SuggestionRequest{Query: "zuchini", Types: m.TermTypes, Limit: defaultSuggestionLimit}, repo.suggestion)
+	assert.Equal(t, []m.SuggestionDTO{{Type: "ingredient", EntityID: recipeID, Name: "zucchini", Recipes: 4, Score: 0.875}}, result)
+}
+
+func TestSuggest_Types(t *testing.T) {
+	repo := &SearchRepositoryMock{}
+	s := NewSearchService(repo, &m.LoggerInterfaceMock{})
+
+	mode = "empty"
+
+	result, err := s.Suggest(m.SuggestionRequestDTO{Query: "parm", Types: "Ingredient, tag", Limit: 5})
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.SuggestionRequest{Query: "parm", Types: []string{"ingredient", "tag"}, Limit: 5}, repo.suggestion)
+	assert.NotNil(t, result)
+	assert.Len(t, result, 0)
+}
+
+func TestSuggest_ValidationErr(t *testing.T) {
+	s := NewSearchService(&SearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "suggest"
+
+	for expected, request := range map[string]m.SuggestionRequestDTO{
+		"query must not be empty": {Query: ""},
+		"query too long":          {Query: strings.Repeat("z", maxSuggestionLength+1)},
+		"invalid limit":           {Query: "zuc", Limit: -1},
+		"invalid type":            {Query: "zuc", Types: "ingredient,unit"},
+	} {
+		_, err := s.Suggest(request)
+
+		assert.EqualError(t, err, expected)
+	}
+}
+
+func TestSuggest_Err(t *testing.T) {
+	s := NewSearchService(&SearchRepositoryMock{}, &m.LoggerInterfaceMock{})
+
+	mode = "error"
+
+	result, err := s.Suggest(m.SuggestionRequestDTO{Query: "zuc"})
+
+	assert.EqualError(t, err, "internal server error")
+	assert.Nil(t, result)
+}